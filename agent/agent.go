@@ -41,6 +41,7 @@ import (
 	"github.com/coder/coder/v2/agent/agentscripts"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/reconnectingpty"
+	"github.com/coder/coder/v2/agent/sessionrecording"
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/gitauth"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
@@ -66,6 +67,7 @@ type Options struct {
 	ExchangeToken                func(ctx context.Context) (string, error)
 	Client                       Client
 	ReconnectingPTYTimeout       time.Duration
+	ReconnectingPTYBackend       string
 	EnvironmentVariables         map[string]string
 	Logger                       slog.Logger
 	IgnorePorts                  map[int]string
@@ -95,6 +97,8 @@ type Client interface {
 	PostMetadata(ctx context.Context, req agentsdk.PostMetadataRequest) error
 	PatchLogs(ctx context.Context, req agentsdk.PatchLogs) error
 	GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerConfig, error)
+	PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error
+	PostScriptTiming(ctx context.Context, req agentsdk.PostScriptTimingRequest) error
 }
 
 type Agent interface {
@@ -145,6 +149,7 @@ func New(options Options) Agent {
 	a := &agent{
 		tailnetListenPort:            options.TailnetListenPort,
 		reconnectingPTYTimeout:       options.ReconnectingPTYTimeout,
+		reconnectingPTYBackend:       options.ReconnectingPTYBackend,
 		logger:                       options.Logger,
 		closeCancel:                  cancelFunc,
 		closed:                       make(chan struct{}),
@@ -191,13 +196,19 @@ type agent struct {
 	portCacheDuration time.Duration
 	subsystems        []codersdk.AgentSubsystem
 
-	reconnectingPTYs       sync.Map
-	reconnectingPTYTimeout time.Duration
+	reconnectingPTYs sync.Map
+	// reconnectingPTYSessions describes each running reconnecting pty for the
+	// api handler.
+	reconnectingPTYSessions sync.Map
+	reconnectingPTYTimeout  time.Duration
+	reconnectingPTYBackend  string
 
 	connCloseWait sync.WaitGroup
 	closeCancel   context.CancelFunc
 	closeMutex    sync.Mutex
 	closed        chan struct{}
+	// sessionRecordingWait tracks session recordings that are being uploaded.
+	sessionRecordingWait sync.WaitGroup
 
 	envVars map[string]string
 
@@ -247,13 +258,15 @@ func (a *agent) init(ctx context.Context) {
 	sshSrv.AgentToken = func() string { return *a.sessionToken.Load() }
 	sshSrv.Manifest = &a.manifest
 	sshSrv.ServiceBanner = &a.serviceBanner
+	sshSrv.SessionRecorded = a.uploadSessionRecording
 	a.sshServer = sshSrv
 	a.scriptRunner = agentscripts.New(agentscripts.Options{
-		LogDir:     a.logDir,
-		Logger:     a.logger,
-		SSHServer:  sshSrv,
-		Filesystem: a.filesystem,
-		PatchLogs:  a.client.PatchLogs,
+		LogDir:          a.logDir,
+		Logger:          a.logger,
+		SSHServer:       sshSrv,
+		Filesystem:      a.filesystem,
+		PatchLogs:       a.client.PatchLogs,
+		ScriptCompleted: a.client.PostScriptTiming,
 	})
 	// Register runner metrics. If the prom registry is nil, the metrics
 	// will not report anywhere.
@@ -752,9 +765,7 @@ func (a *agent) run(ctx context.Context) error {
 		}
 		err = a.trackConnGoroutine(func() {
 			start := time.Now()
-			err := a.scriptRunner.Execute(ctx, func(script codersdk.WorkspaceAgentScript) bool {
-				return script.RunOnStart
-			})
+			err := a.scriptRunner.Execute(ctx, agentscripts.ExecuteStartScripts)
 			// Measure the time immediately after the script has finished
 			dur := time.Since(start).Seconds()
 			if err != nil {
@@ -1112,7 +1123,7 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 	defer a.connCountReconnectingPTY.Add(-1)
 
 	connectionID := uuid.NewString()
-	connLogger := logger.With(slog.F("message_id", msg.ID), slog.F("connection_id", connectionID))
+	connLogger := logger.With(slog.F("message_id", msg.ID), slog.F("connection_id", connectionID), slog.F("mode", msg.Mode))
 	connLogger.Debug(ctx, "starting handler")
 
 	defer func() {
@@ -1132,10 +1143,28 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		connLogger.Info(ctx, "reconnecting pty connection closed")
 	}()
 
+	if !msg.Mode.Valid() {
+		return xerrors.Errorf("invalid reconnecting pty mode %q", msg.Mode)
+	}
+
 	var rpty reconnectingpty.ReconnectingPTY
 	sendConnected := make(chan reconnectingpty.ReconnectingPTY, 1)
-	// On store, reserve this ID to prevent multiple concurrent new connections.
-	waitReady, ok := a.reconnectingPTYs.LoadOrStore(msg.ID, sendConnected)
+	var (
+		waitReady interface{}
+		ok        bool
+	)
+	if msg.Mode.CanResize() {
+		// On store, reserve this ID to prevent multiple concurrent new connections.
+		waitReady, ok = a.reconnectingPTYs.LoadOrStore(msg.ID, sendConnected)
+	} else {
+		// Only the owner can start a session, others can only join it.
+		waitReady, ok = a.reconnectingPTYs.Load(msg.ID)
+		if !ok {
+			close(sendConnected) // Unused.
+			connLogger.Warn(ctx, "reconnecting pty is not running and only the owner can start it")
+			return nil
+		}
+	}
 	if ok {
 		close(sendConnected) // Unused.
 		connLogger.Debug(ctx, "connecting to existing reconnecting pty")
@@ -1167,14 +1196,21 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		}
 
 		rpty = reconnectingpty.New(ctx, cmd, &reconnectingpty.Options{
-			Timeout: a.reconnectingPTYTimeout,
-			Metrics: a.metrics.reconnectingPTYErrors,
+			Timeout:     a.reconnectingPTYTimeout,
+			Metrics:     a.metrics.reconnectingPTYErrors,
+			BackendType: a.reconnectingPTYBackend,
 		}, logger.With(slog.F("message_id", msg.ID)))
 
+		a.reconnectingPTYSessions.Store(msg.ID, &reconnectingPTYSession{
+			command:   msg.Command,
+			createdAt: time.Now(),
+		})
 		if err = a.trackConnGoroutine(func() {
 			rpty.Wait()
 			a.reconnectingPTYs.Delete(msg.ID)
+			a.reconnectingPTYSessions.Delete(msg.ID)
 		}); err != nil {
+			a.reconnectingPTYSessions.Delete(msg.ID)
 			rpty.Close(err)
 			return xerrors.Errorf("start routine: %w", err)
 		}
@@ -1182,7 +1218,57 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		connected = true
 		sendConnected <- rpty
 	}
-	return rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, connLogger)
+	if manifest := a.manifest.Load(); manifest != nil && manifest.RecordSessions {
+		recorder := sessionrecording.New(codersdk.WorkspaceSessionRecordingTypeReconnectingPTY, msg.Width, msg.Height, msg.Command, "xterm-256color")
+		defer func() {
+			a.uploadSessionRecording(recorder.Request())
+		}()
+		conn = sessionrecording.ReconnectingPTYConn(conn, recorder)
+		defer conn.Close()
+	}
+	if s, ok := a.reconnectingPTYSessions.Load(msg.ID); ok {
+		session, _ := s.(*reconnectingPTYSession)
+		session.attach(msg.Mode)
+		defer session.detach(msg.Mode)
+	}
+	return rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, msg.Mode, connLogger)
+}
+
+// reconnectingPTYSession describes a running reconnecting pty.
+type reconnectingPTYSession struct {
+	command     string
+	createdAt   time.Time
+	connections atomic.Int64
+	observers   atomic.Int64
+}
+
+func (s *reconnectingPTYSession) attach(mode codersdk.ReconnectingPTYMode) {
+	s.connections.Add(1)
+	if !mode.CanWrite() {
+		s.observers.Add(1)
+	}
+}
+
+func (s *reconnectingPTYSession) detach(mode codersdk.ReconnectingPTYMode) {
+	s.connections.Add(-1)
+	if !mode.CanWrite() {
+		s.observers.Add(-1)
+	}
+}
+
+// uploadSessionRecording uploads a recorded session in the background, so
+// that the session does not wait on coderd to end.
+func (a *agent) uploadSessionRecording(req agentsdk.PostSessionRecordingRequest) {
+	a.sessionRecordingWait.Add(1)
+	go func() {
+		defer a.sessionRecordingWait.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := a.client.PostSessionRecording(ctx, req)
+		if err != nil {
+			a.logger.Error(ctx, "upload session recording", slog.F("type", req.Type), slog.F("size", len(req.Data)), slog.Error(err))
+		}
+	}()
 }
 
 // startReportingConnectionStats runs the connection stats reporting goroutine.
@@ -1482,9 +1568,7 @@ func (a *agent) Close() error {
 	}
 
 	lifecycleState := codersdk.WorkspaceAgentLifecycleOff
-	err = a.scriptRunner.Execute(ctx, func(script codersdk.WorkspaceAgentScript) bool {
-		return script.RunOnStop
-	})
+	err = a.scriptRunner.Execute(ctx, agentscripts.ExecuteStopScripts)
 	if err != nil {
 		a.logger.Warn(ctx, "shutdown script(s) failed", slog.Error(err))
 		if errors.Is(err, agentscripts.ErrTimeout) {
@@ -1523,6 +1607,7 @@ lifecycleWaitLoop:
 		_ = a.network.Close()
 	}
 	a.connCloseWait.Wait()
+	a.sessionRecordingWait.Wait()
 
 	return nil
 }
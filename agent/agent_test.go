@@ -344,6 +344,70 @@ func TestAgent_SessionTTYExitCode(t *testing.T) {
 	}
 }
 
+func TestAgent_SessionRecording(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{RecordSessions: true}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		require.NoError(t, err)
+		output, err := session.Output("echo recorded-output")
+		require.NoError(t, err)
+		require.Contains(t, string(output), "recorded-output")
+
+		var recordings []agentsdk.PostSessionRecordingRequest
+		require.Eventually(t, func() bool {
+			recordings = client.GetSessionRecordings()
+			return len(recordings) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, codersdk.WorkspaceSessionRecordingTypeSSH, recordings[0].Type)
+		require.Contains(t, string(recordings[0].Data), `"command":"echo recorded-output"`)
+		require.Contains(t, string(recordings[0].Data), `"o","recorded-output`)
+	})
+
+	t.Run("ReconnectingPTY", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, client, _, _, _ := setupAgent(t, agentsdk.Manifest{RecordSessions: true}, 0)
+		ptyConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 24, 80, "sh")
+		require.NoError(t, err)
+
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo recorded-output\r",
+		})
+		require.NoError(t, err)
+		_, err = ptyConn.Write(data)
+		require.NoError(t, err)
+		tr := testutil.NewTerminalReader(t, ptyConn)
+		require.NoError(t, tr.ReadUntilString(ctx, "recorded-output"))
+		require.NoError(t, ptyConn.Close())
+
+		var recordings []agentsdk.PostSessionRecordingRequest
+		require.Eventually(t, func() bool {
+			recordings = client.GetSessionRecordings()
+			return len(recordings) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, codersdk.WorkspaceSessionRecordingTypeReconnectingPTY, recordings[0].Type)
+		require.Contains(t, string(recordings[0].Data), "recorded-output")
+	})
+}
+
 func TestAgent_Session_TTY_MOTD(t *testing.T) {
 	t.Parallel()
 	if runtime.GOOS == "windows" {
@@ -1454,7 +1518,7 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 		t.Skip("ConPTY appears to be inconsistent on Windows.")
 	}
 
-	backends := []string{"Buffered", "Screen"}
+	backends := []string{"Buffered", "Terminal", "Screen"}
 
 	_, err := exec.LookPath("screen")
 	hasScreen := err == nil
@@ -1486,7 +1550,13 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 			defer cancel()
 
 			//nolint:dogsled
-			conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+			conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+				// Screen and buffered are picked by default depending on
+				// whether screen is installed.
+				if backendType == "Terminal" {
+					o.ReconnectingPTYBackend = "terminal"
+				}
+			})
 			id := uuid.New()
 			// --norc disables executing .bashrc, which is often used to customize the bash prompt
 			netConn1, err := conn.ReconnectingPTY(ctx, id, 80, 80, "bash --norc")
@@ -1586,6 +1656,143 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	}
 }
 
+func TestAgent_ReconnectingPTYModes(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	id := uuid.New()
+	attach := func(mode codersdk.ReconnectingPTYMode) net.Conn {
+		ptyConn, err := conn.ReconnectingPTY(ctx, id, 24, 80, "sh", codersdk.WithReconnectingPTYMode(mode))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = ptyConn.Close()
+		})
+		return ptyConn
+	}
+	write := func(ptyConn net.Conn, data string) {
+		req, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: data,
+		})
+		require.NoError(t, err)
+		_, err = ptyConn.Write(req)
+		require.NoError(t, err)
+	}
+
+	// Only the owner can start a session.
+	output, err := io.ReadAll(attach(codersdk.ReconnectingPTYModeObserver))
+	require.NoError(t, err)
+	require.Empty(t, output)
+
+	owner := testutil.NewTerminalReader(t, attach(codersdk.ReconnectingPTYModeOwner))
+	observerConn := attach(codersdk.ReconnectingPTYModeObserver)
+	observer := testutil.NewTerminalReader(t, observerConn)
+	collaboratorConn := attach(codersdk.ReconnectingPTYModeCollaborator)
+
+	require.Eventually(t, func() bool {
+		res, err := conn.ReconnectingPTYs(ctx)
+		if !assert.NoError(t, err) || len(res.Sessions) != 1 {
+			return false
+		}
+		session := res.Sessions[0]
+		return session.ID == id && session.Command == "sh" && session.Connections == 3 && session.Observers == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// Input from observers is dropped, so their command is never echoed.
+	write(observerConn, "echo observer\r")
+	write(collaboratorConn, "echo collaborator-$((1+1))\r")
+	sawObserver := false
+	require.NoError(t, owner.ReadUntil(ctx, func(line string) bool {
+		sawObserver = sawObserver || strings.Contains(line, "observer")
+		return strings.Contains(line, "collaborator-2")
+	}))
+	require.False(t, sawObserver, "observer input was written")
+	require.NoError(t, observer.ReadUntilString(ctx, "collaborator-2"))
+}
+
+func TestAgent_Exec(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("The commands use a POSIX shell.")
+	}
+
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	exec := func(ctx context.Context, req codersdk.WorkspaceAgentExecRequest) (stdout, stderr string, exit codersdk.WorkspaceAgentExecFrame) {
+		stream, err := conn.Exec(ctx, req)
+		require.NoError(t, err)
+		defer stream.Close()
+		for {
+			frame, err := stream.Recv()
+			require.NoError(t, err)
+			switch frame.Type {
+			case codersdk.WorkspaceAgentExecFrameStdout:
+				stdout += string(frame.Data)
+			case codersdk.WorkspaceAgentExecFrameStderr:
+				stderr += string(frame.Data)
+			case codersdk.WorkspaceAgentExecFrameExit:
+				_, err = stream.Recv()
+				require.ErrorIs(t, err, io.EOF)
+				return stdout, stderr, frame
+			}
+		}
+	}
+
+	t.Run("Output", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		stdout, stderr, exit := exec(ctx, codersdk.WorkspaceAgentExecRequest{
+			Command: "echo $GREETING; echo oops >&2; exit 3",
+			Env:     map[string]string{"GREETING": "hello"},
+		})
+		require.Equal(t, "hello\n", stdout)
+		require.Equal(t, "oops\n", stderr)
+		require.Equal(t, 3, exit.ExitCode)
+		require.Empty(t, exit.Error)
+	})
+
+	t.Run("Dir", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		dir := t.TempDir()
+		stdout, _, exit := exec(ctx, codersdk.WorkspaceAgentExecRequest{
+			Command: "pwd",
+			Dir:     dir,
+		})
+		require.Equal(t, 0, exit.ExitCode)
+		// The temp dir may be behind a symlink, e.g. on macOS.
+		want, err := filepath.EvalSymlinks(dir)
+		require.NoError(t, err)
+		got, err := filepath.EvalSymlinks(strings.TrimSpace(stdout))
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, _, exit := exec(ctx, codersdk.WorkspaceAgentExecRequest{
+			Command:       "sleep 30",
+			TimeoutMillis: 100,
+		})
+		require.Equal(t, -1, exit.ExitCode)
+		require.Contains(t, exit.Error, "timed out")
+	})
+
+	t.Run("NoCommand", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := conn.Exec(ctx, codersdk.WorkspaceAgentExecRequest{})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+}
+
 func TestAgent_Dial(t *testing.T) {
 	t.Parallel()
 
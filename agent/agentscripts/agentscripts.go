@@ -48,6 +48,9 @@ type Options struct {
 	SSHServer  *agentssh.Server
 	Filesystem afero.Fs
 	PatchLogs  func(ctx context.Context, req agentsdk.PatchLogs) error
+	// ScriptCompleted is called after each start and stop script runs, to
+	// report how long it took. Scripts run on a schedule aren't reported.
+	ScriptCompleted func(ctx context.Context, req agentsdk.PostScriptTimingRequest) error
 }
 
 // New creates a runner for the provided scripts.
@@ -110,7 +113,7 @@ func (r *Runner) Init(scripts []codersdk.WorkspaceAgentScript) error {
 		}
 		script := script
 		_, err := r.cron.AddFunc(script.Cron, func() {
-			err := r.trackRun(r.cronCtx, script)
+			err := r.trackRun(r.cronCtx, script, ExecuteCronScripts)
 			if err != nil {
 				r.Logger.Warn(context.Background(), "run agent script on schedule", slog.Error(err))
 			}
@@ -136,22 +139,30 @@ func (r *Runner) StartCron() {
 	}
 }
 
-// Execute runs a set of scripts according to a filter.
-func (r *Runner) Execute(ctx context.Context, filter func(script codersdk.WorkspaceAgentScript) bool) error {
-	if filter == nil {
-		// Execute em' all!
-		filter = func(script codersdk.WorkspaceAgentScript) bool {
-			return true
-		}
-	}
+// ExecuteOption describes which scripts Execute runs.
+type ExecuteOption int
+
+const (
+	ExecuteAllScripts ExecuteOption = iota
+	ExecuteStartScripts
+	ExecuteStopScripts
+	ExecuteCronScripts
+)
+
+// Execute runs the set of scripts described by option.
+func (r *Runner) Execute(ctx context.Context, option ExecuteOption) error {
 	var eg errgroup.Group
 	for _, script := range r.scripts {
-		if !filter(script) {
+		runScript := option == ExecuteAllScripts ||
+			(option == ExecuteStartScripts && script.RunOnStart) ||
+			(option == ExecuteStopScripts && script.RunOnStop) ||
+			(option == ExecuteCronScripts && script.Cron != "")
+		if !runScript {
 			continue
 		}
 		script := script
 		eg.Go(func() error {
-			err := r.trackRun(ctx, script)
+			err := r.trackRun(ctx, script, option)
 			if err != nil {
 				return xerrors.Errorf("run agent script %q: %w", script.LogSourceID, err)
 			}
@@ -161,17 +172,60 @@ func (r *Runner) Execute(ctx context.Context, filter func(script codersdk.Worksp
 	return eg.Wait()
 }
 
-// trackRun wraps "run" with metrics.
-func (r *Runner) trackRun(ctx context.Context, script codersdk.WorkspaceAgentScript) error {
+// trackRun wraps "run" with metrics and timing reports.
+func (r *Runner) trackRun(ctx context.Context, script codersdk.WorkspaceAgentScript, option ExecuteOption) error {
+	start := time.Now()
 	err := r.run(ctx, script)
+	end := time.Now()
 	if err != nil {
 		r.scriptsExecuted.WithLabelValues("false").Add(1)
 	} else {
 		r.scriptsExecuted.WithLabelValues("true").Add(1)
 	}
+
+	var stage codersdk.WorkspaceAgentScriptTimingStage
+	switch option {
+	case ExecuteStartScripts:
+		stage = codersdk.WorkspaceAgentScriptTimingStageStart
+	case ExecuteStopScripts:
+		stage = codersdk.WorkspaceAgentScriptTimingStageStop
+	}
+	if stage != "" && r.ScriptCompleted != nil {
+		status, exitCode := scriptResult(err)
+		reportErr := r.ScriptCompleted(ctx, agentsdk.PostScriptTimingRequest{
+			LogSourceID: script.LogSourceID,
+			Stage:       stage,
+			Status:      status,
+			ExitCode:    exitCode,
+			StartedAt:   start,
+			EndedAt:     end,
+		})
+		if reportErr != nil {
+			r.Logger.Warn(ctx, "report script timing", slog.F("log_source_id", script.LogSourceID), slog.Error(reportErr))
+		}
+	}
 	return err
 }
 
+// scriptResult converts the error returned by run into a status and exit
+// code.
+func scriptResult(err error) (codersdk.WorkspaceAgentScriptTimingStatus, int32) {
+	var exitError *exec.ExitError
+	switch {
+	case err == nil:
+		return codersdk.WorkspaceAgentScriptTimingStatusOK, 0
+	case errors.Is(err, ErrTimeout):
+		return codersdk.WorkspaceAgentScriptTimingStatusTimedOut, 255
+	case errors.Is(err, ErrOutputPipesOpen):
+		// The script itself exited successfully.
+		return codersdk.WorkspaceAgentScriptTimingStatusPipesLeftOpen, 0
+	case xerrors.As(err, &exitError):
+		return codersdk.WorkspaceAgentScriptTimingStatusExitFailure, int32(exitError.ExitCode())
+	default:
+		return codersdk.WorkspaceAgentScriptTimingStatusExitFailure, 255
+	}
+}
+
 // run executes the provided script with the timeout.
 // If the timeout is exceeded, the process is sent an interrupt signal.
 // If the process does not exit after a few seconds, it is forcefully killed.
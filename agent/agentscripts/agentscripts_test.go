@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/require"
@@ -34,9 +35,7 @@ func TestExecuteBasic(t *testing.T) {
 		Script: "echo hello",
 	}})
 	require.NoError(t, err)
-	require.NoError(t, runner.Execute(context.Background(), func(script codersdk.WorkspaceAgentScript) bool {
-		return true
-	}))
+	require.NoError(t, runner.Execute(context.Background(), agentscripts.ExecuteAllScripts))
 	log := <-logs
 	require.Equal(t, "hello", log.Logs[0].Output)
 }
@@ -50,7 +49,46 @@ func TestTimeout(t *testing.T) {
 		Timeout: time.Millisecond,
 	}})
 	require.NoError(t, err)
-	require.ErrorIs(t, runner.Execute(context.Background(), nil), agentscripts.ErrTimeout)
+	require.ErrorIs(t, runner.Execute(context.Background(), agentscripts.ExecuteAllScripts), agentscripts.ErrTimeout)
+}
+
+func TestScriptCompleted(t *testing.T) {
+	t.Parallel()
+	timings := make(chan agentsdk.PostScriptTimingRequest, 3)
+	runner := setup(t, nil)
+	runner.ScriptCompleted = func(_ context.Context, req agentsdk.PostScriptTimingRequest) error {
+		timings <- req
+		return nil
+	}
+	defer runner.Close()
+	okID, failID, stopID := uuid.New(), uuid.New(), uuid.New()
+	err := runner.Init([]codersdk.WorkspaceAgentScript{{
+		LogSourceID: okID,
+		Script:      "echo hello",
+		RunOnStart:  true,
+	}, {
+		LogSourceID: failID,
+		Script:      "exit 3",
+		RunOnStart:  true,
+	}, {
+		LogSourceID: stopID,
+		Script:      "echo bye",
+		RunOnStop:   true,
+	}})
+	require.NoError(t, err)
+	require.Error(t, runner.Execute(context.Background(), agentscripts.ExecuteStartScripts))
+
+	got := map[uuid.UUID]agentsdk.PostScriptTimingRequest{}
+	for i := 0; i < 2; i++ {
+		timing := <-timings
+		got[timing.LogSourceID] = timing
+	}
+	require.Len(t, timings, 0, "stop script should not run")
+	require.Equal(t, codersdk.WorkspaceAgentScriptTimingStageStart, got[okID].Stage)
+	require.Equal(t, codersdk.WorkspaceAgentScriptTimingStatusOK, got[okID].Status)
+	require.Equal(t, codersdk.WorkspaceAgentScriptTimingStatusExitFailure, got[failID].Status)
+	require.EqualValues(t, 3, got[failID].ExitCode)
+	require.False(t, got[okID].EndedAt.Before(got[okID].StartedAt))
 }
 
 func setup(t *testing.T, patchLogs func(ctx context.Context, req agentsdk.PatchLogs) error) *agentscripts.Runner {
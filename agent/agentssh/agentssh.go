@@ -30,6 +30,7 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/v2/agent/sessionrecording"
 	"github.com/coder/coder/v2/agent/usershell"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
@@ -74,6 +75,9 @@ type Server struct {
 	AgentToken    func() string
 	Manifest      *atomic.Pointer[agentsdk.Manifest]
 	ServiceBanner *atomic.Pointer[codersdk.ServiceBannerConfig]
+	// SessionRecorded is called with the recording of a PTY session when it
+	// ends, if the manifest enables session recording.
+	SessionRecorded func(agentsdk.PostSessionRecordingRequest)
 
 	connCountVSCode     atomic.Int64
 	connCountJetBrains  atomic.Int64
@@ -425,6 +429,17 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 
 	cmd.Env = append(cmd.Env, fmt.Sprintf("TERM=%s", sshPty.Term))
 
+	var recorder *sessionrecording.Recorder
+	if s.SessionRecorded != nil {
+		if manifest := s.Manifest.Load(); manifest != nil && manifest.RecordSessions {
+			recorder = sessionrecording.New(codersdk.WorkspaceSessionRecordingTypeSSH,
+				uint16(sshPty.Window.Width), uint16(sshPty.Window.Height), session.RawCommand(), sshPty.Term)
+			defer func() {
+				s.SessionRecorded(recorder.Request())
+			}()
+		}
+	}
+
 	// The pty package sets `SSH_TTY` on supported platforms.
 	ptty, process, err := pty.Start(cmd, pty.WithPTYOption(
 		pty.WithSSHRequest(sshPty),
@@ -468,6 +483,9 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 					windowSize = nil
 					continue
 				}
+				if recorder != nil {
+					recorder.Resize(uint16(win.Width), uint16(win.Height))
+				}
 				resizeErr := ptty.Resize(uint16(win.Height), uint16(win.Width))
 				// If the pty is closed, then command has exited, no need to log.
 				if resizeErr != nil && !errors.Is(resizeErr, pty.ErrClosed) {
@@ -493,7 +511,11 @@ func (s *Server) startPTYSession(logger slog.Logger, session ptySession, magicTy
 	//    after we've Read() all the buffered data from the PTY.
 	// 2. The client hangs up, which cancels the command's Context, and go will
 	//    kill the command's process.  This then has the same effect as (1).
-	n, err := io.Copy(session, ptty.OutputReader())
+	var output io.Writer = session
+	if recorder != nil {
+		output = io.MultiWriter(session, recorder)
+	}
+	n, err := io.Copy(output, ptty.OutputReader())
 	logger.Debug(ctx, "copy output done", slog.F("bytes", n), slog.Error(err))
 	if err != nil {
 		s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "output_io_copy").Add(1)
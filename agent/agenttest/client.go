@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -57,6 +58,8 @@ type Client struct {
 	startup         agentsdk.PostStartupRequest
 	logs            []agentsdk.Log
 	derpMapUpdates  chan agentsdk.DERPMapUpdate
+	recordings      []agentsdk.PostSessionRecordingRequest
+	scriptTimings   []agentsdk.PostScriptTimingRequest
 }
 
 func (c *Client) Manifest(_ context.Context) (agentsdk.Manifest, error) {
@@ -197,6 +200,34 @@ func (c *Client) GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerCo
 	return codersdk.ServiceBannerConfig{}, nil
 }
 
+func (c *Client) GetSessionRecordings() []agentsdk.PostSessionRecordingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.recordings)
+}
+
+func (c *Client) PostSessionRecording(ctx context.Context, req agentsdk.PostSessionRecordingRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordings = append(c.recordings, req)
+	c.logger.Debug(ctx, "post session recording", slog.F("type", req.Type), slog.F("size", len(req.Data)))
+	return nil
+}
+
+func (c *Client) GetScriptTimings() []agentsdk.PostScriptTimingRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.scriptTimings)
+}
+
+func (c *Client) PostScriptTiming(ctx context.Context, req agentsdk.PostScriptTimingRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scriptTimings = append(c.scriptTimings, req)
+	c.logger.Debug(ctx, "post script timing", slog.F("log_source_id", req.LogSourceID), slog.F("status", req.Status))
+	return nil
+}
+
 func (c *Client) PushDERPMapUpdate(update agentsdk.DERPMapUpdate) error {
 	timer := time.NewTimer(testutil.WaitShort)
 	defer timer.Stop()
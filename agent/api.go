@@ -2,10 +2,12 @@ package agent
 
 import (
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
@@ -36,10 +38,37 @@ func (a *agent) apiHandler() http.Handler {
 		cacheDuration: cacheDuration,
 	}
 	r.Get("/api/v0/listening-ports", lp.handler)
+	r.Get("/api/v0/reconnecting-ptys", a.handleReconnectingPTYs)
+	r.Post("/api/v0/exec", a.handleExec)
 
 	return r
 }
 
+// handleReconnectingPTYs returns the running reconnecting ptys, oldest first.
+// This is tested by coderd's TestWorkspaceAgentReconnectingPTYs test.
+func (a *agent) handleReconnectingPTYs(rw http.ResponseWriter, r *http.Request) {
+	sessions := []codersdk.WorkspaceAgentReconnectingPTY{}
+	a.reconnectingPTYSessions.Range(func(key, value any) bool {
+		id, _ := key.(uuid.UUID)
+		session, _ := value.(*reconnectingPTYSession)
+		sessions = append(sessions, codersdk.WorkspaceAgentReconnectingPTY{
+			ID:          id,
+			Command:     session.command,
+			CreatedAt:   session.createdAt,
+			Connections: int(session.connections.Load()),
+			Observers:   int(session.observers.Load()),
+		})
+		return true
+	})
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentReconnectingPTYsResponse{
+		Sessions: sessions,
+	})
+}
+
 type listeningPortsHandler struct {
 	ignorePorts   map[int]string
 	cacheDuration time.Duration
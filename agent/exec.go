@@ -0,0 +1,147 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// execWaitDelay is how long an exec waits for its output to be closed after
+// the command exits, in case it left background processes holding it open.
+var execWaitDelay = 5 * time.Second
+
+// handleExec runs a command without a PTY and streams its output as newline
+// delimited frames, ending with the exit status. This is tested by the
+// TestAgent_Exec test.
+func (a *agent) handleExec(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req codersdk.WorkspaceAgentExecRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Command == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A command is required.",
+		})
+		return
+	}
+	if req.TimeoutMillis < 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Timeout must not be negative.",
+		})
+		return
+	}
+
+	cmdCtx := ctx
+	if req.TimeoutMillis > 0 {
+		var cancel func()
+		cmdCtx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutMillis)*time.Millisecond)
+		defer cancel()
+	}
+
+	env := make([]string, 0, len(req.Env))
+	for k, v := range req.Env {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(env)
+	cmdPty, err := a.sshServer.CreateCommand(cmdCtx, req.Command, env)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to create command.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	cmd := cmdPty.AsExec()
+	if req.Dir != "" {
+		if filepath.IsAbs(req.Dir) {
+			cmd.Dir = req.Dir
+		} else {
+			cmd.Dir = filepath.Join(cmd.Dir, req.Dir)
+		}
+	}
+	cmd.WaitDelay = execWaitDelay
+
+	// Commands can run for longer than the API server's write timeout.
+	_ = http.NewResponseController(rw).SetWriteDeadline(time.Time{})
+	rw.Header().Set("Content-Type", "application/x-ndjson")
+	rw.WriteHeader(http.StatusOK)
+
+	frames := &execFrameWriter{rw: rw, enc: json.NewEncoder(rw)}
+	cmd.Stdout = frames.stream(codersdk.WorkspaceAgentExecFrameStdout)
+	cmd.Stderr = frames.stream(codersdk.WorkspaceAgentExecFrameStderr)
+
+	logger := a.logger.With(slog.F("command", req.Command))
+	logger.Debug(ctx, "exec started")
+	start := time.Now()
+	err = cmd.Run()
+
+	exit := codersdk.WorkspaceAgentExecFrame{
+		Type: codersdk.WorkspaceAgentExecFrameExit,
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case cmdCtx.Err() != nil && ctx.Err() == nil:
+		exit.ExitCode = -1
+		exit.Error = fmt.Sprintf("command timed out after %s", time.Duration(req.TimeoutMillis)*time.Millisecond)
+	case xerrors.As(err, &exitErr):
+		exit.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		exit.ExitCode = -1
+		exit.Error = err.Error()
+	}
+	logger.Debug(ctx, "exec exited",
+		slog.F("exit_code", exit.ExitCode),
+		slog.F("duration", time.Since(start)),
+		slog.Error(err),
+	)
+	frames.write(exit)
+}
+
+// execFrameWriter writes frames to the response. The command's output is
+// copied in separate goroutines, so writes are serialized.
+type execFrameWriter struct {
+	mu  sync.Mutex
+	rw  http.ResponseWriter
+	enc *json.Encoder
+}
+
+func (w *execFrameWriter) write(frame codersdk.WorkspaceAgentExecFrame) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// Errors mean the client has gone away, which cancels the command.
+	_ = w.enc.Encode(frame)
+	_ = http.NewResponseController(w.rw).Flush()
+}
+
+func (w *execFrameWriter) stream(typ codersdk.WorkspaceAgentExecFrameType) execStreamWriter {
+	return execStreamWriter{frames: w, typ: typ}
+}
+
+type execStreamWriter struct {
+	frames *execFrameWriter
+	typ    codersdk.WorkspaceAgentExecFrameType
+}
+
+func (w execStreamWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// The frame is encoded before returning, so p is not retained.
+	w.frames.write(codersdk.WorkspaceAgentExecFrame{Type: w.typ, Data: p})
+	return len(p), nil
+}
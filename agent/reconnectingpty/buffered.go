@@ -14,16 +14,61 @@ import (
 
 	"cdr.dev/slog"
 
+	"github.com/coder/coder/v2/agent/reconnectingpty/vt"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty"
 )
 
-// bufferedReconnectingPTY provides a reconnectable PTY by using a ring buffer to store
-// scrollback.
+// history stores the output of a buffered pty so it can be replayed to new
+// connections.  It is not safe for concurrent use.
+type history interface {
+	io.Writer
+	// Replay returns the output to write to a new connection of the provided
+	// size.
+	Replay(height, width uint16) []byte
+	// Resize is called when the pty is resized.
+	Resize(height, width uint16)
+}
+
+// bufferHistory replays the raw output kept in a ring buffer.  Since the
+// buffer can start in the middle of an escape sequence and the output was
+// drawn for whatever size the pty had at the time, the replay can be garbled.
+type bufferHistory struct {
+	*circbuf.Buffer
+}
+
+func (h bufferHistory) Replay(_, _ uint16) []byte {
+	return slices.Clone(h.Bytes())
+}
+
+func (bufferHistory) Resize(_, _ uint16) {}
+
+// terminalHistory feeds the output into a terminal emulator and replays the
+// emulator's screen and scrollback.
+type terminalHistory struct {
+	*vt.Terminal
+}
+
+func (h terminalHistory) Replay(height, width uint16) []byte {
+	h.Resize(height, width)
+	return h.Render()
+}
+
+func (h terminalHistory) Resize(height, width uint16) {
+	if height == 0 || width == 0 {
+		return
+	}
+	h.Terminal.Resize(int(width), int(height))
+}
+
+// bufferedReconnectingPTY provides a reconnectable PTY by keeping the output in
+// a history that is replayed to each connection.  The history is either a ring
+// buffer of raw output or a terminal emulator.
 type bufferedReconnectingPTY struct {
 	command *pty.Cmd
 
-	activeConns    map[string]net.Conn
-	circularBuffer *circbuf.Buffer
+	activeConns map[string]net.Conn
+	history     history
 
 	ptty    pty.PTYCmd
 	process pty.Process
@@ -37,25 +82,37 @@ type bufferedReconnectingPTY struct {
 	timeout time.Duration
 }
 
-// newBuffered starts the buffered pty.  If the context ends the process will be
-// killed.
+// newBuffered starts the buffered pty with a ring buffer history.  If the
+// context ends the process will be killed.
 func newBuffered(ctx context.Context, cmd *pty.Cmd, options *Options, logger slog.Logger) *bufferedReconnectingPTY {
+	// Default to buffer 64KiB.
+	circularBuffer, err := circbuf.NewBuffer(64 << 10)
+	if err != nil {
+		rpty := &bufferedReconnectingPTY{state: newState()}
+		rpty.state.setState(StateDone, xerrors.Errorf("create circular buffer: %w", err))
+		return rpty
+	}
+	return newBufferedWithHistory(ctx, cmd, options, bufferHistory{circularBuffer}, logger)
+}
+
+// newTerminal starts a buffered pty that keeps its output in a terminal
+// emulator, so new connections get the current screen and scrollback instead
+// of raw output.  If the context ends the process will be killed.
+func newTerminal(ctx context.Context, cmd *pty.Cmd, options *Options, logger slog.Logger) *bufferedReconnectingPTY {
+	// The size is set once the first connection attaches.
+	return newBufferedWithHistory(ctx, cmd, options, terminalHistory{vt.New(80, 24, vt.DefaultScrollback)}, logger)
+}
+
+func newBufferedWithHistory(ctx context.Context, cmd *pty.Cmd, options *Options, history history, logger slog.Logger) *bufferedReconnectingPTY {
 	rpty := &bufferedReconnectingPTY{
 		activeConns: map[string]net.Conn{},
 		command:     cmd,
+		history:     history,
 		metrics:     options.Metrics,
 		state:       newState(),
 		timeout:     options.Timeout,
 	}
 
-	// Default to buffer 64KiB.
-	circularBuffer, err := circbuf.NewBuffer(64 << 10)
-	if err != nil {
-		rpty.state.setState(StateDone, xerrors.Errorf("create circular buffer: %w", err))
-		return rpty
-	}
-	rpty.circularBuffer = circularBuffer
-
 	// Add TERM then start the command with a pty.  pty.Cmd duplicates Path as the
 	// first argument so remove it.
 	cmdWithEnv := pty.CommandContext(ctx, cmd.Path, cmd.Args[1:]...)
@@ -71,7 +128,7 @@ func newBuffered(ctx context.Context, cmd *pty.Cmd, options *Options, logger slo
 
 	go rpty.lifecycle(ctx, logger)
 
-	// Multiplex the output onto the circular buffer and each active connection.
+	// Multiplex the output onto the history and each active connection.
 	// We do not need to separately monitor for the process exiting.  When it
 	// exits, our ptty.OutputReader() will return EOF after reading all process
 	// output.
@@ -97,9 +154,9 @@ func newBuffered(ctx context.Context, cmd *pty.Cmd, options *Options, logger slo
 			}
 			part := buffer[:read]
 			rpty.state.cond.L.Lock()
-			_, err = rpty.circularBuffer.Write(part)
+			_, err = rpty.history.Write(part)
 			if err != nil {
-				logger.Error(ctx, "write to circular buffer", slog.Error(err))
+				logger.Error(ctx, "write to history", slog.Error(err))
 				rpty.metrics.WithLabelValues("write_buffer").Add(1)
 			}
 			// TODO: Instead of ranging over a map, could we send the output to a
@@ -171,14 +228,18 @@ func (rpty *bufferedReconnectingPTY) lifecycle(ctx context.Context, logger slog.
 	rpty.state.setState(StateDone, reasonErr)
 }
 
-func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string, conn net.Conn, height, width uint16, logger slog.Logger) error {
+func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string, conn net.Conn, height, width uint16, mode codersdk.ReconnectingPTYMode, logger slog.Logger) error {
 	logger.Info(ctx, "attach to reconnecting pty")
 
 	// This will kill the heartbeat once we hit EOF or an error.
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	err := rpty.doAttach(connID, conn)
+	// Connections that cannot resize get the output at the current size.
+	if !mode.CanResize() {
+		height, width = 0, 0
+	}
+	err := rpty.doAttach(connID, conn, height, width)
 	if err != nil {
 		return err
 	}
@@ -196,34 +257,37 @@ func (rpty *bufferedReconnectingPTY) Attach(ctx context.Context, connID string,
 
 	go heartbeat(ctx, rpty.timer, rpty.timeout)
 
+	ptty := resizeHistoryPTY{PTYCmd: rpty.ptty, rpty: rpty}
+
 	// Resize the PTY to initial height + width.
-	err = rpty.ptty.Resize(height, width)
-	if err != nil {
-		// We can continue after this, it's not fatal!
-		logger.Warn(ctx, "reconnecting PTY initial resize failed, but will continue", slog.Error(err))
-		rpty.metrics.WithLabelValues("resize").Add(1)
+	if mode.CanResize() {
+		err = ptty.Resize(height, width)
+		if err != nil {
+			// We can continue after this, it's not fatal!
+			logger.Warn(ctx, "reconnecting PTY initial resize failed, but will continue", slog.Error(err))
+			rpty.metrics.WithLabelValues("resize").Add(1)
+		}
 	}
 
 	// Pipe conn -> pty and block.  pty -> conn is handled in newBuffered().
-	readConnLoop(ctx, conn, rpty.ptty, rpty.metrics, logger)
+	readConnLoop(ctx, conn, ptty, mode, rpty.metrics, logger)
 	return nil
 }
 
-// doAttach adds the connection to the map and replays the buffer.  It exists
+// doAttach adds the connection to the map and replays the history.  It exists
 // separately only for convenience to defer the mutex unlock which is not
 // possible in Attach since it blocks.
-func (rpty *bufferedReconnectingPTY) doAttach(connID string, conn net.Conn) error {
+func (rpty *bufferedReconnectingPTY) doAttach(connID string, conn net.Conn, height, width uint16) error {
 	rpty.state.cond.L.Lock()
 	defer rpty.state.cond.L.Unlock()
 
 	// Write any previously stored data for the TTY.  Since the command might be
 	// short-lived and have already exited, make sure we always at least output
-	// the buffer before returning, mostly just so tests pass.
-	prevBuf := slices.Clone(rpty.circularBuffer.Bytes())
-	_, err := conn.Write(prevBuf)
+	// the history before returning, mostly just so tests pass.
+	_, err := conn.Write(rpty.history.Replay(height, width))
 	if err != nil {
 		rpty.metrics.WithLabelValues("write").Add(1)
-		return xerrors.Errorf("write buffer to conn: %w", err)
+		return xerrors.Errorf("write history to conn: %w", err)
 	}
 
 	rpty.activeConns[connID] = conn
@@ -231,6 +295,20 @@ func (rpty *bufferedReconnectingPTY) doAttach(connID string, conn net.Conn) erro
 	return nil
 }
 
+// resizeHistoryPTY resizes the history along with the pty so that the terminal
+// emulator wraps output the same way the program does.
+type resizeHistoryPTY struct {
+	pty.PTYCmd
+	rpty *bufferedReconnectingPTY
+}
+
+func (p resizeHistoryPTY) Resize(height, width uint16) error {
+	p.rpty.state.cond.L.Lock()
+	p.rpty.history.Resize(height, width)
+	p.rpty.state.cond.L.Unlock()
+	return p.PTYCmd.Resize(height, width)
+}
+
 func (rpty *bufferedReconnectingPTY) Wait() {
 	_, _ = rpty.state.waitForState(StateClosing)
 }
@@ -32,18 +32,24 @@ type Options struct {
 	Timeout time.Duration
 	// Metrics tracks various error counters.
 	Metrics *prometheus.CounterVec
+	// BackendType selects the backend: "screen", "terminal" or "buffered".  If
+	// empty, screen is used on Linux when it is installed and the buffered
+	// backend otherwise.
+	BackendType string
 }
 
 // ReconnectingPTY is a pty that can be reconnected within a timeout and to
-// simultaneous connections.  The reconnecting pty can be backed by screen if
-// installed or a (buggy) buffer replay fallback.
+// simultaneous connections.  The reconnecting pty can be backed by screen, a
+// built-in terminal emulator that replays the screen and scrollback, or a
+// (buggy) raw output replay.
 type ReconnectingPTY interface {
 	// Attach pipes the connection and pty, spawning it if necessary, replays
 	// history, then blocks until EOF, an error, or the context's end.  The
 	// connection is expected to send JSON-encoded messages and accept raw output
-	// from the ptty.  If the context ends or the process dies the connection will
-	// be detached.
-	Attach(ctx context.Context, connID string, conn net.Conn, height, width uint16, logger slog.Logger) error
+	// from the ptty.  The mode decides whether the connection's input and
+	// resizes are passed on to the pty.  If the context ends or the process dies
+	// the connection will be detached.
+	Attach(ctx context.Context, connID string, conn net.Conn, height, width uint16, mode codersdk.ReconnectingPTYMode, logger slog.Logger) error
 	// Wait waits for the reconnecting pty to close.  The underlying process might
 	// still be exiting.
 	Wait()
@@ -54,8 +60,8 @@ type ReconnectingPTY interface {
 // New sets up a new reconnecting pty that wraps the provided command.  Any
 // errors with starting are returned on Attach().  The reconnecting pty will
 // close itself (and all connections to it) if nothing is attached for the
-// duration of the timeout, if the context ends, or the process exits (buffered
-// backend only).
+// duration of the timeout, if the context ends, or the process exits (terminal
+// and buffered backends only).
 func New(ctx context.Context, cmd *pty.Cmd, options *Options, logger slog.Logger) ReconnectingPTY {
 	if options.Timeout == 0 {
 		options.Timeout = 5 * time.Minute
@@ -64,11 +70,14 @@ func New(ctx context.Context, cmd *pty.Cmd, options *Options, logger slog.Logger
 	// runs) but in CI screen often incorrectly claims the session name does not
 	// exist even though screen -list shows it.  For now, restrict screen to
 	// Linux.
-	backendType := "buffered"
-	if runtime.GOOS == "linux" {
-		_, err := exec.LookPath("screen")
-		if err == nil {
-			backendType = "screen"
+	backendType := options.BackendType
+	if backendType == "" {
+		backendType = "buffered"
+		if runtime.GOOS == "linux" {
+			_, err := exec.LookPath("screen")
+			if err == nil {
+				backendType = "screen"
+			}
 		}
 	}
 
@@ -77,6 +86,8 @@ func New(ctx context.Context, cmd *pty.Cmd, options *Options, logger slog.Logger
 	switch backendType {
 	case "screen":
 		return newScreen(ctx, cmd, options, logger)
+	case "terminal":
+		return newTerminal(ctx, cmd, options, logger)
 	default:
 		return newBuffered(ctx, cmd, options, logger)
 	}
@@ -192,9 +203,10 @@ func (s *ptyState) waitForStateOrContext(ctx context.Context, state State) (Stat
 	return s.state, s.error
 }
 
-// readConnLoop reads messages from conn and writes to ptty as needed.  Blocks
-// until EOF or an error writing to ptty or reading from conn.
-func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *prometheus.CounterVec, logger slog.Logger) {
+// readConnLoop reads messages from conn and writes to ptty as needed.  Input
+// and resizes are dropped if the mode does not allow them.  Blocks until EOF or
+// an error writing to ptty or reading from conn.
+func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, mode codersdk.ReconnectingPTYMode, metrics *prometheus.CounterVec, logger slog.Logger) {
 	decoder := json.NewDecoder(conn)
 	for {
 		var req codersdk.ReconnectingPTYRequest
@@ -206,14 +218,16 @@ func readConnLoop(ctx context.Context, conn net.Conn, ptty pty.PTYCmd, metrics *
 			logger.Warn(ctx, "reconnecting pty failed with read error", slog.Error(err))
 			return
 		}
-		_, err = ptty.InputWriter().Write([]byte(req.Data))
-		if err != nil {
-			logger.Warn(ctx, "reconnecting pty failed with write error", slog.Error(err))
-			metrics.WithLabelValues("input_writer").Add(1)
-			return
+		if mode.CanWrite() && req.Data != "" {
+			_, err = ptty.InputWriter().Write([]byte(req.Data))
+			if err != nil {
+				logger.Warn(ctx, "reconnecting pty failed with write error", slog.Error(err))
+				metrics.WithLabelValues("input_writer").Add(1)
+				return
+			}
 		}
 		// Check if a resize needs to happen!
-		if req.Height == 0 || req.Width == 0 {
+		if !mode.CanResize() || req.Height == 0 || req.Width == 0 {
 			continue
 		}
 		err = ptty.Resize(req.Height, req.Width)
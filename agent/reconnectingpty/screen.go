@@ -20,6 +20,8 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty"
 )
 
@@ -148,7 +150,7 @@ func (rpty *screenReconnectingPTY) lifecycle(ctx context.Context, logger slog.Lo
 	rpty.state.setState(StateDone, reasonErr)
 }
 
-func (rpty *screenReconnectingPTY) Attach(ctx context.Context, _ string, conn net.Conn, height, width uint16, logger slog.Logger) error {
+func (rpty *screenReconnectingPTY) Attach(ctx context.Context, _ string, conn net.Conn, height, width uint16, mode codersdk.ReconnectingPTYMode, logger slog.Logger) error {
 	logger.Info(ctx, "attach to reconnecting pty")
 
 	// This will kill the heartbeat once we hit EOF or an error.
@@ -187,8 +189,9 @@ func (rpty *screenReconnectingPTY) Attach(ctx context.Context, _ string, conn ne
 		}
 	}()
 
-	// Pipe conn -> pty and block.
-	readConnLoop(ctx, conn, ptty, rpty.metrics, logger)
+	// Pipe conn -> pty and block.  Each connection has its own screen client,
+	// so screen decides the size of the session.
+	readConnLoop(ctx, conn, ptty, mode, rpty.metrics, logger)
 	return nil
 }
 
@@ -0,0 +1,43 @@
+package vt
+
+// charset is a character set that can be designated as G0 or G1.
+type charset int
+
+const (
+	charsetASCII charset = iota
+	// charsetDECGraphics is the DEC special graphics set, which programs use to
+	// draw lines and boxes.
+	charsetDECGraphics
+)
+
+func designate(r rune) charset {
+	if r == '0' {
+		return charsetDECGraphics
+	}
+	return charsetASCII
+}
+
+// designator returns the final character that designates the charset.
+func (c charset) designator() byte {
+	if c == charsetDECGraphics {
+		return '0'
+	}
+	return 'B'
+}
+
+var decGraphics = map[rune]rune{
+	'`': '◆', 'a': '▒', 'b': '␉', 'c': '␌', 'd': '␍', 'e': '␊', 'f': '°', 'g': '±',
+	'h': '␤', 'i': '␋', 'j': '┘', 'k': '┐', 'l': '┌', 'm': '└', 'n': '┼', 'o': '⎺',
+	'p': '⎻', 'q': '─', 'r': '⎼', 's': '⎽', 't': '├', 'u': '┤', 'v': '┴', 'w': '┬',
+	'x': '│', 'y': '≤', 'z': '≥', '{': 'π', '|': '≠', '}': '£', '~': '·',
+}
+
+// translate returns the character that r is displayed as.
+func (c charset) translate(r rune) rune {
+	if c == charsetDECGraphics {
+		if g, ok := decGraphics[r]; ok {
+			return g
+		}
+	}
+	return r
+}
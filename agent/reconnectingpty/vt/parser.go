@@ -0,0 +1,347 @@
+package vt
+
+import (
+	"strconv"
+	"strings"
+)
+
+type parserState int
+
+const (
+	stateGround parserState = iota
+	stateEscape
+	stateCSI
+	stateOSC
+	// stateString ignores device control strings and the like.
+	stateString
+)
+
+const (
+	// maxParams bounds the number of parameters kept for a single sequence.
+	maxParams = 32
+	// maxOSC bounds the length of an operating system command.
+	maxOSC = 4096
+)
+
+// parser holds the state of an escape sequence that is being parsed.
+type parser struct {
+	state         parserState
+	private       rune
+	intermediates []rune
+	// params holds the parameters of a control sequence.  Each holds its
+	// colon-separated sub-parameters, and -1 marks an omitted value.
+	params [][]int
+	osc    strings.Builder
+}
+
+func (p *parser) clear() {
+	p.private = 0
+	p.intermediates = p.intermediates[:0]
+	p.params = p.params[:0]
+}
+
+// param returns the ith parameter, or def if it was omitted or zero.
+func (p *parser) param(i, def int) int {
+	if i >= len(p.params) || p.params[i][0] <= 0 {
+		return def
+	}
+	return p.params[i][0]
+}
+
+// input handles a single character of output.
+func (t *Terminal) input(r rune) {
+	p := &t.parser
+	switch {
+	case r == 0x18 || r == 0x1a:
+		// CAN and SUB abort any sequence.
+		p.state = stateGround
+		return
+	case r == 0x1b:
+		if p.state == stateOSC {
+			t.dispatchOSC()
+		}
+		p.clear()
+		p.state = stateEscape
+		return
+	case p.state == stateOSC:
+		switch {
+		case r == 0x07:
+			t.dispatchOSC()
+			p.state = stateGround
+		case r >= 0x20 && p.osc.Len() < maxOSC:
+			p.osc.WriteRune(r)
+		}
+		return
+	case p.state == stateString:
+		return
+	case r < 0x20 || r == 0x7f:
+		// Control characters take effect even in the middle of a sequence.
+		t.execute(r)
+		return
+	}
+
+	switch p.state {
+	case stateGround:
+		// C1 controls are not used in UTF-8 output.
+		if r < 0x80 || r > 0x9f {
+			t.print(r)
+		}
+	case stateEscape:
+		t.escape(r)
+	case stateCSI:
+		t.csi(r)
+	}
+}
+
+// execute handles a control character.
+func (t *Terminal) execute(r rune) {
+	switch r {
+	case '\b':
+		t.moveHorizontal(-1)
+	case '\t':
+		t.tab(1)
+	case '\n', '\v', '\f':
+		t.lineFeed()
+		if t.newline {
+			t.cur.x = 0
+		}
+	case '\r':
+		t.cur.x = 0
+		t.cur.wrapNext = false
+	case 0x0e:
+		t.cur.shift = 1
+	case 0x0f:
+		t.cur.shift = 0
+	}
+}
+
+// escape handles a character following ESC.
+func (t *Terminal) escape(r rune) {
+	p := &t.parser
+	if r >= 0x20 && r <= 0x2f {
+		if len(p.intermediates) < maxParams {
+			p.intermediates = append(p.intermediates, r)
+		}
+		return
+	}
+	p.state = stateGround
+
+	if len(p.intermediates) > 0 {
+		switch p.intermediates[0] {
+		case '(':
+			t.cur.charsets[0] = designate(r)
+		case ')':
+			t.cur.charsets[1] = designate(r)
+		case '#':
+			if r == '8' {
+				t.alignmentTest()
+			}
+		}
+		return
+	}
+
+	switch r {
+	case '[':
+		p.state = stateCSI
+	case ']':
+		p.osc.Reset()
+		p.state = stateOSC
+	case 'P', 'X', '^', '_':
+		p.state = stateString
+	case '7':
+		t.saveCursor()
+	case '8':
+		t.restoreCursor()
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.lineFeed()
+		t.cur.x = 0
+	case 'M':
+		t.reverseIndex()
+	case 'H':
+		t.tabs[t.cur.x] = true
+	case 'c':
+		t.reset()
+	case '=':
+		t.appKeypad = true
+	case '>':
+		t.appKeypad = false
+	}
+}
+
+// csi handles a character of a control sequence.
+func (t *Terminal) csi(r rune) {
+	p := &t.parser
+	switch {
+	case r >= '0' && r <= '9':
+		if len(p.params) == 0 {
+			p.params = append(p.params, []int{-1})
+		}
+		param := p.params[len(p.params)-1]
+		v := param[len(param)-1]
+		if v < 0 {
+			v = 0
+		}
+		if v < 1<<16 {
+			v = v*10 + int(r-'0')
+		}
+		param[len(param)-1] = v
+	case r == ';':
+		if len(p.params) == 0 {
+			p.params = append(p.params, []int{-1})
+		}
+		if len(p.params) < maxParams {
+			p.params = append(p.params, []int{-1})
+		}
+	case r == ':':
+		if len(p.params) == 0 {
+			p.params = append(p.params, []int{-1})
+		}
+		if last := len(p.params) - 1; len(p.params[last]) < maxParams {
+			p.params[last] = append(p.params[last], -1)
+		}
+	case r >= '<' && r <= '?':
+		if len(p.params) == 0 && len(p.intermediates) == 0 {
+			p.private = r
+		}
+	case r >= 0x20 && r <= 0x2f:
+		if len(p.intermediates) < maxParams {
+			p.intermediates = append(p.intermediates, r)
+		}
+	case r >= 0x40 && r <= 0x7e:
+		p.state = stateGround
+		t.dispatchCSI(r)
+	default:
+		p.state = stateGround
+	}
+}
+
+func (t *Terminal) dispatchCSI(final rune) {
+	p := &t.parser
+	if p.private == '?' {
+		switch final {
+		case 'h':
+			t.setPrivateMode(p.params, true)
+		case 'l':
+			t.setPrivateMode(p.params, false)
+		}
+		return
+	}
+	if p.private != 0 {
+		return
+	}
+	if len(p.intermediates) > 0 {
+		switch string(p.intermediates) + string(final) {
+		case " q":
+			t.cursorStyle = p.param(0, 0)
+		case "!p":
+			t.softReset()
+		}
+		return
+	}
+
+	n := p.param(0, 1)
+	switch final {
+	case '@':
+		t.insertBlanks(n)
+	case 'A':
+		t.moveVertical(-n)
+	case 'B', 'e':
+		t.moveVertical(n)
+	case 'C', 'a':
+		t.moveHorizontal(n)
+	case 'D':
+		t.moveHorizontal(-n)
+	case 'E':
+		t.moveVertical(n)
+		t.cur.x = 0
+	case 'F':
+		t.moveVertical(-n)
+		t.cur.x = 0
+	case 'G', '`':
+		t.cur.x = clamp(n-1, 0, t.cols-1)
+		t.cur.wrapNext = false
+	case 'H', 'f':
+		t.moveTo(p.param(1, 1)-1, n-1)
+	case 'I':
+		t.tab(n)
+	case 'J':
+		t.eraseDisplay(p.param(0, 0))
+	case 'K':
+		t.eraseLine(p.param(0, 0))
+	case 'L':
+		t.insertLines(n)
+	case 'M':
+		t.deleteLines(n)
+	case 'P':
+		t.deleteChars(n)
+	case 'S':
+		t.scrollUp(t.top, t.bottom, n)
+	case 'T':
+		// With more parameters this starts mouse highlight tracking instead.
+		if len(p.params) <= 1 {
+			t.scrollDown(t.top, t.bottom, n)
+		}
+	case 'X':
+		t.eraseCells(t.cur.y, t.cur.x, t.cur.x+n)
+		t.cur.wrapNext = false
+	case 'Z':
+		t.tab(-n)
+	case 'b':
+		if t.lastChar != 0 {
+			for i := 0; i < n && i < t.cols*t.rows; i++ {
+				t.print(t.lastChar)
+			}
+		}
+	case 'd':
+		t.moveTo(t.cur.x, n-1)
+	case 'g':
+		switch p.param(0, 0) {
+		case 0:
+			t.tabs[t.cur.x] = false
+		case 3:
+			for i := range t.tabs {
+				t.tabs[i] = false
+			}
+		}
+	case 'h':
+		t.setMode(p.params, true)
+	case 'l':
+		t.setMode(p.params, false)
+	case 'm':
+		t.cur.attrs.applySGR(p.params)
+	case 'r':
+		t.setScrollRegion(n-1, p.param(1, t.rows)-1)
+	case 's':
+		t.saveCursor()
+	case 'u':
+		t.restoreCursor()
+	}
+}
+
+// dispatchOSC handles an operating system command.  Only the window title is
+// kept.
+func (t *Terminal) dispatchOSC() {
+	command, arg, ok := strings.Cut(t.parser.osc.String(), ";")
+	t.parser.osc.Reset()
+	if !ok {
+		return
+	}
+	switch n, _ := strconv.Atoi(command); n {
+	case 0, 2:
+		t.title = arg
+	}
+}
+
+// alignmentTest implements DECALN, which fills the screen with "E".
+func (t *Terminal) alignmentTest() {
+	for _, l := range t.lines() {
+		for x := range l {
+			l[x] = cell{r: 'E'}
+		}
+	}
+	t.top, t.bottom = 0, t.rows-1
+	t.cur.origin = false
+	t.moveTo(0, 0)
+}
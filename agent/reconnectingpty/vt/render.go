@@ -0,0 +1,234 @@
+package vt
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+)
+
+// ring keeps the most recent lines up to its capacity.
+type ring struct {
+	lines []string
+	start int
+	max   int
+}
+
+func newRing(max int) *ring {
+	if max < 0 {
+		max = 0
+	}
+	return &ring{max: max}
+}
+
+func (r *ring) push(s string) {
+	if r.max == 0 {
+		return
+	}
+	if len(r.lines) < r.max {
+		r.lines = append(r.lines, s)
+		return
+	}
+	r.lines[r.start] = s
+	r.start = (r.start + 1) % r.max
+}
+
+func (r *ring) clear() {
+	r.lines = nil
+	r.start = 0
+}
+
+// each calls fn with each line, oldest first.
+func (r *ring) each(fn func(s string)) {
+	for i := range r.lines {
+		fn(r.lines[(r.start+i)%len(r.lines)])
+	}
+}
+
+// renderLine returns the characters and attributes of a line, starting and
+// ending with the default attributes.  Trailing blanks are omitted.
+func renderLine(l line) string {
+	var b bytes.Buffer
+	writeLine(&b, l)
+	return b.String()
+}
+
+func writeLine(b *bytes.Buffer, l line) {
+	end := len(l)
+	for end > 0 && l[end-1].r <= 0 && l[end-1].attrs == (attrs{}) {
+		end--
+	}
+	var current attrs
+	for x := 0; x < end; x++ {
+		c := l[x]
+		if c.attrs != current {
+			c.attrs.writeSGR(b)
+			current = c.attrs
+		}
+		writeCell(b, l, x)
+	}
+	if current != (attrs{}) {
+		b.WriteString("\x1b[m")
+	}
+}
+
+// writeCell writes the character in a cell, keeping the column alignment if
+// the cell holds half of a double-width character.
+func writeCell(b *bytes.Buffer, l line, x int) {
+	c := l[x]
+	switch {
+	case c.r == continuation:
+		// The first half was written with the character before.
+		if x > 0 && l[x-1].r > 0 && widths.RuneWidth(l[x-1].r) == 2 {
+			return
+		}
+		b.WriteByte(' ')
+	case c.r == 0:
+		b.WriteByte(' ')
+	case widths.RuneWidth(c.r) == 2 && (x+1 >= len(l) || l[x+1].r != continuation):
+		b.WriteByte(' ')
+	default:
+		b.WriteRune(c.r)
+		b.WriteString(c.comb)
+	}
+}
+
+func writeCUP(b *bytes.Buffer, x, y int) {
+	b.WriteString("\x1b[" + strconv.Itoa(y+1) + ";" + strconv.Itoa(x+1) + "H")
+}
+
+// writeSavedCursor writes the sequences that make the terminal's DECSC save c.
+func writeSavedCursor(b *bytes.Buffer, c cursor) {
+	// Origin mode moves the cursor home, so enable it first.  The scrolling
+	// region is not set yet, so the position is absolute either way.
+	if c.origin {
+		b.WriteString("\x1b[?6h")
+	}
+	writeCUP(b, c.x, c.y)
+	c.attrs.writeSGR(b)
+	writeCharsets(b, c)
+	b.WriteString("\x1b7")
+	if c.origin {
+		b.WriteString("\x1b[?6l")
+	}
+	writeCharsets(b, cursor{})
+}
+
+// writeCharsets designates the character sets of the cursor.
+func writeCharsets(b *bytes.Buffer, c cursor) {
+	b.WriteString("\x1b(")
+	b.WriteByte(c.charsets[0].designator())
+	b.WriteString("\x1b)")
+	b.WriteByte(c.charsets[1].designator())
+	if c.shift == 1 {
+		b.WriteByte(0x0e)
+	} else {
+		b.WriteByte(0x0f)
+	}
+}
+
+// Render returns output that draws the terminal's scrollback and screen on a
+// terminal of the same size, and restores its cursor, modes and title.  It is
+// meant to be written to a terminal that has just been reset, for example when
+// a new client attaches.
+func (t *Terminal) Render() []byte {
+	var b bytes.Buffer
+	b.WriteString("\x1b[m\x1b[H\x1b[2J")
+
+	// Writing the scrollback and the main screen line by line scrolls the
+	// scrollback off the top of the screen and leaves the main screen in place.
+	first := true
+	newLine := func() {
+		if !first {
+			b.WriteString("\r\n")
+		}
+		first = false
+	}
+	t.scrollback.each(func(s string) {
+		newLine()
+		b.WriteString(s)
+	})
+	for _, l := range t.screens[0] {
+		newLine()
+		writeLine(&b, l)
+	}
+
+	if t.altActive {
+		// Entering the alternate screen saves the main screen's cursor, which is
+		// restored when the alternate screen is left.
+		mainCursor := cursor{}
+		if t.saved[0] != nil {
+			mainCursor = *t.saved[0]
+		}
+		writeCUP(&b, mainCursor.x, mainCursor.y)
+		mainCursor.attrs.writeSGR(&b)
+		writeCharsets(&b, mainCursor)
+		b.WriteString("\x1b[?1049h\x1b[m")
+		writeCharsets(&b, cursor{})
+		for y, l := range t.screens[1] {
+			writeCUP(&b, 0, y)
+			writeLine(&b, l)
+		}
+	}
+	if saved := t.saved[t.screen()]; saved != nil {
+		writeSavedCursor(&b, *saved)
+	}
+
+	if t.top != 0 || t.bottom != t.rows-1 {
+		b.WriteString("\x1b[" + strconv.Itoa(t.top+1) + ";" + strconv.Itoa(t.bottom+1) + "r")
+	}
+	top := 0
+	if t.cur.origin {
+		b.WriteString("\x1b[?6h")
+		top = t.top
+	}
+
+	// Put the cursor back, rewriting the character before it if the next
+	// character wraps.
+	l := t.lines()[t.cur.y]
+	if t.cur.wrapNext {
+		x := t.cur.x
+		if l[x].r == continuation && x > 0 {
+			x--
+		}
+		writeCUP(&b, x, t.cur.y-top)
+		l[x].attrs.writeSGR(&b)
+		writeCell(&b, l, x)
+	} else {
+		writeCUP(&b, t.cur.x, t.cur.y-top)
+	}
+	t.cur.attrs.writeSGR(&b)
+	if t.cur.charsets != [2]charset{} || t.cur.shift != 0 {
+		writeCharsets(&b, t.cur)
+	}
+
+	if !t.autowrap {
+		b.WriteString("\x1b[?7l")
+	}
+	if t.insert {
+		b.WriteString("\x1b[4h")
+	}
+	if t.newline {
+		b.WriteString("\x1b[20h")
+	}
+	if t.appKeypad {
+		b.WriteString("\x1b=")
+	}
+	modes := make([]int, 0, len(t.privateModes))
+	for mode := range t.privateModes {
+		modes = append(modes, mode)
+	}
+	sort.Ints(modes)
+	for _, mode := range modes {
+		b.WriteString("\x1b[?" + strconv.Itoa(mode) + "h")
+	}
+	if t.cursorStyle != 0 {
+		b.WriteString("\x1b[" + strconv.Itoa(t.cursorStyle) + " q")
+	}
+	if t.title != "" {
+		b.WriteString("\x1b]2;" + t.title + "\x07")
+	}
+	if t.cursorHidden {
+		b.WriteString("\x1b[?25l")
+	}
+	return b.Bytes()
+}
@@ -0,0 +1,211 @@
+package vt
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// color is a foreground or background color.  The high byte holds the kind of
+// color and the low bytes the palette index or RGB value.
+type color uint32
+
+const (
+	colorDefault color = 0
+	colorIndexed color = 1 << 24
+	colorRGB     color = 2 << 24
+
+	colorKindMask color = 0xff << 24
+)
+
+type attrFlags uint16
+
+const (
+	attrBold attrFlags = 1 << iota
+	attrFaint
+	attrItalic
+	attrUnderline
+	attrBlink
+	attrReverse
+	attrInvisible
+	attrStrikethrough
+)
+
+// attrs are the graphic rendition of a cell.
+type attrs struct {
+	fg, bg color
+	flags  attrFlags
+}
+
+// sgrFlags maps each attribute to the parameter that sets it.
+var sgrFlags = []struct {
+	flag  attrFlags
+	param int
+}{
+	{attrBold, 1},
+	{attrFaint, 2},
+	{attrItalic, 3},
+	{attrUnderline, 4},
+	{attrBlink, 5},
+	{attrReverse, 7},
+	{attrInvisible, 8},
+	{attrStrikethrough, 9},
+}
+
+// writeSGR writes the sequence that sets the attributes from scratch.
+func (a attrs) writeSGR(b *bytes.Buffer) {
+	b.WriteString("\x1b[0")
+	for _, f := range sgrFlags {
+		if a.flags&f.flag != 0 {
+			b.WriteByte(';')
+			b.WriteString(strconv.Itoa(f.param))
+		}
+	}
+	writeColor(b, a.fg, 30, 90, 38)
+	writeColor(b, a.bg, 40, 100, 48)
+	b.WriteByte('m')
+}
+
+func writeColor(b *bytes.Buffer, c color, base, brightBase, extended int) {
+	switch c & colorKindMask {
+	case colorIndexed:
+		index := int(c &^ colorKindMask)
+		switch {
+		case index < 8:
+			b.WriteString(";" + strconv.Itoa(base+index))
+		case index < 16:
+			b.WriteString(";" + strconv.Itoa(brightBase+index-8))
+		default:
+			b.WriteString(";" + strconv.Itoa(extended) + ";5;" + strconv.Itoa(index))
+		}
+	case colorRGB:
+		b.WriteString(";" + strconv.Itoa(extended) + ";2;" +
+			strconv.Itoa(int(c>>16&0xff)) + ";" + strconv.Itoa(int(c>>8&0xff)) + ";" + strconv.Itoa(int(c&0xff)))
+	}
+}
+
+// applySGR updates the attributes with the parameters of an SGR sequence.
+// Each parameter holds its colon-separated sub-parameters.
+func (a *attrs) applySGR(params [][]int) {
+	if len(params) == 0 {
+		*a = attrs{}
+		return
+	}
+	for i := 0; i < len(params); i++ {
+		p := params[i][0]
+		switch {
+		case p <= 0:
+			*a = attrs{}
+		case p == 1:
+			a.flags |= attrBold
+		case p == 2:
+			a.flags |= attrFaint
+		case p == 3:
+			a.flags |= attrItalic
+		case p == 4, p == 21:
+			a.flags |= attrUnderline
+			// "4:0" turns underlining off.
+			if len(params[i]) > 1 && params[i][1] == 0 {
+				a.flags &^= attrUnderline
+			}
+		case p == 5, p == 6:
+			a.flags |= attrBlink
+		case p == 7:
+			a.flags |= attrReverse
+		case p == 8:
+			a.flags |= attrInvisible
+		case p == 9:
+			a.flags |= attrStrikethrough
+		case p == 22:
+			a.flags &^= attrBold | attrFaint
+		case p == 23:
+			a.flags &^= attrItalic
+		case p == 24:
+			a.flags &^= attrUnderline
+		case p == 25:
+			a.flags &^= attrBlink
+		case p == 27:
+			a.flags &^= attrReverse
+		case p == 28:
+			a.flags &^= attrInvisible
+		case p == 29:
+			a.flags &^= attrStrikethrough
+		case p >= 30 && p <= 37:
+			a.fg = colorIndexed | color(p-30)
+		case p == 38:
+			var consumed int
+			a.fg, consumed = extendedColor(params, i)
+			i += consumed
+		case p == 39:
+			a.fg = colorDefault
+		case p >= 40 && p <= 47:
+			a.bg = colorIndexed | color(p-40)
+		case p == 48:
+			var consumed int
+			a.bg, consumed = extendedColor(params, i)
+			i += consumed
+		case p == 49:
+			a.bg = colorDefault
+		case p >= 90 && p <= 97:
+			a.fg = colorIndexed | color(p-90+8)
+		case p >= 100 && p <= 107:
+			a.bg = colorIndexed | color(p-100+8)
+		}
+	}
+}
+
+// extendedColor parses a 256-color or RGB color starting at params[i], which
+// is 38 or 48.  It supports both the "38;5;n" form and the "38:5:n" form with
+// sub-parameters, and returns the number of extra parameters consumed.
+func extendedColor(params [][]int, i int) (color, int) {
+	values := params[i][1:]
+	consumed := 0
+	if len(values) == 0 {
+		// Semicolon form, so the values are the following parameters.
+		for _, p := range params[i+1:] {
+			values = append(values, p[0])
+		}
+	}
+	if len(values) == 0 {
+		return colorDefault, 0
+	}
+	switch values[0] {
+	case 5:
+		if len(values) < 2 {
+			return colorDefault, len(values)
+		}
+		if len(params[i]) == 1 {
+			consumed = 2
+		}
+		return colorIndexed | color(clampByte(values[1])), consumed
+	case 2:
+		// The colon form may include a color space ID before the components.
+		if len(params[i]) > 1 && len(values) >= 5 {
+			values = append([]int{2}, values[2:]...)
+		}
+		if len(values) < 4 {
+			if len(params[i]) == 1 {
+				consumed = len(values)
+			}
+			return colorDefault, consumed
+		}
+		if len(params[i]) == 1 {
+			consumed = 4
+		}
+		return colorRGB | color(clampByte(values[1]))<<16 | color(clampByte(values[2]))<<8 | color(clampByte(values[3])), consumed
+	default:
+		if len(params[i]) == 1 {
+			consumed = 1
+		}
+		return colorDefault, consumed
+	}
+}
+
+func clampByte(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return v
+}
@@ -0,0 +1,714 @@
+// Package vt implements a headless VT100/xterm terminal emulator.  It tracks
+// the screen contents, scrollback, cursor and modes of a terminal from the
+// output written to it, so that the terminal can be redrawn elsewhere later.
+package vt
+
+import (
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// DefaultScrollback is the number of lines kept after they scroll off the top
+// of the screen.
+const DefaultScrollback = 1000
+
+// continuation marks the cell that is covered by a double-width character in
+// the cell before it.
+const continuation rune = -1
+
+// widths measures characters independently of the agent's locale.
+var widths = &runewidth.Condition{}
+
+// cell is a single character on the screen.  A zero rune is a blank.
+type cell struct {
+	r rune
+	// comb holds any combining characters that follow r.
+	comb string
+	attrs
+}
+
+type line []cell
+
+// cursor is the cursor position along with the state that DECSC saves.
+type cursor struct {
+	x, y  int
+	attrs attrs
+	// wrapNext is set when a character was written to the last column, so the
+	// next character wraps onto the next line.
+	wrapNext bool
+	origin   bool
+	charsets [2]charset
+	shift    int
+}
+
+// Terminal is a headless terminal.  It is not safe for concurrent use.
+type Terminal struct {
+	cols, rows int
+
+	// scrollback holds the rendered lines that scrolled off the top of the main
+	// screen.
+	scrollback *ring
+
+	screens   [2][]line
+	altActive bool
+
+	cur cursor
+	// saved holds the cursor saved by DECSC for each screen, if any.
+	saved [2]*cursor
+
+	// top and bottom are the scrolling region, inclusive.
+	top, bottom int
+	tabs        []bool
+
+	autowrap     bool
+	insert       bool
+	newline      bool
+	cursorHidden bool
+	appKeypad    bool
+	cursorStyle  int
+	title        string
+	lastChar     rune
+	privateModes map[int]bool
+	pendingUTF8  []byte
+	parser       parser
+}
+
+// New creates a terminal of the given size that keeps up to scrollback lines
+// of history.
+func New(cols, rows, scrollback int) *Terminal {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	t := &Terminal{
+		cols:       cols,
+		rows:       rows,
+		scrollback: newRing(scrollback),
+	}
+	t.screens[0] = newScreen(cols, rows)
+	t.screens[1] = newScreen(cols, rows)
+	t.reset()
+	return t
+}
+
+func newScreen(cols, rows int) []line {
+	screen := make([]line, rows)
+	for i := range screen {
+		screen[i] = make(line, cols)
+	}
+	return screen
+}
+
+// Size returns the size of the terminal.
+func (t *Terminal) Size() (cols, rows int) {
+	return t.cols, t.rows
+}
+
+// reset returns the terminal to its initial state.  The scrollback is kept.
+func (t *Terminal) reset() {
+	t.altActive = false
+	for _, screen := range t.screens {
+		for _, l := range screen {
+			clearCells(l, attrs{})
+		}
+	}
+	t.cur = cursor{}
+	t.saved = [2]*cursor{}
+	t.top, t.bottom = 0, t.rows-1
+	t.resetTabs(0)
+	t.autowrap = true
+	t.insert = false
+	t.newline = false
+	t.cursorHidden = false
+	t.appKeypad = false
+	t.cursorStyle = 0
+	t.title = ""
+	t.lastChar = 0
+	t.privateModes = map[int]bool{}
+}
+
+// softReset implements DECSTR.
+func (t *Terminal) softReset() {
+	t.insert = false
+	t.autowrap = true
+	t.cursorHidden = false
+	t.appKeypad = false
+	delete(t.privateModes, 1)
+	t.cur.origin = false
+	t.cur.attrs = attrs{}
+	t.cur.charsets = [2]charset{}
+	t.cur.shift = 0
+	t.saved[t.screen()] = nil
+	t.top, t.bottom = 0, t.rows-1
+}
+
+// resetTabs sets a tab stop every eight columns from column from onwards.
+func (t *Terminal) resetTabs(from int) {
+	if len(t.tabs) < t.cols {
+		t.tabs = append(t.tabs, make([]bool, t.cols-len(t.tabs))...)
+	}
+	t.tabs = t.tabs[:t.cols]
+	for x := from; x < t.cols; x++ {
+		t.tabs[x] = x > 0 && x%8 == 0
+	}
+}
+
+// screen returns the index of the active screen.
+func (t *Terminal) screen() int {
+	if t.altActive {
+		return 1
+	}
+	return 0
+}
+
+// lines returns the lines of the active screen.
+func (t *Terminal) lines() []line {
+	return t.screens[t.screen()]
+}
+
+// clearCells erases the cells, which keep the background color of a.
+func clearCells(l line, a attrs) {
+	for i := range l {
+		l[i] = cell{attrs: attrs{bg: a.bg}}
+	}
+}
+
+// Write updates the terminal with output from the program running in it.  It
+// never fails.  Incomplete UTF-8 sequences at the end of p are held back until
+// the next write.
+func (t *Terminal) Write(p []byte) (int, error) {
+	data := p
+	if len(t.pendingUTF8) > 0 {
+		data = append(t.pendingUTF8, p...)
+		t.pendingUTF8 = nil
+	}
+	for len(data) > 0 {
+		r, size := rune(data[0]), 1
+		if r >= utf8.RuneSelf {
+			if !utf8.FullRune(data) {
+				t.pendingUTF8 = append([]byte(nil), data...)
+				break
+			}
+			r, size = utf8.DecodeRune(data)
+		}
+		t.input(r)
+		data = data[size:]
+	}
+	return len(p), nil
+}
+
+// Resize changes the size of the terminal.  Lines that no longer fit above the
+// cursor move into the scrollback, and lines are truncated or padded to the
+// new width.
+func (t *Terminal) Resize(cols, rows int) {
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	if cols == t.cols && rows == t.rows {
+		return
+	}
+
+	// Follow the main screen's cursor, which is saved while the alternate
+	// screen is active.
+	mainCursor := &t.cur
+	if t.altActive {
+		mainCursor = t.saved[0]
+	}
+	for i := range t.screens {
+		screen := t.screens[i]
+		cursorY := t.rows - 1
+		if i == t.screen() {
+			cursorY = t.cur.y
+		} else if i == 0 && mainCursor != nil {
+			cursorY = mainCursor.y
+		}
+
+		// Drop blank lines below the cursor before scrolling lines off the top.
+		for len(screen) > rows && len(screen)-1 > cursorY && isBlank(screen[len(screen)-1]) {
+			screen = screen[:len(screen)-1]
+		}
+		if excess := len(screen) - rows; excess > 0 {
+			if i == 0 {
+				for _, l := range screen[:excess] {
+					t.scrollback.push(renderLine(l))
+				}
+			}
+			screen = screen[excess:]
+			t.shiftCursors(i, -excess)
+		}
+		for len(screen) < rows {
+			screen = append(screen, make(line, cols))
+		}
+		for y, l := range screen {
+			screen[y] = resizeLine(l, cols)
+		}
+		t.screens[i] = screen
+	}
+
+	t.cols, t.rows = cols, rows
+	oldTabs := len(t.tabs)
+	t.resetTabs(oldTabs)
+	t.top, t.bottom = 0, rows-1
+	t.clampCursor(&t.cur)
+	t.cur.wrapNext = false
+	for _, saved := range t.saved {
+		if saved != nil {
+			t.clampCursor(saved)
+		}
+	}
+}
+
+// shiftCursors moves the cursors that belong to the screen by dy lines.
+func (t *Terminal) shiftCursors(screen, dy int) {
+	if screen == t.screen() {
+		t.cur.y += dy
+	}
+	if saved := t.saved[screen]; saved != nil {
+		saved.y += dy
+	}
+}
+
+func (t *Terminal) clampCursor(c *cursor) {
+	c.x = clamp(c.x, 0, t.cols-1)
+	c.y = clamp(c.y, 0, t.rows-1)
+}
+
+func resizeLine(l line, cols int) line {
+	if len(l) > cols {
+		l = l[:cols]
+		// Do not leave half of a double-width character behind.
+		if last := l[cols-1]; last.r != continuation && widths.RuneWidth(last.r) == 2 {
+			l[cols-1] = cell{attrs: last.attrs}
+		}
+		return l
+	}
+	if len(l) < cols {
+		l = append(l, make(line, cols-len(l))...)
+	}
+	return l
+}
+
+func isBlank(l line) bool {
+	for _, c := range l {
+		if c.r != 0 || c.attrs != (attrs{}) {
+			return false
+		}
+	}
+	return true
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// print writes a printable character at the cursor.
+func (t *Terminal) print(r rune) {
+	r = t.cur.charsets[t.cur.shift].translate(r)
+	width := widths.RuneWidth(r)
+	if width == 0 {
+		t.combine(r)
+		return
+	}
+	if width > t.cols {
+		width = 1
+	}
+
+	if t.cur.wrapNext && t.autowrap {
+		t.cur.x = 0
+		t.lineFeed()
+	}
+	if t.cur.x+width > t.cols {
+		if t.autowrap {
+			t.cur.x = 0
+			t.lineFeed()
+		} else {
+			t.cur.x = t.cols - width
+		}
+	}
+	if t.insert {
+		t.insertBlanks(width)
+	}
+
+	l := t.lines()[t.cur.y]
+	x := t.cur.x
+	// Erase any double-width character that is partially overwritten.
+	if l[x].r == continuation && x > 0 {
+		l[x-1].r, l[x-1].comb = 0, ""
+	}
+	if end := x + width; end < t.cols && l[end].r == continuation {
+		l[end].r = 0
+	}
+	l[x] = cell{r: r, attrs: t.cur.attrs}
+	if width == 2 {
+		l[x+1] = cell{r: continuation, attrs: t.cur.attrs}
+	}
+	t.lastChar = r
+
+	if x+width >= t.cols {
+		t.cur.x = t.cols - 1
+		t.cur.wrapNext = t.autowrap
+	} else {
+		t.cur.x = x + width
+		t.cur.wrapNext = false
+	}
+}
+
+// combine adds a zero-width character to the previously written character.
+func (t *Terminal) combine(r rune) {
+	x := t.cur.x
+	if !t.cur.wrapNext {
+		x--
+	}
+	l := t.lines()[t.cur.y]
+	if x >= 0 && l[x].r == continuation {
+		x--
+	}
+	if x < 0 || l[x].r == 0 {
+		return
+	}
+	if len(l[x].comb) < 32 {
+		l[x].comb += string(r)
+	}
+}
+
+// lineFeed moves the cursor down, scrolling at the bottom of the scrolling
+// region.
+func (t *Terminal) lineFeed() {
+	t.cur.wrapNext = false
+	switch {
+	case t.cur.y == t.bottom:
+		t.scrollUp(t.top, t.bottom, 1)
+	case t.cur.y < t.rows-1:
+		t.cur.y++
+	}
+}
+
+// reverseIndex moves the cursor up, scrolling at the top of the scrolling
+// region.
+func (t *Terminal) reverseIndex() {
+	t.cur.wrapNext = false
+	switch {
+	case t.cur.y == t.top:
+		t.scrollDown(t.top, t.bottom, 1)
+	case t.cur.y > 0:
+		t.cur.y--
+	}
+}
+
+// scrollUp scrolls the lines from top to bottom up by n lines.  Lines that
+// scroll off the top of the main screen are kept in the scrollback.
+func (t *Terminal) scrollUp(top, bottom, n int) {
+	n = clamp(n, 0, bottom-top+1)
+	if n == 0 {
+		return
+	}
+	lines := t.lines()
+	removed := make([]line, n)
+	copy(removed, lines[top:top+n])
+	if top == 0 && !t.altActive {
+		for _, l := range removed {
+			t.scrollback.push(renderLine(l))
+		}
+	}
+	copy(lines[top:], lines[top+n:bottom+1])
+	for i, l := range removed {
+		clearCells(l, t.cur.attrs)
+		lines[bottom-n+1+i] = l
+	}
+}
+
+// scrollDown scrolls the lines from top to bottom down by n lines.
+func (t *Terminal) scrollDown(top, bottom, n int) {
+	n = clamp(n, 0, bottom-top+1)
+	if n == 0 {
+		return
+	}
+	lines := t.lines()
+	removed := make([]line, n)
+	copy(removed, lines[bottom-n+1:bottom+1])
+	copy(lines[top+n:], lines[top:bottom-n+1])
+	for i, l := range removed {
+		clearCells(l, t.cur.attrs)
+		lines[top+i] = l
+	}
+}
+
+// moveTo moves the cursor to the position, relative to the scrolling region in
+// origin mode.
+func (t *Terminal) moveTo(x, y int) {
+	if t.cur.origin {
+		y = clamp(y+t.top, t.top, t.bottom)
+	}
+	t.cur.x = clamp(x, 0, t.cols-1)
+	t.cur.y = clamp(y, 0, t.rows-1)
+	t.cur.wrapNext = false
+}
+
+// moveVertical moves the cursor up or down, stopping at the scrolling region's
+// margins if the cursor is inside it.
+func (t *Terminal) moveVertical(dy int) {
+	y := t.cur.y + dy
+	lo, hi := 0, t.rows-1
+	if t.cur.y >= t.top && t.cur.y <= t.bottom {
+		lo, hi = t.top, t.bottom
+	}
+	t.cur.y = clamp(y, lo, hi)
+	t.cur.wrapNext = false
+}
+
+func (t *Terminal) moveHorizontal(dx int) {
+	t.cur.x = clamp(t.cur.x+dx, 0, t.cols-1)
+	t.cur.wrapNext = false
+}
+
+// tab moves the cursor n tab stops forwards, or backwards if n is negative.
+func (t *Terminal) tab(n int) {
+	x := t.cur.x
+	for ; n > 0 && x < t.cols-1; n-- {
+		for x++; x < t.cols-1 && !t.tabs[x]; x++ {
+		}
+	}
+	for ; n < 0 && x > 0; n++ {
+		for x--; x > 0 && !t.tabs[x]; x-- {
+		}
+	}
+	t.cur.x = x
+	t.cur.wrapNext = false
+}
+
+func (t *Terminal) eraseCells(y, from, to int) {
+	l := t.lines()[y]
+	from = clamp(from, 0, t.cols)
+	to = clamp(to, 0, t.cols)
+	if from < to {
+		// Do not leave half of a double-width character behind.
+		if l[from].r == continuation && from > 0 {
+			l[from-1].r, l[from-1].comb = 0, ""
+		}
+		if to < t.cols && l[to].r == continuation {
+			l[to].r = 0
+		}
+		clearCells(l[from:to], t.cur.attrs)
+	}
+}
+
+// eraseDisplay implements ED.
+func (t *Terminal) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		t.eraseCells(t.cur.y, t.cur.x, t.cols)
+		for y := t.cur.y + 1; y < t.rows; y++ {
+			t.eraseCells(y, 0, t.cols)
+		}
+	case 1:
+		for y := 0; y < t.cur.y; y++ {
+			t.eraseCells(y, 0, t.cols)
+		}
+		t.eraseCells(t.cur.y, 0, t.cur.x+1)
+	case 2:
+		for y := 0; y < t.rows; y++ {
+			t.eraseCells(y, 0, t.cols)
+		}
+	case 3:
+		t.scrollback.clear()
+	}
+}
+
+// eraseLine implements EL.
+func (t *Terminal) eraseLine(mode int) {
+	switch mode {
+	case 0:
+		t.eraseCells(t.cur.y, t.cur.x, t.cols)
+	case 1:
+		t.eraseCells(t.cur.y, 0, t.cur.x+1)
+	case 2:
+		t.eraseCells(t.cur.y, 0, t.cols)
+	}
+}
+
+// insertBlanks implements ICH.
+func (t *Terminal) insertBlanks(n int) {
+	l := t.lines()[t.cur.y]
+	x := t.cur.x
+	n = clamp(n, 0, t.cols-x)
+	copy(l[x+n:], l[x:t.cols-n])
+	t.eraseCells(t.cur.y, x, x+n)
+	t.cur.wrapNext = false
+}
+
+// deleteChars implements DCH.
+func (t *Terminal) deleteChars(n int) {
+	l := t.lines()[t.cur.y]
+	x := t.cur.x
+	n = clamp(n, 0, t.cols-x)
+	copy(l[x:], l[x+n:])
+	t.eraseCells(t.cur.y, t.cols-n, t.cols)
+	t.cur.wrapNext = false
+}
+
+// insertLines implements IL.
+func (t *Terminal) insertLines(n int) {
+	if t.cur.y < t.top || t.cur.y > t.bottom {
+		return
+	}
+	t.scrollDown(t.cur.y, t.bottom, n)
+	t.cur.x = 0
+	t.cur.wrapNext = false
+}
+
+// deleteLines implements DL.
+func (t *Terminal) deleteLines(n int) {
+	if t.cur.y < t.top || t.cur.y > t.bottom {
+		return
+	}
+	// Deleted lines never go into the scrollback.
+	lines := t.lines()
+	n = clamp(n, 0, t.bottom-t.cur.y+1)
+	removed := make([]line, n)
+	copy(removed, lines[t.cur.y:t.cur.y+n])
+	copy(lines[t.cur.y:], lines[t.cur.y+n:t.bottom+1])
+	for i, l := range removed {
+		clearCells(l, t.cur.attrs)
+		lines[t.bottom-n+1+i] = l
+	}
+	t.cur.x = 0
+	t.cur.wrapNext = false
+}
+
+// setScrollRegion implements DECSTBM.  top and bottom are zero-based and
+// inclusive.
+func (t *Terminal) setScrollRegion(top, bottom int) {
+	top = clamp(top, 0, t.rows-1)
+	bottom = clamp(bottom, 0, t.rows-1)
+	if top >= bottom {
+		return
+	}
+	t.top, t.bottom = top, bottom
+	t.moveTo(0, 0)
+}
+
+// saveCursor implements DECSC.
+func (t *Terminal) saveCursor() {
+	saved := t.cur
+	t.saved[t.screen()] = &saved
+}
+
+// restoreCursor implements DECRC.
+func (t *Terminal) restoreCursor() {
+	saved := t.saved[t.screen()]
+	if saved == nil {
+		t.cur = cursor{}
+		return
+	}
+	t.cur = *saved
+	t.clampCursor(&t.cur)
+}
+
+// setAltScreen switches between the main and alternate screens.
+func (t *Terminal) setAltScreen(active, clearAlt bool) {
+	if active == t.altActive {
+		if active && clearAlt {
+			t.eraseDisplay(2)
+		}
+		return
+	}
+	if !active && clearAlt {
+		t.eraseDisplay(2)
+	}
+	t.altActive = active
+	if active && clearAlt {
+		t.eraseDisplay(2)
+	}
+}
+
+// setMode implements SM and RM.
+func (t *Terminal) setMode(params [][]int, set bool) {
+	for _, p := range params {
+		switch p[0] {
+		case 4:
+			t.insert = set
+		case 20:
+			t.newline = set
+		}
+	}
+}
+
+// replayedModes are DEC private modes that do not change how output is
+// emulated, but change how the terminal behaves for the user, so they are
+// restored when the terminal is redrawn.
+var replayedModes = map[int]bool{
+	1:    true, // Application cursor keys.
+	5:    true, // Reverse video.
+	9:    true, // X10 mouse reporting.
+	12:   true, // Blinking cursor.
+	66:   true, // Application keypad.
+	1000: true, // Mouse reporting.
+	1001: true,
+	1002: true,
+	1003: true,
+	1004: true, // Focus reporting.
+	1005: true, // Mouse report encodings.
+	1006: true,
+	1015: true,
+	1016: true,
+	2004: true, // Bracketed paste.
+}
+
+// setPrivateMode implements DECSET and DECRST.
+func (t *Terminal) setPrivateMode(params [][]int, set bool) {
+	for _, p := range params {
+		mode := p[0]
+		switch mode {
+		case 6:
+			t.cur.origin = set
+			t.moveTo(0, 0)
+		case 7:
+			t.autowrap = set
+			if !set {
+				t.cur.wrapNext = false
+			}
+		case 25:
+			t.cursorHidden = !set
+		case 47:
+			t.setAltScreen(set, false)
+		case 1047:
+			t.setAltScreen(set, !set)
+		case 1048:
+			if set {
+				t.saveCursor()
+			} else {
+				t.restoreCursor()
+			}
+		case 1049:
+			if set {
+				if !t.altActive {
+					t.saveCursor()
+				}
+				t.setAltScreen(true, true)
+			} else if t.altActive {
+				t.setAltScreen(false, false)
+				t.restoreCursor()
+			}
+		default:
+			if replayedModes[mode] {
+				if set {
+					t.privateModes[mode] = true
+				} else {
+					delete(t.privateModes, mode)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package vt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// text returns the characters on the active screen, without trailing blanks.
+func text(term *Terminal) []string {
+	lines := make([]string, 0, term.rows)
+	for _, l := range term.lines() {
+		var b strings.Builder
+		for _, c := range l {
+			switch {
+			case c.r == continuation:
+			case c.r == 0:
+				b.WriteByte(' ')
+			default:
+				b.WriteRune(c.r)
+				b.WriteString(c.comb)
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	return lines
+}
+
+func scrollback(term *Terminal) []string {
+	var lines []string
+	term.scrollback.each(func(s string) {
+		lines = append(lines, s)
+	})
+	return lines
+}
+
+// requireRendered checks that rendering the terminal onto a new terminal of
+// the same size reproduces its state.
+func requireRendered(t *testing.T, term *Terminal) {
+	t.Helper()
+	copied := New(term.cols, term.rows, DefaultScrollback)
+	_, err := copied.Write(term.Render())
+	require.NoError(t, err)
+
+	require.Equal(t, scrollback(term), scrollback(copied), "scrollback")
+	require.Equal(t, term.altActive, copied.altActive, "alternate screen")
+	// The alternate screen is only visible while it is active.
+	screens := 1
+	if term.altActive {
+		screens = 2
+	}
+	for i := 0; i < screens; i++ {
+		for y := range term.screens[i] {
+			require.Equal(t, renderLine(term.screens[i][y]), renderLine(copied.screens[i][y]), "screen %d line %d", i, y)
+		}
+	}
+	require.Equal(t, term.cur, copied.cur, "cursor")
+	require.Equal(t, term.saved[term.screen()], copied.saved[copied.screen()], "saved cursor")
+	require.Equal(t, term.top, copied.top, "scrolling region top")
+	require.Equal(t, term.bottom, copied.bottom, "scrolling region bottom")
+	require.Equal(t, term.autowrap, copied.autowrap, "autowrap")
+	require.Equal(t, term.insert, copied.insert, "insert mode")
+	require.Equal(t, term.cursorHidden, copied.cursorHidden, "cursor hidden")
+	require.Equal(t, term.appKeypad, copied.appKeypad, "application keypad")
+	require.Equal(t, term.privateModes, copied.privateModes, "private modes")
+	require.Equal(t, term.cursorStyle, copied.cursorStyle, "cursor style")
+	require.Equal(t, term.title, copied.title, "title")
+}
+
+func write(t *testing.T, term *Terminal, s string) {
+	t.Helper()
+	n, err := term.Write([]byte(s))
+	require.NoError(t, err)
+	require.Equal(t, len(s), n)
+}
+
+func TestTerminal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Text", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 3, DefaultScrollback)
+		write(t, term, "hello\r\nworld")
+		require.Equal(t, []string{"hello", "world", ""}, text(term))
+		require.Equal(t, cursor{x: 5, y: 1}, term.cur)
+		requireRendered(t, term)
+	})
+
+	t.Run("Scrollback", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 3, 2)
+		write(t, term, "1\r\n2\r\n3\r\n4\r\n5\r\n6")
+		require.Equal(t, []string{"4", "5", "6"}, text(term))
+		// Only the two most recent lines are kept.
+		require.Equal(t, []string{"2", "3"}, scrollback(term))
+		requireRendered(t, term)
+
+		write(t, term, "\x1b[3J")
+		require.Empty(t, scrollback(term))
+	})
+
+	t.Run("Wrap", func(t *testing.T) {
+		t.Parallel()
+		term := New(5, 3, DefaultScrollback)
+		write(t, term, "abcde")
+		require.True(t, term.cur.wrapNext)
+		require.Equal(t, 4, term.cur.x)
+		requireRendered(t, term)
+
+		write(t, term, "f")
+		require.Equal(t, []string{"abcde", "f", ""}, text(term))
+
+		write(t, term, "\x1b[?7l\r\nghijklm")
+		require.Equal(t, []string{"abcde", "f", "ghijm"}, text(term))
+		requireRendered(t, term)
+	})
+
+	t.Run("Attributes", func(t *testing.T) {
+		t.Parallel()
+		term := New(20, 3, DefaultScrollback)
+		write(t, term, "\x1b[1;31mred\x1b[0m \x1b[38;5;200;48;2;1;2;3mx\x1b[38:2::10:20:30;4my\x1b[24;39;49mz\x1b[7m")
+		l := term.lines()[0]
+		require.Equal(t, attrs{fg: colorIndexed | 1, flags: attrBold}, l[0].attrs)
+		require.Equal(t, attrs{}, l[3].attrs)
+		require.Equal(t, attrs{fg: colorIndexed | 200, bg: colorRGB | 0x010203}, l[4].attrs)
+		require.Equal(t, attrs{fg: colorRGB | 0x0a141e, bg: colorRGB | 0x010203, flags: attrUnderline}, l[5].attrs)
+		require.Equal(t, attrs{}, l[6].attrs)
+		require.Equal(t, attrs{flags: attrReverse}, term.cur.attrs)
+		requireRendered(t, term)
+	})
+
+	t.Run("WideAndCombining", func(t *testing.T) {
+		t.Parallel()
+		term := New(6, 2, DefaultScrollback)
+		write(t, term, "日本e\u0301x")
+		require.Equal(t, []string{"日本e\u0301x", ""}, text(term))
+		require.True(t, term.cur.wrapNext)
+		requireRendered(t, term)
+
+		// Overwriting half of a double-width character erases the other half.
+		write(t, term, "\x1b[1;2Hy")
+		require.Equal(t, []string{" y本e\u0301x", ""}, text(term))
+		requireRendered(t, term)
+
+		// A double-width character that does not fit wraps.
+		write(t, term, "\x1b[1;6H語")
+		require.Equal(t, []string{" y本e\u0301x", "語"}, text(term))
+		requireRendered(t, term)
+	})
+
+	t.Run("SplitUTF8", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 2, DefaultScrollback)
+		write(t, term, "a\xe2\x94")
+		write(t, term, "\x80b")
+		require.Equal(t, []string{"a─b", ""}, text(term))
+	})
+
+	t.Run("Erase", func(t *testing.T) {
+		t.Parallel()
+		term := New(5, 3, DefaultScrollback)
+		write(t, term, "abcde\r\nfghij\r\nklmno")
+		write(t, term, "\x1b[2;3H\x1b[K")
+		require.Equal(t, []string{"abcde", "fg", "klmno"}, text(term))
+		write(t, term, "\x1b[1K")
+		require.Equal(t, []string{"abcde", "", "klmno"}, text(term))
+		write(t, term, "\x1b[J")
+		require.Equal(t, []string{"abcde", "", ""}, text(term))
+		write(t, term, "\x1b[1;2H\x1b[2P\x1b[@")
+		require.Equal(t, []string{"a de", "", ""}, text(term))
+		write(t, term, "\x1b[44m\x1b[2J")
+		require.Equal(t, []string{"", "", ""}, text(term))
+		// Erased cells keep the background color.
+		require.Equal(t, attrs{bg: colorIndexed | 4}, term.lines()[2][4].attrs)
+		requireRendered(t, term)
+	})
+
+	t.Run("ScrollRegion", func(t *testing.T) {
+		t.Parallel()
+		term := New(5, 5, DefaultScrollback)
+		write(t, term, "1\r\n2\r\n3\r\n4\r\n5")
+		write(t, term, "\x1b[2;4r")
+		require.Equal(t, cursor{}, term.cur)
+		write(t, term, "\x1b[4;1H\nx")
+		require.Equal(t, []string{"1", "3", "4", "x", "5"}, text(term))
+		// Lines scrolled out of a region are not kept.
+		require.Empty(t, scrollback(term))
+
+		write(t, term, "\x1b[2;1H\x1b[L")
+		require.Equal(t, []string{"1", "", "3", "4", "5"}, text(term))
+		write(t, term, "\x1b[2M")
+		require.Equal(t, []string{"1", "4", "", "", "5"}, text(term))
+
+		write(t, term, "\x1b[?6h\x1b[2;2Hy")
+		require.Equal(t, []string{"1", "4", " y", "", "5"}, text(term))
+		requireRendered(t, term)
+	})
+
+	t.Run("AltScreen", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 3, DefaultScrollback)
+		write(t, term, "$ vim\r\n")
+		write(t, term, "\x1b[?1049h\x1b[?1h\x1b=\x1b[2;3Hediting")
+		require.True(t, term.altActive)
+		require.Equal(t, []string{"", "  editing", ""}, text(term))
+		requireRendered(t, term)
+
+		write(t, term, "\x1b[?1049l\x1b[?1l\x1b>")
+		require.False(t, term.altActive)
+		require.Equal(t, []string{"$ vim", "", ""}, text(term))
+		require.Equal(t, cursor{x: 0, y: 1}, term.cur)
+		requireRendered(t, term)
+	})
+
+	t.Run("SavedCursor", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 3, DefaultScrollback)
+		write(t, term, "\x1b[2;4H\x1b[32m\x1b(0\x1b7\x1b[m\x1b(B\x1b[1;1Hx")
+		requireRendered(t, term)
+		write(t, term, "\x1b8q")
+		require.Equal(t, []string{"x", "   ─", ""}, text(term))
+		require.Equal(t, attrs{fg: colorIndexed | 2}, term.lines()[1][3].attrs)
+	})
+
+	t.Run("Modes", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 3, DefaultScrollback)
+		write(t, term, "\x1b[?2004h\x1b[?1000h\x1b[?1006h\x1b[?25l\x1b[4h\x1b[5 q\x1b]0;my title\x07\x1b]1;icon\x1b\\")
+		require.Equal(t, map[int]bool{1000: true, 1006: true, 2004: true}, term.privateModes)
+		require.True(t, term.cursorHidden)
+		require.True(t, term.insert)
+		require.Equal(t, 5, term.cursorStyle)
+		require.Equal(t, "my title", term.title)
+		requireRendered(t, term)
+
+		write(t, term, "\x1b[?1000l\x1b[!p")
+		require.Equal(t, map[int]bool{1006: true, 2004: true}, term.privateModes)
+		require.False(t, term.cursorHidden)
+		require.False(t, term.insert)
+	})
+
+	t.Run("Tabs", func(t *testing.T) {
+		t.Parallel()
+		term := New(20, 2, DefaultScrollback)
+		write(t, term, "a\tb\x1b[3g\x1b[1;5H\x1bH\r\tc")
+		require.Equal(t, []string{"a   c   b", ""}, text(term))
+	})
+
+	t.Run("IgnoredSequences", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 2, DefaultScrollback)
+		write(t, term, "\x1bP+q544e\x1b\\\x1b[>c\x1b[6n\x1b[?u\x1b[>4;1ma\x1b[1\x18b")
+		require.Equal(t, []string{"ab", ""}, text(term))
+		requireRendered(t, term)
+	})
+
+	t.Run("Resize", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 4, DefaultScrollback)
+		write(t, term, "1\r\n2\r\n3 long li")
+		// Blank lines below the cursor are dropped first, then lines move into
+		// the scrollback.
+		term.Resize(5, 2)
+		require.Equal(t, []string{"2", "3 lon"}, text(term))
+		require.Equal(t, []string{"1"}, scrollback(term))
+		require.Equal(t, cursor{x: 4, y: 1}, term.cur)
+		requireRendered(t, term)
+
+		term.Resize(8, 3)
+		require.Equal(t, []string{"2", "3 lon", ""}, text(term))
+		require.Equal(t, cursor{x: 4, y: 1}, term.cur)
+		requireRendered(t, term)
+	})
+
+	t.Run("ResizeAltScreen", func(t *testing.T) {
+		t.Parallel()
+		term := New(10, 4, DefaultScrollback)
+		write(t, term, "1\r\n2\r\n3\r\n4\x1b[?1049h\x1b[Hvim")
+		term.Resize(10, 2)
+		require.Equal(t, []string{"vim", ""}, text(term))
+		requireRendered(t, term)
+
+		write(t, term, "\x1b[?1049l")
+		require.Equal(t, []string{"3", "4"}, text(term))
+		require.Equal(t, []string{"1", "2"}, scrollback(term))
+		require.Equal(t, cursor{x: 1, y: 1}, term.cur)
+	})
+}
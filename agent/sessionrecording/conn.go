@@ -0,0 +1,67 @@
+package sessionrecording
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// reconnectingPTYConn records a reconnecting PTY connection. Writes to the
+// connection are the terminal output, and reads are the client's requests,
+// which are decoded to record resizes.
+type reconnectingPTYConn struct {
+	net.Conn
+	recorder *Recorder
+	requests *io.PipeWriter
+}
+
+// ReconnectingPTYConn wraps a reconnecting PTY connection so that the session
+// is recorded to recorder. The returned connection must be closed.
+func ReconnectingPTYConn(conn net.Conn, recorder *Recorder) net.Conn {
+	pr, pw := io.Pipe()
+	go func() {
+		decoder := json.NewDecoder(pr)
+		for {
+			var req codersdk.ReconnectingPTYRequest
+			err := decoder.Decode(&req)
+			if err != nil {
+				// Unblock any further reads from the connection.
+				_ = pr.CloseWithError(err)
+				return
+			}
+			if req.Height != 0 && req.Width != 0 {
+				recorder.Resize(req.Width, req.Height)
+			}
+		}
+	}()
+	return &reconnectingPTYConn{
+		Conn:     conn,
+		recorder: recorder,
+		requests: pw,
+	}
+}
+
+func (c *reconnectingPTYConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		// Errors only mean the requests could not be decoded, in which case
+		// resizes are not recorded.
+		_, _ = c.requests.Write(p[:n])
+	}
+	return n, err
+}
+
+func (c *reconnectingPTYConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		_, _ = c.recorder.Write(p[:n])
+	}
+	return n, err
+}
+
+func (c *reconnectingPTYConn) Close() error {
+	_ = c.requests.Close()
+	return c.Conn.Close()
+}
@@ -0,0 +1,142 @@
+// Package sessionrecording records terminal sessions in asciinema's asciicast
+// v2 format: a JSON header line followed by one JSON array per event.
+//
+// See https://docs.asciinema.org/manual/asciicast/v2/
+package sessionrecording
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// Header is the first line of an asciicast v2 recording.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event types recorded by the Recorder.
+const (
+	EventOutput = "o"
+	EventResize = "r"
+	EventMarker = "m"
+)
+
+// Recorder records the output of a terminal session. It is safe for concurrent
+// use.
+type Recorder struct {
+	typ     codersdk.WorkspaceSessionRecordingType
+	started time.Time
+	limit   int
+
+	mu sync.Mutex // Protects following.
+	// pending holds an incomplete UTF-8 sequence at the end of the last write,
+	// so that characters split across writes are not replaced.
+	pending   []byte
+	buf       bytes.Buffer
+	truncated bool
+}
+
+// New starts recording a session of the given type. The command is the one
+// the session runs, or empty for a login shell.
+func New(typ codersdk.WorkspaceSessionRecordingType, width, height uint16, command, term string) *Recorder {
+	r := &Recorder{
+		typ:     typ,
+		started: time.Now(),
+		limit:   agentsdk.MaxSessionRecordingSize,
+	}
+	header := Header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: r.started.Unix(),
+		Command:   command,
+	}
+	if term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+	// The header is always small enough to fit.
+	data, _ := json.Marshal(header)
+	r.buf.Write(data)
+	r.buf.WriteByte('\n')
+	return r
+}
+
+// Write records p as terminal output. It never fails, so that it can be used
+// alongside the writer the output is sent to with io.MultiWriter.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := append(r.pending, p...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	r.pending = append([]byte(nil), data[cut:]...)
+	if cut > 0 {
+		r.event(EventOutput, string(data[:cut]))
+	}
+	return len(p), nil
+}
+
+// Resize records that the terminal was resized.
+func (r *Recorder) Resize(width, height uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.event(EventResize, fmt.Sprintf("%dx%d", width, height))
+}
+
+// Request ends the recording and returns it to be uploaded to coderd.
+func (r *Recorder) Request() agentsdk.PostSessionRecordingRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) > 0 {
+		r.event(EventOutput, string(r.pending))
+		r.pending = nil
+	}
+	return agentsdk.PostSessionRecordingRequest{
+		Type:      r.typ,
+		StartedAt: r.started,
+		EndedAt:   time.Now(),
+		Data:      bytes.Clone(r.buf.Bytes()),
+	}
+}
+
+// event appends an event to the recording. Once the recording reaches its
+// size limit, a marker is added and further events are dropped. The caller
+// must hold r.mu.
+func (r *Recorder) event(typ string, data string) {
+	if r.truncated {
+		return
+	}
+	elapsed := math.Round(time.Since(r.started).Seconds()*1e6) / 1e6
+	line, err := json.Marshal([]interface{}{elapsed, typ, data})
+	if err != nil {
+		return
+	}
+	// Leave room for the marker.
+	if r.buf.Len()+len(line)+1 > r.limit-128 {
+		r.truncated = true
+		line, _ = json.Marshal([]interface{}{elapsed, EventMarker, "recording truncated"})
+	}
+	r.buf.Write(line)
+	r.buf.WriteByte('\n')
+}
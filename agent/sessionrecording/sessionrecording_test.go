@@ -0,0 +1,109 @@
+package sessionrecording_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/sessionrecording"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Events", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := sessionrecording.New(codersdk.WorkspaceSessionRecordingTypeSSH, 80, 24, "vim", "xterm")
+		_, err := recorder.Write([]byte("hello "))
+		require.NoError(t, err)
+		recorder.Resize(100, 40)
+		// Split a multi-byte character across writes.
+		n, err := recorder.Write([]byte("w\xc3"))
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		_, err = recorder.Write([]byte("\xb6rld"))
+		require.NoError(t, err)
+
+		req := recorder.Request()
+		require.Equal(t, codersdk.WorkspaceSessionRecordingTypeSSH, req.Type)
+		require.False(t, req.EndedAt.Before(req.StartedAt))
+
+		header, events := parseCast(t, req.Data)
+		require.Equal(t, 2, header.Version)
+		require.EqualValues(t, 80, header.Width)
+		require.EqualValues(t, 24, header.Height)
+		require.Equal(t, "vim", header.Command)
+		require.Equal(t, map[string]string{"TERM": "xterm"}, header.Env)
+		require.Equal(t, [][2]string{
+			{sessionrecording.EventOutput, "hello "},
+			{sessionrecording.EventResize, "100x40"},
+			{sessionrecording.EventOutput, "w"},
+			{sessionrecording.EventOutput, "örld"},
+		}, events)
+	})
+
+	t.Run("FlushPending", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := sessionrecording.New(codersdk.WorkspaceSessionRecordingTypeReconnectingPTY, 80, 24, "", "")
+		_, err := recorder.Write([]byte("a\xe2\x82"))
+		require.NoError(t, err)
+
+		_, events := parseCast(t, recorder.Request().Data)
+		require.Len(t, events, 2)
+		require.Equal(t, "a", events[0][1])
+		// The incomplete character is recorded when the session ends.
+		require.NotEmpty(t, events[1][1])
+		require.True(t, utf8.ValidString(events[1][1]))
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := sessionrecording.New(codersdk.WorkspaceSessionRecordingTypeSSH, 80, 24, "", "")
+		chunk := []byte(strings.Repeat("x", 1<<20))
+		for i := 0; i < 20; i++ {
+			_, err := recorder.Write(chunk)
+			require.NoError(t, err)
+		}
+
+		req := recorder.Request()
+		require.LessOrEqual(t, len(req.Data), agentsdk.MaxSessionRecordingSize)
+		_, events := parseCast(t, req.Data)
+		require.Equal(t, [2]string{sessionrecording.EventMarker, "recording truncated"}, events[len(events)-1])
+	})
+}
+
+// parseCast decodes an asciicast v2 recording into its header and the type
+// and data of each event.
+func parseCast(t *testing.T, data []byte) (sessionrecording.Header, [][2]string) {
+	t.Helper()
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, agentsdk.MaxSessionRecordingSize)
+	require.True(t, scanner.Scan())
+	var header sessionrecording.Header
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+
+	var events [][2]string
+	var last float64
+	for scanner.Scan() {
+		var event [3]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		elapsed, ok := event[0].(float64)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, elapsed, last)
+		last = elapsed
+		events = append(events, [2]string{event[1].(string), event[2].(string)})
+	}
+	require.NoError(t, scanner.Err())
+	return header, events
+}
@@ -49,6 +49,7 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 		slogHumanPath       string
 		slogJSONPath        string
 		slogStackdriverPath string
+		rptyBackend         string
 	)
 	cmd := &clibase.Cmd{
 		Use:   "agent",
@@ -300,9 +301,10 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 					"GIT_ASKPASS":         executablePath,
 					agent.EnvProcPrioMgmt: os.Getenv(agent.EnvProcPrioMgmt),
 				},
-				IgnorePorts:   ignorePorts,
-				SSHMaxTimeout: sshMaxTimeout,
-				Subsystems:    subsystems,
+				IgnorePorts:            ignorePorts,
+				SSHMaxTimeout:          sshMaxTimeout,
+				Subsystems:             subsystems,
+				ReconnectingPTYBackend: rptyBackend,
 
 				PrometheusRegistry: prometheusRegistry,
 				Syscaller:          agentproc.NewSyscaller(),
@@ -359,6 +361,12 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Description: "Specify the max timeout for a SSH connection, it is advisable to set it to a minimum of 60s, but no more than 72h.",
 			Value:       clibase.DurationOf(&sshMaxTimeout),
 		},
+		{
+			Flag:        "reconnecting-pty-backend",
+			Env:         "CODER_AGENT_RECONNECTING_PTY_BACKEND",
+			Description: "The backend of reconnecting ptys, used by the web terminal. The terminal backend emulates a terminal to replay the screen on reconnect. If not set, screen is used if it is installed, and the buffered backend otherwise.",
+			Value:       clibase.EnumOf(&rptyBackend, "screen", "terminal", "buffered"),
+		},
 		{
 			Flag:        "tailnet-listen-port",
 			Default:     "0",
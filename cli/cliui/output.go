@@ -70,6 +70,11 @@ func (f *OutputFormatter) AttachOptions(opts *clibase.OptionSet) {
 	)
 }
 
+// FormatID returns the ID of the format specified by the --output flag.
+func (f *OutputFormatter) FormatID() string {
+	return f.formatID
+}
+
 // Format formats the given data using the format specified by the --output
 // flag. If the flag is not set, the default format is used.
 func (f *OutputFormatter) Format(ctx context.Context, data any) (string, error) {
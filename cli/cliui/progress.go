@@ -0,0 +1,97 @@
+package cliui
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/coder/pretty"
+)
+
+// ProgressOptions configures a Progress.
+type ProgressOptions struct {
+	// Name is displayed before the progress, e.g. the file being copied.
+	Name string
+	// Total is the number of bytes expected. If zero, only the number of bytes
+	// transferred is displayed.
+	Total int64
+	// Interactive redraws the progress in place as it advances. Otherwise, only
+	// the final result is written.
+	Interactive bool
+}
+
+// Progress is an io.Writer that displays how many bytes have been written to
+// it. Close must be called once the transfer is finished.
+type Progress struct {
+	wtr  io.Writer
+	opts ProgressOptions
+
+	mu      sync.Mutex
+	written int64
+	started time.Time
+	drawn   time.Time
+}
+
+// NewProgress returns a Progress that displays to wtr.
+func NewProgress(wtr io.Writer, opts ProgressOptions) *Progress {
+	return &Progress{
+		wtr:     wtr,
+		opts:    opts,
+		started: time.Now(),
+	}
+}
+
+// Add counts n bytes as transferred without writing them, e.g. when resuming
+// a transfer that was already partially complete.
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.written += n
+}
+
+func (p *Progress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.written += int64(len(b))
+	if p.opts.Interactive && time.Since(p.drawn) > 100*time.Millisecond {
+		p.drawn = time.Now()
+		_, _ = fmt.Fprintf(p.wtr, "\r\033[K%s", p.line())
+	}
+	return len(b), nil
+}
+
+// Close writes the final progress.
+func (p *Progress) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prefix := ""
+	if p.opts.Interactive {
+		prefix = "\r\033[K"
+	}
+	_, err := fmt.Fprintf(p.wtr, "%s%s %s\n", prefix, p.line(),
+		pretty.Sprint(DefaultStyles.Placeholder, fmt.Sprintf("in %s", time.Since(p.started).Round(time.Millisecond))))
+	return err
+}
+
+func (p *Progress) line() string {
+	if p.opts.Total <= 0 {
+		return fmt.Sprintf("%s  %s", p.opts.Name, FormatBytes(p.written))
+	}
+	return fmt.Sprintf("%s  %s / %s (%d%%)", p.opts.Name,
+		FormatBytes(p.written), FormatBytes(p.opts.Total), p.written*100/p.opts.Total)
+}
+
+// FormatBytes formats a number of bytes in binary units, e.g. "1.5 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
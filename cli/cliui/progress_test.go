@@ -0,0 +1,33 @@
+package cliui_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/cliui"
+)
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	p := cliui.NewProgress(&buf, cliui.ProgressOptions{Name: "file.txt", Total: 4096})
+	p.Add(1024)
+	_, err := p.Write(make([]byte, 1024))
+	require.NoError(t, err)
+	require.Empty(t, buf.String(), "non-interactive progress is only written on close")
+	require.NoError(t, p.Close())
+	require.Contains(t, buf.String(), "file.txt  2.0 KiB / 4.0 KiB (50%)")
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "0 B", cliui.FormatBytes(0))
+	require.Equal(t, "1023 B", cliui.FormatBytes(1023))
+	require.Equal(t, "1.0 KiB", cliui.FormatBytes(1024))
+	require.Equal(t, "1.5 MiB", cliui.FormatBytes(1536*1024))
+	require.Equal(t, "2.0 GiB", cliui.FormatBytes(2<<30))
+}
@@ -69,6 +69,9 @@ func ProvisionerJob(ctx context.Context, wr io.Writer, opts ProvisionerJobOption
 		errChan  = make(chan error, 1)
 		job      codersdk.ProvisionerJob
 		jobMutex sync.Mutex
+		// queuePosition is the last queue position shown, so that it is only
+		// shown again when it changes.
+		queuePosition int
 	)
 
 	sw := &stageWriter{w: wr, verbose: opts.Verbose, silentLogs: opts.Silent}
@@ -104,6 +107,10 @@ func ProvisionerJob(ctx context.Context, wr io.Writer, opts ProvisionerJobOption
 			return
 		}
 		if job.StartedAt == nil {
+			if job.Status == codersdk.ProvisionerJobPending && job.QueuePosition > 0 && job.QueuePosition != queuePosition {
+				queuePosition = job.QueuePosition
+				sw.QueuePosition(job.QueuePosition, job.QueueSize)
+			}
 			return
 		}
 		if currentStage != "Queued" {
@@ -212,6 +219,13 @@ func (s *stageWriter) Start(stage string) {
 	_, _ = fmt.Fprintf(s.w, "==> ⧗ %s\n", stage)
 }
 
+func (s *stageWriter) QueuePosition(position, size int) {
+	if size < position {
+		size = position
+	}
+	pretty.Fprintf(s.w, DefaultStyles.Placeholder, "Position %d of %d in queue\n", position, size)
+}
+
 func (s *stageWriter) Complete(stage string, duration time.Duration) {
 	s.end(stage, duration, true)
 }
@@ -0,0 +1,522 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// cpWatchInterval is how often the source is checked for changes in watch
+// mode.
+var cpWatchInterval = time.Second
+
+func (r *RootCmd) cp() *clibase.Cmd {
+	var (
+		resume           bool
+		watch            bool
+		disableAutostart bool
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "cp <source> <destination>",
+		Short:       "Copy files and directories to or from a workspace",
+		Long: "Paths in a workspace are written as <workspace>[.<agent>]:<path>, and are relative to " +
+			"the home directory unless they are absolute. Directories are copied recursively. As with " +
+			"rsync, a source directory ending in a slash copies its contents rather than the directory itself.\n\n" +
+			formatExamples(
+				example{
+					Description: "Copy a file into a workspace",
+					Command:     "coder cp ./notes.txt my-workspace:~/notes.txt",
+				},
+				example{
+					Description: "Copy a directory out of a workspace, picking up where a previous copy left off",
+					Command:     "coder cp --resume my-workspace:~/project/build ./build",
+				},
+				example{
+					Description: "Keep the contents of a local directory synced into a workspace",
+					Command:     "coder cp --watch ./src/ my-workspace.main:~/project/src",
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, stop := inv.SignalNotifyContext(inv.Context(), InterruptSignals...)
+			defer stop()
+
+			src, dst := parseCpTarget(inv.Args[0]), parseCpTarget(inv.Args[1])
+			switch {
+			case src.workspace != "" && dst.workspace != "":
+				return xerrors.New("copying between two workspaces is not supported")
+			case src.workspace == "" && dst.workspace == "":
+				return xerrors.New("the source or destination must be in a workspace, e.g. my-workspace:~/path")
+			case watch && src.workspace != "":
+				return xerrors.New("--watch can only sync local files into a workspace")
+			}
+			remote := src
+			if dst.workspace != "" {
+				remote = dst
+			}
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, !disableAutostart, codersdk.Me, remote.workspace)
+			if err != nil {
+				return err
+			}
+			if r.disableDirect {
+				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+			}
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				Logger:         inv.Logger,
+				BlockEndpoints: r.disableDirect,
+			})
+			if err != nil {
+				return xerrors.Errorf("dial agent: %w", err)
+			}
+			defer conn.Close()
+			if !conn.AwaitReachable(ctx) {
+				return xerrors.Errorf("await agent reachable: %w", ctx.Err())
+			}
+			sshClient, err := conn.SSHClient(ctx)
+			if err != nil {
+				return xerrors.Errorf("ssh client: %w", err)
+			}
+			defer sshClient.Close()
+			sftpClient, err := sftp.NewClient(sshClient)
+			if err != nil {
+				return xerrors.Errorf("sftp client: %w", err)
+			}
+			defer sftpClient.Close()
+
+			c := &cpCopier{
+				src:         cpLocalFS{},
+				dst:         cpRemoteFS{client: sftpClient},
+				resume:      resume,
+				stderr:      inv.Stderr,
+				interactive: isTTYErr(inv),
+			}
+			if src.workspace != "" {
+				c.src, c.dst = c.dst, c.src
+			}
+
+			srcPath, dstPath := c.src.Clean(src.path), c.dst.Clean(dst.path)
+			srcInfo, err := c.src.Stat(srcPath)
+			if err != nil {
+				return xerrors.Errorf("stat %s: %w", inv.Args[0], err)
+			}
+			// Like cp and scp, copy into the destination if it's an existing
+			// directory, unless the source directory ends in a slash.
+			contentsOnly := srcInfo.IsDir() && (strings.HasSuffix(src.path, "/") || strings.HasSuffix(src.path, string(filepath.Separator)))
+			if dstInfo, err := c.dst.Stat(dstPath); err == nil && dstInfo.IsDir() && !contentsOnly {
+				dstPath = c.dst.Join(dstPath, c.src.Base(srcPath))
+			}
+
+			// Take the first snapshot before copying, so changes made while
+			// copying are picked up by the watcher.
+			var snapshot map[string]cpFileState
+			if watch {
+				snapshot, err = cpSnapshot(srcPath)
+				if err != nil {
+					return xerrors.Errorf("check %s for changes: %w", srcPath, err)
+				}
+			}
+			err = c.copy(ctx, srcPath, srcInfo, dstPath)
+			if err != nil {
+				return err
+			}
+			if !watch {
+				return nil
+			}
+
+			cliui.Infof(inv.Stderr, "Watching %s for changes, press Ctrl+C to stop...", srcPath)
+			return c.watch(ctx, srcPath, dstPath, snapshot)
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "resume",
+			Description: "Skip files that have already been copied, and continue files that were partially copied instead of starting them over.",
+			Value:       clibase.BoolOf(&resume),
+		},
+		{
+			Flag:        "watch",
+			Description: "After copying, keep watching the source for changes and copy them into the workspace. Files deleted from the source are deleted from the workspace.",
+			Value:       clibase.BoolOf(&watch),
+		},
+		sshDisableAutostartOption(clibase.BoolOf(&disableAutostart)),
+	}
+	return cmd
+}
+
+// cpTarget is the source or destination of a copy.
+type cpTarget struct {
+	// workspace is empty for local paths.
+	workspace string
+	path      string
+}
+
+// parseCpTarget parses a local path, or a path in a workspace in the form
+// <workspace>[.<agent>]:<path>.
+func parseCpTarget(s string) cpTarget {
+	// Windows paths such as C:\Users have a colon, but are local.
+	if filepath.VolumeName(s) != "" {
+		return cpTarget{path: s}
+	}
+	workspace, p, ok := strings.Cut(s, ":")
+	if !ok || workspace == "" || strings.ContainsAny(workspace, `/\`) {
+		return cpTarget{path: s}
+	}
+	if p == "" {
+		p = "."
+	}
+	return cpTarget{workspace: workspace, path: p}
+}
+
+// cpFS is the local or remote side of a copy.
+type cpFS interface {
+	Stat(name string) (fs.FileInfo, error)
+	// ReadDir returns the entries of a directory without following symlinks.
+	ReadDir(name string) ([]fs.FileInfo, error)
+	Open(name string) (io.ReadSeekCloser, error)
+	OpenFile(name string, flag int) (cpWriteFile, error)
+	MkdirAll(name string) error
+	Remove(name string) error
+	Chmod(name string, mode fs.FileMode) error
+	Chtimes(name string, mtime time.Time) error
+
+	Clean(name string) string
+	Join(elem ...string) string
+	Base(name string) string
+}
+
+type cpWriteFile interface {
+	io.WriteSeeker
+	io.Closer
+}
+
+type cpLocalFS struct{}
+
+func (cpLocalFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+func (cpLocalFS) ReadDir(name string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (cpLocalFS) Open(name string) (io.ReadSeekCloser, error) { return os.Open(name) }
+
+func (cpLocalFS) OpenFile(name string, flag int) (cpWriteFile, error) {
+	return os.OpenFile(name, flag, 0o600)
+}
+
+func (cpLocalFS) MkdirAll(name string) error                 { return os.MkdirAll(name, 0o755) }
+func (cpLocalFS) Remove(name string) error                   { return os.Remove(name) }
+func (cpLocalFS) Chmod(name string, mode fs.FileMode) error  { return os.Chmod(name, mode) }
+func (cpLocalFS) Chtimes(name string, mtime time.Time) error { return os.Chtimes(name, mtime, mtime) }
+func (cpLocalFS) Clean(name string) string                   { return filepath.Clean(name) }
+func (cpLocalFS) Join(elem ...string) string                 { return filepath.Join(elem...) }
+func (cpLocalFS) Base(name string) string                    { return filepath.Base(name) }
+
+// cpRemoteFS is a workspace, accessed over the agent's SFTP server. Relative
+// paths are relative to the home directory, which is the server's working
+// directory.
+type cpRemoteFS struct {
+	client *sftp.Client
+}
+
+func (f cpRemoteFS) Stat(name string) (fs.FileInfo, error)      { return f.client.Stat(name) }
+func (f cpRemoteFS) ReadDir(name string) ([]fs.FileInfo, error) { return f.client.ReadDir(name) }
+func (f cpRemoteFS) Open(name string) (io.ReadSeekCloser, error) {
+	return f.client.Open(name)
+}
+
+func (f cpRemoteFS) OpenFile(name string, flag int) (cpWriteFile, error) {
+	return f.client.OpenFile(name, flag)
+}
+
+func (f cpRemoteFS) MkdirAll(name string) error                { return f.client.MkdirAll(name) }
+func (f cpRemoteFS) Remove(name string) error                  { return f.client.Remove(name) }
+func (f cpRemoteFS) Chmod(name string, mode fs.FileMode) error { return f.client.Chmod(name, mode) }
+func (f cpRemoteFS) Chtimes(name string, mtime time.Time) error {
+	return f.client.Chtimes(name, mtime, mtime)
+}
+
+func (cpRemoteFS) Clean(name string) string {
+	// The SFTP server doesn't expand the tilde, but relative paths are already
+	// relative to the home directory.
+	if name == "~" {
+		return "."
+	}
+	name = strings.TrimPrefix(name, "~/")
+	return path.Clean(name)
+}
+func (cpRemoteFS) Join(elem ...string) string { return path.Join(elem...) }
+func (cpRemoteFS) Base(name string) string    { return path.Base(name) }
+
+// cpCopier copies files from one side to the other.
+type cpCopier struct {
+	src, dst cpFS
+	// resume skips files that are up to date and appends to files that are
+	// shorter than the source. A partially copied file is assumed to be the
+	// beginning of the source.
+	resume      bool
+	stderr      io.Writer
+	interactive bool
+}
+
+// copy copies a file or directory, recursively.
+func (c *cpCopier) copy(ctx context.Context, srcPath string, srcInfo fs.FileInfo, dstPath string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	switch {
+	case srcInfo.IsDir():
+		err := c.dst.MkdirAll(dstPath)
+		if err != nil {
+			return xerrors.Errorf("create directory %s: %w", dstPath, err)
+		}
+		entries, err := c.src.ReadDir(srcPath)
+		if err != nil {
+			return xerrors.Errorf("read directory %s: %w", srcPath, err)
+		}
+		for _, entry := range entries {
+			err = c.copy(ctx, c.src.Join(srcPath, entry.Name()), entry, c.dst.Join(dstPath, entry.Name()))
+			if err != nil {
+				return err
+			}
+		}
+		// Set the mode last, in case the directory isn't writable.
+		_ = c.dst.Chmod(dstPath, srcInfo.Mode().Perm())
+		return nil
+	case srcInfo.Mode().IsRegular():
+		return c.copyFile(ctx, srcPath, srcInfo, dstPath)
+	default:
+		cliui.Warnf(c.stderr, "Skipping %s, only regular files and directories are copied.", srcPath)
+		return nil
+	}
+}
+
+func (c *cpCopier) copyFile(ctx context.Context, srcPath string, srcInfo fs.FileInfo, dstPath string) error {
+	var (
+		offset int64
+		flag   = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	)
+	if c.resume {
+		if dstInfo, err := c.dst.Stat(dstPath); err == nil && dstInfo.Mode().IsRegular() {
+			switch {
+			// Modification times are compared to the second, since that's
+			// all SFTP preserves.
+			case dstInfo.Size() == srcInfo.Size() && dstInfo.ModTime().Unix() == srcInfo.ModTime().Unix():
+				return nil
+			case dstInfo.Size() < srcInfo.Size():
+				// O_APPEND isn't supported by every SFTP server, so seek
+				// to the end instead.
+				offset = dstInfo.Size()
+				flag = os.O_WRONLY
+			}
+		}
+	}
+
+	r, err := c.src.Open(srcPath)
+	if err != nil {
+		return xerrors.Errorf("open %s: %w", srcPath, err)
+	}
+	defer r.Close()
+	if offset > 0 {
+		_, err = r.Seek(offset, io.SeekStart)
+		if err != nil {
+			return xerrors.Errorf("seek %s: %w", srcPath, err)
+		}
+	}
+	w, err := c.dst.OpenFile(dstPath, flag)
+	if err != nil {
+		return xerrors.Errorf("open %s: %w", dstPath, err)
+	}
+	defer w.Close()
+	if offset > 0 {
+		_, err = w.Seek(offset, io.SeekStart)
+		if err != nil {
+			return xerrors.Errorf("seek %s: %w", dstPath, err)
+		}
+	}
+
+	progress := cliui.NewProgress(c.stderr, cliui.ProgressOptions{
+		Name:        srcPath,
+		Total:       srcInfo.Size(),
+		Interactive: c.interactive,
+	})
+	progress.Add(offset)
+	_, err = io.Copy(w, io.TeeReader(&cpContextReader{ctx: ctx, r: r}, progress))
+	if err != nil {
+		return xerrors.Errorf("copy %s: %w", srcPath, err)
+	}
+	err = w.Close()
+	if err != nil {
+		return xerrors.Errorf("close %s: %w", dstPath, err)
+	}
+	_ = progress.Close()
+
+	err = c.dst.Chmod(dstPath, srcInfo.Mode().Perm())
+	if err != nil {
+		return xerrors.Errorf("chmod %s: %w", dstPath, err)
+	}
+	// The modification time is what --resume uses to tell the file has been
+	// copied completely.
+	err = c.dst.Chtimes(dstPath, srcInfo.ModTime())
+	if err != nil {
+		return xerrors.Errorf("chtimes %s: %w", dstPath, err)
+	}
+	return nil
+}
+
+// cpFileState is what's compared to detect changes in watch mode.
+type cpFileState struct {
+	dir     bool
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+}
+
+// watch polls the local source for changes and copies them until the context
+// is canceled. Only files and directories that have changed since the previous
+// snapshot are copied.
+func (c *cpCopier) watch(ctx context.Context, srcPath, dstPath string, prev map[string]cpFileState) error {
+	ticker := time.NewTicker(cpWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next, err := cpSnapshot(srcPath)
+		if err != nil {
+			// The source may be in the middle of being replaced, e.g. by an
+			// editor, so try again on the next tick.
+			cliui.Warnf(c.stderr, "Failed to check %s for changes: %s", srcPath, err)
+			continue
+		}
+
+		// Sorting means directories are created before their contents, and
+		// removed after them.
+		changed := make([]string, 0)
+		for rel, state := range next {
+			if prevState, ok := prev[rel]; !ok || prevState != state {
+				changed = append(changed, rel)
+			}
+		}
+		sort.Strings(changed)
+		for _, rel := range changed {
+			state := next[rel]
+			src, dst := filepath.Join(srcPath, filepath.FromSlash(rel)), c.dst.Join(dstPath, rel)
+			if state.dir {
+				err = c.dst.MkdirAll(dst)
+			} else {
+				var info fs.FileInfo
+				info, err = os.Stat(src)
+				if err == nil {
+					err = c.copyFile(ctx, src, info, dst)
+				}
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				cliui.Warnf(c.stderr, "Failed to copy %s: %s", src, err)
+				// Try again on the next tick.
+				delete(next, rel)
+			}
+		}
+
+		removed := make([]string, 0)
+		for rel := range prev {
+			if _, ok := next[rel]; !ok {
+				removed = append(removed, rel)
+			}
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(removed)))
+		for _, rel := range removed {
+			dst := c.dst.Join(dstPath, rel)
+			err = c.dst.Remove(dst)
+			if err != nil && !xerrors.Is(err, fs.ErrNotExist) {
+				cliui.Warnf(c.stderr, "Failed to remove %s: %s", dst, err)
+				continue
+			}
+			_, _ = fmt.Fprintf(c.stderr, "Removed %s\n", dst)
+		}
+
+		prev = next
+	}
+}
+
+// cpSnapshot returns the state of every file and directory under root, keyed
+// by their slash-separated path relative to root.
+func cpSnapshot(root string) (map[string]cpFileState, error) {
+	snapshot := map[string]cpFileState{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		state := cpFileState{dir: true}
+		if !d.IsDir() {
+			// A directory's modification time changes with its contents,
+			// which are compared separately.
+			state = cpFileState{size: info.Size(), modTime: info.ModTime(), mode: info.Mode()}
+		}
+		snapshot[filepath.ToSlash(rel)] = state
+		return nil
+	})
+	return snapshot, err
+}
+
+// cpContextReader stops reading once the context is canceled.
+type cpContextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *cpContextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
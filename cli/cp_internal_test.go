@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCpTarget(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		in   string
+		want cpTarget
+	}{
+		{in: "file.txt", want: cpTarget{path: "file.txt"}},
+		{in: "./dir/file:1.txt", want: cpTarget{path: "./dir/file:1.txt"}},
+		{in: "ws:~/file.txt", want: cpTarget{workspace: "ws", path: "~/file.txt"}},
+		{in: "ws.agent:/tmp", want: cpTarget{workspace: "ws.agent", path: "/tmp"}},
+		{in: "ws:", want: cpTarget{workspace: "ws", path: "."}},
+		{in: ":file", want: cpTarget{path: ":file"}},
+	}
+	if runtime.GOOS == "windows" {
+		cases = append(cases, struct {
+			in   string
+			want cpTarget
+		}{in: `C:\Users\coder`, want: cpTarget{path: `C:\Users\coder`}})
+	}
+	for _, c := range cases {
+		require.Equal(t, c.want, parseCpTarget(c.in), c.in)
+	}
+}
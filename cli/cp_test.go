@@ -0,0 +1,143 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCp(t *testing.T) {
+	t.Parallel()
+
+	// The agent runs in-process, so absolute paths in the "workspace" are on
+	// the local filesystem.
+	writeFile := func(t *testing.T, name, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+	}
+	readFile := func(t *testing.T, name string) string {
+		t.Helper()
+		b, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	t.Run("UploadAndDownload", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+		coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		local := t.TempDir()
+		writeFile(t, filepath.Join(local, "src", "a.txt"), "hello")
+		writeFile(t, filepath.Join(local, "src", "nested", "b.txt"), "world")
+		remote := t.TempDir()
+
+		// The source directory is created inside the existing destination.
+		inv, root := clitest.New(t, "cp", filepath.Join(local, "src"), workspace.Name+":"+remote)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.Run())
+		require.Equal(t, "hello", readFile(t, filepath.Join(remote, "src", "a.txt")))
+		require.Equal(t, "world", readFile(t, filepath.Join(remote, "src", "nested", "b.txt")))
+
+		inv, root = clitest.New(t, "cp", workspace.Name+":"+filepath.Join(remote, "src", "nested", "b.txt"), filepath.Join(local, "b.txt"))
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.Run())
+		require.Equal(t, "world", readFile(t, filepath.Join(local, "b.txt")))
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+		coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		local := t.TempDir()
+		writeFile(t, filepath.Join(local, "partial.txt"), "hello world")
+		writeFile(t, filepath.Join(local, "done.txt"), "done")
+		remote := t.TempDir()
+		writeFile(t, filepath.Join(remote, "partial.txt"), "hello")
+
+		inv, root := clitest.New(t, "cp", "--resume", local+"/", workspace.Name+":"+remote)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.Run())
+		require.Equal(t, "hello world", readFile(t, filepath.Join(remote, "partial.txt")))
+		require.Equal(t, "done", readFile(t, filepath.Join(remote, "done.txt")))
+
+		// Files that are up to date are skipped.
+		inv, root = clitest.New(t, "cp", "--resume", local+"/", workspace.Name+":"+remote)
+		clitest.SetupConfig(t, client, root)
+		errBuf := new(bytes.Buffer)
+		inv.Stderr = errBuf
+		require.NoError(t, inv.Run())
+		require.NotContains(t, errBuf.String(), "done.txt")
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+		coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		local := t.TempDir()
+		writeFile(t, filepath.Join(local, "a.txt"), "one")
+		remote := filepath.Join(t.TempDir(), "synced")
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		inv, root := clitest.New(t, "cp", "--watch", local+"/", workspace.Name+":"+remote)
+		clitest.SetupConfig(t, client, root)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.NoError(t, inv.WithContext(ctx).Run())
+		}()
+
+		require.Eventually(t, func() bool {
+			b, err := os.ReadFile(filepath.Join(remote, "a.txt"))
+			return err == nil && string(b) == "one"
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		// Make sure the modification time changes, even on filesystems with
+		// coarse timestamps.
+		writeFile(t, filepath.Join(local, "a.txt"), "two")
+		require.NoError(t, os.Chtimes(filepath.Join(local, "a.txt"), time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+		writeFile(t, filepath.Join(local, "dir", "b.txt"), "new")
+		require.Eventually(t, func() bool {
+			a, errA := os.ReadFile(filepath.Join(remote, "a.txt"))
+			b, errB := os.ReadFile(filepath.Join(remote, "dir", "b.txt"))
+			return errA == nil && errB == nil && string(a) == "two" && string(b) == "new"
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		require.NoError(t, os.RemoveAll(filepath.Join(local, "dir")))
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(filepath.Join(remote, "dir"))
+			return os.IsNotExist(err)
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		cancel()
+		<-done
+	})
+
+	t.Run("NoWorkspace", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "cp", "a", "b")
+		clitest.SetupConfig(t, client, root)
+		require.ErrorContains(t, inv.Run(), "must be in a workspace")
+	})
+}
@@ -279,6 +279,12 @@ type prepWorkspaceBuildArgs struct {
 	PromptRichParameters bool
 	RichParameters       []codersdk.WorkspaceBuildParameter
 	RichParameterFile    string
+
+	// PreviewChanges plans the build against the state of the existing
+	// workspace WorkspaceID, and is called with the planned changes instead
+	// of showing the resources of the workspace.
+	WorkspaceID    uuid.UUID
+	PreviewChanges func(changes []codersdk.ResourceChange) error
 }
 
 // prepWorkspaceBuild will ensure a workspace build will succeed on the latest template version.
@@ -327,10 +333,14 @@ func prepWorkspaceBuild(inv *clibase.Invocation, client *codersdk.Client, args p
 	}
 
 	// Run a dry-run with the given parameters to check correctness
-	dryRun, err := client.CreateTemplateVersionDryRun(inv.Context(), templateVersion.ID, codersdk.CreateTemplateVersionDryRunRequest{
+	dryRunReq := codersdk.CreateTemplateVersionDryRunRequest{
 		WorkspaceName:       args.NewWorkspaceName,
 		RichParameterValues: buildParameters,
-	})
+	}
+	if args.PreviewChanges != nil {
+		dryRunReq.WorkspaceID = args.WorkspaceID
+	}
+	dryRun, err := client.CreateTemplateVersionDryRun(inv.Context(), templateVersion.ID, dryRunReq)
 	if err != nil {
 		return nil, xerrors.Errorf("begin workspace dry-run: %w", err)
 	}
@@ -354,6 +364,18 @@ func prepWorkspaceBuild(inv *clibase.Invocation, client *codersdk.Client, args p
 		return nil, xerrors.Errorf("dry-run workspace: %w", err)
 	}
 
+	if args.PreviewChanges != nil {
+		changes, err := client.TemplateVersionDryRunResourceChanges(inv.Context(), templateVersion.ID, dryRun.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace dry-run resource changes: %w", err)
+		}
+		err = args.PreviewChanges(changes)
+		if err != nil {
+			return nil, err
+		}
+		return buildParameters, nil
+	}
+
 	resources, err := client.TemplateVersionDryRunResources(inv.Context(), templateVersion.ID, dryRun.ID)
 	if err != nil {
 		return nil, xerrors.Errorf("get workspace dry-run resources: %w", err)
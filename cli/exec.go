@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// execResult is printed by coder exec --json once the command exits.
+type execResult struct {
+	ExitCode int    `json:"exit_code"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	// Error is set if the command could not be started, or was killed
+	// before it exited.
+	Error          string `json:"error,omitempty"`
+	DurationMillis int64  `json:"duration_ms"`
+}
+
+func (r *RootCmd) exec() *clibase.Cmd {
+	var (
+		env              []string
+		dir              string
+		timeout          time.Duration
+		jsonOutput       bool
+		disableAutostart bool
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "exec <workspace> -- <command>",
+		Short:       "Run a command in a workspace and exit with its exit code",
+		Long: "The command is run with the user's shell, without a terminal or any input. Its output is streamed " +
+			"back as it is written, and coder exits with the command's exit code.\n\n" +
+			formatExamples(
+				example{
+					Description: "Run the tests in a workspace",
+					Command:     "coder exec my-workspace -- make test",
+				},
+				example{
+					Description: "Run a command in a directory with an extra environment variable",
+					Command:     "coder exec --dir project -e CI=true my-workspace -- ./build.sh",
+				},
+				example{
+					Description: "Print the output and exit code as JSON, giving up after 10 minutes",
+					Command:     "coder exec --json --timeout 10m my-workspace -- make test",
+				},
+			),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(2, -1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, stop := inv.SignalNotifyContext(inv.Context(), InterruptSignals...)
+			defer stop()
+
+			req := codersdk.WorkspaceAgentExecRequest{
+				Command:       strings.Join(inv.Args[1:], " "),
+				Dir:           dir,
+				TimeoutMillis: timeout.Milliseconds(),
+			}
+			if len(env) > 0 {
+				req.Env = make(map[string]string, len(env))
+				for _, kv := range env {
+					k, v, ok := strings.Cut(kv, "=")
+					if !ok || k == "" {
+						return xerrors.Errorf("invalid environment variable %q, must be KEY=VALUE", kv)
+					}
+					req.Env[k] = v
+				}
+			}
+
+			_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, !disableAutostart, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			if r.disableDirect {
+				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
+			}
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				Logger:         inv.Logger,
+				BlockEndpoints: r.disableDirect,
+			})
+			if err != nil {
+				return xerrors.Errorf("dial agent: %w", err)
+			}
+			defer conn.Close()
+			if !conn.AwaitReachable(ctx) {
+				return xerrors.Errorf("await agent reachable: %w", ctx.Err())
+			}
+
+			start := time.Now()
+			stream, err := conn.Exec(ctx, req)
+			if err != nil {
+				return xerrors.Errorf("exec: %w", err)
+			}
+			defer stream.Close()
+
+			var stdout, stderr strings.Builder
+			var exit codersdk.WorkspaceAgentExecFrame
+			for exit.Type != codersdk.WorkspaceAgentExecFrameExit {
+				frame, err := stream.Recv()
+				if err != nil {
+					return err
+				}
+				switch frame.Type {
+				case codersdk.WorkspaceAgentExecFrameStdout:
+					if jsonOutput {
+						_, _ = stdout.Write(frame.Data)
+					} else {
+						_, _ = inv.Stdout.Write(frame.Data)
+					}
+				case codersdk.WorkspaceAgentExecFrameStderr:
+					if jsonOutput {
+						_, _ = stderr.Write(frame.Data)
+					} else {
+						_, _ = inv.Stderr.Write(frame.Data)
+					}
+				case codersdk.WorkspaceAgentExecFrameExit:
+					exit = frame
+				}
+			}
+
+			exitCode := exit.ExitCode
+			if exitCode < 0 {
+				// The command didn't exit by itself, so use the same code
+				// as a lost SSH connection.
+				exitCode = 255
+			}
+			if jsonOutput {
+				out, err := json.MarshalIndent(execResult{
+					ExitCode:       exit.ExitCode,
+					Stdout:         stdout.String(),
+					Stderr:         stderr.String(),
+					Error:          exit.Error,
+					DurationMillis: time.Since(start).Milliseconds(),
+				}, "", "  ")
+				if err != nil {
+					return xerrors.Errorf("marshal result: %w", err)
+				}
+				_, _ = fmt.Fprintln(inv.Stdout, string(out))
+				if exitCode != 0 {
+					return ExitError(exitCode, nil)
+				}
+				return nil
+			}
+			if exit.Error != "" {
+				return ExitError(exitCode, xerrors.New(exit.Error))
+			}
+			if exitCode != 0 {
+				return ExitError(exitCode, nil)
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "env",
+			FlagShorthand: "e",
+			Env:           "CODER_EXEC_ENV",
+			Description:   "Set an environment variable for the command, as KEY=VALUE. Can be specified multiple times.",
+			Value:         clibase.StringArrayOf(&env),
+		},
+		{
+			Flag:        "dir",
+			Env:         "CODER_EXEC_DIR",
+			Description: "The directory to run the command in. Relative paths are relative to the agent's directory, or the home directory if it isn't set.",
+			Value:       clibase.StringOf(&dir),
+		},
+		{
+			Flag:        "timeout",
+			Env:         "CODER_EXEC_TIMEOUT",
+			Description: "Kill the command if it runs for longer than this. Zero means no timeout.",
+			Value:       clibase.DurationOf(&timeout),
+		},
+		{
+			Flag:        "json",
+			Description: "Print the exit code and output as a JSON object once the command exits, instead of streaming the output.",
+			Value:       clibase.BoolOf(&jsonOutput),
+		},
+		sshDisableAutostartOption(clibase.BoolOf(&disableAutostart)),
+	}
+	return cmd
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/clibase"
@@ -97,6 +98,18 @@ func (r *RootCmd) list() *clibase.Cmd {
 				return err
 			}
 
+			// Only restrict the list when an organization was explicitly
+			// selected, otherwise workspaces from every organization are shown.
+			if selectedOrganization(inv) != "" {
+				organization, err := CurrentOrganization(inv, client)
+				if err != nil {
+					return err
+				}
+				res = slices.DeleteFunc(res, func(row workspaceListRow) bool {
+					return row.OrganizationID != organization.ID
+				})
+			}
+
 			if len(res) == 0 {
 				pretty.Fprintf(inv.Stderr, cliui.DefaultStyles.Prompt, "No workspaces found! Create one:\n")
 				_, _ = fmt.Fprintln(inv.Stderr)
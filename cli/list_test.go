@@ -74,4 +74,39 @@ func TestList(t *testing.T) {
 		require.NoError(t, json.Unmarshal(out.Bytes(), &workspaces))
 		require.Len(t, workspaces, 1)
 	})
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_ = dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        owner.UserID,
+		}).WithAgent().Do()
+
+		ctx, cancelFunc := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancelFunc()
+
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "business-unit",
+		})
+		require.NoError(t, err)
+		r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: org.ID,
+			OwnerID:        owner.UserID,
+		}).WithAgent().Do()
+
+		inv, root := clitest.New(t, "--org", org.Name, "list", "--output=json")
+		clitest.SetupConfig(t, client, root)
+
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var workspaces []codersdk.Workspace
+		require.NoError(t, json.Unmarshal(out.Bytes(), &workspaces))
+		require.Len(t, workspaces, 1)
+		require.Equal(t, r.Workspace.ID, workspaces[0].ID)
+	})
 }
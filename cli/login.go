@@ -133,6 +133,7 @@ func (r *RootCmd) login() *clibase.Cmd {
 		password           string
 		trial              bool
 		useTokenForSession bool
+		loginProvider      string
 	)
 	cmd := &clibase.Cmd{
 		Use:        "login <url>",
@@ -271,10 +272,20 @@ func (r *RootCmd) login() *clibase.Cmd {
 
 			sessionToken, _ := inv.ParsedFlags().GetString(varToken)
 			if sessionToken == "" {
+				provider, err := selectLoginProvider(inv, client, loginProvider)
+				if err != nil {
+					return err
+				}
 				authURL := *serverURL
 				// Don't use filepath.Join, we don't want to use the os separator
 				// for a url.
 				authURL.Path = path.Join(serverURL.Path, "/cli-auth")
+				if provider != nil {
+					// Log in with the provider first, which then redirects to
+					// the page that displays the token.
+					authURL.Path = path.Join(serverURL.Path, provider.CallbackURL)
+					authURL.RawQuery = url.Values{"redirect": {"/cli-auth"}}.Encode()
+				}
 				if err := openURL(inv, authURL.String()); err != nil {
 					_, _ = fmt.Fprintf(inv.Stdout, "Open the following in your browser:\n\n\t%s\n\n", authURL.String())
 				} else {
@@ -364,10 +375,69 @@ func (r *RootCmd) login() *clibase.Cmd {
 			Description: "By default, the CLI will generate a new session token when logging in. This flag will instead use the provided token as the session token.",
 			Value:       clibase.BoolOf(&useTokenForSession),
 		},
+		{
+			Flag:        "login-provider",
+			Env:         "CODER_LOGIN_WITH_PROVIDER",
+			Description: "The ID of the login provider to authenticate with, if the deployment has multiple. By default, you are prompted to pick one in interactive mode.",
+			Value:       clibase.StringOf(&loginProvider),
+		},
 	}
 	return cmd
 }
 
+// selectLoginProvider returns the named login provider to authenticate with,
+// or nil to use the default login page. The user is prompted to pick one if
+// the deployment has any and no ID was provided.
+func selectLoginProvider(inv *clibase.Invocation, client *codersdk.Client, id string) (*codersdk.LoginProviderAuthMethod, error) {
+	if id == "" && !isTTY(inv) {
+		return nil, nil
+	}
+	authMethods, err := client.AuthMethods(inv.Context())
+	if err != nil {
+		if id == "" {
+			// Older deployments may not list login providers, so fall back
+			// to the default login page.
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("get auth methods: %w", err)
+	}
+	providers := authMethods.LoginProviders
+	if id != "" {
+		ids := make([]string, 0, len(providers))
+		for i, provider := range providers {
+			if provider.ID == id {
+				return &providers[i], nil
+			}
+			ids = append(ids, provider.ID)
+		}
+		return nil, xerrors.Errorf("login provider %q not found, available providers: %q", id, ids)
+	}
+	if len(providers) == 0 {
+		return nil, nil
+	}
+
+	const defaultOption = "Default"
+	options := []string{defaultOption}
+	for _, provider := range providers {
+		options = append(options, provider.DisplayName)
+	}
+	_, _ = fmt.Fprintln(inv.Stdout, "Which login provider would you like to use?")
+	selected, err := cliui.Select(inv, cliui.SelectOptions{
+		Options:    options,
+		Default:    defaultOption,
+		HideSearch: true,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("select login provider: %w", err)
+	}
+	for i, provider := range providers {
+		if provider.DisplayName == selected {
+			return &providers[i], nil
+		}
+	}
+	return nil, nil
+}
+
 // isWSL determines if coder-cli is running within Windows Subsystem for Linux
 func isWSL() (bool, error) {
 	if runtime.GOOS == goosDarwin || runtime.GOOS == goosWindows {
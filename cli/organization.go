@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+)
+
+func (r *RootCmd) organizations() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "organizations [subcommand]",
+		Short: "Manage organizations",
+		Long: "Commands that operate on a single organization use the organization selected with the global --org flag.\n" + formatExamples(
+			example{
+				Description: "Create an organization",
+				Command:     "coder organizations create engineering",
+			},
+			example{
+				Description: "Add a user to an organization",
+				Command:     "coder --org engineering organizations members add alice",
+			},
+			example{
+				Description: "Make a member an organization admin",
+				Command:     "coder --org engineering organizations members roles alice organization-admin",
+			},
+			example{
+				Description: "Push a template to an organization",
+				Command:     "coder --org engineering templates push my-template",
+			},
+		),
+		Aliases: []string{"organization", "org", "orgs"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.createOrganization(),
+			r.listOrganizations(),
+			r.showOrganization(),
+			r.organizationMembers(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) createOrganization() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a new organization",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			org, err := client.CreateOrganization(inv.Context(), codersdk.CreateOrganizationRequest{
+				Name: inv.Args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Organization %s (%s) has been created!\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, org.Name), org.ID)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) listOrganizations() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]codersdk.Organization{}, []string{"name", "id", "created at"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Short:   "List the organizations you are a member of",
+		Aliases: []string{"ls"},
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			orgs, err := client.OrganizationsByUser(inv.Context(), codersdk.Me)
+			if err != nil {
+				return err
+			}
+
+			out, err := formatter.Format(inv.Context(), orgs)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) showOrganization() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]codersdk.Organization{}, []string{"name", "id", "created at"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+
+	cmd := &clibase.Cmd{
+		Use:   "show [name|id]",
+		Short: "Show an organization. Defaults to the currently selected organization.",
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			var (
+				org codersdk.Organization
+				err error
+			)
+			if len(inv.Args) == 0 {
+				org, err = CurrentOrganization(inv, client)
+			} else if id, parseErr := uuid.Parse(inv.Args[0]); parseErr == nil {
+				org, err = client.Organization(inv.Context(), id)
+			} else {
+				org, err = client.OrganizationByName(inv.Context(), codersdk.Me, inv.Args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			out, err := formatter.Format(inv.Context(), []codersdk.Organization{org})
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
@@ -0,0 +1,114 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOrganizations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateListShow", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "organizations", "create", "business-unit")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "business-unit")
+
+		inv, root = clitest.New(t, "organizations", "list", "-o", "json")
+		clitest.SetupConfig(t, client, root)
+		buf = new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.Run()
+		require.NoError(t, err)
+		var orgs []codersdk.Organization
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &orgs))
+		require.Len(t, orgs, 2)
+
+		inv, root = clitest.New(t, "--org", "business-unit", "organizations", "show", "-o", "json")
+		clitest.SetupConfig(t, client, root)
+		buf = new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.Run()
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &orgs))
+		require.Len(t, orgs, 1)
+		require.Equal(t, "business-unit", orgs[0].Name)
+	})
+
+	t.Run("UnknownOrg", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "--org", "does-not-exist", "organizations", "show")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "does-not-exist")
+	})
+
+	t.Run("Members", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "business-unit",
+		})
+		require.NoError(t, err)
+
+		inv, root := clitest.New(t, "--org", org.Name, "organizations", "members", "add", user.Username)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.Run())
+
+		inv, root = clitest.New(t, "--org", org.Name, "organizations", "members", "roles", user.Username, "organization-admin")
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.Run())
+
+		members, err := client.OrganizationMembers(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		for _, member := range members {
+			if member.UserID != user.ID {
+				continue
+			}
+			roles := make([]string, 0, len(member.Roles))
+			for _, role := range member.Roles {
+				roles = append(roles, role.Name)
+			}
+			require.Contains(t, roles, rbac.RoleOrgAdmin(org.ID))
+		}
+
+		inv, root = clitest.New(t, "--org", org.ID.String(), "organizations", "members", "list")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		require.NoError(t, inv.Run())
+		require.Contains(t, buf.String(), user.Username)
+
+		inv, root = clitest.New(t, "--org", org.Name, "organizations", "members", "remove", user.Username)
+		clitest.SetupConfig(t, client, root)
+		require.NoError(t, inv.Run())
+
+		members, err = client.OrganizationMembers(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+	})
+}
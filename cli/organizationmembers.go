@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+)
+
+func (r *RootCmd) organizationMembers() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:     "members [subcommand]",
+		Short:   "Manage members of the selected organization",
+		Aliases: []string{"member"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.listOrganizationMembers(),
+			r.addOrganizationMember(),
+			r.removeOrganizationMember(),
+			r.editOrganizationMemberRoles(),
+		},
+	}
+	return cmd
+}
+
+type organizationMemberRow struct {
+	// For JSON format:
+	codersdk.OrganizationMemberWithName `table:"-"`
+
+	// For table format:
+	Username  string    `json:"-" table:"username,default_sort"`
+	UserID    string    `json:"-" table:"user id"`
+	Roles     string    `json:"-" table:"roles"`
+	CreatedAt time.Time `json:"-" table:"created at"`
+}
+
+func organizationMemberRowFromMember(member codersdk.OrganizationMemberWithName) organizationMemberRow {
+	roles := make([]string, 0, len(member.Roles))
+	for _, role := range member.Roles {
+		roles = append(roles, role.Name)
+	}
+	return organizationMemberRow{
+		OrganizationMemberWithName: member,
+		Username:                   member.Username,
+		UserID:                     member.UserID.String(),
+		Roles:                      strings.Join(roles, ","),
+		CreatedAt:                  member.CreatedAt,
+	}
+}
+
+func (r *RootCmd) listOrganizationMembers() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]organizationMemberRow{}, []string{"username", "roles", "created at"}),
+		cliui.JSONFormat(),
+	)
+	client := new(codersdk.Client)
+
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Short:   "List members of the selected organization",
+		Aliases: []string{"ls"},
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			org, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return err
+			}
+
+			members, err := client.OrganizationMembers(inv.Context(), org.ID)
+			if err != nil {
+				return xerrors.Errorf("list members: %w", err)
+			}
+
+			rows := make([]organizationMemberRow, 0, len(members))
+			for _, member := range members {
+				rows = append(rows, organizationMemberRowFromMember(member))
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) addOrganizationMember() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "add <username|user_id>",
+		Short: "Add a user to the selected organization",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			org, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return err
+			}
+
+			_, err = client.PostOrganizationMember(inv.Context(), org.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("add member: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "User %s has been added to organization %s!\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[0]),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, org.Name))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) removeOrganizationMember() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "remove <username|user_id>",
+		Short: "Remove a user from the selected organization",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			org, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteOrganizationMember(inv.Context(), org.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("remove member: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "User %s has been removed from organization %s.\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[0]),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, org.Name))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) editOrganizationMemberRoles() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "roles <username|user_id> [roles...]",
+		Short: "Set the organization roles of a member. Omit the roles to remove all of them.",
+		Long: formatExamples(
+			example{
+				Description: "Make a member an organization admin",
+				Command:     "coder organizations members roles alice organization-admin",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(1, -1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			org, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return err
+			}
+
+			// Organization roles are suffixed with the organization ID, allow
+			// them to be given without it.
+			roles := make([]string, 0, len(inv.Args)-1)
+			for _, role := range inv.Args[1:] {
+				if !strings.Contains(role, ":") {
+					role = role + ":" + org.ID.String()
+				}
+				roles = append(roles, role)
+			}
+
+			_, err = client.UpdateOrganizationMemberRoles(inv.Context(), org.ID, inv.Args[0], codersdk.UpdateRoles{
+				Roles: roles,
+			})
+			if err != nil {
+				return xerrors.Errorf("update member roles: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Roles of %s in organization %s have been updated.\n",
+				pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[0]),
+				pretty.Sprint(cliui.DefaultStyles.Keyword, org.Name))
+			return nil
+		},
+	}
+	return cmd
+}
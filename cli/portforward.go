@@ -11,7 +11,9 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -28,6 +30,10 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 		tcpForwards      []string // <port>:<port>
 		udpForwards      []string // <port>:<port>
 		disableAutostart bool
+		share            bool
+		sharePorts       []string
+		shareExpiry      time.Duration
+		shareRevoke      []string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -55,6 +61,14 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 				Description: "Port forward specifying the local address to bind to",
 				Command:     "coder port-forward <workspace> --tcp 1.2.3.4:8080:8080",
 			},
+			example{
+				Description: "Share port 8080 with anyone who has the link for a day",
+				Command:     "coder port-forward <workspace> --share-port 8080 --share-expiry 24h",
+			},
+			example{
+				Description: "List the active share links of a workspace",
+				Command:     "coder port-forward <workspace> --share",
+			},
 		),
 		Middleware: clibase.Chain(
 			clibase.RequireNArgs(1),
@@ -64,6 +78,10 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
 
+			if share || len(sharePorts) > 0 || len(shareRevoke) > 0 {
+				return portForwardShare(inv, client, !disableAutostart, sharePorts, shareExpiry, shareRevoke)
+			}
+
 			specs, err := parsePortForwards(tcpForwards, udpForwards)
 			if err != nil {
 				return xerrors.Errorf("parse port-forward specs: %w", err)
@@ -185,12 +203,119 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 			Description: "Forward UDP port(s) from the workspace to the local machine. The UDP connection has TCP-like semantics to support stateful UDP protocols.",
 			Value:       clibase.StringArrayOf(&udpForwards),
 		},
+		{
+			Flag:        "share",
+			Description: "List the links that share ports in the workspace instead of forwarding ports.",
+			Value:       clibase.BoolOf(&share),
+		},
+		{
+			Flag:        "share-port",
+			Description: "Create a link to a port in the workspace that anyone can open without signing in, until it expires or is revoked. Requires a wildcard access URL.",
+			Value:       clibase.StringArrayOf(&sharePorts),
+		},
+		{
+			Flag:        "share-expiry",
+			Description: "How long links created with --share-port are valid for.",
+			Default:     "1h",
+			Value:       clibase.DurationOf(&shareExpiry),
+		},
+		{
+			Flag:        "share-revoke",
+			Description: "Revoke a share link by its ID.",
+			Value:       clibase.StringArrayOf(&shareRevoke),
+		},
 		sshDisableAutostartOption(clibase.BoolOf(&disableAutostart)),
 	}
 
 	return cmd
 }
 
+type portShareRow struct {
+	ID        uuid.UUID `table:"id"`
+	AgentName string    `table:"agent"`
+	Port      int32     `table:"port,default_sort"`
+	ExpiresAt time.Time `table:"expires at"`
+	URL       string    `table:"url"`
+}
+
+// portForwardShare creates and revokes port share links, then lists the
+// active links of the workspace.
+func portForwardShare(inv *clibase.Invocation, client *codersdk.Client, autostart bool, ports []string, expiry time.Duration, revoke []string) error {
+	ctx := inv.Context()
+
+	var (
+		workspace codersdk.Workspace
+		agentName string
+		err       error
+	)
+	if len(ports) > 0 {
+		var agent codersdk.WorkspaceAgent
+		workspace, agent, err = getWorkspaceAndAgent(ctx, inv, client, autostart, codersdk.Me, inv.Args[0])
+		if err != nil {
+			return err
+		}
+		agentName = agent.Name
+	} else {
+		// Listing and revoking links doesn't need a running agent.
+		workspaceName, _, _ := strings.Cut(inv.Args[0], ".")
+		workspace, err = namedWorkspace(ctx, client, workspaceName)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+	}
+
+	for _, id := range revoke {
+		shareID, err := uuid.Parse(id)
+		if err != nil {
+			return xerrors.Errorf("parse --share-revoke %q: %w", id, err)
+		}
+		err = client.DeleteWorkspacePortShare(ctx, workspace.ID, shareID)
+		if err != nil {
+			return xerrors.Errorf("revoke share link %s: %w", shareID, err)
+		}
+	}
+
+	for _, p := range ports {
+		port, err := strconv.ParseUint(p, 10, 16)
+		if err != nil {
+			return xerrors.Errorf("parse --share-port %q: %w", p, err)
+		}
+		_, err = client.CreateWorkspacePortShare(ctx, workspace.ID, codersdk.CreateWorkspacePortShareRequest{
+			AgentName: agentName,
+			Port:      int32(port),
+			TTLMillis: expiry.Milliseconds(),
+		})
+		if err != nil {
+			return xerrors.Errorf("share port %d: %w", port, err)
+		}
+	}
+
+	shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+	if err != nil {
+		return xerrors.Errorf("get share links: %w", err)
+	}
+	if len(shares) == 0 {
+		_, _ = fmt.Fprintf(inv.Stderr, "Workspace %q has no active share links.\n", workspace.Name)
+		return nil
+	}
+	rows := make([]portShareRow, 0, len(shares))
+	for _, share := range shares {
+		rows = append(rows, portShareRow{
+			ID:        share.ID,
+			AgentName: share.AgentName,
+			Port:      share.Port,
+			ExpiresAt: share.ExpiresAt,
+			URL:       share.URL,
+		})
+	}
+	out, err := cliui.DisplayTable(rows, "", nil)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(inv.Stdout, out)
+	return err
+}
+
 func listenAndPortForward(
 	ctx context.Context,
 	inv *clibase.Invocation,
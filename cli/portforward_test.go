@@ -1,6 +1,7 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -22,6 +23,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/pty/ptytest"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -265,6 +267,47 @@ func TestPortForward(t *testing.T) {
 	})
 }
 
+func TestPortForward_Share(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+		AppHostname: "*.apps.coder.test",
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	workspace := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Name = "dev"
+		return agents
+	}).Do().Workspace
+
+	inv, root := clitest.New(t, "port-forward", workspace.Name, "--share-port", "8080", "--share-expiry", "2h")
+	clitest.SetupConfig(t, member, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "8080--")
+	require.Contains(t, buf.String(), codersdk.PortShareTokenQueryParameter)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	shares, err := member.WorkspacePortShares(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	require.EqualValues(t, 8080, shares[0].Port)
+	require.WithinDuration(t, time.Now().Add(2*time.Hour), shares[0].ExpiresAt, time.Minute)
+
+	inv, root = clitest.New(t, "port-forward", workspace.Name, "--share-revoke", shares[0].ID.String())
+	clitest.SetupConfig(t, member, root)
+	errBuf := new(bytes.Buffer)
+	inv.Stderr = errBuf
+	err = inv.Run()
+	require.NoError(t, err)
+	require.Contains(t, errBuf.String(), "no active share links")
+}
+
 // runAgent creates a fake workspace and starts an agent locally for that
 // workspace. The agent will be cleaned up on test completion.
 // nolint:unused
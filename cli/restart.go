@@ -35,7 +35,7 @@ func (r *RootCmd) restart() *clibase.Cmd {
 				return err
 			}
 
-			startReq, err := buildWorkspaceStartRequest(inv, client, workspace, parameterFlags, WorkspaceRestart)
+			startReq, err := buildWorkspaceStartRequest(inv, client, workspace, parameterFlags, WorkspaceRestart, nil)
 			if err != nil {
 				return err
 			}
@@ -64,6 +64,7 @@ const (
 	varForceTty         = "force-tty"
 	varVerbose          = "verbose"
 	varDisableDirect    = "disable-direct-connections"
+	varOrganization     = "org"
 	notLoggedInMessage  = "You are not logged in. Try logging in using 'coder login <url>'."
 
 	envNoVersionCheck   = "CODER_NO_VERSION_WARNING"
@@ -86,6 +87,7 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.login(),
 		r.logout(),
 		r.netcheck(),
+		r.organizations(),
 		r.portForward(),
 		r.publickey(),
 		r.resetPassword(),
@@ -98,12 +100,16 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		// Workspace Commands
 		r.autoupdate(),
 		r.configSSH(),
+		r.cp(),
 		r.create(),
 		r.deleteWorkspace(),
+		r.exec(),
 		r.list(),
 		r.ping(),
 		r.rename(),
 		r.schedules(),
+		r.sessions(),
+		r.share(),
 		r.show(),
 		r.speedtest(),
 		r.ssh(),
@@ -408,6 +414,13 @@ func (r *RootCmd) Command(subcommands []*clibase.Cmd) (*clibase.Cmd, error) {
 			Value:         clibase.BoolOf(&r.verbose),
 			Group:         globalGroup,
 		},
+		{
+			Flag:        varOrganization,
+			Env:         "CODER_ORGANIZATION",
+			Description: "Select which organization (name or ID) to use. Defaults to the first organization you are a member of.",
+			Value:       clibase.StringOf(&r.organization),
+			Group:       globalGroup,
+		},
 		{
 			Flag:        varDisableDirect,
 			Env:         "CODER_DISABLE_DIRECT_CONNECTIONS",
@@ -465,6 +478,7 @@ type RootCmd struct {
 	versionFlag    bool
 	disableDirect  bool
 	debugHTTP      bool
+	organization   string
 
 	noVersionCheck   bool
 	noFeatureWarning bool
@@ -677,15 +691,39 @@ func (r *RootCmd) createAgentClient() (*agentsdk.Client, error) {
 	return client, nil
 }
 
-// CurrentOrganization returns the currently active organization for the authenticated user.
+// CurrentOrganization returns the currently active organization for the
+// authenticated user. The organization can be selected with the global --org
+// flag, otherwise the first organization the user is a member of is used.
 func CurrentOrganization(inv *clibase.Invocation, client *codersdk.Client) (codersdk.Organization, error) {
 	orgs, err := client.OrganizationsByUser(inv.Context(), codersdk.Me)
 	if err != nil {
-		return codersdk.Organization{}, nil
+		return codersdk.Organization{}, xerrors.Errorf("get organizations: %w", err)
+	}
+
+	selected := selectedOrganization(inv)
+	if selected == "" {
+		if len(orgs) == 0 {
+			return codersdk.Organization{}, xerrors.New("you are not a member of any organizations")
+		}
+		return orgs[0], nil
+	}
+
+	for _, org := range orgs {
+		if org.Name == selected || org.ID.String() == selected {
+			return org, nil
+		}
+	}
+	return codersdk.Organization{}, xerrors.Errorf("organization %q not found, are you a member of it? Run 'coder organizations list' to see your organizations", selected)
+}
+
+// selectedOrganization returns the value of the global --org flag, or an
+// empty string if no organization was selected.
+func selectedOrganization(inv *clibase.Invocation) string {
+	if inv.ParsedFlags().Lookup(varOrganization) == nil {
+		return ""
 	}
-	// For now, we won't use the config to set this.
-	// Eventually, we will support changing using "coder switch <org>"
-	return orgs[0], nil
+	selected, _ := inv.ParsedFlags().GetString(varOrganization)
+	return selected
 }
 
 func splitNamedWorkspace(identifier string) (owner string, workspaceName string, err error) {
@@ -11,6 +11,7 @@ import (
 	"crypto/x509"
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -76,8 +77,10 @@ import (
 	"github.com/coder/coder/v2/coderd/devtunnel"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
+	"github.com/coder/coder/v2/coderd/gitsync"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/oauthpki"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics/insights"
@@ -185,6 +188,112 @@ func createOIDCConfig(ctx context.Context, vals *codersdk.DeploymentValues) (*co
 	}, nil
 }
 
+// configureLoginProviders validates the named login providers and creates
+// their configurations.
+func configureLoginProviders(ctx context.Context, accessURL *url.URL, configs []codersdk.LoginProviderConfig) ([]*coderd.LoginProvider, error) {
+	ids := map[string]struct{}{}
+	providers := make([]*coderd.LoginProvider, 0, len(configs))
+	for _, cfg := range configs {
+		if err := httpapi.NameValid(cfg.ID); err != nil {
+			return nil, xerrors.Errorf("login provider %q doesn't have a valid id: %w", cfg.ID, err)
+		}
+		if _, exists := ids[cfg.ID]; exists {
+			return nil, xerrors.Errorf("multiple login providers exist with the id %q. specify a unique id for each", cfg.ID)
+		}
+		ids[cfg.ID] = struct{}{}
+		if cfg.ClientID == "" {
+			return nil, xerrors.Errorf("%q login provider: client_id must be provided", cfg.ID)
+		}
+		if cfg.ClientSecret == "" {
+			return nil, xerrors.Errorf("%q login provider: client_secret must be provided", cfg.ID)
+		}
+
+		provider := &coderd.LoginProvider{
+			ID:          cfg.ID,
+			DisplayName: cfg.DisplayName,
+			IconURL:     cfg.IconURL,
+		}
+		if provider.DisplayName == "" {
+			provider.DisplayName = cfg.ID
+		}
+		switch cfg.Type {
+		case "github":
+			githubConfig, err := configureGithubOAuth2(accessURL,
+				provider.CallbackPath(),
+				cfg.ClientID,
+				cfg.ClientSecret,
+				cfg.AllowSignups,
+				cfg.AllowEveryone,
+				cfg.AllowedOrgs,
+				cfg.AllowedTeams,
+				cfg.EnterpriseBaseURL,
+			)
+			if err != nil {
+				return nil, xerrors.Errorf("configure %q login provider: %w", cfg.ID, err)
+			}
+			provider.Github = githubConfig
+		case "oidc":
+			if cfg.IssuerURL == "" {
+				return nil, xerrors.Errorf("%q login provider: issuer_url must be provided", cfg.ID)
+			}
+			oidcProvider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+			if err != nil {
+				return nil, xerrors.Errorf("configure %q login provider: %w", cfg.ID, err)
+			}
+			redirectURL, err := accessURL.Parse(provider.CallbackPath())
+			if err != nil {
+				return nil, xerrors.Errorf("parse %q login provider callback url: %w", cfg.ID, err)
+			}
+			scopes := cfg.Scopes
+			if len(scopes) == 0 {
+				scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+			}
+			emailField := cfg.EmailField
+			if emailField == "" {
+				emailField = "email"
+			}
+			usernameField := cfg.UsernameField
+			if usernameField == "" {
+				usernameField = "preferred_username"
+			}
+			groupField := cfg.GroupField
+			if slice.Contains(scopes, "groups") && groupField == "" {
+				groupField = "groups"
+			}
+			provider.OIDC = &coderd.OIDCConfig{
+				OAuth2Config: &oauth2.Config{
+					ClientID:     cfg.ClientID,
+					ClientSecret: cfg.ClientSecret,
+					RedirectURL:  redirectURL.String(),
+					Endpoint:     oidcProvider.Endpoint(),
+					Scopes:       scopes,
+				},
+				Provider: oidcProvider,
+				Verifier: oidcProvider.Verifier(&oidc.Config{
+					ClientID: cfg.ClientID,
+				}),
+				EmailDomain:         cfg.EmailDomain,
+				AllowSignups:        cfg.AllowSignups,
+				UsernameField:       usernameField,
+				EmailField:          emailField,
+				AuthURLParams:       map[string]string{},
+				IgnoreUserInfo:      cfg.IgnoreUserInfo,
+				GroupField:          groupField,
+				GroupMapping:        cfg.GroupMapping,
+				UserRoleField:       cfg.UserRoleField,
+				UserRoleMapping:     cfg.UserRoleMapping,
+				IconURL:             cfg.IconURL,
+				SignInText:          provider.DisplayName,
+				IgnoreEmailVerified: cfg.IgnoreEmailVerified,
+			}
+		default:
+			return nil, xerrors.Errorf("%q login provider: type must be one of \"oidc\" or \"github\", got %q", cfg.ID, cfg.Type)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
 func afterCtx(ctx context.Context, fn func()) {
 	go func() {
 		<-ctx.Done()
@@ -618,6 +727,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 			if vals.OAuth2.Github.ClientSecret != "" {
 				options.GithubOAuth2Config, err = configureGithubOAuth2(vals.AccessURL.Value(),
+					"/api/v2/users/oauth2/github/callback",
 					vals.OAuth2.Github.ClientID.String(),
 					vals.OAuth2.Github.ClientSecret.String(),
 					vals.OAuth2.Github.AllowSignups.Value(),
@@ -643,6 +753,19 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.OIDCConfig = oc
 			}
 
+			loginProvidersEnv, err := ReadLoginProvidersFromEnv(os.Environ())
+			if err != nil {
+				return xerrors.Errorf("read login providers from env: %w", err)
+			}
+			vals.LoginProviders.Value = append(vals.LoginProviders.Value, loginProvidersEnv...)
+			options.LoginProviders, err = configureLoginProviders(ctx, vals.AccessURL.Value(), vals.LoginProviders.Value)
+			if err != nil {
+				return xerrors.Errorf("configure login providers: %w", err)
+			}
+			for _, p := range options.LoginProviders {
+				logger.Debug(ctx, "loaded login provider", slog.F("id", p.ID))
+			}
+
 			if vals.InMemoryDatabase {
 				// This is only used for testing.
 				options.Database = dbmem.New()
@@ -847,6 +970,24 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			options.StatsBatcher = batcher
 			defer closeBatcher()
 
+			if vals.Notifications.Method.String() != "" {
+				enqueuer, err := notifications.NewStoreEnqueuer(vals.Notifications, options.Database, logger)
+				if err != nil {
+					return xerrors.Errorf("failed to create notifications enqueuer: %w", err)
+				}
+				options.NotificationsEnqueuer = enqueuer
+
+				notificationsTicker := time.NewTicker(vals.Notifications.FetchInterval.Value())
+				defer notificationsTicker.Stop()
+				notificationsManager, err := notifications.New(
+					ctx, vals.Notifications, options.Database, vals.AccessURL.Value(), logger, notificationsTicker.C)
+				if err != nil {
+					return xerrors.Errorf("failed to create notifications manager: %w", err)
+				}
+				notificationsManager.Start()
+				defer notificationsManager.Close()
+			}
+
 			// We use a separate coderAPICloser so the Enterprise API
 			// can have its own close functions. This is cleaner
 			// than abstracting the Coder API itself.
@@ -924,7 +1065,10 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			defer shutdownConns()
 
 			// Ensures that old database entries are cleaned up over time!
-			purger := dbpurge.New(ctx, logger, options.Database)
+			purger := dbpurge.New(ctx, logger, options.Database, dbpurge.Options{
+				Retention:  vals.Retention,
+				Registerer: options.PrometheusRegistry,
+			})
 			defer purger.Close()
 
 			// Wrap the server in middleware that redirects to the access URL if
@@ -991,7 +1135,8 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			autobuildTicker := time.NewTicker(vals.AutobuildPollInterval.Value())
 			defer autobuildTicker.Stop()
 			autobuildExecutor := autobuild.NewExecutor(
-				ctx, options.Database, options.Pubsub, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, logger, autobuildTicker.C)
+				ctx, options.Database, options.Pubsub, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, logger, autobuildTicker.C).
+				WithNotificationsEnqueuer(coderAPI.NotificationsEnqueuer, vals.Notifications.AutostopWarning.Value(), vals.Notifications.AutodeleteWarning.Value())
 			autobuildExecutor.Run()
 
 			hangDetectorTicker := time.NewTicker(vals.JobHangDetectorInterval.Value())
@@ -1000,6 +1145,12 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			hangDetector.Start()
 			defer hangDetector.Close()
 
+			gitPollerTicker := time.NewTicker(30 * time.Second)
+			defer gitPollerTicker.Stop()
+			gitPoller := gitsync.NewPoller(ctx, options.Database, options.Pubsub, logger.Named("gitsync"), gitPollerTicker.C)
+			gitPoller.Start()
+			defer gitPoller.Close()
+
 			// Currently there is no way to ask the server to shut
 			// itself down, so any exit signal will result in a non-zero
 			// exit of the server.
@@ -1732,8 +1883,8 @@ func configureCAPool(tlsClientCAFile string, tlsConfig *tls.Config) error {
 }
 
 //nolint:revive // Ignore flag-parameter: parameter 'allowEveryone' seems to be a control flag, avoid control coupling (revive)
-func configureGithubOAuth2(accessURL *url.URL, clientID, clientSecret string, allowSignups, allowEveryone bool, allowOrgs []string, rawTeams []string, enterpriseBaseURL string) (*coderd.GithubOAuth2Config, error) {
-	redirectURL, err := accessURL.Parse("/api/v2/users/oauth2/github/callback")
+func configureGithubOAuth2(accessURL *url.URL, callbackPath string, clientID, clientSecret string, allowSignups, allowEveryone bool, allowOrgs []string, rawTeams []string, enterpriseBaseURL string) (*coderd.GithubOAuth2Config, error) {
+	redirectURL, err := accessURL.Parse(callbackPath)
 	if err != nil {
 		return nil, xerrors.Errorf("parse github oauth callback url: %w", err)
 	}
@@ -2548,3 +2699,129 @@ func parseExternalAuthProvidersFromEnv(prefix string, environ []string) ([]coder
 	}
 	return providers, nil
 }
+
+// ReadLoginProvidersFromEnv parses named login providers from environment
+// variables of the form CODER_LOGIN_PROVIDER_<n>_<KEY>.
+func ReadLoginProvidersFromEnv(environ []string) ([]codersdk.LoginProviderConfig, error) {
+	type providerEnv struct {
+		num   int
+		key   string
+		value clibase.EnvVar
+	}
+	var envs []providerEnv
+	for _, v := range clibase.ParseEnviron(environ, "CODER_LOGIN_PROVIDER_") {
+		tokens := strings.SplitN(v.Name, "_", 2)
+		if len(tokens) != 2 {
+			return nil, xerrors.Errorf("invalid env var: %s", v.Name)
+		}
+
+		providerNum, err := strconv.Atoi(tokens[0])
+		if err != nil {
+			return nil, xerrors.Errorf("parse number: %s", v.Name)
+		}
+		envs = append(envs, providerEnv{num: providerNum, key: tokens[1], value: v})
+	}
+	// The index numbers must be in-order. Sort by the parsed number, since
+	// sorting the names as text would put 10 before 2.
+	sort.SliceStable(envs, func(i, j int) bool {
+		return envs[i].num < envs[j].num
+	})
+
+	var providers []codersdk.LoginProviderConfig
+	for _, env := range envs {
+		providerNum, key, v := env.num, env.key, env.value
+
+		var provider codersdk.LoginProviderConfig
+		switch {
+		case len(providers) < providerNum:
+			return nil, xerrors.Errorf(
+				"provider num %v skipped: %s",
+				len(providers),
+				v.Name,
+			)
+		case len(providers) == providerNum:
+			// At the next next provider.
+			providers = append(providers, provider)
+		case len(providers) == providerNum+1:
+			// At the current provider.
+			provider = providers[providerNum]
+		default:
+			return nil, xerrors.Errorf(
+				"provider num %v out of order: %s",
+				providerNum,
+				v.Name,
+			)
+		}
+
+		switch key {
+		case "ID":
+			provider.ID = v.Value
+		case "TYPE":
+			provider.Type = v.Value
+		case "CLIENT_ID":
+			provider.ClientID = v.Value
+		case "CLIENT_SECRET":
+			provider.ClientSecret = v.Value
+		case "DISPLAY_NAME":
+			provider.DisplayName = v.Value
+		case "ICON_URL":
+			provider.IconURL = v.Value
+		case "ISSUER_URL":
+			provider.IssuerURL = v.Value
+		case "SCOPES":
+			provider.Scopes = strings.Split(v.Value, " ")
+		case "EMAIL_DOMAIN":
+			provider.EmailDomain = strings.Split(v.Value, " ")
+		case "EMAIL_FIELD":
+			provider.EmailField = v.Value
+		case "USERNAME_FIELD":
+			provider.UsernameField = v.Value
+		case "GROUP_FIELD":
+			provider.GroupField = v.Value
+		case "GROUP_MAPPING":
+			err := json.Unmarshal([]byte(v.Value), &provider.GroupMapping)
+			if err != nil {
+				return nil, xerrors.Errorf("parse group mapping: %s", v.Name)
+			}
+		case "USER_ROLE_FIELD":
+			provider.UserRoleField = v.Value
+		case "USER_ROLE_MAPPING":
+			err := json.Unmarshal([]byte(v.Value), &provider.UserRoleMapping)
+			if err != nil {
+				return nil, xerrors.Errorf("parse user role mapping: %s", v.Name)
+			}
+		case "ALLOWED_ORGS":
+			provider.AllowedOrgs = strings.Split(v.Value, " ")
+		case "ALLOWED_TEAMS":
+			provider.AllowedTeams = strings.Split(v.Value, " ")
+		case "ENTERPRISE_BASE_URL":
+			provider.EnterpriseBaseURL = v.Value
+		case "ALLOW_SIGNUPS":
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse bool: %s", v.Value)
+			}
+			provider.AllowSignups = b
+		case "ALLOW_EVERYONE":
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse bool: %s", v.Value)
+			}
+			provider.AllowEveryone = b
+		case "IGNORE_EMAIL_VERIFIED":
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse bool: %s", v.Value)
+			}
+			provider.IgnoreEmailVerified = b
+		case "IGNORE_USER_INFO":
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil, xerrors.Errorf("parse bool: %s", v.Value)
+			}
+			provider.IgnoreUserInfo = b
+		}
+		providers[providerNum] = provider
+	}
+	return providers, nil
+}
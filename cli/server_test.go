@@ -86,6 +86,69 @@ func TestReadExternalAuthProvidersFromEnv(t *testing.T) {
 	})
 }
 
+func TestReadLoginProvidersFromEnv(t *testing.T) {
+	t.Parallel()
+	t.Run("SkipKey", func(t *testing.T) {
+		t.Parallel()
+		providers, err := cli.ReadLoginProvidersFromEnv([]string{
+			"CODER_LOGIN_PROVIDER_0_ID=invalid",
+			"CODER_LOGIN_PROVIDER_2_ID=invalid",
+		})
+		require.Error(t, err, "%+v", providers)
+		require.Empty(t, providers)
+	})
+	t.Run("NumericOrder", func(t *testing.T) {
+		t.Parallel()
+		environ := []string{}
+		for i := 11; i >= 0; i-- {
+			environ = append(environ, fmt.Sprintf("CODER_LOGIN_PROVIDER_%d_ID=provider-%d", i, i))
+		}
+		providers, err := cli.ReadLoginProvidersFromEnv(environ)
+		require.NoError(t, err)
+		require.Len(t, providers, 12)
+		for i, provider := range providers {
+			assert.Equal(t, fmt.Sprintf("provider-%d", i), provider.ID)
+		}
+	})
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		providers, err := cli.ReadLoginProvidersFromEnv([]string{
+			"CODER_LOGIN_PROVIDER_0_ID=acme",
+			"CODER_LOGIN_PROVIDER_0_TYPE=oidc",
+			"CODER_LOGIN_PROVIDER_0_CLIENT_ID=client",
+			"CODER_LOGIN_PROVIDER_0_CLIENT_SECRET=hunter12",
+			"CODER_LOGIN_PROVIDER_0_ISSUER_URL=https://sso.acme.com",
+			"CODER_LOGIN_PROVIDER_0_SCOPES=openid email groups",
+			"CODER_LOGIN_PROVIDER_0_ALLOW_SIGNUPS=true",
+			"CODER_LOGIN_PROVIDER_0_GROUP_MAPPING={\"eng\":\"engineering\"}",
+			"CODER_LOGIN_PROVIDER_0_DISPLAY_NAME=Acme SSO",
+			"CODER_LOGIN_PROVIDER_1_ID=globex",
+			"CODER_LOGIN_PROVIDER_1_TYPE=github",
+			"CODER_LOGIN_PROVIDER_1_ALLOWED_ORGS=globex globex-labs",
+			"CODER_LOGIN_PROVIDER_1_ALLOW_EVERYONE=false",
+		})
+		require.NoError(t, err)
+		require.Len(t, providers, 2)
+
+		// Validate the first provider.
+		assert.Equal(t, "acme", providers[0].ID)
+		assert.Equal(t, "oidc", providers[0].Type)
+		assert.Equal(t, "client", providers[0].ClientID)
+		assert.Equal(t, "hunter12", providers[0].ClientSecret)
+		assert.Equal(t, "https://sso.acme.com", providers[0].IssuerURL)
+		assert.Equal(t, []string{"openid", "email", "groups"}, providers[0].Scopes)
+		assert.Equal(t, true, providers[0].AllowSignups)
+		assert.Equal(t, map[string]string{"eng": "engineering"}, providers[0].GroupMapping)
+		assert.Equal(t, "Acme SSO", providers[0].DisplayName)
+
+		// Validate the second provider.
+		assert.Equal(t, "globex", providers[1].ID)
+		assert.Equal(t, "github", providers[1].Type)
+		assert.Equal(t, []string{"globex", "globex-labs"}, providers[1].AllowedOrgs)
+		assert.Equal(t, false, providers[1].AllowEveryone)
+	})
+}
+
 // TestReadGitAuthProvidersFromEnv ensures that the deprecated `CODER_GITAUTH_`
 // environment variables are still supported.
 func TestReadGitAuthProvidersFromEnv(t *testing.T) {
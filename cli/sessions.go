@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) sessions() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "sessions",
+		Short: "List and replay recorded terminal sessions",
+		Long: "Terminal sessions are recorded when the template or deployment enables session recording.\n" + formatExamples(
+			example{
+				Description: "List the recorded sessions of a workspace",
+				Command:     "coder sessions list my-workspace",
+			},
+			example{
+				Description: "Replay a recorded session at twice the speed",
+				Command:     "coder sessions replay my-workspace 1b9f0c4e-4b7a-4f4f-9e59-0b7f3c2d8e11 --speed 2",
+			},
+			example{
+				Description: "Save a recorded session to play it with asciinema",
+				Command:     "coder sessions replay my-workspace 1b9f0c4e-4b7a-4f4f-9e59-0b7f3c2d8e11 --raw > session.cast",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.sessionsList(),
+			r.sessionsReplay(),
+		},
+	}
+	return cmd
+}
+
+// sessionRecordingRow is the type provided to the OutputFormatter.
+type sessionRecordingRow struct {
+	// For JSON format:
+	codersdk.WorkspaceSessionRecording `table:"-"`
+
+	// For table format:
+	ID        string        `json:"-" table:"id"`
+	Type      string        `json:"-" table:"type"`
+	StartedAt time.Time     `json:"-" table:"started at,default_sort"`
+	Duration  time.Duration `json:"-" table:"duration"`
+	Size      string        `json:"-" table:"size"`
+}
+
+func (r *RootCmd) sessionsList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]sessionRecordingRow{}, []string{"id", "type", "started at", "duration", "size"}),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list <workspace>",
+		Aliases: []string{"ls"},
+		Short:   "List the recorded sessions of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			recordings, err := client.WorkspaceSessionRecordings(inv.Context(), workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("list session recordings: %w", err)
+			}
+
+			if len(recordings) == 0 {
+				cliui.Infof(
+					inv.Stdout,
+					"No sessions have been recorded in %s.\n", workspace.Name,
+				)
+			}
+
+			rows := make([]sessionRecordingRow, 0, len(recordings))
+			for _, recording := range recordings {
+				rows = append(rows, sessionRecordingRow{
+					WorkspaceSessionRecording: recording,
+					ID:                        recording.ID.String(),
+					Type:                      string(recording.Type),
+					StartedAt:                 recording.StartedAt,
+					Duration:                  recording.EndedAt.Sub(recording.StartedAt).Round(time.Second),
+					Size:                      cliui.FormatBytes(recording.Size),
+				})
+			}
+
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) sessionsReplay() *clibase.Cmd {
+	var (
+		speed         int64
+		idleTimeLimit time.Duration
+		raw           bool
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "replay <workspace> <recording>",
+		Short: "Replay a recorded session in the terminal",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			if speed < 1 {
+				return xerrors.New("--speed must be at least 1")
+			}
+			recordingID, err := uuid.Parse(inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("invalid recording ID %q: %w", inv.Args[1], err)
+			}
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			cast, err := client.WorkspaceSessionRecordingCast(inv.Context(), workspace.ID, recordingID)
+			if err != nil {
+				return xerrors.Errorf("get session recording: %w", err)
+			}
+			defer cast.Close()
+
+			if raw {
+				_, err = io.Copy(inv.Stdout, cast)
+				return err
+			}
+			return replayCast(inv.Context(), inv.Stdout, cast, time.Duration(speed), idleTimeLimit)
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "speed",
+			Description: "Play the session this many times faster than it was recorded.",
+			Default:     "1",
+			Value:       clibase.Int64Of(&speed),
+		},
+		{
+			Flag:        "idle-time-limit",
+			Description: "Limit pauses in the session to at most this duration. Set to 0 to play pauses in full.",
+			Default:     "2s",
+			Value:       clibase.DurationOf(&idleTimeLimit),
+		},
+		{
+			Flag:        "raw",
+			Description: "Print the recording in asciicast v2 format instead of playing it, e.g. to play it with asciinema.",
+			Value:       clibase.BoolOf(&raw),
+		},
+	}
+	return cmd
+}
+
+// replayCast writes the output events of an asciicast v2 recording to w with
+// the timing they were recorded with.
+func replayCast(ctx context.Context, w io.Writer, cast io.Reader, speed, idleTimeLimit time.Duration) error {
+	scanner := bufio.NewScanner(cast)
+	// Output events can be large, e.g. when a full screen is redrawn.
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return xerrors.Errorf("read header: %w", err)
+		}
+		return xerrors.New("recording is empty")
+	}
+	var header struct {
+		Version int `json:"version"`
+	}
+	err := json.Unmarshal(scanner.Bytes(), &header)
+	if err != nil {
+		return xerrors.Errorf("decode header: %w", err)
+	}
+	if header.Version != 2 {
+		return xerrors.Errorf("unsupported asciicast version %d", header.Version)
+	}
+
+	var last float64
+	for scanner.Scan() {
+		var (
+			event   []json.RawMessage
+			elapsed float64
+			typ     string
+			data    string
+		)
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return xerrors.Errorf("decode event: %w", err)
+		}
+		if len(event) != 3 {
+			return xerrors.Errorf("event has %d elements, expected 3", len(event))
+		}
+		if err := json.Unmarshal(event[0], &elapsed); err != nil {
+			return xerrors.Errorf("decode event time: %w", err)
+		}
+		if err := json.Unmarshal(event[1], &typ); err != nil {
+			return xerrors.Errorf("decode event type: %w", err)
+		}
+		if err := json.Unmarshal(event[2], &data); err != nil {
+			return xerrors.Errorf("decode event data: %w", err)
+		}
+		// The terminal replaying the session cannot be resized, and markers
+		// only annotate the recording.
+		if typ != "o" {
+			continue
+		}
+
+		wait := time.Duration((elapsed - last) * float64(time.Second))
+		last = elapsed
+		if idleTimeLimit > 0 && wait > idleTimeLimit {
+			wait = idleTimeLimit
+		}
+		if wait /= speed; wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
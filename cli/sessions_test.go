@@ -0,0 +1,115 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSessions(t *testing.T) {
+	t.Parallel()
+
+	cast := `{"version":2,"width":80,"height":24}` + "\n" +
+		`[0.1,"o","hello "]` + "\n" +
+		`[0.2,"r","100x40"]` + "\n" +
+		`[5.0,"o","world\r\n"]` + "\n"
+
+	client, workspace, agentToken := setupWorkspaceForAgent(t)
+	ctx := testutil.Context(t, testutil.WaitLong)
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(agentToken)
+
+	// No sessions have been recorded yet.
+	inv, root := clitest.New(t, "sessions", "list", workspace.Name)
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "No sessions have been recorded")
+
+	startedAt := time.Now().Add(-time.Minute)
+	err = agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+		Type:      codersdk.WorkspaceSessionRecordingTypeSSH,
+		StartedAt: startedAt,
+		EndedAt:   startedAt.Add(5 * time.Second),
+		Data:      []byte(cast),
+	})
+	require.NoError(t, err)
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "sessions", "list", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "ssh")
+		require.Contains(t, buf.String(), "5s")
+	})
+
+	t.Run("ListJSON", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "sessions", "list", workspace.Name, "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+
+		var recordings []codersdk.WorkspaceSessionRecording
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &recordings))
+		require.Len(t, recordings, 1)
+		require.Equal(t, workspace.ID, recordings[0].WorkspaceID)
+		require.EqualValues(t, len(cast), recordings[0].Size)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		recordings, err := client.WorkspaceSessionRecordings(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, recordings, 1)
+
+		// The pause before "world" is limited, so this does not take five
+		// seconds.
+		inv, root := clitest.New(t, "sessions", "replay", workspace.Name, recordings[0].ID.String(), "--idle-time-limit", "10ms")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Equal(t, "hello world\r\n", buf.String())
+	})
+
+	t.Run("ReplayRaw", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		recordings, err := client.WorkspaceSessionRecordings(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, recordings, 1)
+
+		inv, root := clitest.New(t, "sessions", "replay", workspace.Name, recordings[0].ID.String(), "--raw")
+		clitest.SetupConfig(t, client, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err = inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Equal(t, cast, buf.String())
+	})
+}
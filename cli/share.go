@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+type workspaceACLRow struct {
+	Type string                 `json:"type" table:"type"`
+	Name string                 `json:"name" table:"name,default_sort"`
+	Role codersdk.WorkspaceRole `json:"role" table:"role"`
+}
+
+func (r *RootCmd) share() *clibase.Cmd {
+	var (
+		users        []string
+		groups       []string
+		removeUsers  []string
+		removeGroups []string
+		formatter    = cliui.NewOutputFormatter(
+			cliui.TableFormat([]workspaceACLRow{}, []string{"type", "name", "role"}),
+			cliui.JSONFormat(),
+		)
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "share <workspace>",
+		Short:       "Share a workspace with other users or groups",
+		Long: "Users and groups are granted one of the following roles. Without any flags, " +
+			"the users and groups the workspace is shared with are listed.\n" +
+			"  use: connect over SSH, open apps, and start or stop the workspace\n" +
+			"  ssh: connect over SSH and use the web terminal\n" +
+			"  app: open workspace apps\n\n" + formatExamples(
+			example{
+				Description: "Let a teammate pair with you over SSH",
+				Command:     "coder share my-workspace --user alice:ssh",
+			},
+			example{
+				Description: "Let a group use the workspace, and stop sharing it with a user",
+				Command:     "coder share my-workspace --group frontend:use --remove-user alice",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			req := codersdk.UpdateWorkspaceACL{
+				UserRoles:  map[string]codersdk.WorkspaceRole{},
+				GroupRoles: map[string]codersdk.WorkspaceRole{},
+			}
+			for _, user := range users {
+				name, role, err := parseWorkspaceShare(user)
+				if err != nil {
+					return xerrors.Errorf("parse --user: %w", err)
+				}
+				req.UserRoles[name] = role
+			}
+			for _, group := range groups {
+				name, role, err := parseWorkspaceShare(group)
+				if err != nil {
+					return xerrors.Errorf("parse --group: %w", err)
+				}
+				req.GroupRoles[name] = role
+			}
+			for _, user := range removeUsers {
+				req.UserRoles[user] = codersdk.WorkspaceRoleDeleted
+			}
+			for _, group := range removeGroups {
+				req.GroupRoles[group] = codersdk.WorkspaceRoleDeleted
+			}
+
+			if len(req.UserRoles) > 0 || len(req.GroupRoles) > 0 {
+				err = client.UpdateWorkspaceACL(ctx, workspace.ID, req)
+				if err != nil {
+					return xerrors.Errorf("update workspace ACL: %w", err)
+				}
+			}
+
+			acl, err := client.WorkspaceACL(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get workspace ACL: %w", err)
+			}
+			rows := make([]workspaceACLRow, 0, len(acl.Users)+len(acl.Groups))
+			for _, user := range acl.Users {
+				rows = append(rows, workspaceACLRow{Type: "user", Name: user.Username, Role: user.Role})
+			}
+			for _, group := range acl.Groups {
+				rows = append(rows, workspaceACLRow{Type: "group", Name: group.Name, Role: group.Role})
+			}
+			if len(rows) == 0 {
+				_, _ = fmt.Fprintf(inv.Stderr, "Workspace %q is not shared with anyone.\n", workspace.Name)
+				return nil
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "user",
+			Description: "Share the workspace with a user, as <username>:<role>. The role defaults to \"use\".",
+			Value:       clibase.StringArrayOf(&users),
+		},
+		{
+			Flag:        "group",
+			Description: "Share the workspace with a group, as <group>:<role>. The role defaults to \"use\".",
+			Value:       clibase.StringArrayOf(&groups),
+		},
+		{
+			Flag:        "remove-user",
+			Description: "Stop sharing the workspace with a user.",
+			Value:       clibase.StringArrayOf(&removeUsers),
+		},
+		{
+			Flag:        "remove-group",
+			Description: "Stop sharing the workspace with a group.",
+			Value:       clibase.StringArrayOf(&removeGroups),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+// parseWorkspaceShare parses a <name>:<role> pair from the share command.
+func parseWorkspaceShare(s string) (string, codersdk.WorkspaceRole, error) {
+	name, role, ok := strings.Cut(s, ":")
+	if !ok {
+		return name, codersdk.WorkspaceRoleUse, nil
+	}
+	switch codersdk.WorkspaceRole(role) {
+	case codersdk.WorkspaceRoleUse, codersdk.WorkspaceRoleSSH, codersdk.WorkspaceRoleApp:
+		return name, codersdk.WorkspaceRole(role), nil
+	default:
+		return "", "", xerrors.Errorf("invalid role %q in %q, must be one of %q, %q or %q",
+			role, s, codersdk.WorkspaceRoleUse, codersdk.WorkspaceRoleSSH, codersdk.WorkspaceRoleApp)
+	}
+}
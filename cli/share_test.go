@@ -0,0 +1,79 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestShare(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AddRemove", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, sharee := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		inv, root := clitest.New(t, "share", workspace.Name, "--user", sharee.Username+":ssh", "-o", "json")
+		clitest.SetupConfig(t, member, root)
+		buf := new(bytes.Buffer)
+		inv.Stdout = buf
+		err := inv.Run()
+		require.NoError(t, err)
+
+		var rows []map[string]string
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+		require.Equal(t, []map[string]string{{
+			"type": "user",
+			"name": sharee.Username,
+			"role": string(codersdk.WorkspaceRoleSSH),
+		}}, rows)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		acl, err := client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, sharee.ID, acl.Users[0].ID)
+
+		inv, root = clitest.New(t, "share", workspace.Name, "--remove-user", sharee.Username)
+		clitest.SetupConfig(t, member, root)
+		errBuf := new(bytes.Buffer)
+		inv.Stderr = errBuf
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, errBuf.String(), "not shared with anyone")
+
+		acl, err = client.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, acl.Users)
+	})
+
+	t.Run("InvalidRole", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+
+		inv, root := clitest.New(t, "share", workspace.Name, "--user", "someone:admin")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "invalid role")
+	})
+}
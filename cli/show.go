@@ -1,6 +1,12 @@
 package cli
 
 import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/clibase"
@@ -9,6 +15,7 @@ import (
 )
 
 func (r *RootCmd) show() *clibase.Cmd {
+	var timings bool
 	client := new(codersdk.Client)
 	return &clibase.Cmd{
 		Use:   "show <workspace>",
@@ -17,6 +24,13 @@ func (r *RootCmd) show() *clibase.Cmd {
 			clibase.RequireNArgs(1),
 			r.InitClient(client),
 		),
+		Options: clibase.OptionSet{
+			{
+				Flag:        "timings",
+				Description: "Also display a timeline of the latest build, from provisioning each resource to the agents running their startup scripts.",
+				Value:       clibase.BoolOf(&timings),
+			},
+		},
 		Handler: func(inv *clibase.Invocation) error {
 			buildInfo, err := client.BuildInfo(inv.Context())
 			if err != nil {
@@ -26,10 +40,65 @@ func (r *RootCmd) show() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("get workspace: %w", err)
 			}
-			return cliui.WorkspaceResources(inv.Stdout, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
+			err = cliui.WorkspaceResources(inv.Stdout, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
 				WorkspaceName: workspace.Name,
 				ServerVersion: buildInfo.Version,
 			})
+			if err != nil || !timings {
+				return err
+			}
+			buildTimings, err := client.WorkspaceBuildTimings(inv.Context(), workspace.LatestBuild.ID)
+			if err != nil {
+				return xerrors.Errorf("get build timings: %w", err)
+			}
+			return displayBuildTimings(inv.Stdout, buildTimings)
 		},
 	}
 }
+
+type buildTimingSpan struct {
+	start, end time.Time
+	stage      string
+	name       string
+	action     string
+}
+
+// displayBuildTimings prints every span of a build in the order they started.
+// Start times are relative to the first span.
+func displayBuildTimings(out io.Writer, timings codersdk.WorkspaceBuildTimings) error {
+	var spans []buildTimingSpan
+	for _, t := range timings.ProvisionerTimings {
+		name := t.Resource
+		if name == "" {
+			name = "(whole stage)"
+		}
+		spans = append(spans, buildTimingSpan{t.StartedAt, t.EndedAt, string(t.Stage), name, t.Action})
+	}
+	for _, t := range timings.AgentConnectionTimings {
+		spans = append(spans, buildTimingSpan{t.StartedAt, t.EndedAt, "connect", t.WorkspaceAgentName, "connect"})
+	}
+	for _, t := range timings.AgentScriptTimings {
+		spans = append(spans, buildTimingSpan{t.StartedAt, t.EndedAt, string(t.Stage), t.WorkspaceAgentName + ": " + t.DisplayName, string(t.Status)})
+	}
+	if len(spans) == 0 {
+		_, err := fmt.Fprintln(out, "No timings were recorded for this build.")
+		return err
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start.Before(spans[j].start)
+	})
+	tableWriter := cliui.Table()
+	tableWriter.AppendHeader(table.Row{"Start", "Duration", "Stage", "Name", "Action"})
+	for _, span := range spans {
+		tableWriter.AppendRow(table.Row{
+			"+" + span.start.Sub(spans[0].start).Round(time.Millisecond).String(),
+			span.end.Sub(span.start).Round(time.Millisecond).String(),
+			span.stage,
+			span.name,
+			span.action,
+		})
+	}
+	_, err := fmt.Fprintln(out, tableWriter.Render())
+	return err
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/mattn/go-isatty"
 	gossh "golang.org/x/crypto/ssh"
 	gosshagent "golang.org/x/crypto/ssh/agent"
+	"golang.org/x/exp/slices"
 	"golang.org/x/term"
 	"golang.org/x/xerrors"
 	"gvisor.dev/gvisor/pkg/tcpip/adapters/gonet"
@@ -55,14 +56,21 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 		logDirPath       string
 		remoteForward    string
 		disableAutostart bool
+		observe          bool
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Annotations: workspaceCommand,
 		Use:         "ssh <workspace>",
 		Short:       "Start a shell into a workspace",
+		Long: formatExamples(
+			example{
+				Description: "Watch a web terminal session without typing into it",
+				Command:     "coder ssh --observe my-workspace 1b9f0c4e-4b7a-4f4f-9e59-0b7f3c2d8e11",
+			},
+		),
 		Middleware: clibase.Chain(
-			clibase.RequireNArgs(1),
+			clibase.RequireRangeArgs(1, 2),
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) (retErr error) {
@@ -132,6 +140,13 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				// log HTTP requests
 				client.SetLogger(logger)
 			}
+			if observe {
+				return observeReconnectingPTY(ctx, inv, client, inv.Args)
+			}
+			if len(inv.Args) > 1 {
+				return xerrors.New("a session ID can only be given with --observe")
+			}
+
 			stack := newCloserStack(ctx, logger)
 			defer stack.close(nil)
 
@@ -462,10 +477,113 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			Value:         clibase.StringOf(&remoteForward),
 		},
 		sshDisableAutostartOption(clibase.BoolOf(&disableAutostart)),
+		{
+			Flag:        "observe",
+			Env:         "CODER_SSH_OBSERVE",
+			Description: "Watch a running web terminal session, given its ID after the workspace, without typing into it. Lists the running sessions if no ID is given.",
+			Value:       clibase.BoolOf(&observe),
+		},
 	}
 	return cmd
 }
 
+// reconnectingPTYRow is a running reconnecting PTY in the table format.
+type reconnectingPTYRow struct {
+	ID          string    `table:"id"`
+	Command     string    `table:"command"`
+	CreatedAt   time.Time `table:"created at,default_sort"`
+	Connections int       `table:"connections"`
+	Observers   int       `table:"observers"`
+}
+
+// observeReconnectingPTY writes the output of a running reconnecting PTY, such
+// as a web terminal, until it exits or the command is interrupted.  Anything
+// typed is ignored.
+func observeReconnectingPTY(ctx context.Context, inv *clibase.Invocation, client *codersdk.Client, args []string) error {
+	_, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, false, codersdk.Me, args[0])
+	if err != nil {
+		return err
+	}
+
+	res, err := client.WorkspaceAgentReconnectingPTYs(ctx, workspaceAgent.ID)
+	if err != nil {
+		return xerrors.Errorf("list sessions: %w", err)
+	}
+	if len(args) < 2 {
+		if len(res.Sessions) == 0 {
+			cliui.Infof(inv.Stdout, "No sessions are running in %s.\n", args[0])
+			return nil
+		}
+		rows := make([]reconnectingPTYRow, 0, len(res.Sessions))
+		for _, session := range res.Sessions {
+			command := session.Command
+			if command == "" {
+				command = "(shell)"
+			}
+			rows = append(rows, reconnectingPTYRow{
+				ID:          session.ID.String(),
+				Command:     command,
+				CreatedAt:   session.CreatedAt,
+				Connections: session.Connections,
+				Observers:   session.Observers,
+			})
+		}
+		out, err := cliui.DisplayTable(rows, "", nil)
+		if err != nil {
+			return xerrors.Errorf("display sessions: %w", err)
+		}
+		_, err = fmt.Fprintln(inv.Stdout, out)
+		return err
+	}
+
+	id, err := uuid.Parse(args[1])
+	if err != nil {
+		return xerrors.Errorf("parse session ID: %w", err)
+	}
+	// The agent closes the connection without an error if the session is not
+	// running, so check first.
+	if !slices.ContainsFunc(res.Sessions, func(session codersdk.WorkspaceAgentReconnectingPTY) bool {
+		return session.ID == id
+	}) {
+		return xerrors.Errorf("session %s is not running in %s", id, args[0])
+	}
+	// The size is only used when recording the session, since observers do
+	// not resize it.
+	width, height := 80, 24
+	if stdoutFile, ok := inv.Stdout.(*os.File); ok && isatty.IsTerminal(stdoutFile.Fd()) {
+		if w, h, err := term.GetSize(int(stdoutFile.Fd())); err == nil {
+			width, height = w, h
+		}
+	}
+	conn, err := client.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+		AgentID:   workspaceAgent.ID,
+		Reconnect: id,
+		Width:     uint16(width),
+		Height:    uint16(height),
+		Mode:      codersdk.ReconnectingPTYModeObserver,
+	})
+	if err != nil {
+		return xerrors.Errorf("observe session: %w", err)
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	_, _ = fmt.Fprintf(inv.Stderr, "Observing session %s. Press Ctrl+C to stop.\n", id)
+	_, err = io.Copy(inv.Stdout, conn)
+	if isTTYOut(inv) {
+		// Leave the alternate screen and show the cursor in case the
+		// session was using them.
+		_, _ = fmt.Fprint(inv.Stdout, "\x1b[?1049l\x1b[?25h\x1b[m\r\n")
+	}
+	if err != nil && ctx.Err() == nil && !xerrors.Is(err, io.EOF) {
+		return xerrors.Errorf("session ended: %w", err)
+	}
+	return nil
+}
+
 // watchAndClose ensures closer is called if the context is canceled or
 // the workspace reaches the stopped state.
 //
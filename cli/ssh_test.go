@@ -6,6 +6,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -795,6 +796,60 @@ func TestSSH(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, ents, 1, "expected one file in logdir %s", logDir)
 	})
+
+	t.Run("Observe", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("ConPTY appears to be inconsistent on Windows.")
+		}
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		id := uuid.New()
+		ptyConn, err := client.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+			AgentID:   resources[0].Agents[0].ID,
+			Reconnect: id,
+			Width:     80,
+			Height:    24,
+			Command:   "sh",
+		})
+		require.NoError(t, err)
+		defer ptyConn.Close()
+
+		// Without an ID the running sessions are listed.
+		inv, root := clitest.New(t, "ssh", "--observe", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		require.NoError(t, inv.WithContext(ctx).Run())
+		require.Contains(t, out.String(), id.String())
+
+		inv, root = clitest.New(t, "ssh", "--observe", workspace.Name, id.String())
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+		w := clitest.StartWithWaiter(t, inv.WithContext(ctx))
+		pty.ExpectMatch("Observing session")
+
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo observed-$((1+1))\r",
+		})
+		require.NoError(t, err)
+		_, err = ptyConn.Write(data)
+		require.NoError(t, err)
+		pty.ExpectMatch("observed-2")
+
+		// The observer stops when the session ends.
+		data, err = json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "exit\r",
+		})
+		require.NoError(t, err)
+		_, err = ptyConn.Write(data)
+		require.NoError(t, err)
+		w.RequireSuccess()
+	})
 }
 
 //nolint:paralleltest // This test uses t.Setenv, parent test MUST NOT be parallel.
@@ -62,7 +62,10 @@ func (r *RootCmd) start() *clibase.Cmd {
 	return cmd
 }
 
-func buildWorkspaceStartRequest(inv *clibase.Invocation, client *codersdk.Client, workspace codersdk.Workspace, parameterFlags workspaceParameterFlags, action WorkspaceCLIAction) (codersdk.CreateWorkspaceBuildRequest, error) {
+// buildWorkspaceStartRequest resolves the parameters of the build and plans
+// it. If previewChanges is set, it is called with the changes the build would
+// make to the workspace, and can abort the build by returning an error.
+func buildWorkspaceStartRequest(inv *clibase.Invocation, client *codersdk.Client, workspace codersdk.Workspace, parameterFlags workspaceParameterFlags, action WorkspaceCLIAction, previewChanges func([]codersdk.ResourceChange) error) (codersdk.CreateWorkspaceBuildRequest, error) {
 	version := workspace.LatestBuild.TemplateVersionID
 	if workspace.AutomaticUpdates == codersdk.AutomaticUpdatesAlways || action == WorkspaceUpdate {
 		version = workspace.TemplateActiveVersionID
@@ -97,6 +100,9 @@ func buildWorkspaceStartRequest(inv *clibase.Invocation, client *codersdk.Client
 		PromptRichParameters: parameterFlags.promptRichParameters,
 		RichParameters:       cliRichParameters,
 		RichParameterFile:    parameterFlags.richParameterFile,
+
+		WorkspaceID:    workspace.ID,
+		PreviewChanges: previewChanges,
 	})
 	if err != nil {
 		return codersdk.CreateWorkspaceBuildRequest{}, err
@@ -110,7 +116,7 @@ func buildWorkspaceStartRequest(inv *clibase.Invocation, client *codersdk.Client
 }
 
 func startWorkspace(inv *clibase.Invocation, client *codersdk.Client, workspace codersdk.Workspace, parameterFlags workspaceParameterFlags, action WorkspaceCLIAction) (codersdk.WorkspaceBuild, error) {
-	req, err := buildWorkspaceStartRequest(inv, client, workspace, parameterFlags, action)
+	req, err := buildWorkspaceStartRequest(inv, client, workspace, parameterFlags, action, nil)
 	if err != nil {
 		return codersdk.WorkspaceBuild{}, err
 	}
@@ -7,7 +7,6 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
@@ -286,10 +285,9 @@ func TestTemplateCreate(t *testing.T) {
 
 		// We expect the cli to return an error, so we have to handle it
 		// ourselves.
+		errC := make(chan error)
 		go func() {
-			cancel()
-			err := inv.Run()
-			assert.Error(t, err)
+			errC <- inv.Run()
 		}()
 
 		matches := []struct {
@@ -305,7 +303,7 @@ func TestTemplateCreate(t *testing.T) {
 			}
 		}
 
-		<-ctx.Done()
+		require.Error(t, <-errC)
 	})
 
 	t.Run("WithVariablesFileWithTheRequiredValue", func(t *testing.T) {
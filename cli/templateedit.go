@@ -34,6 +34,7 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 		allowUserAutostart             bool
 		allowUserAutostop              bool
 		requireActiveVersion           bool
+		recordSessions                 bool
 		deprecationMessage             string
 	)
 	client := new(codersdk.Client)
@@ -144,6 +145,10 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				displayName = template.DisplayName
 			}
 
+			if !userSetOption(inv, "record-sessions") {
+				recordSessions = template.RecordSessions
+			}
+
 			var deprecated *string
 			if !userSetOption(inv, "deprecated") {
 				deprecated = &deprecationMessage
@@ -171,6 +176,7 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				AllowUserAutostop:              allowUserAutostop,
 				RequireActiveVersion:           requireActiveVersion,
 				DeprecationMessage:             deprecated,
+				RecordSessions:                 recordSessions,
 			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
@@ -301,6 +307,11 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			Value:       clibase.BoolOf(&requireActiveVersion),
 			Default:     "false",
 		},
+		{
+			Flag:        "record-sessions",
+			Description: "Record terminal sessions in workspaces created from this template. Recordings can be replayed with \"coder sessions replay\".",
+			Value:       clibase.BoolOf(&recordSessions),
+		},
 		cliui.SkipPromptOption(),
 	}
 
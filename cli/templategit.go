@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+)
+
+func (r *RootCmd) templateGit() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "git",
+		Short: "Create template versions automatically from a git branch",
+		Long: "A template can track a branch of a git repository. Pushes to the branch create new template " +
+			"versions, either when the repository delivers a webhook or when the branch is polled.\n" + formatExamples(
+			example{
+				Description: "Track the main branch, promoting versions that import successfully",
+				Command:     "coder templates git set my-template --repository https://github.com/acme/templates.git --branch main --subdirectory docker --auto-promote",
+			},
+			example{
+				Description: "Show the webhook URL and the outcome of the last sync",
+				Command:     "coder templates git show my-template",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.templateGitSet(),
+			r.templateGitShow(),
+			r.templateGitSync(),
+			r.templateGitUnset(),
+		},
+	}
+	return cmd
+}
+
+func templateGitSourceFormatter() *cliui.OutputFormatter {
+	return cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+			source, ok := data.(codersdk.TemplateGitSource)
+			if !ok {
+				return nil, xerrors.Errorf("expected codersdk.TemplateGitSource, got %T", data)
+			}
+			return formatTemplateGitSource(source), nil
+		}),
+		cliui.JSONFormat(),
+	)
+}
+
+func (r *RootCmd) templateGitShow() *clibase.Cmd {
+	formatter := templateGitSourceFormatter()
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "show <template>",
+		Short: "Show the git source of a template",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			template, err := templateByArg(inv, client)
+			if err != nil {
+				return err
+			}
+			source, err := client.TemplateGitSource(inv.Context(), template.ID)
+			if err != nil {
+				return xerrors.Errorf("get template git source: %w", err)
+			}
+			out, err := formatter.Format(inv.Context(), source)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) templateGitSet() *clibase.Cmd {
+	var (
+		repository       string
+		branch           string
+		subdirectory     string
+		autoPromote      bool
+		pollInterval     time.Duration
+		regenerateSecret bool
+		formatter        = templateGitSourceFormatter()
+		client           = new(codersdk.Client)
+	)
+	cmd := &clibase.Cmd{
+		Use:   "set <template>",
+		Short: "Track a git branch, replacing any existing git source",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: clibase.OptionSet{
+			{
+				Flag:        "repository",
+				Description: "URL of the repository to clone. Both HTTPS and SSH URLs are supported.",
+				Value:       clibase.StringOf(&repository),
+			},
+			{
+				Flag:        "branch",
+				Description: "Branch to track.",
+				Default:     "main",
+				Value:       clibase.StringOf(&branch),
+			},
+			{
+				Flag:        "subdirectory",
+				Description: "Directory within the repository that contains the template.",
+				Value:       clibase.StringOf(&subdirectory),
+			},
+			{
+				Flag:        "auto-promote",
+				Description: "Make new versions active once they import successfully.",
+				Value:       clibase.BoolOf(&autoPromote),
+			},
+			{
+				Flag:        "poll-interval",
+				Description: "How often to check the branch for new commits. Set to 0 to rely on webhooks only.",
+				Default:     "0",
+				Value:       clibase.DurationOf(&pollInterval),
+			},
+			{
+				Flag:        "regenerate-webhook-secret",
+				Description: "Replace the webhook secret. Webhooks configured with the old secret stop working.",
+				Value:       clibase.BoolOf(&regenerateSecret),
+			},
+		},
+		Handler: func(inv *clibase.Invocation) error {
+			if repository == "" {
+				return xerrors.New("--repository is required")
+			}
+			template, err := templateByArg(inv, client)
+			if err != nil {
+				return err
+			}
+			source, err := client.UpdateTemplateGitSource(inv.Context(), template.ID, codersdk.UpdateTemplateGitSourceRequest{
+				RepositoryURL:           repository,
+				Branch:                  branch,
+				Subdirectory:            subdirectory,
+				AutoPromote:             autoPromote,
+				PollIntervalMillis:      pollInterval.Milliseconds(),
+				RegenerateWebhookSecret: regenerateSecret,
+			})
+			if err != nil {
+				return xerrors.Errorf("update template git source: %w", err)
+			}
+			out, err := formatter.Format(inv.Context(), source)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) templateGitSync() *clibase.Cmd {
+	formatter := templateGitSourceFormatter()
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "sync <template>",
+		Short: "Check the tracked branch now, creating a template version if it has new commits",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			template, err := templateByArg(inv, client)
+			if err != nil {
+				return err
+			}
+			source, err := client.SyncTemplateGitSource(inv.Context(), template.ID)
+			if err != nil {
+				return xerrors.Errorf("sync template git source: %w", err)
+			}
+			out, err := formatter.Format(inv.Context(), source)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			if source.LastError != "" {
+				return xerrors.Errorf("sync failed: %s", source.LastError)
+			}
+			return nil
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) templateGitUnset() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "unset <template>",
+		Short: "Stop tracking a git branch. Existing template versions are kept.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			template, err := templateByArg(inv, client)
+			if err != nil {
+				return err
+			}
+			err = client.DeleteTemplateGitSource(inv.Context(), template.ID)
+			if err != nil {
+				return xerrors.Errorf("delete template git source: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Template %s no longer tracks a git branch.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func templateByArg(inv *clibase.Invocation, client *codersdk.Client) (codersdk.Template, error) {
+	organization, err := CurrentOrganization(inv, client)
+	if err != nil {
+		return codersdk.Template{}, xerrors.Errorf("get current organization: %w", err)
+	}
+	template, err := client.TemplateByName(inv.Context(), organization.ID, inv.Args[0])
+	if err != nil {
+		return codersdk.Template{}, xerrors.Errorf("get template by name: %w", err)
+	}
+	return template, nil
+}
+
+func formatTemplateGitSource(source codersdk.TemplateGitSource) string {
+	var sb strings.Builder
+	line := func(key, value string) {
+		_, _ = fmt.Fprintf(&sb, "%-15s %s\n", key+":", value)
+	}
+	line("Repository", source.RepositoryURL)
+	line("Branch", source.Branch)
+	if source.Subdirectory != "" {
+		line("Subdirectory", source.Subdirectory)
+	}
+	line("Auto-promote", fmt.Sprint(source.AutoPromote))
+	if source.PollIntervalMillis > 0 {
+		line("Poll interval", (time.Duration(source.PollIntervalMillis) * time.Millisecond).String())
+	} else {
+		line("Poll interval", "disabled")
+	}
+	line("Webhook URL", source.WebhookURL)
+	line("Webhook secret", source.WebhookSecret)
+	if source.LastCheckedAt != nil {
+		line("Last checked", cliui.Timestamp(*source.LastCheckedAt))
+	} else {
+		line("Last checked", "never")
+	}
+	if source.LastCommitSHA != "" {
+		line("Last commit", source.LastCommitSHA)
+	}
+	if source.LastError != "" {
+		line("Last error", pretty.Sprint(cliui.DefaultStyles.Error, source.LastError))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
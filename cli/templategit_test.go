@@ -0,0 +1,61 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateGit(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	inv, root := clitest.New(t, "templates", "git", "set", template.Name,
+		"--repository", "https://github.com/coder/coder.git",
+		"--subdirectory", "examples/templates/docker",
+		"--auto-promote",
+		"--poll-interval", "5m",
+	)
+	clitest.SetupConfig(t, templateAdmin, root)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	clitest.Run(t, inv)
+	require.Contains(t, stdout.String(), "examples/templates/docker")
+	require.Contains(t, stdout.String(), "/git/webhook")
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	source, err := client.TemplateGitSource(ctx, template.ID)
+	require.NoError(t, err)
+	require.Equal(t, "main", source.Branch)
+	require.True(t, source.AutoPromote)
+	require.Equal(t, (5 * time.Minute).Milliseconds(), source.PollIntervalMillis)
+
+	inv, root = clitest.New(t, "templates", "git", "show", template.Name, "-o", "json")
+	clitest.SetupConfig(t, templateAdmin, root)
+	stdout.Reset()
+	inv.Stdout = &stdout
+	clitest.Run(t, inv)
+	var shown codersdk.TemplateGitSource
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &shown))
+	require.Equal(t, source.WebhookSecret, shown.WebhookSecret)
+
+	inv, root = clitest.New(t, "templates", "git", "unset", template.Name)
+	clitest.SetupConfig(t, templateAdmin, root)
+	clitest.Run(t, inv)
+	_, err = client.TemplateGitSource(ctx, template.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/provisioner/terraform"
+	"github.com/coder/pretty"
+)
+
+func (*RootCmd) templateLint() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+			diags, ok := data.([]*tfjson.Diagnostic)
+			if !ok {
+				return nil, xerrors.Errorf("expected []*tfjson.Diagnostic, got %T", data)
+			}
+			return formatLintDiagnostics(diags), nil
+		}),
+		cliui.JSONFormat(),
+	)
+
+	cmd := &clibase.Cmd{
+		Use:   "lint [directory]",
+		Short: "Check a template for common mistakes without contacting a Coder deployment.",
+		Long: "Statically checks the Terraform files in a directory for problems that Terraform accepts but that " +
+			"break workspaces, such as apps referring to undeclared agents, duplicate app slugs, parameter defaults " +
+			"that fail their own validation, and workspace resources that keep running when the workspace stops. " +
+			"Exits with a non-zero status if any errors are found.\n" + formatExamples(
+			example{
+				Description: "Lint the template in the current directory",
+				Command:     "coder templates lint",
+			},
+			example{
+				Description: "Lint a template and print the diagnostics as JSON",
+				Command:     "coder templates lint ./my-template -o json",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(0, 1),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			dir := "."
+			if len(inv.Args) > 0 {
+				dir = inv.Args[0]
+			}
+
+			diags, err := terraform.Lint(dir)
+			if err != nil {
+				return xerrors.Errorf("lint template: %w", err)
+			}
+
+			out, err := formatter.Format(inv.Context(), diags)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+
+			errors := 0
+			for _, diag := range diags {
+				if diag.Severity == tfjson.DiagnosticSeverityError {
+					errors++
+				}
+			}
+			if errors > 0 {
+				return xerrors.Errorf("template has %d error(s)", errors)
+			}
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func formatLintDiagnostics(diags []*tfjson.Diagnostic) string {
+	if len(diags) == 0 {
+		return "No problems found."
+	}
+
+	var sb strings.Builder
+	for i, diag := range diags {
+		if i > 0 {
+			_, _ = sb.WriteString("\n\n")
+		}
+		switch diag.Severity {
+		case tfjson.DiagnosticSeverityWarning:
+			_, _ = sb.WriteString(pretty.Sprint(cliui.DefaultStyles.Warn, "Warning: "+diag.Summary))
+		default:
+			_, _ = sb.WriteString(pretty.Sprint(cliui.DefaultStyles.Error, "Error: "+diag.Summary))
+		}
+		_, _ = sb.WriteString("\n\n")
+		_, _ = sb.WriteString(terraform.FormatDiagnostic(diag))
+	}
+	return sb.String()
+}
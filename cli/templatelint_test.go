@@ -0,0 +1,73 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+)
+
+func TestTemplateLint(t *testing.T) {
+	t.Parallel()
+
+	const invalid = `
+resource "coder_agent" "main" {}
+
+resource "coder_app" "code" {
+  agent_id = coder_agent.dev.id
+  slug     = "code"
+}
+`
+
+	t.Run("Clean", func(t *testing.T) {
+		t.Parallel()
+
+		inv, _ := clitest.New(t, "templates", "lint", filepath.Join("..", "examples", "templates", "docker"))
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.Run(t, inv)
+		require.Contains(t, stdout.String(), "No problems found.")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(invalid), 0o600)
+		require.NoError(t, err)
+
+		inv, _ := clitest.New(t, "templates", "lint", dir)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err = inv.Run()
+		require.ErrorContains(t, err, "template has 1 error(s)")
+		require.Contains(t, stdout.String(), "Reference to undeclared agent")
+		require.Contains(t, stdout.String(), `on main.tf line 5, in resource "coder_app" "code":`)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(invalid), 0o600)
+		require.NoError(t, err)
+
+		inv, _ := clitest.New(t, "templates", "lint", dir, "-o", "json")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err = inv.Run()
+		require.Error(t, err)
+
+		var diags []*tfjson.Diagnostic
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &diags))
+		require.Len(t, diags, 1)
+		require.Equal(t, tfjson.DiagnosticSeverityError, diags[0].Severity)
+		require.Equal(t, "main.tf", diags[0].Range.Filename)
+	})
+}
@@ -37,7 +37,9 @@ func (r *RootCmd) templates() *clibase.Cmd {
 		Children: []*clibase.Cmd{
 			r.templateCreate(),
 			r.templateEdit(),
+			r.templateGit(),
 			r.templateInit(),
+			r.templateLint(),
 			r.templateList(),
 			r.templatePush(),
 			r.templateVersions(),
@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
@@ -22,6 +23,10 @@ func (r *RootCmd) tokens() *clibase.Cmd {
 				Description: "Create a token for automation",
 				Command:     "coder tokens create",
 			},
+			example{
+				Description: "Create a token that can only read workspaces",
+				Command:     "coder tokens create --permission workspace:read",
+			},
 			example{
 				Description: "List your tokens",
 				Command:     "coder tokens ls",
@@ -48,6 +53,8 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 	var (
 		tokenLifetime time.Duration
 		name          string
+		permissions   []string
+		allowList     []string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -58,10 +65,29 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			r.InitClient(client),
 		),
 		Handler: func(inv *clibase.Invocation) error {
-			res, err := client.CreateToken(inv.Context(), codersdk.Me, codersdk.CreateTokenRequest{
+			req := codersdk.CreateTokenRequest{
 				Lifetime:  tokenLifetime,
 				TokenName: name,
-			})
+			}
+			for _, permission := range permissions {
+				perm, err := codersdk.ParsePermission(permission)
+				if err != nil {
+					return xerrors.Errorf("parse permission: %w", err)
+				}
+				req.ScopePermissions = append(req.ScopePermissions, perm)
+			}
+			for _, id := range allowList {
+				parsed, err := uuid.Parse(id)
+				if err != nil {
+					return xerrors.Errorf("parse allowed resource id %q: %w", id, err)
+				}
+				req.ScopeAllowList = append(req.ScopeAllowList, parsed)
+			}
+			if len(req.ScopeAllowList) > 0 && len(req.ScopePermissions) == 0 {
+				return xerrors.New("--allow requires at least one --permission")
+			}
+
+			res, err := client.CreateToken(inv.Context(), codersdk.Me, req)
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
 			}
@@ -87,6 +113,17 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			Description:   "Specify a human-readable name.",
 			Value:         clibase.StringOf(&name),
 		},
+		{
+			Flag: "permission",
+			Description: "Restrict the token to a permission in the form \"<resource>:<action>\", " +
+				"for example \"workspace:read\". Can be repeated. By default, tokens can do everything the user can.",
+			Value: clibase.StringArrayOf(&permissions),
+		},
+		{
+			Flag:        "allow",
+			Description: "Restrict the token to a resource ID, such as a workspace or template ID. Can be repeated. Requires --permission.",
+			Value:       clibase.StringArrayOf(&allowList),
+		},
 	}
 
 	return cmd
@@ -100,6 +137,7 @@ type tokenListRow struct {
 	// For table format:
 	ID        string    `json:"-" table:"id,default_sort"`
 	TokenName string    `json:"token_name" table:"name"`
+	Scope     string    `json:"-" table:"scope"`
 	LastUsed  time.Time `json:"-" table:"last used"`
 	ExpiresAt time.Time `json:"-" table:"expires at"`
 	CreatedAt time.Time `json:"-" table:"created at"`
@@ -111,6 +149,7 @@ func tokenListRowFromToken(token codersdk.APIKeyWithOwner) tokenListRow {
 		APIKey:    token.APIKey,
 		ID:        token.ID,
 		TokenName: token.TokenName,
+		Scope:     string(token.Scope),
 		LastUsed:  token.LastUsed,
 		ExpiresAt: token.ExpiresAt,
 		CreatedAt: token.CreatedAt,
@@ -78,3 +78,37 @@ func TestTokens(t *testing.T) {
 	require.NotEmpty(t, res)
 	require.Contains(t, res, "deleted")
 }
+
+func TestTokensScopePermissions(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "tokens", "create", "--name", "reader", "--permission", "workspace:read", "--permission", "template:read")
+	clitest.SetupConfig(t, client, root)
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	inv, root = clitest.New(t, "tokens", "ls", "--output=json")
+	clitest.SetupConfig(t, client, root)
+	buf := new(bytes.Buffer)
+	inv.Stdout = buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	var tokens []codersdk.APIKey
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &tokens))
+	require.Len(t, tokens, 1)
+	require.Equal(t, codersdk.APIKeyScopeCustom, tokens[0].Scope)
+	require.Equal(t, []codersdk.Permission{
+		{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+		{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+	}, tokens[0].ScopePermissions)
+
+	inv, root = clitest.New(t, "tokens", "create", "--permission", "workspace")
+	clitest.SetupConfig(t, client, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "expected <resource>:<action>")
+}
@@ -2,26 +2,52 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"golang.org/x/xerrors"
 
+	"github.com/coder/pretty"
+
 	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/codersdk"
 )
 
+// errUpdateNotConfirmed is returned from the plan preview to stop before
+// the workspace is updated.
+var errUpdateNotConfirmed = xerrors.New("update not confirmed")
+
 func (r *RootCmd) update() *clibase.Cmd {
-	var parameterFlags workspaceParameterFlags
+	var (
+		parameterFlags workspaceParameterFlags
+		formatter      = cliui.NewOutputFormatter(
+			cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+				changes, ok := data.([]codersdk.ResourceChange)
+				if !ok {
+					return nil, xerrors.Errorf("expected type %T, got %T", changes, data)
+				}
+				return renderResourceChanges(changes), nil
+			}),
+			cliui.JSONFormat(),
+		)
+	)
 
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Annotations: workspaceCommand,
 		Use:         "update <workspace>",
 		Short:       "Will update and start a given workspace if it is out of date",
-		Long:        "Use --always-prompt to change the parameter values of the workspace.",
+		Long: "Use --always-prompt to change the parameter values of the workspace.\n\n" +
+			"Before the workspace is updated, the changes to its infrastructure are planned " +
+			"and shown for confirmation. With --output json, the planned changes are printed " +
+			"as JSON and the workspace is only updated if --yes is set.",
 		Middleware: clibase.Chain(
 			clibase.RequireNArgs(1),
 			r.InitClient(client),
 		),
+		Options: clibase.OptionSet{
+			cliui.SkipPromptOption(),
+		},
 		Handler: func(inv *clibase.Invocation) error {
 			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
@@ -32,9 +58,54 @@ func (r *RootCmd) update() *clibase.Cmd {
 				return nil
 			}
 
-			build, err := startWorkspace(inv, client, workspace, parameterFlags, WorkspaceUpdate)
+			out := inv.Stdout
+			jsonOutput := formatter.FormatID() == "json"
+			if jsonOutput {
+				// Keep stdout parseable, progress and build logs are
+				// written to stderr.
+				inv.Stdout = inv.Stderr
+			}
+
+			req, err := buildWorkspaceStartRequest(inv, client, workspace, parameterFlags, WorkspaceUpdate, func(changes []codersdk.ResourceChange) error {
+				rendered, err := formatter.Format(inv.Context(), changes)
+				if err != nil {
+					return xerrors.Errorf("format resource changes: %w", err)
+				}
+				_, _ = fmt.Fprintln(out, rendered)
+
+				if jsonOutput {
+					if yes, _ := inv.ParsedFlags().GetBool("yes"); !yes {
+						return errUpdateNotConfirmed
+					}
+					return nil
+				}
+				if len(changes) == 0 {
+					return nil
+				}
+				text := fmt.Sprintf("Update workspace %s?", pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name))
+				if volumes := destroyedVolumes(changes); len(volumes) > 0 {
+					text = fmt.Sprintf("Update workspace %s? This destroys %s and the data on it.",
+						pretty.Sprint(cliui.DefaultStyles.Keyword, workspace.Name),
+						strings.Join(volumes, ", "),
+					)
+				}
+				_, err = cliui.Prompt(inv, cliui.PromptOptions{
+					Text:      text,
+					IsConfirm: true,
+					Default:   cliui.ConfirmNo,
+				})
+				return err
+			})
+			if xerrors.Is(err, errUpdateNotConfirmed) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			build, err := client.CreateWorkspaceBuild(inv.Context(), workspace.ID, req)
 			if err != nil {
-				return xerrors.Errorf("start workspace: %w", err)
+				return xerrors.Errorf("start workspace: create workspace build: %w", err)
 			}
 
 			logs, closer, err := client.WorkspaceBuildLogsAfter(inv.Context(), build.ID, 0)
@@ -54,5 +125,77 @@ func (r *RootCmd) update() *clibase.Cmd {
 	}
 
 	cmd.Options = append(cmd.Options, parameterFlags.allOptions()...)
+	formatter.AttachOptions(&cmd.Options)
 	return cmd
 }
+
+// renderResourceChanges renders the planned changes to the resources of a
+// workspace in a format similar to `terraform plan`.
+func renderResourceChanges(changes []codersdk.ResourceChange) string {
+	if len(changes) == 0 {
+		return "No changes to the infrastructure of the workspace are planned."
+	}
+
+	var (
+		sb     strings.Builder
+		counts = map[codersdk.ResourceChangeAction]int{}
+	)
+	_, _ = sb.WriteString("Planned changes to the workspace:\n\n")
+	for _, change := range changes {
+		counts[change.Action]++
+
+		var symbol string
+		style := cliui.DefaultStyles.Keyword
+		switch change.Action {
+		case codersdk.ResourceChangeActionCreate:
+			symbol = "+"
+		case codersdk.ResourceChangeActionUpdate:
+			symbol = "~"
+		case codersdk.ResourceChangeActionReplace:
+			symbol = "-/+"
+			style = cliui.DefaultStyles.Warn
+		case codersdk.ResourceChangeActionDelete:
+			symbol = "-"
+			style = cliui.DefaultStyles.Warn
+		}
+		line := fmt.Sprintf("  %3s %s (%s)", symbol, change.Address, change.Action)
+		if destroysVolume(change) {
+			line += " " + pretty.Sprint(cliui.DefaultStyles.Error, "persistent volume will be destroyed")
+		}
+		_, _ = sb.WriteString(pretty.Sprint(style, line) + "\n")
+	}
+	_, _ = fmt.Fprintf(&sb, "\n%d to create, %d to update, %d to replace, %d to delete.",
+		counts[codersdk.ResourceChangeActionCreate],
+		counts[codersdk.ResourceChangeActionUpdate],
+		counts[codersdk.ResourceChangeActionReplace],
+		counts[codersdk.ResourceChangeActionDelete],
+	)
+	return sb.String()
+}
+
+// destroyedVolumes returns the addresses of the persistent volumes the changes
+// destroy.
+func destroyedVolumes(changes []codersdk.ResourceChange) []string {
+	var volumes []string
+	for _, change := range changes {
+		if destroysVolume(change) {
+			volumes = append(volumes, change.Address)
+		}
+	}
+	return volumes
+}
+
+// destroysVolume returns whether the change destroys a resource that likely
+// holds persistent data, like the home volume of a workspace. Terraform
+// providers don't mark such resources, so this goes by the resource type.
+func destroysVolume(change codersdk.ResourceChange) bool {
+	if change.Action != codersdk.ResourceChangeActionReplace && change.Action != codersdk.ResourceChangeActionDelete {
+		return false
+	}
+	for _, kind := range []string{"volume", "disk"} {
+		if strings.Contains(change.Type, kind) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,9 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
@@ -78,6 +80,100 @@ func TestUpdate(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, version2.ID.String(), ws.LatestBuild.TemplateVersionID.String())
 	})
+
+	// setupOutdated creates a workspace whose template has a new active
+	// version that replaces the home volume of the workspace.
+	setupOutdated := func(t *testing.T) (client, member *codersdk.Client, workspace codersdk.Workspace, version codersdk.TemplateVersion) {
+		client = coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ = coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version1 := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version1.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version1.ID)
+		workspace = coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		version = coderdtest.UpdateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						ResourceChanges: []*proto.ResourceChange{
+							{Address: "coder_app.web", Type: "coder_app", Name: "web", Action: "create"},
+							{Address: "docker_volume.home", Type: "docker_volume", Name: "home", Action: "replace"},
+						},
+					},
+				},
+			}},
+		}, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		err := client.UpdateActiveTemplateVersion(context.Background(), template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		require.NoError(t, err)
+		return client, member, workspace, version
+	}
+
+	t.Run("PlanPreview", func(t *testing.T) {
+		t.Parallel()
+
+		_, member, workspace, version := setupOutdated(t)
+
+		inv, root := clitest.New(t, "update", workspace.Name)
+		clitest.SetupConfig(t, member, root)
+		doneChan := make(chan struct{})
+		pty := ptytest.New(t).Attach(inv)
+		go func() {
+			defer close(doneChan)
+			err := inv.Run()
+			assert.NoError(t, err)
+		}()
+
+		pty.ExpectMatch("+ coder_app.web (create)")
+		pty.ExpectMatch("-/+ docker_volume.home (replace)")
+		pty.ExpectMatch("persistent volume will be destroyed")
+		pty.ExpectMatch("1 to create, 0 to update, 1 to replace, 0 to delete.")
+		pty.ExpectMatch("This destroys docker_volume.home")
+		pty.WriteLine("yes")
+		<-doneChan
+
+		workspace, err := member.Workspace(context.Background(), workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, workspace.LatestBuild.TemplateVersionID)
+	})
+
+	t.Run("PlanPreviewJSON", func(t *testing.T) {
+		t.Parallel()
+
+		_, member, workspace, version := setupOutdated(t)
+
+		inv, root := clitest.New(t, "update", workspace.Name, "--output", "json")
+		clitest.SetupConfig(t, member, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		err := inv.Run()
+		require.NoError(t, err)
+
+		var changes []codersdk.ResourceChange
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &changes))
+		require.Len(t, changes, 2)
+		require.Equal(t, codersdk.ResourceChangeActionReplace, changes[1].Action)
+
+		// Without --yes, the workspace is not updated.
+		updated, err := member.Workspace(context.Background(), workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, workspace.LatestBuild.ID, updated.LatestBuild.ID)
+
+		inv, root = clitest.New(t, "update", workspace.Name, "--output", "json", "--yes")
+		clitest.SetupConfig(t, member, root)
+		err = inv.Run()
+		require.NoError(t, err)
+
+		updated, err = member.Workspace(context.Background(), workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, updated.LatestBuild.TemplateVersionID)
+	})
 }
 
 func TestUpdateWithRichParameters(t *testing.T) {
@@ -56,8 +56,56 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	scope := database.APIKeyScopeAll
-	if scope != "" {
+	if createToken.Scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
+	} else if len(createToken.ScopePermissions) > 0 {
+		scope = database.APIKeyScopeCustom
+	}
+
+	scopePermissions := make(database.CustomRolePermissions, 0, len(createToken.ScopePermissions))
+	for i, permission := range createToken.ScopePermissions {
+		perm := rbac.Permission{
+			Negate:       permission.Negate,
+			ResourceType: string(permission.ResourceType),
+			Action:       rbac.Action(permission.Action),
+		}
+		if err := perm.Valid(); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid scope permission.",
+				Validations: []codersdk.ValidationError{{
+					Field:  fmt.Sprintf("scope_permissions[%d]", i),
+					Detail: err.Error(),
+				}},
+			})
+			return
+		}
+		scopePermissions = append(scopePermissions, perm)
+	}
+	scopeAllowList := make([]string, 0, len(createToken.ScopeAllowList))
+	for _, id := range createToken.ScopeAllowList {
+		scopeAllowList = append(scopeAllowList, id.String())
+	}
+	if scope == database.APIKeyScopeCustom && len(scopePermissions) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The %q scope requires at least one scope permission.", scope),
+		})
+		return
+	}
+	if scope != database.APIKeyScopeCustom && (len(scopePermissions) > 0 || len(scopeAllowList) > 0) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Scope permissions and allow lists require the %q scope.", database.APIKeyScopeCustom),
+		})
+		return
+	}
+
+	requested := database.APIKey{
+		UserID:           user.ID,
+		Scope:            scope,
+		ScopePermissions: scopePermissions,
+		ScopeAllowList:   scopeAllowList,
+	}
+	if !api.scopeWithinAPIKey(rw, r, requested.RBACScope()) {
+		return
 	}
 
 	// default lifetime is 30 days
@@ -89,6 +137,8 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		Scope:            scope,
 		LifetimeSeconds:  int64(lifeTime.Seconds()),
 		TokenName:        tokenName,
+		ScopePermissions: scopePermissions,
+		ScopeAllowList:   scopeAllowList,
 	})
 	if err != nil {
 		if database.IsUniqueViolation(err, database.UniqueIndexAPIKeyName) {
@@ -125,6 +175,11 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user := httpmw.UserParam(r)
 
+	// Session keys are never scoped.
+	if !api.scopeWithinAPIKey(rw, r, rbac.ScopeAll) {
+		return
+	}
+
 	lifeTime := time.Hour * 24 * 7
 	cookie, _, err := api.createAPIKey(ctx, apikey.CreateParams{
 		UserID:           user.ID,
@@ -151,6 +206,39 @@ func (api *API) postAPIKey(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.GenerateAPIKeyResponse{Key: cookie.Value})
 }
 
+// scopeWithinAPIKey ensures a key with the given scope allows nothing the key
+// authenticating the request does not. Otherwise a scoped key that may create
+// keys could create one without its restrictions. It writes an error response
+// and returns false if the scope is broader.
+func (api *API) scopeWithinAPIKey(rw http.ResponseWriter, r *http.Request, scope rbac.ExpandableScope) bool {
+	ctx := r.Context()
+	key := httpmw.APIKey(r)
+
+	parent, err := key.RBACScope().Expand()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error expanding API key scope.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	expanded, err := scope.Expand()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid scope.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if !rbac.ScopeWithin(expanded, parent) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Keys cannot be created with a broader scope than the key making the request.",
+		})
+		return false
+	}
+	return true
+}
+
 // @Summary Get API key by ID
 // @ID get-api-key-by-id
 // @Security CoderSessionToken
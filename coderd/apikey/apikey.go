@@ -27,6 +27,10 @@ type CreateParams struct {
 	Scope           database.APIKeyScope
 	TokenName       string
 	RemoteAddr      string
+	// ScopePermissions and ScopeAllowList restrict keys with the custom
+	// scope. They must be empty for any other scope.
+	ScopePermissions database.CustomRolePermissions
+	ScopeAllowList   []string
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -67,10 +71,26 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 	}
 	switch scope {
 	case database.APIKeyScopeAll, database.APIKeyScopeApplicationConnect:
+		if len(params.ScopePermissions) > 0 || len(params.ScopeAllowList) > 0 {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("scope permissions require the %q scope", database.APIKeyScopeCustom)
+		}
+	case database.APIKeyScopeCustom:
+		if len(params.ScopePermissions) == 0 {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("the %q scope requires at least one permission", scope)
+		}
 	default:
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
 
+	scopePermissions := params.ScopePermissions
+	if scopePermissions == nil {
+		scopePermissions = database.CustomRolePermissions{}
+	}
+	scopeAllowList := params.ScopeAllowList
+	if scopeAllowList == nil {
+		scopeAllowList = []string{}
+	}
+
 	token := fmt.Sprintf("%s-%s", keyID, keySecret)
 
 	return database.InsertAPIKeyParams{
@@ -86,13 +106,15 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 			Valid: true,
 		},
 		// Make sure in UTC time for common time zone
-		ExpiresAt:    params.ExpiresAt.UTC(),
-		CreatedAt:    dbtime.Now(),
-		UpdatedAt:    dbtime.Now(),
-		HashedSecret: hashed[:],
-		LoginType:    params.LoginType,
-		Scope:        scope,
-		TokenName:    params.TokenName,
+		ExpiresAt:        params.ExpiresAt.UTC(),
+		CreatedAt:        dbtime.Now(),
+		UpdatedAt:        dbtime.Now(),
+		HashedSecret:     hashed[:],
+		LoginType:        params.LoginType,
+		Scope:            scope,
+		TokenName:        params.TokenName,
+		ScopePermissions: scopePermissions,
+		ScopeAllowList:   scopeAllowList,
 	}, token, nil
 }
 
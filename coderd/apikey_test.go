@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -85,6 +86,117 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenScopePermissions(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	allowed := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	other := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "workspace-reader",
+			ScopePermissions: []codersdk.Permission{
+				// Reading a workspace also reads its owner and template.
+				{ResourceType: codersdk.ResourceWorkspace, Action: "read"},
+				{ResourceType: codersdk.ResourceUser, Action: "read"},
+				{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+			},
+			ScopeAllowList: []uuid.UUID{allowed.ID, template.ID},
+		})
+		require.NoError(t, err)
+
+		keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+		require.NoError(t, err)
+		var key codersdk.APIKey
+		for _, k := range keys {
+			if k.TokenName == "workspace-reader" {
+				key = k.APIKey
+			}
+		}
+		require.Equal(t, codersdk.APIKeyScopeCustom, key.Scope)
+		require.Len(t, key.ScopePermissions, 3)
+		require.Equal(t, []uuid.UUID{allowed.ID, template.ID}, key.ScopeAllowList)
+
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		_, err = scoped.Workspace(ctx, allowed.ID)
+		require.NoError(t, err)
+
+		// Not in the allow list.
+		_, err = scoped.Workspace(ctx, other.ID)
+		require.Error(t, err)
+
+		// Not a permission of the token.
+		_, err = scoped.CreateWorkspaceBuild(ctx, allowed.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Escalation", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "token-creator",
+			ScopePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceAPIKey, Action: "create"},
+				{ResourceType: codersdk.ResourceUser, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		// The scoped token cannot create a token without its restrictions.
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope: codersdk.APIKeyScopeAll,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Nor a session key, which is never scoped.
+		_, err = scoped.CreateAPIKey(ctx, codersdk.Me)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// But it can create a token with fewer permissions.
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			ScopePermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceUser, Action: "read"},
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, req := range []codersdk.CreateTokenRequest{
+			{ScopePermissions: []codersdk.Permission{{ResourceType: "*", Action: "*"}}},
+			{Scope: codersdk.APIKeyScopeCustom},
+			{Scope: codersdk.APIKeyScopeAll, ScopePermissions: []codersdk.Permission{{ResourceType: codersdk.ResourceWorkspace, Action: "read"}}},
+		} {
+			_, err := client.CreateToken(ctx, codersdk.Me, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
@@ -3,15 +3,18 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
 	"net/netip"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -20,7 +23,6 @@ import (
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
-	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -82,6 +84,146 @@ func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// auditLogExportPageSize is the number of audit logs fetched from the
+// database at a time while exporting.
+const auditLogExportPageSize = 1000
+
+// auditLogExportCSVHeader is the header row of CSV exports. Its order must
+// match auditLogCSVRecord.
+var auditLogExportCSVHeader = []string{
+	"id", "time", "organization_id", "user_id", "username", "email", "ip",
+	"user_agent", "action", "resource_type", "resource_id", "resource_target",
+	"status_code", "request_id", "description", "diff", "additional_fields",
+}
+
+// @Summary Export audit logs
+// @Description Streams every audit log matching the search query, newest first.
+// @ID export-audit-logs
+// @Security CoderSessionToken
+// @Produce text/csv,application/x-ndjson
+// @Tags Audit
+// @Param q query string false "Search query"
+// @Param format query string false "Export format" Enums(csv,ndjson)
+// @Success 200
+// @Router /audit/export [get]
+func (api *API) exportAuditLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	format := codersdk.AuditLogExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = codersdk.AuditLogExportFormatNDJSON
+	}
+	if !slices.Contains(codersdk.AuditLogExportFormats, format) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid export format %q.", format),
+			Detail:  fmt.Sprintf("Valid formats are %v.", codersdk.AuditLogExportFormats),
+		})
+		return
+	}
+
+	queryStr := r.URL.Query().Get("q")
+	filter, errs := searchquery.AuditLogs(queryStr)
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid audit search query.",
+			Validations: errs,
+		})
+		return
+	}
+	if filter.Username == "me" {
+		filter.UserID = apiKey.UserID
+		filter.Username = ""
+	}
+	params := database.GetAuditLogsBeforeParams{
+		ResourceType:   filter.ResourceType,
+		ResourceID:     filter.ResourceID,
+		ResourceTarget: filter.ResourceTarget,
+		Action:         filter.Action,
+		UserID:         filter.UserID,
+		Username:       filter.Username,
+		Email:          filter.Email,
+		DateFrom:       filter.DateFrom,
+		DateTo:         filter.DateTo,
+		BuildReason:    filter.BuildReason,
+		RowLimit:       auditLogExportPageSize,
+	}
+
+	// Fetch the first page before writing the header, so authorization and
+	// database errors can still be returned as a normal response.
+	dblogs, err := api.Database.GetAuditLogsBefore(ctx, params)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	var (
+		write func(codersdk.AuditLog) error
+		csvw  *csv.Writer
+	)
+	switch format {
+	case codersdk.AuditLogExportFormatCSV:
+		rw.Header().Set("Content-Type", "text/csv")
+		rw.Header().Set("Content-Disposition", `attachment; filename="audit_logs.csv"`)
+		csvw = csv.NewWriter(rw)
+		write = func(alog codersdk.AuditLog) error {
+			if err := csvw.Write(auditLogCSVRecord(alog)); err != nil {
+				return err
+			}
+			csvw.Flush()
+			return csvw.Error()
+		}
+	case codersdk.AuditLogExportFormatNDJSON:
+		rw.Header().Set("Content-Type", "application/x-ndjson")
+		rw.Header().Set("Content-Disposition", `attachment; filename="audit_logs.ndjson"`)
+		enc := json.NewEncoder(rw)
+		write = func(alog codersdk.AuditLog) error {
+			return enc.Encode(alog)
+		}
+	}
+	rw.WriteHeader(http.StatusOK)
+
+	if csvw != nil {
+		// The header row is written even if there are no audit logs.
+		if err := csvw.Write(auditLogExportCSVHeader); err != nil {
+			return
+		}
+		csvw.Flush()
+	}
+
+	flusher, _ := rw.(http.Flusher)
+	for len(dblogs) > 0 {
+		for _, dblog := range dblogs {
+			if err := write(api.convertExportedAuditLog(ctx, dblog)); err != nil {
+				// The client has most likely gone away.
+				api.Logger.Debug(ctx, "write exported audit log", slog.Error(err))
+				return
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if len(dblogs) < auditLogExportPageSize {
+			return
+		}
+
+		last := dblogs[len(dblogs)-1]
+		params.BeforeTime = last.Time
+		params.BeforeID = last.ID
+		dblogs, err = api.Database.GetAuditLogsBefore(ctx, params)
+		if err != nil {
+			// Headers have already been written, so the best we can do is
+			// log the error and truncate the response.
+			api.Logger.Error(ctx, "fetch audit logs for export", slog.Error(err))
+			return
+		}
+	}
+}
+
 // @Summary Generate fake audit log
 // @ID generate-fake-audit-log
 // @Security CoderSessionToken
@@ -177,6 +319,48 @@ func (api *API) convertAuditLogs(ctx context.Context, dblogs []database.GetAudit
 }
 
 func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogsOffsetRow) codersdk.AuditLog {
+	alog, additionalFields := api.convertAuditLogRow(ctx, dblog)
+	alog.IsDeleted = api.auditLogIsResourceDeleted(ctx, dblog)
+	if !alog.IsDeleted {
+		alog.ResourceLink = api.auditLogResourceLink(ctx, dblog, additionalFields)
+	}
+	return alog
+}
+
+// convertExportedAuditLog converts an audit log for export. Unlike
+// convertAuditLog, it does not look up whether the resource still exists,
+// since doing so for every log in a large export would be prohibitively
+// expensive. IsDeleted and ResourceLink are therefore always empty.
+func (api *API) convertExportedAuditLog(ctx context.Context, dblog database.GetAuditLogsBeforeRow) codersdk.AuditLog {
+	alog, _ := api.convertAuditLogRow(ctx, database.GetAuditLogsOffsetRow{
+		ID:               dblog.ID,
+		Time:             dblog.Time,
+		UserID:           dblog.UserID,
+		OrganizationID:   dblog.OrganizationID,
+		Ip:               dblog.Ip,
+		UserAgent:        dblog.UserAgent,
+		ResourceType:     dblog.ResourceType,
+		ResourceID:       dblog.ResourceID,
+		ResourceTarget:   dblog.ResourceTarget,
+		Action:           dblog.Action,
+		Diff:             dblog.Diff,
+		StatusCode:       dblog.StatusCode,
+		AdditionalFields: dblog.AdditionalFields,
+		RequestID:        dblog.RequestID,
+		ResourceIcon:     dblog.ResourceIcon,
+		UserUsername:     dblog.UserUsername,
+		UserEmail:        dblog.UserEmail,
+		UserCreatedAt:    dblog.UserCreatedAt,
+		UserStatus:       dblog.UserStatus,
+		UserRoles:        dblog.UserRoles,
+		UserAvatarUrl:    dblog.UserAvatarUrl,
+	})
+	return alog
+}
+
+// convertAuditLogRow converts the fields of an audit log which don't require
+// any further queries.
+func (api *API) convertAuditLogRow(ctx context.Context, dblog database.GetAuditLogsOffsetRow) (codersdk.AuditLog, audit.AdditionalFields) {
 	ip, _ := netip.AddrFromSlice(dblog.Ip.IPNet.IP)
 
 	diff := codersdk.AuditDiff{}
@@ -196,8 +380,7 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 		}
 
 		for _, roleName := range dblog.UserRoles {
-			rbacRole, _ := rbac.RoleByName(roleName)
-			user.Roles = append(user.Roles, db2sdk.Role(rbacRole))
+			user.Roles = append(user.Roles, db2sdk.RoleByName(roleName))
 		}
 	}
 
@@ -219,16 +402,6 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 		api.Logger.Error(ctx, "marshal additional fields", slog.Error(err))
 	}
 
-	var (
-		isDeleted    = api.auditLogIsResourceDeleted(ctx, dblog)
-		resourceLink string
-	)
-	if isDeleted {
-		resourceLink = ""
-	} else {
-		resourceLink = api.auditLogResourceLink(ctx, dblog, additionalFields)
-	}
-
 	return codersdk.AuditLog{
 		ID:               dblog.ID,
 		RequestID:        dblog.RequestID,
@@ -246,8 +419,41 @@ func (api *API) convertAuditLog(ctx context.Context, dblog database.GetAuditLogs
 		AdditionalFields: dblog.AdditionalFields,
 		User:             user,
 		Description:      auditLogDescription(dblog),
-		ResourceLink:     resourceLink,
-		IsDeleted:        isDeleted,
+	}, additionalFields
+}
+
+// auditLogCSVRecord returns the CSV row for an exported audit log. Nested
+// values are encoded as JSON.
+func auditLogCSVRecord(alog codersdk.AuditLog) []string {
+	var userID, username, email string
+	if alog.User != nil {
+		userID = alog.User.ID.String()
+		username = alog.User.Username
+		email = alog.User.Email
+	}
+	ip := ""
+	if alog.IP.IsValid() {
+		ip = alog.IP.String()
+	}
+	diff, _ := json.Marshal(alog.Diff)
+	return []string{
+		alog.ID.String(),
+		alog.Time.Format(time.RFC3339Nano),
+		alog.OrganizationID.String(),
+		userID,
+		username,
+		email,
+		ip,
+		alog.UserAgent,
+		string(alog.Action),
+		string(alog.ResourceType),
+		alog.ResourceID.String(),
+		alog.ResourceTarget,
+		strconv.Itoa(int(alog.StatusCode)),
+		alog.RequestID.String(),
+		alog.Description,
+		string(diff),
+		string(alog.AdditionalFields),
 	}
 }
 
@@ -19,7 +19,8 @@ type Auditable interface {
 		database.License |
 		database.WorkspaceProxy |
 		database.AuditOAuthConvertState |
-		database.HealthSettings
+		database.HealthSettings |
+		database.OIDCSyncRules
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
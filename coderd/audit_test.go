@@ -2,18 +2,24 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestAuditLogs(t *testing.T) {
@@ -257,3 +263,121 @@ func TestAuditLogsFilter(t *testing.T) {
 		}
 	})
 }
+
+func TestExportAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NDJSON", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		// Create more logs than fit on a single page, many of which share
+		// the same time, to ensure none are skipped or repeated.
+		const count = 2500
+		now := dbtime.Now()
+		for i := 0; i < count; i++ {
+			_ = dbgen.AuditLog(t, db, database.AuditLog{
+				UserID: user.UserID,
+				Time:   now.Add(-time.Duration(i/10) * time.Second),
+			})
+		}
+
+		body, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			Format: codersdk.AuditLogExportFormatNDJSON,
+		})
+		require.NoError(t, err)
+		defer body.Close()
+
+		seen := map[uuid.UUID]struct{}{}
+		var last time.Time
+		dec := json.NewDecoder(body)
+		for dec.More() {
+			var alog codersdk.AuditLog
+			require.NoError(t, dec.Decode(&alog))
+			require.NotContains(t, seen, alog.ID)
+			seen[alog.ID] = struct{}{}
+			if !last.IsZero() {
+				require.False(t, alog.Time.After(last), "logs must be ordered newest first")
+			}
+			last = alog.Time
+			require.NotNil(t, alog.User)
+			require.Equal(t, user.UserID, alog.User.ID)
+		}
+		// The first user's creation is also audited.
+		require.GreaterOrEqual(t, len(seen), count)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+			Action:       codersdk.AuditActionDelete,
+			ResourceType: codersdk.ResourceTypeWorkspace,
+			ResourceID:   user.UserID,
+		})
+		require.NoError(t, err)
+		err = client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+			Action:       codersdk.AuditActionCreate,
+			ResourceType: codersdk.ResourceTypeTemplate,
+			ResourceID:   user.UserID,
+		})
+		require.NoError(t, err)
+
+		body, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			SearchQuery: "action:delete",
+			Format:      codersdk.AuditLogExportFormatCSV,
+		})
+		require.NoError(t, err)
+		defer body.Close()
+
+		records, err := csv.NewReader(body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		require.Equal(t, "id", records[0][0])
+		row := map[string]string{}
+		for i, col := range records[0] {
+			row[col] = records[1][i]
+		}
+		require.Equal(t, "delete", row["action"])
+		require.Equal(t, "workspace", row["resource_type"])
+		require.Equal(t, user.UserID.String(), row["user_id"])
+		require.Equal(t, coderdtest.FirstUserParams.Username, row["username"])
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			Format: "xml",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidQuery", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := client.ExportAuditLogs(ctx, codersdk.AuditLogExportRequest{
+			SearchQuery: "action:bogus",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
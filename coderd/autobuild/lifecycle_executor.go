@@ -3,6 +3,7 @@ package autobuild
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -19,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
@@ -35,6 +37,13 @@ type Executor struct {
 	log                   slog.Logger
 	tick                  <-chan time.Time
 	statsCh               chan<- Stats
+
+	// notificationsEnqueuer is used to warn workspace owners ahead of an
+	// autostop or autodelete, and to tell them when their workspace becomes
+	// dormant.
+	notificationsEnqueuer notifications.Enqueuer
+	autostopWarning       time.Duration
+	autodeleteWarning     time.Duration
 }
 
 // Stats contains information about one run of Executor.
@@ -56,6 +65,7 @@ func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, tss *
 		log:                   log.Named("autobuild"),
 		auditor:               auditor,
 		accessControlStore:    acs,
+		notificationsEnqueuer: notifications.NewNoopEnqueuer(),
 	}
 	return le
 }
@@ -67,6 +77,17 @@ func (e *Executor) WithStatsChannel(ch chan<- Stats) *Executor {
 	return e
 }
 
+// WithNotificationsEnqueuer will cause Executor to notify workspace owners
+// when their workspace becomes dormant, and when it is due to be autostopped
+// or autodeleted within the given warning periods. A zero warning period
+// disables the corresponding notification.
+func (e *Executor) WithNotificationsEnqueuer(enq notifications.Enqueuer, autostopWarning, autodeleteWarning time.Duration) *Executor {
+	e.notificationsEnqueuer = enq
+	e.autostopWarning = autostopWarning
+	e.autodeleteWarning = autodeleteWarning
+	return e
+}
+
 // Run will cause executor to start or stop workspaces on every
 // tick from its channel. It will stop when its context is Done, or when
 // its channel is closed.
@@ -106,6 +127,8 @@ func (e *Executor) runOnce(t time.Time) Stats {
 	}()
 	currentTick := t.Truncate(time.Minute)
 
+	e.notifyApproachingDeadlines(t)
+
 	// TTL is set at the workspace level, and deadline at the workspace build level.
 	// When a workspace build is created, its deadline initially starts at zero.
 	// When provisionerd successfully completes a provision job, the deadline is
@@ -136,6 +159,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 			err := func() error {
 				var job *database.ProvisionerJob
 				var auditLog *auditParams
+				var dormantWorkspace *database.Workspace
 				err := e.db.InTx(func(tx database.Store) error {
 					// Re-check eligibility since the first check was outside the
 					// transaction and the workspace settings may have changed.
@@ -225,6 +249,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 							slog.F("time_til_dormant", templateSchedule.TimeTilDormant),
 							slog.F("since_last_used_at", time.Since(ws.LastUsedAt)),
 						)
+						dormantWorkspace = &ws
 					}
 
 					if reason == database.BuildReasonAutodelete {
@@ -261,6 +286,11 @@ func (e *Executor) runOnce(t time.Time) Stats {
 				if err != nil {
 					return xerrors.Errorf("transition workspace: %w", err)
 				}
+				if dormantWorkspace != nil {
+					// Notify only once the transaction has committed so that
+					// the owner isn't told about a change that was rolled back.
+					e.notifyDormant(*dormantWorkspace)
+				}
 				if job != nil {
 					// Note that we can't refactor such that posting the job happens inside wsbuilder because it's called
 					// with an outer transaction like this, and we need to make sure the outer transaction commits before
@@ -485,3 +515,60 @@ func auditBuild(ctx context.Context, log slog.Logger, auditor audit.Auditor, par
 func useActiveVersion(opts dbauthz.TemplateAccessControl, ws database.Workspace) bool {
 	return opts.RequireActiveVersion || ws.AutomaticUpdates == database.AutomaticUpdatesAlways
 }
+
+// notifyApproachingDeadlines warns the owners of workspaces which will soon
+// be autostopped or autodeleted. Notifications are deduplicated on the
+// deadline, so each owner is warned once per deadline.
+func (e *Executor) notifyApproachingDeadlines(t time.Time) {
+	if e.autostopWarning <= 0 && e.autodeleteWarning <= 0 {
+		return
+	}
+
+	workspaces, err := e.db.GetWorkspacesApproachingDeadline(e.ctx, database.GetWorkspacesApproachingDeadlineParams{
+		Now:              t,
+		AutostopBefore:   t.Add(e.autostopWarning),
+		AutodeleteBefore: t.Add(e.autodeleteWarning),
+	})
+	if err != nil {
+		e.log.Error(e.ctx, "get workspaces approaching deadline", slog.Error(err))
+		return
+	}
+
+	for _, ws := range workspaces {
+		if e.autostopWarning > 0 && ws.Deadline.After(t) && !ws.Deadline.After(t.Add(e.autostopWarning)) {
+			e.enqueueNotification(ws.OwnerID, database.NotificationEventWorkspaceAutostopImminent, map[string]string{
+				notifications.LabelWorkspaceName: ws.Name,
+				notifications.LabelDeadline:      ws.Deadline.UTC().Format(time.RFC1123),
+			}, ws.ID, ws.Deadline)
+		}
+		if e.autodeleteWarning > 0 && ws.DeletingAt.Valid && !ws.DeletingAt.Time.After(t.Add(e.autodeleteWarning)) {
+			e.enqueueNotification(ws.OwnerID, database.NotificationEventWorkspaceAutodeletePending, map[string]string{
+				notifications.LabelWorkspaceName: ws.Name,
+				notifications.LabelDeletingAt:    ws.DeletingAt.Time.UTC().Format(time.RFC1123),
+			}, ws.ID, ws.DeletingAt.Time)
+		}
+	}
+}
+
+// notifyDormant tells the owner of ws that it has been marked dormant.
+func (e *Executor) notifyDormant(ws database.Workspace) {
+	labels := map[string]string{
+		notifications.LabelWorkspaceName: ws.Name,
+	}
+	if ws.DeletingAt.Valid {
+		labels[notifications.LabelDeletingAt] = ws.DeletingAt.Time.UTC().Format(time.RFC1123)
+	}
+	e.enqueueNotification(ws.OwnerID, database.NotificationEventWorkspaceDormant, labels, ws.ID, ws.DormantAt.Time)
+}
+
+func (e *Executor) enqueueNotification(userID uuid.UUID, event database.NotificationEvent, labels map[string]string, workspaceID uuid.UUID, at time.Time) {
+	dedupeKey := fmt.Sprintf("%s:%s:%d", event, workspaceID, at.Unix())
+	err := e.notificationsEnqueuer.Enqueue(e.ctx, userID, event, labels, dedupeKey)
+	if err != nil {
+		e.log.Error(e.ctx, "failed to enqueue notification",
+			slog.F("workspace_id", workspaceID),
+			slog.F("event", event),
+			slog.Error(err),
+		)
+	}
+}
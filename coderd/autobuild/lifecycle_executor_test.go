@@ -17,6 +17,7 @@ import (
 	"github.com/coder/coder/v2/coderd/autobuild"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/util/ptr"
@@ -345,6 +346,49 @@ func TestExecutorAutostopOK(t *testing.T) {
 	assert.Equal(t, codersdk.BuildReasonAutostop, workspace.LatestBuild.Reason)
 }
 
+func TestExecutorAutostopImminentNotification(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tickCh   = make(chan time.Time)
+		statsCh  = make(chan autobuild.Stats)
+		enqueuer = notifications.NewMockEnqueuer()
+		client   = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+			NotificationsEnqueuer:    enqueuer,
+		})
+		// Given: we have a user with a workspace
+		workspace = mustProvisionWorkspace(t, client)
+	)
+	// Given: workspace is running
+	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+	require.NotZero(t, workspace.LatestBuild.Deadline)
+
+	// When: the autobuild executor ticks twice within the warning period
+	// before the deadline:
+	go func() {
+		tickCh <- workspace.LatestBuild.Deadline.Time.Add(-30 * time.Minute)
+		tickCh <- workspace.LatestBuild.Deadline.Time.Add(-20 * time.Minute)
+		close(tickCh)
+	}()
+
+	// Then: the workspace should not be stopped
+	for i := 0; i < 2; i++ {
+		stats := <-statsCh
+		assert.Len(t, stats.Errors, 0)
+		assert.Len(t, stats.Transitions, 0)
+	}
+
+	// And: the owner should be notified exactly once
+	sent := enqueuer.Notifications()
+	require.Len(t, sent, 1)
+	assert.Equal(t, workspace.OwnerID, sent[0].UserID)
+	assert.Equal(t, database.NotificationEventWorkspaceAutostopImminent, sent[0].Event)
+	assert.Equal(t, workspace.Name, sent[0].Labels[notifications.LabelWorkspaceName])
+}
+
 func TestExecutorAutostopExtend(t *testing.T) {
 	t.Parallel()
 
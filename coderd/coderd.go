@@ -54,7 +54,9 @@ import (
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/idpsync"
 	"github.com/coder/coder/v2/coderd/metricscache"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
@@ -104,6 +106,7 @@ type Options struct {
 	CacheDir string
 
 	Auditor                        audit.Auditor
+	NotificationsEnqueuer          notifications.Enqueuer
 	AgentConnectionUpdateFrequency time.Duration
 	AgentInactiveDisconnectTimeout time.Duration
 	AWSCertificates                awsidentity.Certificates
@@ -121,17 +124,23 @@ type Options struct {
 	ExternalAuthConfigs            []*externalauth.Config
 	RealIPConfig                   *httpmw.RealIPConfig
 	TrialGenerator                 func(ctx context.Context, email string) error
+	// LoginProviders are named identity providers users can log in with, in
+	// addition to GithubOAuth2Config and OIDCConfig.
+	LoginProviders []*LoginProvider
 	// TLSCertificates is used to mesh DERP servers securely.
 	TLSCertificates    []tls.Certificate
 	TailnetCoordinator tailnet.Coordinator
 	DERPServer         *derp.Server
 	// BaseDERPMap is used as the base DERP map for all clients and agents.
 	// Proxies are added to this list.
-	BaseDERPMap                 *tailcfg.DERPMap
-	DERPMapUpdateFrequency      time.Duration
-	SwaggerEndpoint             bool
-	SetUserGroups               func(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, groupNames []string, createMissingGroups bool) error
-	SetUserSiteRoles            func(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, roles []string) error
+	BaseDERPMap            *tailcfg.DERPMap
+	DERPMapUpdateFrequency time.Duration
+	SwaggerEndpoint        bool
+	SetUserGroups          func(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, groupNames []string, createMissingGroups bool) error
+	SetUserSiteRoles       func(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, roles []string) error
+	// ApplyOIDCSyncRules assigns the organizations, roles and groups that the
+	// OIDC sync rules produced for the claims of a user logging in.
+	ApplyOIDCSyncRules          func(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, result idpsync.Result) error
 	TemplateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	AccessControlStore          *atomic.Pointer[dbauthz.AccessControlStore]
@@ -280,6 +289,9 @@ func New(options *Options) *API {
 	if options.Auditor == nil {
 		options.Auditor = audit.NewNop()
 	}
+	if options.NotificationsEnqueuer == nil {
+		options.NotificationsEnqueuer = notifications.NewNoopEnqueuer()
+	}
 	if options.SSHConfig.HostnamePrefix == "" {
 		options.SSHConfig.HostnamePrefix = "coder."
 	}
@@ -302,6 +314,14 @@ func New(options *Options) *API {
 			return nil
 		}
 	}
+	if options.ApplyOIDCSyncRules == nil {
+		options.ApplyOIDCSyncRules = func(ctx context.Context, logger slog.Logger, _ database.Store, userID uuid.UUID, result idpsync.Result) error {
+			logger.Warn(ctx, "attempted to apply OIDC sync rules without enterprise license",
+				slog.F("user_id", userID), slog.F("matched_rules", result.MatchedRules),
+			)
+			return nil
+		}
+	}
 	if options.TemplateScheduleStore == nil {
 		options.TemplateScheduleStore = &atomic.Pointer[schedule.TemplateScheduleStore]{}
 	}
@@ -340,8 +360,12 @@ func New(options *Options) *API {
 	)
 
 	oauthConfigs := &httpmw.OAuth2Configs{
-		Github: options.GithubOAuth2Config,
-		OIDC:   options.OIDCConfig,
+		Github:         options.GithubOAuth2Config,
+		OIDC:           options.OIDCConfig,
+		LoginProviders: make(map[string]httpmw.OAuth2Config, len(options.LoginProviders)),
+	}
+	for _, provider := range options.LoginProviders {
+		oauthConfigs.LoginProviders[provider.ID] = provider.OAuth2Config()
 	}
 
 	staticHandler := site.New(&site.Options{
@@ -396,7 +420,12 @@ func New(options *Options) *API {
 			ctx,
 			options.Logger.Named("acquirer"),
 			options.Database,
-			options.Pubsub),
+			options.Pubsub,
+			provisionerdserver.WithConcurrencyLimits(
+				int32(options.DeploymentValues.Provisioner.MaxConcurrentJobsPerUser.Value()),
+				int32(options.DeploymentValues.Provisioner.MaxConcurrentJobsPerTemplate.Value()),
+			),
+		),
 	}
 	if options.UpdateCheckOptions != nil {
 		api.updateChecker = updatecheck.New(
@@ -657,6 +686,7 @@ func New(options *Options) *API {
 			)
 
 			r.Get("/", api.auditLogs)
+			r.Get("/export", api.exportAuditLogs)
 			r.Post("/testgenerate", api.generateFakeAuditLog)
 		})
 		r.Route("/files", func(r chi.Router) {
@@ -707,31 +737,44 @@ func New(options *Options) *API {
 					})
 				})
 				r.Route("/members", func(r chi.Router) {
+					r.Get("/", api.listMembers)
 					r.Get("/roles", api.assignableOrgRoles)
 					r.Route("/{user}", func(r chi.Router) {
-						r.Use(
-							httpmw.ExtractOrganizationMemberParam(options.Database),
-						)
-						r.Put("/roles", api.putMemberRoles)
-						r.Post("/workspaces", api.postWorkspacesByOrganization)
+						r.With(httpmw.ExtractUserParam(options.Database)).Post("/", api.postOrganizationMember)
+						r.Group(func(r chi.Router) {
+							r.Use(
+								httpmw.ExtractOrganizationMemberParam(options.Database),
+							)
+							r.Delete("/", api.deleteOrganizationMember)
+							r.Put("/roles", api.putMemberRoles)
+							r.Post("/workspaces", api.postWorkspacesByOrganization)
+						})
 					})
 				})
 			})
 		})
 		r.Route("/templates/{template}", func(r chi.Router) {
-			r.Use(
-				apiKeyMiddleware,
-				httpmw.ExtractTemplateParam(options.Database),
-			)
-			r.Get("/daus", api.templateDAUs)
-			r.Get("/", api.template)
-			r.Delete("/", api.deleteTemplate)
-			r.Patch("/", api.patchTemplateMeta)
-			r.Route("/versions", func(r chi.Router) {
-				r.Post("/archive", api.postArchiveTemplateVersions)
-				r.Get("/", api.templateVersionsByTemplate)
-				r.Patch("/", api.patchActiveTemplateVersion)
-				r.Get("/{templateversionname}", api.templateVersionByName)
+			// Webhook deliveries are authenticated by the source's secret.
+			r.Post("/git/webhook", api.postTemplateGitWebhook)
+			r.Group(func(r chi.Router) {
+				r.Use(
+					apiKeyMiddleware,
+					httpmw.ExtractTemplateParam(options.Database),
+				)
+				r.Get("/daus", api.templateDAUs)
+				r.Get("/", api.template)
+				r.Delete("/", api.deleteTemplate)
+				r.Patch("/", api.patchTemplateMeta)
+				r.Get("/git", api.templateGitSource)
+				r.Put("/git", api.putTemplateGitSource)
+				r.Delete("/git", api.deleteTemplateGitSource)
+				r.Post("/git/sync", api.postTemplateGitSourceSync)
+				r.Route("/versions", func(r chi.Router) {
+					r.Post("/archive", api.postArchiveTemplateVersions)
+					r.Get("/", api.templateVersionsByTemplate)
+					r.Patch("/", api.patchActiveTemplateVersion)
+					r.Get("/{templateversionname}", api.templateVersionByName)
+				})
 			})
 		})
 		r.Route("/templateversions/{templateversion}", func(r chi.Router) {
@@ -757,6 +800,7 @@ func New(options *Options) *API {
 				r.Post("/", api.postTemplateVersionDryRun)
 				r.Get("/{jobID}", api.templateVersionDryRun)
 				r.Get("/{jobID}/resources", api.templateVersionDryRunResources)
+				r.Get("/{jobID}/resource-changes", api.templateVersionDryRunResourceChanges)
 				r.Get("/{jobID}/logs", api.templateVersionDryRunLogs)
 				r.Patch("/{jobID}/cancel", api.patchTemplateVersionDryRunCancel)
 			})
@@ -788,6 +832,18 @@ func New(options *Options) *API {
 					)
 					r.Get("/", api.userOIDC)
 				})
+				for _, provider := range options.LoginProviders {
+					var authURLParams map[string]string
+					if provider.OIDC != nil {
+						authURLParams = provider.OIDC.AuthURLParams
+					}
+					r.Route(fmt.Sprintf("/login-providers/%s/callback", provider.ID), func(r chi.Router) {
+						r.Use(
+							httpmw.ExtractOAuth2(provider.OAuth2Config(), options.HTTPClient, authURLParams),
+						)
+						r.Get("/", api.loginProviderCallback(provider))
+					})
+				}
 			})
 			r.Group(func(r chi.Router) {
 				r.Use(
@@ -800,6 +856,13 @@ func New(options *Options) *API {
 				r.Route("/roles", func(r chi.Router) {
 					r.Get("/", api.assignableSiteRoles)
 				})
+				// Workspaces shared with the caller can be looked up by
+				// owner and name without being able to read the owner.
+				r.Route("/{user}/workspace/{workspacename}", func(r chi.Router) {
+					r.Use(httpmw.ExtractWorkspaceOwnerParam(options.Database))
+					r.Get("/", api.workspaceByOwnerAndName)
+					r.Get("/builds/{buildnumber}", api.workspaceBuildByBuildNumber)
+				})
 				r.Route("/{user}", func(r chi.Router) {
 					r.Use(httpmw.ExtractUserParam(options.Database))
 					r.Post("/convert-login", api.postConvertLoginType)
@@ -839,10 +902,6 @@ func New(options *Options) *API {
 						r.Get("/", api.organizationsByUser)
 						r.Get("/{organizationname}", api.organizationByUserAndName)
 					})
-					r.Route("/workspace/{workspacename}", func(r chi.Router) {
-						r.Get("/", api.workspaceByOwnerAndName)
-						r.Get("/builds/{buildnumber}", api.workspaceBuildByBuildNumber)
-					})
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
 				})
@@ -882,6 +941,8 @@ func New(options *Options) *API {
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
 				r.Post("/metadata", api.workspaceAgentPostMetadata)
 				r.Post("/metadata/{key}", api.workspaceAgentPostMetadataDeprecated)
+				r.Post("/session-recordings", api.postWorkspaceAgentSessionRecording)
+				r.Post("/script-timings", api.postWorkspaceAgentScriptTiming)
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
 				r.Use(
@@ -901,6 +962,7 @@ func New(options *Options) *API {
 				r.Get("/startup-logs", api.workspaceAgentLogsDeprecated)
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/reconnecting-ptys", api.workspaceAgentReconnectingPTYs)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
@@ -918,6 +980,19 @@ func New(options *Options) *API {
 				)
 				r.Get("/", api.workspace)
 				r.Patch("/", api.patchWorkspace)
+				r.Route("/acl", func(r chi.Router) {
+					r.Get("/", api.workspaceACL)
+					r.Patch("/", api.patchWorkspaceACL)
+				})
+				r.Route("/port-shares", func(r chi.Router) {
+					r.Get("/", api.workspacePortShares)
+					r.Post("/", api.postWorkspacePortShare)
+					r.Delete("/{portshare}", api.deleteWorkspacePortShare)
+				})
+				r.Route("/session-recordings", func(r chi.Router) {
+					r.Get("/", api.workspaceSessionRecordings)
+					r.Get("/{recording}", api.workspaceSessionRecording)
+				})
 				r.Route("/builds", func(r chi.Router) {
 					r.Get("/", api.workspaceBuilds)
 					r.Post("/", api.postWorkspaceBuilds)
@@ -947,6 +1022,7 @@ func New(options *Options) *API {
 			r.Get("/parameters", api.workspaceBuildParameters)
 			r.Get("/resources", api.workspaceBuildResources)
 			r.Get("/state", api.workspaceBuildState)
+			r.Get("/timings", api.workspaceBuildTimings)
 		})
 		r.Route("/authcheck", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
@@ -1097,6 +1173,9 @@ type API struct {
 	WebsocketWaitGroup sync.WaitGroup
 	derpCloseFunc      func()
 
+	// gitSyncWaitGroup tracks syncs triggered by template git webhooks.
+	gitSyncWaitGroup sync.WaitGroup
+
 	metricsCache          *metricscache.Cache
 	updateChecker         *updatecheck.Checker
 	WorkspaceAppsProvider workspaceapps.SignedTokenProvider
@@ -1123,6 +1202,7 @@ func (api *API) Close() error {
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Wait()
 	api.WebsocketWaitMutex.Unlock()
+	api.gitSyncWaitGroup.Wait()
 
 	api.metricsCache.Close()
 	if api.updateChecker != nil {
@@ -1201,8 +1281,9 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, name string
 		api.UserQuietHoursScheduleStore,
 		api.DeploymentValues,
 		provisionerdserver.Options{
-			OIDCConfig:          api.OIDCConfig,
-			ExternalAuthConfigs: api.ExternalAuthConfigs,
+			OIDCConfig:            api.OIDCConfig,
+			ExternalAuthConfigs:   api.ExternalAuthConfigs,
+			NotificationsEnqueuer: api.NotificationsEnqueuer,
 		},
 	)
 	if err != nil {
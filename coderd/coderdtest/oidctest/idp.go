@@ -53,7 +53,10 @@ type FakeIDP struct {
 	// externalProviderID is optional to match the provider in coderd for
 	// redirectURLs.
 	externalProviderID string
-	logger             slog.Logger
+	// loginProviderID is optional to log in with a named login provider in
+	// coderd instead of the default OIDC provider.
+	loginProviderID string
+	logger          slog.Logger
 	// externalAuthValidate will be called when the user tries to validate their
 	// external auth. The fake IDP will reject any invalid tokens, so this just
 	// controls the response payload after a successfully authed token.
@@ -171,6 +174,14 @@ func WithIssuer(issuer string) func(*FakeIDP) {
 	}
 }
 
+// WithLoginProvider makes logins use the callback of the named login provider
+// with the ID instead of the default OIDC callback.
+func WithLoginProvider(id string) func(*FakeIDP) {
+	return func(f *FakeIDP) {
+		f.loginProviderID = id
+	}
+}
+
 const (
 	// nolint:gosec // It thinks this is a secret lol
 	tokenPath     = "/oauth2/token"
@@ -310,7 +321,11 @@ func (f *FakeIDP) AttemptLogin(t testing.TB, client *codersdk.Client, idTokenCla
 func (f *FakeIDP) LoginWithClient(t testing.TB, client *codersdk.Client, idTokenClaims jwt.MapClaims, opts ...func(r *http.Request)) (*codersdk.Client, *http.Response) {
 	t.Helper()
 
-	coderOauthURL, err := client.URL.Parse("/api/v2/users/oidc/callback")
+	callbackPath := "/api/v2/users/oidc/callback"
+	if f.loginProviderID != "" {
+		callbackPath = fmt.Sprintf("/api/v2/users/login-providers/%s/callback", f.loginProviderID)
+	}
+	coderOauthURL, err := client.URL.Parse(callbackPath)
 	require.NoError(t, err)
 	f.SetRedirect(t, coderOauthURL.String())
 
@@ -12,6 +12,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/parameter"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 )
@@ -126,8 +127,7 @@ func User(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
-		convertedUser.Roles = append(convertedUser.Roles, Role(rbacRole))
+		convertedUser.Roles = append(convertedUser.Roles, RoleByName(roleName))
 	}
 
 	return convertedUser
@@ -140,6 +140,40 @@ func Role(role rbac.Role) codersdk.Role {
 	}
 }
 
+// RoleByName converts an assigned role name into a role. Custom roles are not
+// compiled in, so only their name is known without a database lookup.
+func RoleByName(name string) codersdk.Role {
+	rbacRole, err := rbac.RoleByName(name)
+	if err != nil {
+		return codersdk.Role{Name: name}
+	}
+	return Role(rbacRole)
+}
+
+// CustomRole converts a custom role stored in the database, including the
+// permissions it grants.
+func CustomRole(role database.CustomRole) codersdk.Role {
+	return codersdk.Role{
+		Name:                    rolestore.RoleName(role),
+		DisplayName:             role.DisplayName,
+		SitePermissions:         Permissions(role.SitePermissions),
+		OrganizationPermissions: Permissions(role.OrgPermissions),
+		UserPermissions:         Permissions(role.UserPermissions),
+	}
+}
+
+func Permissions(permissions []rbac.Permission) []codersdk.Permission {
+	converted := make([]codersdk.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		converted = append(converted, codersdk.Permission{
+			Negate:       permission.Negate,
+			ResourceType: codersdk.RBACResource(permission.ResourceType),
+			Action:       string(permission.Action),
+		})
+	}
+	return converted
+}
+
 func TemplateInsightsParameters(parameterRows []database.GetTemplateParameterInsightsRow) ([]codersdk.TemplateParameterUsage, error) {
 	// Use a stable sort, similarly to how we would sort in the query, note that
 	// we don't sort in the query because order varies depending on the table
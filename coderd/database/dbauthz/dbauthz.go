@@ -21,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi/httpapiconstraints"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/provisionersdk"
 )
@@ -166,6 +167,8 @@ var (
 					rbac.ResourceWorkspaceBuild.Type: {rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
 					rbac.ResourceUserData.Type:       {rbac.ActionRead, rbac.ActionUpdate},
 					rbac.ResourceAPIKey.Type:         {rbac.WildcardSymbol},
+					// When serving, daemons record themselves.
+					rbac.ResourceProvisionerDaemon.Type: {rbac.ActionCreate},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -222,7 +225,7 @@ var (
 				Site: rbac.Permissions(map[string][]rbac.Action{
 					rbac.ResourceWildcard.Type:           {rbac.ActionRead},
 					rbac.ResourceAPIKey.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
 					rbac.ResourceRoleAssignment.Type:     {rbac.ActionCreate, rbac.ActionDelete},
 					rbac.ResourceSystem.Type:             {rbac.WildcardSymbol},
 					rbac.ResourceOrganization.Type:       {rbac.ActionCreate},
@@ -540,6 +543,29 @@ func (q *querier) authorizeUpdateFileTemplate(ctx context.Context, file database
 	}
 }
 
+// authorizeWorkspacePortShares checks the caller can update the workspace.
+// A share link exposes the workspace's ports to anyone holding it, so being
+// able to open apps in the workspace is not enough.
+func (q *querier) authorizeWorkspacePortShares(ctx context.Context, workspaceID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	return q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+}
+
+// authorizeWorkspaceSessionRecordings checks the caller can read the session
+// recordings of a workspace. Recordings hold everything that was typed and
+// printed in a terminal, so reading them requires the same access as opening
+// a terminal in the workspace, not just reading it.
+func (q *querier) authorizeWorkspaceSessionRecordings(ctx context.Context, workspaceID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	return q.authorizeContext(ctx, rbac.ActionCreate, workspace.ExecutionRBAC())
+}
+
 func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, removed []string) error {
 	actor, ok := ActorFromContext(ctx)
 	if !ok {
@@ -563,11 +589,12 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 		if !shouldBeOrgRoles && isOrgRole {
 			return xerrors.Errorf("Must only update site wide roles")
 		}
+	}
 
-		// All roles should be valid roles
-		if _, err := rbac.RoleByName(r); err != nil {
-			return xerrors.Errorf("%q is not a supported role", r)
-		}
+	// All added roles should be valid roles. Removed roles are not checked,
+	// since a custom role can be deleted while it is still assigned.
+	if err := rolestore.Validate(ctx, q.db, added); err != nil {
+		return err
 	}
 
 	if len(added) > 0 {
@@ -588,9 +615,93 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 		}
 	}
 
+	// Custom roles can only be assigned by actors that already hold every
+	// permission they grant. Otherwise an organization admin could create
+	// or reuse a role that grants more than they have, and assign it.
+	var custom []string
+	for _, roleName := range added {
+		if _, err := rbac.RoleByName(roleName); err != nil {
+			custom = append(custom, roleName)
+		}
+	}
+	if len(custom) > 0 {
+		dbroles, err := q.db.CustomRoles(ctx, database.CustomRolesParams{
+			LookupRoles: custom,
+		})
+		if err != nil {
+			return xerrors.Errorf("fetch custom roles: %w", err)
+		}
+		for _, dbrole := range dbroles {
+			if err := q.customRoleEscalationCheck(ctx, dbrole); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// customRoleObject returns the object custom roles are authorized against.
+// Organization roles are managed by organization admins.
+func customRoleObject(organizationID uuid.NullUUID) rbac.Object {
+	if organizationID.Valid {
+		return rbac.ResourceOrgRoleAssignment.InOrg(organizationID.UUID)
+	}
+	return rbac.ResourceRoleAssignment
+}
+
+// customRoleEscalationCheck ensures the actor holds every permission granted by
+// a custom role, so custom roles cannot be used to escalate privileges. It is
+// checked both when a role is created or updated and when it is assigned.
+func (q *querier) customRoleEscalationCheck(ctx context.Context, role database.CustomRole) error {
+	act, ok := ActorFromContext(ctx)
+	if !ok {
+		return NoActorError
+	}
+
+	if role.OrganizationID.Valid && len(role.SitePermissions) > 0 {
+		return xerrors.Errorf("organization roles cannot grant site wide permissions")
+	}
+	if role.OrganizationID.Valid && len(role.UserPermissions) > 0 {
+		// User permissions apply to the user's resources in every
+		// organization, so they cannot be granted by an organization.
+		return xerrors.Errorf("organization roles cannot grant user permissions")
+	}
+	if !role.OrganizationID.Valid && len(role.OrgPermissions) > 0 {
+		return xerrors.Errorf("site wide roles cannot grant organization permissions")
+	}
+
+	check := func(perms []rbac.Permission, scope func(rbac.Object) rbac.Object) error {
+		for _, perm := range perms {
+			if perm.Negate {
+				// Negating a permission never grants anything.
+				continue
+			}
+			obj := scope(rbac.Object{Type: perm.ResourceType})
+			if err := q.authorizeContext(ctx, perm.Action, obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := check(role.SitePermissions, func(obj rbac.Object) rbac.Object {
+		return obj
+	})
+	if err != nil {
+		return err
+	}
+	err = check(role.OrgPermissions, func(obj rbac.Object) rbac.Object {
+		return obj.InOrg(role.OrganizationID.UUID)
+	})
+	if err != nil {
+		return err
+	}
+	return check(role.UserPermissions, func(obj rbac.Object) rbac.Object {
+		return obj.WithOwner(act.ID)
+	})
+}
+
 func (q *querier) SoftDeleteTemplateByID(ctx context.Context, id uuid.UUID) error {
 	deleteF := func(ctx context.Context, id uuid.UUID) error {
 		return q.db.UpdateTemplateDeletedByID(ctx, database.UpdateTemplateDeletedByIDParams{
@@ -655,6 +766,13 @@ func (q *querier) AcquireLock(ctx context.Context, id int64) error {
 	return q.db.AcquireLock(ctx, id)
 }
 
+func (q *querier) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireNotificationMessages(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -711,6 +829,41 @@ func (q *querier) CleanTailnetTunnels(ctx context.Context) error {
 	return q.db.CleanTailnetTunnels(ctx)
 }
 
+func (q *querier) CountOldAuditLogs(ctx context.Context, beforeTime time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.CountOldAuditLogs(ctx, beforeTime)
+}
+
+func (q *querier) CountOldProvisionerJobLogs(ctx context.Context, beforeTime time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.CountOldProvisionerJobLogs(ctx, beforeTime)
+}
+
+func (q *querier) CountOldWorkspaceBuilds(ctx context.Context, keepCount int32) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.CountOldWorkspaceBuilds(ctx, keepCount)
+}
+
+func (q *querier) CountOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.CountOldWorkspaceSessionRecordings(ctx, beforeTime)
+}
+
+func (q *querier) CustomRoles(ctx context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceRoleAssignment); err != nil {
+		return nil, err
+	}
+	return q.db.CustomRoles(ctx, arg)
+}
+
 func (q *querier) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	return deleteQ(q.log, q.auth, q.db.GetAPIKeyByID, q.db.DeleteAPIKeyByID)(ctx, id)
 }
@@ -756,6 +909,13 @@ func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	return q.db.DeleteCoordinator(ctx, id)
 }
 
+func (q *querier) DeleteCustomRole(ctx context.Context, arg database.DeleteCustomRoleParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, customRoleObject(arg.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.DeleteCustomRole(ctx, arg)
+}
+
 func (q *querier) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	return deleteQ(q.log, q.auth, func(ctx context.Context, arg database.DeleteExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		//nolint:gosimple
@@ -800,6 +960,20 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteOldAuditLogs(ctx context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldAuditLogs(ctx, arg)
+}
+
+func (q *querier) DeleteOldNotificationMessages(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldNotificationMessages(ctx)
+}
+
 func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -807,6 +981,13 @@ func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	return q.db.DeleteOldProvisionerDaemons(ctx)
 }
 
+func (q *querier) DeleteOldProvisionerJobLogs(ctx context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldProvisionerJobLogs(ctx, arg)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -821,6 +1002,54 @@ func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	return q.db.DeleteOldWorkspaceAgentStats(ctx)
 }
 
+func (q *querier) DeleteOldWorkspaceBuilds(ctx context.Context, arg database.DeleteOldWorkspaceBuildsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldWorkspaceBuilds(ctx, arg)
+}
+
+func (q *querier) DeleteOldWorkspacePortShares(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspacePortShares(ctx)
+}
+
+func (q *querier) DeleteOldWorkspaceSessionRecordings(ctx context.Context, arg database.DeleteOldWorkspaceSessionRecordingsParams) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.DeleteOldWorkspaceSessionRecordings(ctx, arg)
+}
+
+func (q *querier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
+	member, err := q.db.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+		OrganizationID: arg.OrganizationID,
+		UserID:         arg.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Removing a member removes all of their roles, including the implied
+	// org member role.
+	removedRoles := append(member.Roles, rbac.RoleOrgMember(arg.OrganizationID))
+	err = q.canAssignRoles(ctx, &arg.OrganizationID, []string{}, removedRoles)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, member); err != nil {
+		return err
+	}
+	return q.db.DeleteOrganizationMember(ctx, arg)
+}
+
+func (q *querier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetProvisionerKeyByID, q.db.DeleteProvisionerKey)(ctx, id)
+}
+
 func (q *querier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -863,6 +1092,31 @@ func (q *querier) DeleteTailnetTunnel(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetTunnel(ctx, arg)
 }
 
+func (q *querier) DeleteTemplateGitSource(ctx context.Context, templateID uuid.UUID) error {
+	fetch := func(ctx context.Context, templateID uuid.UUID) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, templateID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteTemplateGitSource)(ctx, templateID)
+}
+
+func (q *querier) DeleteWorkspacePortShareByID(ctx context.Context, id uuid.UUID) error {
+	share, err := q.db.GetWorkspacePortShareByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeWorkspacePortShares(ctx, share.WorkspaceID); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspacePortShareByID(ctx, id)
+}
+
+func (q *querier) EnqueueNotificationMessage(ctx context.Context, arg database.EnqueueNotificationMessageParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.EnqueueNotificationMessage(ctx, arg)
+}
+
 func (q *querier) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByID)(ctx, id)
 }
@@ -936,6 +1190,14 @@ func (q *querier) GetApplicationName(ctx context.Context) (string, error) {
 	return q.db.GetApplicationName(ctx)
 }
 
+func (q *querier) GetAuditLogsBefore(ctx context.Context, arg database.GetAuditLogsBeforeParams) ([]database.GetAuditLogsBeforeRow, error) {
+	// Like GetAuditLogsOffset, only check the global audit log permission.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogsBefore(ctx, arg)
+}
+
 func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	// To optimize audit logs, we only check the global audit log permission once.
 	// This is because we expect a large unbounded set of audit logs, and applying a SQL
@@ -1133,6 +1395,13 @@ func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	return q.db.GetOAuthSigningKey(ctx)
 }
 
+func (q *querier) GetOIDCSyncRules(ctx context.Context) (string, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceDeploymentValues); err != nil {
+		return "", err
+	}
+	return q.db.GetOIDCSyncRules(ctx)
+}
+
 func (q *querier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
 	return fetch(q.log, q.auth, q.db.GetOrganizationByID)(ctx, id)
 }
@@ -1151,6 +1420,10 @@ func (q *querier) GetOrganizationMemberByUserID(ctx context.Context, arg databas
 	return fetch(q.log, q.auth, q.db.GetOrganizationMemberByUserID)(ctx, arg)
 }
 
+func (q *querier) GetOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]database.GetOrganizationMembersRow, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetOrganizationMembers)(ctx, organizationID)
+}
+
 func (q *querier) GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OrganizationMember, error) {
 	return fetchWithPostFilter(q.auth, q.db.GetOrganizationMembershipsByUserID)(ctx, userID)
 }
@@ -1235,6 +1508,24 @@ func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (data
 	return job, nil
 }
 
+func (q *querier) GetProvisionerJobResourceChangesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobResourceChange, error) {
+	// Authorized fetch of the job.
+	_, err := q.GetProvisionerJobByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobResourceChangesByJobID(ctx, jobID)
+}
+
+func (q *querier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
+	// Authorized fetch of the job.
+	_, err := q.GetProvisionerJobByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobTimingsByJobID(ctx, jobID)
+}
+
 // TODO: we need to add a provisioner job resource
 func (q *querier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
@@ -1256,6 +1547,14 @@ func (q *querier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetProvisionerJobsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
+	return fetch(q.log, q.auth, q.db.GetProvisionerKeyByID)(ctx, id)
+}
+
+func (q *querier) GetProvisionerKeyByName(ctx context.Context, arg database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	return fetch(q.log, q.auth, q.db.GetProvisionerKeyByName)(ctx, arg)
+}
+
 func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	// Authorized read on job lets the actor also read the logs.
 	_, err := q.GetProvisionerJobByID(ctx, arg.JobID)
@@ -1295,6 +1594,15 @@ func (q *querier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Ti
 	return q.db.GetReplicasUpdatedAfter(ctx, updatedAt)
 }
 
+func (q *querier) GetSensitiveTemplateVersionVariables(ctx context.Context) ([]database.TemplateVersionVariable, error) {
+	// Only system-related functions should be allowed to read the values of
+	// all sensitive variables.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetSensitiveTemplateVersionVariables(ctx)
+}
+
 func (q *querier) GetServiceBanner(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetServiceBanner(ctx)
@@ -1390,6 +1698,30 @@ func (q *querier) GetTemplateDAUs(ctx context.Context, arg database.GetTemplateD
 	return q.db.GetTemplateDAUs(ctx, arg)
 }
 
+func (q *querier) GetTemplateGitSourceByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateGitSource, error) {
+	// The source includes the webhook secret, so outside of the system it is
+	// only readable by actors that can update the template.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); IsNotAuthorizedError(err) {
+		template, err := q.db.GetTemplateByID(ctx, templateID)
+		if err != nil {
+			return database.TemplateGitSource{}, err
+		}
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+			return database.TemplateGitSource{}, err
+		}
+	} else if err != nil {
+		return database.TemplateGitSource{}, err
+	}
+	return q.db.GetTemplateGitSourceByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetTemplateGitSources(ctx context.Context) ([]database.TemplateGitSource, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateGitSources(ctx)
+}
+
 func (q *querier) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	// Used by TemplateInsights endpoint
 	// For auditors, check read template_insights, and fall back to update template.
@@ -1787,6 +2119,13 @@ func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, arg database.Ge
 	return q.db.GetWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentScriptTimingsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScriptTiming, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentScriptTimingsByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -1890,6 +2229,13 @@ func (q *querier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Conte
 	return q.db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceBuildIDsWithProvisionerState(ctx context.Context) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildIDsWithProvisionerState(ctx)
+}
+
 func (q *querier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	// Authorized call to get the workspace build. If we can read the build,
 	// we can read the params.
@@ -1934,6 +2280,30 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspacePortShareByID(ctx context.Context, id uuid.UUID) (database.WorkspacePortShare, error) {
+	share, err := q.db.GetWorkspacePortShareByID(ctx, id)
+	if err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+	// Reading a single share is needed to check a link when it is used, so
+	// only read access to the workspace is required.
+	workspace, err := q.db.GetWorkspaceByID(ctx, share.WorkspaceID)
+	if err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, workspace); err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+	return share, nil
+}
+
+func (q *querier) GetWorkspacePortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortShare, error) {
+	if err := q.authorizeWorkspacePortShares(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacePortSharesByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxies(ctx)
@@ -2048,6 +2418,31 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	recording, err := q.db.GetWorkspaceSessionRecordingByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	if err := q.authorizeWorkspaceSessionRecordings(ctx, recording.WorkspaceID); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	return recording, nil
+}
+
+func (q *querier) GetWorkspaceSessionRecordingIDs(ctx context.Context) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSessionRecordingIDs(ctx)
+}
+
+func (q *querier) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceSessionRecordingsByWorkspaceIDRow, error) {
+	if err := q.authorizeWorkspaceSessionRecordings(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -2063,6 +2458,13 @@ func (q *querier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesP
 	return q.db.GetAuthorizedWorkspaces(ctx, arg, prep)
 }
 
+func (q *querier) GetWorkspacesApproachingDeadline(ctx context.Context, arg database.GetWorkspacesApproachingDeadlineParams) ([]database.GetWorkspacesApproachingDeadlineRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspacesApproachingDeadline(ctx, arg)
+}
+
 func (q *querier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	return q.db.GetWorkspacesEligibleForTransition(ctx, now)
 }
@@ -2172,6 +2574,24 @@ func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.Ins
 	return q.db.InsertProvisionerJobLogs(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerJobResourceChanges(ctx context.Context, arg database.InsertProvisionerJobResourceChangesParams) ([]database.ProvisionerJobResourceChange, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertProvisionerJobResourceChanges(ctx, arg)
+}
+
+func (q *querier) InsertProvisionerJobTimings(ctx context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.InsertProvisionerJobTimings(ctx, arg)
+}
+
+func (q *querier) InsertProvisionerKey(ctx context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	return insert(q.log, q.auth, rbac.ResourceProvisionerDaemon.InOrg(arg.OrganizationID), q.db.InsertProvisionerKey)(ctx, arg)
+}
+
 func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -2283,6 +2703,17 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentScriptTiming(ctx context.Context, arg database.InsertWorkspaceAgentScriptTimingParams) (database.WorkspaceAgentScriptTiming, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return database.WorkspaceAgentScriptTiming{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentScriptTiming{}, err
+	}
+	return q.db.InsertWorkspaceAgentScriptTiming(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentScripts(ctx context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return []database.WorkspaceAgentScript{}, err
@@ -2377,7 +2808,12 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 		return err
 	}
 
-	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	var action rbac.Action = rbac.ActionUpdate
+	if build.Transition == database.WorkspaceTransitionDelete {
+		action = rbac.ActionDelete
+	}
+
+	err = q.authorizeContext(ctx, action, workspace.WorkspaceBuildRBAC(build.Transition))
 	if err != nil {
 		return err
 	}
@@ -2385,6 +2821,13 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspacePortShare(ctx context.Context, arg database.InsertWorkspacePortShareParams) (database.WorkspacePortShare, error) {
+	if err := q.authorizeWorkspacePortShares(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+	return q.db.InsertWorkspacePortShare(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	return insert(q.log, q.auth, rbac.ResourceWorkspaceProxy, q.db.InsertWorkspaceProxy)(ctx, arg)
 }
@@ -2403,6 +2846,35 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceSessionRecording(ctx context.Context, arg database.InsertWorkspaceSessionRecordingParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceSessionRecording(ctx, arg)
+}
+
+func (q *querier) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
+	return fetchWithPostFilter(q.auth, q.db.ListProvisionerKeysByOrganization)(ctx, organizationID)
+}
+
+func (q *querier) MarkNotificationMessageFailed(ctx context.Context, arg database.MarkNotificationMessageFailedParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.MarkNotificationMessageFailed(ctx, arg)
+}
+
+func (q *querier) MarkNotificationMessageSent(ctx context.Context, arg database.MarkNotificationMessageSentParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.MarkNotificationMessageSent(ctx, arg)
+}
+
 func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2493,6 +2965,13 @@ func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemb
 	return q.db.UpdateMemberRoles(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerDaemonLastSeenAt(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -2611,6 +3090,20 @@ func (q *querier) UpdateTemplateDeletedByID(ctx context.Context, arg database.Up
 	return q.SoftDeleteTemplateByID(ctx, arg.ID)
 }
 
+func (q *querier) UpdateTemplateGitSourceSyncStatus(ctx context.Context, arg database.UpdateTemplateGitSourceSyncStatusParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateGitSourceSyncStatusParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.TemplateID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateGitSourceSyncStatus)(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateGitSourceWebhookSecret(ctx context.Context, arg database.UpdateTemplateGitSourceWebhookSecretParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateGitSourceWebhookSecret(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateMetaByID(ctx context.Context, arg database.UpdateTemplateMetaByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateMetaByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -2691,6 +3184,13 @@ func (q *querier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.
 	return q.db.UpdateTemplateVersionExternalAuthProvidersByJobID(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateVersionVariableValue(ctx context.Context, arg database.UpdateTemplateVersionVariableValueParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateTemplateVersionVariableValue(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.TemplateID)
@@ -2830,6 +3330,16 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	// Like templates, only users that can create the workspace may update the
+	// ACL. This prevents users the workspace is shared with from sharing it
+	// further.
+	return fetchAndExec(q.log, q.auth, rbac.ActionCreate, fetch, q.db.UpdateWorkspaceACLByID)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3005,6 +3515,13 @@ func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyDeleted)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceSessionRecordingDataByID(ctx context.Context, arg database.UpdateWorkspaceSessionRecordingDataByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceSessionRecordingDataByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceTTLParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -3032,6 +3549,21 @@ func (q *querier) UpsertApplicationName(ctx context.Context, value string) error
 	return q.db.UpsertApplicationName(ctx, value)
 }
 
+func (q *querier) UpsertCustomRole(ctx context.Context, arg database.UpsertCustomRoleParams) (database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, customRoleObject(arg.OrganizationID)); err != nil {
+		return database.CustomRole{}, err
+	}
+	if err := q.customRoleEscalationCheck(ctx, database.CustomRole{
+		OrganizationID:  arg.OrganizationID,
+		SitePermissions: arg.SitePermissions,
+		OrgPermissions:  arg.OrgPermissions,
+		UserPermissions: arg.UserPermissions,
+	}); err != nil {
+		return database.CustomRole{}, err
+	}
+	return q.db.UpsertCustomRole(ctx, arg)
+}
+
 func (q *querier) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3067,6 +3599,13 @@ func (q *querier) UpsertOAuthSigningKey(ctx context.Context, value string) error
 	return q.db.UpsertOAuthSigningKey(ctx, value)
 }
 
+func (q *querier) UpsertOIDCSyncRules(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceDeploymentValues); err != nil {
+		return err
+	}
+	return q.db.UpsertOIDCSyncRules(ctx, value)
+}
+
 func (q *querier) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	res := rbac.ResourceProvisionerDaemon.All()
 	if arg.Tags[provisionersdk.TagScope] == provisionersdk.ScopeUser {
@@ -3127,6 +3666,17 @@ func (q *querier) UpsertTailnetTunnel(ctx context.Context, arg database.UpsertTa
 	return q.db.UpsertTailnetTunnel(ctx, arg)
 }
 
+func (q *querier) UpsertTemplateGitSource(ctx context.Context, arg database.UpsertTemplateGitSourceParams) (database.TemplateGitSource, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateGitSource{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return database.TemplateGitSource{}, err
+	}
+	return q.db.UpsertTemplateGitSource(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
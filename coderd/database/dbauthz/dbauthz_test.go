@@ -234,6 +234,13 @@ func (s *MethodTestSuite) TestAuditLogs() {
 			Action:       database.AuditActionCreate,
 		}).Asserts(rbac.ResourceAuditLog, rbac.ActionCreate)
 	}))
+	s.Run("GetAuditLogsBefore", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(database.GetAuditLogsBeforeParams{
+			RowLimit: 10,
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
 	s.Run("GetAuditLogsOffset", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
 		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
@@ -243,6 +250,51 @@ func (s *MethodTestSuite) TestAuditLogs() {
 	}))
 }
 
+func (s *MethodTestSuite) TestCustomRoles() {
+	s.Run("CustomRoles", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.CustomRolesParams{}).Asserts(rbac.ResourceRoleAssignment, rbac.ActionRead).Returns([]database.CustomRole{})
+	}))
+	s.Run("DeleteCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.DeleteCustomRoleParams{
+			Name:           "test",
+			OrganizationID: uuid.NullUUID{UUID: o.ID, Valid: true},
+		}).Asserts(rbac.ResourceOrgRoleAssignment.InOrg(o.ID), rbac.ActionUpdate)
+	}))
+	s.Run("UpsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertCustomRoleParams{
+			Name:        "test",
+			DisplayName: "Test Name",
+			SitePermissions: database.CustomRolePermissions{
+				{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionRead},
+				{ResourceType: rbac.ResourceWorkspace.Type, Action: rbac.ActionDelete, Negate: true},
+			},
+			OrgPermissions:  database.CustomRolePermissions{},
+			UserPermissions: database.CustomRolePermissions{},
+		}).Asserts(
+			rbac.ResourceRoleAssignment, rbac.ActionUpdate,
+			// Negated permissions do not need to be held by the actor.
+			rbac.ResourceTemplate, rbac.ActionRead,
+		)
+	}))
+	s.Run("Organization/UpsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpsertCustomRoleParams{
+			Name:            "test",
+			DisplayName:     "Test Name",
+			OrganizationID:  uuid.NullUUID{UUID: o.ID, Valid: true},
+			SitePermissions: database.CustomRolePermissions{},
+			OrgPermissions: database.CustomRolePermissions{
+				{ResourceType: rbac.ResourceTemplate.Type, Action: rbac.ActionUpdate},
+			},
+			UserPermissions: database.CustomRolePermissions{},
+		}).Asserts(
+			rbac.ResourceOrgRoleAssignment.InOrg(o.ID), rbac.ActionUpdate,
+			rbac.ResourceTemplate.InOrg(o.ID), rbac.ActionUpdate,
+		)
+	}))
+}
+
 func (s *MethodTestSuite) TestFile() {
 	s.Run("GetFileByHashAndCreator", s.Subtest(func(db database.Store, check *expects) {
 		f := dbgen.File(s.T(), db, database.File{})
@@ -388,6 +440,14 @@ func (s *MethodTestSuite) TestProvsionerJob() {
 		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{JobID: j.ID, WorkspaceID: w.ID})
 		check.Args(j.ID).Asserts(w, rbac.ActionRead).Returns(j)
 	}))
+	s.Run("GetProvisionerJobTimingsByJobID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{JobID: j.ID, WorkspaceID: w.ID})
+		check.Args(j.ID).Asserts(w, rbac.ActionRead).Returns([]database.ProvisionerJobTiming{})
+	}))
 	s.Run("TemplateVersion/GetProvisionerJobByID", s.Subtest(func(db database.Store, check *expects) {
 		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
 			Type: database.ProvisionerJobTypeTemplateVersionImport,
@@ -412,6 +472,19 @@ func (s *MethodTestSuite) TestProvsionerJob() {
 		})
 		check.Args(j.ID).Asserts(v.RBACObject(tpl), rbac.ActionRead).Returns(j)
 	}))
+	s.Run("GetProvisionerJobResourceChangesByJobID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		v := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: tpl.ID, Valid: true},
+		})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeTemplateVersionDryRun,
+			Input: must(json.Marshal(struct {
+				TemplateVersionID uuid.UUID `json:"template_version_id"`
+			}{TemplateVersionID: v.ID})),
+		})
+		check.Args(j.ID).Asserts(v.RBACObject(tpl), rbac.ActionRead).Returns([]database.ProvisionerJobResourceChange{})
+	}))
 	s.Run("Build/UpdateProvisionerJobWithCancelByID", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{AllowUserCancelWorkspaceJobs: true})
 		w := dbgen.Workspace(s.T(), db, database.Workspace{TemplateID: tpl.ID})
@@ -536,6 +609,14 @@ func (s *MethodTestSuite) TestLicense() {
 		require.NoError(s.T(), err)
 		check.Args().Asserts().Returns("value")
 	}))
+	s.Run("UpsertOIDCSyncRules", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(`{"rules":[]}`).Asserts(rbac.ResourceDeploymentValues, rbac.ActionCreate)
+	}))
+	s.Run("GetOIDCSyncRules", s.Subtest(func(db database.Store, check *expects) {
+		err := db.UpsertOIDCSyncRules(context.Background(), `{"rules":[]}`)
+		require.NoError(s.T(), err)
+		check.Args().Asserts(rbac.ResourceDeploymentValues, rbac.ActionRead).Returns(`{"rules":[]}`)
+	}))
 }
 
 func (s *MethodTestSuite) TestOrganization() {
@@ -569,6 +650,13 @@ func (s *MethodTestSuite) TestOrganization() {
 			UserID:         mem.UserID,
 		}).Asserts(mem, rbac.ActionRead).Returns(mem)
 	}))
+	s.Run("GetOrganizationMembers", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		mem := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{OrganizationID: o.ID, UserID: u.ID})
+		row := database.GetOrganizationMembersRow{OrganizationMember: mem, Username: u.Username}
+		check.Args(o.ID).Asserts(row, rbac.ActionRead).Returns(slice.New(row))
+	}))
 	s.Run("GetOrganizationMembershipsByUserID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		a := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{UserID: u.ID})
@@ -606,6 +694,23 @@ func (s *MethodTestSuite) TestOrganization() {
 			rbac.ResourceRoleAssignment.InOrg(o.ID), rbac.ActionCreate,
 			rbac.ResourceOrganizationMember.InOrg(o.ID).WithID(u.ID), rbac.ActionCreate)
 	}))
+	s.Run("DeleteOrganizationMember", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		mem := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{
+			OrganizationID: o.ID,
+			UserID:         u.ID,
+			Roles:          []string{rbac.RoleOrgAdmin(o.ID)},
+		})
+
+		check.Args(database.DeleteOrganizationMemberParams{
+			OrganizationID: o.ID,
+			UserID:         u.ID,
+		}).Asserts(
+			rbac.ResourceRoleAssignment.InOrg(o.ID), rbac.ActionDelete,
+			mem, rbac.ActionDelete,
+		).Returns()
+	}))
 	s.Run("UpdateMemberRoles", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		u := dbgen.User(s.T(), db, database.User{})
@@ -659,6 +764,42 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 	}))
 }
 
+func (s *MethodTestSuite) TestProvisionerKey() {
+	s.Run("InsertProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.InsertProvisionerKeyParams{
+			ID:             uuid.New(),
+			OrganizationID: org.ID,
+			Name:           "key",
+			Tags:           database.StringMap{},
+		}).Asserts(rbac.ResourceProvisionerDaemon.InOrg(org.ID), rbac.ActionCreate)
+	}))
+	s.Run("GetProvisionerKeyByID", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		key := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
+		check.Args(key.ID).Asserts(key, rbac.ActionRead).Returns(key)
+	}))
+	s.Run("GetProvisionerKeyByName", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		key := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
+		check.Args(database.GetProvisionerKeyByNameParams{
+			OrganizationID: org.ID,
+			Name:           key.Name,
+		}).Asserts(key, rbac.ActionRead).Returns(key)
+	}))
+	s.Run("ListProvisionerKeysByOrganization", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		a := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID, Name: "a"})
+		b := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID, Name: "b"})
+		check.Args(org.ID).Asserts(a, rbac.ActionRead, b, rbac.ActionRead).Returns(slice.New(a, b))
+	}))
+	s.Run("DeleteProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		key := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: org.ID})
+		check.Args(key.ID).Asserts(key, rbac.ActionDelete).Returns()
+	}))
+}
+
 func (s *MethodTestSuite) TestTemplate() {
 	s.Run("GetPreviousTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
 		tvid := uuid.New()
@@ -739,6 +880,45 @@ func (s *MethodTestSuite) TestTemplate() {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate)
 	}))
+	s.Run("GetTemplateGitSourceByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		source := dbgen.TemplateGitSource(s.T(), db, database.TemplateGitSource{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(source)
+	}))
+	s.Run("GetTemplateGitSources", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		source := dbgen.TemplateGitSource(s.T(), db, database.TemplateGitSource{TemplateID: t1.ID})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns([]database.TemplateGitSource{source})
+	}))
+	s.Run("UpsertTemplateGitSource", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateGitSourceParams{
+			TemplateID:    t1.ID,
+			RepositoryURL: "https://github.com/coder/coder.git",
+			Branch:        "main",
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateGitSourceSyncStatus", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		_ = dbgen.TemplateGitSource(s.T(), db, database.TemplateGitSource{TemplateID: t1.ID})
+		check.Args(database.UpdateTemplateGitSourceSyncStatusParams{
+			TemplateID:    t1.ID,
+			LastCommitSHA: "0123456789abcdef0123456789abcdef01234567",
+		}).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateTemplateGitSourceWebhookSecret", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		_ = dbgen.TemplateGitSource(s.T(), db, database.TemplateGitSource{TemplateID: t1.ID})
+		check.Args(database.UpdateTemplateGitSourceWebhookSecretParams{
+			TemplateID:    t1.ID,
+			WebhookSecret: "secret",
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("DeleteTemplateGitSource", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		_ = dbgen.TemplateGitSource(s.T(), db, database.TemplateGitSource{TemplateID: t1.ID})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("GetTemplateUserRoles", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate)
@@ -1204,6 +1384,21 @@ func (s *MethodTestSuite) TestWorkspace() {
 		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, BuildNumber: 3})
 		check.Args(database.GetWorkspaceBuildsByWorkspaceIDParams{WorkspaceID: ws.ID}).Asserts(ws, rbac.ActionRead) // ordering
 	}))
+	s.Run("InsertWorkspaceAgentScriptTiming", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.InsertWorkspaceAgentScriptTimingParams{
+			AgentID:     agt.ID,
+			LogSourceID: uuid.New(),
+			Stage:       database.WorkspaceAgentScriptTimingStageStart,
+			Status:      database.WorkspaceAgentScriptTimingStatusOk,
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
 	s.Run("GetWorkspaceByAgentID", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
@@ -1331,7 +1526,7 @@ func (s *MethodTestSuite) TestWorkspace() {
 			WorkspaceBuildID: b.ID,
 			Name:             []string{"foo", "bar"},
 			Value:            []string{"baz", "qux"},
-		}).Asserts(w, rbac.ActionUpdate)
+		}).Asserts(w.WorkspaceBuildRBAC(b.Transition), rbac.ActionUpdate)
 	}))
 	s.Run("UpdateWorkspace", s.Subtest(func(db database.Store, check *expects) {
 		w := dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1341,6 +1536,89 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: w.ID,
 		}).Asserts(w, rbac.ActionUpdate).Returns(expected)
 	}))
+	s.Run("UpdateWorkspaceACLByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceACLByIDParams{
+			ID: w.ID,
+		}).Asserts(w, rbac.ActionCreate)
+	}))
+	s.Run("InsertWorkspacePortShare", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		w := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		check.Args(database.InsertWorkspacePortShareParams{
+			ID:          uuid.New(),
+			WorkspaceID: w.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			CreatedBy:   u.ID,
+			ExpiresAt:   dbtime.Now().Add(time.Hour),
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("GetWorkspacePortShareByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		w := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		share, err := db.InsertWorkspacePortShare(context.Background(), database.InsertWorkspacePortShareParams{
+			ID:          uuid.New(),
+			WorkspaceID: w.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			CreatedBy:   u.ID,
+			ExpiresAt:   dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		check.Args(share.ID).Asserts(w, rbac.ActionRead).Returns(share)
+	}))
+	s.Run("GetWorkspacePortSharesByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(w.ID).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspacePortShareByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		w := dbgen.Workspace(s.T(), db, database.Workspace{OwnerID: u.ID})
+		share, err := db.InsertWorkspacePortShare(context.Background(), database.InsertWorkspacePortShareParams{
+			ID:          uuid.New(),
+			WorkspaceID: w.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			CreatedBy:   u.ID,
+			ExpiresAt:   dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		check.Args(share.ID).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("InsertWorkspaceSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceSessionRecordingParams{
+			ID:               uuid.New(),
+			WorkspaceID:      w.ID,
+			WorkspaceBuildID: uuid.New(),
+			AgentID:          uuid.New(),
+			Type:             database.WorkspaceSessionRecordingTypeSsh,
+			StartedAt:        dbtime.Now(),
+			EndedAt:          dbtime.Now(),
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		id := uuid.New()
+		err := db.InsertWorkspaceSessionRecording(context.Background(), database.InsertWorkspaceSessionRecordingParams{
+			ID:               id,
+			WorkspaceID:      w.ID,
+			WorkspaceBuildID: uuid.New(),
+			AgentID:          uuid.New(),
+			Type:             database.WorkspaceSessionRecordingTypeReconnectingPty,
+			StartedAt:        dbtime.Now(),
+			EndedAt:          dbtime.Now(),
+			Size:             2,
+			Data:             []byte("{}"),
+		})
+		require.NoError(s.T(), err)
+		check.Args(id).Asserts(w.ExecutionRBAC(), rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceSessionRecordingsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(w.ID).Asserts(w.ExecutionRBAC(), rbac.ActionCreate).Returns([]database.GetWorkspaceSessionRecordingsByWorkspaceIDRow{})
+	}))
 	s.Run("InsertWorkspaceAgentStat", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.InsertWorkspaceAgentStatParams{
@@ -1519,6 +1797,49 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			ProvisionerState: []byte("testing"),
 		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
 	}))
+	s.Run("GetWorkspaceBuildIDsWithProvisionerState", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, ProvisionerState: []byte("testing")})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns([]uuid.UUID{build.ID})
+	}))
+	s.Run("GetSensitiveTemplateVersionVariables", s.Subtest(func(db database.Store, check *expects) {
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{})
+		dbgen.TemplateVersionVariable(s.T(), db, database.TemplateVersionVariable{TemplateVersionID: tv.ID})
+		sensitive := dbgen.TemplateVersionVariable(s.T(), db, database.TemplateVersionVariable{TemplateVersionID: tv.ID, Sensitive: true})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns([]database.TemplateVersionVariable{sensitive})
+	}))
+	s.Run("UpdateTemplateVersionVariableValue", s.Subtest(func(db database.Store, check *expects) {
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{})
+		v := dbgen.TemplateVersionVariable(s.T(), db, database.TemplateVersionVariable{TemplateVersionID: tv.ID, Sensitive: true})
+		check.Args(database.UpdateTemplateVersionVariableValueParams{
+			TemplateVersionID: tv.ID,
+			Name:              v.Name,
+			Value:             "testing",
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceSessionRecordingIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceSessionRecordingDataByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		id := uuid.New()
+		err := db.InsertWorkspaceSessionRecording(context.Background(), database.InsertWorkspaceSessionRecordingParams{
+			ID:               id,
+			WorkspaceID:      w.ID,
+			WorkspaceBuildID: uuid.New(),
+			AgentID:          uuid.New(),
+			Type:             database.WorkspaceSessionRecordingTypeSsh,
+			StartedAt:        dbtime.Now(),
+			EndedAt:          dbtime.Now(),
+			Size:             2,
+			Data:             []byte("{}"),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateWorkspaceSessionRecordingDataByIDParams{
+			ID:   id,
+			Data: []byte("[]"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("UpsertLastUpdateCheck", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("value").Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
 	}))
@@ -1550,6 +1871,53 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
 	}))
+	s.Run("DeleteOldWorkspacePortShares", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteOldAuditLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldAuditLogsParams{
+			BeforeTime: dbtime.Now(),
+			LimitCount: 1000,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("CountOldAuditLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteOldProvisionerJobLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldProvisionerJobLogsParams{
+			BeforeTime: dbtime.Now(),
+			LimitCount: 1000,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("CountOldProvisionerJobLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteOldWorkspaceBuilds", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldWorkspaceBuildsParams{
+			KeepCount:  10,
+			LimitCount: 1000,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("CountOldWorkspaceBuilds", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(int32(10)).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteOldWorkspaceSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteOldWorkspaceSessionRecordingsParams{
+			BeforeTime: dbtime.Now(),
+			LimitCount: 1000,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("CountOldWorkspaceSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspacesApproachingDeadline", s.Subtest(func(db database.Store, check *expects) {
+		now := dbtime.Now()
+		check.Args(database.GetWorkspacesApproachingDeadlineParams{
+			Now:              now,
+			AutostopBefore:   now.Add(time.Hour),
+			AutodeleteBefore: now.Add(24 * time.Hour),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: add provisioner job resource type
 		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
@@ -1682,8 +2050,26 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			Provisioner:   database.ProvisionerTypeEcho,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 			Type:          database.ProvisionerJobTypeWorkspaceBuild,
+			Priority:      database.ProvisionerJobPriorityUser,
 		}).Asserts( /*rbac.ResourceSystem, rbac.ActionCreate*/ )
 	}))
+	s.Run("InsertProvisionerJobResourceChanges", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.InsertProvisionerJobResourceChangesParams{
+			JobID: j.ID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("InsertProvisionerJobTimings", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.InsertProvisionerJobTimingsParams{
+			JobID: j.ID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceAgentScriptTimingsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead).
+			Returns([]database.WorkspaceAgentScriptTiming{})
+	}))
 	s.Run("InsertProvisionerJobLogs", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: we need to create a ProvisionerJob resource
 		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
@@ -1691,6 +2077,18 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			JobID: j.ID,
 		}).Asserts( /*rbac.ResourceSystem, rbac.ActionCreate*/ )
 	}))
+	s.Run("UpdateProvisionerDaemonLastSeenAt", s.Subtest(func(db database.Store, check *expects) {
+		pd, err := db.UpsertProvisionerDaemon(context.Background(), database.UpsertProvisionerDaemonParams{
+			Tags: database.StringMap(map[string]string{
+				provisionersdk.TagScope: provisionersdk.ScopeOrganization,
+			}),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateProvisionerDaemonLastSeenAtParams{
+			ID:         pd.ID,
+			LastSeenAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("UpsertProvisionerDaemon", s.Subtest(func(db database.Store, check *expects) {
 		pd := rbac.ResourceProvisionerDaemon.All()
 		check.Args(database.UpsertProvisionerDaemonParams{
@@ -1719,3 +2117,41 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
 }
+
+func (s *MethodTestSuite) TestNotifications() {
+	s.Run("EnqueueNotificationMessage", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.EnqueueNotificationMessageParams{
+			ID:      uuid.New(),
+			Event:   database.NotificationEventWorkspaceDormant,
+			UserID:  u.ID,
+			Method:  database.NotificationMethodSMTP,
+			Payload: database.StringMap{"workspace_name": "test"},
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("AcquireNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		now := dbtime.Now()
+		check.Args(database.AcquireNotificationMessagesParams{
+			Now:         now,
+			LeasedUntil: now.Add(time.Minute),
+			Count:       10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("MarkNotificationMessageSent", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.MarkNotificationMessageSentParams{
+			ID:        uuid.New(),
+			UpdatedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("MarkNotificationMessageFailed", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.MarkNotificationMessageFailedParams{
+			ID:           uuid.New(),
+			Status:       database.NotificationMessageStatusFailed,
+			StatusReason: "failed",
+			UpdatedAt:    dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOldNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+}
@@ -163,6 +163,8 @@ func (b WorkspaceBuildBuilder) Do() WorkspaceResponse {
 		Input:          payload,
 		Tags:           nil,
 		TraceMetadata:  pqtype.NullRawMessage{},
+		Priority:       database.ProvisionerJobPriorityUser,
+		TemplateID:     uuid.NullUUID{UUID: b.ws.TemplateID, Valid: true},
 	})
 	require.NoError(b.t, err, "insert job")
 
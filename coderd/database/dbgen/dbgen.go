@@ -114,17 +114,19 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 	key, err := db.InsertAPIKey(genCtx, database.InsertAPIKeyParams{
 		ID: takeFirst(seed.ID, id),
 		// 0 defaults to 86400 at the db layer
-		LifetimeSeconds: takeFirst(seed.LifetimeSeconds, 0),
-		HashedSecret:    takeFirstSlice(seed.HashedSecret, hashed[:]),
-		IPAddress:       ip,
-		UserID:          takeFirst(seed.UserID, uuid.New()),
-		LastUsed:        takeFirst(seed.LastUsed, dbtime.Now()),
-		ExpiresAt:       takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
-		CreatedAt:       takeFirst(seed.CreatedAt, dbtime.Now()),
-		UpdatedAt:       takeFirst(seed.UpdatedAt, dbtime.Now()),
-		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
-		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
-		TokenName:       takeFirst(seed.TokenName),
+		LifetimeSeconds:  takeFirst(seed.LifetimeSeconds, 0),
+		HashedSecret:     takeFirstSlice(seed.HashedSecret, hashed[:]),
+		IPAddress:        ip,
+		UserID:           takeFirst(seed.UserID, uuid.New()),
+		LastUsed:         takeFirst(seed.LastUsed, dbtime.Now()),
+		ExpiresAt:        takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
+		CreatedAt:        takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:        takeFirst(seed.UpdatedAt, dbtime.Now()),
+		LoginType:        takeFirst(seed.LoginType, database.LoginTypePassword),
+		Scope:            takeFirst(seed.Scope, database.APIKeyScopeAll),
+		TokenName:        takeFirst(seed.TokenName),
+		ScopePermissions: takeFirstSlice(seed.ScopePermissions, database.CustomRolePermissions{}),
+		ScopeAllowList:   takeFirstSlice(seed.ScopeAllowList, []string{}),
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -205,19 +207,20 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 	var build database.WorkspaceBuild
 	err := db.InTx(func(db database.Store) error {
 		err := db.InsertWorkspaceBuild(genCtx, database.InsertWorkspaceBuildParams{
-			ID:                buildID,
-			CreatedAt:         takeFirst(orig.CreatedAt, dbtime.Now()),
-			UpdatedAt:         takeFirst(orig.UpdatedAt, dbtime.Now()),
-			WorkspaceID:       takeFirst(orig.WorkspaceID, uuid.New()),
-			TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
-			BuildNumber:       takeFirst(orig.BuildNumber, 1),
-			Transition:        takeFirst(orig.Transition, database.WorkspaceTransitionStart),
-			InitiatorID:       takeFirst(orig.InitiatorID, uuid.New()),
-			JobID:             takeFirst(orig.JobID, uuid.New()),
-			ProvisionerState:  takeFirstSlice(orig.ProvisionerState, []byte{}),
-			Deadline:          takeFirst(orig.Deadline, dbtime.Now().Add(time.Hour)),
-			MaxDeadline:       takeFirst(orig.MaxDeadline, time.Time{}),
-			Reason:            takeFirst(orig.Reason, database.BuildReasonInitiator),
+			ID:                    buildID,
+			CreatedAt:             takeFirst(orig.CreatedAt, dbtime.Now()),
+			UpdatedAt:             takeFirst(orig.UpdatedAt, dbtime.Now()),
+			WorkspaceID:           takeFirst(orig.WorkspaceID, uuid.New()),
+			TemplateVersionID:     takeFirst(orig.TemplateVersionID, uuid.New()),
+			BuildNumber:           takeFirst(orig.BuildNumber, 1),
+			Transition:            takeFirst(orig.Transition, database.WorkspaceTransitionStart),
+			InitiatorID:           takeFirst(orig.InitiatorID, uuid.New()),
+			JobID:                 takeFirst(orig.JobID, uuid.New()),
+			ProvisionerState:      takeFirstSlice(orig.ProvisionerState, []byte{}),
+			Deadline:              takeFirst(orig.Deadline, dbtime.Now().Add(time.Hour)),
+			MaxDeadline:           takeFirst(orig.MaxDeadline, time.Time{}),
+			Reason:                takeFirst(orig.Reason, database.BuildReasonInitiator),
+			ProvisionerStateKeyID: takeFirst(orig.ProvisionerStateKeyID, sql.NullString{}),
 		})
 		if err != nil {
 			return err
@@ -396,6 +399,8 @@ func ProvisionerJob(t testing.TB, db database.Store, ps pubsub.Pubsub, orig data
 		Input:          takeFirstSlice(orig.Input, []byte("{}")),
 		Tags:           orig.Tags,
 		TraceMetadata:  pqtype.NullRawMessage{},
+		Priority:       takeFirst(orig.Priority, database.ProvisionerJobPriorityUser),
+		TemplateID:     orig.TemplateID,
 	})
 	require.NoError(t, err, "insert job")
 	if ps != nil {
@@ -528,6 +533,23 @@ func WorkspaceProxy(t testing.TB, db database.Store, orig database.WorkspaceProx
 	return proxy, secret
 }
 
+func ProvisionerKey(t testing.TB, db database.Store, orig database.ProvisionerKey) database.ProvisionerKey {
+	tags := orig.Tags
+	if tags == nil {
+		tags = database.StringMap{}
+	}
+	key, err := db.InsertProvisionerKey(genCtx, database.InsertProvisionerKeyParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		Name:           takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		HashedSecret:   takeFirstSlice(orig.HashedSecret, []byte{}),
+		Tags:           tags,
+	})
+	require.NoError(t, err, "insert provisioner key")
+	return key
+}
+
 func File(t testing.TB, db database.Store, orig database.File) database.File {
 	file, err := db.InsertFile(genCtx, database.InsertFileParams{
 		ID:        takeFirst(orig.ID, uuid.New()),
@@ -552,6 +574,7 @@ func UserLink(t testing.TB, db database.Store, orig database.UserLink) database.
 		OAuthRefreshTokenKeyID: takeFirst(orig.OAuthRefreshTokenKeyID, sql.NullString{}),
 		OAuthExpiry:            takeFirst(orig.OAuthExpiry, dbtime.Now().Add(time.Hour*24)),
 		DebugContext:           takeFirstSlice(orig.DebugContext, json.RawMessage("{}")),
+		LoginProvider:          takeFirst(orig.LoginProvider),
 	})
 
 	require.NoError(t, err, "insert link")
@@ -582,16 +605,18 @@ func TemplateVersion(t testing.TB, db database.Store, orig database.TemplateVers
 	err := db.InTx(func(db database.Store) error {
 		versionID := takeFirst(orig.ID, uuid.New())
 		err := db.InsertTemplateVersion(genCtx, database.InsertTemplateVersionParams{
-			ID:             versionID,
-			TemplateID:     takeFirst(orig.TemplateID, uuid.NullUUID{}),
-			OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
-			CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
-			UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
-			Name:           takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
-			Message:        orig.Message,
-			Readme:         takeFirst(orig.Readme, namesgenerator.GetRandomName(1)),
-			JobID:          takeFirst(orig.JobID, uuid.New()),
-			CreatedBy:      takeFirst(orig.CreatedBy, uuid.New()),
+			ID:               versionID,
+			TemplateID:       takeFirst(orig.TemplateID, uuid.NullUUID{}),
+			OrganizationID:   takeFirst(orig.OrganizationID, uuid.New()),
+			CreatedAt:        takeFirst(orig.CreatedAt, dbtime.Now()),
+			UpdatedAt:        takeFirst(orig.UpdatedAt, dbtime.Now()),
+			Name:             takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+			Message:          orig.Message,
+			Readme:           takeFirst(orig.Readme, namesgenerator.GetRandomName(1)),
+			JobID:            takeFirst(orig.JobID, uuid.New()),
+			CreatedBy:        takeFirst(orig.CreatedBy, uuid.New()),
+			GitCommitSHA:     orig.GitCommitSHA,
+			GitCommitMessage: orig.GitCommitMessage,
 		})
 		if err != nil {
 			return err
@@ -608,6 +633,24 @@ func TemplateVersion(t testing.TB, db database.Store, orig database.TemplateVers
 	return version
 }
 
+func TemplateGitSource(t testing.TB, db database.Store, orig database.TemplateGitSource) database.TemplateGitSource {
+	source, err := db.UpsertTemplateGitSource(genCtx, database.UpsertTemplateGitSourceParams{
+		TemplateID:         takeFirst(orig.TemplateID, uuid.New()),
+		CreatedAt:          takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:          takeFirst(orig.UpdatedAt, dbtime.Now()),
+		CreatedBy:          takeFirst(orig.CreatedBy, uuid.New()),
+		RepositoryURL:      takeFirst(orig.RepositoryURL, "https://github.com/coder/coder.git"),
+		Branch:             takeFirst(orig.Branch, "main"),
+		Subdirectory:       orig.Subdirectory,
+		AutoPromote:        orig.AutoPromote,
+		PollInterval:       orig.PollInterval,
+		WebhookSecret:      takeFirst(orig.WebhookSecret, namesgenerator.GetRandomName(1)),
+		WebhookSecretKeyID: takeFirst(orig.WebhookSecretKeyID, sql.NullString{}),
+	})
+	require.NoError(t, err, "insert template git source")
+	return source
+}
+
 func TemplateVersionVariable(t testing.TB, db database.Store, orig database.TemplateVersionVariable) database.TemplateVersionVariable {
 	version, err := db.InsertTemplateVersionVariable(genCtx, database.InsertTemplateVersionVariableParams{
 		TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
@@ -618,6 +661,7 @@ func TemplateVersionVariable(t testing.TB, db database.Store, orig database.Temp
 		DefaultValue:      takeFirst(orig.DefaultValue, namesgenerator.GetRandomName(1)),
 		Required:          takeFirst(orig.Required, false),
 		Sensitive:         takeFirst(orig.Sensitive, false),
+		ValueKeyID:        takeFirst(orig.ValueKeyID, sql.NullString{}),
 	})
 	require.NoError(t, err, "insert template version variable")
 	return version
@@ -1,6 +1,7 @@
 package dbmem
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -123,6 +124,7 @@ type data struct {
 	// New tables
 	workspaceAgentStats           []database.WorkspaceAgentStat
 	auditLogs                     []database.AuditLog
+	customRoles                   []database.CustomRole
 	dbcryptKeys                   []database.DBCryptKey
 	files                         []database.File
 	externalAuthLinks             []database.ExternalAuthLink
@@ -130,11 +132,16 @@ type data struct {
 	groupMembers                  []database.GroupMember
 	groups                        []database.Group
 	licenses                      []database.License
+	notificationMessages          []database.NotificationMessage
 	parameterSchemas              []database.ParameterSchema
 	provisionerDaemons            []database.ProvisionerDaemon
 	provisionerJobLogs            []database.ProvisionerJobLog
+	provisionerJobResourceChanges []database.ProvisionerJobResourceChange
+	provisionerJobTimings         []database.ProvisionerJobTiming
 	provisionerJobs               []database.ProvisionerJob
+	provisionerKeys               []database.ProvisionerKey
 	replicas                      []database.Replica
+	templateGitSources            []database.TemplateGitSource
 	templateVersions              []database.TemplateVersionTable
 	templateVersionParameters     []database.TemplateVersionParameter
 	templateVersionVariables      []database.TemplateVersionVariable
@@ -144,6 +151,7 @@ type data struct {
 	workspaceAgentLogs            []database.WorkspaceAgentLog
 	workspaceAgentLogSources      []database.WorkspaceAgentLogSource
 	workspaceAgentScripts         []database.WorkspaceAgentScript
+	workspaceAgentScriptTimings   []database.WorkspaceAgentScriptTiming
 	workspaceApps                 []database.WorkspaceApp
 	workspaceAppStatsLastInsertID int64
 	workspaceAppStats             []database.WorkspaceAppStat
@@ -153,6 +161,8 @@ type data struct {
 	workspaceResources            []database.WorkspaceResource
 	workspaces                    []database.Workspace
 	workspaceProxies              []database.WorkspaceProxy
+	workspacePortShares           []database.WorkspacePortShare
+	workspaceSessionRecordings    []database.WorkspaceSessionRecording
 	// Locks is a map of lock names. Any keys within the map are currently
 	// locked.
 	locks                   map[int64]struct{}
@@ -161,6 +171,7 @@ type data struct {
 	lastUpdateCheck         []byte
 	serviceBanner           []byte
 	healthSettings          []byte
+	oidcSyncRules           []byte
 	applicationName         string
 	logoURL                 string
 	appSecurityKey          string
@@ -357,6 +368,8 @@ func (q *FakeQuerier) convertToWorkspaceRowsNoLock(ctx context.Context, workspac
 			DeletingAt:        w.DeletingAt,
 			Count:             count,
 			AutomaticUpdates:  w.AutomaticUpdates,
+			UserACL:           w.UserACL,
+			GroupACL:          w.GroupACL,
 		}
 
 		for _, t := range q.templates {
@@ -718,6 +731,114 @@ func provisonerJobStatus(j database.ProvisionerJob) database.ProvisionerJobStatu
 	return database.ProvisionerJobStatusRunning
 }
 
+// provisionerJobPriorityRank orders priorities the way the database enum does,
+// from lowest to highest.
+func provisionerJobPriorityRank(p database.ProvisionerJobPriority) int {
+	return slices.Index(database.AllProvisionerJobPriorityValues(), p)
+}
+
+// oldAuditLogsNoLock returns the audit logs older than before, oldest first.
+func (q *FakeQuerier) oldAuditLogsNoLock(before time.Time) []database.AuditLog {
+	var logs []database.AuditLog
+	for _, alog := range q.auditLogs {
+		if alog.Time.Before(before) {
+			logs = append(logs, alog)
+		}
+	}
+	slices.SortFunc(logs, func(a, b database.AuditLog) int {
+		return a.Time.Compare(b.Time)
+	})
+	return logs
+}
+
+// oldProvisionerJobLogsNoLock returns the logs of completed provisioner jobs
+// that are older than before, in insertion order.
+func (q *FakeQuerier) oldProvisionerJobLogsNoLock(before time.Time) []database.ProvisionerJobLog {
+	completed := make(map[uuid.UUID]struct{})
+	for _, job := range q.provisionerJobs {
+		if job.CompletedAt.Valid {
+			completed[job.ID] = struct{}{}
+		}
+	}
+	var logs []database.ProvisionerJobLog
+	for _, log := range q.provisionerJobLogs {
+		if _, ok := completed[log.JobID]; ok && log.CreatedAt.Before(before) {
+			logs = append(logs, log)
+		}
+	}
+	return logs
+}
+
+// oldWorkspaceBuildJobIDsNoLock returns the job IDs of completed workspace
+// builds that are older than the keepCount most recent builds of their
+// workspace, skipping builds whose agents have app stats and builds with
+// session recordings.
+func (q *FakeQuerier) oldWorkspaceBuildJobIDsNoLock(keepCount int32) []uuid.UUID {
+	buildsByWorkspace := make(map[uuid.UUID][]database.WorkspaceBuildTable)
+	for _, build := range q.workspaceBuilds {
+		buildsByWorkspace[build.WorkspaceID] = append(buildsByWorkspace[build.WorkspaceID], build)
+	}
+
+	agentsWithStats := make(map[uuid.UUID]struct{})
+	for _, stat := range q.workspaceAppStats {
+		agentsWithStats[stat.AgentID] = struct{}{}
+	}
+	hasAppStats := func(jobID uuid.UUID) bool {
+		for _, resource := range q.workspaceResources {
+			if resource.JobID != jobID {
+				continue
+			}
+			for _, agent := range q.workspaceAgents {
+				if _, ok := agentsWithStats[agent.ID]; ok && agent.ResourceID == resource.ID {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	buildsWithRecordings := make(map[uuid.UUID]struct{})
+	for _, recording := range q.workspaceSessionRecordings {
+		buildsWithRecordings[recording.WorkspaceBuildID] = struct{}{}
+	}
+
+	var jobIDs []uuid.UUID
+	for _, builds := range buildsByWorkspace {
+		slices.SortFunc(builds, func(a, b database.WorkspaceBuildTable) int {
+			return int(b.BuildNumber - a.BuildNumber)
+		})
+		for i, build := range builds {
+			if i < int(keepCount) {
+				continue
+			}
+			job, err := q.getProvisionerJobByIDNoLock(context.Background(), build.JobID)
+			if err != nil || !job.CompletedAt.Valid || hasAppStats(build.JobID) {
+				continue
+			}
+			if _, ok := buildsWithRecordings[build.ID]; ok {
+				continue
+			}
+			jobIDs = append(jobIDs, build.JobID)
+		}
+	}
+	return jobIDs
+}
+
+// oldWorkspaceSessionRecordingsNoLock returns the recordings that ended
+// before before, oldest first.
+func (q *FakeQuerier) oldWorkspaceSessionRecordingsNoLock(before time.Time) []database.WorkspaceSessionRecording {
+	var recordings []database.WorkspaceSessionRecording
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.EndedAt.Before(before) {
+			recordings = append(recordings, recording)
+		}
+	}
+	slices.SortFunc(recordings, func(a, b database.WorkspaceSessionRecording) int {
+		return a.EndedAt.Compare(b.EndedAt)
+	})
+	return recordings
+}
+
 // isNull is only used in dbmem, so reflect is ok. Use this to make the logic
 // look more similar to the postgres.
 func isNull(v interface{}) bool {
@@ -732,6 +853,39 @@ func (*FakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
 
+func (q *FakeQuerier) AcquireNotificationMessages(_ context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	// Messages are appended in creation order, so iterating in order
+	// matches the ORDER BY created_at of the SQL query.
+	acquired := make([]database.NotificationMessage, 0)
+	for i, msg := range q.notificationMessages {
+		if len(acquired) >= int(arg.Count) {
+			break
+		}
+		leaseExpired := msg.Status == database.NotificationMessageStatusLeased &&
+			msg.LeasedUntil.Valid && msg.LeasedUntil.Time.Before(arg.Now)
+		if msg.Status != database.NotificationMessageStatusPending && !leaseExpired {
+			continue
+		}
+		if msg.NextRetryAfter.Valid && msg.NextRetryAfter.Time.After(arg.Now) {
+			continue
+		}
+		msg.Status = database.NotificationMessageStatusLeased
+		msg.UpdatedAt = arg.Now
+		msg.LeasedUntil = sql.NullTime{Time: arg.LeasedUntil, Valid: true}
+		q.notificationMessages[i] = msg
+		acquired = append(acquired, msg)
+	}
+	return acquired, nil
+}
+
 func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -740,37 +894,38 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for index, provisionerJob := range q.provisionerJobs {
-		if provisionerJob.StartedAt.Valid {
+	tags := map[string]string{}
+	if arg.Tags != nil {
+		err := json.Unmarshal(arg.Tags, &tags)
+		if err != nil {
+			return database.ProvisionerJob{}, xerrors.Errorf("unmarshal: %w", err)
+		}
+	}
+
+	runningByUser := map[uuid.UUID]int32{}
+	runningByTemplate := map[uuid.UUID]int32{}
+	for _, provisionerJob := range q.provisionerJobs {
+		if !provisionerJob.StartedAt.Valid || provisionerJob.CompletedAt.Valid {
 			continue
 		}
-		found := false
-		for _, provisionerType := range arg.Types {
-			if provisionerJob.Provisioner != provisionerType {
-				continue
-			}
-			found = true
-			break
+		runningByUser[provisionerJob.InitiatorID]++
+		if provisionerJob.TemplateID.Valid {
+			runningByTemplate[provisionerJob.TemplateID.UUID]++
 		}
-		if !found {
+	}
+
+	var candidates []int
+	for index, provisionerJob := range q.provisionerJobs {
+		if provisionerJob.StartedAt.Valid {
 			continue
 		}
-		tags := map[string]string{}
-		if arg.Tags != nil {
-			err := json.Unmarshal(arg.Tags, &tags)
-			if err != nil {
-				return provisionerJob, xerrors.Errorf("unmarshal: %w", err)
-			}
+		if !slices.Contains(arg.Types, provisionerJob.Provisioner) {
+			continue
 		}
-
 		missing := false
 		for key, value := range provisionerJob.Tags {
 			provided, found := tags[key]
-			if !found {
-				missing = true
-				break
-			}
-			if provided != value {
+			if !found || provided != value {
 				missing = true
 				break
 			}
@@ -778,18 +933,41 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 		if missing {
 			continue
 		}
-		provisionerJob.StartedAt = arg.StartedAt
-		provisionerJob.UpdatedAt = arg.StartedAt.Time
-		provisionerJob.WorkerID = arg.WorkerID
-		provisionerJob.JobStatus = provisonerJobStatus(provisionerJob)
-		q.provisionerJobs[index] = provisionerJob
-		// clone the Tags before returning, since maps are reference types and
-		// we don't want the caller to be able to mutate the map we have inside
-		// dbmem!
-		provisionerJob.Tags = maps.Clone(provisionerJob.Tags)
-		return provisionerJob, nil
+		if arg.MaxConcurrentPerUser >= 1 && runningByUser[provisionerJob.InitiatorID] >= arg.MaxConcurrentPerUser {
+			continue
+		}
+		if arg.MaxConcurrentPerTemplate >= 1 && provisionerJob.TemplateID.Valid &&
+			runningByTemplate[provisionerJob.TemplateID.UUID] >= arg.MaxConcurrentPerTemplate {
+			continue
+		}
+		candidates = append(candidates, index)
 	}
-	return database.ProvisionerJob{}, sql.ErrNoRows
+	if len(candidates) == 0 {
+		return database.ProvisionerJob{}, sql.ErrNoRows
+	}
+	slices.SortStableFunc(candidates, func(i, j int) int {
+		a, b := q.provisionerJobs[i], q.provisionerJobs[j]
+		if c := provisionerJobPriorityRank(b.Priority) - provisionerJobPriorityRank(a.Priority); c != 0 {
+			return c
+		}
+		if c := int(runningByUser[a.InitiatorID]) - int(runningByUser[b.InitiatorID]); c != 0 {
+			return c
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	index := candidates[0]
+	provisionerJob := q.provisionerJobs[index]
+	provisionerJob.StartedAt = arg.StartedAt
+	provisionerJob.UpdatedAt = arg.StartedAt.Time
+	provisionerJob.WorkerID = arg.WorkerID
+	provisionerJob.JobStatus = provisonerJobStatus(provisionerJob)
+	q.provisionerJobs[index] = provisionerJob
+	// clone the Tags before returning, since maps are reference types and
+	// we don't want the caller to be able to mutate the map we have inside
+	// dbmem!
+	provisionerJob.Tags = maps.Clone(provisionerJob.Tags)
+	return provisionerJob, nil
 }
 
 func (q *FakeQuerier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
@@ -973,6 +1151,67 @@ func (*FakeQuerier) CleanTailnetTunnels(context.Context) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) CountOldAuditLogs(_ context.Context, beforeTime time.Time) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return int64(len(q.oldAuditLogsNoLock(beforeTime))), nil
+}
+
+func (q *FakeQuerier) CountOldProvisionerJobLogs(_ context.Context, beforeTime time.Time) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return int64(len(q.oldProvisionerJobLogsNoLock(beforeTime))), nil
+}
+
+func (q *FakeQuerier) CountOldWorkspaceBuilds(_ context.Context, keepCount int32) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return int64(len(q.oldWorkspaceBuildJobIDsNoLock(keepCount))), nil
+}
+
+func (q *FakeQuerier) CountOldWorkspaceSessionRecordings(_ context.Context, beforeTime time.Time) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return int64(len(q.oldWorkspaceSessionRecordingsNoLock(beforeTime))), nil
+}
+
+func (q *FakeQuerier) CustomRoles(_ context.Context, arg database.CustomRolesParams) ([]database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	found := make([]database.CustomRole, 0)
+	for _, role := range q.customRoles {
+		if len(arg.LookupRoles) > 0 {
+			name := role.Name
+			if role.OrganizationID.Valid {
+				name += ":" + role.OrganizationID.UUID.String()
+			}
+			if !slices.Contains(arg.LookupRoles, name) {
+				continue
+			}
+		}
+		if arg.ExcludeOrgRoles && role.OrganizationID.Valid {
+			continue
+		}
+		if arg.OrganizationID != uuid.Nil && role.OrganizationID.UUID != arg.OrganizationID {
+			continue
+		}
+		found = append(found, role)
+	}
+	slices.SortFunc(found, func(a, b database.CustomRole) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return found, nil
+}
+
 func (q *FakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1036,6 +1275,20 @@ func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteCustomRole(_ context.Context, arg database.DeleteCustomRoleParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.customRoles = slices.DeleteFunc(q.customRoles, func(role database.CustomRole) bool {
+		return role.Name == strings.ToLower(arg.Name) && role.OrganizationID == arg.OrganizationID
+	})
+	return nil
+}
+
 func (q *FakeQuerier) DeleteExternalAuthLink(_ context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1144,6 +1397,52 @@ func (q *FakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error)
 	return 0, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteOldAuditLogs(_ context.Context, arg database.DeleteOldAuditLogsParams) (int64, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	old := q.oldAuditLogsNoLock(arg.BeforeTime)
+	if len(old) > int(arg.LimitCount) {
+		old = old[:arg.LimitCount]
+	}
+	deleted := make(map[uuid.UUID]struct{}, len(old))
+	for _, alog := range old {
+		deleted[alog.ID] = struct{}{}
+	}
+	var validLogs []database.AuditLog
+	for _, alog := range q.auditLogs {
+		if _, ok := deleted[alog.ID]; ok {
+			continue
+		}
+		validLogs = append(validLogs, alog)
+	}
+	q.auditLogs = validLogs
+	return int64(len(deleted)), nil
+}
+
+func (q *FakeQuerier) DeleteOldNotificationMessages(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	weekAgo := dbtime.Now().Add(-7 * 24 * time.Hour)
+
+	var validMessages []database.NotificationMessage
+	for _, msg := range q.notificationMessages {
+		done := msg.Status == database.NotificationMessageStatusSent ||
+			msg.Status == database.NotificationMessageStatusFailed
+		if done && msg.UpdatedAt.Before(weekAgo) {
+			continue
+		}
+		validMessages = append(validMessages, msg)
+	}
+	q.notificationMessages = validMessages
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOldProvisionerDaemons(_ context.Context) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1163,6 +1462,33 @@ func (q *FakeQuerier) DeleteOldProvisionerDaemons(_ context.Context) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldProvisionerJobLogs(_ context.Context, arg database.DeleteOldProvisionerJobLogsParams) (int64, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	old := q.oldProvisionerJobLogsNoLock(arg.BeforeTime)
+	if len(old) > int(arg.LimitCount) {
+		old = old[:arg.LimitCount]
+	}
+	deleted := make(map[int64]struct{}, len(old))
+	for _, log := range old {
+		deleted[log.ID] = struct{}{}
+	}
+	var validLogs []database.ProvisionerJobLog
+	for _, log := range q.provisionerJobLogs {
+		if _, ok := deleted[log.ID]; ok {
+			continue
+		}
+		validLogs = append(validLogs, log)
+	}
+	q.provisionerJobLogs = validLogs
+	return int64(len(deleted)), nil
+}
+
 func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1208,6 +1534,138 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldWorkspaceBuilds(_ context.Context, arg database.DeleteOldWorkspaceBuildsParams) (int64, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	jobIDs := q.oldWorkspaceBuildJobIDsNoLock(arg.KeepCount)
+	if len(jobIDs) > int(arg.LimitCount) {
+		jobIDs = jobIDs[:arg.LimitCount]
+	}
+	deletedJobs := make(map[uuid.UUID]struct{}, len(jobIDs))
+	for _, id := range jobIDs {
+		deletedJobs[id] = struct{}{}
+	}
+
+	// Emulate the cascading deletes of the provisioner job.
+	deletedBuilds := make(map[uuid.UUID]struct{})
+	deletedResources := make(map[uuid.UUID]struct{})
+	deletedAgents := make(map[uuid.UUID]struct{})
+	q.provisionerJobs = slices.DeleteFunc(q.provisionerJobs, func(job database.ProvisionerJob) bool {
+		_, ok := deletedJobs[job.ID]
+		return ok
+	})
+	q.provisionerJobLogs = slices.DeleteFunc(q.provisionerJobLogs, func(log database.ProvisionerJobLog) bool {
+		_, ok := deletedJobs[log.JobID]
+		return ok
+	})
+	q.workspaceBuilds = slices.DeleteFunc(q.workspaceBuilds, func(build database.WorkspaceBuildTable) bool {
+		_, ok := deletedJobs[build.JobID]
+		if ok {
+			deletedBuilds[build.ID] = struct{}{}
+		}
+		return ok
+	})
+	q.workspaceBuildParameters = slices.DeleteFunc(q.workspaceBuildParameters, func(param database.WorkspaceBuildParameter) bool {
+		_, ok := deletedBuilds[param.WorkspaceBuildID]
+		return ok
+	})
+	q.workspaceResources = slices.DeleteFunc(q.workspaceResources, func(resource database.WorkspaceResource) bool {
+		_, ok := deletedJobs[resource.JobID]
+		if ok {
+			deletedResources[resource.ID] = struct{}{}
+		}
+		return ok
+	})
+	q.workspaceResourceMetadata = slices.DeleteFunc(q.workspaceResourceMetadata, func(metadata database.WorkspaceResourceMetadatum) bool {
+		_, ok := deletedResources[metadata.WorkspaceResourceID]
+		return ok
+	})
+	q.workspaceAgents = slices.DeleteFunc(q.workspaceAgents, func(agent database.WorkspaceAgent) bool {
+		_, ok := deletedResources[agent.ResourceID]
+		if ok {
+			deletedAgents[agent.ID] = struct{}{}
+		}
+		return ok
+	})
+	q.workspaceApps = slices.DeleteFunc(q.workspaceApps, func(app database.WorkspaceApp) bool {
+		_, ok := deletedAgents[app.AgentID]
+		return ok
+	})
+	return int64(len(deletedJobs)), nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspacePortShares(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	now := dbtime.Now()
+	q.workspacePortShares = slices.DeleteFunc(q.workspacePortShares, func(share database.WorkspacePortShare) bool {
+		return share.ExpiresAt.Before(now)
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceSessionRecordings(_ context.Context, arg database.DeleteOldWorkspaceSessionRecordingsParams) (int64, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	old := q.oldWorkspaceSessionRecordingsNoLock(arg.BeforeTime)
+	if len(old) > int(arg.LimitCount) {
+		old = old[:arg.LimitCount]
+	}
+	deleted := make(map[uuid.UUID]struct{}, len(old))
+	for _, recording := range old {
+		deleted[recording.ID] = struct{}{}
+	}
+	q.workspaceSessionRecordings = slices.DeleteFunc(q.workspaceSessionRecordings, func(recording database.WorkspaceSessionRecording) bool {
+		_, ok := deleted[recording.ID]
+		return ok
+	})
+	return int64(len(deleted)), nil
+}
+
+func (q *FakeQuerier) DeleteOrganizationMember(_ context.Context, arg database.DeleteOrganizationMemberParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.organizationMembers = slices.DeleteFunc(q.organizationMembers, func(member database.OrganizationMember) bool {
+		return member.OrganizationID == arg.OrganizationID && member.UserID == arg.UserID
+	})
+	return nil
+}
+
+func (q *FakeQuerier) DeleteProvisionerKey(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range q.provisionerKeys {
+		if key.ID != id {
+			continue
+		}
+		q.provisionerKeys = append(q.provisionerKeys[:i], q.provisionerKeys[i+1:]...)
+		for j, daemon := range q.provisionerDaemons {
+			if daemon.KeyID.Valid && daemon.KeyID.UUID == id {
+				q.provisionerDaemons[j].KeyID = uuid.NullUUID{}
+			}
+		}
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) DeleteReplicasUpdatedBefore(_ context.Context, before time.Time) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1251,6 +1709,64 @@ func (*FakeQuerier) DeleteTailnetTunnel(_ context.Context, arg database.DeleteTa
 	return database.DeleteTailnetTunnelRow{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteTemplateGitSource(_ context.Context, templateID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, source := range q.templateGitSources {
+		if source.TemplateID == templateID {
+			q.templateGitSources = append(q.templateGitSources[:i], q.templateGitSources[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspacePortShareByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, share := range q.workspacePortShares {
+		if share.ID == id {
+			q.workspacePortShares = append(q.workspacePortShares[:i], q.workspacePortShares[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) EnqueueNotificationMessage(_ context.Context, arg database.EnqueueNotificationMessageParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if arg.DedupeKey.Valid {
+		for _, msg := range q.notificationMessages {
+			if msg.DedupeKey.Valid && msg.DedupeKey.String == arg.DedupeKey.String {
+				// ON CONFLICT DO NOTHING
+				return nil
+			}
+		}
+	}
+
+	q.notificationMessages = append(q.notificationMessages, database.NotificationMessage{
+		ID:        arg.ID,
+		Event:     arg.Event,
+		UserID:    arg.UserID,
+		Method:    arg.Method,
+		Status:    database.NotificationMessageStatusPending,
+		Payload:   arg.Payload,
+		DedupeKey: arg.DedupeKey,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) GetAPIKeyByID(_ context.Context, id string) (database.APIKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1358,42 +1874,144 @@ func (q *FakeQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context,
 		if build.Transition == database.WorkspaceTransitionStart {
 			filteredBuilds = append(filteredBuilds, build)
 		}
-	}
-	return filteredBuilds, nil
-}
-
-func (*FakeQuerier) GetAllTailnetAgents(_ context.Context) ([]database.TailnetAgent, error) {
-	return nil, ErrUnimplemented
-}
-
-func (*FakeQuerier) GetAllTailnetCoordinators(context.Context) ([]database.TailnetCoordinator, error) {
-	return nil, ErrUnimplemented
-}
-
-func (*FakeQuerier) GetAllTailnetPeers(context.Context) ([]database.TailnetPeer, error) {
-	return nil, ErrUnimplemented
-}
-
-func (*FakeQuerier) GetAllTailnetTunnels(context.Context) ([]database.TailnetTunnel, error) {
-	return nil, ErrUnimplemented
-}
-
-func (q *FakeQuerier) GetAppSecurityKey(_ context.Context) (string, error) {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
+	}
+	return filteredBuilds, nil
+}
+
+func (*FakeQuerier) GetAllTailnetAgents(_ context.Context) ([]database.TailnetAgent, error) {
+	return nil, ErrUnimplemented
+}
+
+func (*FakeQuerier) GetAllTailnetCoordinators(context.Context) ([]database.TailnetCoordinator, error) {
+	return nil, ErrUnimplemented
+}
+
+func (*FakeQuerier) GetAllTailnetPeers(context.Context) ([]database.TailnetPeer, error) {
+	return nil, ErrUnimplemented
+}
+
+func (*FakeQuerier) GetAllTailnetTunnels(context.Context) ([]database.TailnetTunnel, error) {
+	return nil, ErrUnimplemented
+}
+
+func (q *FakeQuerier) GetAppSecurityKey(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.appSecurityKey, nil
+}
+
+func (q *FakeQuerier) GetApplicationName(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.applicationName == "" {
+		return "", sql.ErrNoRows
+	}
+
+	return q.applicationName, nil
+}
+
+func (q *FakeQuerier) GetAuditLogsBefore(_ context.Context, arg database.GetAuditLogsBeforeParams) ([]database.GetAuditLogsBeforeRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	alogs := make([]database.AuditLog, len(q.auditLogs))
+	copy(alogs, q.auditLogs)
+	slices.SortStableFunc(alogs, func(a, b database.AuditLog) int {
+		if !a.Time.Equal(b.Time) {
+			return b.Time.Compare(a.Time)
+		}
+		return bytes.Compare(b.ID[:], a.ID[:])
+	})
+
+	logs := make([]database.GetAuditLogsBeforeRow, 0, arg.RowLimit)
+	for _, alog := range alogs {
+		if !arg.BeforeTime.IsZero() {
+			if alog.Time.After(arg.BeforeTime) {
+				continue
+			}
+			if alog.Time.Equal(arg.BeforeTime) && bytes.Compare(alog.ID[:], arg.BeforeID[:]) >= 0 {
+				continue
+			}
+		}
+		if arg.Action != "" && !strings.Contains(string(alog.Action), arg.Action) {
+			continue
+		}
+		if arg.ResourceType != "" && !strings.Contains(string(alog.ResourceType), arg.ResourceType) {
+			continue
+		}
+		if arg.ResourceID != uuid.Nil && alog.ResourceID != arg.ResourceID {
+			continue
+		}
+		if arg.ResourceTarget != "" && alog.ResourceTarget != arg.ResourceTarget {
+			continue
+		}
+		if arg.UserID != uuid.Nil && alog.UserID != arg.UserID {
+			continue
+		}
+		if arg.Username != "" {
+			user, err := q.getUserByIDNoLock(alog.UserID)
+			if err == nil && !strings.EqualFold(arg.Username, user.Username) {
+				continue
+			}
+		}
+		if arg.Email != "" {
+			user, err := q.getUserByIDNoLock(alog.UserID)
+			if err == nil && !strings.EqualFold(arg.Email, user.Email) {
+				continue
+			}
+		}
+		if !arg.DateFrom.IsZero() && alog.Time.Before(arg.DateFrom) {
+			continue
+		}
+		if !arg.DateTo.IsZero() && alog.Time.After(arg.DateTo) {
+			continue
+		}
+		if arg.BuildReason != "" {
+			workspaceBuild, err := q.getWorkspaceBuildByIDNoLock(context.Background(), alog.ResourceID)
+			if err == nil && !strings.EqualFold(arg.BuildReason, string(workspaceBuild.Reason)) {
+				continue
+			}
+		}
 
-	return q.appSecurityKey, nil
-}
+		user, err := q.getUserByIDNoLock(alog.UserID)
+		userValid := err == nil
 
-func (q *FakeQuerier) GetApplicationName(_ context.Context) (string, error) {
-	q.mutex.RLock()
-	defer q.mutex.RUnlock()
+		logs = append(logs, database.GetAuditLogsBeforeRow{
+			ID:               alog.ID,
+			Time:             alog.Time,
+			RequestID:        alog.RequestID,
+			OrganizationID:   alog.OrganizationID,
+			Ip:               alog.Ip,
+			UserAgent:        alog.UserAgent,
+			ResourceType:     alog.ResourceType,
+			ResourceID:       alog.ResourceID,
+			ResourceTarget:   alog.ResourceTarget,
+			ResourceIcon:     alog.ResourceIcon,
+			Action:           alog.Action,
+			Diff:             alog.Diff,
+			StatusCode:       alog.StatusCode,
+			AdditionalFields: alog.AdditionalFields,
+			UserID:           alog.UserID,
+			UserUsername:     sql.NullString{String: user.Username, Valid: userValid},
+			UserEmail:        sql.NullString{String: user.Email, Valid: userValid},
+			UserCreatedAt:    sql.NullTime{Time: user.CreatedAt, Valid: userValid},
+			UserStatus:       database.NullUserStatus{UserStatus: user.Status, Valid: userValid},
+			UserRoles:        user.RBACRoles,
+			UserAvatarUrl:    sql.NullString{String: user.AvatarURL, Valid: userValid},
+		})
 
-	if q.applicationName == "" {
-		return "", sql.ErrNoRows
+		if len(logs) >= int(arg.RowLimit) {
+			break
+		}
 	}
 
-	return q.applicationName, nil
+	return logs, nil
 }
 
 func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
@@ -2011,6 +2629,17 @@ func (q *FakeQuerier) GetOAuthSigningKey(_ context.Context) (string, error) {
 	return q.oauthSigningKey, nil
 }
 
+func (q *FakeQuerier) GetOIDCSyncRules(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.oidcSyncRules == nil {
+		return "{}", nil
+	}
+
+	return string(q.oidcSyncRules), nil
+}
+
 func (q *FakeQuerier) GetOrganizationByID(_ context.Context, id uuid.UUID) (database.Organization, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2078,6 +2707,33 @@ func (q *FakeQuerier) GetOrganizationMemberByUserID(_ context.Context, arg datab
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetOrganizationMembers(_ context.Context, organizationID uuid.UUID) ([]database.GetOrganizationMembersRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	members := make([]database.GetOrganizationMembersRow, 0)
+	for _, member := range q.organizationMembers {
+		if member.OrganizationID != organizationID {
+			continue
+		}
+		user, err := q.getUserByIDNoLock(member.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user.Deleted {
+			continue
+		}
+		members = append(members, database.GetOrganizationMembersRow{
+			OrganizationMember: member,
+			Username:           user.Username,
+		})
+	}
+	slices.SortFunc(members, func(a, b database.GetOrganizationMembersRow) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+	return members, nil
+}
+
 func (q *FakeQuerier) GetOrganizationMembershipsByUserID(_ context.Context, userID uuid.UUID) ([]database.OrganizationMember, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2221,6 +2877,38 @@ func (q *FakeQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (
 	return q.getProvisionerJobByIDNoLock(ctx, id)
 }
 
+func (q *FakeQuerier) GetProvisionerJobResourceChangesByJobID(_ context.Context, jobID uuid.UUID) ([]database.ProvisionerJobResourceChange, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	changes := make([]database.ProvisionerJobResourceChange, 0)
+	for _, change := range q.provisionerJobResourceChanges {
+		if change.JobID == jobID {
+			changes = append(changes, change)
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b database.ProvisionerJobResourceChange) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+	return changes, nil
+}
+
+func (q *FakeQuerier) GetProvisionerJobTimingsByJobID(_ context.Context, jobID uuid.UUID) ([]database.ProvisionerJobTiming, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	timings := make([]database.ProvisionerJobTiming, 0)
+	for _, timing := range q.provisionerJobTimings {
+		if timing.JobID == jobID {
+			timings = append(timings, timing)
+		}
+	}
+	slices.SortStableFunc(timings, func(a, b database.ProvisionerJobTiming) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return timings, nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobsByIDs(_ context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2249,34 +2937,38 @@ func (q *FakeQuerier) GetProvisionerJobsByIDsWithQueuePosition(_ context.Context
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	jobs := make([]database.GetProvisionerJobsByIDsWithQueuePositionRow, 0)
-	queuePosition := int64(1)
+	var queue []database.ProvisionerJob
 	for _, job := range q.provisionerJobs {
-		for _, id := range ids {
-			if id == job.ID {
-				// clone the Tags before appending, since maps are reference types and
-				// we don't want the caller to be able to mutate the map we have inside
-				// dbmem!
-				job.Tags = maps.Clone(job.Tags)
-				job := database.GetProvisionerJobsByIDsWithQueuePositionRow{
-					ProvisionerJob: job,
-				}
-				if !job.ProvisionerJob.StartedAt.Valid {
-					job.QueuePosition = queuePosition
-				}
-				jobs = append(jobs, job)
-				break
-			}
-		}
 		if !job.StartedAt.Valid {
-			queuePosition++
+			queue = append(queue, job)
 		}
 	}
-	for _, job := range jobs {
-		if !job.ProvisionerJob.StartedAt.Valid {
-			// Set it to the max position!
-			job.QueueSize = queuePosition
+	slices.SortStableFunc(queue, func(a, b database.ProvisionerJob) int {
+		if c := provisionerJobPriorityRank(b.Priority) - provisionerJobPriorityRank(a.Priority); c != 0 {
+			return c
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	jobs := make([]database.GetProvisionerJobsByIDsWithQueuePositionRow, 0)
+	for _, job := range q.provisionerJobs {
+		if !slices.Contains(ids, job.ID) {
+			continue
 		}
+		// clone the Tags before appending, since maps are reference types and
+		// we don't want the caller to be able to mutate the map we have inside
+		// dbmem!
+		job.Tags = maps.Clone(job.Tags)
+		row := database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: job,
+			QueueSize:      int64(len(queue)),
+		}
+		if !job.StartedAt.Valid {
+			row.QueuePosition = int64(slices.IndexFunc(queue, func(queued database.ProvisionerJob) bool {
+				return queued.ID == job.ID
+			}) + 1)
+		}
+		jobs = append(jobs, row)
 	}
 	return jobs, nil
 }
@@ -2298,6 +2990,34 @@ func (q *FakeQuerier) GetProvisionerJobsCreatedAfter(_ context.Context, after ti
 	return jobs, nil
 }
 
+func (q *FakeQuerier) GetProvisionerKeyByID(_ context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetProvisionerKeyByName(_ context.Context, arg database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == arg.OrganizationID && strings.EqualFold(key.Name, arg.Name) {
+			return key, nil
+		}
+	}
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetProvisionerLogsAfterID(_ context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -2397,6 +3117,19 @@ func (q *FakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.
 	return replicas, nil
 }
 
+func (q *FakeQuerier) GetSensitiveTemplateVersionVariables(_ context.Context) ([]database.TemplateVersionVariable, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	variables := make([]database.TemplateVersionVariable, 0)
+	for _, variable := range q.templateVersionVariables {
+		if variable.Sensitive {
+			variables = append(variables, variable)
+		}
+	}
+	return variables, nil
+}
+
 func (q *FakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2780,6 +3513,29 @@ func (q *FakeQuerier) GetTemplateDAUs(_ context.Context, arg database.GetTemplat
 	return rs, nil
 }
 
+func (q *FakeQuerier) GetTemplateGitSourceByTemplateID(_ context.Context, templateID uuid.UUID) (database.TemplateGitSource, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, source := range q.templateGitSources {
+		if source.TemplateID == templateID {
+			return source, nil
+		}
+	}
+	return database.TemplateGitSource{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateGitSources(_ context.Context) ([]database.TemplateGitSource, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	sources := slices.Clone(q.templateGitSources)
+	slices.SortFunc(sources, func(a, b database.TemplateGitSource) int {
+		return slice.Ascending(a.TemplateID.String(), b.TemplateID.String())
+	})
+	return sources, nil
+}
+
 func (q *FakeQuerier) GetTemplateInsights(_ context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -3928,6 +4684,22 @@ func (q *FakeQuerier) GetWorkspaceAgentMetadata(_ context.Context, arg database.
 	return metadata, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentScriptTimingsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScriptTiming, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	timings := make([]database.WorkspaceAgentScriptTiming, 0)
+	for _, timing := range q.workspaceAgentScriptTimings {
+		if slices.Contains(ids, timing.AgentID) {
+			timings = append(timings, timing)
+		}
+	}
+	slices.SortStableFunc(timings, func(a, b database.WorkspaceAgentScriptTiming) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return timings, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentScriptsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4236,6 +5008,26 @@ func (q *FakeQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(_ context.Con
 	return database.WorkspaceBuild{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceBuildIDsWithProvisionerState(_ context.Context) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	builds := make([]database.WorkspaceBuildTable, 0)
+	for _, build := range q.workspaceBuilds {
+		if len(build.ProvisionerState) > 0 {
+			builds = append(builds, build)
+		}
+	}
+	slices.SortFunc(builds, func(a, b database.WorkspaceBuildTable) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	ids := make([]uuid.UUID, 0, len(builds))
+	for _, build := range builds {
+		ids = append(ids, build.ID)
+	}
+	return ids, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceBuildParameters(_ context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4401,6 +5193,35 @@ func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceA
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspacePortShareByID(_ context.Context, id uuid.UUID) (database.WorkspacePortShare, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, share := range q.workspacePortShares {
+		if share.ID == id {
+			return share, nil
+		}
+	}
+	return database.WorkspacePortShare{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspacePortSharesByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspacePortShare, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	now := dbtime.Now()
+	shares := make([]database.WorkspacePortShare, 0)
+	for _, share := range q.workspacePortShares {
+		if share.WorkspaceID == workspaceID && share.ExpiresAt.After(now) {
+			shares = append(shares, share)
+		}
+	}
+	slices.SortFunc(shares, func(a, b database.WorkspacePortShare) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return shares, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceProxies(_ context.Context) ([]database.WorkspaceProxy, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4565,6 +5386,59 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceSessionRecordingByID(_ context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.ID == id {
+			return recording, nil
+		}
+	}
+	return database.WorkspaceSessionRecording{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingIDs(_ context.Context) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	recordings := slices.Clone(q.workspaceSessionRecordings)
+	slices.SortFunc(recordings, func(a, b database.WorkspaceSessionRecording) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	ids := make([]uuid.UUID, 0, len(recordings))
+	for _, recording := range recordings {
+		ids = append(ids, recording.ID)
+	}
+	return ids, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceSessionRecordingsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceSessionRecordingsByWorkspaceIDRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspaceSessionRecordingsByWorkspaceIDRow, 0)
+	for _, recording := range q.workspaceSessionRecordings {
+		if recording.WorkspaceID != workspaceID {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceSessionRecordingsByWorkspaceIDRow{
+			ID:               recording.ID,
+			WorkspaceID:      recording.WorkspaceID,
+			WorkspaceBuildID: recording.WorkspaceBuildID,
+			AgentID:          recording.AgentID,
+			Type:             recording.Type,
+			StartedAt:        recording.StartedAt,
+			EndedAt:          recording.EndedAt,
+			Size:             recording.Size,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceSessionRecordingsByWorkspaceIDRow) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(_ context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4600,9 +5474,55 @@ func (q *FakeQuerier) GetWorkspaces(ctx context.Context, arg database.GetWorkspa
 		return nil, err
 	}
 
-	// A nil auth filter means no auth filter.
-	workspaceRows, err := q.GetAuthorizedWorkspaces(ctx, arg, nil)
-	return workspaceRows, err
+	// A nil auth filter means no auth filter.
+	workspaceRows, err := q.GetAuthorizedWorkspaces(ctx, arg, nil)
+	return workspaceRows, err
+}
+
+func (q *FakeQuerier) GetWorkspacesApproachingDeadline(ctx context.Context, arg database.GetWorkspacesApproachingDeadlineParams) ([]database.GetWorkspacesApproachingDeadlineRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspacesApproachingDeadlineRow, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, xerrors.Errorf("get provisioner job by ID: %w", err)
+		}
+
+		autostopSoon := build.Transition == database.WorkspaceTransitionStart &&
+			job.JobStatus == database.ProvisionerJobStatusSucceeded &&
+			!workspace.DormantAt.Valid &&
+			build.Deadline.After(arg.Now) &&
+			!build.Deadline.After(arg.AutostopBefore)
+		autodeleteSoon := workspace.DeletingAt.Valid &&
+			workspace.DeletingAt.Time.After(arg.Now) &&
+			!workspace.DeletingAt.Time.After(arg.AutodeleteBefore)
+		if !autostopSoon && !autodeleteSoon {
+			continue
+		}
+
+		rows = append(rows, database.GetWorkspacesApproachingDeadlineRow{
+			ID:         workspace.ID,
+			OwnerID:    workspace.OwnerID,
+			Name:       workspace.Name,
+			Deadline:   build.Deadline,
+			DeletingAt: workspace.DeletingAt,
+		})
+	}
+	return rows, nil
 }
 
 func (q *FakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
@@ -4677,18 +5597,20 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 
 	//nolint:gosimple
 	key := database.APIKey{
-		ID:              arg.ID,
-		LifetimeSeconds: arg.LifetimeSeconds,
-		HashedSecret:    arg.HashedSecret,
-		IPAddress:       arg.IPAddress,
-		UserID:          arg.UserID,
-		ExpiresAt:       arg.ExpiresAt,
-		CreatedAt:       arg.CreatedAt,
-		UpdatedAt:       arg.UpdatedAt,
-		LastUsed:        arg.LastUsed,
-		LoginType:       arg.LoginType,
-		Scope:           arg.Scope,
-		TokenName:       arg.TokenName,
+		ID:               arg.ID,
+		LifetimeSeconds:  arg.LifetimeSeconds,
+		HashedSecret:     arg.HashedSecret,
+		IPAddress:        arg.IPAddress,
+		UserID:           arg.UserID,
+		ExpiresAt:        arg.ExpiresAt,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		LastUsed:         arg.LastUsed,
+		LoginType:        arg.LoginType,
+		Scope:            arg.Scope,
+		TokenName:        arg.TokenName,
+		ScopePermissions: arg.ScopePermissions,
+		ScopeAllowList:   arg.ScopeAllowList,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -5003,6 +5925,8 @@ func (q *FakeQuerier) InsertProvisionerJob(_ context.Context, arg database.Inser
 		Input:          arg.Input,
 		Tags:           maps.Clone(arg.Tags),
 		TraceMetadata:  arg.TraceMetadata,
+		Priority:       arg.Priority,
+		TemplateID:     arg.TemplateID,
 	}
 	job.JobStatus = provisonerJobStatus(job)
 	q.provisionerJobs = append(q.provisionerJobs, job)
@@ -5038,6 +5962,78 @@ func (q *FakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.I
 	return logs, nil
 }
 
+func (q *FakeQuerier) InsertProvisionerJobResourceChanges(_ context.Context, arg database.InsertProvisionerJobResourceChangesParams) ([]database.ProvisionerJobResourceChange, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	changes := make([]database.ProvisionerJobResourceChange, 0, len(arg.Address))
+	for index, address := range arg.Address {
+		changes = append(changes, database.ProvisionerJobResourceChange{
+			JobID:   arg.JobID,
+			Address: address,
+			Type:    arg.Type[index],
+			Name:    arg.Name[index],
+			Action:  arg.Action[index],
+		})
+	}
+	q.provisionerJobResourceChanges = append(q.provisionerJobResourceChanges, changes...)
+	return changes, nil
+}
+
+func (q *FakeQuerier) InsertProvisionerJobTimings(_ context.Context, arg database.InsertProvisionerJobTimingsParams) ([]database.ProvisionerJobTiming, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	timings := make([]database.ProvisionerJobTiming, 0, len(arg.StartedAt))
+	for index, startedAt := range arg.StartedAt {
+		timings = append(timings, database.ProvisionerJobTiming{
+			JobID:     arg.JobID,
+			StartedAt: startedAt,
+			EndedAt:   arg.EndedAt[index],
+			Stage:     arg.Stage[index],
+			Source:    arg.Source[index],
+			Action:    arg.Action[index],
+			Resource:  arg.Resource[index],
+		})
+	}
+	q.provisionerJobTimings = append(q.provisionerJobTimings, timings...)
+	return timings, nil
+}
+
+func (q *FakeQuerier) InsertProvisionerKey(_ context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == arg.OrganizationID && strings.EqualFold(key.Name, arg.Name) {
+			return database.ProvisionerKey{}, errDuplicateKey
+		}
+	}
+
+	key := database.ProvisionerKey{
+		ID:             arg.ID,
+		CreatedAt:      arg.CreatedAt,
+		OrganizationID: arg.OrganizationID,
+		Name:           arg.Name,
+		HashedSecret:   arg.HashedSecret,
+		Tags:           maps.Clone(arg.Tags),
+	}
+	q.provisionerKeys = append(q.provisionerKeys, key)
+	return key, nil
+}
+
 func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Replica{}, err
@@ -5107,16 +6103,18 @@ func (q *FakeQuerier) InsertTemplateVersion(_ context.Context, arg database.Inse
 
 	//nolint:gosimple
 	version := database.TemplateVersionTable{
-		ID:             arg.ID,
-		TemplateID:     arg.TemplateID,
-		OrganizationID: arg.OrganizationID,
-		CreatedAt:      arg.CreatedAt,
-		UpdatedAt:      arg.UpdatedAt,
-		Name:           arg.Name,
-		Message:        arg.Message,
-		Readme:         arg.Readme,
-		JobID:          arg.JobID,
-		CreatedBy:      arg.CreatedBy,
+		ID:               arg.ID,
+		TemplateID:       arg.TemplateID,
+		OrganizationID:   arg.OrganizationID,
+		CreatedAt:        arg.CreatedAt,
+		UpdatedAt:        arg.UpdatedAt,
+		Name:             arg.Name,
+		Message:          arg.Message,
+		Readme:           arg.Readme,
+		JobID:            arg.JobID,
+		CreatedBy:        arg.CreatedBy,
+		GitCommitSHA:     arg.GitCommitSHA,
+		GitCommitMessage: arg.GitCommitMessage,
 	}
 	q.templateVersions = append(q.templateVersions, version)
 	return nil
@@ -5172,6 +6170,7 @@ func (q *FakeQuerier) InsertTemplateVersionVariable(_ context.Context, arg datab
 		DefaultValue:      arg.DefaultValue,
 		Required:          arg.Required,
 		Sensitive:         arg.Sensitive,
+		ValueKeyID:        arg.ValueKeyID,
 	}
 	q.templateVersionVariables = append(q.templateVersionVariables, variable)
 	return variable, nil
@@ -5259,6 +6258,7 @@ func (q *FakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 		OAuthRefreshTokenKeyID: args.OAuthRefreshTokenKeyID,
 		OAuthExpiry:            args.OAuthExpiry,
 		DebugContext:           args.DebugContext,
+		LoginProvider:          args.LoginProvider,
 	}
 
 	q.userLinks = append(q.userLinks, link)
@@ -5287,6 +6287,8 @@ func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		Ttl:               arg.Ttl,
 		LastUsedAt:        arg.LastUsedAt,
 		AutomaticUpdates:  arg.AutomaticUpdates,
+		UserACL:           database.WorkspaceACL{},
+		GroupACL:          database.WorkspaceACL{},
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -5412,6 +6414,28 @@ func (q *FakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentScriptTiming(_ context.Context, arg database.InsertWorkspaceAgentScriptTimingParams) (database.WorkspaceAgentScriptTiming, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceAgentScriptTiming{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	timing := database.WorkspaceAgentScriptTiming{
+		AgentID:     arg.AgentID,
+		LogSourceID: arg.LogSourceID,
+		Stage:       arg.Stage,
+		Status:      arg.Status,
+		ExitCode:    arg.ExitCode,
+		StartedAt:   arg.StartedAt,
+		EndedAt:     arg.EndedAt,
+	}
+	q.workspaceAgentScriptTimings = append(q.workspaceAgentScriptTimings, timing)
+	return timing, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -5596,19 +6620,20 @@ func (q *FakeQuerier) InsertWorkspaceBuild(_ context.Context, arg database.Inser
 	defer q.mutex.Unlock()
 
 	workspaceBuild := database.WorkspaceBuildTable{
-		ID:                arg.ID,
-		CreatedAt:         arg.CreatedAt,
-		UpdatedAt:         arg.UpdatedAt,
-		WorkspaceID:       arg.WorkspaceID,
-		TemplateVersionID: arg.TemplateVersionID,
-		BuildNumber:       arg.BuildNumber,
-		Transition:        arg.Transition,
-		InitiatorID:       arg.InitiatorID,
-		JobID:             arg.JobID,
-		ProvisionerState:  arg.ProvisionerState,
-		Deadline:          arg.Deadline,
-		MaxDeadline:       arg.MaxDeadline,
-		Reason:            arg.Reason,
+		ID:                    arg.ID,
+		CreatedAt:             arg.CreatedAt,
+		UpdatedAt:             arg.UpdatedAt,
+		WorkspaceID:           arg.WorkspaceID,
+		TemplateVersionID:     arg.TemplateVersionID,
+		BuildNumber:           arg.BuildNumber,
+		Transition:            arg.Transition,
+		InitiatorID:           arg.InitiatorID,
+		JobID:                 arg.JobID,
+		ProvisionerState:      arg.ProvisionerState,
+		Deadline:              arg.Deadline,
+		MaxDeadline:           arg.MaxDeadline,
+		Reason:                arg.Reason,
+		ProvisionerStateKeyID: arg.ProvisionerStateKeyID,
 	}
 	q.workspaceBuilds = append(q.workspaceBuilds, workspaceBuild)
 	return nil
@@ -5632,6 +6657,28 @@ func (q *FakeQuerier) InsertWorkspaceBuildParameters(_ context.Context, arg data
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspacePortShare(_ context.Context, arg database.InsertWorkspacePortShareParams) (database.WorkspacePortShare, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspacePortShare{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	share := database.WorkspacePortShare{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		AgentName:   arg.AgentName,
+		Port:        arg.Port,
+		CreatedBy:   arg.CreatedBy,
+		CreatedAt:   arg.CreatedAt,
+		ExpiresAt:   arg.ExpiresAt,
+	}
+	q.workspacePortShares = append(q.workspacePortShares, share)
+	return share, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceProxy(_ context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5718,6 +6765,97 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceSessionRecording(_ context.Context, arg database.InsertWorkspaceSessionRecordingParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	recording := database.WorkspaceSessionRecording{
+		ID:               arg.ID,
+		WorkspaceID:      arg.WorkspaceID,
+		WorkspaceBuildID: arg.WorkspaceBuildID,
+		AgentID:          arg.AgentID,
+		Type:             arg.Type,
+		StartedAt:        arg.StartedAt,
+		EndedAt:          arg.EndedAt,
+		Size:             arg.Size,
+		Data:             arg.Data,
+		DataKeyID:        arg.DataKeyID,
+	}
+	q.workspaceSessionRecordings = append(q.workspaceSessionRecordings, recording)
+	return nil
+}
+
+func (q *FakeQuerier) ListProvisionerKeysByOrganization(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	keys := make([]database.ProvisionerKey, 0)
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == organizationID {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b database.ProvisionerKey) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return keys, nil
+}
+
+func (q *FakeQuerier) MarkNotificationMessageFailed(_ context.Context, arg database.MarkNotificationMessageFailedParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, msg := range q.notificationMessages {
+		if msg.ID != arg.ID {
+			continue
+		}
+		msg.Status = arg.Status
+		msg.StatusReason = sql.NullString{String: arg.StatusReason, Valid: true}
+		msg.AttemptCount++
+		msg.UpdatedAt = arg.UpdatedAt
+		msg.LeasedUntil = sql.NullTime{}
+		msg.NextRetryAfter = arg.NextRetryAfter
+		q.notificationMessages[i] = msg
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) MarkNotificationMessageSent(_ context.Context, arg database.MarkNotificationMessageSentParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, msg := range q.notificationMessages {
+		if msg.ID != arg.ID {
+			continue
+		}
+		msg.Status = database.NotificationMessageStatusSent
+		msg.StatusReason = sql.NullString{}
+		msg.AttemptCount++
+		msg.UpdatedAt = arg.UpdatedAt
+		msg.LeasedUntil = sql.NullTime{}
+		msg.NextRetryAfter = sql.NullTime{}
+		q.notificationMessages[i] = msg
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5945,6 +7083,28 @@ func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMe
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateProvisionerDaemonLastSeenAt(_ context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx := range q.provisionerDaemons {
+		if q.provisionerDaemons[idx].ID != arg.ID {
+			continue
+		}
+		if q.provisionerDaemons[idx].LastSeenAt.Valid && q.provisionerDaemons[idx].LastSeenAt.Time.After(arg.LastSeenAt.Time) {
+			continue
+		}
+		q.provisionerDaemons[idx].LastSeenAt = arg.LastSeenAt
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6118,6 +7278,49 @@ func (q *FakeQuerier) UpdateTemplateDeletedByID(_ context.Context, arg database.
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateGitSourceSyncStatus(_ context.Context, arg database.UpdateTemplateGitSourceSyncStatusParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, source := range q.templateGitSources {
+		if source.TemplateID != arg.TemplateID {
+			continue
+		}
+		source.UpdatedAt = arg.UpdatedAt
+		source.LastCheckedAt = arg.LastCheckedAt
+		source.LastCommitSHA = arg.LastCommitSHA
+		source.LastTemplateVersionID = arg.LastTemplateVersionID
+		source.LastError = arg.LastError
+		q.templateGitSources[i] = source
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateTemplateGitSourceWebhookSecret(_ context.Context, arg database.UpdateTemplateGitSourceWebhookSecretParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, source := range q.templateGitSources {
+		if source.TemplateID != arg.TemplateID {
+			continue
+		}
+		source.WebhookSecret = arg.WebhookSecret
+		source.WebhookSecretKeyID = arg.WebhookSecretKeyID
+		q.templateGitSources[i] = source
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.UpdateTemplateMetaByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6135,6 +7338,7 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.DisplayName = arg.DisplayName
 		tpl.Description = arg.Description
 		tpl.Icon = arg.Icon
+		tpl.RecordSessions = arg.RecordSessions
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -6235,6 +7439,27 @@ func (q *FakeQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(_ contex
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateVersionVariableValue(_ context.Context, arg database.UpdateTemplateVersionVariableValueParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, variable := range q.templateVersionVariables {
+		if variable.TemplateVersionID != arg.TemplateVersionID || variable.Name != arg.Name || !variable.Sensitive {
+			continue
+		}
+		variable.Value = arg.Value
+		variable.ValueKeyID = arg.ValueKeyID
+		q.templateVersionVariables[idx] = variable
+		return nil
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateTemplateWorkspacesLastUsedAt(_ context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -6537,6 +7762,27 @@ func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceACLByID(_ context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.ID == arg.ID {
+			workspace.UserACL = arg.UserACL
+			workspace.GroupACL = arg.GroupACL
+
+			q.workspaces[i] = workspace
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6771,6 +8017,7 @@ func (q *FakeQuerier) UpdateWorkspaceBuildProvisionerStateByID(_ context.Context
 			continue
 		}
 		build.ProvisionerState = arg.ProvisionerState
+		build.ProvisionerStateKeyID = arg.ProvisionerStateKeyID
 		build.UpdatedAt = arg.UpdatedAt
 		q.workspaceBuilds[idx] = build
 		return nil
@@ -6897,6 +8144,28 @@ func (q *FakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg databas
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceSessionRecordingDataByID(_ context.Context, arg database.UpdateWorkspaceSessionRecordingDataByIDParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, recording := range q.workspaceSessionRecordings {
+		if recording.ID != arg.ID {
+			continue
+		}
+		recording.Data = arg.Data
+		recording.DataKeyID = arg.DataKeyID
+		q.workspaceSessionRecordings[idx] = recording
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateWorkspaceTTLParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6971,6 +8240,41 @@ func (q *FakeQuerier) UpsertApplicationName(_ context.Context, data string) erro
 	return nil
 }
 
+func (q *FakeQuerier) UpsertCustomRole(_ context.Context, arg database.UpsertCustomRoleParams) (database.CustomRole, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	name := strings.ToLower(arg.Name)
+	for i, role := range q.customRoles {
+		if role.Name == name && role.OrganizationID == arg.OrganizationID {
+			role.DisplayName = arg.DisplayName
+			role.SitePermissions = arg.SitePermissions
+			role.OrgPermissions = arg.OrgPermissions
+			role.UserPermissions = arg.UserPermissions
+			role.UpdatedAt = dbtime.Now()
+			q.customRoles[i] = role
+			return role, nil
+		}
+	}
+
+	role := database.CustomRole{
+		Name:            name,
+		DisplayName:     arg.DisplayName,
+		OrganizationID:  arg.OrganizationID,
+		SitePermissions: arg.SitePermissions,
+		OrgPermissions:  arg.OrgPermissions,
+		UserPermissions: arg.UserPermissions,
+		CreatedAt:       dbtime.Now(),
+		UpdatedAt:       dbtime.Now(),
+	}
+	q.customRoles = append(q.customRoles, role)
+	return role, nil
+}
+
 func (q *FakeQuerier) UpsertDefaultProxy(_ context.Context, arg database.UpsertDefaultProxyParams) error {
 	q.defaultProxyDisplayName = arg.DisplayName
 	q.defaultProxyIconURL = arg.IconUrl
@@ -7009,6 +8313,14 @@ func (q *FakeQuerier) UpsertOAuthSigningKey(_ context.Context, value string) err
 	return nil
 }
 
+func (q *FakeQuerier) UpsertOIDCSyncRules(_ context.Context, value string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.oidcSyncRules = []byte(value)
+	return nil
+}
+
 func (q *FakeQuerier) UpsertProvisionerDaemon(_ context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -7017,7 +8329,7 @@ func (q *FakeQuerier) UpsertProvisionerDaemon(_ context.Context, arg database.Up
 
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
-	for _, d := range q.provisionerDaemons {
+	for i, d := range q.provisionerDaemons {
 		if d.Name == arg.Name {
 			if d.Tags[provisionersdk.TagScope] == provisionersdk.ScopeOrganization && arg.Tags[provisionersdk.TagOwner] != "" {
 				continue
@@ -7028,7 +8340,10 @@ func (q *FakeQuerier) UpsertProvisionerDaemon(_ context.Context, arg database.Up
 			d.Provisioners = arg.Provisioners
 			d.Tags = maps.Clone(arg.Tags)
 			d.Version = arg.Version
+			d.APIVersion = arg.APIVersion
 			d.LastSeenAt = arg.LastSeenAt
+			d.KeyID = arg.KeyID
+			q.provisionerDaemons[i] = d
 			return d, nil
 		}
 	}
@@ -7041,6 +8356,8 @@ func (q *FakeQuerier) UpsertProvisionerDaemon(_ context.Context, arg database.Up
 		ReplicaID:    uuid.NullUUID{},
 		LastSeenAt:   arg.LastSeenAt,
 		Version:      arg.Version,
+		APIVersion:   arg.APIVersion,
+		KeyID:        arg.KeyID,
 	}
 	q.provisionerDaemons = append(q.provisionerDaemons, d)
 	return d, nil
@@ -7088,6 +8405,49 @@ func (*FakeQuerier) UpsertTailnetTunnel(_ context.Context, arg database.UpsertTa
 	return database.TailnetTunnel{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) UpsertTemplateGitSource(_ context.Context, arg database.UpsertTemplateGitSourceParams) (database.TemplateGitSource, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TemplateGitSource{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, source := range q.templateGitSources {
+		if source.TemplateID != arg.TemplateID {
+			continue
+		}
+		source.UpdatedAt = arg.UpdatedAt
+		source.CreatedBy = arg.CreatedBy
+		source.RepositoryURL = arg.RepositoryURL
+		source.Branch = arg.Branch
+		source.Subdirectory = arg.Subdirectory
+		source.AutoPromote = arg.AutoPromote
+		source.PollInterval = arg.PollInterval
+		source.WebhookSecret = arg.WebhookSecret
+		source.WebhookSecretKeyID = arg.WebhookSecretKeyID
+		q.templateGitSources[i] = source
+		return source, nil
+	}
+
+	//nolint:gosimple
+	source := database.TemplateGitSource{
+		TemplateID:         arg.TemplateID,
+		CreatedAt:          arg.CreatedAt,
+		UpdatedAt:          arg.UpdatedAt,
+		CreatedBy:          arg.CreatedBy,
+		RepositoryURL:      arg.RepositoryURL,
+		Branch:             arg.Branch,
+		Subdirectory:       arg.Subdirectory,
+		AutoPromote:        arg.AutoPromote,
+		PollInterval:       arg.PollInterval,
+		WebhookSecret:      arg.WebhookSecret,
+		WebhookSecretKeyID: arg.WebhookSecretKeyID,
+	}
+	q.templateGitSources = append(q.templateGitSources, source)
+	return source, nil
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -7240,7 +8600,7 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 
 	if prepared != nil {
 		// Call this to match the same function calls as the SQL implementation.
-		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWithoutACL())
+		_, err := prepared.CompileToSQL(ctx, rbac.ConfigWithACL())
 		if err != nil {
 			return nil, err
 		}
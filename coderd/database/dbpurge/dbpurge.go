@@ -6,29 +6,61 @@ import (
 	"io"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 const (
 	delay = 10 * time.Minute
+	// batchSize is the maximum number of rows deleted by a single statement
+	// when enforcing retention, so large tables are not locked for long.
+	batchSize = 1000
 )
 
+// Options configures the purging of records with a configurable retention.
+type Options struct {
+	Retention  codersdk.RetentionConfig
+	Registerer prometheus.Registerer
+}
+
 // New creates a new periodically purging database instance.
 // It is the caller's responsibility to call Close on the returned instance.
 //
 // This is for cleaning up old, unused resources from the database that take up space.
-func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
+func New(ctx context.Context, logger slog.Logger, db database.Store, opts Options) io.Closer {
 	closed := make(chan struct{})
 
 	ctx, cancelFunc := context.WithCancel(ctx)
 	//nolint:gocritic // The system purges old db records without user input.
 	ctx = dbauthz.AsSystemRestricted(ctx)
 
+	factory := promauto.With(opts.Registerer)
+	r := &retention{
+		db:     db,
+		config: opts.Retention,
+		deletedRows: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "dbpurge",
+			Name:      "deleted_rows_total",
+			Help:      "The number of rows deleted because they exceeded the configured retention.",
+		}, []string{"table"}),
+		expiredRows: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "dbpurge",
+			Name:      "expired_rows",
+			Help:      "The number of rows that exceed the configured retention and would be deleted. Only set when retention is in dry-run mode.",
+		}, []string{"table"}),
+	}
+
 	// Use time.Nanosecond to force an initial tick. It will be reset to the
 	// correct duration after executing once.
 	ticker := time.NewTicker(time.Nanosecond)
@@ -45,6 +77,15 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 		eg.Go(func() error {
 			return db.DeleteOldProvisionerDaemons(ctx)
 		})
+		eg.Go(func() error {
+			return db.DeleteOldNotificationMessages(ctx)
+		})
+		eg.Go(func() error {
+			return db.DeleteOldWorkspacePortShares(ctx)
+		})
+		eg.Go(func() error {
+			return r.enforce(ctx)
+		})
 		err := eg.Wait()
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
@@ -73,6 +114,103 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 	}
 }
 
+// retention deletes records that are older than the configured retention.
+type retention struct {
+	db          database.Store
+	config      codersdk.RetentionConfig
+	deletedRows *prometheus.CounterVec
+	expiredRows *prometheus.GaugeVec
+}
+
+func (r *retention) enforce(ctx context.Context) error {
+	now := dbtime.Now()
+	if window := r.config.AuditLogs.Value(); window > 0 {
+		before := now.Add(-window)
+		err := r.purge(ctx, "audit_logs", func() (int64, error) {
+			return r.db.CountOldAuditLogs(ctx, before)
+		}, func() (int64, error) {
+			return r.db.DeleteOldAuditLogs(ctx, database.DeleteOldAuditLogsParams{
+				BeforeTime: before,
+				LimitCount: batchSize,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if window := r.config.ProvisionerJobLogs.Value(); window > 0 {
+		before := now.Add(-window)
+		err := r.purge(ctx, "provisioner_job_logs", func() (int64, error) {
+			return r.db.CountOldProvisionerJobLogs(ctx, before)
+		}, func() (int64, error) {
+			return r.db.DeleteOldProvisionerJobLogs(ctx, database.DeleteOldProvisionerJobLogsParams{
+				BeforeTime: before,
+				LimitCount: batchSize,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	// Recordings are purged before builds, since builds with recordings
+	// are kept.
+	if window := r.config.SessionRecordings.Value(); window > 0 {
+		before := now.Add(-window)
+		err := r.purge(ctx, "workspace_session_recordings", func() (int64, error) {
+			return r.db.CountOldWorkspaceSessionRecordings(ctx, before)
+		}, func() (int64, error) {
+			return r.db.DeleteOldWorkspaceSessionRecordings(ctx, database.DeleteOldWorkspaceSessionRecordingsParams{
+				BeforeTime: before,
+				LimitCount: batchSize,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if keep := r.config.WorkspaceBuilds.Value(); keep > 0 {
+		err := r.purge(ctx, "workspace_builds", func() (int64, error) {
+			return r.db.CountOldWorkspaceBuilds(ctx, int32(keep))
+		}, func() (int64, error) {
+			return r.db.DeleteOldWorkspaceBuilds(ctx, database.DeleteOldWorkspaceBuildsParams{
+				KeepCount:  int32(keep),
+				LimitCount: batchSize,
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purge calls deleteBatch until there is nothing left to delete. In dry-run
+// mode the expired rows are only counted.
+func (r *retention) purge(ctx context.Context, table string, count, deleteBatch func() (int64, error)) error {
+	if r.config.DryRun.Value() {
+		expired, err := count()
+		if err != nil {
+			return xerrors.Errorf("count expired %s: %w", table, err)
+		}
+		r.expiredRows.WithLabelValues(table).Set(float64(expired))
+		return nil
+	}
+
+	for {
+		deleted, err := deleteBatch()
+		if err != nil {
+			return xerrors.Errorf("delete expired %s: %w", table, err)
+		}
+		r.deletedRows.WithLabelValues(table).Add(float64(deleted))
+		if deleted < batchSize {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
 type instance struct {
 	cancel context.CancelFunc
 	closed chan struct{}
@@ -7,18 +7,21 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"golang.org/x/exp/slices"
 
 	"cdr.dev/slog/sloggers/slogtest"
 
+	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmem"
 	"github.com/coder/coder/v2/coderd/database/dbpurge"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -30,7 +33,7 @@ func TestMain(m *testing.M) {
 // Ensures no goroutines leak.
 func TestPurge(t *testing.T) {
 	t.Parallel()
-	purger := dbpurge.New(context.Background(), slogtest.Make(t, nil), dbmem.New())
+	purger := dbpurge.New(context.Background(), slogtest.Make(t, nil), dbmem.New(), dbpurge.Options{})
 	err := purger.Close()
 	require.NoError(t, err)
 }
@@ -63,7 +66,7 @@ func TestDeleteOldWorkspaceAgentStats(t *testing.T) {
 	})
 
 	// when
-	closer := dbpurge.New(ctx, logger, db)
+	closer := dbpurge.New(ctx, logger, db, dbpurge.Options{})
 	defer closer.Close()
 
 	// then
@@ -109,7 +112,7 @@ func TestDeleteOldWorkspaceAgentLogs(t *testing.T) {
 		agent := mustCreateAgentWithLogs(ctx, t, db, user, org, tmpl, tv, now.Add(-8*24*time.Hour), t.Name())
 
 		// when
-		closer := dbpurge.New(ctx, logger, db)
+		closer := dbpurge.New(ctx, logger, db, dbpurge.Options{})
 		defer closer.Close()
 
 		// then
@@ -134,7 +137,7 @@ func TestDeleteOldWorkspaceAgentLogs(t *testing.T) {
 		agent := mustCreateAgentWithLogs(ctx, t, db, user, org, tmpl, tv, now.Add(-6*24*time.Hour), t.Name())
 
 		// when
-		closer := dbpurge.New(ctx, logger, db)
+		closer := dbpurge.New(ctx, logger, db, dbpurge.Options{})
 		defer closer.Close()
 
 		// then
@@ -261,7 +264,7 @@ func TestDeleteOldProvisionerDaemons(t *testing.T) {
 	require.NoError(t, err)
 
 	// when
-	closer := dbpurge.New(ctx, logger, db)
+	closer := dbpurge.New(ctx, logger, db, dbpurge.Options{})
 	defer closer.Close()
 
 	// then
@@ -282,3 +285,213 @@ func containsProvisionerDaemon(daemons []database.ProvisionerDaemon, name string
 		return d.Name == name
 	})
 }
+
+func TestDeleteOldAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	now := dbtime.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	// given
+	// More expired logs than fit in a single batch.
+	for i := 0; i < 1500; i++ {
+		dbgen.AuditLog(t, db, database.AuditLog{Time: now.Add(-31 * 24 * time.Hour)})
+	}
+	recent := dbgen.AuditLog(t, db, database.AuditLog{Time: now.Add(-29 * 24 * time.Hour)})
+
+	// when
+	closer := dbpurge.New(ctx, logger, db, dbpurge.Options{
+		Retention: codersdk.RetentionConfig{
+			AuditLogs: clibase.Duration(30 * 24 * time.Hour),
+		},
+	})
+	defer closer.Close()
+
+	// then
+	require.Eventually(t, func() bool {
+		logs, err := db.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{Limit: 2000})
+		if err != nil {
+			return false
+		}
+		return len(logs) == 1 && logs[0].ID == recent.ID
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func TestDeleteOldWorkspaceBuilds(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	now := dbtime.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	// given
+	workspace := dbgen.Workspace(t, db, database.Workspace{})
+	var builds []database.WorkspaceBuild
+	for i := 1; i <= 4; i++ {
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			StartedAt:   sql.NullTime{Time: now, Valid: true},
+			CompletedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		builds = append(builds, dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			BuildNumber: int32(i),
+			JobID:       job.ID,
+		}))
+	}
+
+	// when
+	closer := dbpurge.New(ctx, logger, db, dbpurge.Options{
+		Retention: codersdk.RetentionConfig{
+			WorkspaceBuilds: 2,
+		},
+	})
+	defer closer.Close()
+
+	// then
+	require.Eventually(t, func() bool {
+		remaining, err := db.GetWorkspaceBuildsByWorkspaceID(ctx, database.GetWorkspaceBuildsByWorkspaceIDParams{
+			WorkspaceID: workspace.ID,
+		})
+		if err != nil {
+			return false
+		}
+		return len(remaining) == 2 &&
+			containsWorkspaceBuild(remaining, builds[2]) &&
+			containsWorkspaceBuild(remaining, builds[3])
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func TestDeleteOldWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	now := dbtime.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	// given
+	workspace := dbgen.Workspace(t, db, database.Workspace{})
+	var builds []database.WorkspaceBuild
+	for i := 1; i <= 4; i++ {
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			StartedAt:   sql.NullTime{Time: now, Valid: true},
+			CompletedAt: sql.NullTime{Time: now, Valid: true},
+		})
+		builds = append(builds, dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			BuildNumber: int32(i),
+			JobID:       job.ID,
+		}))
+	}
+	insertRecording := func(build database.WorkspaceBuild, endedAt time.Time) uuid.UUID {
+		id := uuid.New()
+		err := db.InsertWorkspaceSessionRecording(ctx, database.InsertWorkspaceSessionRecordingParams{
+			ID:               id,
+			WorkspaceID:      workspace.ID,
+			WorkspaceBuildID: build.ID,
+			AgentID:          uuid.New(),
+			Type:             database.WorkspaceSessionRecordingTypeSsh,
+			StartedAt:        endedAt.Add(-time.Minute),
+			EndedAt:          endedAt,
+			Data:             []byte{},
+		})
+		require.NoError(t, err)
+		return id
+	}
+	// The first build is deleted once its recording expires, while the second
+	// is kept for its recent recording.
+	insertRecording(builds[0], now.Add(-31*24*time.Hour))
+	recent := insertRecording(builds[1], now.Add(-29*24*time.Hour))
+
+	// when
+	closer := dbpurge.New(ctx, logger, db, dbpurge.Options{
+		Retention: codersdk.RetentionConfig{
+			WorkspaceBuilds:   2,
+			SessionRecordings: clibase.Duration(30 * 24 * time.Hour),
+		},
+	})
+	defer closer.Close()
+
+	// then
+	require.Eventually(t, func() bool {
+		recordings, err := db.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspace.ID)
+		if err != nil || len(recordings) != 1 || recordings[0].ID != recent {
+			return false
+		}
+		remaining, err := db.GetWorkspaceBuildsByWorkspaceID(ctx, database.GetWorkspaceBuildsByWorkspaceIDParams{
+			WorkspaceID: workspace.ID,
+		})
+		if err != nil {
+			return false
+		}
+		return len(remaining) == 3 &&
+			containsWorkspaceBuild(remaining, builds[1]) &&
+			containsWorkspaceBuild(remaining, builds[2]) &&
+			containsWorkspaceBuild(remaining, builds[3])
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func containsWorkspaceBuild(builds []database.WorkspaceBuild, needle database.WorkspaceBuild) bool {
+	return slices.ContainsFunc(builds, func(b database.WorkspaceBuild) bool {
+		return b.ID == needle.ID
+	})
+}
+
+func TestRetentionDryRun(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	registry := prometheus.NewRegistry()
+	now := dbtime.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	// given
+	for i := 0; i < 3; i++ {
+		dbgen.AuditLog(t, db, database.AuditLog{Time: now.Add(-2 * time.Hour)})
+	}
+
+	// when
+	closer := dbpurge.New(ctx, logger, db, dbpurge.Options{
+		Retention: codersdk.RetentionConfig{
+			AuditLogs: clibase.Duration(time.Hour),
+			DryRun:    true,
+		},
+		Registerer: registry,
+	})
+	defer closer.Close()
+
+	// then
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		if err != nil {
+			return false
+		}
+		for _, metric := range metrics {
+			if metric.GetName() != "coderd_dbpurge_expired_rows" {
+				continue
+			}
+			for _, m := range metric.GetMetric() {
+				if m.GetLabel()[0].GetValue() == "audit_logs" {
+					return m.GetGauge().GetValue() == 3
+				}
+			}
+		}
+		return false
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	count, err := db.CountOldAuditLogs(ctx, now)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, count, "dry run must not delete audit logs")
+}
@@ -9,6 +9,9 @@ const (
 	// Keep the unused iota here so we don't need + 1 every time
 	lockIDUnused = iota
 	LockIDDeploymentSetup
+	// LockIDAcquireProvisionerJob serializes provisioner job acquisition
+	// across all replicas when job concurrency limits are configured.
+	LockIDAcquireProvisionerJob
 )
 
 // GenLockID generates a unique and consistent lock ID from a given string.
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notification_messages_status;
+DROP TABLE IF EXISTS notification_messages;
+
+DROP TYPE IF EXISTS notification_message_status;
+DROP TYPE IF EXISTS notification_method;
+DROP TYPE IF EXISTS notification_event;
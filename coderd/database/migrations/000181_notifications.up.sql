@@ -0,0 +1,50 @@
+CREATE TYPE notification_event AS ENUM (
+	'workspace_autostop_imminent',
+	'workspace_dormant',
+	'workspace_autodelete_pending',
+	'workspace_autobuild_failed'
+);
+
+CREATE TYPE notification_method AS ENUM (
+	'smtp',
+	'webhook'
+);
+
+CREATE TYPE notification_message_status AS ENUM (
+	'pending',
+	'leased',
+	'sent',
+	'failed'
+);
+
+CREATE TABLE notification_messages (
+	id uuid NOT NULL,
+	event notification_event NOT NULL,
+	user_id uuid NOT NULL,
+	method notification_method NOT NULL,
+	status notification_message_status DEFAULT 'pending'::notification_message_status NOT NULL,
+	status_reason text,
+	payload jsonb NOT NULL,
+	attempt_count integer DEFAULT 0 NOT NULL,
+	dedupe_key text,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	leased_until timestamp with time zone,
+	next_retry_after timestamp with time zone,
+	PRIMARY KEY (id),
+	UNIQUE (dedupe_key),
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE notification_messages IS 'Outbound notifications queued for delivery to users.';
+
+COMMENT ON COLUMN notification_messages.payload IS 'The rendered labels used to fill in the event template at delivery time.';
+
+COMMENT ON COLUMN notification_messages.dedupe_key IS 'An optional key used to avoid enqueuing the same notification more than once.';
+
+COMMENT ON COLUMN notification_messages.leased_until IS 'The time at which a lease held by a dispatcher expires and the message may be acquired again.';
+
+COMMENT ON COLUMN notification_messages.next_retry_after IS 'The earliest time at which a failed message may be retried.';
+
+-- Dispatchers poll for pending messages, oldest first.
+CREATE INDEX idx_notification_messages_status ON notification_messages (status, created_at);
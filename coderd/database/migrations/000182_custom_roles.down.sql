@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_custom_roles_name_organization_id;
+DROP TABLE IF EXISTS custom_roles;
@@ -0,0 +1,17 @@
+CREATE TABLE custom_roles (
+	name text NOT NULL,
+	display_name text NOT NULL,
+	organization_id uuid REFERENCES organizations (id) ON DELETE CASCADE,
+	site_permissions jsonb NOT NULL DEFAULT '[]',
+	org_permissions jsonb NOT NULL DEFAULT '[]',
+	user_permissions jsonb NOT NULL DEFAULT '[]',
+	created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+	updated_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+-- Site wide roles have no organization, so the organization is coalesced to
+-- make the name unique among them too.
+CREATE UNIQUE INDEX idx_custom_roles_name_organization_id ON custom_roles (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+COMMENT ON TABLE custom_roles IS 'Roles defined by administrators in addition to the built-in roles. They are expanded into their permissions at runtime.';
+COMMENT ON COLUMN custom_roles.organization_id IS 'Roles with an organization are organization roles and can only grant permissions within that organization.';
@@ -0,0 +1,7 @@
+-- Enum values cannot be removed, so remove the keys that use it instead. They
+-- would otherwise no longer be restricted to their permissions.
+DELETE FROM api_keys WHERE scope = 'custom';
+
+ALTER TABLE api_keys
+	DROP COLUMN scope_permissions,
+	DROP COLUMN scope_allow_list;
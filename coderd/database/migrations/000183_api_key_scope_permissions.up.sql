@@ -0,0 +1,8 @@
+ALTER TYPE api_key_scope ADD VALUE IF NOT EXISTS 'custom';
+
+ALTER TABLE api_keys
+	ADD COLUMN scope_permissions jsonb NOT NULL DEFAULT '[]',
+	ADD COLUMN scope_allow_list text[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.scope_permissions IS 'Permissions the key is restricted to when the scope is custom.';
+COMMENT ON COLUMN api_keys.scope_allow_list IS 'Resource IDs the key is restricted to when the scope is custom. An empty list allows every resource.';
@@ -0,0 +1,3 @@
+ALTER TABLE workspaces
+	DROP COLUMN user_acl,
+	DROP COLUMN group_acl;
@@ -0,0 +1,6 @@
+ALTER TABLE workspaces
+	ADD COLUMN user_acl jsonb NOT NULL DEFAULT '{}',
+	ADD COLUMN group_acl jsonb NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN workspaces.user_acl IS 'Actions granted on the workspace to other users, keyed by user ID.';
+COMMENT ON COLUMN workspaces.group_acl IS 'Actions granted on the workspace to groups, keyed by group ID.';
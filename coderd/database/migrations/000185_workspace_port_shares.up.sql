@@ -0,0 +1,14 @@
+CREATE TABLE workspace_port_shares (
+	id uuid NOT NULL,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	agent_name text NOT NULL,
+	port integer NOT NULL,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE workspace_port_shares IS 'Signed links that grant anyone holding them access to a workspace port until they expire or are revoked.';
+
+CREATE INDEX workspace_port_shares_workspace_id_idx ON workspace_port_shares USING btree (workspace_id);
@@ -0,0 +1,43 @@
+ALTER TABLE templates ADD COLUMN record_sessions boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.record_sessions IS 'Whether the agent records PTY sessions in workspaces created from this template.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+CREATE TYPE workspace_session_recording_type AS ENUM (
+	'ssh',
+	'reconnecting_pty'
+);
+
+CREATE TABLE workspace_session_recordings (
+	id uuid NOT NULL,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	type workspace_session_recording_type NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone NOT NULL,
+	size bigint NOT NULL,
+	data bytea NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE workspace_session_recordings IS 'Terminal sessions recorded by workspace agents in asciinema v2 cast format.';
+
+CREATE INDEX workspace_session_recordings_workspace_id_idx ON workspace_session_recordings USING btree (workspace_id);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS workspace_agent_script_timings;
+
+DROP TYPE IF EXISTS workspace_agent_script_timing_status;
+
+DROP TYPE IF EXISTS workspace_agent_script_timing_stage;
+
+DROP TABLE IF EXISTS provisioner_job_timings;
+
+DROP TYPE IF EXISTS provisioner_job_timing_stage;
@@ -0,0 +1,46 @@
+CREATE TYPE provisioner_job_timing_stage AS ENUM (
+	'init',
+	'plan',
+	'graph',
+	'apply'
+);
+
+CREATE TABLE provisioner_job_timings (
+	job_id uuid NOT NULL REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone NOT NULL,
+	stage provisioner_job_timing_stage NOT NULL,
+	source text NOT NULL,
+	action text NOT NULL,
+	resource text NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_timings IS 'Time spent on each stage of a provisioner job, and on each resource within a stage.';
+
+CREATE INDEX provisioner_job_timings_job_id_idx ON provisioner_job_timings USING btree (job_id);
+
+CREATE TYPE workspace_agent_script_timing_stage AS ENUM (
+	'start',
+	'stop'
+);
+
+CREATE TYPE workspace_agent_script_timing_status AS ENUM (
+	'ok',
+	'exit_failure',
+	'timed_out',
+	'pipes_left_open'
+);
+
+CREATE TABLE workspace_agent_script_timings (
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	log_source_id uuid NOT NULL,
+	stage workspace_agent_script_timing_stage NOT NULL,
+	status workspace_agent_script_timing_status NOT NULL,
+	exit_code integer NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_script_timings IS 'Time spent running each start and stop script, as reported by workspace agents.';
+
+CREATE INDEX workspace_agent_script_timings_agent_id_idx ON workspace_agent_script_timings USING btree (agent_id);
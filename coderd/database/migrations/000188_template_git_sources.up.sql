@@ -0,0 +1,50 @@
+CREATE TABLE template_git_sources (
+	template_id uuid NOT NULL PRIMARY KEY REFERENCES templates (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	-- Versions created from the repository are attributed to this user.
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+	repository_url text NOT NULL,
+	branch text NOT NULL,
+	subdirectory text NOT NULL DEFAULT '',
+	auto_promote boolean NOT NULL DEFAULT false,
+	poll_interval bigint NOT NULL DEFAULT 0,
+	webhook_secret text NOT NULL,
+	last_checked_at timestamp with time zone,
+	last_commit_sha text NOT NULL DEFAULT '',
+	last_template_version_id uuid REFERENCES template_versions (id) ON DELETE SET NULL,
+	last_error text NOT NULL DEFAULT ''
+);
+
+COMMENT ON TABLE template_git_sources IS 'Git repositories that templates track. A new template version is created whenever the tracked branch moves, either on a webhook or when polled.';
+COMMENT ON COLUMN template_git_sources.subdirectory IS 'Directory within the repository that contains the template. Empty for the repository root.';
+COMMENT ON COLUMN template_git_sources.auto_promote IS 'Whether versions created from the repository become the active version once they import successfully.';
+COMMENT ON COLUMN template_git_sources.poll_interval IS 'How often the branch is polled for new commits, in nanoseconds. Zero disables polling.';
+COMMENT ON COLUMN template_git_sources.webhook_secret IS 'Secret used to verify webhook deliveries, either as an HMAC-SHA256 signature (GitHub, Gitea) or as a plain token (GitLab).';
+COMMENT ON COLUMN template_git_sources.last_commit_sha IS 'The commit that the latest template version was created from.';
+COMMENT ON COLUMN template_git_sources.last_error IS 'The error from the most recent sync, if it failed.';
+
+-- The view will be rebuilt with the new columns
+DROP VIEW template_version_with_user;
+
+ALTER TABLE template_versions
+	ADD COLUMN git_commit_sha text NOT NULL DEFAULT '',
+	ADD COLUMN git_commit_message text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN template_versions.git_commit_sha IS 'The commit this version was created from, if it was created from a git repository.';
+
+CREATE VIEW
+	template_version_with_user
+AS
+SELECT
+	template_versions.*,
+	coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+	coalesce(visible_users.username, '') AS created_by_username
+FROM
+	template_versions
+		LEFT JOIN
+	visible_users
+	ON
+			template_versions.created_by = visible_users.id;
+
+COMMENT ON VIEW template_version_with_user IS 'Joins in the username + avatar url of the created by user.';
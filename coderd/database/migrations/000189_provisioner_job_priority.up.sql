@@ -0,0 +1,42 @@
+-- Values are declared from lowest to highest priority so that jobs can be
+-- ordered by priority directly.
+CREATE TYPE provisioner_job_priority AS ENUM (
+	'batch',
+	'dry_run',
+	'autobuild',
+	'user'
+);
+
+ALTER TABLE provisioner_jobs
+	ADD COLUMN priority provisioner_job_priority NOT NULL DEFAULT 'user',
+	ADD COLUMN template_id uuid REFERENCES templates (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN provisioner_jobs.priority IS 'Jobs with a higher priority are acquired before jobs with a lower priority, regardless of when they were created.';
+COMMENT ON COLUMN provisioner_jobs.template_id IS 'The template the job belongs to, used to limit the number of concurrent jobs per template. Null for jobs of templates that have not been created yet.';
+
+-- Only jobs that are still queued or running are affected by scheduling,
+-- so older jobs are left alone.
+UPDATE provisioner_jobs
+SET priority = 'dry_run'
+WHERE type = 'template_version_dry_run' AND completed_at IS NULL;
+
+UPDATE provisioner_jobs
+SET template_id = template_versions.template_id
+FROM template_versions
+WHERE
+	provisioner_jobs.completed_at IS NULL
+	AND provisioner_jobs.type = 'template_version_import'
+	AND template_versions.job_id = provisioner_jobs.id;
+
+UPDATE provisioner_jobs
+SET template_id = workspaces.template_id
+FROM workspace_builds
+	JOIN workspaces ON workspaces.id = workspace_builds.workspace_id
+WHERE
+	provisioner_jobs.completed_at IS NULL
+	AND provisioner_jobs.type = 'workspace_build'
+	AND workspace_builds.job_id = provisioner_jobs.id;
+
+-- Used to count the running jobs of a user or template when acquiring jobs.
+CREATE INDEX provisioner_jobs_running_initiator_id_idx ON provisioner_jobs USING btree (initiator_id) WHERE (started_at IS NOT NULL AND completed_at IS NULL);
+CREATE INDEX provisioner_jobs_running_template_id_idx ON provisioner_jobs USING btree (template_id) WHERE (started_at IS NOT NULL AND completed_at IS NULL);
@@ -0,0 +1,3 @@
+ALTER TABLE provisioner_daemons DROP COLUMN key_id;
+
+DROP TABLE provisioner_keys;
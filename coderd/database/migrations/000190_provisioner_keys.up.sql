@@ -0,0 +1,17 @@
+CREATE TABLE provisioner_keys (
+	id uuid PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	name varchar(64) NOT NULL,
+	hashed_secret bytea NOT NULL,
+	tags jsonb NOT NULL DEFAULT '{}'::jsonb
+);
+
+COMMENT ON TABLE provisioner_keys IS 'Keys used by external provisioner daemons to authenticate. Daemons that authenticate with a key are restricted to the tags of the key.';
+
+CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
+
+ALTER TABLE provisioner_daemons
+	ADD COLUMN key_id uuid REFERENCES provisioner_keys (id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN provisioner_daemons.key_id IS 'The provisioner key the daemon authenticated with, if any.';
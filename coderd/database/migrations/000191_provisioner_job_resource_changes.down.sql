@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS provisioner_job_resource_changes;
+
+DROP TYPE IF EXISTS provisioner_job_resource_change_action;
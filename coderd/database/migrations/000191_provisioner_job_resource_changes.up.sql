@@ -0,0 +1,18 @@
+CREATE TYPE provisioner_job_resource_change_action AS ENUM (
+	'create',
+	'update',
+	'replace',
+	'delete'
+);
+
+CREATE TABLE provisioner_job_resource_changes (
+	job_id uuid NOT NULL REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	address text NOT NULL,
+	type text NOT NULL,
+	name text NOT NULL,
+	action provisioner_job_resource_change_action NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_resource_changes IS 'Changes a template version dry-run plans to make to the resources of an existing workspace.';
+
+CREATE INDEX provisioner_job_resource_changes_job_id_idx ON provisioner_job_resource_changes USING btree (job_id);
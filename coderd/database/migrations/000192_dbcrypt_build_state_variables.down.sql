@@ -0,0 +1,24 @@
+ALTER TABLE template_version_variables
+DROP COLUMN IF EXISTS value_key_id;
+
+-- The view will be rebuilt without the new column
+DROP VIEW workspace_build_with_user;
+
+ALTER TABLE workspace_builds
+DROP COLUMN IF EXISTS provisioner_state_key_id;
+
+CREATE VIEW
+	workspace_build_with_user
+AS
+SELECT
+	workspace_builds.*,
+	coalesce(visible_users.avatar_url, '') AS initiator_by_avatar_url,
+	coalesce(visible_users.username, '') AS initiator_by_username
+FROM
+	workspace_builds
+	LEFT JOIN
+		visible_users
+	ON
+		workspace_builds.initiator_id = visible_users.id;
+
+COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
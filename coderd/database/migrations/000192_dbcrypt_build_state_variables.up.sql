@@ -0,0 +1,28 @@
+-- The view will be rebuilt with the new column
+DROP VIEW workspace_build_with_user;
+
+ALTER TABLE workspace_builds
+ADD COLUMN IF NOT EXISTS provisioner_state_key_id text REFERENCES dbcrypt_keys(active_key_digest);
+
+COMMENT ON COLUMN workspace_builds.provisioner_state_key_id IS 'The ID of the key used to encrypt the provisioner state. If this is NULL, the provisioner state is not encrypted';
+
+CREATE VIEW
+	workspace_build_with_user
+AS
+SELECT
+	workspace_builds.*,
+	coalesce(visible_users.avatar_url, '') AS initiator_by_avatar_url,
+	coalesce(visible_users.username, '') AS initiator_by_username
+FROM
+	workspace_builds
+	LEFT JOIN
+		visible_users
+	ON
+		workspace_builds.initiator_id = visible_users.id;
+
+COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
+
+ALTER TABLE template_version_variables
+ADD COLUMN IF NOT EXISTS value_key_id text REFERENCES dbcrypt_keys(active_key_digest);
+
+COMMENT ON COLUMN template_version_variables.value_key_id IS 'The ID of the key used to encrypt the value of a sensitive variable. If this is NULL, the value is not encrypted';
@@ -0,0 +1,2 @@
+ALTER TABLE user_links
+DROP COLUMN login_provider;
@@ -0,0 +1,4 @@
+ALTER TABLE user_links
+ADD COLUMN login_provider text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN user_links.login_provider IS 'The ID of the named login provider the user is linked with. Empty for the default OIDC and GitHub providers.';
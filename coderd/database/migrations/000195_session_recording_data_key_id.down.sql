@@ -0,0 +1,2 @@
+ALTER TABLE workspace_session_recordings
+DROP COLUMN IF EXISTS data_key_id;
@@ -0,0 +1,4 @@
+ALTER TABLE workspace_session_recordings
+ADD COLUMN IF NOT EXISTS data_key_id text REFERENCES dbcrypt_keys(active_key_digest);
+
+COMMENT ON COLUMN workspace_session_recordings.data_key_id IS 'The ID of the key used to encrypt the recording data. If this is NULL, the data is not encrypted';
@@ -0,0 +1,2 @@
+ALTER TABLE template_git_sources
+DROP COLUMN IF EXISTS webhook_secret_key_id;
@@ -0,0 +1,4 @@
+ALTER TABLE template_git_sources
+ADD COLUMN IF NOT EXISTS webhook_secret_key_id text REFERENCES dbcrypt_keys(active_key_digest);
+
+COMMENT ON COLUMN template_git_sources.webhook_secret_key_id IS 'The ID of the key used to encrypt the webhook secret. If this is NULL, the webhook secret is not encrypted';
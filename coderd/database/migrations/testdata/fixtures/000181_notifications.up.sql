@@ -0,0 +1,17 @@
+INSERT INTO notification_messages
+	(id, event, user_id, method, status, status_reason, payload, attempt_count, dedupe_key, created_at, updated_at, leased_until, next_retry_after)
+VALUES (
+	'd0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11',
+	'workspace_dormant',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'smtp',
+	'failed',
+	'dial tcp: connection refused',
+	'{"workspace_name": "my-workspace"}',
+	3,
+	'workspace_dormant:3a9a1feb-e89d-457c-9d53-ac751b198ebe:2023-11-27T10:00:00Z',
+	'2023-11-27 10:00:00+00',
+	'2023-11-27 10:05:00+00',
+	NULL,
+	'2023-11-27 10:10:00+00'
+);
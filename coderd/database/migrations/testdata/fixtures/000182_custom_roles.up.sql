@@ -0,0 +1,12 @@
+INSERT INTO custom_roles
+	(name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at)
+VALUES (
+	'template-viewer',
+	'Template Viewer',
+	NULL,
+	'[{"negate": false, "resource_type": "template", "action": "read"}]',
+	'[]',
+	'[]',
+	'2023-11-27 10:00:00+00',
+	'2023-11-27 10:00:00+00'
+);
@@ -0,0 +1,11 @@
+INSERT INTO workspace_port_shares
+	(id, workspace_id, agent_name, port, created_by, created_at, expires_at)
+VALUES (
+	'c6ad2c59-0c1f-4bb9-9d09-5b0f4e3e4c2b',
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'main',
+	8080,
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'2024-04-01 10:00:00+00',
+	'2024-04-02 10:00:00+00'
+);
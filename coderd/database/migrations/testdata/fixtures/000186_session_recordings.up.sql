@@ -0,0 +1,15 @@
+INSERT INTO workspace_session_recordings
+	(id, workspace_id, workspace_build_id, agent_id, type, started_at, ended_at, size, data)
+VALUES (
+	'5c4a1d6e-2b8f-4f61-9d2a-7e3b0c9f8a14',
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'a8c0b8c5-c9a8-4f33-93a4-8142e6858244',
+	'8fa17bbd-c48c-44c7-91ae-d4acbc755fad',
+	'ssh',
+	'2022-11-02 13:04:30+02',
+	'2022-11-02 13:04:40+02',
+	55,
+	'{"version":2,"width":80,"height":24}
+[0.5,"o","hello"]
+'
+);
@@ -0,0 +1,23 @@
+INSERT INTO provisioner_job_timings
+	(job_id, started_at, ended_at, stage, source, action, resource)
+VALUES (
+	'52a90399-a53d-4644-be3c-47ee18a5716e',
+	'2022-11-02 13:04:20+02',
+	'2022-11-02 13:04:21+02',
+	'apply',
+	'docker',
+	'create',
+	'docker_container.workspace'
+);
+
+INSERT INTO workspace_agent_script_timings
+	(agent_id, log_source_id, stage, status, exit_code, started_at, ended_at)
+VALUES (
+	'8fa17bbd-c48c-44c7-91ae-d4acbc755fad',
+	'0a9c5bc3-9a08-4d62-8f2c-0e7a4b6dd0b1',
+	'start',
+	'ok',
+	0,
+	'2022-11-02 13:04:30+02',
+	'2022-11-02 13:04:35+02'
+);
@@ -0,0 +1,17 @@
+INSERT INTO template_git_sources
+	(template_id, created_at, updated_at, created_by, repository_url, branch, subdirectory, auto_promote, poll_interval, webhook_secret, last_checked_at, last_commit_sha, last_template_version_id)
+VALUES (
+	'4cc1f466-f326-477e-8762-9d0c6781fc56',
+	'2022-11-02 13:04:00+02',
+	'2022-11-02 13:04:00+02',
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'https://github.com/coder/coder.git',
+	'main',
+	'examples/templates/docker',
+	true,
+	300000000000,
+	'secret',
+	'2022-11-02 13:05:00+02',
+	'0123456789abcdef0123456789abcdef01234567',
+	'4e681a60-83da-42c2-902e-6535376ebb77'
+);
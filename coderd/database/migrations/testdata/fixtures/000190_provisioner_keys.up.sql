@@ -0,0 +1,10 @@
+INSERT INTO provisioner_keys
+	(id, created_at, organization_id, name, hashed_secret, tags)
+VALUES (
+	'b90547be-8870-4d68-8184-e8b2242b7c01',
+	'2022-11-02 13:04:00+02',
+	'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+	'chicago',
+	'\xdeadbeef'::bytea,
+	'{"data_center": "chicago"}'::jsonb
+);
@@ -0,0 +1,9 @@
+INSERT INTO provisioner_job_resource_changes
+	(job_id, address, type, name, action)
+VALUES (
+	'52a90399-a53d-4644-be3c-47ee18a5716e',
+	'docker_volume.home_volume',
+	'docker_volume',
+	'home_volume',
+	'replace'
+);
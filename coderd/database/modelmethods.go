@@ -98,6 +98,15 @@ func (s APIKeyScope) ToRBAC() rbac.ScopeName {
 	}
 }
 
+// RBACScope returns the scope that requests authenticated with the key are
+// restricted to.
+func (k APIKey) RBACScope() rbac.ExpandableScope {
+	if k.Scope == APIKeyScopeCustom {
+		return rbac.CustomScope(k.UserID.String(), k.ScopePermissions, k.ScopeAllowList)
+	}
+	return k.Scope.ToRBAC()
+}
+
 func (k APIKey) RBACObject() rbac.Object {
 	return rbac.ResourceAPIKey.WithIDString(k.ID).
 		WithOwner(k.UserID.String())
@@ -155,7 +164,9 @@ func (w GetWorkspaceByAgentIDRow) RBACObject() rbac.Object {
 func (w Workspace) RBACObject() rbac.Object {
 	return rbac.ResourceWorkspace.WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL).
+		WithGroupACL(w.GroupACL)
 }
 
 func (w Workspace) ExecutionRBAC() rbac.Object {
@@ -167,7 +178,9 @@ func (w Workspace) ExecutionRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceExecution.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL.grantACL(WorkspaceACLActionSSH, rbac.ActionCreate)).
+		WithGroupACL(w.GroupACL.grantACL(WorkspaceACLActionSSH, rbac.ActionCreate))
 }
 
 func (w Workspace) ApplicationConnectRBAC() rbac.Object {
@@ -179,7 +192,9 @@ func (w Workspace) ApplicationConnectRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceApplicationConnect.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL.grantACL(WorkspaceACLActionApp, rbac.ActionCreate)).
+		WithGroupACL(w.GroupACL.grantACL(WorkspaceACLActionApp, rbac.ActionCreate))
 }
 
 func (w Workspace) WorkspaceBuildRBAC(transition WorkspaceTransition) rbac.Object {
@@ -192,10 +207,14 @@ func (w Workspace) WorkspaceBuildRBAC(transition WorkspaceTransition) rbac.Objec
 		return w.DormantRBAC()
 	}
 
+	// Users the workspace is shared with via "use" may start and stop it,
+	// but never delete it.
 	return rbac.ResourceWorkspaceBuild.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL.grantACL(WorkspaceACLActionBuild, rbac.ActionUpdate)).
+		WithGroupACL(w.GroupACL.grantACL(WorkspaceACLActionBuild, rbac.ActionUpdate))
 }
 
 func (w Workspace) DormantRBAC() rbac.Object {
@@ -205,6 +224,10 @@ func (w Workspace) DormantRBAC() rbac.Object {
 		WithOwner(w.OwnerID.String())
 }
 
+func (m GetOrganizationMembersRow) RBACObject() rbac.Object {
+	return m.OrganizationMember.RBACObject()
+}
+
 func (m OrganizationMember) RBACObject() rbac.Object {
 	return rbac.ResourceOrganizationMember.
 		WithID(m.UserID).
@@ -229,6 +252,14 @@ func (p ProvisionerDaemon) RBACObject() rbac.Object {
 	return rbac.ResourceProvisionerDaemon.WithID(p.ID)
 }
 
+// RBACObject for a provisioner key is the same as for the daemons it
+// authenticates, scoped to the organization of the key.
+func (p ProvisionerKey) RBACObject() rbac.Object {
+	return rbac.ResourceProvisionerDaemon.
+		WithID(p.ID).
+		InOrg(p.OrganizationID)
+}
+
 func (w WorkspaceProxy) RBACObject() rbac.Object {
 	return rbac.ResourceWorkspaceProxy.
 		WithID(w.ID)
@@ -373,6 +404,8 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) []Workspace {
 			DormantAt:         r.DormantAt,
 			DeletingAt:        r.DeletingAt,
 			AutomaticUpdates:  r.AutomaticUpdates,
+			UserACL:           r.UserACL,
+			GroupACL:          r.GroupACL,
 		}
 	}
 
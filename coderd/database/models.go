@@ -21,6 +21,7 @@ type APIKeyScope string
 const (
 	APIKeyScopeAll                APIKeyScope = "all"
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	APIKeyScopeCustom             APIKeyScope = "custom"
 )
 
 func (e *APIKeyScope) Scan(src interface{}) error {
@@ -61,7 +62,8 @@ func (ns NullAPIKeyScope) Value() (driver.Value, error) {
 func (e APIKeyScope) Valid() bool {
 	switch e {
 	case APIKeyScopeAll,
-		APIKeyScopeApplicationConnect:
+		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom:
 		return true
 	}
 	return false
@@ -71,6 +73,7 @@ func AllAPIKeyScopeValues() []APIKeyScope {
 	return []APIKeyScope{
 		APIKeyScopeAll,
 		APIKeyScopeApplicationConnect,
+		APIKeyScopeCustom,
 	}
 }
 
@@ -657,6 +660,192 @@ func AllLoginTypeValues() []LoginType {
 	}
 }
 
+type NotificationEvent string
+
+const (
+	NotificationEventWorkspaceAutostopImminent  NotificationEvent = "workspace_autostop_imminent"
+	NotificationEventWorkspaceDormant           NotificationEvent = "workspace_dormant"
+	NotificationEventWorkspaceAutodeletePending NotificationEvent = "workspace_autodelete_pending"
+	NotificationEventWorkspaceAutobuildFailed   NotificationEvent = "workspace_autobuild_failed"
+)
+
+func (e *NotificationEvent) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationEvent(s)
+	case string:
+		*e = NotificationEvent(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationEvent: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationEvent struct {
+	NotificationEvent NotificationEvent `json:"notification_event"`
+	Valid             bool              `json:"valid"` // Valid is true if NotificationEvent is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationEvent) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationEvent, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationEvent.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationEvent) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationEvent), nil
+}
+
+func (e NotificationEvent) Valid() bool {
+	switch e {
+	case NotificationEventWorkspaceAutostopImminent,
+		NotificationEventWorkspaceDormant,
+		NotificationEventWorkspaceAutodeletePending,
+		NotificationEventWorkspaceAutobuildFailed:
+		return true
+	}
+	return false
+}
+
+func AllNotificationEventValues() []NotificationEvent {
+	return []NotificationEvent{
+		NotificationEventWorkspaceAutostopImminent,
+		NotificationEventWorkspaceDormant,
+		NotificationEventWorkspaceAutodeletePending,
+		NotificationEventWorkspaceAutobuildFailed,
+	}
+}
+
+type NotificationMessageStatus string
+
+const (
+	NotificationMessageStatusPending NotificationMessageStatus = "pending"
+	NotificationMessageStatusLeased  NotificationMessageStatus = "leased"
+	NotificationMessageStatusSent    NotificationMessageStatus = "sent"
+	NotificationMessageStatusFailed  NotificationMessageStatus = "failed"
+)
+
+func (e *NotificationMessageStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationMessageStatus(s)
+	case string:
+		*e = NotificationMessageStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationMessageStatus: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationMessageStatus struct {
+	NotificationMessageStatus NotificationMessageStatus `json:"notification_message_status"`
+	Valid                     bool                      `json:"valid"` // Valid is true if NotificationMessageStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationMessageStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationMessageStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationMessageStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationMessageStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationMessageStatus), nil
+}
+
+func (e NotificationMessageStatus) Valid() bool {
+	switch e {
+	case NotificationMessageStatusPending,
+		NotificationMessageStatusLeased,
+		NotificationMessageStatusSent,
+		NotificationMessageStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllNotificationMessageStatusValues() []NotificationMessageStatus {
+	return []NotificationMessageStatus{
+		NotificationMessageStatusPending,
+		NotificationMessageStatusLeased,
+		NotificationMessageStatusSent,
+		NotificationMessageStatusFailed,
+	}
+}
+
+type NotificationMethod string
+
+const (
+	NotificationMethodSMTP    NotificationMethod = "smtp"
+	NotificationMethodWebhook NotificationMethod = "webhook"
+)
+
+func (e *NotificationMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationMethod(s)
+	case string:
+		*e = NotificationMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationMethod: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationMethod struct {
+	NotificationMethod NotificationMethod `json:"notification_method"`
+	Valid              bool               `json:"valid"` // Valid is true if NotificationMethod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationMethod) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationMethod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationMethod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationMethod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationMethod), nil
+}
+
+func (e NotificationMethod) Valid() bool {
+	switch e {
+	case NotificationMethodSMTP,
+		NotificationMethodWebhook:
+		return true
+	}
+	return false
+}
+
+func AllNotificationMethodValues() []NotificationMethod {
+	return []NotificationMethod{
+		NotificationMethodSMTP,
+		NotificationMethodWebhook,
+	}
+}
+
 type ParameterDestinationScheme string
 
 const (
@@ -895,6 +1084,134 @@ func AllParameterTypeSystemValues() []ParameterTypeSystem {
 	}
 }
 
+type ProvisionerJobPriority string
+
+const (
+	ProvisionerJobPriorityBatch     ProvisionerJobPriority = "batch"
+	ProvisionerJobPriorityDryRun    ProvisionerJobPriority = "dry_run"
+	ProvisionerJobPriorityAutobuild ProvisionerJobPriority = "autobuild"
+	ProvisionerJobPriorityUser      ProvisionerJobPriority = "user"
+)
+
+func (e *ProvisionerJobPriority) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProvisionerJobPriority(s)
+	case string:
+		*e = ProvisionerJobPriority(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProvisionerJobPriority: %T", src)
+	}
+	return nil
+}
+
+type NullProvisionerJobPriority struct {
+	ProvisionerJobPriority ProvisionerJobPriority `json:"provisioner_job_priority"`
+	Valid                  bool                   `json:"valid"` // Valid is true if ProvisionerJobPriority is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProvisionerJobPriority) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProvisionerJobPriority, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProvisionerJobPriority.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProvisionerJobPriority) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProvisionerJobPriority), nil
+}
+
+func (e ProvisionerJobPriority) Valid() bool {
+	switch e {
+	case ProvisionerJobPriorityBatch,
+		ProvisionerJobPriorityDryRun,
+		ProvisionerJobPriorityAutobuild,
+		ProvisionerJobPriorityUser:
+		return true
+	}
+	return false
+}
+
+func AllProvisionerJobPriorityValues() []ProvisionerJobPriority {
+	return []ProvisionerJobPriority{
+		ProvisionerJobPriorityBatch,
+		ProvisionerJobPriorityDryRun,
+		ProvisionerJobPriorityAutobuild,
+		ProvisionerJobPriorityUser,
+	}
+}
+
+type ProvisionerJobResourceChangeAction string
+
+const (
+	ProvisionerJobResourceChangeActionCreate  ProvisionerJobResourceChangeAction = "create"
+	ProvisionerJobResourceChangeActionUpdate  ProvisionerJobResourceChangeAction = "update"
+	ProvisionerJobResourceChangeActionReplace ProvisionerJobResourceChangeAction = "replace"
+	ProvisionerJobResourceChangeActionDelete  ProvisionerJobResourceChangeAction = "delete"
+)
+
+func (e *ProvisionerJobResourceChangeAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProvisionerJobResourceChangeAction(s)
+	case string:
+		*e = ProvisionerJobResourceChangeAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProvisionerJobResourceChangeAction: %T", src)
+	}
+	return nil
+}
+
+type NullProvisionerJobResourceChangeAction struct {
+	ProvisionerJobResourceChangeAction ProvisionerJobResourceChangeAction `json:"provisioner_job_resource_change_action"`
+	Valid                              bool                               `json:"valid"` // Valid is true if ProvisionerJobResourceChangeAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProvisionerJobResourceChangeAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProvisionerJobResourceChangeAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProvisionerJobResourceChangeAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProvisionerJobResourceChangeAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProvisionerJobResourceChangeAction), nil
+}
+
+func (e ProvisionerJobResourceChangeAction) Valid() bool {
+	switch e {
+	case ProvisionerJobResourceChangeActionCreate,
+		ProvisionerJobResourceChangeActionUpdate,
+		ProvisionerJobResourceChangeActionReplace,
+		ProvisionerJobResourceChangeActionDelete:
+		return true
+	}
+	return false
+}
+
+func AllProvisionerJobResourceChangeActionValues() []ProvisionerJobResourceChangeAction {
+	return []ProvisionerJobResourceChangeAction{
+		ProvisionerJobResourceChangeActionCreate,
+		ProvisionerJobResourceChangeActionUpdate,
+		ProvisionerJobResourceChangeActionReplace,
+		ProvisionerJobResourceChangeActionDelete,
+	}
+}
+
 // Computed status of a provisioner job. Jobs could be stuck in a hung state, these states do not guarantee any transition to another state.
 type ProvisionerJobStatus string
 
@@ -969,6 +1286,70 @@ func AllProvisionerJobStatusValues() []ProvisionerJobStatus {
 	}
 }
 
+type ProvisionerJobTimingStage string
+
+const (
+	ProvisionerJobTimingStageInit  ProvisionerJobTimingStage = "init"
+	ProvisionerJobTimingStagePlan  ProvisionerJobTimingStage = "plan"
+	ProvisionerJobTimingStageGraph ProvisionerJobTimingStage = "graph"
+	ProvisionerJobTimingStageApply ProvisionerJobTimingStage = "apply"
+)
+
+func (e *ProvisionerJobTimingStage) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProvisionerJobTimingStage(s)
+	case string:
+		*e = ProvisionerJobTimingStage(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProvisionerJobTimingStage: %T", src)
+	}
+	return nil
+}
+
+type NullProvisionerJobTimingStage struct {
+	ProvisionerJobTimingStage ProvisionerJobTimingStage `json:"provisioner_job_timing_stage"`
+	Valid                     bool                      `json:"valid"` // Valid is true if ProvisionerJobTimingStage is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProvisionerJobTimingStage) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProvisionerJobTimingStage, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProvisionerJobTimingStage.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProvisionerJobTimingStage) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProvisionerJobTimingStage), nil
+}
+
+func (e ProvisionerJobTimingStage) Valid() bool {
+	switch e {
+	case ProvisionerJobTimingStageInit,
+		ProvisionerJobTimingStagePlan,
+		ProvisionerJobTimingStageGraph,
+		ProvisionerJobTimingStageApply:
+		return true
+	}
+	return false
+}
+
+func AllProvisionerJobTimingStageValues() []ProvisionerJobTimingStage {
+	return []ProvisionerJobTimingStage{
+		ProvisionerJobTimingStageInit,
+		ProvisionerJobTimingStagePlan,
+		ProvisionerJobTimingStageGraph,
+		ProvisionerJobTimingStageApply,
+	}
+}
+
 type ProvisionerJobType string
 
 const (
@@ -1159,6 +1540,7 @@ const (
 	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
 	ResourceTypeConvertLogin    ResourceType = "convert_login"
 	ResourceTypeHealthSettings  ResourceType = "health_settings"
+	ResourceTypeOidcSyncRules   ResourceType = "oidc_sync_rules"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1210,7 +1592,8 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
 		ResourceTypeConvertLogin,
-		ResourceTypeHealthSettings:
+		ResourceTypeHealthSettings,
+		ResourceTypeOidcSyncRules:
 		return true
 	}
 	return false
@@ -1231,6 +1614,7 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeWorkspaceProxy,
 		ResourceTypeConvertLogin,
 		ResourceTypeHealthSettings,
+		ResourceTypeOidcSyncRules,
 	}
 }
 
@@ -1555,6 +1939,128 @@ func AllWorkspaceAgentSubsystemValues() []WorkspaceAgentSubsystem {
 	}
 }
 
+type WorkspaceAgentScriptTimingStage string
+
+const (
+	WorkspaceAgentScriptTimingStageStart WorkspaceAgentScriptTimingStage = "start"
+	WorkspaceAgentScriptTimingStageStop  WorkspaceAgentScriptTimingStage = "stop"
+)
+
+func (e *WorkspaceAgentScriptTimingStage) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentScriptTimingStage(s)
+	case string:
+		*e = WorkspaceAgentScriptTimingStage(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentScriptTimingStage: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentScriptTimingStage struct {
+	WorkspaceAgentScriptTimingStage WorkspaceAgentScriptTimingStage `json:"workspace_agent_script_timing_stage"`
+	Valid                           bool                            `json:"valid"` // Valid is true if WorkspaceAgentScriptTimingStage is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentScriptTimingStage) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentScriptTimingStage, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentScriptTimingStage.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentScriptTimingStage) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentScriptTimingStage), nil
+}
+
+func (e WorkspaceAgentScriptTimingStage) Valid() bool {
+	switch e {
+	case WorkspaceAgentScriptTimingStageStart,
+		WorkspaceAgentScriptTimingStageStop:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentScriptTimingStageValues() []WorkspaceAgentScriptTimingStage {
+	return []WorkspaceAgentScriptTimingStage{
+		WorkspaceAgentScriptTimingStageStart,
+		WorkspaceAgentScriptTimingStageStop,
+	}
+}
+
+type WorkspaceAgentScriptTimingStatus string
+
+const (
+	WorkspaceAgentScriptTimingStatusOk            WorkspaceAgentScriptTimingStatus = "ok"
+	WorkspaceAgentScriptTimingStatusExitFailure   WorkspaceAgentScriptTimingStatus = "exit_failure"
+	WorkspaceAgentScriptTimingStatusTimedOut      WorkspaceAgentScriptTimingStatus = "timed_out"
+	WorkspaceAgentScriptTimingStatusPipesLeftOpen WorkspaceAgentScriptTimingStatus = "pipes_left_open"
+)
+
+func (e *WorkspaceAgentScriptTimingStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentScriptTimingStatus(s)
+	case string:
+		*e = WorkspaceAgentScriptTimingStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentScriptTimingStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentScriptTimingStatus struct {
+	WorkspaceAgentScriptTimingStatus WorkspaceAgentScriptTimingStatus `json:"workspace_agent_script_timing_status"`
+	Valid                            bool                             `json:"valid"` // Valid is true if WorkspaceAgentScriptTimingStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentScriptTimingStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentScriptTimingStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentScriptTimingStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentScriptTimingStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentScriptTimingStatus), nil
+}
+
+func (e WorkspaceAgentScriptTimingStatus) Valid() bool {
+	switch e {
+	case WorkspaceAgentScriptTimingStatusOk,
+		WorkspaceAgentScriptTimingStatusExitFailure,
+		WorkspaceAgentScriptTimingStatusTimedOut,
+		WorkspaceAgentScriptTimingStatusPipesLeftOpen:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentScriptTimingStatusValues() []WorkspaceAgentScriptTimingStatus {
+	return []WorkspaceAgentScriptTimingStatus{
+		WorkspaceAgentScriptTimingStatusOk,
+		WorkspaceAgentScriptTimingStatusExitFailure,
+		WorkspaceAgentScriptTimingStatusTimedOut,
+		WorkspaceAgentScriptTimingStatusPipesLeftOpen,
+	}
+}
+
 type WorkspaceAppHealth string
 
 const (
@@ -1619,6 +2125,64 @@ func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
 	}
 }
 
+type WorkspaceSessionRecordingType string
+
+const (
+	WorkspaceSessionRecordingTypeSsh             WorkspaceSessionRecordingType = "ssh"
+	WorkspaceSessionRecordingTypeReconnectingPty WorkspaceSessionRecordingType = "reconnecting_pty"
+)
+
+func (e *WorkspaceSessionRecordingType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceSessionRecordingType(s)
+	case string:
+		*e = WorkspaceSessionRecordingType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceSessionRecordingType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceSessionRecordingType struct {
+	WorkspaceSessionRecordingType WorkspaceSessionRecordingType `json:"workspace_session_recording_type"`
+	Valid                         bool                          `json:"valid"` // Valid is true if WorkspaceSessionRecordingType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceSessionRecordingType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceSessionRecordingType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceSessionRecordingType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceSessionRecordingType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceSessionRecordingType), nil
+}
+
+func (e WorkspaceSessionRecordingType) Valid() bool {
+	switch e {
+	case WorkspaceSessionRecordingTypeSsh,
+		WorkspaceSessionRecordingTypeReconnectingPty:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceSessionRecordingTypeValues() []WorkspaceSessionRecordingType {
+	return []WorkspaceSessionRecordingType{
+		WorkspaceSessionRecordingTypeSsh,
+		WorkspaceSessionRecordingTypeReconnectingPty,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -1694,6 +2258,10 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// Permissions the key is restricted to when the scope is custom.
+	ScopePermissions CustomRolePermissions `db:"scope_permissions" json:"scope_permissions"`
+	// Resource IDs the key is restricted to when the scope is custom. An empty list allows every resource.
+	ScopeAllowList []string `db:"scope_allow_list" json:"scope_allow_list"`
 }
 
 type AuditLog struct {
@@ -1714,6 +2282,19 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Roles defined by administrators in addition to the built-in roles. They are expanded into their permissions at runtime.
+type CustomRole struct {
+	Name        string `db:"name" json:"name"`
+	DisplayName string `db:"display_name" json:"display_name"`
+	// Roles with an organization are organization roles and can only grant permissions within that organization.
+	OrganizationID  uuid.NullUUID         `db:"organization_id" json:"organization_id"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time             `db:"updated_at" json:"updated_at"`
+}
+
 // A table used to store the keys used to encrypt the database.
 type DBCryptKey struct {
 	// An integer used to identify the key.
@@ -1788,6 +2369,27 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// Outbound notifications queued for delivery to users.
+type NotificationMessage struct {
+	ID           uuid.UUID                 `db:"id" json:"id"`
+	Event        NotificationEvent         `db:"event" json:"event"`
+	UserID       uuid.UUID                 `db:"user_id" json:"user_id"`
+	Method       NotificationMethod        `db:"method" json:"method"`
+	Status       NotificationMessageStatus `db:"status" json:"status"`
+	StatusReason sql.NullString            `db:"status_reason" json:"status_reason"`
+	// The rendered labels used to fill in the event template at delivery time.
+	Payload      StringMap `db:"payload" json:"payload"`
+	AttemptCount int32     `db:"attempt_count" json:"attempt_count"`
+	// An optional key used to avoid enqueuing the same notification more than once.
+	DedupeKey sql.NullString `db:"dedupe_key" json:"dedupe_key"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at" json:"updated_at"`
+	// The time at which a lease held by a dispatcher expires and the message may be acquired again.
+	LeasedUntil sql.NullTime `db:"leased_until" json:"leased_until"`
+	// The earliest time at which a failed message may be retried.
+	NextRetryAfter sql.NullTime `db:"next_retry_after" json:"next_retry_after"`
+}
+
 type Organization struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -1847,6 +2449,8 @@ type ProvisionerDaemon struct {
 	Version      string            `db:"version" json:"version"`
 	// The API version of the provisioner daemon
 	APIVersion string `db:"api_version" json:"api_version"`
+	// The provisioner key the daemon authenticated with, if any.
+	KeyID uuid.NullUUID `db:"key_id" json:"key_id"`
 }
 
 type ProvisionerJob struct {
@@ -1870,6 +2474,10 @@ type ProvisionerJob struct {
 	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
 	// Computed column to track the status of the job.
 	JobStatus ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	// Jobs with a higher priority are acquired before jobs with a lower priority, regardless of when they were created.
+	Priority ProvisionerJobPriority `db:"priority" json:"priority"`
+	// The template the job belongs to, used to limit the number of concurrent jobs per template. Null for jobs of templates that have not been created yet.
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
 }
 
 type ProvisionerJobLog struct {
@@ -1882,6 +2490,36 @@ type ProvisionerJobLog struct {
 	ID        int64     `db:"id" json:"id"`
 }
 
+// Changes a template version dry-run plans to make to the resources of an existing workspace.
+type ProvisionerJobResourceChange struct {
+	JobID   uuid.UUID                          `db:"job_id" json:"job_id"`
+	Address string                             `db:"address" json:"address"`
+	Type    string                             `db:"type" json:"type"`
+	Name    string                             `db:"name" json:"name"`
+	Action  ProvisionerJobResourceChangeAction `db:"action" json:"action"`
+}
+
+// Time spent on each stage of a provisioner job, and on each resource within a stage.
+type ProvisionerJobTiming struct {
+	JobID     uuid.UUID                 `db:"job_id" json:"job_id"`
+	StartedAt time.Time                 `db:"started_at" json:"started_at"`
+	EndedAt   time.Time                 `db:"ended_at" json:"ended_at"`
+	Stage     ProvisionerJobTimingStage `db:"stage" json:"stage"`
+	Source    string                    `db:"source" json:"source"`
+	Action    string                    `db:"action" json:"action"`
+	Resource  string                    `db:"resource" json:"resource"`
+}
+
+// Keys used by external provisioner daemons to authenticate. Daemons that authenticate with a key are restricted to the tags of the key.
+type ProvisionerKey struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
+	Tags           StringMap `db:"tags" json:"tags"`
+}
+
 type Replica struct {
 	ID              uuid.UUID    `db:"id" json:"id"`
 	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
@@ -1974,10 +2612,37 @@ type Template struct {
 	RequireActiveVersion          bool            `db:"require_active_version" json:"require_active_version"`
 	Deprecated                    string          `db:"deprecated" json:"deprecated"`
 	UseMaxTtl                     bool            `db:"use_max_ttl" json:"use_max_ttl"`
+	RecordSessions                bool            `db:"record_sessions" json:"record_sessions"`
 	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
 }
 
+// Git repositories that templates track. A new template version is created whenever the tracked branch moves, either on a webhook or when polled.
+type TemplateGitSource struct {
+	TemplateID    uuid.UUID `db:"template_id" json:"template_id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	CreatedBy     uuid.UUID `db:"created_by" json:"created_by"`
+	RepositoryURL string    `db:"repository_url" json:"repository_url"`
+	Branch        string    `db:"branch" json:"branch"`
+	// Directory within the repository that contains the template. Empty for the repository root.
+	Subdirectory string `db:"subdirectory" json:"subdirectory"`
+	// Whether versions created from the repository become the active version once they import successfully.
+	AutoPromote bool `db:"auto_promote" json:"auto_promote"`
+	// How often the branch is polled for new commits, in nanoseconds. Zero disables polling.
+	PollInterval int64 `db:"poll_interval" json:"poll_interval"`
+	// Secret used to verify webhook deliveries, either as an HMAC-SHA256 signature (GitHub, Gitea) or as a plain token (GitLab).
+	WebhookSecret string       `db:"webhook_secret" json:"webhook_secret"`
+	LastCheckedAt sql.NullTime `db:"last_checked_at" json:"last_checked_at"`
+	// The commit that the latest template version was created from.
+	LastCommitSHA         string        `db:"last_commit_sha" json:"last_commit_sha"`
+	LastTemplateVersionID uuid.NullUUID `db:"last_template_version_id" json:"last_template_version_id"`
+	// The error from the most recent sync, if it failed.
+	LastError string `db:"last_error" json:"last_error"`
+	// The ID of the key used to encrypt the webhook secret. If this is NULL, the webhook secret is not encrypted
+	WebhookSecretKeyID sql.NullString `db:"webhook_secret_key_id" json:"webhook_secret_key_id"`
+}
+
 type TemplateTable struct {
 	ID              uuid.UUID       `db:"id" json:"id"`
 	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
@@ -2016,6 +2681,8 @@ type TemplateTable struct {
 	// If set to a non empty string, the template will no longer be able to be used. The message will be displayed to the user.
 	Deprecated string `db:"deprecated" json:"deprecated"`
 	UseMaxTtl  bool   `db:"use_max_ttl" json:"use_max_ttl"`
+	// Whether the agent records PTY sessions in workspaces created from this template.
+	RecordSessions bool `db:"record_sessions" json:"record_sessions"`
 }
 
 // Joins in the username + avatar url of the created by user.
@@ -2032,6 +2699,8 @@ type TemplateVersion struct {
 	ExternalAuthProviders []string      `db:"external_auth_providers" json:"external_auth_providers"`
 	Message               string        `db:"message" json:"message"`
 	Archived              bool          `db:"archived" json:"archived"`
+	GitCommitSHA          string        `db:"git_commit_sha" json:"git_commit_sha"`
+	GitCommitMessage      string        `db:"git_commit_message" json:"git_commit_message"`
 	CreatedByAvatarURL    string        `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername     string        `db:"created_by_username" json:"created_by_username"`
 }
@@ -2087,6 +2756,9 @@ type TemplateVersionTable struct {
 	// Message describing the changes in this version of the template, similar to a Git commit message. Like a commit message, this should be a short, high-level description of the changes in this version of the template. This message is immutable and should not be updated after the fact.
 	Message  string `db:"message" json:"message"`
 	Archived bool   `db:"archived" json:"archived"`
+	// The commit this version was created from, if it was created from a git repository.
+	GitCommitSHA     string `db:"git_commit_sha" json:"git_commit_sha"`
+	GitCommitMessage string `db:"git_commit_message" json:"git_commit_message"`
 }
 
 type TemplateVersionVariable struct {
@@ -2105,6 +2777,8 @@ type TemplateVersionVariable struct {
 	Required bool `db:"required" json:"required"`
 	// Sensitive variables have their values redacted in logs or site UI
 	Sensitive bool `db:"sensitive" json:"sensitive"`
+	// The ID of the key used to encrypt the value of a sensitive variable. If this is NULL, the value is not encrypted
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
 }
 
 type User struct {
@@ -2139,6 +2813,8 @@ type UserLink struct {
 	OAuthRefreshTokenKeyID sql.NullString `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
 	// Debug information includes information like id_token and userinfo claims.
 	DebugContext json.RawMessage `db:"debug_context" json:"debug_context"`
+	// The ID of the named login provider the user is linked with. Empty for the default OIDC and GitHub providers.
+	LoginProvider string `db:"login_provider" json:"login_provider"`
 }
 
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
@@ -2163,6 +2839,10 @@ type Workspace struct {
 	DormantAt         sql.NullTime     `db:"dormant_at" json:"dormant_at"`
 	DeletingAt        sql.NullTime     `db:"deleting_at" json:"deleting_at"`
 	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	// Actions granted on the workspace to other users, keyed by user ID.
+	UserACL WorkspaceACL `db:"user_acl" json:"user_acl"`
+	// Actions granted on the workspace to groups, keyed by group ID.
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
 }
 
 type WorkspaceAgent struct {
@@ -2250,6 +2930,17 @@ type WorkspaceAgentScript struct {
 	TimeoutSeconds   int32     `db:"timeout_seconds" json:"timeout_seconds"`
 }
 
+// Time spent running each start and stop script, as reported by workspace agents.
+type WorkspaceAgentScriptTiming struct {
+	AgentID     uuid.UUID                        `db:"agent_id" json:"agent_id"`
+	LogSourceID uuid.UUID                        `db:"log_source_id" json:"log_source_id"`
+	Stage       WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
+	Status      WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
+	ExitCode    int32                            `db:"exit_code" json:"exit_code"`
+	StartedAt   time.Time                        `db:"started_at" json:"started_at"`
+	EndedAt     time.Time                        `db:"ended_at" json:"ended_at"`
+}
+
 type WorkspaceAgentStat struct {
 	ID                          uuid.UUID       `db:"id" json:"id"`
 	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
@@ -2314,22 +3005,23 @@ type WorkspaceAppStat struct {
 
 // Joins in the username + avatar url of the initiated by user.
 type WorkspaceBuild struct {
-	ID                   uuid.UUID           `db:"id" json:"id"`
-	CreatedAt            time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt            time.Time           `db:"updated_at" json:"updated_at"`
-	WorkspaceID          uuid.UUID           `db:"workspace_id" json:"workspace_id"`
-	TemplateVersionID    uuid.UUID           `db:"template_version_id" json:"template_version_id"`
-	BuildNumber          int32               `db:"build_number" json:"build_number"`
-	Transition           WorkspaceTransition `db:"transition" json:"transition"`
-	InitiatorID          uuid.UUID           `db:"initiator_id" json:"initiator_id"`
-	ProvisionerState     []byte              `db:"provisioner_state" json:"provisioner_state"`
-	JobID                uuid.UUID           `db:"job_id" json:"job_id"`
-	Deadline             time.Time           `db:"deadline" json:"deadline"`
-	Reason               BuildReason         `db:"reason" json:"reason"`
-	DailyCost            int32               `db:"daily_cost" json:"daily_cost"`
-	MaxDeadline          time.Time           `db:"max_deadline" json:"max_deadline"`
-	InitiatorByAvatarUrl string              `db:"initiator_by_avatar_url" json:"initiator_by_avatar_url"`
-	InitiatorByUsername  string              `db:"initiator_by_username" json:"initiator_by_username"`
+	ID                    uuid.UUID           `db:"id" json:"id"`
+	CreatedAt             time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time           `db:"updated_at" json:"updated_at"`
+	WorkspaceID           uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID     uuid.UUID           `db:"template_version_id" json:"template_version_id"`
+	BuildNumber           int32               `db:"build_number" json:"build_number"`
+	Transition            WorkspaceTransition `db:"transition" json:"transition"`
+	InitiatorID           uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	ProvisionerState      []byte              `db:"provisioner_state" json:"provisioner_state"`
+	JobID                 uuid.UUID           `db:"job_id" json:"job_id"`
+	Deadline              time.Time           `db:"deadline" json:"deadline"`
+	Reason                BuildReason         `db:"reason" json:"reason"`
+	DailyCost             int32               `db:"daily_cost" json:"daily_cost"`
+	MaxDeadline           time.Time           `db:"max_deadline" json:"max_deadline"`
+	ProvisionerStateKeyID sql.NullString      `db:"provisioner_state_key_id" json:"provisioner_state_key_id"`
+	InitiatorByAvatarUrl  string              `db:"initiator_by_avatar_url" json:"initiator_by_avatar_url"`
+	InitiatorByUsername   string              `db:"initiator_by_username" json:"initiator_by_username"`
 }
 
 type WorkspaceBuildParameter struct {
@@ -2355,6 +3047,19 @@ type WorkspaceBuildTable struct {
 	Reason            BuildReason         `db:"reason" json:"reason"`
 	DailyCost         int32               `db:"daily_cost" json:"daily_cost"`
 	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
+	// The ID of the key used to encrypt the provisioner state. If this is NULL, the provisioner state is not encrypted
+	ProvisionerStateKeyID sql.NullString `db:"provisioner_state_key_id" json:"provisioner_state_key_id"`
+}
+
+// Signed links that grant anyone holding them access to a workspace port until they expire or are revoked.
+type WorkspacePortShare struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+	CreatedBy   uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
 }
 
 type WorkspaceProxy struct {
@@ -2400,3 +3105,18 @@ type WorkspaceResourceMetadatum struct {
 	Sensitive           bool           `db:"sensitive" json:"sensitive"`
 	ID                  int64          `db:"id" json:"id"`
 }
+
+// Terminal sessions recorded by workspace agents in asciinema v2 cast format.
+type WorkspaceSessionRecording struct {
+	ID               uuid.UUID                     `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID                     `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID                     `db:"workspace_build_id" json:"workspace_build_id"`
+	AgentID          uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Type             WorkspaceSessionRecordingType `db:"type" json:"type"`
+	StartedAt        time.Time                     `db:"started_at" json:"started_at"`
+	EndedAt          time.Time                     `db:"ended_at" json:"ended_at"`
+	Size             int64                         `db:"size" json:"size"`
+	Data             []byte                        `db:"data" json:"data"`
+	// The ID of the key used to encrypt the recording data. If this is NULL, the data is not encrypted
+	DataKeyID sql.NullString `db:"data_key_id" json:"data_key_id"`
+}
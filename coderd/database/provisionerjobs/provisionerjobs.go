@@ -9,7 +9,12 @@ import (
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 )
 
-const EventJobPosted = "provisioner_job_posted"
+const (
+	EventJobPosted = "provisioner_job_posted"
+	// EventJobCompleted is published when a running job completes, freeing up
+	// capacity for jobs that were held back by concurrency limits.
+	EventJobCompleted = "provisioner_job_completed"
+)
 
 type JobPosting struct {
 	ProvisionerType database.ProvisionerType `json:"type"`
@@ -27,3 +32,8 @@ func PostJob(ps pubsub.Pubsub, job database.ProvisionerJob) error {
 	err = ps.Publish(EventJobPosted, msg)
 	return err
 }
+
+// CompleteJob notifies acquirers that a running job has completed.
+func CompleteJob(ps pubsub.Pubsub) error {
+	return ps.Publish(EventJobCompleted, []byte{})
+}
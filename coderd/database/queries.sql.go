@@ -138,7 +138,7 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 FROM
 	api_keys
 WHERE
@@ -163,13 +163,15 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopePermissions,
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 FROM
 	api_keys
 WHERE
@@ -202,12 +204,14 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopePermissions,
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -232,6 +236,8 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopePermissions,
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -247,7 +253,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -277,6 +283,8 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopePermissions,
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -292,7 +300,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -317,6 +325,8 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			&i.ScopePermissions,
+			pq.Array(&i.ScopeAllowList),
 		); err != nil {
 			return nil, err
 		}
@@ -345,7 +355,9 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scope_permissions,
+		scope_allow_list
 	)
 VALUES
 	($1,
@@ -354,22 +366,24 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scope_permissions, scope_allow_list
 `
 
 type InsertAPIKeyParams struct {
-	ID              string      `db:"id" json:"id"`
-	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
-	HashedSecret    []byte      `db:"hashed_secret" json:"hashed_secret"`
-	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	LastUsed        time.Time   `db:"last_used" json:"last_used"`
-	ExpiresAt       time.Time   `db:"expires_at" json:"expires_at"`
-	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
-	LoginType       LoginType   `db:"login_type" json:"login_type"`
-	Scope           APIKeyScope `db:"scope" json:"scope"`
-	TokenName       string      `db:"token_name" json:"token_name"`
+	ID               string                `db:"id" json:"id"`
+	LifetimeSeconds  int64                 `db:"lifetime_seconds" json:"lifetime_seconds"`
+	HashedSecret     []byte                `db:"hashed_secret" json:"hashed_secret"`
+	IPAddress        pqtype.Inet           `db:"ip_address" json:"ip_address"`
+	UserID           uuid.UUID             `db:"user_id" json:"user_id"`
+	LastUsed         time.Time             `db:"last_used" json:"last_used"`
+	ExpiresAt        time.Time             `db:"expires_at" json:"expires_at"`
+	CreatedAt        time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time             `db:"updated_at" json:"updated_at"`
+	LoginType        LoginType             `db:"login_type" json:"login_type"`
+	Scope            APIKeyScope           `db:"scope" json:"scope"`
+	TokenName        string                `db:"token_name" json:"token_name"`
+	ScopePermissions CustomRolePermissions `db:"scope_permissions" json:"scope_permissions"`
+	ScopeAllowList   []string              `db:"scope_allow_list" json:"scope_allow_list"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -386,6 +400,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		arg.ScopePermissions,
+		pq.Array(arg.ScopeAllowList),
 	)
 	var i APIKey
 	err := row.Scan(
@@ -401,6 +417,8 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		&i.ScopePermissions,
+		pq.Array(&i.ScopeAllowList),
 	)
 	return i, err
 }
@@ -433,6 +451,266 @@ func (q *sqlQuerier) UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDP
 	return err
 }
 
+const countOldAuditLogs = `-- name: CountOldAuditLogs :one
+SELECT
+	COUNT(*)
+FROM
+	audit_logs
+WHERE
+	"time" < $1
+`
+
+func (q *sqlQuerier) CountOldAuditLogs(ctx context.Context, beforeTime time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOldAuditLogs, beforeTime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteOldAuditLogs = `-- name: DeleteOldAuditLogs :execrows
+DELETE FROM audit_logs WHERE id IN (
+	SELECT
+		id
+	FROM
+		audit_logs
+	WHERE
+		"time" < $1
+	ORDER BY
+		"time" ASC
+	LIMIT
+		$2 :: int
+)
+`
+
+type DeleteOldAuditLogsParams struct {
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Delete at most limit_count audit logs that are older than before_time. The
+// limit keeps each statement short so that purging a large backlog does not
+// hold locks on the table for long.
+func (q *sqlQuerier) DeleteOldAuditLogs(ctx context.Context, arg DeleteOldAuditLogsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldAuditLogs, arg.BeforeTime, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAuditLogsBefore = `-- name: GetAuditLogsBefore :many
+SELECT
+    audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
+    users.username AS user_username,
+    users.email AS user_email,
+    users.created_at AS user_created_at,
+    users.status AS user_status,
+    users.rbac_roles AS user_roles,
+    users.avatar_url AS user_avatar_url
+FROM
+    audit_logs
+    LEFT JOIN users ON audit_logs.user_id = users.id
+    LEFT JOIN
+        -- First join on workspaces to get the initial workspace create
+        -- to workspace build 1 id. This is because the first create is
+        -- is a different audit log than subsequent starts.
+        workspaces ON
+		    audit_logs.resource_type = 'workspace' AND
+			audit_logs.resource_id = workspaces.id
+    LEFT JOIN
+	    workspace_builds ON
+            -- Get the reason from the build if the resource type
+            -- is a workspace_build
+            (
+			    audit_logs.resource_type = 'workspace_build'
+                AND audit_logs.resource_id = workspace_builds.id
+			)
+            OR
+            -- Get the reason from the build #1 if this is the first
+            -- workspace create.
+            (
+				audit_logs.resource_type = 'workspace' AND
+				audit_logs.action = 'create' AND
+				workspaces.id = workspace_builds.workspace_id AND
+				workspace_builds.build_number = 1
+			)
+WHERE
+    -- Filter resource_type
+	CASE
+		WHEN $1 :: text != '' THEN
+			resource_type = $1 :: resource_type
+		ELSE true
+	END
+	-- Filter resource_id
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			resource_id = $2
+		ELSE true
+	END
+	-- Filter by resource_target
+	AND CASE
+		WHEN $3 :: text != '' THEN
+			resource_target = $3
+		ELSE true
+	END
+	-- Filter action
+	AND CASE
+		WHEN $4 :: text != '' THEN
+			action = $4 :: audit_action
+		ELSE true
+	END
+	-- Filter by user_id
+	AND CASE
+		WHEN $5 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = $5
+		ELSE true
+	END
+	-- Filter by username
+	AND CASE
+		WHEN $6 :: text != '' THEN
+			user_id = (SELECT id FROM users WHERE lower(username) = lower($6) AND deleted = false)
+		ELSE true
+	END
+	-- Filter by user_email
+	AND CASE
+		WHEN $7 :: text != '' THEN
+			users.email = $7
+		ELSE true
+	END
+	-- Filter by date_from
+	AND CASE
+		WHEN $8 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" >= $8
+		ELSE true
+	END
+	-- Filter by date_to
+	AND CASE
+		WHEN $9 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" <= $9
+		ELSE true
+	END
+    -- Filter by build_reason
+    AND CASE
+	    WHEN $10::text != '' THEN
+            workspace_builds.reason::text = $10
+        ELSE true
+    END
+	-- Only return logs before the cursor. Logs are ordered by time and then
+	-- ID, since many logs may share the same time.
+	AND CASE
+		WHEN $11 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			("time", audit_logs.id) < ($11, $12 :: uuid)
+		ELSE true
+	END
+ORDER BY
+    "time" DESC, audit_logs.id DESC
+LIMIT
+    $13 :: int
+`
+
+type GetAuditLogsBeforeParams struct {
+	ResourceType   string    `db:"resource_type" json:"resource_type"`
+	ResourceID     uuid.UUID `db:"resource_id" json:"resource_id"`
+	ResourceTarget string    `db:"resource_target" json:"resource_target"`
+	Action         string    `db:"action" json:"action"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	Username       string    `db:"username" json:"username"`
+	Email          string    `db:"email" json:"email"`
+	DateFrom       time.Time `db:"date_from" json:"date_from"`
+	DateTo         time.Time `db:"date_to" json:"date_to"`
+	BuildReason    string    `db:"build_reason" json:"build_reason"`
+	BeforeTime     time.Time `db:"before_time" json:"before_time"`
+	BeforeID       uuid.UUID `db:"before_id" json:"before_id"`
+	RowLimit       int32     `db:"row_limit" json:"row_limit"`
+}
+
+type GetAuditLogsBeforeRow struct {
+	ID               uuid.UUID       `db:"id" json:"id"`
+	Time             time.Time       `db:"time" json:"time"`
+	UserID           uuid.UUID       `db:"user_id" json:"user_id"`
+	OrganizationID   uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Ip               pqtype.Inet     `db:"ip" json:"ip"`
+	UserAgent        sql.NullString  `db:"user_agent" json:"user_agent"`
+	ResourceType     ResourceType    `db:"resource_type" json:"resource_type"`
+	ResourceID       uuid.UUID       `db:"resource_id" json:"resource_id"`
+	ResourceTarget   string          `db:"resource_target" json:"resource_target"`
+	Action           AuditAction     `db:"action" json:"action"`
+	Diff             json.RawMessage `db:"diff" json:"diff"`
+	StatusCode       int32           `db:"status_code" json:"status_code"`
+	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
+	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
+	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
+	UserUsername     sql.NullString  `db:"user_username" json:"user_username"`
+	UserEmail        sql.NullString  `db:"user_email" json:"user_email"`
+	UserCreatedAt    sql.NullTime    `db:"user_created_at" json:"user_created_at"`
+	UserStatus       NullUserStatus  `db:"user_status" json:"user_status"`
+	UserRoles        pq.StringArray  `db:"user_roles" json:"user_roles"`
+	UserAvatarUrl    sql.NullString  `db:"user_avatar_url" json:"user_avatar_url"`
+}
+
+// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
+// cursor, which is the time and ID of the last log on the previous page. It
+// accepts the same filters as GetAuditLogsOffset, but can efficiently page
+// through any number of logs.
+func (q *sqlQuerier) GetAuditLogsBefore(ctx context.Context, arg GetAuditLogsBeforeParams) ([]GetAuditLogsBeforeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsBefore,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.ResourceTarget,
+		arg.Action,
+		arg.UserID,
+		arg.Username,
+		arg.Email,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.BuildReason,
+		arg.BeforeTime,
+		arg.BeforeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAuditLogsBeforeRow
+	for rows.Next() {
+		var i GetAuditLogsBeforeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ResourceTarget,
+			&i.Action,
+			&i.Diff,
+			&i.StatusCode,
+			&i.AdditionalFields,
+			&i.RequestID,
+			&i.ResourceIcon,
+			&i.UserUsername,
+			&i.UserEmail,
+			&i.UserCreatedAt,
+			&i.UserStatus,
+			&i.UserRoles,
+			&i.UserAvatarUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
 SELECT
     audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
@@ -579,8 +857,6 @@ type GetAuditLogsOffsetRow struct {
 	Count            int64           `db:"count" json:"count"`
 }
 
-// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
-// ID.
 func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOffsetParams) ([]GetAuditLogsOffsetRow, error) {
 	rows, err := q.db.QueryContext(ctx, getAuditLogsOffset,
 		arg.Limit,
@@ -2610,32 +2886,71 @@ func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock i
 	return pg_try_advisory_xact_lock, err
 }
 
-const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
-SELECT
-    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
-FROM
-    organization_members
+const acquireNotificationMessages = `-- name: AcquireNotificationMessages :many
+UPDATE
+	notification_messages
+SET
+	status = 'leased'::notification_message_status,
+	updated_at = $1::timestamptz,
+	leased_until = $2::timestamptz
 WHERE
-    user_id = ANY($1 :: uuid [ ])
-GROUP BY
-    user_id
+	id IN (
+		SELECT
+			nm.id
+		FROM
+			notification_messages AS nm
+		WHERE
+			(
+				nm.status = 'pending'::notification_message_status OR
+				(
+					nm.status = 'leased'::notification_message_status AND
+					nm.leased_until < $1::timestamptz
+				)
+			) AND
+			(nm.next_retry_after IS NULL OR nm.next_retry_after <= $1::timestamptz)
+		ORDER BY
+			nm.created_at ASC
+		FOR UPDATE SKIP LOCKED
+		LIMIT
+			$3::int
+	)
+RETURNING
+	id, event, user_id, method, status, status_reason, payload, attempt_count, dedupe_key, created_at, updated_at, leased_until, next_retry_after
 `
 
-type GetOrganizationIDsByMemberIDsRow struct {
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+type AcquireNotificationMessagesParams struct {
+	Now         time.Time `db:"now" json:"now"`
+	LeasedUntil time.Time `db:"leased_until" json:"leased_until"`
+	Count       int32     `db:"count" json:"count"`
 }
 
-func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
+// Acquires up to @count messages that are ready to be dispatched and leases
+// them until @leased_until. Leased messages whose lease has expired are
+// acquired again, as the dispatcher holding them is presumed to be gone.
+func (q *sqlQuerier) AcquireNotificationMessages(ctx context.Context, arg AcquireNotificationMessagesParams) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, acquireNotificationMessages, arg.Now, arg.LeasedUntil, arg.Count)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetOrganizationIDsByMemberIDsRow
+	var items []NotificationMessage
 	for rows.Next() {
-		var i GetOrganizationIDsByMemberIDsRow
-		if err := rows.Scan(&i.UserID, pq.Array(&i.OrganizationIDs)); err != nil {
+		var i NotificationMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.UserID,
+			&i.Method,
+			&i.Status,
+			&i.StatusReason,
+			&i.Payload,
+			&i.AttemptCount,
+			&i.DedupeKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LeasedUntil,
+			&i.NextRetryAfter,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -2649,36 +2964,251 @@ func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uu
 	return items, nil
 }
 
-const getOrganizationMemberByUserID = `-- name: GetOrganizationMemberByUserID :one
-SELECT
-	user_id, organization_id, created_at, updated_at, roles
-FROM
-	organization_members
+const deleteOldNotificationMessages = `-- name: DeleteOldNotificationMessages :exec
+DELETE FROM notification_messages
 WHERE
-	organization_id = $1
-	AND user_id = $2
-LIMIT
-	1
+	status IN ('sent'::notification_message_status, 'failed'::notification_message_status) AND
+	updated_at < NOW() - INTERVAL '7 days'
 `
 
-type GetOrganizationMemberByUserIDParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+// Delete messages which were delivered, or which permanently failed, more
+// than a week ago.
+func (q *sqlQuerier) DeleteOldNotificationMessages(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldNotificationMessages)
+	return err
 }
 
-func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error) {
-	row := q.db.QueryRowContext(ctx, getOrganizationMemberByUserID, arg.OrganizationID, arg.UserID)
-	var i OrganizationMember
-	err := row.Scan(
-		&i.UserID,
-		&i.OrganizationID,
-		&i.CreatedAt,
+const enqueueNotificationMessage = `-- name: EnqueueNotificationMessage :exec
+INSERT INTO notification_messages
+	(id, event, user_id, method, payload, dedupe_key, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (dedupe_key) DO NOTHING
+`
+
+type EnqueueNotificationMessageParams struct {
+	ID        uuid.UUID          `db:"id" json:"id"`
+	Event     NotificationEvent  `db:"event" json:"event"`
+	UserID    uuid.UUID          `db:"user_id" json:"user_id"`
+	Method    NotificationMethod `db:"method" json:"method"`
+	Payload   StringMap          `db:"payload" json:"payload"`
+	DedupeKey sql.NullString     `db:"dedupe_key" json:"dedupe_key"`
+	CreatedAt time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `db:"updated_at" json:"updated_at"`
+}
+
+// Messages sharing a dedupe key are only ever enqueued once.
+func (q *sqlQuerier) EnqueueNotificationMessage(ctx context.Context, arg EnqueueNotificationMessageParams) error {
+	_, err := q.db.ExecContext(ctx, enqueueNotificationMessage,
+		arg.ID,
+		arg.Event,
+		arg.UserID,
+		arg.Method,
+		arg.Payload,
+		arg.DedupeKey,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const markNotificationMessageFailed = `-- name: MarkNotificationMessageFailed :exec
+UPDATE
+	notification_messages
+SET
+	status = $1::notification_message_status,
+	status_reason = $2::text,
+	attempt_count = attempt_count + 1,
+	updated_at = $3::timestamptz,
+	leased_until = NULL,
+	next_retry_after = $4
+WHERE
+	id = $5::uuid
+`
+
+type MarkNotificationMessageFailedParams struct {
+	Status         NotificationMessageStatus `db:"status" json:"status"`
+	StatusReason   string                    `db:"status_reason" json:"status_reason"`
+	UpdatedAt      time.Time                 `db:"updated_at" json:"updated_at"`
+	NextRetryAfter sql.NullTime              `db:"next_retry_after" json:"next_retry_after"`
+	ID             uuid.UUID                 `db:"id" json:"id"`
+}
+
+// Records a failed delivery attempt. A status of 'pending' schedules the
+// message to be retried after @next_retry_after, while 'failed' gives up on
+// it entirely.
+func (q *sqlQuerier) MarkNotificationMessageFailed(ctx context.Context, arg MarkNotificationMessageFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markNotificationMessageFailed,
+		arg.Status,
+		arg.StatusReason,
+		arg.UpdatedAt,
+		arg.NextRetryAfter,
+		arg.ID,
+	)
+	return err
+}
+
+const markNotificationMessageSent = `-- name: MarkNotificationMessageSent :exec
+UPDATE
+	notification_messages
+SET
+	status = 'sent'::notification_message_status,
+	status_reason = NULL,
+	attempt_count = attempt_count + 1,
+	updated_at = $1::timestamptz,
+	leased_until = NULL,
+	next_retry_after = NULL
+WHERE
+	id = $2::uuid
+`
+
+type MarkNotificationMessageSentParams struct {
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) MarkNotificationMessageSent(ctx context.Context, arg MarkNotificationMessageSentParams) error {
+	_, err := q.db.ExecContext(ctx, markNotificationMessageSent, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const deleteOrganizationMember = `-- name: DeleteOrganizationMember :exec
+DELETE FROM
+	organization_members
+WHERE
+	organization_id = $1
+	AND user_id = $2
+`
+
+type DeleteOrganizationMemberParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganizationMember, arg.OrganizationID, arg.UserID)
+	return err
+}
+
+const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
+SELECT
+    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
+FROM
+    organization_members
+WHERE
+    user_id = ANY($1 :: uuid [ ])
+GROUP BY
+    user_id
+`
+
+type GetOrganizationIDsByMemberIDsRow struct {
+	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
+	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+}
+
+func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrganizationIDsByMemberIDsRow
+	for rows.Next() {
+		var i GetOrganizationIDsByMemberIDsRow
+		if err := rows.Scan(&i.UserID, pq.Array(&i.OrganizationIDs)); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrganizationMemberByUserID = `-- name: GetOrganizationMemberByUserID :one
+SELECT
+	user_id, organization_id, created_at, updated_at, roles
+FROM
+	organization_members
+WHERE
+	organization_id = $1
+	AND user_id = $2
+LIMIT
+	1
+`
+
+type GetOrganizationMemberByUserIDParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationMemberByUserID, arg.OrganizationID, arg.UserID)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.UserID,
+		&i.OrganizationID,
+		&i.CreatedAt,
 		&i.UpdatedAt,
 		pq.Array(&i.Roles),
 	)
 	return i, err
 }
 
+const getOrganizationMembers = `-- name: GetOrganizationMembers :many
+SELECT
+	organization_members.user_id, organization_members.organization_id, organization_members.created_at, organization_members.updated_at, organization_members.roles,
+	users.username
+FROM
+	organization_members
+INNER JOIN
+	users ON organization_members.user_id = users.id
+WHERE
+	organization_members.organization_id = $1
+	AND users.deleted = false
+ORDER BY
+	users.username ASC
+`
+
+type GetOrganizationMembersRow struct {
+	OrganizationMember OrganizationMember `db:"organization_member" json:"organization_member"`
+	Username           string             `db:"username" json:"username"`
+}
+
+func (q *sqlQuerier) GetOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]GetOrganizationMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationMembers, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrganizationMembersRow
+	for rows.Next() {
+		var i GetOrganizationMembersRow
+		if err := rows.Scan(
+			&i.OrganizationMember.UserID,
+			&i.OrganizationMember.OrganizationID,
+			&i.OrganizationMember.CreatedAt,
+			&i.OrganizationMember.UpdatedAt,
+			pq.Array(&i.OrganizationMember.Roles),
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getOrganizationMembershipsByUserID = `-- name: GetOrganizationMembershipsByUserID :many
 SELECT
 	user_id, organization_id, created_at, updated_at, roles
@@ -3019,7 +3549,7 @@ func (q *sqlQuerier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 
 const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
 SELECT
-	id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version
+	id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version, key_id
 FROM
 	provisioner_daemons
 `
@@ -3043,6 +3573,7 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 			&i.LastSeenAt,
 			&i.Version,
 			&i.APIVersion,
+			&i.KeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -3057,6 +3588,27 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 	return items, nil
 }
 
+const updateProvisionerDaemonLastSeenAt = `-- name: UpdateProvisionerDaemonLastSeenAt :exec
+UPDATE provisioner_daemons
+SET
+	last_seen_at = $1
+WHERE
+	id = $2
+	AND (last_seen_at IS NULL OR last_seen_at <= $1)
+`
+
+type UpdateProvisionerDaemonLastSeenAtParams struct {
+	LastSeenAt sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
+}
+
+// Connected daemons heartbeat through this query. The comparison keeps a
+// delayed heartbeat from moving last_seen_at backwards.
+func (q *sqlQuerier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerDaemonLastSeenAt, arg.LastSeenAt, arg.ID)
+	return err
+}
+
 const upsertProvisionerDaemon = `-- name: UpsertProvisionerDaemon :one
 INSERT INTO
 	provisioner_daemons (
@@ -3067,7 +3619,8 @@ INSERT INTO
 		tags,
 		last_seen_at,
 		"version",
-		api_version
+		api_version,
+		key_id
 	)
 VALUES (
 	gen_random_uuid(),
@@ -3077,17 +3630,19 @@ VALUES (
 	$4,
 	$5,
 	$6,
-	$7
+	$7,
+	$8
 ) ON CONFLICT("name", lower((tags ->> 'owner'::text))) DO UPDATE SET
 	provisioners = $3,
 	tags = $4,
 	last_seen_at = $5,
 	"version" = $6,
-	api_version = $7
+	api_version = $7,
+	key_id = $8
 WHERE
 	-- Only ones with the same tags are allowed clobber
 	provisioner_daemons.tags <@ $4 :: jsonb
-RETURNING id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version
+RETURNING id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version, key_id
 `
 
 type UpsertProvisionerDaemonParams struct {
@@ -3098,6 +3653,7 @@ type UpsertProvisionerDaemonParams struct {
 	LastSeenAt   sql.NullTime      `db:"last_seen_at" json:"last_seen_at"`
 	Version      string            `db:"version" json:"version"`
 	APIVersion   string            `db:"api_version" json:"api_version"`
+	KeyID        uuid.NullUUID     `db:"key_id" json:"key_id"`
 }
 
 func (q *sqlQuerier) UpsertProvisionerDaemon(ctx context.Context, arg UpsertProvisionerDaemonParams) (ProvisionerDaemon, error) {
@@ -3109,6 +3665,7 @@ func (q *sqlQuerier) UpsertProvisionerDaemon(ctx context.Context, arg UpsertProv
 		arg.LastSeenAt,
 		arg.Version,
 		arg.APIVersion,
+		arg.KeyID,
 	)
 	var i ProvisionerDaemon
 	err := row.Scan(
@@ -3121,10 +3678,59 @@ func (q *sqlQuerier) UpsertProvisionerDaemon(ctx context.Context, arg UpsertProv
 		&i.LastSeenAt,
 		&i.Version,
 		&i.APIVersion,
+		&i.KeyID,
 	)
 	return i, err
 }
 
+const countOldProvisionerJobLogs = `-- name: CountOldProvisionerJobLogs :one
+SELECT
+	COUNT(*)
+FROM
+	provisioner_job_logs
+WHERE
+	created_at < $1
+	AND job_id IN (SELECT id FROM provisioner_jobs WHERE completed_at IS NOT NULL)
+`
+
+func (q *sqlQuerier) CountOldProvisionerJobLogs(ctx context.Context, beforeTime time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOldProvisionerJobLogs, beforeTime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteOldProvisionerJobLogs = `-- name: DeleteOldProvisionerJobLogs :execrows
+DELETE FROM provisioner_job_logs WHERE id IN (
+	SELECT
+		id
+	FROM
+		provisioner_job_logs
+	WHERE
+		created_at < $1
+		AND job_id IN (SELECT id FROM provisioner_jobs WHERE completed_at IS NOT NULL)
+	ORDER BY
+		id ASC
+	LIMIT
+		$2 :: int
+)
+`
+
+type DeleteOldProvisionerJobLogsParams struct {
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Delete at most limit_count logs of completed provisioner jobs that are older
+// than before_time.
+func (q *sqlQuerier) DeleteOldProvisionerJobLogs(ctx context.Context, arg DeleteOldProvisionerJobLogsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldProvisionerJobLogs, arg.BeforeTime, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
 SELECT
 	job_id, created_at, source, level, stage, output, id
@@ -3251,34 +3857,86 @@ WHERE
 			AND nested.provisioner = ANY($3 :: provisioner_type [ ])
 			-- Ensure the caller satisfies all job tags.
 			AND nested.tags <@ $4 :: jsonb
+			AND (
+				$5 :: integer < 1
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						provisioner_jobs AS running
+					WHERE
+						running.initiator_id = nested.initiator_id
+						AND running.started_at IS NOT NULL
+						AND running.completed_at IS NULL
+				) < $5 :: integer
+			)
+			AND (
+				$6 :: integer < 1
+				OR nested.template_id IS NULL
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						provisioner_jobs AS running
+					WHERE
+						running.template_id = nested.template_id
+						AND running.started_at IS NOT NULL
+						AND running.completed_at IS NULL
+				) < $6 :: integer
+			)
 		ORDER BY
+			nested.priority DESC,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					provisioner_jobs AS running
+				WHERE
+					running.initiator_id = nested.initiator_id
+					AND running.started_at IS NOT NULL
+					AND running.completed_at IS NULL
+			) ASC,
 			nested.created_at
 		FOR UPDATE
 		SKIP LOCKED
 		LIMIT
 			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, template_id
 `
 
 type AcquireProvisionerJobParams struct {
-	StartedAt sql.NullTime      `db:"started_at" json:"started_at"`
-	WorkerID  uuid.NullUUID     `db:"worker_id" json:"worker_id"`
-	Types     []ProvisionerType `db:"types" json:"types"`
-	Tags      json.RawMessage   `db:"tags" json:"tags"`
+	StartedAt                sql.NullTime      `db:"started_at" json:"started_at"`
+	WorkerID                 uuid.NullUUID     `db:"worker_id" json:"worker_id"`
+	Types                    []ProvisionerType `db:"types" json:"types"`
+	Tags                     json.RawMessage   `db:"tags" json:"tags"`
+	MaxConcurrentPerUser     int32             `db:"max_concurrent_per_user" json:"max_concurrent_per_user"`
+	MaxConcurrentPerTemplate int32             `db:"max_concurrent_per_template" json:"max_concurrent_per_template"`
 }
 
 // Acquires the lock for a single job that isn't started, completed,
 // canceled, and that matches an array of provisioner types.
 //
+// Jobs are acquired in order of priority. Among jobs of the same priority,
+// jobs of initiators with the fewest running jobs go first so that one user's
+// batch of builds doesn't starve everyone else, and then the oldest job wins.
+// Jobs whose initiator or template already has the maximum number of running
+// jobs are skipped. A maximum below 1 means there is no limit.
+//
 // SKIP LOCKED is used to jump over locked rows. This prevents
 // multiple provisioners from acquiring the same jobs. See:
 // https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
+//
+// SKIP LOCKED does not make the running job counts consistent between
+// concurrent callers, so callers that set a maximum must hold the
+// LockIDAcquireProvisionerJob advisory lock in the same transaction.
 func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error) {
 	row := q.db.QueryRowContext(ctx, acquireProvisionerJob,
 		arg.StartedAt,
 		arg.WorkerID,
 		pq.Array(arg.Types),
 		arg.Tags,
+		arg.MaxConcurrentPerUser,
+		arg.MaxConcurrentPerTemplate,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -3301,13 +3959,15 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.Priority,
+		&i.TemplateID,
 	)
 	return i, err
 }
 
 const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, template_id
 FROM
 	provisioner_jobs
 WHERE
@@ -3345,6 +4005,8 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.Priority,
+			&i.TemplateID,
 		); err != nil {
 			return nil, err
 		}
@@ -3361,7 +4023,7 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 
 const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, template_id
 FROM
 	provisioner_jobs
 WHERE
@@ -3391,13 +4053,87 @@ func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (P
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.Priority,
+		&i.TemplateID,
 	)
 	return i, err
 }
 
+const getProvisionerJobResourceChangesByJobID = `-- name: GetProvisionerJobResourceChangesByJobID :many
+SELECT job_id, address, type, name, action FROM provisioner_job_resource_changes
+WHERE job_id = $1
+ORDER BY address ASC
+`
+
+func (q *sqlQuerier) GetProvisionerJobResourceChangesByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobResourceChange, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobResourceChangesByJobID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobResourceChange
+	for rows.Next() {
+		var i ProvisionerJobResourceChange
+		if err := rows.Scan(
+			&i.JobID,
+			&i.Address,
+			&i.Type,
+			&i.Name,
+			&i.Action,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProvisionerJobTimingsByJobID = `-- name: GetProvisionerJobTimingsByJobID :many
+SELECT job_id, started_at, ended_at, stage, source, action, resource FROM provisioner_job_timings
+WHERE job_id = $1
+ORDER BY started_at ASC
+`
+
+func (q *sqlQuerier) GetProvisionerJobTimingsByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobTiming, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobTimingsByJobID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobTiming
+	for rows.Next() {
+		var i ProvisionerJobTiming
+		if err := rows.Scan(
+			&i.JobID,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Stage,
+			&i.Source,
+			&i.Action,
+			&i.Resource,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, template_id
 FROM
 	provisioner_jobs
 WHERE
@@ -3433,6 +4169,8 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.Priority,
+			&i.TemplateID,
 		); err != nil {
 			return nil, err
 		}
@@ -3450,7 +4188,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 const getProvisionerJobsByIDsWithQueuePosition = `-- name: GetProvisionerJobsByIDsWithQueuePosition :many
 WITH unstarted_jobs AS (
     SELECT
-        id, created_at
+        id, created_at, priority
     FROM
         provisioner_jobs
     WHERE
@@ -3459,7 +4197,7 @@ WITH unstarted_jobs AS (
 queue_position AS (
     SELECT
         id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+        ROW_NUMBER() OVER (ORDER BY priority DESC, created_at ASC) AS queue_position
     FROM
         unstarted_jobs
 ),
@@ -3467,7 +4205,7 @@ queue_size AS (
 	SELECT COUNT(*) as count FROM unstarted_jobs
 )
 SELECT
-	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status,
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.priority, pj.template_id,
     COALESCE(qp.queue_position, 0) AS queue_position,
     COALESCE(qs.count, 0) AS queue_size
 FROM
@@ -3515,6 +4253,8 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 			&i.ProvisionerJob.ErrorCode,
 			&i.ProvisionerJob.TraceMetadata,
 			&i.ProvisionerJob.JobStatus,
+			&i.ProvisionerJob.Priority,
+			&i.ProvisionerJob.TemplateID,
 			&i.QueuePosition,
 			&i.QueueSize,
 		); err != nil {
@@ -3532,7 +4272,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 }
 
 const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status FROM provisioner_jobs WHERE created_at > $1
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, template_id FROM provisioner_jobs WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
@@ -3564,6 +4304,8 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 			&i.ErrorCode,
 			&i.TraceMetadata,
 			&i.JobStatus,
+			&i.Priority,
+			&i.TemplateID,
 		); err != nil {
 			return nil, err
 		}
@@ -3592,10 +4334,12 @@ INSERT INTO
 		"type",
 		"input",
 		tags,
-		trace_metadata
+		trace_metadata,
+		priority,
+		template_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, template_id
 `
 
 type InsertProvisionerJobParams struct {
@@ -3611,6 +4355,8 @@ type InsertProvisionerJobParams struct {
 	Input          json.RawMessage          `db:"input" json:"input"`
 	Tags           StringMap                `db:"tags" json:"tags"`
 	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
+	Priority       ProvisionerJobPriority   `db:"priority" json:"priority"`
+	TemplateID     uuid.NullUUID            `db:"template_id" json:"template_id"`
 }
 
 func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
@@ -3627,6 +4373,8 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		arg.Input,
 		arg.Tags,
 		arg.TraceMetadata,
+		arg.Priority,
+		arg.TemplateID,
 	)
 	var i ProvisionerJob
 	err := row.Scan(
@@ -3649,14 +4397,134 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		&i.ErrorCode,
 		&i.TraceMetadata,
 		&i.JobStatus,
+		&i.Priority,
+		&i.TemplateID,
 	)
 	return i, err
 }
 
-const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
-UPDATE
-	provisioner_jobs
-SET
+const insertProvisionerJobResourceChanges = `-- name: InsertProvisionerJobResourceChanges :many
+INSERT INTO
+	provisioner_job_resource_changes (job_id, address, type, name, action)
+SELECT
+	$1 :: uuid AS job_id,
+	unnest($2 :: text [ ]) AS address,
+	unnest($3 :: text [ ]) AS type,
+	unnest($4 :: text [ ]) AS name,
+	unnest($5 :: provisioner_job_resource_change_action [ ]) AS action
+RETURNING job_id, address, type, name, action
+`
+
+type InsertProvisionerJobResourceChangesParams struct {
+	JobID   uuid.UUID                            `db:"job_id" json:"job_id"`
+	Address []string                             `db:"address" json:"address"`
+	Type    []string                             `db:"type" json:"type"`
+	Name    []string                             `db:"name" json:"name"`
+	Action  []ProvisionerJobResourceChangeAction `db:"action" json:"action"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobResourceChanges(ctx context.Context, arg InsertProvisionerJobResourceChangesParams) ([]ProvisionerJobResourceChange, error) {
+	rows, err := q.db.QueryContext(ctx, insertProvisionerJobResourceChanges,
+		arg.JobID,
+		pq.Array(arg.Address),
+		pq.Array(arg.Type),
+		pq.Array(arg.Name),
+		pq.Array(arg.Action),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobResourceChange
+	for rows.Next() {
+		var i ProvisionerJobResourceChange
+		if err := rows.Scan(
+			&i.JobID,
+			&i.Address,
+			&i.Type,
+			&i.Name,
+			&i.Action,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertProvisionerJobTimings = `-- name: InsertProvisionerJobTimings :many
+INSERT INTO
+	provisioner_job_timings (job_id, started_at, ended_at, stage, source, action, resource)
+SELECT
+	$1 :: uuid AS job_id,
+	unnest($2 :: timestamptz [ ]) AS started_at,
+	unnest($3 :: timestamptz [ ]) AS ended_at,
+	unnest($4 :: provisioner_job_timing_stage [ ]) AS stage,
+	unnest($5 :: text [ ]) AS source,
+	unnest($6 :: text [ ]) AS action,
+	unnest($7 :: text [ ]) AS resource
+RETURNING job_id, started_at, ended_at, stage, source, action, resource
+`
+
+type InsertProvisionerJobTimingsParams struct {
+	JobID     uuid.UUID                   `db:"job_id" json:"job_id"`
+	StartedAt []time.Time                 `db:"started_at" json:"started_at"`
+	EndedAt   []time.Time                 `db:"ended_at" json:"ended_at"`
+	Stage     []ProvisionerJobTimingStage `db:"stage" json:"stage"`
+	Source    []string                    `db:"source" json:"source"`
+	Action    []string                    `db:"action" json:"action"`
+	Resource  []string                    `db:"resource" json:"resource"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobTimings(ctx context.Context, arg InsertProvisionerJobTimingsParams) ([]ProvisionerJobTiming, error) {
+	rows, err := q.db.QueryContext(ctx, insertProvisionerJobTimings,
+		arg.JobID,
+		pq.Array(arg.StartedAt),
+		pq.Array(arg.EndedAt),
+		pq.Array(arg.Stage),
+		pq.Array(arg.Source),
+		pq.Array(arg.Action),
+		pq.Array(arg.Resource),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobTiming
+	for rows.Next() {
+		var i ProvisionerJobTiming
+		if err := rows.Scan(
+			&i.JobID,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Stage,
+			&i.Source,
+			&i.Action,
+			&i.Resource,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
+UPDATE
+	provisioner_jobs
+SET
 	updated_at = $2
 WHERE
 	id = $1
@@ -3724,6 +4592,155 @@ func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, a
 	return err
 }
 
+const deleteProvisionerKey = `-- name: DeleteProvisionerKey :exec
+DELETE FROM
+	provisioner_keys
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProvisionerKey, id)
+	return err
+}
+
+const getProvisionerKeyByID = `-- name: GetProvisionerKeyByID :one
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags
+FROM
+	provisioner_keys
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerKeyByID, id)
+	var i ProvisionerKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const getProvisionerKeyByName = `-- name: GetProvisionerKeyByName :one
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags
+FROM
+	provisioner_keys
+WHERE
+	organization_id = $1
+	AND lower("name") = lower($2)
+`
+
+type GetProvisionerKeyByNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetProvisionerKeyByName(ctx context.Context, arg GetProvisionerKeyByNameParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerKeyByName, arg.OrganizationID, arg.Name)
+	var i ProvisionerKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const insertProvisionerKey = `-- name: InsertProvisionerKey :one
+INSERT INTO
+	provisioner_keys (
+		id,
+		created_at,
+		organization_id,
+		"name",
+		hashed_secret,
+		tags
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, created_at, organization_id, name, hashed_secret, tags
+`
+
+type InsertProvisionerKeyParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
+	Tags           StringMap `db:"tags" json:"tags"`
+}
+
+func (q *sqlQuerier) InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerKey,
+		arg.ID,
+		arg.CreatedAt,
+		arg.OrganizationID,
+		arg.Name,
+		arg.HashedSecret,
+		arg.Tags,
+	)
+	var i ProvisionerKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const listProvisionerKeysByOrganization = `-- name: ListProvisionerKeysByOrganization :many
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags
+FROM
+	provisioner_keys
+WHERE
+	organization_id = $1
+ORDER BY
+	"name" ASC
+`
+
+func (q *sqlQuerier) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error) {
+	rows, err := q.db.QueryContext(ctx, listProvisionerKeysByOrganization, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerKey
+	for rows.Next() {
+		var i ProvisionerKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.OrganizationID,
+			&i.Name,
+			&i.HashedSecret,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
 SELECT
 	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
@@ -4341,6 +5358,154 @@ func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams)
 	return i, err
 }
 
+const customRoles = `-- name: CustomRoles :many
+SELECT
+	name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+FROM
+	custom_roles
+WHERE
+	true
+	-- Lookup roles filter expects the role names to be in the rbac package
+	-- format, which is name[:<organization_id>].
+	AND CASE WHEN array_length($1 :: text[], 1) > 0 THEN
+		-- The organization id is only appended for organization roles.
+		concat(name, NULLIF(concat(':', organization_id), ':')) = ANY($1 :: text[])
+	ELSE true
+	END
+	-- Only fetch site wide roles.
+	AND CASE WHEN $2 :: boolean THEN
+		organization_id IS NULL
+	ELSE true
+	END
+	AND CASE WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+		organization_id = $3
+	ELSE true
+	END
+ORDER BY
+	name ASC
+`
+
+type CustomRolesParams struct {
+	LookupRoles     []string  `db:"lookup_roles" json:"lookup_roles"`
+	ExcludeOrgRoles bool      `db:"exclude_org_roles" json:"exclude_org_roles"`
+	OrganizationID  uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) CustomRoles(ctx context.Context, arg CustomRolesParams) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, customRoles, pq.Array(arg.LookupRoles), arg.ExcludeOrgRoles, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CustomRole
+	for rows.Next() {
+		var i CustomRole
+		if err := rows.Scan(
+			&i.Name,
+			&i.DisplayName,
+			&i.OrganizationID,
+			&i.SitePermissions,
+			&i.OrgPermissions,
+			&i.UserPermissions,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteCustomRole = `-- name: DeleteCustomRole :exec
+DELETE FROM
+	custom_roles
+WHERE
+	name = lower($1)
+	AND organization_id IS NOT DISTINCT FROM $2
+`
+
+type DeleteCustomRoleParams struct {
+	Name           string        `db:"name" json:"name"`
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) DeleteCustomRole(ctx context.Context, arg DeleteCustomRoleParams) error {
+	_, err := q.db.ExecContext(ctx, deleteCustomRole, arg.Name, arg.OrganizationID)
+	return err
+}
+
+const upsertCustomRole = `-- name: UpsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES (
+	-- Role names are always lowercase.
+	lower($1),
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	now(),
+	now()
+)
+ON CONFLICT (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid))
+	DO UPDATE SET
+		display_name = $2,
+		site_permissions = $4,
+		org_permissions = $5,
+		user_permissions = $6,
+		updated_at = now()
+RETURNING name, display_name, organization_id, site_permissions, org_permissions, user_permissions, created_at, updated_at
+`
+
+type UpsertCustomRoleParams struct {
+	Name            string                `db:"name" json:"name"`
+	DisplayName     string                `db:"display_name" json:"display_name"`
+	OrganizationID  uuid.NullUUID         `db:"organization_id" json:"organization_id"`
+	SitePermissions CustomRolePermissions `db:"site_permissions" json:"site_permissions"`
+	OrgPermissions  CustomRolePermissions `db:"org_permissions" json:"org_permissions"`
+	UserPermissions CustomRolePermissions `db:"user_permissions" json:"user_permissions"`
+}
+
+func (q *sqlQuerier) UpsertCustomRole(ctx context.Context, arg UpsertCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, upsertCustomRole,
+		arg.Name,
+		arg.DisplayName,
+		arg.OrganizationID,
+		arg.SitePermissions,
+		arg.OrgPermissions,
+		arg.UserPermissions,
+	)
+	var i CustomRole
+	err := row.Scan(
+		&i.Name,
+		&i.DisplayName,
+		&i.OrganizationID,
+		&i.SitePermissions,
+		&i.OrgPermissions,
+		&i.UserPermissions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getAppSecurityKey = `-- name: GetAppSecurityKey :one
 SELECT value FROM site_configs WHERE key = 'app_signing_key'
 `
@@ -4448,6 +5613,18 @@ func (q *sqlQuerier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	return value, err
 }
 
+const getOIDCSyncRules = `-- name: GetOIDCSyncRules :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'oidc_sync_rules'), '{}') :: text AS oidc_sync_rules
+`
+
+func (q *sqlQuerier) GetOIDCSyncRules(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getOIDCSyncRules)
+	var oidc_sync_rules string
+	err := row.Scan(&oidc_sync_rules)
+	return oidc_sync_rules, err
+}
+
 const getServiceBanner = `-- name: GetServiceBanner :one
 SELECT value FROM site_configs WHERE key = 'service_banner'
 `
@@ -4560,6 +5737,16 @@ func (q *sqlQuerier) UpsertOAuthSigningKey(ctx context.Context, value string) er
 	return err
 }
 
+const upsertOIDCSyncRules = `-- name: UpsertOIDCSyncRules :exec
+INSERT INTO site_configs (key, value) VALUES ('oidc_sync_rules', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'oidc_sync_rules'
+`
+
+func (q *sqlQuerier) UpsertOIDCSyncRules(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertOIDCSyncRules, value)
+	return err
+}
+
 const upsertServiceBanner = `-- name: UpsertServiceBanner :exec
 INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner'
@@ -5284,10 +6471,238 @@ func (q *sqlQuerier) UpsertTailnetTunnel(ctx context.Context, arg UpsertTailnetT
 	row := q.db.QueryRowContext(ctx, upsertTailnetTunnel, arg.CoordinatorID, arg.SrcID, arg.DstID)
 	var i TailnetTunnel
 	err := row.Scan(
-		&i.CoordinatorID,
-		&i.SrcID,
-		&i.DstID,
+		&i.CoordinatorID,
+		&i.SrcID,
+		&i.DstID,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTemplateGitSource = `-- name: DeleteTemplateGitSource :exec
+DELETE FROM
+	template_git_sources
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) DeleteTemplateGitSource(ctx context.Context, templateID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateGitSource, templateID)
+	return err
+}
+
+const getTemplateGitSourceByTemplateID = `-- name: GetTemplateGitSourceByTemplateID :one
+SELECT
+	template_id, created_at, updated_at, created_by, repository_url, branch, subdirectory, auto_promote, poll_interval, webhook_secret, last_checked_at, last_commit_sha, last_template_version_id, last_error, webhook_secret_key_id
+FROM
+	template_git_sources
+WHERE
+	template_id = $1
+`
+
+func (q *sqlQuerier) GetTemplateGitSourceByTemplateID(ctx context.Context, templateID uuid.UUID) (TemplateGitSource, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateGitSourceByTemplateID, templateID)
+	var i TemplateGitSource
+	err := row.Scan(
+		&i.TemplateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.RepositoryURL,
+		&i.Branch,
+		&i.Subdirectory,
+		&i.AutoPromote,
+		&i.PollInterval,
+		&i.WebhookSecret,
+		&i.LastCheckedAt,
+		&i.LastCommitSHA,
+		&i.LastTemplateVersionID,
+		&i.LastError,
+		&i.WebhookSecretKeyID,
+	)
+	return i, err
+}
+
+const getTemplateGitSources = `-- name: GetTemplateGitSources :many
+SELECT
+	template_id, created_at, updated_at, created_by, repository_url, branch, subdirectory, auto_promote, poll_interval, webhook_secret, last_checked_at, last_commit_sha, last_template_version_id, last_error, webhook_secret_key_id
+FROM
+	template_git_sources
+ORDER BY
+	template_id
+`
+
+func (q *sqlQuerier) GetTemplateGitSources(ctx context.Context) ([]TemplateGitSource, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateGitSources)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateGitSource
+	for rows.Next() {
+		var i TemplateGitSource
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.RepositoryURL,
+			&i.Branch,
+			&i.Subdirectory,
+			&i.AutoPromote,
+			&i.PollInterval,
+			&i.WebhookSecret,
+			&i.LastCheckedAt,
+			&i.LastCommitSHA,
+			&i.LastTemplateVersionID,
+			&i.LastError,
+			&i.WebhookSecretKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTemplateGitSourceSyncStatus = `-- name: UpdateTemplateGitSourceSyncStatus :exec
+UPDATE
+	template_git_sources
+SET
+	updated_at = $1,
+	last_checked_at = $2,
+	last_commit_sha = $3,
+	last_template_version_id = $4,
+	last_error = $5
+WHERE
+	template_id = $6
+`
+
+type UpdateTemplateGitSourceSyncStatusParams struct {
+	UpdatedAt             time.Time     `db:"updated_at" json:"updated_at"`
+	LastCheckedAt         sql.NullTime  `db:"last_checked_at" json:"last_checked_at"`
+	LastCommitSHA         string        `db:"last_commit_sha" json:"last_commit_sha"`
+	LastTemplateVersionID uuid.NullUUID `db:"last_template_version_id" json:"last_template_version_id"`
+	LastError             string        `db:"last_error" json:"last_error"`
+	TemplateID            uuid.UUID     `db:"template_id" json:"template_id"`
+}
+
+func (q *sqlQuerier) UpdateTemplateGitSourceSyncStatus(ctx context.Context, arg UpdateTemplateGitSourceSyncStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateGitSourceSyncStatus,
+		arg.UpdatedAt,
+		arg.LastCheckedAt,
+		arg.LastCommitSHA,
+		arg.LastTemplateVersionID,
+		arg.LastError,
+		arg.TemplateID,
+	)
+	return err
+}
+
+const updateTemplateGitSourceWebhookSecret = `-- name: UpdateTemplateGitSourceWebhookSecret :exec
+UPDATE
+	template_git_sources
+SET
+	webhook_secret = $1,
+	webhook_secret_key_id = $2
+WHERE
+	template_id = $3
+`
+
+type UpdateTemplateGitSourceWebhookSecretParams struct {
+	WebhookSecret      string         `db:"webhook_secret" json:"webhook_secret"`
+	WebhookSecretKeyID sql.NullString `db:"webhook_secret_key_id" json:"webhook_secret_key_id"`
+	TemplateID         uuid.UUID      `db:"template_id" json:"template_id"`
+}
+
+// Used by dbcrypt to re-encrypt or decrypt webhook secrets.
+func (q *sqlQuerier) UpdateTemplateGitSourceWebhookSecret(ctx context.Context, arg UpdateTemplateGitSourceWebhookSecretParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateGitSourceWebhookSecret, arg.WebhookSecret, arg.WebhookSecretKeyID, arg.TemplateID)
+	return err
+}
+
+const upsertTemplateGitSource = `-- name: UpsertTemplateGitSource :one
+INSERT INTO
+	template_git_sources (
+		template_id,
+		created_at,
+		updated_at,
+		created_by,
+		repository_url,
+		branch,
+		subdirectory,
+		auto_promote,
+		poll_interval,
+		webhook_secret,
+		webhook_secret_key_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (template_id) DO UPDATE
+SET
+	updated_at = $3,
+	created_by = $4,
+	repository_url = $5,
+	branch = $6,
+	subdirectory = $7,
+	auto_promote = $8,
+	poll_interval = $9,
+	webhook_secret = $10,
+	webhook_secret_key_id = $11
+RETURNING template_id, created_at, updated_at, created_by, repository_url, branch, subdirectory, auto_promote, poll_interval, webhook_secret, last_checked_at, last_commit_sha, last_template_version_id, last_error, webhook_secret_key_id
+`
+
+type UpsertTemplateGitSourceParams struct {
+	TemplateID         uuid.UUID      `db:"template_id" json:"template_id"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+	CreatedBy          uuid.UUID      `db:"created_by" json:"created_by"`
+	RepositoryURL      string         `db:"repository_url" json:"repository_url"`
+	Branch             string         `db:"branch" json:"branch"`
+	Subdirectory       string         `db:"subdirectory" json:"subdirectory"`
+	AutoPromote        bool           `db:"auto_promote" json:"auto_promote"`
+	PollInterval       int64          `db:"poll_interval" json:"poll_interval"`
+	WebhookSecret      string         `db:"webhook_secret" json:"webhook_secret"`
+	WebhookSecretKeyID sql.NullString `db:"webhook_secret_key_id" json:"webhook_secret_key_id"`
+}
+
+func (q *sqlQuerier) UpsertTemplateGitSource(ctx context.Context, arg UpsertTemplateGitSourceParams) (TemplateGitSource, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateGitSource,
+		arg.TemplateID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.CreatedBy,
+		arg.RepositoryURL,
+		arg.Branch,
+		arg.Subdirectory,
+		arg.AutoPromote,
+		arg.PollInterval,
+		arg.WebhookSecret,
+		arg.WebhookSecretKeyID,
+	)
+	var i TemplateGitSource
+	err := row.Scan(
+		&i.TemplateID,
+		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.RepositoryURL,
+		&i.Branch,
+		&i.Subdirectory,
+		&i.AutoPromote,
+		&i.PollInterval,
+		&i.WebhookSecret,
+		&i.LastCheckedAt,
+		&i.LastCommitSHA,
+		&i.LastTemplateVersionID,
+		&i.LastError,
+		&i.WebhookSecretKeyID,
 	)
 	return i, err
 }
@@ -5354,7 +6769,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTem
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, record_sessions, created_by_avatar_url, created_by_username
 FROM
 	template_with_users
 WHERE
@@ -5395,6 +6810,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.RequireActiveVersion,
 		&i.Deprecated,
 		&i.UseMaxTtl,
+		&i.RecordSessions,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -5403,7 +6819,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, record_sessions, created_by_avatar_url, created_by_username
 FROM
 	template_with_users AS templates
 WHERE
@@ -5452,6 +6868,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.RequireActiveVersion,
 		&i.Deprecated,
 		&i.UseMaxTtl,
+		&i.RecordSessions,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -5459,7 +6876,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, created_by_avatar_url, created_by_username FROM template_with_users AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, record_sessions, created_by_avatar_url, created_by_username FROM template_with_users AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -5501,6 +6918,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.RequireActiveVersion,
 			&i.Deprecated,
 			&i.UseMaxTtl,
+			&i.RecordSessions,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -5519,7 +6937,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, record_sessions, created_by_avatar_url, created_by_username
 FROM
 	template_with_users AS templates
 WHERE
@@ -5611,6 +7029,7 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.RequireActiveVersion,
 			&i.Deprecated,
 			&i.UseMaxTtl,
+			&i.RecordSessions,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -5779,7 +7198,8 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	record_sessions = $8
 WHERE
 	id = $1
 `
@@ -5792,6 +7212,7 @@ type UpdateTemplateMetaByIDParams struct {
 	Icon                         string    `db:"icon" json:"icon"`
 	DisplayName                  string    `db:"display_name" json:"display_name"`
 	AllowUserCancelWorkspaceJobs bool      `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	RecordSessions               bool      `db:"record_sessions" json:"record_sessions"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -5803,6 +7224,7 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.Icon,
 		arg.DisplayName,
 		arg.AllowUserCancelWorkspaceJobs,
+		arg.RecordSessions,
 	)
 	return err
 }
@@ -6129,7 +7551,7 @@ func (q *sqlQuerier) ArchiveUnusedTemplateVersions(ctx context.Context, arg Arch
 
 const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_commit_message, created_by_avatar_url, created_by_username
 FROM
 	template_version_with_user AS template_versions
 WHERE
@@ -6166,6 +7588,8 @@ func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPrev
 		pq.Array(&i.ExternalAuthProviders),
 		&i.Message,
 		&i.Archived,
+		&i.GitCommitSHA,
+		&i.GitCommitMessage,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -6174,7 +7598,7 @@ func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPrev
 
 const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_commit_message, created_by_avatar_url, created_by_username
 FROM
 	template_version_with_user AS template_versions
 WHERE
@@ -6197,6 +7621,8 @@ func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (
 		pq.Array(&i.ExternalAuthProviders),
 		&i.Message,
 		&i.Archived,
+		&i.GitCommitSHA,
+		&i.GitCommitMessage,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -6205,7 +7631,7 @@ func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (
 
 const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_commit_message, created_by_avatar_url, created_by_username
 FROM
 	template_version_with_user AS template_versions
 WHERE
@@ -6228,6 +7654,8 @@ func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.U
 		pq.Array(&i.ExternalAuthProviders),
 		&i.Message,
 		&i.Archived,
+		&i.GitCommitSHA,
+		&i.GitCommitMessage,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -6236,7 +7664,7 @@ func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.U
 
 const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_commit_message, created_by_avatar_url, created_by_username
 FROM
 	template_version_with_user AS template_versions
 WHERE
@@ -6265,6 +7693,8 @@ func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context,
 		pq.Array(&i.ExternalAuthProviders),
 		&i.Message,
 		&i.Archived,
+		&i.GitCommitSHA,
+		&i.GitCommitMessage,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -6273,7 +7703,7 @@ func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context,
 
 const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_commit_message, created_by_avatar_url, created_by_username
 FROM
 	template_version_with_user AS template_versions
 WHERE
@@ -6302,6 +7732,8 @@ func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UU
 			pq.Array(&i.ExternalAuthProviders),
 			&i.Message,
 			&i.Archived,
+			&i.GitCommitSHA,
+			&i.GitCommitMessage,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -6320,7 +7752,7 @@ func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UU
 
 const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_commit_message, created_by_avatar_url, created_by_username
 FROM
 	template_version_with_user AS template_versions
 WHERE
@@ -6396,6 +7828,8 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 			pq.Array(&i.ExternalAuthProviders),
 			&i.Message,
 			&i.Archived,
+			&i.GitCommitSHA,
+			&i.GitCommitMessage,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -6413,7 +7847,7 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 }
 
 const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
-SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username FROM template_version_with_user AS template_versions WHERE created_at > $1
+SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_commit_message, created_by_avatar_url, created_by_username FROM template_version_with_user AS template_versions WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
@@ -6438,6 +7872,8 @@ func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, create
 			pq.Array(&i.ExternalAuthProviders),
 			&i.Message,
 			&i.Archived,
+			&i.GitCommitSHA,
+			&i.GitCommitMessage,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -6466,23 +7902,27 @@ INSERT INTO
 		message,
 		readme,
 		job_id,
-		created_by
+		created_by,
+		git_commit_sha,
+		git_commit_message
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 `
 
 type InsertTemplateVersionParams struct {
-	ID             uuid.UUID     `db:"id" json:"id"`
-	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
-	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
-	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
-	Name           string        `db:"name" json:"name"`
-	Message        string        `db:"message" json:"message"`
-	Readme         string        `db:"readme" json:"readme"`
-	JobID          uuid.UUID     `db:"job_id" json:"job_id"`
-	CreatedBy      uuid.UUID     `db:"created_by" json:"created_by"`
+	ID               uuid.UUID     `db:"id" json:"id"`
+	TemplateID       uuid.NullUUID `db:"template_id" json:"template_id"`
+	OrganizationID   uuid.UUID     `db:"organization_id" json:"organization_id"`
+	CreatedAt        time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time     `db:"updated_at" json:"updated_at"`
+	Name             string        `db:"name" json:"name"`
+	Message          string        `db:"message" json:"message"`
+	Readme           string        `db:"readme" json:"readme"`
+	JobID            uuid.UUID     `db:"job_id" json:"job_id"`
+	CreatedBy        uuid.UUID     `db:"created_by" json:"created_by"`
+	GitCommitSHA     string        `db:"git_commit_sha" json:"git_commit_sha"`
+	GitCommitMessage string        `db:"git_commit_message" json:"git_commit_message"`
 }
 
 func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
@@ -6497,6 +7937,8 @@ func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTempla
 		arg.Readme,
 		arg.JobID,
 		arg.CreatedBy,
+		arg.GitCommitSHA,
+		arg.GitCommitMessage,
 	)
 	return err
 }
@@ -6595,8 +8037,47 @@ func (q *sqlQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx conte
 	return err
 }
 
+const getSensitiveTemplateVersionVariables = `-- name: GetSensitiveTemplateVersionVariables :many
+SELECT template_version_id, name, description, type, value, default_value, required, sensitive, value_key_id FROM template_version_variables WHERE sensitive = true
+`
+
+// Used by dbcrypt to re-encrypt or decrypt the values of all sensitive
+// variables.
+func (q *sqlQuerier) GetSensitiveTemplateVersionVariables(ctx context.Context) ([]TemplateVersionVariable, error) {
+	rows, err := q.db.QueryContext(ctx, getSensitiveTemplateVersionVariables)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionVariable
+	for rows.Next() {
+		var i TemplateVersionVariable
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Value,
+			&i.DefaultValue,
+			&i.Required,
+			&i.Sensitive,
+			&i.ValueKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
-SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
+SELECT template_version_id, name, description, type, value, default_value, required, sensitive, value_key_id FROM template_version_variables WHERE template_version_id = $1
 `
 
 func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
@@ -6617,6 +8098,7 @@ func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVe
 			&i.DefaultValue,
 			&i.Required,
 			&i.Sensitive,
+			&i.ValueKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -6641,7 +8123,8 @@ INSERT INTO
         value,
         default_value,
         required,
-        sensitive
+        sensitive,
+        value_key_id
     )
 VALUES
     (
@@ -6652,19 +8135,21 @@ VALUES
         $5,
         $6,
         $7,
-        $8
-    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
+        $8,
+        $9
+    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive, value_key_id
 `
 
 type InsertTemplateVersionVariableParams struct {
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
-	Name              string    `db:"name" json:"name"`
-	Description       string    `db:"description" json:"description"`
-	Type              string    `db:"type" json:"type"`
-	Value             string    `db:"value" json:"value"`
-	DefaultValue      string    `db:"default_value" json:"default_value"`
-	Required          bool      `db:"required" json:"required"`
-	Sensitive         bool      `db:"sensitive" json:"sensitive"`
+	TemplateVersionID uuid.UUID      `db:"template_version_id" json:"template_version_id"`
+	Name              string         `db:"name" json:"name"`
+	Description       string         `db:"description" json:"description"`
+	Type              string         `db:"type" json:"type"`
+	Value             string         `db:"value" json:"value"`
+	DefaultValue      string         `db:"default_value" json:"default_value"`
+	Required          bool           `db:"required" json:"required"`
+	Sensitive         bool           `db:"sensitive" json:"sensitive"`
+	ValueKeyID        sql.NullString `db:"value_key_id" json:"value_key_id"`
 }
 
 func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
@@ -6677,6 +8162,7 @@ func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg Inse
 		arg.DefaultValue,
 		arg.Required,
 		arg.Sensitive,
+		arg.ValueKeyID,
 	)
 	var i TemplateVersionVariable
 	err := row.Scan(
@@ -6688,10 +8174,40 @@ func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg Inse
 		&i.DefaultValue,
 		&i.Required,
 		&i.Sensitive,
+		&i.ValueKeyID,
 	)
 	return i, err
 }
 
+const updateTemplateVersionVariableValue = `-- name: UpdateTemplateVersionVariableValue :exec
+UPDATE
+	template_version_variables
+SET
+	value = $1,
+	value_key_id = $2
+WHERE
+	template_version_id = $3 AND name = $4 AND sensitive = true
+`
+
+type UpdateTemplateVersionVariableValueParams struct {
+	Value             string         `db:"value" json:"value"`
+	ValueKeyID        sql.NullString `db:"value_key_id" json:"value_key_id"`
+	TemplateVersionID uuid.UUID      `db:"template_version_id" json:"template_version_id"`
+	Name              string         `db:"name" json:"name"`
+}
+
+// Used by dbcrypt to re-encrypt or decrypt the value of a sensitive variable.
+// The values of other variables are never encrypted.
+func (q *sqlQuerier) UpdateTemplateVersionVariableValue(ctx context.Context, arg UpdateTemplateVersionVariableValueParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionVariableValue,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.TemplateVersionID,
+		arg.Name,
+	)
+	return err
+}
+
 const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
 SELECT
 	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
@@ -6714,6 +8230,7 @@ func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string)
 		&i.OAuthAccessTokenKeyID,
 		&i.OAuthRefreshTokenKeyID,
 		&i.DebugContext,
+		&i.LoginProvider,
 	)
 	return i, err
 }
@@ -6745,12 +8262,13 @@ func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUs
 		&i.OAuthAccessTokenKeyID,
 		&i.OAuthRefreshTokenKeyID,
 		&i.DebugContext,
+		&i.LoginProvider,
 	)
 	return i, err
 }
 
 const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
-SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context FROM user_links WHERE user_id = $1
+SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context, login_provider FROM user_links WHERE user_id = $1
 `
 
 func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
@@ -6772,6 +8290,7 @@ func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID)
 			&i.OAuthAccessTokenKeyID,
 			&i.OAuthRefreshTokenKeyID,
 			&i.DebugContext,
+			&i.LoginProvider,
 		); err != nil {
 			return nil, err
 		}
@@ -6797,10 +8316,11 @@ INSERT INTO
 		oauth_refresh_token,
 		oauth_refresh_token_key_id,
 		oauth_expiry,
-	    debug_context
+	    debug_context,
+		login_provider
 	)
 VALUES
-	( $1, $2, $3, $4, $5, $6, $7, $8, $9 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+	( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context, login_provider
 `
 
 type InsertUserLinkParams struct {
@@ -6813,6 +8333,7 @@ type InsertUserLinkParams struct {
 	OAuthRefreshTokenKeyID sql.NullString  `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
 	OAuthExpiry            time.Time       `db:"oauth_expiry" json:"oauth_expiry"`
 	DebugContext           json.RawMessage `db:"debug_context" json:"debug_context"`
+	LoginProvider          string          `db:"login_provider" json:"login_provider"`
 }
 
 func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
@@ -6826,6 +8347,7 @@ func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParam
 		arg.OAuthRefreshTokenKeyID,
 		arg.OAuthExpiry,
 		arg.DebugContext,
+		arg.LoginProvider,
 	)
 	var i UserLink
 	err := row.Scan(
@@ -6838,6 +8360,7 @@ func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParam
 		&i.OAuthAccessTokenKeyID,
 		&i.OAuthRefreshTokenKeyID,
 		&i.DebugContext,
+		&i.LoginProvider,
 	)
 	return i, err
 }
@@ -6853,7 +8376,7 @@ SET
 	oauth_expiry = $5,
 	debug_context = $6
 WHERE
-	user_id = $7 AND login_type = $8 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+	user_id = $7 AND login_type = $8 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context, login_provider
 `
 
 type UpdateUserLinkParams struct {
@@ -6889,6 +8412,7 @@ func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParam
 		&i.OAuthAccessTokenKeyID,
 		&i.OAuthRefreshTokenKeyID,
 		&i.DebugContext,
+		&i.LoginProvider,
 	)
 	return i, err
 }
@@ -6899,7 +8423,7 @@ UPDATE
 SET
 	linked_id = $1
 WHERE
-	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context, login_provider
 `
 
 type UpdateUserLinkedIDParams struct {
@@ -6921,6 +8445,7 @@ func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinke
 		&i.OAuthAccessTokenKeyID,
 		&i.OAuthRefreshTokenKeyID,
 		&i.DebugContext,
+		&i.LoginProvider,
 	)
 	return i, err
 }
@@ -9600,8 +11125,114 @@ func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg Ins
 	return err
 }
 
+const countOldWorkspaceBuilds = `-- name: CountOldWorkspaceBuilds :one
+SELECT
+	COUNT(*)
+FROM (
+	SELECT
+		id,
+		job_id,
+		row_number() OVER (PARTITION BY workspace_id ORDER BY build_number DESC) AS build_rank
+	FROM
+		workspace_builds
+) AS ranked_builds
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = ranked_builds.job_id
+WHERE
+	ranked_builds.build_rank > $1 :: int
+	AND provisioner_jobs.completed_at IS NOT NULL
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			workspace_app_stats
+		JOIN
+			workspace_agents ON workspace_agents.id = workspace_app_stats.agent_id
+		JOIN
+			workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+		WHERE
+			workspace_resources.job_id = ranked_builds.job_id
+	)
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			workspace_session_recordings
+		WHERE
+			workspace_session_recordings.workspace_build_id = ranked_builds.id
+	)
+`
+
+func (q *sqlQuerier) CountOldWorkspaceBuilds(ctx context.Context, keepCount int32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOldWorkspaceBuilds, keepCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteOldWorkspaceBuilds = `-- name: DeleteOldWorkspaceBuilds :execrows
+DELETE FROM provisioner_jobs WHERE id IN (
+	SELECT
+		ranked_builds.job_id
+	FROM (
+		SELECT
+			id,
+			job_id,
+			row_number() OVER (PARTITION BY workspace_id ORDER BY build_number DESC) AS build_rank
+		FROM
+			workspace_builds
+	) AS ranked_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = ranked_builds.job_id
+	WHERE
+		ranked_builds.build_rank > $1 :: int
+		AND provisioner_jobs.completed_at IS NOT NULL
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_app_stats
+			JOIN
+				workspace_agents ON workspace_agents.id = workspace_app_stats.agent_id
+			JOIN
+				workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+			WHERE
+				workspace_resources.job_id = ranked_builds.job_id
+		)
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_session_recordings
+			WHERE
+				workspace_session_recordings.workspace_build_id = ranked_builds.id
+		)
+	LIMIT
+		$2 :: int
+)
+`
+
+type DeleteOldWorkspaceBuildsParams struct {
+	KeepCount  int32 `db:"keep_count" json:"keep_count"`
+	LimitCount int32 `db:"limit_count" json:"limit_count"`
+}
+
+// Delete at most limit_count completed workspace builds that are older than
+// the keep_count most recent builds of their workspace. The provisioner job is
+// deleted and the build, its resources, agents and logs are removed by
+// cascade. Builds with recorded app usage are kept, since workspace_app_stats
+// does not cascade. Builds with session recordings are kept until the
+// recordings are deleted by their own retention.
+func (q *sqlQuerier) DeleteOldWorkspaceBuilds(ctx context.Context, arg DeleteOldWorkspaceBuildsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceBuilds, arg.KeepCount, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.provisioner_state_key_id, wb.initiator_by_avatar_url, wb.initiator_by_username
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -9655,6 +11286,7 @@ func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, t
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.ProvisionerStateKeyID,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -9673,7 +11305,7 @@ func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, t
 
 const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, provisioner_state_key_id, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -9702,6 +11334,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 		&i.Reason,
 		&i.DailyCost,
 		&i.MaxDeadline,
+		&i.ProvisionerStateKeyID,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 	)
@@ -9709,7 +11342,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 }
 
 const getLatestWorkspaceBuilds = `-- name: GetLatestWorkspaceBuilds :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.provisioner_state_key_id, wb.initiator_by_avatar_url, wb.initiator_by_username
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -9747,6 +11380,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.ProvisionerStateKeyID,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -9764,7 +11398,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 }
 
 const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.provisioner_state_key_id, wb.initiator_by_avatar_url, wb.initiator_by_username
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -9804,6 +11438,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.ProvisionerStateKeyID,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -9822,7 +11457,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 
 const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, provisioner_state_key_id, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -9849,6 +11484,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 		&i.Reason,
 		&i.DailyCost,
 		&i.MaxDeadline,
+		&i.ProvisionerStateKeyID,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 	)
@@ -9857,7 +11493,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 
 const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, provisioner_state_key_id, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -9884,6 +11520,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 		&i.Reason,
 		&i.DailyCost,
 		&i.MaxDeadline,
+		&i.ProvisionerStateKeyID,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 	)
@@ -9892,7 +11529,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 
 const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, provisioner_state_key_id, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -9923,15 +11560,52 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Co
 		&i.Reason,
 		&i.DailyCost,
 		&i.MaxDeadline,
+		&i.ProvisionerStateKeyID,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 	)
 	return i, err
 }
 
+const getWorkspaceBuildIDsWithProvisionerState = `-- name: GetWorkspaceBuildIDsWithProvisionerState :many
+SELECT
+	id
+FROM
+	workspace_builds
+WHERE
+	provisioner_state IS NOT NULL AND length(provisioner_state) > 0
+ORDER BY
+	created_at ASC
+`
+
+// Used by dbcrypt to re-encrypt or decrypt the provisioner state of all
+// builds.
+func (q *sqlQuerier) GetWorkspaceBuildIDsWithProvisionerState(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildIDsWithProvisionerState)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, provisioner_state_key_id, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -10001,6 +11675,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.ProvisionerStateKeyID,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -10018,7 +11693,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 }
 
 const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
-SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username FROM workspace_build_with_user WHERE created_at > $1
+SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, provisioner_state_key_id, initiator_by_avatar_url, initiator_by_username FROM workspace_build_with_user WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
@@ -10045,6 +11720,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, created
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.ProvisionerStateKeyID,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -10076,26 +11752,28 @@ INSERT INTO
 		provisioner_state,
 		deadline,
 		max_deadline,
-		reason
+		reason,
+		provisioner_state_key_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 `
 
 type InsertWorkspaceBuildParams struct {
-	ID                uuid.UUID           `db:"id" json:"id"`
-	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
-	WorkspaceID       uuid.UUID           `db:"workspace_id" json:"workspace_id"`
-	TemplateVersionID uuid.UUID           `db:"template_version_id" json:"template_version_id"`
-	BuildNumber       int32               `db:"build_number" json:"build_number"`
-	Transition        WorkspaceTransition `db:"transition" json:"transition"`
-	InitiatorID       uuid.UUID           `db:"initiator_id" json:"initiator_id"`
-	JobID             uuid.UUID           `db:"job_id" json:"job_id"`
-	ProvisionerState  []byte              `db:"provisioner_state" json:"provisioner_state"`
-	Deadline          time.Time           `db:"deadline" json:"deadline"`
-	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
-	Reason            BuildReason         `db:"reason" json:"reason"`
+	ID                    uuid.UUID           `db:"id" json:"id"`
+	CreatedAt             time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time           `db:"updated_at" json:"updated_at"`
+	WorkspaceID           uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID     uuid.UUID           `db:"template_version_id" json:"template_version_id"`
+	BuildNumber           int32               `db:"build_number" json:"build_number"`
+	Transition            WorkspaceTransition `db:"transition" json:"transition"`
+	InitiatorID           uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	JobID                 uuid.UUID           `db:"job_id" json:"job_id"`
+	ProvisionerState      []byte              `db:"provisioner_state" json:"provisioner_state"`
+	Deadline              time.Time           `db:"deadline" json:"deadline"`
+	MaxDeadline           time.Time           `db:"max_deadline" json:"max_deadline"`
+	Reason                BuildReason         `db:"reason" json:"reason"`
+	ProvisionerStateKeyID sql.NullString      `db:"provisioner_state_key_id" json:"provisioner_state_key_id"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error {
@@ -10113,6 +11791,7 @@ func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspa
 		arg.Deadline,
 		arg.MaxDeadline,
 		arg.Reason,
+		arg.ProvisionerStateKeyID,
 	)
 	return err
 }
@@ -10168,21 +11847,160 @@ UPDATE
 	workspace_builds
 SET
 	provisioner_state = $1::bytea,
-	updated_at = $2::timestamptz
-WHERE id = $3::uuid
+	provisioner_state_key_id = $2,
+	updated_at = $3::timestamptz
+WHERE id = $4::uuid
 `
 
 type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
-	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
-	ID               uuid.UUID `db:"id" json:"id"`
+	ProvisionerState      []byte         `db:"provisioner_state" json:"provisioner_state"`
+	ProvisionerStateKeyID sql.NullString `db:"provisioner_state_key_id" json:"provisioner_state_key_id"`
+	UpdatedAt             time.Time      `db:"updated_at" json:"updated_at"`
+	ID                    uuid.UUID      `db:"id" json:"id"`
 }
 
 func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID,
+		arg.ProvisionerState,
+		arg.ProvisionerStateKeyID,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const deleteOldWorkspacePortShares = `-- name: DeleteOldWorkspacePortShares :exec
+DELETE FROM
+	workspace_port_shares
+WHERE
+	expires_at < NOW()
+`
+
+// Expired port share links can no longer be used, so they are removed.
+func (q *sqlQuerier) DeleteOldWorkspacePortShares(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspacePortShares)
+	return err
+}
+
+const deleteWorkspacePortShareByID = `-- name: DeleteWorkspacePortShareByID :exec
+DELETE FROM
+	workspace_port_shares
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspacePortShareByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePortShareByID, id)
 	return err
 }
 
+const getWorkspacePortShareByID = `-- name: GetWorkspacePortShareByID :one
+SELECT
+	id, workspace_id, agent_name, port, created_by, created_at, expires_at
+FROM
+	workspace_port_shares
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspacePortShareByID(ctx context.Context, id uuid.UUID) (WorkspacePortShare, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspacePortShareByID, id)
+	var i WorkspacePortShare
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getWorkspacePortSharesByWorkspaceID = `-- name: GetWorkspacePortSharesByWorkspaceID :many
+SELECT
+	id, workspace_id, agent_name, port, created_by, created_at, expires_at
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND expires_at > NOW()
+ORDER BY
+	created_at ASC
+`
+
+func (q *sqlQuerier) GetWorkspacePortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShare, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePortSharesByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspacePortShare
+	for rows.Next() {
+		var i WorkspacePortShare
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.Port,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspacePortShare = `-- name: InsertWorkspacePortShare :one
+INSERT INTO
+	workspace_port_shares (id, workspace_id, agent_name, port, created_by, created_at, expires_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, workspace_id, agent_name, port, created_by, created_at, expires_at
+`
+
+type InsertWorkspacePortShareParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+	CreatedBy   uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspacePortShare(ctx context.Context, arg InsertWorkspacePortShareParams) (WorkspacePortShare, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspacePortShare,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.Port,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i WorkspacePortShare
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
 SELECT
 	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
@@ -10593,7 +12411,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.user_acl, workspaces.group_acl,
 	templates.name as template_name
 FROM
 	workspaces
@@ -10647,6 +12465,8 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.Workspace.DormantAt,
 		&i.Workspace.DeletingAt,
 		&i.Workspace.AutomaticUpdates,
+		&i.Workspace.UserACL,
+		&i.Workspace.GroupACL,
 		&i.TemplateName,
 	)
 	return i, err
@@ -10654,7 +12474,7 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, user_acl, group_acl
 FROM
 	workspaces
 WHERE
@@ -10681,13 +12501,15 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, user_acl, group_acl
 FROM
 	workspaces
 WHERE
@@ -10721,13 +12543,15 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, user_acl, group_acl
 FROM
 	workspaces
 WHERE
@@ -10780,6 +12604,8 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
@@ -10824,7 +12650,7 @@ func (q *sqlQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Conte
 
 const getWorkspaces = `-- name: GetWorkspaces :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.user_acl, workspaces.group_acl,
 	COALESCE(template_name.template_name, 'unknown') as template_name,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
@@ -11055,6 +12881,8 @@ type GetWorkspacesRow struct {
 	DormantAt           sql.NullTime     `db:"dormant_at" json:"dormant_at"`
 	DeletingAt          sql.NullTime     `db:"deleting_at" json:"deleting_at"`
 	AutomaticUpdates    AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	UserACL             WorkspaceACL     `db:"user_acl" json:"user_acl"`
+	GroupACL            WorkspaceACL     `db:"group_acl" json:"group_acl"`
 	TemplateName        string           `db:"template_name" json:"template_name"`
 	TemplateVersionID   uuid.UUID        `db:"template_version_id" json:"template_version_id"`
 	TemplateVersionName sql.NullString   `db:"template_version_name" json:"template_version_name"`
@@ -11100,6 +12928,8 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.DormantAt,
 			&i.DeletingAt,
 			&i.AutomaticUpdates,
+			&i.UserACL,
+			&i.GroupACL,
 			&i.TemplateName,
 			&i.TemplateVersionID,
 			&i.TemplateVersionName,
@@ -11118,9 +12948,95 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 	return items, nil
 }
 
+const getWorkspacesApproachingDeadline = `-- name: GetWorkspacesApproachingDeadline :many
+SELECT
+	workspaces.id,
+	workspaces.owner_id,
+	workspaces.name,
+	workspace_builds.deadline,
+	workspaces.deleting_at
+FROM
+	workspaces
+INNER JOIN
+	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+INNER JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+WHERE
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+	) AND
+
+	(
+		(
+			workspace_builds.transition = 'start'::workspace_transition AND
+			provisioner_jobs.job_status = 'succeeded'::provisioner_job_status AND
+			workspaces.dormant_at IS NULL AND
+			workspace_builds.deadline > $1 :: timestamptz AND
+			workspace_builds.deadline <= $2 :: timestamptz
+		) OR
+
+		(
+			workspaces.deleting_at IS NOT NULL AND
+			workspaces.deleting_at > $1 :: timestamptz AND
+			workspaces.deleting_at <= $3 :: timestamptz
+		)
+	) AND workspaces.deleted = 'false'
+`
+
+type GetWorkspacesApproachingDeadlineParams struct {
+	Now              time.Time `db:"now" json:"now"`
+	AutostopBefore   time.Time `db:"autostop_before" json:"autostop_before"`
+	AutodeleteBefore time.Time `db:"autodelete_before" json:"autodelete_before"`
+}
+
+type GetWorkspacesApproachingDeadlineRow struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	OwnerID    uuid.UUID    `db:"owner_id" json:"owner_id"`
+	Name       string       `db:"name" json:"name"`
+	Deadline   time.Time    `db:"deadline" json:"deadline"`
+	DeletingAt sql.NullTime `db:"deleting_at" json:"deleting_at"`
+}
+
+// Returns workspaces that will be autostopped before @autostop_before, and
+// dormant workspaces that will be deleted before @autodelete_before. Used
+// to warn owners ahead of time.
+func (q *sqlQuerier) GetWorkspacesApproachingDeadline(ctx context.Context, arg GetWorkspacesApproachingDeadlineParams) ([]GetWorkspacesApproachingDeadlineRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacesApproachingDeadline, arg.Now, arg.AutostopBefore, arg.AutodeleteBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspacesApproachingDeadlineRow
+	for rows.Next() {
+		var i GetWorkspacesApproachingDeadlineRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Name,
+			&i.Deadline,
+			&i.DeletingAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspacesEligibleForTransition = `-- name: GetWorkspacesEligibleForTransition :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.user_acl, workspaces.group_acl
 FROM
 	workspaces
 LEFT JOIN
@@ -11208,6 +13124,8 @@ func (q *sqlQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now
 			&i.DormantAt,
 			&i.DeletingAt,
 			&i.AutomaticUpdates,
+			&i.UserACL,
+			&i.GroupACL,
 		); err != nil {
 			return nil, err
 		}
@@ -11238,7 +13156,7 @@ INSERT INTO
 		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, user_acl, group_acl
 `
 
 type InsertWorkspaceParams struct {
@@ -11285,6 +13203,8 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
@@ -11315,7 +13235,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, user_acl, group_acl
 `
 
 type UpdateWorkspaceParams struct {
@@ -11341,10 +13261,33 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
 
+const updateWorkspaceACLByID = `-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	user_acl = $1,
+	group_acl = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceACLByIDParams struct {
+	UserACL  WorkspaceACL `db:"user_acl" json:"user_acl"`
+	GroupACL WorkspaceACL `db:"group_acl" json:"group_acl"`
+	ID       uuid.UUID    `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceACLByID, arg.UserACL, arg.GroupACL, arg.ID)
+	return err
+}
+
 const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
 UPDATE
 	workspaces
@@ -11427,7 +13370,7 @@ WHERE
     workspaces.id = $1
     AND templates.id = workspaces.template_id
 RETURNING
-    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates
+    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.user_acl, workspaces.group_acl
 `
 
 type UpdateWorkspaceDormantDeletingAtParams struct {
@@ -11453,6 +13396,8 @@ func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg U
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.UserACL,
+		&i.GroupACL,
 	)
 	return i, err
 }
@@ -11521,6 +13466,43 @@ func (q *sqlQuerier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.C
 	return err
 }
 
+const getWorkspaceAgentScriptTimingsByAgentIDs = `-- name: GetWorkspaceAgentScriptTimingsByAgentIDs :many
+SELECT agent_id, log_source_id, stage, status, exit_code, started_at, ended_at FROM workspace_agent_script_timings
+WHERE agent_id = ANY($1 :: uuid [ ])
+ORDER BY started_at ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentScriptTimingsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScriptTiming, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentScriptTimingsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentScriptTiming
+	for rows.Next() {
+		var i WorkspaceAgentScriptTiming
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.LogSourceID,
+			&i.Stage,
+			&i.Status,
+			&i.ExitCode,
+			&i.StartedAt,
+			&i.EndedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentScriptsByAgentIDs = `-- name: GetWorkspaceAgentScriptsByAgentIDs :many
 SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
 `
@@ -11559,6 +13541,47 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids
 	return items, nil
 }
 
+const insertWorkspaceAgentScriptTiming = `-- name: InsertWorkspaceAgentScriptTiming :one
+INSERT INTO
+	workspace_agent_script_timings (agent_id, log_source_id, stage, status, exit_code, started_at, ended_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING agent_id, log_source_id, stage, status, exit_code, started_at, ended_at
+`
+
+type InsertWorkspaceAgentScriptTimingParams struct {
+	AgentID     uuid.UUID                        `db:"agent_id" json:"agent_id"`
+	LogSourceID uuid.UUID                        `db:"log_source_id" json:"log_source_id"`
+	Stage       WorkspaceAgentScriptTimingStage  `db:"stage" json:"stage"`
+	Status      WorkspaceAgentScriptTimingStatus `db:"status" json:"status"`
+	ExitCode    int32                            `db:"exit_code" json:"exit_code"`
+	StartedAt   time.Time                        `db:"started_at" json:"started_at"`
+	EndedAt     time.Time                        `db:"ended_at" json:"ended_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentScriptTiming(ctx context.Context, arg InsertWorkspaceAgentScriptTimingParams) (WorkspaceAgentScriptTiming, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentScriptTiming,
+		arg.AgentID,
+		arg.LogSourceID,
+		arg.Stage,
+		arg.Status,
+		arg.ExitCode,
+		arg.StartedAt,
+		arg.EndedAt,
+	)
+	var i WorkspaceAgentScriptTiming
+	err := row.Scan(
+		&i.AgentID,
+		&i.LogSourceID,
+		&i.Stage,
+		&i.Status,
+		&i.ExitCode,
+		&i.StartedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
 const insertWorkspaceAgentScripts = `-- name: InsertWorkspaceAgentScripts :many
 INSERT INTO
 	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds)
@@ -11633,3 +13656,221 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 	}
 	return items, nil
 }
+
+const countOldWorkspaceSessionRecordings = `-- name: CountOldWorkspaceSessionRecordings :one
+SELECT
+	COUNT(*)
+FROM
+	workspace_session_recordings
+WHERE
+	ended_at < $1
+`
+
+func (q *sqlQuerier) CountOldWorkspaceSessionRecordings(ctx context.Context, beforeTime time.Time) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOldWorkspaceSessionRecordings, beforeTime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteOldWorkspaceSessionRecordings = `-- name: DeleteOldWorkspaceSessionRecordings :execrows
+DELETE FROM workspace_session_recordings WHERE id IN (
+	SELECT
+		id
+	FROM
+		workspace_session_recordings
+	WHERE
+		ended_at < $1
+	ORDER BY
+		ended_at ASC
+	LIMIT
+		$2 :: int
+)
+`
+
+type DeleteOldWorkspaceSessionRecordingsParams struct {
+	BeforeTime time.Time `db:"before_time" json:"before_time"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Delete at most limit_count recordings that ended before before_time.
+func (q *sqlQuerier) DeleteOldWorkspaceSessionRecordings(ctx context.Context, arg DeleteOldWorkspaceSessionRecordingsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldWorkspaceSessionRecordings, arg.BeforeTime, arg.LimitCount)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getWorkspaceSessionRecordingByID = `-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	id, workspace_id, workspace_build_id, agent_id, type, started_at, ended_at, size, data, data_key_id
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (WorkspaceSessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSessionRecordingByID, id)
+	var i WorkspaceSessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.WorkspaceBuildID,
+		&i.AgentID,
+		&i.Type,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.Size,
+		&i.Data,
+		&i.DataKeyID,
+	)
+	return i, err
+}
+
+const getWorkspaceSessionRecordingIDs = `-- name: GetWorkspaceSessionRecordingIDs :many
+SELECT
+	id
+FROM
+	workspace_session_recordings
+ORDER BY
+	started_at ASC
+`
+
+// Used by dbcrypt to re-encrypt or decrypt the data of all recordings.
+func (q *sqlQuerier) GetWorkspaceSessionRecordingIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceSessionRecordingsByWorkspaceID = `-- name: GetWorkspaceSessionRecordingsByWorkspaceID :many
+SELECT
+	id, workspace_id, workspace_build_id, agent_id, type, started_at, ended_at, size
+FROM
+	workspace_session_recordings
+WHERE
+	workspace_id = $1
+ORDER BY
+	started_at DESC
+`
+
+type GetWorkspaceSessionRecordingsByWorkspaceIDRow struct {
+	ID               uuid.UUID                     `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID                     `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID                     `db:"workspace_build_id" json:"workspace_build_id"`
+	AgentID          uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Type             WorkspaceSessionRecordingType `db:"type" json:"type"`
+	StartedAt        time.Time                     `db:"started_at" json:"started_at"`
+	EndedAt          time.Time                     `db:"ended_at" json:"ended_at"`
+	Size             int64                         `db:"size" json:"size"`
+}
+
+// Recordings can be large, so the data is only returned when fetching a
+// single recording.
+func (q *sqlQuerier) GetWorkspaceSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]GetWorkspaceSessionRecordingsByWorkspaceIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSessionRecordingsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceSessionRecordingsByWorkspaceIDRow
+	for rows.Next() {
+		var i GetWorkspaceSessionRecordingsByWorkspaceIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.WorkspaceBuildID,
+			&i.AgentID,
+			&i.Type,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.Size,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceSessionRecording = `-- name: InsertWorkspaceSessionRecording :exec
+INSERT INTO
+	workspace_session_recordings (id, workspace_id, workspace_build_id, agent_id, type, started_at, ended_at, size, data, data_key_id)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+`
+
+type InsertWorkspaceSessionRecordingParams struct {
+	ID               uuid.UUID                     `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID                     `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID                     `db:"workspace_build_id" json:"workspace_build_id"`
+	AgentID          uuid.UUID                     `db:"agent_id" json:"agent_id"`
+	Type             WorkspaceSessionRecordingType `db:"type" json:"type"`
+	StartedAt        time.Time                     `db:"started_at" json:"started_at"`
+	EndedAt          time.Time                     `db:"ended_at" json:"ended_at"`
+	Size             int64                         `db:"size" json:"size"`
+	Data             []byte                        `db:"data" json:"data"`
+	DataKeyID        sql.NullString                `db:"data_key_id" json:"data_key_id"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSessionRecording(ctx context.Context, arg InsertWorkspaceSessionRecordingParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceSessionRecording,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.WorkspaceBuildID,
+		arg.AgentID,
+		arg.Type,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.Size,
+		arg.Data,
+		arg.DataKeyID,
+	)
+	return err
+}
+
+const updateWorkspaceSessionRecordingDataByID = `-- name: UpdateWorkspaceSessionRecordingDataByID :exec
+UPDATE
+	workspace_session_recordings
+SET
+	data = $1,
+	data_key_id = $2
+WHERE
+	id = $3
+`
+
+type UpdateWorkspaceSessionRecordingDataByIDParams struct {
+	Data      []byte         `db:"data" json:"data"`
+	DataKeyID sql.NullString `db:"data_key_id" json:"data_key_id"`
+	ID        uuid.UUID      `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceSessionRecordingDataByID(ctx context.Context, arg UpdateWorkspaceSessionRecordingDataByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceSessionRecordingDataByID, arg.Data, arg.DataKeyID, arg.ID)
+	return err
+}
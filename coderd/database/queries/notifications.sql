@@ -0,0 +1,78 @@
+-- name: EnqueueNotificationMessage :exec
+-- Messages sharing a dedupe key are only ever enqueued once.
+INSERT INTO notification_messages
+	(id, event, user_id, method, payload, dedupe_key, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (dedupe_key) DO NOTHING;
+
+-- name: AcquireNotificationMessages :many
+-- Acquires up to @count messages that are ready to be dispatched and leases
+-- them until @leased_until. Leased messages whose lease has expired are
+-- acquired again, as the dispatcher holding them is presumed to be gone.
+UPDATE
+	notification_messages
+SET
+	status = 'leased'::notification_message_status,
+	updated_at = @now::timestamptz,
+	leased_until = @leased_until::timestamptz
+WHERE
+	id IN (
+		SELECT
+			nm.id
+		FROM
+			notification_messages AS nm
+		WHERE
+			(
+				nm.status = 'pending'::notification_message_status OR
+				(
+					nm.status = 'leased'::notification_message_status AND
+					nm.leased_until < @now::timestamptz
+				)
+			) AND
+			(nm.next_retry_after IS NULL OR nm.next_retry_after <= @now::timestamptz)
+		ORDER BY
+			nm.created_at ASC
+		FOR UPDATE SKIP LOCKED
+		LIMIT
+			@count::int
+	)
+RETURNING
+	*;
+
+-- name: MarkNotificationMessageSent :exec
+UPDATE
+	notification_messages
+SET
+	status = 'sent'::notification_message_status,
+	status_reason = NULL,
+	attempt_count = attempt_count + 1,
+	updated_at = @updated_at::timestamptz,
+	leased_until = NULL,
+	next_retry_after = NULL
+WHERE
+	id = @id::uuid;
+
+-- name: MarkNotificationMessageFailed :exec
+-- Records a failed delivery attempt. A status of 'pending' schedules the
+-- message to be retried after @next_retry_after, while 'failed' gives up on
+-- it entirely.
+UPDATE
+	notification_messages
+SET
+	status = @status::notification_message_status,
+	status_reason = @status_reason::text,
+	attempt_count = attempt_count + 1,
+	updated_at = @updated_at::timestamptz,
+	leased_until = NULL,
+	next_retry_after = @next_retry_after
+WHERE
+	id = @id::uuid;
+
+-- name: DeleteOldNotificationMessages :exec
+-- Delete messages which were delivered, or which permanently failed, more
+-- than a week ago.
+DELETE FROM notification_messages
+WHERE
+	status IN ('sent'::notification_message_status, 'failed'::notification_message_status) AND
+	updated_at < NOW() - INTERVAL '7 days';
@@ -9,6 +9,20 @@ WHERE
 LIMIT
 	1;
 
+-- name: GetOrganizationMembers :many
+SELECT
+	sqlc.embed(organization_members),
+	users.username
+FROM
+	organization_members
+INNER JOIN
+	users ON organization_members.user_id = users.id
+WHERE
+	organization_members.organization_id = @organization_id
+	AND users.deleted = false
+ORDER BY
+	users.username ASC;
+
 -- name: InsertOrganizationMember :one
 INSERT INTO
 	organization_members (
@@ -50,3 +64,10 @@ WHERE
 	user_id = @user_id
 	AND organization_id = @org_id
 RETURNING *;
+
+-- name: DeleteOrganizationMember :exec
+DELETE FROM
+	organization_members
+WHERE
+	organization_id = @organization_id
+	AND user_id = @user_id;
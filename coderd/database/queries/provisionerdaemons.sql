@@ -14,6 +14,16 @@ DELETE FROM provisioner_daemons WHERE (
 	(last_seen_at IS NOT NULL AND last_seen_at < (NOW() - INTERVAL '7 days'))
 );
 
+-- name: UpdateProvisionerDaemonLastSeenAt :exec
+-- Connected daemons heartbeat through this query. The comparison keeps a
+-- delayed heartbeat from moving last_seen_at backwards.
+UPDATE provisioner_daemons
+SET
+	last_seen_at = @last_seen_at
+WHERE
+	id = @id
+	AND (last_seen_at IS NULL OR last_seen_at <= @last_seen_at);
+
 -- name: UpsertProvisionerDaemon :one
 INSERT INTO
 	provisioner_daemons (
@@ -24,7 +34,8 @@ INSERT INTO
 		tags,
 		last_seen_at,
 		"version",
-		api_version
+		api_version,
+		key_id
 	)
 VALUES (
 	gen_random_uuid(),
@@ -34,13 +45,15 @@ VALUES (
 	@tags,
 	@last_seen_at,
 	@version,
-	@api_version
+	@api_version,
+	@key_id
 ) ON CONFLICT("name", lower((tags ->> 'owner'::text))) DO UPDATE SET
 	provisioners = @provisioners,
 	tags = @tags,
 	last_seen_at = @last_seen_at,
 	"version" = @version,
-	api_version = @api_version
+	api_version = @api_version,
+	key_id = @key_id
 WHERE
 	-- Only ones with the same tags are allowed clobber
 	provisioner_daemons.tags <@ @tags :: jsonb
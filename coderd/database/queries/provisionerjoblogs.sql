@@ -1,3 +1,29 @@
+-- name: CountOldProvisionerJobLogs :one
+SELECT
+	COUNT(*)
+FROM
+	provisioner_job_logs
+WHERE
+	created_at < @before_time
+	AND job_id IN (SELECT id FROM provisioner_jobs WHERE completed_at IS NOT NULL);
+
+-- name: DeleteOldProvisionerJobLogs :execrows
+-- Delete at most limit_count logs of completed provisioner jobs that are older
+-- than before_time.
+DELETE FROM provisioner_job_logs WHERE id IN (
+	SELECT
+		id
+	FROM
+		provisioner_job_logs
+	WHERE
+		created_at < @before_time
+		AND job_id IN (SELECT id FROM provisioner_jobs WHERE completed_at IS NOT NULL)
+	ORDER BY
+		id ASC
+	LIMIT
+		@limit_count :: int
+);
+
 -- name: GetProvisionerLogsAfterID :many
 SELECT
 	*
@@ -1,9 +1,19 @@
 -- Acquires the lock for a single job that isn't started, completed,
 -- canceled, and that matches an array of provisioner types.
 --
+-- Jobs are acquired in order of priority. Among jobs of the same priority,
+-- jobs of initiators with the fewest running jobs go first so that one user's
+-- batch of builds doesn't starve everyone else, and then the oldest job wins.
+-- Jobs whose initiator or template already has the maximum number of running
+-- jobs are skipped. A maximum below 1 means there is no limit.
+--
 -- SKIP LOCKED is used to jump over locked rows. This prevents
 -- multiple provisioners from acquiring the same jobs. See:
 -- https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
+--
+-- SKIP LOCKED does not make the running job counts consistent between
+-- concurrent callers, so callers that set a maximum must hold the
+-- LockIDAcquireProvisionerJob advisory lock in the same transaction.
 -- name: AcquireProvisionerJob :one
 UPDATE
 	provisioner_jobs
@@ -23,7 +33,45 @@ WHERE
 			AND nested.provisioner = ANY(@types :: provisioner_type [ ])
 			-- Ensure the caller satisfies all job tags.
 			AND nested.tags <@ @tags :: jsonb
+			AND (
+				@max_concurrent_per_user :: integer < 1
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						provisioner_jobs AS running
+					WHERE
+						running.initiator_id = nested.initiator_id
+						AND running.started_at IS NOT NULL
+						AND running.completed_at IS NULL
+				) < @max_concurrent_per_user :: integer
+			)
+			AND (
+				@max_concurrent_per_template :: integer < 1
+				OR nested.template_id IS NULL
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						provisioner_jobs AS running
+					WHERE
+						running.template_id = nested.template_id
+						AND running.started_at IS NOT NULL
+						AND running.completed_at IS NULL
+				) < @max_concurrent_per_template :: integer
+			)
 		ORDER BY
+			nested.priority DESC,
+			(
+				SELECT
+					COUNT(*)
+				FROM
+					provisioner_jobs AS running
+				WHERE
+					running.initiator_id = nested.initiator_id
+					AND running.started_at IS NOT NULL
+					AND running.completed_at IS NULL
+			) ASC,
 			nested.created_at
 		FOR UPDATE
 		SKIP LOCKED
@@ -50,7 +98,7 @@ WHERE
 -- name: GetProvisionerJobsByIDsWithQueuePosition :many
 WITH unstarted_jobs AS (
     SELECT
-        id, created_at
+        id, created_at, priority
     FROM
         provisioner_jobs
     WHERE
@@ -59,7 +107,7 @@ WITH unstarted_jobs AS (
 queue_position AS (
     SELECT
         id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+        ROW_NUMBER() OVER (ORDER BY priority DESC, created_at ASC) AS queue_position
     FROM
         unstarted_jobs
 ),
@@ -96,10 +144,12 @@ INSERT INTO
 		"type",
 		"input",
 		tags,
-		trace_metadata
+		trace_metadata,
+		priority,
+		template_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING *;
 
 -- name: UpdateProvisionerJobByID :exec
 UPDATE
@@ -138,3 +188,37 @@ WHERE
 	updated_at < $1
 	AND started_at IS NOT NULL
 	AND completed_at IS NULL;
+
+-- name: InsertProvisionerJobTimings :many
+INSERT INTO
+	provisioner_job_timings (job_id, started_at, ended_at, stage, source, action, resource)
+SELECT
+	@job_id :: uuid AS job_id,
+	unnest(@started_at :: timestamptz [ ]) AS started_at,
+	unnest(@ended_at :: timestamptz [ ]) AS ended_at,
+	unnest(@stage :: provisioner_job_timing_stage [ ]) AS stage,
+	unnest(@source :: text [ ]) AS source,
+	unnest(@action :: text [ ]) AS action,
+	unnest(@resource :: text [ ]) AS resource
+RETURNING *;
+
+-- name: GetProvisionerJobTimingsByJobID :many
+SELECT * FROM provisioner_job_timings
+WHERE job_id = $1
+ORDER BY started_at ASC;
+
+-- name: InsertProvisionerJobResourceChanges :many
+INSERT INTO
+	provisioner_job_resource_changes (job_id, address, type, name, action)
+SELECT
+	@job_id :: uuid AS job_id,
+	unnest(@address :: text [ ]) AS address,
+	unnest(@type :: text [ ]) AS type,
+	unnest(@name :: text [ ]) AS name,
+	unnest(@action :: provisioner_job_resource_change_action [ ]) AS action
+RETURNING *;
+
+-- name: GetProvisionerJobResourceChangesByJobID :many
+SELECT * FROM provisioner_job_resource_changes
+WHERE job_id = $1
+ORDER BY address ASC;
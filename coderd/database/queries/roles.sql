@@ -0,0 +1,64 @@
+-- name: CustomRoles :many
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	true
+	-- Lookup roles filter expects the role names to be in the rbac package
+	-- format, which is name[:<organization_id>].
+	AND CASE WHEN array_length(@lookup_roles :: text[], 1) > 0 THEN
+		-- The organization id is only appended for organization roles.
+		concat(name, NULLIF(concat(':', organization_id), ':')) = ANY(@lookup_roles :: text[])
+	ELSE true
+	END
+	-- Only fetch site wide roles.
+	AND CASE WHEN @exclude_org_roles :: boolean THEN
+		organization_id IS NULL
+	ELSE true
+	END
+	AND CASE WHEN @organization_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+		organization_id = @organization_id
+	ELSE true
+	END
+ORDER BY
+	name ASC;
+
+-- name: DeleteCustomRole :exec
+DELETE FROM
+	custom_roles
+WHERE
+	name = lower(@name)
+	AND organization_id IS NOT DISTINCT FROM @organization_id;
+
+-- name: UpsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		name,
+		display_name,
+		organization_id,
+		site_permissions,
+		org_permissions,
+		user_permissions,
+		created_at,
+		updated_at
+	)
+VALUES (
+	-- Role names are always lowercase.
+	lower(@name),
+	@display_name,
+	@organization_id,
+	@site_permissions,
+	@org_permissions,
+	@user_permissions,
+	now(),
+	now()
+)
+ON CONFLICT (name, COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid))
+	DO UPDATE SET
+		display_name = @display_name,
+		site_permissions = @site_permissions,
+		org_permissions = @org_permissions,
+		user_permissions = @user_permissions,
+		updated_at = now()
+RETURNING *;
@@ -79,3 +79,12 @@ SELECT
 -- name: UpsertHealthSettings :exec
 INSERT INTO site_configs (key, value) VALUES ('health_settings', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'health_settings';
+
+-- name: GetOIDCSyncRules :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'oidc_sync_rules'), '{}') :: text AS oidc_sync_rules
+;
+
+-- name: UpsertOIDCSyncRules :exec
+INSERT INTO site_configs (key, value) VALUES ('oidc_sync_rules', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'oidc_sync_rules';
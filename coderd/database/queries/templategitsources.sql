@@ -0,0 +1,73 @@
+-- name: GetTemplateGitSourceByTemplateID :one
+SELECT
+	*
+FROM
+	template_git_sources
+WHERE
+	template_id = $1;
+
+-- name: GetTemplateGitSources :many
+SELECT
+	*
+FROM
+	template_git_sources
+ORDER BY
+	template_id;
+
+-- name: UpsertTemplateGitSource :one
+INSERT INTO
+	template_git_sources (
+		template_id,
+		created_at,
+		updated_at,
+		created_by,
+		repository_url,
+		branch,
+		subdirectory,
+		auto_promote,
+		poll_interval,
+		webhook_secret,
+		webhook_secret_key_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (template_id) DO UPDATE
+SET
+	updated_at = $3,
+	created_by = $4,
+	repository_url = $5,
+	branch = $6,
+	subdirectory = $7,
+	auto_promote = $8,
+	poll_interval = $9,
+	webhook_secret = $10,
+	webhook_secret_key_id = $11
+RETURNING *;
+
+-- name: UpdateTemplateGitSourceSyncStatus :exec
+UPDATE
+	template_git_sources
+SET
+	updated_at = @updated_at,
+	last_checked_at = @last_checked_at,
+	last_commit_sha = @last_commit_sha,
+	last_template_version_id = @last_template_version_id,
+	last_error = @last_error
+WHERE
+	template_id = @template_id;
+
+-- name: UpdateTemplateGitSourceWebhookSecret :exec
+-- Used by dbcrypt to re-encrypt or decrypt webhook secrets.
+UPDATE
+	template_git_sources
+SET
+	webhook_secret = @webhook_secret,
+	webhook_secret_key_id = @webhook_secret_key_id
+WHERE
+	template_id = @template_id;
+
+-- name: DeleteTemplateGitSource :exec
+DELETE FROM
+	template_git_sources
+WHERE
+	template_id = $1;
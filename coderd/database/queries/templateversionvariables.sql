@@ -8,7 +8,8 @@ INSERT INTO
         value,
         default_value,
         required,
-        sensitive
+        sensitive,
+        value_key_id
     )
 VALUES
     (
@@ -19,8 +20,25 @@ VALUES
         $5,
         $6,
         $7,
-        $8
+        $8,
+        $9
     ) RETURNING *;
 
 -- name: GetTemplateVersionVariables :many
 SELECT * FROM template_version_variables WHERE template_version_id = $1;
+
+-- name: GetSensitiveTemplateVersionVariables :many
+-- Used by dbcrypt to re-encrypt or decrypt the values of all sensitive
+-- variables.
+SELECT * FROM template_version_variables WHERE sensitive = true;
+
+-- name: UpdateTemplateVersionVariableValue :exec
+-- Used by dbcrypt to re-encrypt or decrypt the value of a sensitive variable.
+-- The values of other variables are never encrypted.
+UPDATE
+	template_version_variables
+SET
+	value = @value,
+	value_key_id = @value_key_id
+WHERE
+	template_version_id = @template_version_id AND name = @name AND sensitive = true;
@@ -28,10 +28,11 @@ INSERT INTO
 		oauth_refresh_token,
 		oauth_refresh_token_key_id,
 		oauth_expiry,
-	    debug_context
+	    debug_context,
+		login_provider
 	)
 VALUES
-	( $1, $2, $3, $4, $5, $6, $7, $8, $9 ) RETURNING *;
+	( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10 ) RETURNING *;
 
 -- name: UpdateUserLinkedID :one
 UPDATE
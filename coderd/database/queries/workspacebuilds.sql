@@ -1,3 +1,87 @@
+-- name: CountOldWorkspaceBuilds :one
+SELECT
+	COUNT(*)
+FROM (
+	SELECT
+		id,
+		job_id,
+		row_number() OVER (PARTITION BY workspace_id ORDER BY build_number DESC) AS build_rank
+	FROM
+		workspace_builds
+) AS ranked_builds
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = ranked_builds.job_id
+WHERE
+	ranked_builds.build_rank > @keep_count :: int
+	AND provisioner_jobs.completed_at IS NOT NULL
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			workspace_app_stats
+		JOIN
+			workspace_agents ON workspace_agents.id = workspace_app_stats.agent_id
+		JOIN
+			workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+		WHERE
+			workspace_resources.job_id = ranked_builds.job_id
+	)
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			workspace_session_recordings
+		WHERE
+			workspace_session_recordings.workspace_build_id = ranked_builds.id
+	);
+
+-- name: DeleteOldWorkspaceBuilds :execrows
+-- Delete at most limit_count completed workspace builds that are older than
+-- the keep_count most recent builds of their workspace. The provisioner job is
+-- deleted and the build, its resources, agents and logs are removed by
+-- cascade. Builds with recorded app usage are kept, since workspace_app_stats
+-- does not cascade. Builds with session recordings are kept until the
+-- recordings are deleted by their own retention.
+DELETE FROM provisioner_jobs WHERE id IN (
+	SELECT
+		ranked_builds.job_id
+	FROM (
+		SELECT
+			id,
+			job_id,
+			row_number() OVER (PARTITION BY workspace_id ORDER BY build_number DESC) AS build_rank
+		FROM
+			workspace_builds
+	) AS ranked_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = ranked_builds.job_id
+	WHERE
+		ranked_builds.build_rank > @keep_count :: int
+		AND provisioner_jobs.completed_at IS NOT NULL
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_app_stats
+			JOIN
+				workspace_agents ON workspace_agents.id = workspace_app_stats.agent_id
+			JOIN
+				workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+			WHERE
+				workspace_resources.job_id = ranked_builds.job_id
+		)
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_session_recordings
+			WHERE
+				workspace_session_recordings.workspace_build_id = ranked_builds.id
+		)
+	LIMIT
+		@limit_count :: int
+);
+
 -- name: GetWorkspaceBuildByID :one
 SELECT
 	*
@@ -120,10 +204,11 @@ INSERT INTO
 		provisioner_state,
 		deadline,
 		max_deadline,
-		reason
+		reason,
+		provisioner_state_key_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
 
 -- name: UpdateWorkspaceBuildCostByID :exec
 UPDATE
@@ -147,9 +232,22 @@ UPDATE
 	workspace_builds
 SET
 	provisioner_state = @provisioner_state::bytea,
+	provisioner_state_key_id = @provisioner_state_key_id,
 	updated_at = @updated_at::timestamptz
 WHERE id = @id::uuid;
 
+-- name: GetWorkspaceBuildIDsWithProvisionerState :many
+-- Used by dbcrypt to re-encrypt or decrypt the provisioner state of all
+-- builds.
+SELECT
+	id
+FROM
+	workspace_builds
+WHERE
+	provisioner_state IS NOT NULL AND length(provisioner_state) > 0
+ORDER BY
+	created_at ASC;
+
 -- name: GetActiveWorkspaceBuildsByTemplateID :many
 SELECT wb.*
 FROM (
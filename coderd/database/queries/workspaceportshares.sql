@@ -0,0 +1,37 @@
+-- name: InsertWorkspacePortShare :one
+INSERT INTO
+	workspace_port_shares (id, workspace_id, agent_name, port, created_by, created_at, expires_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetWorkspacePortShareByID :one
+SELECT
+	*
+FROM
+	workspace_port_shares
+WHERE
+	id = $1;
+
+-- name: GetWorkspacePortSharesByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_port_shares
+WHERE
+	workspace_id = $1
+	AND expires_at > NOW()
+ORDER BY
+	created_at ASC;
+
+-- name: DeleteWorkspacePortShareByID :exec
+DELETE FROM
+	workspace_port_shares
+WHERE
+	id = $1;
+
+-- name: DeleteOldWorkspacePortShares :exec
+-- Expired port share links can no longer be used, so they are removed.
+DELETE FROM
+	workspace_port_shares
+WHERE
+	expires_at < NOW();
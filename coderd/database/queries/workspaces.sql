@@ -333,6 +333,15 @@ WHERE
 	AND deleted = false
 RETURNING *;
 
+-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	user_acl = @user_acl,
+	group_acl = @group_acl
+WHERE
+	id = @id;
+
 -- name: UpdateWorkspaceAutostart :exec
 UPDATE
 	workspaces
@@ -484,6 +493,48 @@ WHERE
 		)
 	) AND workspaces.deleted = 'false';
 
+-- name: GetWorkspacesApproachingDeadline :many
+-- Returns workspaces that will be autostopped before @autostop_before, and
+-- dormant workspaces that will be deleted before @autodelete_before. Used
+-- to warn owners ahead of time.
+SELECT
+	workspaces.id,
+	workspaces.owner_id,
+	workspaces.name,
+	workspace_builds.deadline,
+	workspaces.deleting_at
+FROM
+	workspaces
+INNER JOIN
+	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+INNER JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+WHERE
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds
+		WHERE
+			workspace_builds.workspace_id = workspaces.id
+	) AND
+
+	(
+		(
+			workspace_builds.transition = 'start'::workspace_transition AND
+			provisioner_jobs.job_status = 'succeeded'::provisioner_job_status AND
+			workspaces.dormant_at IS NULL AND
+			workspace_builds.deadline > @now :: timestamptz AND
+			workspace_builds.deadline <= @autostop_before :: timestamptz
+		) OR
+
+		(
+			workspaces.deleting_at IS NOT NULL AND
+			workspaces.deleting_at > @now :: timestamptz AND
+			workspaces.deleting_at <= @autodelete_before :: timestamptz
+		)
+	) AND workspaces.deleted = 'false';
+
 -- name: UpdateWorkspaceDormantDeletingAt :one
 UPDATE
     workspaces
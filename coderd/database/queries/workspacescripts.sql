@@ -16,3 +16,15 @@ RETURNING workspace_agent_scripts.*;
 
 -- name: GetWorkspaceAgentScriptsByAgentIDs :many
 SELECT * FROM workspace_agent_scripts WHERE workspace_agent_id = ANY(@ids :: uuid [ ]);
+
+-- name: InsertWorkspaceAgentScriptTiming :one
+INSERT INTO
+	workspace_agent_script_timings (agent_id, log_source_id, stage, status, exit_code, started_at, ended_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetWorkspaceAgentScriptTimingsByAgentIDs :many
+SELECT * FROM workspace_agent_script_timings
+WHERE agent_id = ANY(@ids :: uuid [ ])
+ORDER BY started_at ASC;
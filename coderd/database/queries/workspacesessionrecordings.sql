@@ -0,0 +1,66 @@
+-- name: InsertWorkspaceSessionRecording :exec
+INSERT INTO
+	workspace_session_recordings (id, workspace_id, workspace_build_id, agent_id, type, started_at, ended_at, size, data, data_key_id)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetWorkspaceSessionRecordingByID :one
+SELECT
+	*
+FROM
+	workspace_session_recordings
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceSessionRecordingsByWorkspaceID :many
+-- Recordings can be large, so the data is only returned when fetching a
+-- single recording.
+SELECT
+	id, workspace_id, workspace_build_id, agent_id, type, started_at, ended_at, size
+FROM
+	workspace_session_recordings
+WHERE
+	workspace_id = $1
+ORDER BY
+	started_at DESC;
+
+-- name: GetWorkspaceSessionRecordingIDs :many
+-- Used by dbcrypt to re-encrypt or decrypt the data of all recordings.
+SELECT
+	id
+FROM
+	workspace_session_recordings
+ORDER BY
+	started_at ASC;
+
+-- name: UpdateWorkspaceSessionRecordingDataByID :exec
+UPDATE
+	workspace_session_recordings
+SET
+	data = @data,
+	data_key_id = @data_key_id
+WHERE
+	id = @id;
+
+-- name: CountOldWorkspaceSessionRecordings :one
+SELECT
+	COUNT(*)
+FROM
+	workspace_session_recordings
+WHERE
+	ended_at < @before_time;
+
+-- name: DeleteOldWorkspaceSessionRecordings :execrows
+-- Delete at most limit_count recordings that ended before before_time.
+DELETE FROM workspace_session_recordings WHERE id IN (
+	SELECT
+		id
+	FROM
+		workspace_session_recordings
+	WHERE
+		ended_at < @before_time
+	ORDER BY
+		ended_at ASC
+	LIMIT
+		@limit_count :: int
+);
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -29,6 +30,14 @@ type HealthSettings struct {
 	DismissedHealthchecks []codersdk.HealthSection `db:"dismissed_healthchecks" json:"dismissed_healthchecks"`
 }
 
+// OIDCSyncRules is stored as JSON in site_configs. This type is provided for
+// audit logging purposes.
+type OIDCSyncRules struct {
+	ID        uuid.UUID               `db:"id" json:"id"`
+	SyncRoles bool                    `db:"sync_roles" json:"sync_roles"`
+	Rules     []codersdk.OIDCSyncRule `db:"rules" json:"rules"`
+}
+
 type Actions []rbac.Action
 
 func (a *Actions) Scan(src interface{}) error {
@@ -64,6 +73,72 @@ func (t TemplateACL) Value() (driver.Value, error) {
 	return json.Marshal(t)
 }
 
+// WorkspaceACL is a map of user or group ids to the actions they have been
+// granted on a workspace. Besides the regular RBAC actions, which apply to the
+// workspace itself, WorkspaceACLActionBuild grants starting and stopping the
+// workspace, and WorkspaceACLActionSSH and WorkspaceACLActionApp grant access
+// to the workspace agent and to workspace apps.
+type WorkspaceACL map[string][]rbac.Action
+
+const (
+	WorkspaceACLActionBuild rbac.Action = "build"
+	WorkspaceACLActionSSH   rbac.Action = "ssh"
+	WorkspaceACLActionApp   rbac.Action = "app"
+)
+
+func (w *WorkspaceACL) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &w)
+	case []byte:
+		return json.Unmarshal(v, &w)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (w WorkspaceACL) Value() (driver.Value, error) {
+	if w == nil {
+		// Never store null, the column is not nullable.
+		w = WorkspaceACL{}
+	}
+	return json.Marshal(w)
+}
+
+// grantACL returns the ACL for an RBAC object derived from the workspace,
+// granting the RBAC action granted to everyone holding the workspace ACL
+// action.
+func (w WorkspaceACL) grantACL(action rbac.Action, granted rbac.Action) map[string][]rbac.Action {
+	acl := make(map[string][]rbac.Action)
+	for id, actions := range w {
+		if slices.Contains(actions, action) {
+			acl[id] = []rbac.Action{granted}
+		}
+	}
+	return acl
+}
+
+// CustomRolePermissions are the permissions of a custom role or custom API key
+// scope, stored as a JSON array.
+type CustomRolePermissions []rbac.Permission
+
+func (a *CustomRolePermissions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), &a)
+	case []byte:
+		return json.Unmarshal(v, &a)
+	}
+	return xerrors.Errorf("unexpected type %T", src)
+}
+
+func (a CustomRolePermissions) Value() (driver.Value, error) {
+	if a == nil {
+		// Never store null, the column is not nullable.
+		a = CustomRolePermissions{}
+	}
+	return json.Marshal(a)
+}
+
 type StringMap map[string]string
 
 func (m *StringMap) Scan(src interface{}) error {
@@ -0,0 +1,403 @@
+// Package gitsync creates template versions from git repositories. A
+// template can track a branch of a repository, and whenever that branch
+// moves, either because a webhook was delivered or because the branch was
+// polled, a new template version is created from the commit.
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+// MinPollInterval is the shortest interval a branch may be polled at.
+const MinPollInterval = time.Minute
+
+// scpLikeURL matches the scp-like syntax git accepts for SSH remotes, e.g.
+// git@github.com:coder/coder.git.
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^/]`)
+
+// ValidateRepositoryURL ensures the URL refers to a remote repository. Local
+// paths and file:// URLs are rejected, since they would let template admins
+// read files from the coderd host.
+func ValidateRepositoryURL(raw string) error {
+	if raw == "" {
+		return xerrors.New("repository URL is required")
+	}
+	if strings.HasPrefix(raw, "-") {
+		return xerrors.New("repository URL must not start with a dash")
+	}
+	if scpLikeURL.MatchString(raw) {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return xerrors.Errorf("parse repository URL: %w", err)
+	}
+	switch u.Scheme {
+	case "https", "http", "ssh", "git":
+	default:
+		return xerrors.Errorf("unsupported repository URL scheme %q, must be one of https, http, ssh or git", u.Scheme)
+	}
+	if u.Host == "" {
+		return xerrors.New("repository URL must include a host")
+	}
+	return nil
+}
+
+// ValidateBranch ensures the branch name can be safely passed to git.
+func ValidateBranch(branch string) error {
+	if branch == "" {
+		return xerrors.New("branch is required")
+	}
+	if strings.HasPrefix(branch, "-") || strings.ContainsAny(branch, " ~^:?*[\\") || strings.Contains(branch, "..") {
+		return xerrors.Errorf("invalid branch name %q", branch)
+	}
+	return nil
+}
+
+// ValidateSubdirectory ensures the subdirectory stays within the repository.
+func ValidateSubdirectory(subdirectory string) error {
+	if subdirectory == "" {
+		return nil
+	}
+	if !filepath.IsLocal(filepath.FromSlash(subdirectory)) {
+		return xerrors.Errorf("subdirectory %q must be a relative path within the repository", subdirectory)
+	}
+	return nil
+}
+
+// Sync checks the branch tracked by the template's git source and creates a
+// new template version if it has moved since the last sync. The outcome,
+// including any error, is recorded on the source, which is returned.
+//
+// Versions are created on behalf of the user who configured the source, so
+// syncing stops working if they lose permission to update the template.
+func Sync(ctx context.Context, db database.Store, ps pubsub.Pubsub, logger slog.Logger, templateID uuid.UUID) (database.TemplateGitSource, error) {
+	//nolint:gocritic // The source is read before we know who to act as.
+	source, err := db.GetTemplateGitSourceByTemplateID(dbauthz.AsSystemRestricted(ctx), templateID)
+	if err != nil {
+		return database.TemplateGitSource{}, xerrors.Errorf("get template git source: %w", err)
+	}
+	logger = logger.With(slog.F("template_id", templateID), slog.F("repository_url", source.RepositoryURL), slog.F("branch", source.Branch))
+
+	userCtx, err := actAs(ctx, db, source.CreatedBy)
+	if err != nil {
+		return database.TemplateGitSource{}, xerrors.Errorf("authorize as %s: %w", source.CreatedBy, err)
+	}
+
+	version, syncErr := sync(userCtx, db, ps, logger, source)
+	status := database.UpdateTemplateGitSourceSyncStatusParams{
+		TemplateID:            templateID,
+		UpdatedAt:             dbtime.Now(),
+		LastCheckedAt:         sql.NullTime{Time: dbtime.Now(), Valid: true},
+		LastCommitSHA:         source.LastCommitSHA,
+		LastTemplateVersionID: source.LastTemplateVersionID,
+	}
+	if syncErr != nil {
+		logger.Warn(ctx, "sync template from git", slog.Error(syncErr))
+		status.LastError = syncErr.Error()
+	} else if version.ID != uuid.Nil {
+		logger.Info(ctx, "created template version from git",
+			slog.F("template_version_id", version.ID), slog.F("commit", version.GitCommitSHA))
+		status.LastCommitSHA = version.GitCommitSHA
+		status.LastTemplateVersionID = uuid.NullUUID{UUID: version.ID, Valid: true}
+	}
+	err = db.UpdateTemplateGitSourceSyncStatus(userCtx, status)
+	if err != nil {
+		return database.TemplateGitSource{}, xerrors.Errorf("update sync status: %w", err)
+	}
+
+	//nolint:gocritic // Read back as the same actor the source was read as.
+	source, err = db.GetTemplateGitSourceByTemplateID(dbauthz.AsSystemRestricted(ctx), templateID)
+	if err != nil {
+		return database.TemplateGitSource{}, xerrors.Errorf("get template git source: %w", err)
+	}
+	return source, syncErr
+}
+
+// sync creates a template version from the head of the source's branch. A
+// zero version is returned if the branch hasn't moved.
+func sync(ctx context.Context, db database.Store, ps pubsub.Pubsub, logger slog.Logger, source database.TemplateGitSource) (database.TemplateVersion, error) {
+	head, err := remoteHead(ctx, source.RepositoryURL, source.Branch)
+	if err != nil {
+		return database.TemplateVersion{}, err
+	}
+	if head == source.LastCommitSHA {
+		return database.TemplateVersion{}, nil
+	}
+
+	dir, err := os.MkdirTemp("", "coder-template-git-*")
+	if err != nil {
+		return database.TemplateVersion{}, xerrors.Errorf("create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = git(ctx, "", "clone", "--quiet", "--depth", "1", "--single-branch", "--branch", source.Branch, "--", source.RepositoryURL, dir)
+	if err != nil {
+		return database.TemplateVersion{}, err
+	}
+	sha, err := git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return database.TemplateVersion{}, err
+	}
+	message, err := git(ctx, dir, "log", "-1", "--format=%B")
+	if err != nil {
+		return database.TemplateVersion{}, err
+	}
+
+	templateDir, err := resolveSubdirectory(dir, source.Subdirectory)
+	if err != nil {
+		return database.TemplateVersion{}, err
+	}
+	var archive bytes.Buffer
+	err = provisionersdk.Tar(&archive, logger, templateDir, provisionersdk.TemplateArchiveLimit)
+	if err != nil {
+		return database.TemplateVersion{}, xerrors.Errorf("archive template: %w", err)
+	}
+
+	var (
+		version database.TemplateVersion
+		job     database.ProvisionerJob
+	)
+	err = db.InTx(func(tx database.Store) error {
+		// Serialize syncs of the same template across replicas, and skip the
+		// commit if a concurrent sync got to it first.
+		err := tx.AcquireLock(ctx, database.GenLockID("template-git-sync:"+source.TemplateID.String()))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		current, err := tx.GetTemplateGitSourceByTemplateID(ctx, source.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("get template git source: %w", err)
+		}
+		if current.LastCommitSHA == sha {
+			return nil
+		}
+
+		version, job, err = insertVersion(ctx, tx, source, archive.Bytes(), sha, message)
+		return err
+	}, nil)
+	if err != nil {
+		return database.TemplateVersion{}, err
+	}
+	if version.ID == uuid.Nil {
+		return database.TemplateVersion{}, nil
+	}
+
+	err = provisionerjobs.PostJob(ps, job)
+	if err != nil {
+		// The provisioners will pick the job up on their next poll.
+		logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	return version, nil
+}
+
+func insertVersion(ctx context.Context, tx database.Store, source database.TemplateGitSource, archive []byte, sha, message string) (database.TemplateVersion, database.ProvisionerJob, error) {
+	template, err := tx.GetTemplateByID(ctx, source.TemplateID)
+	if err != nil {
+		return database.TemplateVersion{}, database.ProvisionerJob{}, xerrors.Errorf("get template: %w", err)
+	}
+
+	// Run the import on the same provisioners as the active version.
+	tags := provisionersdk.MutateTags(source.CreatedBy, nil)
+	activeVersion, err := tx.GetTemplateVersionByID(ctx, template.ActiveVersionID)
+	if err == nil {
+		activeJob, err := tx.GetProvisionerJobByID(ctx, activeVersion.JobID)
+		if err == nil {
+			tags = provisionersdk.MutateTags(source.CreatedBy, activeJob.Tags)
+		}
+	}
+
+	hashBytes := sha256.Sum256(archive)
+	hash := hex.EncodeToString(hashBytes[:])
+	file, err := tx.GetFileByHashAndCreator(ctx, database.GetFileByHashAndCreatorParams{
+		Hash:      hash,
+		CreatedBy: source.CreatedBy,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		file, err = tx.InsertFile(ctx, database.InsertFileParams{
+			ID:        uuid.New(),
+			Hash:      hash,
+			CreatedBy: source.CreatedBy,
+			CreatedAt: dbtime.Now(),
+			Mimetype:  "application/x-tar",
+			Data:      archive,
+		})
+	}
+	if err != nil {
+		return database.TemplateVersion{}, database.ProvisionerJob{}, xerrors.Errorf("insert file: %w", err)
+	}
+
+	versionID := uuid.New()
+	input, err := json.Marshal(provisionerdserver.TemplateVersionImportJob{
+		TemplateVersionID: versionID,
+		ActivateOnSuccess: source.AutoPromote,
+	})
+	if err != nil {
+		return database.TemplateVersion{}, database.ProvisionerJob{}, xerrors.Errorf("marshal job input: %w", err)
+	}
+	job, err := tx.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+		ID:             uuid.New(),
+		CreatedAt:      dbtime.Now(),
+		UpdatedAt:      dbtime.Now(),
+		OrganizationID: template.OrganizationID,
+		InitiatorID:    source.CreatedBy,
+		Provisioner:    template.Provisioner,
+		StorageMethod:  database.ProvisionerStorageMethodFile,
+		FileID:         file.ID,
+		Type:           database.ProvisionerJobTypeTemplateVersionImport,
+		Input:          input,
+		Tags:           tags,
+		// Versions are created without anyone waiting on them.
+		Priority:   database.ProvisionerJobPriorityAutobuild,
+		TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
+	})
+	if err != nil {
+		return database.TemplateVersion{}, database.ProvisionerJob{}, xerrors.Errorf("insert provisioner job: %w", err)
+	}
+
+	// Name the version after the commit, unless a version of that name
+	// already exists (e.g. the branch was reset to an earlier commit).
+	name := sha
+	if len(name) > 7 {
+		name = name[:7]
+	}
+	_, err = tx.GetTemplateVersionByTemplateIDAndName(ctx, database.GetTemplateVersionByTemplateIDAndNameParams{
+		TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
+		Name:       name,
+	})
+	if err == nil {
+		name = namesgenerator.GetRandomName(1)
+	}
+
+	message = strings.TrimSpace(message)
+	subject, _, _ := strings.Cut(message, "\n")
+	err = tx.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
+		ID:               versionID,
+		TemplateID:       uuid.NullUUID{UUID: template.ID, Valid: true},
+		OrganizationID:   template.OrganizationID,
+		CreatedAt:        dbtime.Now(),
+		UpdatedAt:        dbtime.Now(),
+		Name:             name,
+		Message:          subject,
+		JobID:            job.ID,
+		CreatedBy:        source.CreatedBy,
+		GitCommitSHA:     sha,
+		GitCommitMessage: message,
+	})
+	if err != nil {
+		return database.TemplateVersion{}, database.ProvisionerJob{}, xerrors.Errorf("insert template version: %w", err)
+	}
+	version, err := tx.GetTemplateVersionByID(ctx, versionID)
+	if err != nil {
+		return database.TemplateVersion{}, database.ProvisionerJob{}, xerrors.Errorf("get template version: %w", err)
+	}
+	return version, job, nil
+}
+
+// actAs returns a context authorized as the given user.
+func actAs(ctx context.Context, db database.Store, userID uuid.UUID) (context.Context, error) {
+	//nolint:gocritic // The user's roles must be read to authorize as them.
+	roles, err := db.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), userID)
+	if err != nil {
+		return nil, xerrors.Errorf("get user roles: %w", err)
+	}
+	if roles.Status != database.UserStatusActive {
+		return nil, xerrors.Errorf("user is not active (status = %q)", roles.Status)
+	}
+	//nolint:gocritic // Custom roles must be read to authorize the user.
+	rbacRoles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), db, roles.Roles)
+	if err != nil {
+		return nil, xerrors.Errorf("expand roles: %w", err)
+	}
+	return dbauthz.As(ctx, rbac.Subject{
+		ID:     userID.String(),
+		Roles:  rbacRoles,
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}.WithCachedASTValue()), nil
+}
+
+// resolveSubdirectory returns the template directory within the clone,
+// refusing symlinks that point outside of it.
+func resolveSubdirectory(clone, subdirectory string) (string, error) {
+	err := ValidateSubdirectory(subdirectory)
+	if err != nil {
+		return "", err
+	}
+	root, err := filepath.EvalSymlinks(clone)
+	if err != nil {
+		return "", xerrors.Errorf("resolve clone: %w", err)
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(subdirectory)))
+	if err != nil {
+		return "", xerrors.Errorf("subdirectory %q not found in repository", subdirectory)
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || !filepath.IsLocal(rel) && rel != "." {
+		return "", xerrors.Errorf("subdirectory %q must be within the repository", subdirectory)
+	}
+	return dir, nil
+}
+
+// remoteHead returns the commit the branch points at without cloning.
+func remoteHead(ctx context.Context, repositoryURL, branch string) (string, error) {
+	if strings.HasPrefix(repositoryURL, "-") {
+		return "", xerrors.New("repository URL must not start with a dash")
+	}
+	out, err := git(ctx, "", "ls-remote", "--heads", repositoryURL, "refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+	sha, _, ok := strings.Cut(out, "\t")
+	if !ok || sha == "" {
+		return "", xerrors.Errorf("branch %q not found", branch)
+	}
+	return sha, nil
+}
+
+// git runs a git command that must not prompt for credentials, returning
+// its trimmed output.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", xerrors.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,274 @@
+package gitsync_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/gitsync"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestValidateRepositoryURL(t *testing.T) {
+	t.Parallel()
+
+	for raw, valid := range map[string]bool{
+		"https://github.com/coder/coder.git": true,
+		"ssh://git@github.com/coder/coder":   true,
+		"git@github.com:coder/coder.git":     true,
+		"git://example.com/repo":             true,
+		"":                                   false,
+		"file:///etc":                        false,
+		"/srv/repo":                          false,
+		"../repo":                            false,
+		"--upload-pack=touch":                false,
+		"https:///coder/coder":               false,
+	} {
+		err := gitsync.ValidateRepositoryURL(raw)
+		if valid {
+			require.NoError(t, err, raw)
+		} else {
+			require.Error(t, err, raw)
+		}
+	}
+
+	require.NoError(t, gitsync.ValidateSubdirectory("templates/docker"))
+	require.Error(t, gitsync.ValidateSubdirectory("../docker"))
+	require.Error(t, gitsync.ValidateSubdirectory("/docker"))
+	require.NoError(t, gitsync.ValidateBranch("feature/git-sync"))
+	require.Error(t, gitsync.ValidateBranch("--force"))
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreatesVersions", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db, rawDB, ps, template := setup(t)
+		repo := gitRepo(t, map[string]string{"templates/docker/main.tf": `resource "coder_agent" "main" {}`})
+		source := dbgen.TemplateGitSource(t, rawDB, database.TemplateGitSource{
+			TemplateID:    template.ID,
+			CreatedBy:     template.CreatedBy,
+			RepositoryURL: repo,
+			Subdirectory:  "templates/docker",
+			AutoPromote:   true,
+		})
+
+		source, err := gitsync.Sync(ctx, db, ps, slogtest.Make(t, nil), source.TemplateID)
+		require.NoError(t, err)
+		require.Empty(t, source.LastError)
+		require.True(t, source.LastCheckedAt.Valid)
+		require.Len(t, source.LastCommitSHA, 40)
+		require.True(t, source.LastTemplateVersionID.Valid)
+
+		version, err := rawDB.GetTemplateVersionByID(ctx, source.LastTemplateVersionID.UUID)
+		require.NoError(t, err)
+		require.Equal(t, source.LastCommitSHA, version.GitCommitSHA)
+		require.Equal(t, source.LastCommitSHA[:7], version.Name)
+		require.Equal(t, "Add template", version.Message)
+		require.Equal(t, "Add template\n\nWith a body.", version.GitCommitMessage)
+		require.Equal(t, template.CreatedBy, version.CreatedBy)
+
+		job, err := rawDB.GetProvisionerJobByID(ctx, version.JobID)
+		require.NoError(t, err)
+		require.Equal(t, database.ProvisionerJobTypeTemplateVersionImport, job.Type)
+		var input provisionerdserver.TemplateVersionImportJob
+		require.NoError(t, json.Unmarshal(job.Input, &input))
+		require.Equal(t, version.ID, input.TemplateVersionID)
+		require.True(t, input.ActivateOnSuccess)
+
+		// The archive only contains the subdirectory.
+		file, err := rawDB.GetFileByID(ctx, job.FileID)
+		require.NoError(t, err)
+		require.Contains(t, string(file.Data), "main.tf")
+		require.NotContains(t, string(file.Data), "templates/docker")
+
+		// Nothing changed, so no version is created.
+		first := source
+		source, err = gitsync.Sync(ctx, db, ps, slogtest.Make(t, nil), source.TemplateID)
+		require.NoError(t, err)
+		require.Equal(t, first.LastTemplateVersionID, source.LastTemplateVersionID)
+		require.False(t, source.LastCheckedAt.Time.Before(first.LastCheckedAt.Time))
+
+		gitCommit(t, repo, map[string]string{"templates/docker/main.tf": `resource "coder_agent" "dev" {}`}, "Rename agent")
+		source, err = gitsync.Sync(ctx, db, ps, slogtest.Make(t, nil), source.TemplateID)
+		require.NoError(t, err)
+		require.NotEqual(t, first.LastCommitSHA, source.LastCommitSHA)
+		require.NotEqual(t, first.LastTemplateVersionID, source.LastTemplateVersionID)
+	})
+
+	t.Run("BranchNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db, rawDB, ps, template := setup(t)
+		repo := gitRepo(t, map[string]string{"main.tf": `resource "coder_agent" "main" {}`})
+		dbgen.TemplateGitSource(t, rawDB, database.TemplateGitSource{
+			TemplateID:    template.ID,
+			CreatedBy:     template.CreatedBy,
+			RepositoryURL: repo,
+			Branch:        "release",
+		})
+
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+		source, err := gitsync.Sync(ctx, db, ps, logger, template.ID)
+		require.ErrorContains(t, err, `branch "release" not found`)
+		require.Contains(t, source.LastError, `branch "release" not found`)
+		require.True(t, source.LastCheckedAt.Valid)
+		require.False(t, source.LastTemplateVersionID.Valid)
+	})
+
+	t.Run("SubdirectoryEscapes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		db, rawDB, ps, template := setup(t)
+		repo := gitRepo(t, map[string]string{"main.tf": `resource "coder_agent" "main" {}`})
+		require.NoError(t, os.Symlink("/etc", filepath.Join(repo, "etc")))
+		gitCommit(t, repo, nil, "Add symlink")
+		dbgen.TemplateGitSource(t, rawDB, database.TemplateGitSource{
+			TemplateID:    template.ID,
+			CreatedBy:     template.CreatedBy,
+			RepositoryURL: repo,
+			Subdirectory:  "etc",
+		})
+
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		source, err := gitsync.Sync(ctx, db, ps, logger, template.ID)
+		require.ErrorContains(t, err, "must be within the repository")
+		require.False(t, source.LastTemplateVersionID.Valid)
+	})
+}
+
+func TestPoller(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	db, rawDB, ps, template := setup(t)
+	repo := gitRepo(t, map[string]string{"main.tf": `resource "coder_agent" "main" {}`})
+	dbgen.TemplateGitSource(t, rawDB, database.TemplateGitSource{
+		TemplateID:    template.ID,
+		CreatedBy:     template.CreatedBy,
+		RepositoryURL: repo,
+		PollInterval:  int64(time.Hour),
+	})
+	// Sources without a poll interval are only synced by webhooks.
+	other := dbgen.Template(t, rawDB, database.Template{
+		OrganizationID:  template.OrganizationID,
+		CreatedBy:       template.CreatedBy,
+		ActiveVersionID: template.ActiveVersionID,
+	})
+	dbgen.TemplateGitSource(t, rawDB, database.TemplateGitSource{
+		TemplateID:    other.ID,
+		CreatedBy:     template.CreatedBy,
+		RepositoryURL: repo,
+	})
+
+	tickCh := make(chan time.Time)
+	statsCh := make(chan gitsync.PollStats)
+	poller := gitsync.NewPoller(ctx, db, ps, slogtest.Make(t, nil), tickCh).WithStatsChannel(statsCh)
+	poller.Start()
+	t.Cleanup(poller.Close)
+
+	now := time.Now()
+	tickCh <- now
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{template.ID}, stats.SyncedTemplateIDs)
+
+	// Not due again until the interval has passed.
+	tickCh <- now.Add(time.Minute)
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.SyncedTemplateIDs)
+
+	tickCh <- now.Add(2 * time.Hour)
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{template.ID}, stats.SyncedTemplateIDs)
+}
+
+// setup returns an authorized store, the store it wraps, and a template
+// created by a template admin.
+func setup(t *testing.T) (database.Store, database.Store, pubsub.Pubsub, database.Template) {
+	t.Helper()
+
+	rawDB, ps := dbtestutil.NewDB(t)
+	db := dbauthz.New(rawDB, rbac.NewCachingAuthorizer(prometheus.NewRegistry()), slogtest.Make(t, nil), coderdtest.AccessControlStorePointer())
+
+	org := dbgen.Organization(t, rawDB, database.Organization{})
+	user := dbgen.User(t, rawDB, database.User{RBACRoles: []string{rbac.RoleTemplateAdmin()}})
+	dbgen.OrganizationMember(t, rawDB, database.OrganizationMember{OrganizationID: org.ID, UserID: user.ID})
+	job := dbgen.ProvisionerJob(t, rawDB, ps, database.ProvisionerJob{
+		OrganizationID: org.ID,
+		InitiatorID:    user.ID,
+		Type:           database.ProvisionerJobTypeTemplateVersionImport,
+	})
+	version := dbgen.TemplateVersion(t, rawDB, database.TemplateVersion{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+		JobID:          job.ID,
+	})
+	template := dbgen.Template(t, rawDB, database.Template{
+		OrganizationID:  org.ID,
+		CreatedBy:       user.ID,
+		ActiveVersionID: version.ID,
+	})
+	return db, rawDB, ps, template
+}
+
+// gitRepo creates a repository with a single commit on main.
+func gitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	git(t, dir, "init", "--quiet", "--initial-branch", "main")
+	gitCommit(t, dir, files, "Add template\n\nWith a body.")
+	return dir
+}
+
+func gitCommit(t *testing.T, dir string, files map[string]string, message string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	git(t, dir, "add", "-A")
+	git(t, dir, "-c", "user.name=Coder", "-c", "user.email=test@coder.com", "commit", "--quiet", "-m", message)
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,132 @@
+package gitsync
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+)
+
+// Poller periodically syncs templates whose git sources have a poll
+// interval. Sources that rely solely on webhooks are ignored.
+type Poller struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	db     database.Store
+	pubsub pubsub.Pubsub
+	log    slog.Logger
+	tick   <-chan time.Time
+	stats  chan<- PollStats
+}
+
+// PollStats contains statistics about the last run of the poller.
+type PollStats struct {
+	// SyncedTemplateIDs contains the IDs of all templates that were due and
+	// synced, whether or not a new version was created.
+	SyncedTemplateIDs []uuid.UUID
+	// Error is the fatal error that occurred during the last run of the
+	// poller, if any. Errors syncing individual templates are recorded on
+	// their sources instead.
+	Error error
+}
+
+// NewPoller returns a new git source poller.
+func NewPoller(ctx context.Context, db database.Store, ps pubsub.Pubsub, log slog.Logger, tick <-chan time.Time) *Poller {
+	//nolint:gocritic // The poller needs to list the sources of all templates.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Poller{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		db:     db,
+		pubsub: ps,
+		log:    log,
+		tick:   tick,
+	}
+}
+
+// WithStatsChannel will cause Poller to push a PollStats to ch after every
+// tick. This push is blocking, so if ch is not read, the poller will hang.
+// This should only be used in tests.
+func (p *Poller) WithStatsChannel(ch chan<- PollStats) *Poller {
+	p.stats = ch
+	return p
+}
+
+// Start will cause the poller to sync due git sources on every tick from its
+// channel. It will stop when its context is Done, or when its channel is
+// closed.
+//
+// Start should only be called once.
+func (p *Poller) Start() {
+	go func() {
+		defer close(p.done)
+		defer p.cancel()
+
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case t, ok := <-p.tick:
+				if !ok {
+					return
+				}
+				stats := p.run(t)
+				if stats.Error != nil {
+					p.log.Warn(p.ctx, "error polling template git sources", slog.Error(stats.Error))
+				}
+				if p.stats != nil {
+					select {
+					case <-p.ctx.Done():
+						return
+					case p.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the poller.
+func (p *Poller) Close() {
+	p.cancel()
+	<-p.done
+}
+
+func (p *Poller) run(t time.Time) PollStats {
+	stats := PollStats{
+		SyncedTemplateIDs: []uuid.UUID{},
+	}
+
+	sources, err := p.db.GetTemplateGitSources(p.ctx)
+	if err != nil {
+		stats.Error = xerrors.Errorf("get template git sources: %w", err)
+		return stats
+	}
+
+	for _, source := range sources {
+		interval := time.Duration(source.PollInterval)
+		if interval <= 0 {
+			continue
+		}
+		if source.LastCheckedAt.Valid && t.Sub(source.LastCheckedAt.Time) < interval {
+			continue
+		}
+		if p.ctx.Err() != nil {
+			break
+		}
+
+		// Errors are recorded on the source and logged by Sync.
+		_, _ = Sync(p.ctx, p.db, p.pubsub, p.log, source.TemplateID)
+		stats.SyncedTemplateIDs = append(stats.SyncedTemplateIDs, source.TemplateID)
+	}
+	return stats
+}
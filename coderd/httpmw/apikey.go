@@ -23,6 +23,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -76,13 +77,17 @@ func UserAuthorization(r *http.Request) Authorization {
 type OAuth2Configs struct {
 	Github OAuth2Config
 	OIDC   OAuth2Config
+	// LoginProviders are the configurations of named login providers by ID.
+	// Users linked to a named provider are refreshed with its configuration
+	// instead of the one for their login type.
+	LoginProviders map[string]OAuth2Config
 }
 
 func (c *OAuth2Configs) IsZero() bool {
 	if c == nil {
 		return true
 	}
-	return c.Github == nil && c.OIDC == nil
+	return c.Github == nil && c.OIDC == nil && len(c.LoginProviders) == 0
 }
 
 const (
@@ -282,6 +287,9 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 					Detail:  fmt.Sprintf("Unexpected authentication type %q.", key.LoginType),
 				})
 			}
+			if link.LoginProvider != "" {
+				oauthConfig = cfg.OAuth2Configs.LoginProviders[link.LoginProvider]
+			}
 
 			// It's possible for cfg.OAuth2Configs to be non-nil, but still
 			// missing this type. For example, if a user logged in with GitHub,
@@ -442,14 +450,23 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	//nolint:gocritic // Custom roles must be read to authorize the user.
+	rbacRoles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), cfg.DB, roles.Roles)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error expanding user's roles. %s", err.Error()),
+		})
+	}
+
 	// Actor is the user's authorization context.
 	authz := Authorization{
 		ActorName: roles.Username,
 		Actor: rbac.Subject{
 			ID:     key.UserID.String(),
-			Roles:  rbac.RoleNames(roles.Roles),
+			Roles:  rbacRoles,
 			Groups: roles.Groups,
-			Scope:  rbac.ScopeName(key.Scope),
+			Scope:  key.RBACScope(),
 		}.WithCachedASTValue(),
 	}
 
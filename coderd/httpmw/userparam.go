@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -48,6 +49,27 @@ func ExtractUserParam(db database.Store) func(http.Handler) http.Handler {
 	}
 }
 
+// ExtractWorkspaceOwnerParam extracts the owner of a workspace from an
+// ID/username in the {user} URL parameter, and makes it available through
+// UserParam. The owner is fetched as the system, because users a workspace has
+// been shared with cannot always read its owner. Handlers must authorize
+// access to the workspace itself.
+func ExtractWorkspaceOwnerParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			// nolint:gocritic // The workspace lookup is authorized instead.
+			user, ok := extractUserContext(dbauthz.AsSystemRestricted(ctx), db, rw, r)
+			if !ok {
+				// response already handled
+				return
+			}
+			ctx = context.WithValue(ctx, userParamContextKey{}, user)
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
+
 // extractUserContext queries the database for the parameterized `{user}` from the request URL.
 func extractUserContext(ctx context.Context, db database.Store, rw http.ResponseWriter, r *http.Request) (user database.User, ok bool) {
 	// userQuery is either a uuid, a username, or 'me'
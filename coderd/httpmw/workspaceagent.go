@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -93,9 +94,19 @@ func ExtractWorkspaceAgent(opts ExtractWorkspaceAgentConfig) func(http.Handler)
 				return
 			}
 
+			//nolint:gocritic // Custom roles must be read to authorize the agent.
+			ownerRoles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), opts.DB, row.OwnerRoles)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error expanding workspace owner roles.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+
 			subject := rbac.Subject{
 				ID:     row.OwnerID.String(),
-				Roles:  rbac.RoleNames(row.OwnerRoles),
+				Roles:  ownerRoles,
 				Groups: row.OwnerGroups,
 				Scope:  rbac.WorkspaceAgentScope(row.WorkspaceID, row.OwnerID),
 			}.WithCachedASTValue()
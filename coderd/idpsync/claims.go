@@ -0,0 +1,56 @@
+package idpsync
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ClaimValues returns the values of the claim at path, with fields of nested
+// objects separated by dots, like "realm_access.roles". Arrays are flattened,
+// so the values of every element are returned. Since claim names are often
+// URLs, like "https://example.com/groups", the longest field name that exists
+// is used at each level.
+//
+// Strings are returned as is, and numbers and booleans are formatted like they
+// are in JSON. Objects and nulls have no values.
+func ClaimValues(claims map[string]interface{}, path string) []string {
+	return appendClaimValues(nil, claims, strings.Split(path, "."))
+}
+
+func appendClaimValues(values []string, claim interface{}, path []string) []string {
+	switch claim := claim.(type) {
+	case []interface{}:
+		for _, elem := range claim {
+			values = appendClaimValues(values, elem, path)
+		}
+		return values
+	case []string:
+		for _, elem := range claim {
+			values = appendClaimValues(values, elem, path)
+		}
+		return values
+	case map[string]interface{}:
+		for i := len(path); i > 0; i-- {
+			if field, ok := claim[strings.Join(path[:i], ".")]; ok {
+				return appendClaimValues(values, field, path[i:])
+			}
+		}
+		return values
+	}
+
+	if len(path) > 0 {
+		return values
+	}
+	switch claim := claim.(type) {
+	case string:
+		return append(values, claim)
+	case bool:
+		return append(values, strconv.FormatBool(claim))
+	case float64:
+		return append(values, strconv.FormatFloat(claim, 'f', -1, 64))
+	case json.Number:
+		return append(values, claim.String())
+	}
+	return values
+}
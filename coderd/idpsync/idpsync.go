@@ -0,0 +1,210 @@
+// Package idpsync evaluates declarative rules that map the claims of an
+// identity provider to organizations, roles, groups and quota allowances.
+package idpsync
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Engine evaluates a set of sync rules.
+type Engine struct {
+	rules []rule
+
+	syncSiteRoles         bool
+	syncOrganizationRoles bool
+	syncGroups            bool
+	ownerManaged          bool
+}
+
+type rule struct {
+	codersdk.OIDCSyncRule
+	match *regexp.Regexp
+}
+
+// New validates and compiles the rules.
+func New(rules codersdk.OIDCSyncRules) (*Engine, error) {
+	e := &Engine{rules: make([]rule, 0, len(rules.Rules))}
+	for i, r := range rules.Rules {
+		if r.Name == "" {
+			return nil, xerrors.Errorf("rule %d: name is required", i)
+		}
+		if r.Claim == "" {
+			return nil, xerrors.Errorf("rule %q: claim is required", r.Name)
+		}
+		if r.Organization == "" && len(r.SiteRoles) == 0 && len(r.OrganizationRoles) == 0 && len(r.Groups) == 0 {
+			return nil, xerrors.Errorf("rule %q: must assign an organization, roles or groups", r.Name)
+		}
+		if !rules.SyncRoles && (len(r.SiteRoles) > 0 || len(r.OrganizationRoles) > 0) {
+			return nil, xerrors.Errorf("rule %q: assigning roles requires sync_roles to be enabled", r.Name)
+		}
+		if r.QuotaAllowance != nil {
+			if len(r.Groups) == 0 {
+				return nil, xerrors.Errorf("rule %q: quota allowance requires groups", r.Name)
+			}
+			if *r.QuotaAllowance < 0 {
+				return nil, xerrors.Errorf("rule %q: quota allowance must not be negative", r.Name)
+			}
+		}
+		for _, names := range [][]string{r.SiteRoles, r.OrganizationRoles, r.Groups} {
+			for _, name := range names {
+				if strings.TrimSpace(name) == "" {
+					return nil, xerrors.Errorf("rule %q: role and group names must not be empty", r.Name)
+				}
+			}
+		}
+
+		// An empty pattern matches every value, and captures it as $0.
+		pattern := r.Match
+		if pattern == "" {
+			pattern = "(?s)^.*$"
+		}
+		match, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, xerrors.Errorf("rule %q: invalid match: %w", r.Name, err)
+		}
+		e.rules = append(e.rules, rule{OIDCSyncRule: r, match: match})
+		e.syncSiteRoles = e.syncSiteRoles || len(r.SiteRoles) > 0
+		e.syncOrganizationRoles = e.syncOrganizationRoles || len(r.OrganizationRoles) > 0
+		e.syncGroups = e.syncGroups || len(r.Groups) > 0
+		for _, role := range r.SiteRoles {
+			e.ownerManaged = e.ownerManaged || role == rbac.RoleOwner()
+		}
+	}
+	return e, nil
+}
+
+// Empty returns whether there are no rules, in which case claims are synced
+// as configured by deployment flags.
+func (e *Engine) Empty() bool {
+	return len(e.rules) == 0
+}
+
+// Result is what the rules assign for a set of claims.
+type Result struct {
+	// MatchedRules are the names of the rules that matched, in order.
+	MatchedRules []string
+	SiteRoles    []string
+	// Organizations holds the assignments by organization name. The default
+	// organization has the empty name.
+	Organizations map[string]Organization
+
+	// SyncSiteRoles, SyncOrganizationRoles and SyncGroups are whether any
+	// rule assigns that kind, whether it matched or not. Kinds no rule
+	// assigns must be left unchanged.
+	SyncSiteRoles         bool
+	SyncOrganizationRoles bool
+	SyncGroups            bool
+	// OwnerManaged is whether a rule assigns the owner role by name. If not,
+	// the owner role must not be removed from users that have it.
+	OwnerManaged bool
+}
+
+// Organization is what the rules assign in an organization. Role names are
+// not qualified with the organization ID.
+type Organization struct {
+	Roles  []string
+	Groups []string
+	// QuotaAllowances are the quota allowances of groups by name.
+	QuotaAllowances map[string]int
+}
+
+// Evaluate returns what the rules assign for the claims. Each value of the
+// claim of a rule is matched separately, so a rule can assign different names
+// for each value.
+func (e *Engine) Evaluate(claims map[string]interface{}) Result {
+	var (
+		matched   []string
+		siteRoles = map[string]struct{}{}
+		orgs      = map[string]*orgSets{}
+	)
+	for _, r := range e.rules {
+		ruleMatched := false
+		for _, value := range ClaimValues(claims, r.Claim) {
+			loc := r.match.FindStringSubmatchIndex(value)
+			if loc == nil {
+				continue
+			}
+			ruleMatched = true
+			expand := func(template string) string {
+				return strings.TrimSpace(string(r.match.ExpandString(nil, template, value, loc)))
+			}
+
+			addAll(siteRoles, r.SiteRoles, expand)
+			if r.Organization == "" && len(r.OrganizationRoles) == 0 && len(r.Groups) == 0 {
+				continue
+			}
+			orgName := expand(r.Organization)
+			org, ok := orgs[orgName]
+			if !ok {
+				org = &orgSets{
+					roles:  map[string]struct{}{},
+					groups: map[string]struct{}{},
+					quotas: map[string]int{},
+				}
+				orgs[orgName] = org
+			}
+			addAll(org.roles, r.OrganizationRoles, expand)
+			for _, group := range r.Groups {
+				group = expand(group)
+				if group == "" {
+					continue
+				}
+				org.groups[group] = struct{}{}
+				if r.QuotaAllowance != nil && *r.QuotaAllowance >= org.quotas[group] {
+					org.quotas[group] = *r.QuotaAllowance
+				}
+			}
+		}
+		if ruleMatched {
+			matched = append(matched, r.Name)
+		}
+	}
+
+	result := Result{
+		MatchedRules:          matched,
+		SiteRoles:             sortedKeys(siteRoles),
+		Organizations:         make(map[string]Organization, len(orgs)),
+		SyncSiteRoles:         e.syncSiteRoles,
+		SyncOrganizationRoles: e.syncOrganizationRoles,
+		SyncGroups:            e.syncGroups,
+		OwnerManaged:          e.ownerManaged,
+	}
+	for name, org := range orgs {
+		result.Organizations[name] = Organization{
+			Roles:           sortedKeys(org.roles),
+			Groups:          sortedKeys(org.groups),
+			QuotaAllowances: org.quotas,
+		}
+	}
+	return result
+}
+
+type orgSets struct {
+	roles  map[string]struct{}
+	groups map[string]struct{}
+	quotas map[string]int
+}
+
+func addAll(set map[string]struct{}, templates []string, expand func(string) string) {
+	for _, template := range templates {
+		if name := expand(template); name != "" {
+			set[name] = struct{}{}
+		}
+	}
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,208 @@
+package idpsync_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestClaimValues(t *testing.T) {
+	t.Parallel()
+
+	var claims map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"email": "alice@coder.com",
+		"email_verified": true,
+		"level": 3,
+		"groups": ["admins", "developers"],
+		"realm_access": {"roles": ["owner", {"name": "nested"}]},
+		"https://coder.com/claims": {"teams": [{"name": "infra"}, {"name": "web"}]},
+		"empty": null
+	}`), &claims)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		Path     string
+		Expected []string
+	}{
+		{Path: "email", Expected: []string{"alice@coder.com"}},
+		{Path: "email_verified", Expected: []string{"true"}},
+		{Path: "level", Expected: []string{"3"}},
+		{Path: "groups", Expected: []string{"admins", "developers"}},
+		{Path: "realm_access.roles", Expected: []string{"owner"}},
+		{Path: "realm_access.roles.name", Expected: []string{"nested"}},
+		{Path: "https://coder.com/claims.teams.name", Expected: []string{"infra", "web"}},
+		{Path: "realm_access", Expected: nil},
+		{Path: "empty", Expected: nil},
+		{Path: "missing", Expected: nil},
+		{Path: "email.domain", Expected: nil},
+	}
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Path, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.Expected, idpsync.ClaimValues(claims, c.Path))
+		})
+	}
+}
+
+func TestEngine(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Evaluate", func(t *testing.T) {
+		t.Parallel()
+
+		engine, err := idpsync.New(codersdk.OIDCSyncRules{
+			SyncRoles: true,
+			Rules: []codersdk.OIDCSyncRule{{
+				Name:      "admins",
+				Claim:     "groups",
+				Match:     "^admins$",
+				SiteRoles: []string{"owner"},
+			}, {
+				Name:   "teams",
+				Claim:  "groups",
+				Match:  "^team-(.+)$",
+				Groups: []string{"$1"},
+			}, {
+				Name:              "departments",
+				Claim:             "org.department",
+				Organization:      "dept-$0",
+				OrganizationRoles: []string{"organization-admin"},
+				Groups:            []string{"everyone-${0}"},
+				QuotaAllowance:    ptr.Ref(10),
+			}, {
+				Name:           "quota",
+				Claim:          "groups",
+				Match:          "^team-infra$",
+				Groups:         []string{"infra"},
+				QuotaAllowance: ptr.Ref(50),
+			}, {
+				Name:      "unmatched",
+				Claim:     "missing",
+				SiteRoles: []string{"auditor"},
+			}},
+		})
+		require.NoError(t, err)
+		require.False(t, engine.Empty())
+
+		result := engine.Evaluate(map[string]interface{}{
+			"groups": []interface{}{"admins", "team-infra", "team-web", "other"},
+			"org":    map[string]interface{}{"department": "eng"},
+		})
+		require.Equal(t, []string{"admins", "teams", "departments", "quota"}, result.MatchedRules)
+		require.Equal(t, []string{"owner"}, result.SiteRoles)
+		require.Equal(t, map[string]idpsync.Organization{
+			"": {
+				Roles:           []string{},
+				Groups:          []string{"infra", "web"},
+				QuotaAllowances: map[string]int{"infra": 50},
+			},
+			"dept-eng": {
+				Roles:           []string{"organization-admin"},
+				Groups:          []string{"everyone-eng"},
+				QuotaAllowances: map[string]int{"everyone-eng": 10},
+			},
+		}, result.Organizations)
+		require.True(t, result.SyncSiteRoles)
+		require.True(t, result.SyncOrganizationRoles)
+		require.True(t, result.SyncGroups)
+		require.True(t, result.OwnerManaged)
+	})
+
+	t.Run("GroupsOnly", func(t *testing.T) {
+		t.Parallel()
+
+		engine, err := idpsync.New(codersdk.OIDCSyncRules{
+			Rules: []codersdk.OIDCSyncRule{{
+				Name:   "teams",
+				Claim:  "groups",
+				Groups: []string{"$0"},
+			}},
+		})
+		require.NoError(t, err)
+		result := engine.Evaluate(map[string]interface{}{"groups": []interface{}{"infra"}})
+		require.False(t, result.SyncSiteRoles)
+		require.False(t, result.SyncOrganizationRoles)
+		require.True(t, result.SyncGroups)
+		require.False(t, result.OwnerManaged)
+	})
+
+	t.Run("NoRules", func(t *testing.T) {
+		t.Parallel()
+
+		engine, err := idpsync.New(codersdk.OIDCSyncRules{})
+		require.NoError(t, err)
+		require.True(t, engine.Empty())
+		result := engine.Evaluate(map[string]interface{}{"groups": []interface{}{"admins"}})
+		require.Empty(t, result.MatchedRules)
+		require.Empty(t, result.SiteRoles)
+		require.Empty(t, result.Organizations)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			Name          string
+			Rule          codersdk.OIDCSyncRule
+			NoSyncRoles   bool
+			ErrorContains string
+		}{
+			{
+				Name:          "RolesWithoutSyncRoles",
+				Rule:          codersdk.OIDCSyncRule{Name: "rule", Claim: "groups", SiteRoles: []string{"owner"}},
+				NoSyncRoles:   true,
+				ErrorContains: "requires sync_roles",
+			},
+			{
+				Name:          "NoName",
+				Rule:          codersdk.OIDCSyncRule{Claim: "groups", Groups: []string{"a"}},
+				ErrorContains: "name is required",
+			},
+			{
+				Name:          "NoClaim",
+				Rule:          codersdk.OIDCSyncRule{Name: "rule", Groups: []string{"a"}},
+				ErrorContains: "claim is required",
+			},
+			{
+				Name:          "NoAssignments",
+				Rule:          codersdk.OIDCSyncRule{Name: "rule", Claim: "groups"},
+				ErrorContains: "must assign",
+			},
+			{
+				Name:          "InvalidMatch",
+				Rule:          codersdk.OIDCSyncRule{Name: "rule", Claim: "groups", Match: "(", Groups: []string{"a"}},
+				ErrorContains: "invalid match",
+			},
+			{
+				Name:          "QuotaWithoutGroups",
+				Rule:          codersdk.OIDCSyncRule{Name: "rule", Claim: "groups", SiteRoles: []string{"a"}, QuotaAllowance: ptr.Ref(1)},
+				ErrorContains: "quota allowance requires groups",
+			},
+			{
+				Name:          "NegativeQuota",
+				Rule:          codersdk.OIDCSyncRule{Name: "rule", Claim: "groups", Groups: []string{"a"}, QuotaAllowance: ptr.Ref(-1)},
+				ErrorContains: "must not be negative",
+			},
+			{
+				Name:          "EmptyGroup",
+				Rule:          codersdk.OIDCSyncRule{Name: "rule", Claim: "groups", Groups: []string{" "}},
+				ErrorContains: "must not be empty",
+			},
+		}
+		for _, c := range testCases {
+			c := c
+			t.Run(c.Name, func(t *testing.T) {
+				t.Parallel()
+				_, err := idpsync.New(codersdk.OIDCSyncRules{SyncRoles: !c.NoSyncRoles, Rules: []codersdk.OIDCSyncRule{c.Rule}})
+				require.ErrorContains(t, err, c.ErrorContains)
+			})
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOrganizationMembers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AddListRemove", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		_, user := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "business-unit",
+		})
+		require.NoError(t, err)
+
+		member, err := client.PostOrganizationMember(ctx, org.ID, user.Username)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, member.UserID)
+		require.Equal(t, org.ID, member.OrganizationID)
+
+		_, err = client.PostOrganizationMember(ctx, org.ID, user.Username)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		members, err := client.OrganizationMembers(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		usernames := []string{members[0].Username, members[1].Username}
+		require.ElementsMatch(t, []string{user.Username, coderdtest.FirstUserParams.Username}, usernames)
+
+		err = client.DeleteOrganizationMember(ctx, org.ID, user.Username)
+		require.NoError(t, err)
+
+		members, err = client.OrganizationMembers(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+
+		err = client.DeleteOrganizationMember(ctx, org.ID, user.Username)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("RemoveSelf", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		err := client.DeleteOrganizationMember(ctx, first.OrganizationID, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberCannotRemove", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		_, other := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		err := memberClient.DeleteOrganizationMember(ctx, first.OrganizationID, other.ID.String())
+		require.Error(t, err)
+	})
+
+	t.Run("OrgAdminRemove", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		first := coderdtest.CreateFirstUser(t, client)
+		orgAdminClient, _ := coderdtest.CreateAnotherUser(t, client, first.OrganizationID, rbac.RoleOrgAdmin(first.OrganizationID))
+		_, other := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		err := orgAdminClient.DeleteOrganizationMember(ctx, first.OrganizationID, other.ID.String())
+		require.NoError(t, err)
+	})
+}
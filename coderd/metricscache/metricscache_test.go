@@ -443,6 +443,7 @@ func TestCache_BuildTime(t *testing.T) {
 					Provisioner:   database.ProvisionerTypeEcho,
 					StorageMethod: database.ProvisionerStorageMethodFile,
 					Type:          database.ProvisionerJobTypeWorkspaceBuild,
+					Priority:      database.ProvisionerJobPriorityUser,
 				})
 				require.NoError(t, err)
 
@@ -0,0 +1,28 @@
+// Package dispatch contains the delivery methods for notifications.
+package dispatch
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is a rendered notification ready to be delivered to a user.
+type Message struct {
+	ID        uuid.UUID
+	Event     string
+	UserID    uuid.UUID
+	UserEmail string
+	Username  string
+	Title     string
+	Body      string
+	Labels    map[string]string
+	CreatedAt time.Time
+}
+
+// Handler delivers messages using a single delivery method. Returning an
+// error marks the attempt as failed, and the message may be retried.
+type Handler interface {
+	Dispatch(ctx context.Context, msg Message) error
+}
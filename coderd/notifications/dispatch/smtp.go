@@ -0,0 +1,155 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// SMTPHandler delivers messages as plain-text emails through an SMTP
+// smarthost.
+type SMTPHandler struct {
+	cfg  codersdk.NotificationsEmailConfig
+	from *mail.Address
+	log  slog.Logger
+}
+
+func NewSMTPHandler(cfg codersdk.NotificationsEmailConfig, log slog.Logger) (*SMTPHandler, error) {
+	from, err := mail.ParseAddress(cfg.From.String())
+	if err != nil {
+		return nil, xerrors.Errorf("parse from address %q: %w", cfg.From.String(), err)
+	}
+	if cfg.Smarthost.Host == "" || cfg.Smarthost.Port == "" {
+		return nil, xerrors.New("smarthost must be set")
+	}
+	return &SMTPHandler{
+		cfg:  cfg,
+		from: from,
+		log:  log.Named("smtp"),
+	}, nil
+}
+
+func (s *SMTPHandler) Dispatch(ctx context.Context, msg Message) error {
+	to, err := mail.ParseAddress(msg.UserEmail)
+	if err != nil {
+		return xerrors.Errorf("parse recipient address %q: %w", msg.UserEmail, err)
+	}
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return xerrors.Errorf("dial smarthost: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.cfg.Smarthost.Host)
+	if err != nil {
+		return xerrors.Errorf("create client: %w", err)
+	}
+	defer c.Close()
+
+	if hello := s.cfg.Hello.String(); hello != "" {
+		if err := c.Hello(hello); err != nil {
+			return xerrors.Errorf("hello: %w", err)
+		}
+	}
+
+	if !s.cfg.ForceTLS.Value() {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			err = c.StartTLS(&tls.Config{
+				ServerName: s.cfg.Smarthost.Host,
+				MinVersion: tls.VersionTLS12,
+			})
+			if err != nil {
+				return xerrors.Errorf("starttls: %w", err)
+			}
+		}
+	}
+
+	if username := s.cfg.Username.String(); username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return xerrors.New("smarthost does not support authentication")
+		}
+		auth := smtp.PlainAuth("", username, s.cfg.Password.String(), s.cfg.Smarthost.Host)
+		if err := c.Auth(auth); err != nil {
+			return xerrors.Errorf("authenticate: %w", err)
+		}
+	}
+
+	if err := c.Mail(s.from.Address); err != nil {
+		return xerrors.Errorf("mail from: %w", err)
+	}
+	if err := c.Rcpt(to.Address); err != nil {
+		return xerrors.Errorf("rcpt to: %w", err)
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return xerrors.Errorf("data: %w", err)
+	}
+	if _, err := w.Write(s.render(msg, to)); err != nil {
+		_ = w.Close()
+		return xerrors.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return xerrors.Errorf("close message: %w", err)
+	}
+
+	if err := c.Quit(); err != nil {
+		// The message was accepted, so a failure to quit cleanly should not
+		// cause it to be sent again.
+		s.log.Warn(ctx, "failed to quit smtp session", slog.Error(err))
+	}
+	s.log.Debug(ctx, "delivered email", slog.F("msg_id", msg.ID))
+	return nil
+}
+
+func (s *SMTPHandler) dial(ctx context.Context) (net.Conn, error) {
+	addr := s.cfg.Smarthost.String()
+	if s.cfg.ForceTLS.Value() {
+		d := &tls.Dialer{
+			Config: &tls.Config{
+				ServerName: s.cfg.Smarthost.Host,
+				MinVersion: tls.VersionTLS12,
+			},
+		}
+		return d.DialContext(ctx, "tcp", addr)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", addr)
+}
+
+// render returns the message formatted as an RFC 5322 email.
+func (s *SMTPHandler) render(msg Message, to *mail.Address) []byte {
+	var buf bytes.Buffer
+	header := func(k, v string) {
+		_, _ = fmt.Fprintf(&buf, "%s: %s\r\n", k, v)
+	}
+	header("From", s.from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("UTF-8", msg.Title))
+	header("Date", msg.CreatedAt.Format(time.RFC1123Z))
+	header("Message-Id", fmt.Sprintf("<%s@%s>", msg.ID, s.cfg.Hello.String()))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	buf.WriteString("\r\n")
+	// SMTP requires CRLF line endings.
+	body := strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n")
+	buf.WriteString(body)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
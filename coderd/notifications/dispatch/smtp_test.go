@@ -0,0 +1,131 @@
+package dispatch_test
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSMTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		addr, received := fakeSMTPServer(t)
+
+		var cfg codersdk.NotificationsEmailConfig
+		require.NoError(t, cfg.From.Set("Coder <coder@coder.com>"))
+		require.NoError(t, cfg.Smarthost.Set(addr))
+		require.NoError(t, cfg.Hello.Set("localhost"))
+		h, err := dispatch.NewSMTPHandler(cfg, slogtest.Make(t, nil))
+		require.NoError(t, err)
+
+		msg := dispatch.Message{
+			ID:        uuid.New(),
+			Event:     "workspace_dormant",
+			UserID:    uuid.New(),
+			UserEmail: "alice@coder.com",
+			Username:  "alice",
+			Title:     "Workspace marked as dormant",
+			Body:      "Your workspace is dormant.\nIt will be deleted soon.",
+			CreatedAt: time.Now(),
+		}
+		require.NoError(t, h.Dispatch(ctx, msg))
+
+		var email fakeEmail
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for email")
+		case email = <-received:
+		}
+		require.Equal(t, "<coder@coder.com>", email.From)
+		require.Equal(t, []string{"<alice@coder.com>"}, email.To)
+		require.Contains(t, email.Data, "Subject: Workspace marked as dormant\r\n")
+		require.Contains(t, email.Data, "To: <alice@coder.com>\r\n")
+		require.Contains(t, email.Data, "Your workspace is dormant.\r\nIt will be deleted soon.")
+	})
+
+	t.Run("InvalidFrom", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg codersdk.NotificationsEmailConfig
+		require.NoError(t, cfg.From.Set("not an address"))
+		require.NoError(t, cfg.Smarthost.Set("localhost:587"))
+		_, err := dispatch.NewSMTPHandler(cfg, slogtest.Make(t, nil))
+		require.Error(t, err)
+	})
+}
+
+type fakeEmail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// fakeSMTPServer accepts a single SMTP session without TLS or
+// authentication and sends the received email on the returned channel.
+func fakeSMTPServer(t *testing.T) (string, <-chan fakeEmail) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	received := make(chan fakeEmail, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 localhost ESMTP")
+
+		var email fakeEmail
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250 localhost")
+			case "MAIL":
+				email.From = strings.TrimPrefix(arg, "FROM:")
+				_ = tp.PrintfLine("250 OK")
+			case "RCPT":
+				email.To = append(email.To, strings.TrimPrefix(arg, "TO:"))
+				_ = tp.PrintfLine("250 OK")
+			case "DATA":
+				_ = tp.PrintfLine("354 Go ahead")
+				lines, err := tp.ReadDotLines()
+				if err != nil {
+					return
+				}
+				email.Data = strings.Join(lines, "\r\n")
+				_ = tp.PrintfLine("250 OK")
+				received <- email
+			case "QUIT":
+				_ = tp.PrintfLine("221 Bye")
+				return
+			default:
+				_ = tp.PrintfLine("502 Not implemented")
+			}
+		}
+	}()
+	return l.Addr().String(), received
+}
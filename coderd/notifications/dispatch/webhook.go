@@ -0,0 +1,94 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// WebhookPayloadVersion is bumped whenever WebhookPayload changes in a
+// backwards-incompatible way.
+const WebhookPayloadVersion = "1.0"
+
+// WebhookPayload is the body POSTed to the configured webhook endpoint.
+type WebhookPayload struct {
+	Version   string            `json:"_version"`
+	MsgID     uuid.UUID         `json:"msg_id"`
+	Event     string            `json:"event"`
+	UserID    uuid.UUID         `json:"user_id"`
+	UserEmail string            `json:"user_email"`
+	Username  string            `json:"username"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Labels    map[string]string `json:"labels"`
+}
+
+// WebhookHandler delivers messages by POSTing them as JSON to an HTTP
+// endpoint.
+type WebhookHandler struct {
+	endpoint *url.URL
+	client   *http.Client
+	log      slog.Logger
+}
+
+func NewWebhookHandler(cfg codersdk.NotificationsWebhookConfig, client *http.Client, log slog.Logger) (*WebhookHandler, error) {
+	endpoint := cfg.Endpoint.Value()
+	if endpoint == nil || endpoint.String() == "" {
+		return nil, xerrors.New("webhook endpoint must be set")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookHandler{
+		endpoint: endpoint,
+		client:   client,
+		log:      log.Named("webhook"),
+	}, nil
+}
+
+func (w *WebhookHandler) Dispatch(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(WebhookPayload{
+		Version:   WebhookPayloadVersion,
+		MsgID:     msg.ID,
+		Event:     msg.Event,
+		UserID:    msg.UserID,
+		UserEmail: msg.UserEmail,
+		Username:  msg.Username,
+		Title:     msg.Title,
+		Body:      msg.Body,
+		Labels:    msg.Labels,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Message-Id", msg.ID.String())
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Include a snippet of the response to aid debugging.
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return xerrors.Errorf("non-2xx response (%d): %s", resp.StatusCode, respBody)
+	}
+	w.log.Debug(ctx, "delivered webhook", slog.F("msg_id", msg.ID), slog.F("status", resp.StatusCode))
+	return nil
+}
@@ -0,0 +1,98 @@
+package dispatch_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+
+	msg := dispatch.Message{
+		ID:        uuid.New(),
+		Event:     "workspace_dormant",
+		UserID:    uuid.New(),
+		UserEmail: "alice@coder.com",
+		Username:  "alice",
+		Title:     "Workspace marked as dormant",
+		Body:      "Your workspace is dormant.",
+		Labels:    map[string]string{"workspace_name": "dev"},
+		CreatedAt: time.Now(),
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		received := make(chan dispatch.WebhookPayload, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, msg.ID.String(), r.Header.Get("X-Message-Id"))
+
+			var payload dispatch.WebhookPayload
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			received <- payload
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		h := newWebhookHandler(t, srv.URL)
+		require.NoError(t, h.Dispatch(ctx, msg))
+
+		payload := <-received
+		require.Equal(t, dispatch.WebhookPayloadVersion, payload.Version)
+		require.Equal(t, msg.ID, payload.MsgID)
+		require.Equal(t, msg.Event, payload.Event)
+		require.Equal(t, msg.UserID, payload.UserID)
+		require.Equal(t, msg.UserEmail, payload.UserEmail)
+		require.Equal(t, msg.Title, payload.Title)
+		require.Equal(t, msg.Body, payload.Body)
+		require.Equal(t, msg.Labels, payload.Labels)
+	})
+
+	t.Run("Non2xx", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusBadGateway)
+			_, _ = rw.Write([]byte("upstream unavailable"))
+		}))
+		t.Cleanup(srv.Close)
+
+		h := newWebhookHandler(t, srv.URL)
+		err := h.Dispatch(ctx, msg)
+		require.ErrorContains(t, err, "502")
+		require.ErrorContains(t, err, "upstream unavailable")
+	})
+
+	t.Run("NoEndpoint", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := dispatch.NewWebhookHandler(codersdk.NotificationsWebhookConfig{}, nil, slogtest.Make(t, nil))
+		require.Error(t, err)
+	})
+}
+
+func newWebhookHandler(t *testing.T, rawURL string) *dispatch.WebhookHandler {
+	t.Helper()
+
+	var cfg codersdk.NotificationsWebhookConfig
+	require.NoError(t, cfg.Endpoint.Set(rawURL))
+	h, err := dispatch.NewWebhookHandler(cfg, nil, slogtest.Make(t, nil))
+	require.NoError(t, err)
+	return h
+}
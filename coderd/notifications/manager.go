@@ -0,0 +1,265 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Manager periodically acquires pending notifications from the database and
+// delivers them. Many replicas may run a Manager at once; each message is
+// leased to a single Manager while it is being delivered.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	cfg       codersdk.NotificationsConfig
+	store     database.Store
+	accessURL *url.URL
+	log       slog.Logger
+	tick      <-chan time.Time
+	stats     chan<- Stats
+
+	handlers map[database.NotificationMethod]dispatch.Handler
+}
+
+// Stats contains statistics about a single run of the Manager.
+type Stats struct {
+	Sent   []uuid.UUID
+	Failed []uuid.UUID
+	// Error is the fatal error that occurred during the run, if any.
+	Error error
+}
+
+// New returns a Manager which delivers notifications using the method
+// configured in cfg on every tick. Call Start to begin delivering.
+func New(ctx context.Context, cfg codersdk.NotificationsConfig, store database.Store, accessURL *url.URL, log slog.Logger, tick <-chan time.Time) (*Manager, error) {
+	log = log.Named("notifications")
+
+	handlers := map[database.NotificationMethod]dispatch.Handler{}
+	switch method := database.NotificationMethod(cfg.Method.String()); method {
+	case database.NotificationMethodSMTP:
+		h, err := dispatch.NewSMTPHandler(cfg.SMTP, log)
+		if err != nil {
+			return nil, xerrors.Errorf("create smtp handler: %w", err)
+		}
+		handlers[method] = h
+	case database.NotificationMethodWebhook:
+		h, err := dispatch.NewWebhookHandler(cfg.Webhook, http.DefaultClient, log)
+		if err != nil {
+			return nil, xerrors.Errorf("create webhook handler: %w", err)
+		}
+		handlers[method] = h
+	default:
+		return nil, xerrors.Errorf("invalid notification method %q, must be one of %v", method, database.AllNotificationMethodValues())
+	}
+
+	//nolint:gocritic // The notification manager delivers messages on behalf of the system.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	return &Manager{
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		cfg:       cfg,
+		store:     store,
+		accessURL: accessURL,
+		log:       log,
+		tick:      tick,
+		handlers:  handlers,
+	}, nil
+}
+
+// WithHandler overrides the handler used to deliver messages for the given
+// method.
+func (m *Manager) WithHandler(method database.NotificationMethod, h dispatch.Handler) *Manager {
+	m.handlers[method] = h
+	return m
+}
+
+// WithStatsChannel will cause the Manager to push Stats to ch after every
+// tick. This push is blocking, so if ch is not read, the Manager will hang.
+// This should only be used in tests.
+func (m *Manager) WithStatsChannel(ch chan<- Stats) *Manager {
+	m.stats = ch
+	return m
+}
+
+// Start will cause the Manager to deliver pending notifications on every
+// tick from its channel. It will stop when its context is Done, or when its
+// channel is closed.
+//
+// Start should only be called once.
+func (m *Manager) Start() {
+	go func() {
+		defer close(m.done)
+		defer m.cancel()
+
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case t, ok := <-m.tick:
+				if !ok {
+					return
+				}
+				stats := m.run(t)
+				if stats.Error != nil {
+					m.log.Warn(m.ctx, "failed to deliver notifications", slog.Error(stats.Error))
+				}
+				if m.stats != nil {
+					select {
+					case <-m.ctx.Done():
+						return
+					case m.stats <- stats:
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Close will stop the Manager. Messages which are being delivered will be
+// acquired again once their lease expires.
+func (m *Manager) Close() {
+	m.cancel()
+	<-m.done
+}
+
+func (m *Manager) run(t time.Time) Stats {
+	stats := Stats{
+		Sent:   []uuid.UUID{},
+		Failed: []uuid.UUID{},
+	}
+
+	msgs, err := m.store.AcquireNotificationMessages(m.ctx, database.AcquireNotificationMessagesParams{
+		Now:         t,
+		LeasedUntil: t.Add(m.cfg.LeasePeriod.Value()),
+		Count:       int32(m.cfg.LeaseCount.Value()),
+	})
+	if err != nil {
+		stats.Error = xerrors.Errorf("acquire notification messages: %w", err)
+		return stats
+	}
+	if len(msgs) == 0 {
+		return stats
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, msg := range msgs {
+		msg := msg
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := m.deliver(msg)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				stats.Failed = append(stats.Failed, msg.ID)
+			} else {
+				stats.Sent = append(stats.Sent, msg.ID)
+			}
+		}()
+	}
+	wg.Wait()
+	return stats
+}
+
+// deliver dispatches a single message and records the outcome.
+func (m *Manager) deliver(msg database.NotificationMessage) error {
+	log := m.log.With(
+		slog.F("msg_id", msg.ID),
+		slog.F("event", msg.Event),
+		slog.F("method", msg.Method),
+		slog.F("attempt", msg.AttemptCount+1),
+	)
+
+	err := m.dispatch(msg)
+	if err == nil {
+		err = m.store.MarkNotificationMessageSent(m.ctx, database.MarkNotificationMessageSentParams{
+			ID:        msg.ID,
+			UpdatedAt: dbtime.Now(),
+		})
+		if err != nil {
+			log.Error(m.ctx, "failed to mark notification as sent", slog.Error(err))
+		}
+		return nil
+	}
+
+	params := database.MarkNotificationMessageFailedParams{
+		ID:           msg.ID,
+		Status:       database.NotificationMessageStatusPending,
+		StatusReason: err.Error(),
+		UpdatedAt:    dbtime.Now(),
+	}
+	if int64(msg.AttemptCount+1) >= m.cfg.MaxSendAttempts.Value() {
+		log.Warn(m.ctx, "giving up on notification", slog.Error(err))
+		params.Status = database.NotificationMessageStatusFailed
+	} else {
+		log.Info(m.ctx, "failed to deliver notification, will retry", slog.Error(err))
+		params.NextRetryAfter.Time = dbtime.Now().Add(m.cfg.RetryInterval.Value())
+		params.NextRetryAfter.Valid = true
+	}
+	if markErr := m.store.MarkNotificationMessageFailed(m.ctx, params); markErr != nil {
+		log.Error(m.ctx, "failed to mark notification as failed", slog.Error(markErr))
+	}
+	return err
+}
+
+func (m *Manager) dispatch(msg database.NotificationMessage) error {
+	handler, ok := m.handlers[msg.Method]
+	if !ok {
+		return xerrors.Errorf("no handler configured for method %q", msg.Method)
+	}
+
+	user, err := m.store.GetUserByID(m.ctx, msg.UserID)
+	if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+
+	title, body, err := render(msg.Event, templateData{
+		Username:     user.Username,
+		WorkspaceURL: m.workspaceURL(user.Username, msg.Payload[LabelWorkspaceName]),
+		Labels:       msg.Payload,
+	})
+	if err != nil {
+		return xerrors.Errorf("render %q notification: %w", msg.Event, err)
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, m.cfg.DispatchTimeout.Value())
+	defer cancel()
+	return handler.Dispatch(ctx, dispatch.Message{
+		ID:        msg.ID,
+		Event:     string(msg.Event),
+		UserID:    user.ID,
+		UserEmail: user.Email,
+		Username:  user.Username,
+		Title:     title,
+		Body:      body,
+		Labels:    msg.Payload,
+		CreatedAt: msg.CreatedAt,
+	})
+}
+
+func (m *Manager) workspaceURL(username, workspaceName string) string {
+	if m.accessURL == nil {
+		return ""
+	}
+	return m.accessURL.JoinPath("@"+username, workspaceName).String()
+}
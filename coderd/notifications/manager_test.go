@@ -0,0 +1,173 @@
+package notifications_test
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/coderd/notifications/dispatch"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestManagerDelivers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = testutil.Context(t, testutil.WaitShort)
+		db      = dbmem.New()
+		log     = slogtest.Make(t, nil)
+		cfg     = defaultConfig(t)
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan notifications.Stats)
+		handler = &fakeHandler{}
+		user    = dbgen.User(t, db, database.User{Username: "alice"})
+	)
+
+	enq, err := notifications.NewStoreEnqueuer(cfg, db, log)
+	require.NoError(t, err)
+	labels := map[string]string{
+		notifications.LabelWorkspaceName: "dev",
+		notifications.LabelDeletingAt:    "tomorrow",
+	}
+	require.NoError(t, enq.Enqueue(ctx, user.ID, database.NotificationEventWorkspaceAutodeletePending, labels, "dedupe"))
+	// Enqueuing the same notification twice should be a no-op.
+	require.NoError(t, enq.Enqueue(ctx, user.ID, database.NotificationEventWorkspaceAutodeletePending, labels, "dedupe"))
+
+	accessURL, err := url.Parse("https://coder.example.com")
+	require.NoError(t, err)
+	mgr, err := notifications.New(ctx, cfg, db, accessURL, log, tickCh)
+	require.NoError(t, err)
+	mgr.WithHandler(database.NotificationMethodWebhook, handler).WithStatsChannel(statsCh)
+	mgr.Start()
+	t.Cleanup(mgr.Close)
+
+	tickCh <- time.Now()
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Sent, 1)
+	require.Empty(t, stats.Failed)
+
+	msgs := handler.Messages()
+	require.Len(t, msgs, 1)
+	require.Equal(t, stats.Sent[0], msgs[0].ID)
+	require.Equal(t, string(database.NotificationEventWorkspaceAutodeletePending), msgs[0].Event)
+	require.Equal(t, user.ID, msgs[0].UserID)
+	require.Equal(t, user.Email, msgs[0].UserEmail)
+	require.Equal(t, `Workspace "dev" will be deleted soon`, msgs[0].Title)
+	require.Contains(t, msgs[0].Body, "Hi alice,")
+	require.Contains(t, msgs[0].Body, "tomorrow")
+	require.Contains(t, msgs[0].Body, "https://coder.example.com/@alice/dev")
+	require.Equal(t, labels, msgs[0].Labels)
+
+	// Sent messages are not delivered again.
+	tickCh <- time.Now()
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.Sent)
+	require.Len(t, handler.Messages(), 1)
+}
+
+func TestManagerRetries(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = testutil.Context(t, testutil.WaitShort)
+		db      = dbmem.New()
+		log     = slogtest.Make(t, nil)
+		cfg     = defaultConfig(t)
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan notifications.Stats)
+		handler = &fakeHandler{err: xerrors.New("endpoint unavailable")}
+		user    = dbgen.User(t, db, database.User{})
+	)
+	cfg.MaxSendAttempts = 2
+
+	enq, err := notifications.NewStoreEnqueuer(cfg, db, log)
+	require.NoError(t, err)
+	require.NoError(t, enq.Enqueue(ctx, user.ID, database.NotificationEventWorkspaceDormant, map[string]string{
+		notifications.LabelWorkspaceName: "dev",
+	}, ""))
+
+	mgr, err := notifications.New(ctx, cfg, db, nil, log, tickCh)
+	require.NoError(t, err)
+	mgr.WithHandler(database.NotificationMethodWebhook, handler).WithStatsChannel(statsCh)
+	mgr.Start()
+	t.Cleanup(mgr.Close)
+
+	// The first attempt fails and the message is scheduled for a retry.
+	now := time.Now()
+	tickCh <- now
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Failed, 1)
+
+	// The message is not retried before the retry interval has elapsed.
+	tickCh <- now
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.Failed)
+
+	// The second attempt fails, and the message is not retried again since
+	// it has reached the maximum number of attempts.
+	tickCh <- now.Add(cfg.RetryInterval.Value() + time.Minute)
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Len(t, stats.Failed, 1)
+
+	tickCh <- now.Add(10 * cfg.RetryInterval.Value())
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.Failed)
+	require.Len(t, handler.Messages(), 2)
+}
+
+func defaultConfig(t *testing.T) codersdk.NotificationsConfig {
+	t.Helper()
+
+	var cfg codersdk.NotificationsConfig
+	require.NoError(t, cfg.Method.Set(string(database.NotificationMethodWebhook)))
+	require.NoError(t, cfg.Webhook.Endpoint.Set("http://localhost"))
+	cfg.MaxSendAttempts = 5
+	cfg.RetryInterval = clibase.Duration(5 * time.Minute)
+	cfg.DispatchTimeout = clibase.Duration(time.Minute)
+	cfg.LeasePeriod = clibase.Duration(2 * time.Minute)
+	cfg.LeaseCount = 20
+	return cfg
+}
+
+type fakeHandler struct {
+	err error
+
+	mu       sync.Mutex
+	messages []dispatch.Message
+}
+
+func (h *fakeHandler) Dispatch(_ context.Context, msg dispatch.Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, msg)
+	return h.err
+}
+
+func (h *fakeHandler) Messages() []dispatch.Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]dispatch.Message{}, h.messages...)
+}
@@ -0,0 +1,148 @@
+// Package notifications queues and delivers notifications about events
+// affecting users' workspaces, such as an imminent autostop or a pending
+// deletion.
+//
+// Notifications are enqueued into the database by an Enqueuer and delivered
+// in the background by a Manager, which retries failed deliveries until
+// they exceed the configured number of attempts.
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Enqueuer queues notifications for delivery to users.
+type Enqueuer interface {
+	// Enqueue queues a notification about event for the given user. Labels
+	// are used to render the event's template. Notifications which share a
+	// non-empty dedupeKey are only ever enqueued once.
+	Enqueue(ctx context.Context, userID uuid.UUID, event database.NotificationEvent, labels map[string]string, dedupeKey string) error
+}
+
+// NewNoopEnqueuer returns an Enqueuer which discards all notifications. It
+// is used when notifications are not configured.
+func NewNoopEnqueuer() Enqueuer {
+	return noopEnqueuer{}
+}
+
+type noopEnqueuer struct{}
+
+func (noopEnqueuer) Enqueue(context.Context, uuid.UUID, database.NotificationEvent, map[string]string, string) error {
+	return nil
+}
+
+// StoreEnqueuer persists notifications to the database to be picked up by a
+// Manager.
+type StoreEnqueuer struct {
+	store  database.Store
+	method database.NotificationMethod
+	log    slog.Logger
+}
+
+// NewStoreEnqueuer returns an Enqueuer which persists notifications to be
+// delivered using the method configured in cfg.
+func NewStoreEnqueuer(cfg codersdk.NotificationsConfig, store database.Store, log slog.Logger) (*StoreEnqueuer, error) {
+	method := database.NotificationMethod(cfg.Method.String())
+	if !method.Valid() {
+		return nil, xerrors.Errorf("invalid notification method %q, must be one of %v", method, database.AllNotificationMethodValues())
+	}
+	return &StoreEnqueuer{
+		store:  store,
+		method: method,
+		log:    log.Named("notifications_enqueuer"),
+	}, nil
+}
+
+// Enqueue implements Enqueuer. The context must be authorized to create
+// system resources.
+func (s *StoreEnqueuer) Enqueue(ctx context.Context, userID uuid.UUID, event database.NotificationEvent, labels map[string]string, dedupeKey string) error {
+	if !event.Valid() {
+		return xerrors.Errorf("invalid notification event %q", event)
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	now := dbtime.Now()
+	err := s.store.EnqueueNotificationMessage(ctx, database.EnqueueNotificationMessageParams{
+		ID:      uuid.New(),
+		Event:   event,
+		UserID:  userID,
+		Method:  s.method,
+		Payload: labels,
+		DedupeKey: sql.NullString{
+			String: dedupeKey,
+			Valid:  dedupeKey != "",
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return xerrors.Errorf("enqueue notification: %w", err)
+	}
+	s.log.Debug(ctx, "enqueued notification",
+		slog.F("user_id", userID),
+		slog.F("event", event),
+		slog.F("dedupe_key", dedupeKey),
+	)
+	return nil
+}
+
+// NewMockEnqueuer returns an Enqueuer which records notifications in memory.
+// It is intended for use in tests.
+func NewMockEnqueuer() *MockEnqueuer {
+	return &MockEnqueuer{}
+}
+
+// MockNotification is a notification recorded by MockEnqueuer.
+type MockNotification struct {
+	UserID    uuid.UUID
+	Event     database.NotificationEvent
+	Labels    map[string]string
+	DedupeKey string
+}
+
+type MockEnqueuer struct {
+	mutex         sync.Mutex
+	notifications []MockNotification
+}
+
+// Enqueue implements Enqueuer. Like StoreEnqueuer, notifications sharing a
+// non-empty dedupe key are only recorded once.
+func (m *MockEnqueuer) Enqueue(_ context.Context, userID uuid.UUID, event database.NotificationEvent, labels map[string]string, dedupeKey string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if dedupeKey != "" {
+		for _, n := range m.notifications {
+			if n.DedupeKey == dedupeKey {
+				return nil
+			}
+		}
+	}
+	m.notifications = append(m.notifications, MockNotification{
+		UserID:    userID,
+		Event:     event,
+		Labels:    labels,
+		DedupeKey: dedupeKey,
+	})
+	return nil
+}
+
+// Notifications returns the notifications recorded so far.
+func (m *MockEnqueuer) Notifications() []MockNotification {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	notifications := make([]MockNotification, len(m.notifications))
+	copy(notifications, m.notifications)
+	return notifications
+}
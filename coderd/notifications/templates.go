@@ -0,0 +1,111 @@
+package notifications
+
+import (
+	"strings"
+	"text/template"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// Labels which may be set on notifications.
+const (
+	LabelWorkspaceName = "workspace_name"
+	LabelDeadline      = "deadline"
+	LabelDeletingAt    = "deleting_at"
+	LabelTransition    = "transition"
+	LabelReason        = "reason"
+)
+
+// eventTemplate is the title and body of an event's notification.
+type eventTemplate struct {
+	title string
+	body  string
+}
+
+// templates are the compiled-in templates used to render each event. Every
+// event must have a template.
+var templates = map[database.NotificationEvent]eventTemplate{
+	database.NotificationEventWorkspaceAutostopImminent: {
+		title: `Workspace "{{.Labels.workspace_name}}" will stop soon`,
+		body: `Hi {{.Username}},
+
+Your workspace {{.Labels.workspace_name}} is scheduled to stop automatically at {{.Labels.deadline}}.
+
+If you are still using it, any activity will extend its deadline. You can also extend it from the dashboard:
+{{.WorkspaceURL}}`,
+	},
+	database.NotificationEventWorkspaceDormant: {
+		title: `Workspace "{{.Labels.workspace_name}}" was marked as dormant`,
+		body: `Hi {{.Username}},
+
+Your workspace {{.Labels.workspace_name}} was marked as dormant because it has not been used recently.
+{{- if .Labels.deleting_at}}
+
+It will be deleted at {{.Labels.deleting_at}} unless it is activated before then.
+{{- end}}
+
+To keep using it, activate it from the dashboard:
+{{.WorkspaceURL}}`,
+	},
+	database.NotificationEventWorkspaceAutodeletePending: {
+		title: `Workspace "{{.Labels.workspace_name}}" will be deleted soon`,
+		body: `Hi {{.Username}},
+
+Your dormant workspace {{.Labels.workspace_name}} is scheduled to be deleted automatically at {{.Labels.deleting_at}}.
+
+To keep it, activate it from the dashboard before then:
+{{.WorkspaceURL}}`,
+	},
+	database.NotificationEventWorkspaceAutobuildFailed: {
+		title: `Automatic {{.Labels.transition}} of workspace "{{.Labels.workspace_name}}" failed`,
+		body: `Hi {{.Username}},
+
+An automatic {{.Labels.transition}} of your workspace {{.Labels.workspace_name}} failed.
+{{- if .Labels.reason}}
+
+The build failed with the following error:
+{{.Labels.reason}}
+{{- end}}
+
+View the build logs on the dashboard:
+{{.WorkspaceURL}}`,
+	},
+}
+
+// templateData is passed to event templates when rendering.
+type templateData struct {
+	Username     string
+	WorkspaceURL string
+	Labels       map[string]string
+}
+
+// render renders the title and body of a notification for the given event.
+func render(event database.NotificationEvent, data templateData) (title string, body string, err error) {
+	tmpl, ok := templates[event]
+	if !ok {
+		return "", "", xerrors.Errorf("no template for event %q", event)
+	}
+	title, err = execute(tmpl.title, data)
+	if err != nil {
+		return "", "", xerrors.Errorf("render title: %w", err)
+	}
+	body, err = execute(tmpl.body, data)
+	if err != nil {
+		return "", "", xerrors.Errorf("render body: %w", err)
+	}
+	return title, body, nil
+}
+
+func execute(text string, data templateData) (string, error) {
+	tmpl, err := template.New("").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", xerrors.Errorf("parse: %w", err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", xerrors.Errorf("execute: %w", err)
+	}
+	return sb.String(), nil
+}
@@ -121,6 +121,7 @@ func TestWorkspaces(t *testing.T) {
 			Provisioner:   database.ProvisionerTypeEcho,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 			Type:          database.ProvisionerJobTypeWorkspaceBuild,
+			Priority:      database.ProvisionerJobPriorityUser,
 		})
 		require.NoError(t, err)
 		err = db.InsertWorkspaceBuild(context.Background(), database.InsertWorkspaceBuildParams{
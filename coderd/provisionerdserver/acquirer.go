@@ -40,12 +40,18 @@ const (
 // As a backup to pubsub notifications, each domain is allowed to query periodically once every 30s.
 // This ensures jobs are not stuck permanently if the service that created them fails to publish
 // (e.g. a crash).
+//
+// When concurrency limits are set, jobs can be held back even though they are acceptable to a
+// domain, so every domain is also notified whenever a running job completes.
 type Acquirer struct {
 	ctx    context.Context
 	logger slog.Logger
 	store  AcquirerStore
 	ps     pubsub.Pubsub
 
+	maxConcurrentPerUser     int32
+	maxConcurrentPerTemplate int32
+
 	mu sync.Mutex
 	q  map[dKey]domain
 
@@ -61,9 +67,19 @@ func TestingBackupPollDuration(dur time.Duration) AcquirerOption {
 	}
 }
 
+// WithConcurrencyLimits limits the number of jobs that may run at the same time for a single
+// initiator and for a single template. Values below 1 mean there is no limit.
+func WithConcurrencyLimits(perUser, perTemplate int32) AcquirerOption {
+	return func(a *Acquirer) {
+		a.maxConcurrentPerUser = perUser
+		a.maxConcurrentPerTemplate = perTemplate
+	}
+}
+
 // AcquirerStore is the subset of database.Store that the Acquirer needs
 type AcquirerStore interface {
 	AcquireProvisionerJob(context.Context, database.AcquireProvisionerJobParams) (database.ProvisionerJob, error)
+	InTx(func(database.Store) error, *sql.TxOptions) error
 }
 
 func NewAcquirer(ctx context.Context, logger slog.Logger, store AcquirerStore, ps pubsub.Pubsub,
@@ -119,7 +135,7 @@ func (a *Acquirer) AcquireJob(
 			return database.ProvisionerJob{}, err
 		case <-clearance:
 			logger.Debug(ctx, "got clearance to call database")
-			job, err := a.store.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			job, err := a.acquire(ctx, database.AcquireProvisionerJobParams{
 				StartedAt: sql.NullTime{
 					Time:  dbtime.Now(),
 					Valid: true,
@@ -128,8 +144,10 @@ func (a *Acquirer) AcquireJob(
 					UUID:  worker,
 					Valid: true,
 				},
-				Types: pt,
-				Tags:  dbTags,
+				Types:                    pt,
+				Tags:                     dbTags,
+				MaxConcurrentPerUser:     a.maxConcurrentPerUser,
+				MaxConcurrentPerTemplate: a.maxConcurrentPerTemplate,
 			})
 			if xerrors.Is(err, sql.ErrNoRows) {
 				logger.Debug(ctx, "no job available")
@@ -151,6 +169,34 @@ func (a *Acquirer) AcquireJob(
 	}
 }
 
+// acquire attempts to acquire a job from the store. Without concurrency limits
+// a single AcquireProvisionerJob call is safe because SKIP LOCKED stops two
+// callers from taking the same job. With limits, two callers (possibly on
+// different replicas) could each count the running jobs before either commits,
+// and both start a job that only one of them was allowed to. So the count and
+// the update run in a transaction that holds an advisory lock.
+//
+// The lock is a single deployment-wide lock, not one per organization or tag
+// set: a user's or template's running jobs are counted across every tag set,
+// so any two capped acquisitions may race. This serializes all capped
+// acquisitions across every replica, trading acquisition throughput for
+// correct limits. Deployments without limits never take the lock.
+func (a *Acquirer) acquire(ctx context.Context, params database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
+	if !a.hasConcurrencyLimits() {
+		return a.store.AcquireProvisionerJob(ctx, params)
+	}
+	var job database.ProvisionerJob
+	err := a.store.InTx(func(tx database.Store) error {
+		err := tx.AcquireLock(ctx, database.LockIDAcquireProvisionerJob)
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		job, err = tx.AcquireProvisionerJob(ctx, params)
+		return err
+	}, nil)
+	return job, err
+}
+
 // want signals that an acquiree wants clearance to query for a job with the given dKey.
 func (a *Acquirer) want(pt []database.ProvisionerType, tags Tags, clearance chan<- struct{}) {
 	dk := domainKey(pt, tags)
@@ -300,6 +346,19 @@ func (a *Acquirer) subscribe() {
 				a.logger.Warn(a.ctx, "failed to subscribe to job postings", slog.Error(err))
 				return err
 			}
+			if a.hasConcurrencyLimits() {
+				cancelCompleted, err := a.ps.SubscribeWithErr(provisionerjobs.EventJobCompleted, a.jobCompleted)
+				if err != nil {
+					cancelFn()
+					a.logger.Warn(a.ctx, "failed to subscribe to job completions", slog.Error(err))
+					return err
+				}
+				cancel = func() {
+					cancelFn()
+					cancelCompleted()
+				}
+				return nil
+			}
 			cancel = cancelFn
 			return nil
 		}, bkoff)
@@ -355,6 +414,20 @@ func (a *Acquirer) jobPosted(ctx context.Context, message []byte, err error) {
 	}
 }
 
+func (a *Acquirer) hasConcurrencyLimits() bool {
+	return a.maxConcurrentPerUser >= 1 || a.maxConcurrentPerTemplate >= 1
+}
+
+// jobCompleted wakes every domain, since any of them may have jobs that were held back by the
+// concurrency limits of the initiator or template of the completed job.
+func (a *Acquirer) jobCompleted(_ context.Context, _ []byte, err error) {
+	if err != nil && !xerrors.Is(err, pubsub.ErrDroppedMessages) {
+		a.logger.Warn(a.ctx, "unhandled pubsub error", slog.Error(err))
+		return
+	}
+	a.clearOrPendAll()
+}
+
 func (a *Acquirer) clearOrPendAll() {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -13,11 +13,15 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
@@ -315,6 +319,151 @@ func TestAcquirer_UnblockOnCancel(t *testing.T) {
 	require.Equal(t, jobID, job.ID)
 }
 
+// TestAcquirer_Priority tests that jobs are acquired by priority first, then by the number of
+// running jobs of their initiator, and then by age.
+func TestAcquirer_Priority(t *testing.T) {
+	t.Parallel()
+	db := dbmem.New()
+	ps := pubsub.NewInMemory()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	uut := provisionerdserver.NewAcquirer(ctx, logger.Named("acquirer"), db, ps)
+
+	busyUser := uuid.New()
+	otherUser := uuid.New()
+	// busyUser already has a job running.
+	dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		InitiatorID: busyUser,
+		StartedAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+
+	now := dbtime.Now()
+	insert := func(initiator uuid.UUID, priority database.ProvisionerJobPriority, age time.Duration) uuid.UUID {
+		return dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			CreatedAt:   now.Add(-age),
+			InitiatorID: initiator,
+			Priority:    priority,
+			Tags:        database.StringMap{},
+		}).ID
+	}
+	batch := insert(otherUser, database.ProvisionerJobPriorityBatch, 5*time.Minute)
+	dryRun := insert(otherUser, database.ProvisionerJobPriorityDryRun, 4*time.Minute)
+	busyUserJob := insert(busyUser, database.ProvisionerJobPriorityUser, 3*time.Minute)
+	autobuild := insert(otherUser, database.ProvisionerJobPriorityAutobuild, 2*time.Minute)
+	otherUserJob := insert(otherUser, database.ProvisionerJobPriorityUser, time.Minute)
+
+	pt := []database.ProvisionerType{database.ProvisionerTypeEcho}
+	for _, expected := range []uuid.UUID{otherUserJob, busyUserJob, autobuild, dryRun, batch} {
+		acquiree := newTestAcquiree(t, uuid.New(), pt, provisionerdserver.Tags{})
+		acquiree.startAcquire(ctx, uut)
+		job := acquiree.success(ctx)
+		require.Equal(t, expected, job.ID)
+	}
+}
+
+// TestAcquirer_ConcurrencyLimits tests that jobs are held back while their initiator has too many
+// running jobs, and are acquired once one of those jobs completes.
+func TestAcquirer_ConcurrencyLimits(t *testing.T) {
+	t.Parallel()
+	db := dbmem.New()
+	ps := pubsub.NewInMemory()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	uut := provisionerdserver.NewAcquirer(ctx, logger.Named("acquirer"), db, ps,
+		provisionerdserver.WithConcurrencyLimits(1, 0))
+
+	user := uuid.New()
+	running := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		InitiatorID: user,
+		StartedAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	queued := dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+		InitiatorID: user,
+		Tags:        database.StringMap{},
+	})
+
+	pt := []database.ProvisionerType{database.ProvisionerTypeEcho}
+	acquiree := newTestAcquiree(t, uuid.New(), pt, provisionerdserver.Tags{})
+	acquiree.startAcquire(ctx, uut)
+	// Give the acquirer a chance to query for the job before checking
+	// that it's held back.
+	time.Sleep(testutil.IntervalMedium)
+	acquiree.requireBlocked()
+
+	err := db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+		ID:          running.ID,
+		UpdatedAt:   dbtime.Now(),
+		CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	require.NoError(t, err)
+	err = provisionerjobs.CompleteJob(ps)
+	require.NoError(t, err)
+
+	job := acquiree.success(ctx)
+	require.Equal(t, queued.ID, job.ID)
+}
+
+// TestAcquirer_ConcurrencyLimitsParallel tests that acquirers racing on
+// separate replicas never start more jobs than the limit allows.
+func TestAcquirer_ConcurrencyLimitsParallel(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("the race only exists with real postgres")
+	}
+	db, ps := dbtestutil.NewDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+
+	const replicas = 5
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := uuid.New()
+	for i := 0; i < replicas; i++ {
+		dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			InitiatorID:    user,
+			Tags:           database.StringMap{},
+		})
+	}
+
+	acquireCtx, acquireCancel := context.WithCancel(ctx)
+	defer acquireCancel()
+	pt := []database.ProvisionerType{database.ProvisionerTypeEcho}
+	acquired := make(chan error, replicas)
+	for i := 0; i < replicas; i++ {
+		uut := provisionerdserver.NewAcquirer(ctx, logger.Named("acquirer"), db, ps,
+			provisionerdserver.WithConcurrencyLimits(1, 0))
+		go func() {
+			_, err := uut.AcquireJob(acquireCtx, uuid.New(), pt, provisionerdserver.Tags{})
+			acquired <- err
+		}()
+	}
+
+	// Wait for the first job to start, and give the other acquirers a chance
+	// to wrongly start another before stopping them.
+	var started int
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for a job to be acquired")
+	case err := <-acquired:
+		require.NoError(t, err)
+		started++
+	}
+	time.Sleep(testutil.IntervalMedium)
+	acquireCancel()
+	for i := 1; i < replicas; i++ {
+		err := <-acquired
+		if err == nil {
+			started++
+			continue
+		}
+		require.ErrorIs(t, err, context.Canceled)
+	}
+	require.Equal(t, 1, started)
+}
+
 func postJob(t *testing.T, ps pubsub.Pubsub, pt database.ProvisionerType, tags provisionerdserver.Tags) {
 	t.Helper()
 	msg, err := json.Marshal(provisionerjobs.JobPosting{
@@ -374,6 +523,11 @@ func (s *fakeOrderedStore) AcquireProvisionerJob(
 	return job, err
 }
 
+// InTx is only used for concurrency limits, which the fake doesn't support.
+func (*fakeOrderedStore) InTx(func(database.Store) error, *sql.TxOptions) error {
+	return xerrors.New("not implemented")
+}
+
 func (s *fakeOrderedStore) sendCtx(ctx context.Context, job database.ProvisionerJob, err error) error {
 	select {
 	case <-ctx.Done():
@@ -441,6 +595,11 @@ jobLoop:
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+// InTx is only used for concurrency limits, which the fake doesn't support.
+func (*fakeTaggedStore) InTx(func(database.Store) error, *sql.TxOptions) error {
+	return xerrors.New("not implemented")
+}
+
 // testAcquiree is a helper type that handles asynchronously calling AcquireJob
 // and asserting whether or not it returns, blocks, or is canceled.
 type testAcquiree struct {
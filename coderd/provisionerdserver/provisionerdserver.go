@@ -30,9 +30,11 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
@@ -56,6 +58,10 @@ type Options struct {
 
 	// AcquireJobLongPollDur is used in tests
 	AcquireJobLongPollDur time.Duration
+
+	// NotificationsEnqueuer is used to tell workspace owners about failed
+	// automatic builds. Defaults to a no-op.
+	NotificationsEnqueuer notifications.Enqueuer
 }
 
 type server struct {
@@ -79,6 +85,7 @@ type server struct {
 	TemplateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	DeploymentValues            *codersdk.DeploymentValues
+	NotificationsEnqueuer       notifications.Enqueuer
 
 	OIDCConfig httpmw.OAuth2Config
 
@@ -161,6 +168,9 @@ func NewServer(
 	if options.AcquireJobLongPollDur == 0 {
 		options.AcquireJobLongPollDur = DefaultAcquireJobLongPollDur
 	}
+	if options.NotificationsEnqueuer == nil {
+		options.NotificationsEnqueuer = notifications.NewNoopEnqueuer()
+	}
 	return &server{
 		lifecycleCtx:                lifecycleCtx,
 		AccessURL:                   accessURL,
@@ -179,6 +189,7 @@ func NewServer(
 		TemplateScheduleStore:       templateScheduleStore,
 		UserQuietHoursScheduleStore: userQuietHoursScheduleStore,
 		DeploymentValues:            deploymentValues,
+		NotificationsEnqueuer:       options.NotificationsEnqueuer,
 		OIDCConfig:                  options.OIDCConfig,
 		TimeNowFn:                   options.TimeNowFn,
 		acquireJobLongPollDur:       options.AcquireJobLongPollDur,
@@ -398,7 +409,7 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		var sessionToken string
 		switch workspaceBuild.Transition {
 		case database.WorkspaceTransitionStart:
-			sessionToken, err = s.regenerateSessionToken(ctx, owner, workspace)
+			sessionToken, err = s.regenerateSessionToken(ctx, owner, workspaceSessionTokenName(workspace))
 			if err != nil {
 				return nil, failJob(fmt.Sprintf("regenerate session token: %s", err))
 			}
@@ -502,16 +513,75 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 			return nil, failJob(fmt.Sprintf("get template version variables: %s", err))
 		}
 
-		protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
-			TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
-				RichParameterValues: convertRichParameterValues(input.RichParameterValues),
-				VariableValues:      asVariableValues(templateVariables),
-				Metadata: &sdkproto.Metadata{
-					CoderUrl:      s.AccessURL.String(),
-					WorkspaceName: input.WorkspaceName,
-				},
+		dryRun := &proto.AcquiredJob_TemplateDryRun{
+			RichParameterValues: convertRichParameterValues(input.RichParameterValues),
+			VariableValues:      asVariableValues(templateVariables),
+			Metadata: &sdkproto.Metadata{
+				CoderUrl:      s.AccessURL.String(),
+				WorkspaceName: input.WorkspaceName,
 			},
 		}
+		if input.WorkspaceID.Valid {
+			// Plan against the state of the workspace, as if it was
+			// updated to the template version.
+			workspace, err := s.Database.GetWorkspaceByID(ctx, input.WorkspaceID.UUID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get workspace: %s", err))
+			}
+			latestBuild, err := s.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get latest workspace build: %s", err))
+			}
+			owner, err := s.Database.GetUserByID(ctx, workspace.OwnerID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get owner: %s", err))
+			}
+			template, err := s.Database.GetTemplateByID(ctx, workspace.TemplateID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get template: %s", err))
+			}
+
+			var workspaceOwnerOIDCAccessToken string
+			if s.OIDCConfig != nil {
+				workspaceOwnerOIDCAccessToken, err = obtainOIDCAccessToken(ctx, s.Database, s.OIDCConfig, owner.ID)
+				if err != nil {
+					return nil, failJob(fmt.Sprintf("obtain OIDC access token: %s", err))
+				}
+			}
+
+			// The dry-run gets its own token so previewing an update
+			// doesn't invalidate the one the running workspace uses.
+			sessionToken, err := s.regenerateSessionToken(ctx, owner, workspaceDryRunSessionTokenName(workspace))
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("regenerate session token: %s", err))
+			}
+
+			// Updating a workspace starts it, so plan the start transition.
+			transition, err := convertWorkspaceTransition(database.WorkspaceTransitionStart)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("convert workspace transition: %s", err))
+			}
+
+			dryRun.State = latestBuild.ProvisionerState
+			dryRun.Metadata = &sdkproto.Metadata{
+				CoderUrl:                      s.AccessURL.String(),
+				WorkspaceTransition:           transition,
+				WorkspaceName:                 workspace.Name,
+				WorkspaceOwner:                owner.Username,
+				WorkspaceOwnerEmail:           owner.Email,
+				WorkspaceOwnerOidcAccessToken: workspaceOwnerOIDCAccessToken,
+				WorkspaceId:                   workspace.ID.String(),
+				WorkspaceOwnerId:              owner.ID.String(),
+				TemplateId:                    template.ID.String(),
+				TemplateName:                  template.Name,
+				TemplateVersion:               templateVersion.Name,
+				WorkspaceOwnerSessionToken:    sessionToken,
+			}
+		}
+
+		protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
+			TemplateDryRun: dryRun,
+		}
 	case database.ProvisionerJobTypeTemplateVersionImport:
 		var input TemplateVersionImportJob
 		err = json.Unmarshal(job.Input, &input)
@@ -602,6 +672,32 @@ func (s *server) includeLastVariableValues(ctx context.Context, templateVersionI
 	return values, nil
 }
 
+// activateTemplateVersion makes a freshly imported version the active version
+// of its template. Versions that have since been archived, or that aren't
+// attached to a template, are left alone.
+func (s *server) activateTemplateVersion(ctx context.Context, templateVersionID uuid.UUID) error {
+	templateVersion, err := s.Database.GetTemplateVersionByID(ctx, templateVersionID)
+	if err != nil {
+		return xerrors.Errorf("get template version: %w", err)
+	}
+	if !templateVersion.TemplateID.Valid || templateVersion.Archived {
+		return nil
+	}
+
+	err = s.Database.UpdateTemplateActiveVersionByID(ctx, database.UpdateTemplateActiveVersionByIDParams{
+		ID:              templateVersion.TemplateID.UUID,
+		ActiveVersionID: templateVersion.ID,
+		UpdatedAt:       dbtime.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("update active version: %w", err)
+	}
+	s.Logger.Info(ctx, "promoted template version to active",
+		slog.F("template_id", templateVersion.TemplateID.UUID),
+		slog.F("template_version_id", templateVersion.ID))
+	return nil
+}
+
 func (s *server) CommitQuota(ctx context.Context, request *proto.CommitQuotaRequest) (*proto.CommitQuotaResponse, error) {
 	ctx, span := s.startTrace(ctx, tracing.FuncName())
 	defer span.End()
@@ -763,6 +859,7 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 				Required:          templateVariable.Required,
 				Sensitive:         templateVariable.Sensitive,
 				Value:             value,
+				ValueKeyID:        sql.NullString{}, // set by dbcrypt if required
 			})
 			if err != nil {
 				return nil, xerrors.Errorf("insert parameter schema: %w", err)
@@ -849,9 +946,10 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 
 			if jobType.WorkspaceBuild.State != nil {
 				err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
-					ID:               input.WorkspaceBuildID,
-					UpdatedAt:        dbtime.Now(),
-					ProvisionerState: jobType.WorkspaceBuild.State,
+					ID:                    input.WorkspaceBuildID,
+					UpdatedAt:             dbtime.Now(),
+					ProvisionerState:      jobType.WorkspaceBuild.State,
+					ProvisionerStateKeyID: sql.NullString{}, // set by dbcrypt if required
 				})
 				if err != nil {
 					return xerrors.Errorf("update workspace build state: %w", err)
@@ -929,10 +1027,17 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 					Status:           http.StatusInternalServerError,
 					AdditionalFields: wriBytes,
 				})
+
+				s.notifyWorkspaceBuildFailed(ctx, workspace, build, job)
 			}
 		}
 	}
 
+	err = provisionerjobs.CompleteJob(s.Pubsub)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to publish job completion", slog.F("job_id", jobID), slog.Error(err))
+	}
+
 	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
 	if err != nil {
 		return nil, xerrors.Errorf("marshal job log: %w", err)
@@ -945,6 +1050,28 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 	return &proto.Empty{}, nil
 }
 
+// notifyWorkspaceBuildFailed tells the workspace owner that an automatic build
+// of their workspace failed. Failures of builds started by a user are not
+// notified since the user is expected to be watching the build.
+func (s *server) notifyWorkspaceBuildFailed(ctx context.Context, workspace database.Workspace, build database.WorkspaceBuild, job database.ProvisionerJob) {
+	if build.Reason == database.BuildReasonInitiator {
+		return
+	}
+
+	err := s.NotificationsEnqueuer.Enqueue(ctx, workspace.OwnerID, database.NotificationEventWorkspaceAutobuildFailed, map[string]string{
+		notifications.LabelWorkspaceName: workspace.Name,
+		notifications.LabelTransition:    string(build.Transition),
+		notifications.LabelReason:        job.Error.String,
+	}, fmt.Sprintf("%s:%s", database.NotificationEventWorkspaceAutobuildFailed, build.ID))
+	if err != nil {
+		s.Logger.Error(ctx, "failed to enqueue failed autobuild notification",
+			slog.F("workspace_id", workspace.ID),
+			slog.F("build_id", build.ID),
+			slog.Error(err),
+		)
+	}
+}
+
 // CompleteJob is triggered by a provision daemon to mark a provisioner job as completed.
 func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob) (*proto.Empty, error) {
 	ctx, span := s.startTrace(ctx, tracing.FuncName())
@@ -1090,6 +1217,13 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 		if err != nil {
 			return nil, xerrors.Errorf("complete job: %w", err)
 		}
+
+		if input.ActivateOnSuccess && !completedError.Valid {
+			err = s.activateTemplateVersion(ctx, input.TemplateVersionID)
+			if err != nil {
+				return nil, xerrors.Errorf("activate template version: %w", err)
+			}
+		}
 	case *proto.CompletedJob_WorkspaceBuild_:
 		var input WorkspaceProvisionJob
 		err = json.Unmarshal(job.Input, &input)
@@ -1145,9 +1279,10 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 				return xerrors.Errorf("update provisioner job: %w", err)
 			}
 			err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
-				ID:               workspaceBuild.ID,
-				ProvisionerState: jobType.WorkspaceBuild.State,
-				UpdatedAt:        now,
+				ID:                    workspaceBuild.ID,
+				ProvisionerState:      jobType.WorkspaceBuild.State,
+				ProvisionerStateKeyID: sql.NullString{}, // set by dbcrypt if required
+				UpdatedAt:             now,
 			})
 			if err != nil {
 				return xerrors.Errorf("update workspace build provisioner state: %w", err)
@@ -1175,6 +1310,32 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 				}
 			}
 
+			if len(jobType.WorkspaceBuild.Timings) > 0 {
+				timings := database.InsertProvisionerJobTimingsParams{
+					JobID: jobID,
+				}
+				for _, timing := range jobType.WorkspaceBuild.Timings {
+					stage := database.ProvisionerJobTimingStage(timing.Stage)
+					if !stage.Valid() {
+						s.Logger.Warn(ctx, "ignoring timing with unknown stage",
+							slog.F("job_id", jobID),
+							slog.F("stage", timing.Stage),
+						)
+						continue
+					}
+					timings.StartedAt = append(timings.StartedAt, time.UnixMilli(timing.StartedAt))
+					timings.EndedAt = append(timings.EndedAt, time.UnixMilli(timing.EndedAt))
+					timings.Stage = append(timings.Stage, stage)
+					timings.Source = append(timings.Source, timing.Source)
+					timings.Action = append(timings.Action, timing.Action)
+					timings.Resource = append(timings.Resource, timing.Resource)
+				}
+				_, err = db.InsertProvisionerJobTimings(ctx, timings)
+				if err != nil {
+					return xerrors.Errorf("insert provisioner job timings: %w", err)
+				}
+			}
+
 			// On start, we want to ensure that workspace agents timeout statuses
 			// are propagated. This method is simple and does not protect against
 			// notifying in edge cases like when a workspace is stopped soon
@@ -1305,6 +1466,26 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			}
 		}
 
+		if changes := jobType.TemplateDryRun.ResourceChanges; len(changes) > 0 {
+			params := database.InsertProvisionerJobResourceChangesParams{
+				JobID: jobID,
+			}
+			for _, change := range changes {
+				action := database.ProvisionerJobResourceChangeAction(change.Action)
+				if !action.Valid() {
+					return nil, xerrors.Errorf("invalid resource change action %q", change.Action)
+				}
+				params.Address = append(params.Address, change.Address)
+				params.Type = append(params.Type, change.Type)
+				params.Name = append(params.Name, change.Name)
+				params.Action = append(params.Action, action)
+			}
+			_, err = s.Database.InsertProvisionerJobResourceChanges(ctx, params)
+			if err != nil {
+				return nil, xerrors.Errorf("insert resource changes: %w", err)
+			}
+		}
+
 		err = s.Database.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
 			ID:        jobID,
 			UpdatedAt: dbtime.Now(),
@@ -1331,6 +1512,11 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			reflect.TypeOf(completed.Type).String())
 	}
 
+	err = provisionerjobs.CompleteJob(s.Pubsub)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to publish job completion", slog.F("job_id", jobID), slog.Error(err))
+	}
+
 	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{EndOfLogs: true})
 	if err != nil {
 		return nil, xerrors.Errorf("marshal job log: %w", err)
@@ -1598,12 +1784,16 @@ func workspaceSessionTokenName(workspace database.Workspace) string {
 	return fmt.Sprintf("%s_%s_session_token", workspace.OwnerID, workspace.ID)
 }
 
-func (s *server) regenerateSessionToken(ctx context.Context, user database.User, workspace database.Workspace) (string, error) {
+func workspaceDryRunSessionTokenName(workspace database.Workspace) string {
+	return fmt.Sprintf("%s_%s_dry_run_session_token", workspace.OwnerID, workspace.ID)
+}
+
+func (s *server) regenerateSessionToken(ctx context.Context, user database.User, tokenName string) (string, error) {
 	newkey, sessionToken, err := apikey.Generate(apikey.CreateParams{
 		UserID:           user.ID,
 		LoginType:        user.LoginType,
 		DeploymentValues: s.DeploymentValues,
-		TokenName:        workspaceSessionTokenName(workspace),
+		TokenName:        tokenName,
 		LifetimeSeconds:  int64(s.DeploymentValues.MaxTokenLifetime.Value().Seconds()),
 	})
 	if err != nil {
@@ -1611,7 +1801,7 @@ func (s *server) regenerateSessionToken(ctx context.Context, user database.User,
 	}
 
 	err = s.Database.InTx(func(tx database.Store) error {
-		err := deleteSessionToken(ctx, tx, workspace)
+		err := deleteAPIKeyByName(ctx, tx, user.ID, tokenName)
 		if err != nil {
 			return xerrors.Errorf("delete session token: %w", err)
 		}
@@ -1629,20 +1819,19 @@ func (s *server) regenerateSessionToken(ctx context.Context, user database.User,
 	return sessionToken, nil
 }
 
+// deleteSessionToken deletes the workspace session token, along with any
+// token minted for a dry-run against the workspace.
 func deleteSessionToken(ctx context.Context, db database.Store, workspace database.Workspace) error {
 	err := db.InTx(func(tx database.Store) error {
-		key, err := tx.GetAPIKeyByName(ctx, database.GetAPIKeyByNameParams{
-			UserID:    workspace.OwnerID,
-			TokenName: workspaceSessionTokenName(workspace),
-		})
-		if err == nil {
-			err = tx.DeleteAPIKeyByID(ctx, key.ID)
-		}
-
-		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
-			return xerrors.Errorf("get api key by name: %w", err)
+		for _, tokenName := range []string{
+			workspaceSessionTokenName(workspace),
+			workspaceDryRunSessionTokenName(workspace),
+		} {
+			err := deleteAPIKeyByName(ctx, tx, workspace.OwnerID, tokenName)
+			if err != nil {
+				return err
+			}
 		}
-
 		return nil
 	}, nil)
 	if err != nil {
@@ -1652,6 +1841,22 @@ func deleteSessionToken(ctx context.Context, db database.Store, workspace databa
 	return nil
 }
 
+func deleteAPIKeyByName(ctx context.Context, db database.Store, userID uuid.UUID, tokenName string) error {
+	key, err := db.GetAPIKeyByName(ctx, database.GetAPIKeyByNameParams{
+		UserID:    userID,
+		TokenName: tokenName,
+	})
+	if err == nil {
+		err = db.DeleteAPIKeyByID(ctx, key.ID)
+	}
+
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return xerrors.Errorf("get api key by name: %w", err)
+	}
+
+	return nil
+}
+
 // obtainOIDCAccessToken returns a valid OpenID Connect access token
 // for the user if it's able to obtain one, otherwise it returns an empty string.
 func obtainOIDCAccessToken(ctx context.Context, db database.Store, oidcConfig httpmw.OAuth2Config, userID uuid.UUID) (string, error) {
@@ -1780,6 +1985,10 @@ func auditActionFromTransition(transition database.WorkspaceTransition) database
 type TemplateVersionImportJob struct {
 	TemplateVersionID  uuid.UUID                `json:"template_version_id"`
 	UserVariableValues []codersdk.VariableValue `json:"user_variable_values"`
+	// ActivateOnSuccess promotes the version to the active version of its
+	// template once the import succeeds. It's set for versions created from
+	// a git source with auto promotion enabled.
+	ActivateOnSuccess bool `json:"activate_on_success,omitempty"`
 }
 
 // WorkspaceProvisionJob is the payload for the "workspace_provision" job type.
@@ -1794,6 +2003,9 @@ type TemplateVersionDryRunJob struct {
 	TemplateVersionID   uuid.UUID                          `json:"template_version_id"`
 	WorkspaceName       string                             `json:"workspace_name"`
 	RichParameterValues []database.WorkspaceBuildParameter `json:"rich_parameter_values"`
+	// WorkspaceID is set when the dry-run is planned against the state of
+	// an existing workspace, e.g. to preview an update.
+	WorkspaceID uuid.NullUUID `json:"workspace_id"`
 }
 
 func asVariableValues(templateVariables []database.TemplateVersionVariable) []*sdkproto.VariableValue {
@@ -129,6 +129,7 @@ func TestAcquireJob(t *testing.T) {
 				Provisioner:   database.ProvisionerTypeEcho,
 				StorageMethod: database.ProvisionerStorageMethodFile,
 				Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+				Priority:      database.ProvisionerJobPriorityDryRun,
 			})
 			require.NoError(t, err)
 			_, err = tc.acquire(ctx, srv)
@@ -394,6 +395,99 @@ func TestAcquireJob(t *testing.T) {
 			require.NoError(t, err)
 			require.JSONEq(t, string(want), string(got))
 		})
+		t.Run(tc.name+"_TemplateVersionDryRunWorkspace", func(t *testing.T) {
+			t.Parallel()
+			srv, db, ps := setup(t, false, nil)
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+			defer cancel()
+
+			user := dbgen.User(t, db, database.User{})
+			_ = dbgen.UserLink(t, db, database.UserLink{
+				LoginType:        database.LoginTypeOIDC,
+				UserID:           user.ID,
+				OAuthExpiry:      dbtime.Now().Add(time.Hour),
+				OAuthAccessToken: "access-token",
+			})
+			template := dbgen.Template(t, db, database.Template{
+				Name:        "template",
+				Provisioner: database.ProvisionerTypeEcho,
+			})
+			version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+				TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
+			})
+			workspace := dbgen.Workspace(t, db, database.Workspace{
+				TemplateID: template.ID,
+				OwnerID:    user.ID,
+			})
+			file := dbgen.File(t, db, database.File{CreatedBy: user.ID})
+			build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+				WorkspaceID:       workspace.ID,
+				BuildNumber:       1,
+				TemplateVersionID: version.ID,
+				Transition:        database.WorkspaceTransitionStart,
+				Reason:            database.BuildReasonInitiator,
+				ProvisionerState:  []byte("state"),
+			})
+			_ = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+				ID:            build.JobID,
+				InitiatorID:   user.ID,
+				Provisioner:   database.ProvisionerTypeEcho,
+				StorageMethod: database.ProvisionerStorageMethodFile,
+				FileID:        file.ID,
+				Type:          database.ProvisionerJobTypeWorkspaceBuild,
+				Input: must(json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+					WorkspaceBuildID: build.ID,
+				})),
+			})
+
+			job, err := tc.acquire(ctx, srv)
+			require.NoError(t, err)
+			buildJob, ok := job.Type.(*proto.AcquiredJob_WorkspaceBuild_)
+			require.True(t, ok, "acquired job not a workspace build?")
+			buildMetadata := buildJob.WorkspaceBuild.Metadata
+
+			_ = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+				InitiatorID:   user.ID,
+				Provisioner:   database.ProvisionerTypeEcho,
+				StorageMethod: database.ProvisionerStorageMethodFile,
+				FileID:        file.ID,
+				Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+				Input: must(json.Marshal(provisionerdserver.TemplateVersionDryRunJob{
+					TemplateVersionID: version.ID,
+					WorkspaceName:     workspace.Name,
+					WorkspaceID:       uuid.NullUUID{UUID: workspace.ID, Valid: true},
+				})),
+			})
+
+			job, err = tc.acquire(ctx, srv)
+			require.NoError(t, err)
+			dryRunJob, ok := job.Type.(*proto.AcquiredJob_TemplateDryRun_)
+			require.True(t, ok, "acquired job not a dry-run?")
+			dryRunMetadata := dryRunJob.TemplateDryRun.Metadata
+			require.Equal(t, []byte("state"), dryRunJob.TemplateDryRun.State)
+
+			// The dry-run gets a session token of its own, and the
+			// workspace's token must survive it.
+			require.NotEmpty(t, dryRunMetadata.WorkspaceOwnerSessionToken)
+			require.NotEqual(t, buildMetadata.WorkspaceOwnerSessionToken, dryRunMetadata.WorkspaceOwnerSessionToken)
+			for _, sessionToken := range []string{buildMetadata.WorkspaceOwnerSessionToken, dryRunMetadata.WorkspaceOwnerSessionToken} {
+				toks := strings.Split(sessionToken, "-")
+				require.Len(t, toks, 2, "invalid api key")
+				_, err = db.GetAPIKeyByID(ctx, toks[0])
+				require.NoError(t, err)
+			}
+
+			// Otherwise the dry-run plans with the same metadata as the
+			// build it previews.
+			require.Equal(t, "access-token", dryRunMetadata.WorkspaceOwnerOidcAccessToken)
+			buildMetadata.WorkspaceOwnerSessionToken = ""
+			dryRunMetadata.WorkspaceOwnerSessionToken = ""
+			want, err := json.Marshal(buildMetadata)
+			require.NoError(t, err)
+			got, err := json.Marshal(dryRunMetadata)
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+		})
 		t.Run(tc.name+"_TemplateVersionImport", func(t *testing.T) {
 			t.Parallel()
 			srv, db, ps := setup(t, false, nil)
@@ -495,6 +589,7 @@ func TestUpdateJob(t *testing.T) {
 			Provisioner:   database.ProvisionerTypeEcho,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 			Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+			Priority:      database.ProvisionerJobPriorityDryRun,
 		})
 		require.NoError(t, err)
 		_, err = srv.UpdateJob(ctx, &proto.UpdateJobRequest{
@@ -511,6 +606,7 @@ func TestUpdateJob(t *testing.T) {
 			Provisioner:   database.ProvisionerTypeEcho,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 			Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+			Priority:      database.ProvisionerJobPriorityDryRun,
 		})
 		require.NoError(t, err)
 		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
@@ -532,6 +628,7 @@ func TestUpdateJob(t *testing.T) {
 			ID:            uuid.New(),
 			Provisioner:   database.ProvisionerTypeEcho,
 			Type:          database.ProvisionerJobTypeTemplateVersionImport,
+			Priority:      database.ProvisionerJobPriorityUser,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 		})
 		require.NoError(t, err)
@@ -727,6 +824,7 @@ func TestFailJob(t *testing.T) {
 			Provisioner:   database.ProvisionerTypeEcho,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 			Type:          database.ProvisionerJobTypeTemplateVersionImport,
+			Priority:      database.ProvisionerJobPriorityUser,
 		})
 		require.NoError(t, err)
 		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
@@ -750,6 +848,7 @@ func TestFailJob(t *testing.T) {
 			ID:            uuid.New(),
 			Provisioner:   database.ProvisionerTypeEcho,
 			Type:          database.ProvisionerJobTypeTemplateVersionImport,
+			Priority:      database.ProvisionerJobPriorityUser,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 		})
 		require.NoError(t, err)
@@ -805,6 +904,7 @@ func TestFailJob(t *testing.T) {
 			Input:         input,
 			Provisioner:   database.ProvisionerTypeEcho,
 			Type:          database.ProvisionerJobTypeWorkspaceBuild,
+			Priority:      database.ProvisionerJobPriorityUser,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 		})
 		require.NoError(t, err)
@@ -872,6 +972,7 @@ func TestCompleteJob(t *testing.T) {
 			Provisioner:   database.ProvisionerTypeEcho,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 			Type:          database.ProvisionerJobTypeWorkspaceBuild,
+			Priority:      database.ProvisionerJobPriorityUser,
 		})
 		require.NoError(t, err)
 		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
@@ -905,6 +1006,7 @@ func TestCompleteJob(t *testing.T) {
 			Input:         []byte(`{"template_version_id": "` + versionID.String() + `"}`),
 			StorageMethod: database.ProvisionerStorageMethodFile,
 			Type:          database.ProvisionerJobTypeWorkspaceBuild,
+			Priority:      database.ProvisionerJobPriorityUser,
 		})
 		require.NoError(t, err)
 		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
@@ -959,6 +1061,7 @@ func TestCompleteJob(t *testing.T) {
 			Input:         []byte(`{"template_version_id": "` + versionID.String() + `"}`),
 			StorageMethod: database.ProvisionerStorageMethodFile,
 			Type:          database.ProvisionerJobTypeWorkspaceBuild,
+			Priority:      database.ProvisionerJobPriorityUser,
 		})
 		require.NoError(t, err)
 		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
@@ -991,6 +1094,48 @@ func TestCompleteJob(t *testing.T) {
 		require.False(t, job.Error.Valid)
 	})
 
+	t.Run("TemplateImport_ActivateOnSuccess", func(t *testing.T) {
+		t.Parallel()
+		srvID := uuid.New()
+		srv, db, _ := setup(t, false, &overrides{id: &srvID})
+		user := dbgen.User(t, db, database.User{})
+		template := dbgen.Template(t, db, database.Template{CreatedBy: user.ID})
+		jobID := uuid.New()
+		version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
+			JobID:      jobID,
+			CreatedBy:  user.ID,
+		})
+		job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+			ID:            jobID,
+			Provisioner:   database.ProvisionerTypeEcho,
+			Input:         []byte(`{"template_version_id": "` + version.ID.String() + `", "activate_on_success": true}`),
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			Type:          database.ProvisionerJobTypeTemplateVersionImport,
+			Priority:      database.ProvisionerJobPriorityUser,
+		})
+		require.NoError(t, err)
+		_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			WorkerID: uuid.NullUUID{
+				UUID:  srvID,
+				Valid: true,
+			},
+			Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		})
+		require.NoError(t, err)
+		_, err = srv.CompleteJob(ctx, &proto.CompletedJob{
+			JobId: job.ID.String(),
+			Type: &proto.CompletedJob_TemplateImport_{
+				TemplateImport: &proto.CompletedJob_TemplateImport{},
+			},
+		})
+		require.NoError(t, err)
+
+		template, err = db.GetTemplateByID(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, template.ActiveVersionID)
+	})
+
 	// TODO(@dean): remove this legacy test for MaxTTL
 	t.Run("WorkspaceBuildLegacy", func(t *testing.T) {
 		t.Parallel()
@@ -1447,6 +1592,16 @@ func TestCompleteJob(t *testing.T) {
 								Name: "example",
 								Type: "aws_instance",
 							}},
+							Timings: []*sdkproto.Timing{{
+								StartedAt: start.UnixMilli(),
+								EndedAt:   start.Add(time.Second).UnixMilli(),
+								Stage:     "apply",
+								Source:    "aws",
+								Action:    "create",
+								Resource:  "aws_instance.example",
+							}, {
+								Stage: "unknown",
+							}},
 						},
 					},
 				})
@@ -1455,6 +1610,13 @@ func TestCompleteJob(t *testing.T) {
 				<-publishedWorkspace
 				<-publishedLogs
 
+				timings, err := db.GetProvisionerJobTimingsByJobID(ctx, job.ID)
+				require.NoError(t, err)
+				require.Len(t, timings, 1)
+				require.Equal(t, database.ProvisionerJobTimingStageApply, timings[0].Stage)
+				require.Equal(t, "aws_instance.example", timings[0].Resource)
+				require.Equal(t, time.Second, timings[0].EndedAt.Sub(timings[0].StartedAt))
+
 				workspace, err = db.GetWorkspaceByID(ctx, workspace.ID)
 				require.NoError(t, err)
 				require.Equal(t, c.transition == database.WorkspaceTransitionDelete, workspace.Deleted)
@@ -1490,6 +1652,7 @@ func TestCompleteJob(t *testing.T) {
 			ID:            uuid.New(),
 			Provisioner:   database.ProvisionerTypeEcho,
 			Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+			Priority:      database.ProvisionerJobPriorityDryRun,
 			StorageMethod: database.ProvisionerStorageMethodFile,
 		})
 		require.NoError(t, err)
@@ -1510,10 +1673,22 @@ func TestCompleteJob(t *testing.T) {
 						Name: "something",
 						Type: "aws_instance",
 					}},
+					ResourceChanges: []*sdkproto.ResourceChange{{
+						Address: "aws_ebs_volume.home",
+						Type:    "aws_ebs_volume",
+						Name:    "home",
+						Action:  "replace",
+					}},
 				},
 			},
 		})
 		require.NoError(t, err)
+
+		changes, err := db.GetProvisionerJobResourceChangesByJobID(ctx, job.ID)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, "aws_ebs_volume.home", changes[0].Address)
+		require.Equal(t, database.ProvisionerJobResourceChangeActionReplace, changes[0].Action)
 	})
 }
 
@@ -243,6 +243,7 @@ func convertProvisionerJob(pj database.GetProvisionerJobsByIDsWithQueuePositionR
 		Tags:          provisionerJob.Tags,
 		QueuePosition: int(pj.QueuePosition),
 		QueueSize:     int(pj.QueueSize),
+		Priority:      codersdk.ProvisionerJobPriority(provisionerJob.Priority),
 	}
 	// Applying values optional to the struct.
 	if provisionerJob.StartedAt.Valid {
@@ -1021,6 +1021,30 @@ func TestAuthorizeScope(t *testing.T) {
 			{resource: ResourceWorkspace.InOrg(unusedID).WithOwner("not-me"), actions: []Action{ActionCreate}, allow: false},
 		},
 	)
+
+	// Custom scopes restrict to their permissions and allow list, and always
+	// allow the owner of the scope.
+	user = Subject{
+		ID: uuid.NewString(),
+		Roles: Roles{
+			must(RoleByName(RoleMember())),
+			must(RoleByName(RoleOrgMember(defOrg))),
+		},
+	}
+	user.Scope = CustomScope(user.ID, Permissions(map[string][]Action{
+		ResourceWorkspace.Type: {ActionRead},
+		ResourceUser.Type:      {ActionRead},
+	}), []string{workspaceID.String()})
+
+	testAuthorize(t, "CustomScope", user,
+		[]authTestCase{
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionUpdate, ActionDelete}, allow: false},
+			{resource: ResourceWorkspace.WithID(uuid.New()).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead}, allow: false},
+			{resource: ResourceUserObject(uuid.MustParse(user.ID)), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceUserObject(uuid.New()), actions: []Action{ActionRead}, allow: false},
+		},
+	)
 }
 
 // cases applies a given function to all test cases. This makes generalities easier to create.
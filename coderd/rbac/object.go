@@ -112,7 +112,7 @@ var (
 	// allows granting/deleting **ALL** roles.
 	// Never has an owner or org.
 	//	create  = Assign roles
-	//	update  = ??
+	//	update  = Create, update or delete custom roles
 	//	read	= View available roles to assign
 	//	delete	= Remove role
 	ResourceRoleAssignment = Object{
@@ -19,6 +19,11 @@ const (
 
 	orgAdmin  string = "organization-admin"
 	orgMember string = "organization-member"
+
+	// customSiteRole and customOrganizationRole are not roles, they stand in
+	// for any custom role in assignRoles.
+	customSiteRole         string = "custom-site-role"
+	customOrganizationRole string = "custom-organization-role"
 )
 
 func init() {
@@ -28,7 +33,8 @@ func init() {
 
 // RoleNames is a list of user assignable role names. The role names must be
 // in the builtInRoles map. Any non-user assignable roles will generate an
-// error on Expand.
+// error on Expand. Custom roles are stored in the database, so subjects with
+// custom roles must be expanded with the rolestore package instead.
 type RoleNames []string
 
 func (names RoleNames) Expand() ([]Role, error) {
@@ -296,32 +302,39 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 // The first key is the actor role, the second is the roles they can assign.
 //
 //	map[actor_role][assign_role]<can_assign>
+//
+// Custom roles are matched by customSiteRole and customOrganizationRole.
 var assignRoles = map[string]map[string]bool{
 	"system": {
-		owner:         true,
-		auditor:       true,
-		member:        true,
-		orgAdmin:      true,
-		orgMember:     true,
-		templateAdmin: true,
-		userAdmin:     true,
+		owner:                  true,
+		auditor:                true,
+		member:                 true,
+		orgAdmin:               true,
+		orgMember:              true,
+		templateAdmin:          true,
+		userAdmin:              true,
+		customSiteRole:         true,
+		customOrganizationRole: true,
 	},
 	owner: {
-		owner:         true,
-		auditor:       true,
-		member:        true,
-		orgAdmin:      true,
-		orgMember:     true,
-		templateAdmin: true,
-		userAdmin:     true,
+		owner:                  true,
+		auditor:                true,
+		member:                 true,
+		orgAdmin:               true,
+		orgMember:              true,
+		templateAdmin:          true,
+		userAdmin:              true,
+		customSiteRole:         true,
+		customOrganizationRole: true,
 	},
 	userAdmin: {
 		member:    true,
 		orgMember: true,
 	},
 	orgAdmin: {
-		orgAdmin:  true,
-		orgMember: true,
+		orgAdmin:               true,
+		orgMember:              true,
+		customOrganizationRole: true,
 	},
 }
 
@@ -348,6 +361,29 @@ type Permission struct {
 	Action       Action `json:"action"`
 }
 
+// Valid returns an error if the permission does not reference a known
+// resource type and action. Wildcards are not valid, they are reserved for
+// built-in roles.
+func (perm Permission) Valid() error {
+	var validType bool
+	for _, r := range AllResources() {
+		if r.Type == perm.ResourceType && r.Type != WildcardSymbol {
+			validType = true
+			break
+		}
+	}
+	if !validType {
+		return xerrors.Errorf("invalid resource type %q", perm.ResourceType)
+	}
+
+	for _, action := range AllActions() {
+		if action == perm.Action {
+			return nil
+		}
+	}
+	return xerrors.Errorf("invalid action %q", perm.Action)
+}
+
 // Role is a set of permissions at multiple levels:
 // - Site level permissions apply EVERYWHERE
 // - Org level permissions apply to EVERYTHING in a given ORG
@@ -381,7 +417,7 @@ func (roles Roles) Expand() ([]Role, error) {
 func (roles Roles) Names() []string {
 	names := make([]string, 0, len(roles))
 	for _, r := range roles {
-		return append(names, r.Name)
+		names = append(names, r.Name)
 	}
 	return names
 }
@@ -417,6 +453,16 @@ func CanAssignRole(expandable ExpandableRoles, assignedRole string) bool {
 		if allowed[assigned] {
 			return true
 		}
+
+		if _, builtIn := builtInRoles[assigned]; !builtIn {
+			custom := customSiteRole
+			if assignedOrg != "" {
+				custom = customOrganizationRole
+			}
+			if allowed[custom] {
+				return true
+			}
+		}
 	}
 	return false
 }
@@ -461,6 +507,18 @@ func rolesByNames(roleNames []string) ([]Role, error) {
 	return roles, nil
 }
 
+// ReservedRoleName returns true if the name is used by a built-in role, and so
+// cannot be used for a custom role. The name must not contain an organization
+// id.
+func ReservedRoleName(name string) bool {
+	if _, ok := builtInRoles[name]; ok {
+		return true
+	}
+	// These are not real roles, but have a special meaning when assigning
+	// roles.
+	return name == "system" || name == customSiteRole || name == customOrganizationRole
+}
+
 func IsOrgRole(roleName string) (string, bool) {
 	_, orgID, err := roleSplit(roleName)
 	if err == nil && orgID != "" {
@@ -426,6 +426,38 @@ func TestIsOrgRole(t *testing.T) {
 	}
 }
 
+func TestCanAssignCustomRole(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	otherOrgID := uuid.New()
+	siteRole := "custom-viewer"
+	orgRole := "custom-viewer:" + orgID.String()
+
+	testCases := []struct {
+		Name     string
+		Actor    rbac.RoleNames
+		Assigned string
+		Allowed  bool
+	}{
+		{Name: "OwnerSite", Actor: rbac.RoleNames{rbac.RoleOwner()}, Assigned: siteRole, Allowed: true},
+		{Name: "OwnerOrg", Actor: rbac.RoleNames{rbac.RoleOwner()}, Assigned: orgRole, Allowed: true},
+		{Name: "UserAdminSite", Actor: rbac.RoleNames{rbac.RoleUserAdmin()}, Assigned: siteRole, Allowed: false},
+		{Name: "MemberSite", Actor: rbac.RoleNames{rbac.RoleMember()}, Assigned: siteRole, Allowed: false},
+		{Name: "OrgAdminSite", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(orgID)}, Assigned: siteRole, Allowed: false},
+		{Name: "OrgAdminOrg", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(orgID)}, Assigned: orgRole, Allowed: true},
+		{Name: "OtherOrgAdminOrg", Actor: rbac.RoleNames{rbac.RoleOrgAdmin(otherOrgID)}, Assigned: orgRole, Allowed: false},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.Allowed, rbac.CanAssignRole(c.Actor, c.Assigned))
+		})
+	}
+}
+
 func TestListRoles(t *testing.T) {
 	t.Parallel()
 
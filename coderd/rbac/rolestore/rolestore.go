@@ -0,0 +1,102 @@
+// Package rolestore expands role names into rbac roles, including the custom
+// roles that are stored in the database.
+package rolestore
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// Expand returns the rbac roles for the given role names. Built-in roles are
+// expanded without a database query, and custom roles are fetched from the
+// database. Roles that do not exist are skipped, since a custom role can be
+// deleted while it is still assigned to users.
+//
+// The database must allow reading custom roles, so callers authenticating a
+// request should use a system context.
+func Expand(ctx context.Context, db database.Store, names []string) (rbac.Roles, error) {
+	roles, _, err := expand(ctx, db, names)
+	return roles, err
+}
+
+// Validate returns an error if any of the role names is neither a built-in
+// role nor an existing custom role.
+func Validate(ctx context.Context, db database.Store, names []string) error {
+	_, missing, err := expand(ctx, db, names)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return xerrors.Errorf("%q is not a supported role", missing[0])
+	}
+	return nil
+}
+
+func expand(ctx context.Context, db database.Store, names []string) (rbac.Roles, []string, error) {
+	roles := make(rbac.Roles, 0, len(names))
+	var lookup []string
+	for _, name := range names {
+		role, err := rbac.RoleByName(name)
+		if err == nil {
+			roles = append(roles, role)
+			continue
+		}
+		lookup = append(lookup, name)
+	}
+	if len(lookup) == 0 {
+		return roles, nil, nil
+	}
+
+	dbroles, err := db.CustomRoles(ctx, database.CustomRolesParams{
+		LookupRoles: lookup,
+	})
+	if err != nil {
+		return nil, nil, xerrors.Errorf("fetch custom roles: %w", err)
+	}
+
+	found := make(map[string]rbac.Role, len(dbroles))
+	for _, dbrole := range dbroles {
+		role := ConvertDBRole(dbrole)
+		found[role.Name] = role
+	}
+	var missing []string
+	for _, name := range lookup {
+		role, ok := found[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		roles = append(roles, role)
+	}
+	return roles, missing, nil
+}
+
+// RoleName returns the name a custom role is assigned by. Organization roles
+// include the organization id, just like built-in organization roles.
+func RoleName(dbrole database.CustomRole) string {
+	if dbrole.OrganizationID.Valid {
+		return dbrole.Name + ":" + dbrole.OrganizationID.UUID.String()
+	}
+	return dbrole.Name
+}
+
+// ConvertDBRole converts a custom role stored in the database into an rbac
+// role. Organization permissions are only granted within the organization of
+// the role.
+func ConvertDBRole(dbrole database.CustomRole) rbac.Role {
+	role := rbac.Role{
+		Name:        RoleName(dbrole),
+		DisplayName: dbrole.DisplayName,
+		Site:        dbrole.SitePermissions,
+		Org:         map[string][]rbac.Permission{},
+		User:        dbrole.UserPermissions,
+	}
+	if dbrole.OrganizationID.Valid && len(dbrole.OrgPermissions) > 0 {
+		role.Org[dbrole.OrganizationID.UUID.String()] = dbrole.OrgPermissions
+	}
+	return role
+}
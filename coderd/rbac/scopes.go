@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 )
 
@@ -31,6 +32,78 @@ func WorkspaceAgentScope(workspaceID, ownerID uuid.UUID) Scope {
 	}
 }
 
+// CustomScope returns a scope that only allows the given permissions. If an
+// allow list is given, only the listed resources and the owner of the scope
+// can be accessed.
+//
+// Permissions are granted at the site level of the scope. The scope can only
+// ever restrict the roles of the subject, so this does not grant anything the
+// subject could not already do.
+func CustomScope(ownerID string, permissions []Permission, allowList []string) Scope {
+	allowIDs := []string{WildcardSymbol}
+	if len(allowList) > 0 {
+		allowIDs = append([]string{ownerID}, allowList...)
+	}
+	return Scope{
+		Role: Role{
+			Name:        "Scope_custom",
+			DisplayName: "Custom permissions",
+			Site:        permissions,
+			Org:         map[string][]Permission{},
+			User:        []Permission{},
+		},
+		AllowIDList: allowIDs,
+	}
+}
+
+// ScopeWithin returns true if scope allows nothing that parent does not. It is
+// used to ensure a key can only create keys that are at most as powerful as
+// itself. Only site permissions are compared, since scopes do not use the
+// other levels.
+func ScopeWithin(scope, parent Scope) bool {
+	if !slices.Contains(parent.AllowIDList, WildcardSymbol) {
+		for _, id := range scope.AllowIDList {
+			if !slices.Contains(parent.AllowIDList, id) {
+				return false
+			}
+		}
+	}
+
+	for _, perm := range scope.Site {
+		if perm.Negate {
+			// Negated permissions only ever restrict the scope.
+			continue
+		}
+		var granted bool
+		for _, parentPerm := range parent.Site {
+			if parentPerm.Negate {
+				if overlaps(parentPerm.ResourceType, perm.ResourceType) && overlaps(string(parentPerm.Action), string(perm.Action)) {
+					return false
+				}
+				continue
+			}
+			if covers(parentPerm.ResourceType, perm.ResourceType) && covers(string(parentPerm.Action), string(perm.Action)) {
+				granted = true
+			}
+		}
+		if !granted {
+			return false
+		}
+	}
+	return true
+}
+
+// covers returns true if the parent resource type or action includes the
+// given one.
+func covers(parent, value string) bool {
+	return parent == WildcardSymbol || parent == value
+}
+
+// overlaps returns true if two resource types or actions share anything.
+func overlaps(a, b string) bool {
+	return a == WildcardSymbol || b == WildcardSymbol || a == b
+}
+
 const (
 	ScopeAll                ScopeName = "all"
 	ScopeApplicationConnect ScopeName = "application_connect"
@@ -0,0 +1,99 @@
+package rbac_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+func TestScopeWithin(t *testing.T) {
+	t.Parallel()
+
+	all, err := rbac.ScopeAll.Expand()
+	require.NoError(t, err)
+	appConnect, err := rbac.ScopeApplicationConnect.Expand()
+	require.NoError(t, err)
+
+	owner := uuid.NewString()
+	workspace := uuid.NewString()
+	readWorkspace := rbac.Permission{ResourceType: rbac.ResourceWorkspace.Type, Action: rbac.ActionRead}
+	createAPIKey := rbac.Permission{ResourceType: rbac.ResourceAPIKey.Type, Action: rbac.ActionCreate}
+
+	testCases := []struct {
+		Name   string
+		Scope  rbac.Scope
+		Parent rbac.Scope
+		Within bool
+	}{
+		{
+			Name:   "AllWithinAll",
+			Scope:  all,
+			Parent: all,
+			Within: true,
+		},
+		{
+			Name:   "CustomWithinAll",
+			Scope:  rbac.CustomScope(owner, []rbac.Permission{readWorkspace}, []string{workspace}),
+			Parent: all,
+			Within: true,
+		},
+		{
+			Name:   "AllNotWithinCustom",
+			Scope:  all,
+			Parent: rbac.CustomScope(owner, []rbac.Permission{readWorkspace, createAPIKey}, nil),
+			Within: false,
+		},
+		{
+			Name:   "AllNotWithinApplicationConnect",
+			Scope:  all,
+			Parent: appConnect,
+			Within: false,
+		},
+		{
+			Name:   "FewerPermissions",
+			Scope:  rbac.CustomScope(owner, []rbac.Permission{readWorkspace}, nil),
+			Parent: rbac.CustomScope(owner, []rbac.Permission{readWorkspace, createAPIKey}, nil),
+			Within: true,
+		},
+		{
+			Name:   "MorePermissions",
+			Scope:  rbac.CustomScope(owner, []rbac.Permission{readWorkspace, createAPIKey}, nil),
+			Parent: rbac.CustomScope(owner, []rbac.Permission{createAPIKey}, nil),
+			Within: false,
+		},
+		{
+			Name:  "NegatedInParent",
+			Scope: rbac.CustomScope(owner, []rbac.Permission{readWorkspace}, nil),
+			Parent: rbac.Scope{
+				Role: rbac.Role{Site: []rbac.Permission{
+					{ResourceType: rbac.WildcardSymbol, Action: rbac.WildcardSymbol},
+					{Negate: true, ResourceType: rbac.ResourceWorkspace.Type, Action: rbac.ActionRead},
+				}},
+				AllowIDList: []string{rbac.WildcardSymbol},
+			},
+			Within: false,
+		},
+		{
+			Name:   "NarrowerAllowList",
+			Scope:  rbac.CustomScope(owner, []rbac.Permission{readWorkspace}, []string{workspace}),
+			Parent: rbac.CustomScope(owner, []rbac.Permission{readWorkspace}, []string{workspace, uuid.NewString()}),
+			Within: true,
+		},
+		{
+			Name:   "NoAllowList",
+			Scope:  rbac.CustomScope(owner, []rbac.Permission{readWorkspace}, nil),
+			Parent: rbac.CustomScope(owner, []rbac.Permission{readWorkspace}, []string{workspace}),
+			Within: false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.Within, rbac.ScopeWithin(tc.Scope, tc.Parent))
+		})
+	}
+}
@@ -3,6 +3,8 @@ package coderd
 import (
 	"net/http"
 
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
 
@@ -27,8 +29,19 @@ func (api *API) assignableSiteRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	customRoles, err := api.Database.CustomRoles(ctx, database.CustomRolesParams{
+		ExcludeOrgRoles: true,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	roles := rbac.SiteRoles()
-	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
+	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles, customRoles))
 }
 
 // assignableSiteRoles returns all org wide roles that can be assigned.
@@ -51,11 +64,22 @@ func (api *API) assignableOrgRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	customRoles, err := api.Database.CustomRoles(ctx, database.CustomRolesParams{
+		OrganizationID: organization.ID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	roles := rbac.OrganizationRoles(organization.ID)
-	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
+	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles, customRoles))
 }
 
-func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []codersdk.AssignableRoles {
+func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role, customRoles []database.CustomRole) []codersdk.AssignableRoles {
 	assignable := make([]codersdk.AssignableRoles, 0)
 	for _, role := range roles {
 		// The member role is implied, and not assignable.
@@ -70,6 +94,15 @@ func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []coder
 				DisplayName: role.DisplayName,
 			},
 			Assignable: rbac.CanAssignRole(actorRoles, role.Name),
+			BuiltIn:    true,
+		})
+	}
+
+	for _, role := range customRoles {
+		converted := db2sdk.CustomRole(role)
+		assignable = append(assignable, codersdk.AssignableRoles{
+			Role:       converted,
+			Assignable: rbac.CanAssignRole(actorRoles, converted.Name),
 		})
 	}
 	return assignable
@@ -158,6 +158,7 @@ func convertRoles(assignableRoles map[string]bool) []codersdk.AssignableRoles {
 		converted = append(converted, codersdk.AssignableRoles{
 			Role:       role,
 			Assignable: assignable,
+			BuiltIn:    true,
 		})
 	}
 	return converted
@@ -0,0 +1,375 @@
+package coderd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/gitsync"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// @Summary Get template git source
+// @ID get-template-git-source
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateGitSource
+// @Router /templates/{template}/git [get]
+func (api *API) templateGitSource(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	source, err := api.Database.GetTemplateGitSourceByTemplateID(ctx, template.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template git source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplateGitSource(source))
+}
+
+// @Summary Update template git source
+// @ID update-template-git-source
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.UpdateTemplateGitSourceRequest true "Git source"
+// @Success 200 {object} codersdk.TemplateGitSource
+// @Router /templates/{template}/git [put]
+func (api *API) putTemplateGitSource(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	var req codersdk.UpdateTemplateGitSourceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validErrs []codersdk.ValidationError
+	if err := gitsync.ValidateRepositoryURL(req.RepositoryURL); err != nil {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "repository_url", Detail: err.Error()})
+	}
+	if err := gitsync.ValidateBranch(req.Branch); err != nil {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "branch", Detail: err.Error()})
+	}
+	if err := gitsync.ValidateSubdirectory(req.Subdirectory); err != nil {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "subdirectory", Detail: err.Error()})
+	}
+	pollInterval := time.Duration(req.PollIntervalMillis) * time.Millisecond
+	if pollInterval < 0 || (pollInterval > 0 && pollInterval < gitsync.MinPollInterval) {
+		validErrs = append(validErrs, codersdk.ValidationError{
+			Field:  "poll_interval_ms",
+			Detail: fmt.Sprintf("Must be zero to disable polling, or at least %s.", gitsync.MinPollInterval),
+		})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update template git source.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	var source database.TemplateGitSource
+	err := api.Database.InTx(func(tx database.Store) error {
+		existing, err := tx.GetTemplateGitSourceByTemplateID(ctx, template.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		exists := err == nil
+
+		secret := existing.WebhookSecret
+		if !exists || req.RegenerateWebhookSecret {
+			secret, err = cryptorand.String(32)
+			if err != nil {
+				return err
+			}
+		}
+		createdAt := existing.CreatedAt
+		if !exists {
+			createdAt = dbtime.Now()
+		}
+
+		// Versions are created on behalf of whoever last configured the
+		// source, so it keeps working if the original author leaves.
+		source, err = tx.UpsertTemplateGitSource(ctx, database.UpsertTemplateGitSourceParams{
+			TemplateID:    template.ID,
+			CreatedAt:     createdAt,
+			UpdatedAt:     dbtime.Now(),
+			CreatedBy:     apiKey.UserID,
+			RepositoryURL: req.RepositoryURL,
+			Branch:        req.Branch,
+			Subdirectory:  req.Subdirectory,
+			AutoPromote:   req.AutoPromote,
+			PollInterval:  int64(pollInterval),
+			WebhookSecret: secret,
+		})
+		return err
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating template git source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplateGitSource(source))
+}
+
+// @Summary Delete template git source
+// @ID delete-template-git-source
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 204
+// @Router /templates/{template}/git [delete]
+func (api *API) deleteTemplateGitSource(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	_, err := api.Database.GetTemplateGitSourceByTemplateID(ctx, template.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err == nil {
+		err = api.Database.DeleteTemplateGitSource(ctx, template.ID)
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template git source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Sync template git source
+// @Description Checks the template's branch and creates a template version
+// @Description if it has new commits.
+// @ID sync-template-git-source
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateGitSource
+// @Router /templates/{template}/git/sync [post]
+func (api *API) postTemplateGitSourceSync(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	// Ensure the caller can manage the source before syncing it as its
+	// creator.
+	_, err := api.Database.GetTemplateGitSourceByTemplateID(ctx, template.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template git source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Sync errors are recorded on the source, which is returned regardless.
+	source, err := gitsync.Sync(ctx, api.Database, api.Pubsub, api.Logger.Named("gitsync"), template.ID)
+	if err != nil && source.TemplateID == uuid.Nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error syncing template git source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplateGitSource(source))
+}
+
+// @Summary Receive template git webhook
+// @Description Accepts push events from GitHub and GitLab. Deliveries are
+// @Description authenticated with the source's webhook secret instead of a
+// @Description session token.
+// @ID receive-template-git-webhook
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 202 {object} codersdk.Response
+// @Router /templates/{template}/git/webhook [post]
+func (api *API) postTemplateGitWebhook(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	templateID, err := uuid.Parse(chi.URLParam(r, "template"))
+	if err != nil {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	//nolint:gocritic // The delivery is authenticated by the webhook secret below.
+	source, err := api.Database.GetTemplateGitSourceByTemplateID(dbauthz.AsSystemRestricted(ctx), templateID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template git source.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to read request body.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !validGitWebhookSignature(r, body, source.WebhookSecret) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid webhook signature.",
+		})
+		return
+	}
+
+	if event := r.Header.Get("X-GitHub-Event"); event != "" && event != "push" {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+			Message: fmt.Sprintf("Ignoring %q event.", event),
+		})
+		return
+	}
+	var push struct {
+		Ref string `json:"ref"`
+	}
+	err = json.Unmarshal(body, &push)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to parse push event.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if push.Ref != "refs/heads/"+source.Branch {
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+			Message: fmt.Sprintf("Ignoring push to %q.", push.Ref),
+		})
+		return
+	}
+
+	// Git providers time out deliveries after a few seconds, which isn't
+	// long enough to clone the repository.
+	api.gitSyncWaitGroup.Add(1)
+	go func() {
+		defer api.gitSyncWaitGroup.Done()
+		_, err := gitsync.Sync(api.ctx, api.Database, api.Pubsub, api.Logger.Named("gitsync"), source.TemplateID)
+		if err != nil {
+			api.Logger.Debug(api.ctx, "webhook triggered git sync failed",
+				slog.F("template_id", source.TemplateID), slog.Error(err))
+		}
+	}()
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.Response{
+		Message: "Sync started.",
+	})
+}
+
+// validGitWebhookSignature verifies a delivery signed by GitHub
+// (X-Hub-Signature-256) or carrying a GitLab secret token (X-Gitlab-Token).
+func validGitWebhookSignature(r *http.Request, body []byte, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	if signature := r.Header.Get("X-Hub-Signature-256"); signature != "" {
+		digest, ok := strings.CutPrefix(signature, "sha256=")
+		if !ok {
+			return false
+		}
+		got, err := hex.DecodeString(digest)
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write(body)
+		return hmac.Equal(got, mac.Sum(nil))
+	}
+	if token := r.Header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+	return false
+}
+
+func (api *API) convertTemplateGitSource(source database.TemplateGitSource) codersdk.TemplateGitSource {
+	webhookURL := api.AccessURL.JoinPath("/api/v2/templates", source.TemplateID.String(), "git", "webhook")
+	converted := codersdk.TemplateGitSource{
+		TemplateID:         source.TemplateID,
+		RepositoryURL:      source.RepositoryURL,
+		Branch:             source.Branch,
+		Subdirectory:       source.Subdirectory,
+		AutoPromote:        source.AutoPromote,
+		PollIntervalMillis: time.Duration(source.PollInterval).Milliseconds(),
+		WebhookURL:         webhookURL.String(),
+		WebhookSecret:      source.WebhookSecret,
+		LastCommitSHA:      source.LastCommitSHA,
+		LastError:          source.LastError,
+		CreatedAt:          source.CreatedAt,
+		UpdatedAt:          source.UpdatedAt,
+	}
+	if source.LastCheckedAt.Valid {
+		converted.LastCheckedAt = &source.LastCheckedAt.Time
+	}
+	if source.LastTemplateVersionID.Valid {
+		converted.LastTemplateVersionID = &source.LastTemplateVersionID.UUID
+	}
+	return converted
+}
@@ -0,0 +1,226 @@
+package coderd_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateGitSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.TemplateGitSource(ctx, template.ID)
+		requireStatus(t, err, http.StatusNotFound)
+
+		_, err = client.UpdateTemplateGitSource(ctx, template.ID, codersdk.UpdateTemplateGitSourceRequest{
+			RepositoryURL:      "file:///etc",
+			Branch:             "main",
+			Subdirectory:       "../outside",
+			PollIntervalMillis: 1000,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 3)
+
+		source, err := client.UpdateTemplateGitSource(ctx, template.ID, codersdk.UpdateTemplateGitSourceRequest{
+			RepositoryURL:      "https://github.com/coder/coder.git",
+			Branch:             "main",
+			Subdirectory:       "examples/templates/docker",
+			PollIntervalMillis: 300000,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "examples/templates/docker", source.Subdirectory)
+		require.EqualValues(t, 300000, source.PollIntervalMillis)
+		require.NotEmpty(t, source.WebhookSecret)
+		require.True(t, strings.HasSuffix(source.WebhookURL, "/api/v2/templates/"+template.ID.String()+"/git/webhook"))
+
+		// The secret is kept unless it is regenerated.
+		updated, err := client.UpdateTemplateGitSource(ctx, template.ID, codersdk.UpdateTemplateGitSourceRequest{
+			RepositoryURL: "https://github.com/coder/coder.git",
+			Branch:        "release",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "release", updated.Branch)
+		require.Equal(t, source.WebhookSecret, updated.WebhookSecret)
+		updated, err = client.UpdateTemplateGitSource(ctx, template.ID, codersdk.UpdateTemplateGitSourceRequest{
+			RepositoryURL:           "https://github.com/coder/coder.git",
+			Branch:                  "release",
+			RegenerateWebhookSecret: true,
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, source.WebhookSecret, updated.WebhookSecret)
+
+		// Members can use the template, but not see its webhook secret.
+		_, err = member.TemplateGitSource(ctx, template.ID)
+		requireStatus(t, err, http.StatusNotFound)
+		_, err = member.SyncTemplateGitSource(ctx, template.ID)
+		requireStatus(t, err, http.StatusNotFound)
+
+		err = client.DeleteTemplateGitSource(ctx, template.ID)
+		require.NoError(t, err)
+		_, err = client.TemplateGitSource(ctx, template.ID)
+		requireStatus(t, err, http.StatusNotFound)
+	})
+
+	t.Run("Webhook", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, templateAdminUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		version := coderdtest.CreateTemplateVersion(t, templateAdmin, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, templateAdmin, version.ID)
+		template := coderdtest.CreateTemplate(t, templateAdmin, owner.OrganizationID, version.ID)
+
+		// Local repositories are rejected by the API, so the source is
+		// inserted directly.
+		repo := echoGitRepo(t)
+		source := dbgen.TemplateGitSource(t, db, database.TemplateGitSource{
+			TemplateID:    template.ID,
+			CreatedBy:     templateAdminUser.ID,
+			RepositoryURL: repo,
+			AutoPromote:   true,
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		webhookURL := client.URL.JoinPath("/api/v2/templates", template.ID.String(), "git", "webhook").String()
+		deliver := func(body string, headers map[string]string) int {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, strings.NewReader(body))
+			require.NoError(t, err)
+			for key, value := range headers {
+				req.Header.Set(key, value)
+			}
+			res, err := client.HTTPClient.Do(req)
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			return res.StatusCode
+		}
+		sign := func(body string) string {
+			mac := hmac.New(sha256.New, []byte(source.WebhookSecret))
+			_, _ = mac.Write([]byte(body))
+			return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		}
+
+		const push = `{"ref":"refs/heads/main"}`
+		require.Equal(t, http.StatusUnauthorized, deliver(push, nil))
+		require.Equal(t, http.StatusUnauthorized, deliver(push, map[string]string{"X-Hub-Signature-256": sign("tampered")}))
+		require.Equal(t, http.StatusUnauthorized, deliver(push, map[string]string{"X-Gitlab-Token": "wrong"}))
+		require.Equal(t, http.StatusOK, deliver(`{"zen":"hi"}`, map[string]string{
+			"X-GitHub-Event":      "ping",
+			"X-Hub-Signature-256": sign(`{"zen":"hi"}`),
+		}))
+		const otherBranch = `{"ref":"refs/heads/feature"}`
+		require.Equal(t, http.StatusOK, deliver(otherBranch, map[string]string{"X-Gitlab-Token": source.WebhookSecret}))
+
+		require.Equal(t, http.StatusAccepted, deliver(push, map[string]string{
+			"X-GitHub-Event":      "push",
+			"X-Hub-Signature-256": sign(push),
+		}))
+
+		var synced codersdk.TemplateGitSource
+		require.Eventually(t, func() bool {
+			var err error
+			synced, err = templateAdmin.TemplateGitSource(ctx, template.ID)
+			return err == nil && synced.LastTemplateVersionID != nil
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Empty(t, synced.LastError)
+
+		gitVersion := coderdtest.AwaitTemplateVersionJobCompleted(t, templateAdmin, *synced.LastTemplateVersionID)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, gitVersion.Job.Status)
+		require.NotNil(t, gitVersion.GitCommit)
+		require.Equal(t, synced.LastCommitSHA, gitVersion.GitCommit.SHA)
+		require.Equal(t, "Add template", gitVersion.Message)
+		require.Equal(t, templateAdminUser.ID, gitVersion.CreatedBy.ID)
+
+		// Auto-promote makes the version active once it imports.
+		require.Eventually(t, func() bool {
+			updated, err := templateAdmin.Template(ctx, template.ID)
+			return err == nil && updated.ActiveVersionID == gitVersion.ID
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		// Manual syncs without new commits don't create versions.
+		resynced, err := templateAdmin.SyncTemplateGitSource(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, synced.LastTemplateVersionID, resynced.LastTemplateVersionID)
+	})
+}
+
+func requireStatus(t *testing.T, err error, status int) {
+	t.Helper()
+
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, status, apiErr.StatusCode())
+}
+
+// echoGitRepo creates a repository containing a template for the echo
+// provisioner.
+func echoGitRepo(t *testing.T) string {
+	t.Helper()
+
+	archive, err := echo.Tar(&echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ApplyComplete,
+	})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, header.Name), data, 0o600))
+	}
+	// Templates must contain Terraform files to be archived.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), nil, 0o600))
+
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch", "main"},
+		{"add", "-A"},
+		{"-c", "user.name=Coder", "-c", "user.email=test@coder.com", "commit", "--quiet", "-m", "Add template"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return dir
+}
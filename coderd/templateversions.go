@@ -446,6 +446,37 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	workspaceName := req.WorkspaceName
+	var workspaceID uuid.NullUUID
+	if req.WorkspaceID != uuid.Nil {
+		workspace, err := api.Database.GetWorkspaceByID(ctx, req.WorkspaceID)
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		// Planning against the workspace previews an update, so require
+		// the same permission as updating it.
+		if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		if !templateVersion.TemplateID.Valid || workspace.TemplateID != templateVersion.TemplateID.UUID {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Template version must belong to the template of the workspace.",
+			})
+			return
+		}
+		workspaceName = workspace.Name
+		workspaceID = uuid.NullUUID{UUID: workspace.ID, Valid: true}
+	}
+
 	richParameterValues := make([]database.WorkspaceBuildParameter, len(req.RichParameterValues))
 	for i, v := range req.RichParameterValues {
 		richParameterValues[i] = database.WorkspaceBuildParameter{
@@ -459,8 +490,9 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 	// request.
 	input, err := json.Marshal(provisionerdserver.TemplateVersionDryRunJob{
 		TemplateVersionID:   templateVersion.ID,
-		WorkspaceName:       req.WorkspaceName,
+		WorkspaceName:       workspaceName,
 		RichParameterValues: richParameterValues,
+		WorkspaceID:         workspaceID,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -498,6 +530,8 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 			Valid:      true,
 			RawMessage: metadataRaw,
 		},
+		Priority:   database.ProvisionerJobPriorityDryRun,
+		TemplateID: templateVersion.TemplateID,
 	})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -555,6 +589,49 @@ func (api *API) templateVersionDryRunResources(rw http.ResponseWriter, r *http.R
 	api.provisionerJobResources(rw, r, job.ProvisionerJob)
 }
 
+// @Summary Get template version dry-run resource changes by job ID
+// @ID get-template-version-dry-run-resource-changes-by-job-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param jobID path string true "Job ID" format(uuid)
+// @Success 200 {array} codersdk.ResourceChange
+// @Router /templateversions/{templateversion}/dry-run/{jobID}/resource-changes [get]
+func (api *API) templateVersionDryRunResourceChanges(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	job, ok := api.fetchTemplateVersionDryRunJob(rw, r)
+	if !ok {
+		return
+	}
+	if !job.ProvisionerJob.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Job hasn't completed!",
+		})
+		return
+	}
+
+	changes, err := api.Database.GetProvisionerJobResourceChangesByJobID(ctx, job.ProvisionerJob.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching resource changes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiChanges := make([]codersdk.ResourceChange, 0, len(changes))
+	for _, change := range changes {
+		apiChanges = append(apiChanges, codersdk.ResourceChange{
+			Address: change.Address,
+			Type:    change.Type,
+			Name:    change.Name,
+			Action:  codersdk.ResourceChangeAction(change.Action),
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiChanges)
+}
+
 // @Summary Get template version dry-run logs by job ID
 // @ID get-template-version-dry-run-logs-by-job-id
 // @Security CoderSessionToken
@@ -1445,6 +1522,14 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 			return xerrors.Errorf("marshal job metadata: %w", err)
 		}
 
+		var templateID uuid.NullUUID
+		if req.TemplateID != uuid.Nil {
+			templateID = uuid.NullUUID{
+				UUID:  req.TemplateID,
+				Valid: true,
+			}
+		}
+
 		provisionerJob, err = tx.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
 			ID:             jobID,
 			CreatedAt:      dbtime.Now(),
@@ -1461,19 +1546,13 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 				Valid:      true,
 				RawMessage: traceMetadataRaw,
 			},
+			Priority:   database.ProvisionerJobPriorityUser,
+			TemplateID: templateID,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert provisioner job: %w", err)
 		}
 
-		var templateID uuid.NullUUID
-		if req.TemplateID != uuid.Nil {
-			templateID = uuid.NullUUID{
-				UUID:  req.TemplateID,
-				Valid: true,
-			}
-		}
-
 		if req.Name == "" {
 			req.Name = namesgenerator.GetRandomName(1)
 		}
@@ -1585,6 +1664,13 @@ func (api *API) templateVersionLogs(rw http.ResponseWriter, r *http.Request) {
 }
 
 func convertTemplateVersion(version database.TemplateVersion, job codersdk.ProvisionerJob, warnings []codersdk.TemplateVersionWarning) codersdk.TemplateVersion {
+	var gitCommit *codersdk.TemplateVersionGitCommit
+	if version.GitCommitSHA != "" {
+		gitCommit = &codersdk.TemplateVersionGitCommit{
+			SHA:     version.GitCommitSHA,
+			Message: version.GitCommitMessage,
+		}
+	}
 	return codersdk.TemplateVersion{
 		ID:             version.ID,
 		TemplateID:     &version.TemplateID.UUID,
@@ -1600,8 +1686,9 @@ func convertTemplateVersion(version database.TemplateVersion, job codersdk.Provi
 			Username:  version.CreatedByUsername,
 			AvatarURL: version.CreatedByAvatarURL,
 		},
-		Archived: version.Archived,
-		Warnings: warnings,
+		Archived:  version.Archived,
+		GitCommit: gitCommit,
+		Warnings:  warnings,
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/provisionersdk"
 )
@@ -338,9 +339,10 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 				}
 				if err == nil {
 					err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
-						ID:               build.ID,
-						UpdatedAt:        dbtime.Now(),
-						ProvisionerState: prevBuild.ProvisionerState,
+						ID:                    build.ID,
+						UpdatedAt:             dbtime.Now(),
+						ProvisionerState:      prevBuild.ProvisionerState,
+						ProvisionerStateKeyID: sql.NullString{}, // set by dbcrypt if required
 					})
 					if err != nil {
 						return xerrors.Errorf("update workspace build by id: %w", err)
@@ -355,6 +357,11 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 		return xerrors.Errorf("in tx: %w", err)
 	}
 
+	err = provisionerjobs.CompleteJob(pub)
+	if err != nil {
+		return xerrors.Errorf("publish job completion: %w", err)
+	}
+
 	// Publish the new log notification to pubsub. Use the lowest log ID
 	// inserted so the log stream will fetch everything after that point.
 	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{
@@ -31,7 +31,9 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/idpsync"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/userpassword"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
@@ -237,9 +239,19 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	//nolint:gocritic // Custom roles must be read to authorize the user.
+	userRoles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), api.Database, roles.Roles)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error expanding user roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	userSubj := rbac.Subject{
 		ID:     user.ID.String(),
-		Roles:  rbac.RoleNames(roles.Roles),
+		Roles:  userRoles,
 		Groups: roles.Groups,
 		Scope:  rbac.ScopeAll,
 	}
@@ -468,6 +480,17 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 		iconURL = api.OIDCConfig.IconURL
 	}
 
+	loginProviders := make([]codersdk.LoginProviderAuthMethod, 0, len(api.LoginProviders))
+	for _, provider := range api.LoginProviders {
+		loginProviders = append(loginProviders, codersdk.LoginProviderAuthMethod{
+			ID:          provider.ID,
+			Type:        codersdk.LoginType(provider.LoginType()),
+			DisplayName: provider.DisplayName,
+			IconURL:     provider.IconURL,
+			CallbackURL: provider.CallbackPath(),
+		})
+	}
+
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.AuthMethods{
 		Password: codersdk.AuthMethod{
 			Enabled: !api.DeploymentValues.DisablePasswordAuth.Value(),
@@ -478,9 +501,64 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 			SignInText: signInText,
 			IconURL:    iconURL,
 		},
+		LoginProviders: loginProviders,
 	})
 }
 
+// LoginProvider is a named identity provider users can log in with, in
+// addition to the default GitHub and OIDC providers. Users that log in with it
+// are linked to it by its ID, so they can't log in with another provider of
+// the same type.
+type LoginProvider struct {
+	ID          string
+	DisplayName string
+	IconURL     string
+
+	// Exactly one of OIDC and Github is set.
+	OIDC   *OIDCConfig
+	Github *GithubOAuth2Config
+}
+
+// LoginType returns the login type of users that log in with the provider.
+func (p *LoginProvider) LoginType() database.LoginType {
+	if p.Github != nil {
+		return database.LoginTypeGithub
+	}
+	return database.LoginTypeOIDC
+}
+
+// OAuth2Config returns the configuration used to authenticate with the
+// provider.
+func (p *LoginProvider) OAuth2Config() httpmw.OAuth2Config {
+	if p.Github != nil {
+		return p.Github
+	}
+	return p.OIDC
+}
+
+// CallbackPath returns the path of the callback that logs users in with the
+// provider.
+func (p *LoginProvider) CallbackPath() string {
+	return fmt.Sprintf("/api/v2/users/login-providers/%s/callback", p.ID)
+}
+
+// @Summary Login provider callback
+// @ID login-provider-callback
+// @Security CoderSessionToken
+// @Tags Users
+// @Param loginprovider path string true "Login provider ID"
+// @Success 307
+// @Router /users/login-providers/{loginprovider}/callback [get]
+func (api *API) loginProviderCallback(provider *LoginProvider) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if provider.Github != nil {
+			api.githubLogin(rw, r, provider.Github, provider.ID)
+			return
+		}
+		api.oidcLogin(rw, r, provider.OIDC, provider.ID)
+	}
+}
+
 // @Summary OAuth 2.0 GitHub Callback
 // @ID oauth-20-github-callback
 // @Security CoderSessionToken
@@ -488,8 +566,14 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 // @Success 307
 // @Router /users/oauth2/github/callback [get]
 func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
+	api.githubLogin(rw, r, api.GithubOAuth2Config, "")
+}
+
+// githubLogin logs in a user with GitHub. The login provider is the ID of the
+// named login provider the config belongs to, or empty for the default one.
+func (api *API) githubLogin(rw http.ResponseWriter, r *http.Request, cfg *GithubOAuth2Config, loginProvider string) {
 	var (
-		// userOAuth2Github is a system function.
+		// githubLogin is a system function.
 		//nolint:gocritic
 		ctx               = dbauthz.AsSystemRestricted(r.Context())
 		state             = httpmw.OAuth2(r)
@@ -511,8 +595,8 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 	var selectedMemberships []*github.Membership
 	var organizationNames []string
 	redirect := state.Redirect
-	if !api.GithubOAuth2Config.AllowEveryone {
-		memberships, err := api.GithubOAuth2Config.ListOrganizationMemberships(ctx, oauthClient)
+	if !cfg.AllowEveryone {
+		memberships, err := cfg.ListOrganizationMemberships(ctx, oauthClient)
 		if err != nil {
 			logger.Error(ctx, "unable to list organization members", slog.Error(err))
 			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -526,7 +610,7 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 			if membership.GetState() != "active" {
 				continue
 			}
-			for _, allowed := range api.GithubOAuth2Config.AllowOrganizations {
+			for _, allowed := range cfg.AllowOrganizations {
 				if *membership.Organization.Login != allowed {
 					continue
 				}
@@ -541,7 +625,7 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	ghUser, err := api.GithubOAuth2Config.AuthenticatedUser(ctx, oauthClient)
+	ghUser, err := cfg.AuthenticatedUser(ctx, oauthClient)
 	if err != nil {
 		logger.Error(ctx, "oauth2: unable to fetch authenticated user", slog.Error(err))
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -552,9 +636,9 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	// The default if no teams are specified is to allow all.
-	if !api.GithubOAuth2Config.AllowEveryone && len(api.GithubOAuth2Config.AllowTeams) > 0 {
+	if !cfg.AllowEveryone && len(cfg.AllowTeams) > 0 {
 		var allowedTeam *github.Membership
-		for _, allowTeam := range api.GithubOAuth2Config.AllowTeams {
+		for _, allowTeam := range cfg.AllowTeams {
 			if allowedTeam != nil {
 				break
 			}
@@ -565,7 +649,7 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 					continue
 				}
 
-				allowedTeam, err = api.GithubOAuth2Config.TeamMembership(ctx, oauthClient, allowTeam.Organization, allowTeam.Slug, *ghUser.Login)
+				allowedTeam, err = cfg.TeamMembership(ctx, oauthClient, allowTeam.Organization, allowTeam.Slug, *ghUser.Login)
 				// The calling user may not have permission to the requested team!
 				if err != nil {
 					continue
@@ -578,7 +662,7 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	emails, err := api.GithubOAuth2Config.ListEmails(ctx, oauthClient)
+	emails, err := cfg.ListEmails(ctx, oauthClient)
 	if err != nil {
 		logger.Error(ctx, "oauth2: unable to list emails", slog.Error(err))
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -620,16 +704,17 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	params := (&oauthLoginParams{
-		User:         user,
-		Link:         link,
-		State:        state,
-		LinkedID:     githubLinkedID(ghUser),
-		LoginType:    database.LoginTypeGithub,
-		AllowSignups: api.GithubOAuth2Config.AllowSignups,
-		Email:        verifiedEmail.GetEmail(),
-		Username:     ghUser.GetLogin(),
-		AvatarURL:    ghUser.GetAvatarURL(),
-		DebugContext: OauthDebugContext{},
+		User:          user,
+		Link:          link,
+		State:         state,
+		LinkedID:      githubLinkedID(ghUser),
+		LoginType:     database.LoginTypeGithub,
+		LoginProvider: loginProvider,
+		AllowSignups:  cfg.AllowSignups,
+		Email:         verifiedEmail.GetEmail(),
+		Username:      ghUser.GetLogin(),
+		AvatarURL:     ghUser.GetAvatarURL(),
+		DebugContext:  OauthDebugContext{},
 	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
 		return audit.InitRequest[database.User](rw, params)
 	})
@@ -733,8 +818,14 @@ func (cfg OIDCConfig) RoleSyncEnabled() bool {
 // @Success 307
 // @Router /users/oidc/callback [get]
 func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
+	api.oidcLogin(rw, r, api.OIDCConfig, "")
+}
+
+// oidcLogin logs in a user with OIDC. The login provider is the ID of the
+// named login provider the config belongs to, or empty for the default one.
+func (api *API) oidcLogin(rw http.ResponseWriter, r *http.Request, cfg *OIDCConfig, loginProvider string) {
 	var (
-		// userOIDC is a system function.
+		// oidcLogin is a system function.
 		//nolint:gocritic
 		ctx               = dbauthz.AsSystemRestricted(r.Context())
 		state             = httpmw.OAuth2(r)
@@ -758,7 +849,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	idToken, err := api.OIDCConfig.Verifier.Verify(ctx, rawIDToken)
+	idToken, err := cfg.Verifier.Verify(ctx, rawIDToken)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Failed to verify OIDC token.",
@@ -802,8 +893,8 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	userInfoClaims := make(map[string]interface{})
 	// If user info is skipped, the idtokenClaims are the claims.
 	mergedClaims := idtokenClaims
-	if !api.OIDCConfig.IgnoreUserInfo {
-		userInfo, err := api.OIDCConfig.Provider.UserInfo(ctx, oauth2.StaticTokenSource(state.Token))
+	if !cfg.IgnoreUserInfo {
+		userInfo, err := cfg.Provider.UserInfo(ctx, oauth2.StaticTokenSource(state.Token))
 		if err == nil {
 			err = userInfo.Claims(&userInfoClaims)
 			if err != nil {
@@ -845,13 +936,13 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	usernameRaw, ok := mergedClaims[api.OIDCConfig.UsernameField]
+	usernameRaw, ok := mergedClaims[cfg.UsernameField]
 	var username string
 	if ok {
 		username, _ = usernameRaw.(string)
 	}
 
-	emailRaw, ok := mergedClaims[api.OIDCConfig.EmailField]
+	emailRaw, ok := mergedClaims[cfg.EmailField]
 	if !ok {
 		// Email is an optional claim in OIDC and
 		// instead the email is frequently sent in
@@ -879,7 +970,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	if ok {
 		verified, ok := verifiedRaw.(bool)
 		if ok && !verified {
-			if !api.OIDCConfig.IgnoreEmailVerified {
+			if !cfg.IgnoreEmailVerified {
 				httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
 					Message: fmt.Sprintf("Verify the %q email address on your OIDC provider to authenticate!", email),
 				})
@@ -903,9 +994,9 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		username = httpapi.UsernameFrom(username)
 	}
 
-	if len(api.OIDCConfig.EmailDomain) > 0 {
+	if len(cfg.EmailDomain) > 0 {
 		ok = false
-		for _, domain := range api.OIDCConfig.EmailDomain {
+		for _, domain := range cfg.EmailDomain {
 			if strings.HasSuffix(strings.ToLower(email), strings.ToLower(domain)) {
 				ok = true
 				break
@@ -913,7 +1004,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		}
 		if !ok {
 			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
-				Message: fmt.Sprintf("Your email %q is not in domains %q !", email, api.OIDCConfig.EmailDomain),
+				Message: fmt.Sprintf("Your email %q is not in domains %q !", email, cfg.EmailDomain),
 			})
 			return
 		}
@@ -926,18 +1017,29 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx = slog.With(ctx, slog.F("email", email), slog.F("username", username))
-	usingGroups, groups, groupErr := api.oidcGroups(ctx, mergedClaims)
+	usingGroups, groups, groupErr := api.oidcGroups(ctx, cfg, mergedClaims)
 	if groupErr != nil {
 		groupErr.Write(rw, r)
 		return
 	}
 
-	roles, roleErr := api.oidcRoles(ctx, mergedClaims)
+	roles, roleErr := api.oidcRoles(ctx, cfg, mergedClaims)
 	if roleErr != nil {
 		roleErr.Write(rw, r)
 		return
 	}
 
+	syncResult, syncErr := api.oidcSyncRules(ctx, mergedClaims)
+	if syncErr != nil {
+		syncErr.Write(rw, r)
+		return
+	}
+	usingRoles := cfg.RoleSyncEnabled()
+	if syncResult != nil {
+		// Sync rules replace the group and role sync configured by flags.
+		usingGroups, usingRoles = false, false
+	}
+
 	user, link, err := findLinkedUser(ctx, api.Database, oidcLinkedID(idToken), email)
 	if err != nil {
 		logger.Error(ctx, "oauth2: unable to find linked user", slog.F("email", email), slog.Error(err))
@@ -960,16 +1062,18 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		State:               state,
 		LinkedID:            oidcLinkedID(idToken),
 		LoginType:           database.LoginTypeOIDC,
-		AllowSignups:        api.OIDCConfig.AllowSignups,
+		LoginProvider:       loginProvider,
+		AllowSignups:        cfg.AllowSignups,
 		Email:               email,
 		Username:            username,
 		AvatarURL:           picture,
-		UsingRoles:          api.OIDCConfig.RoleSyncEnabled(),
+		UsingRoles:          usingRoles,
 		Roles:               roles,
 		UsingGroups:         usingGroups,
 		Groups:              groups,
-		CreateMissingGroups: api.OIDCConfig.CreateMissingGroups,
-		GroupFilter:         api.OIDCConfig.GroupFilter,
+		CreateMissingGroups: cfg.CreateMissingGroups,
+		GroupFilter:         cfg.GroupFilter,
+		SyncResult:          syncResult,
 		DebugContext: OauthDebugContext{
 			IDTokenClaims:  idtokenClaims,
 			UserInfoClaims: userInfoClaims,
@@ -1007,20 +1111,20 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 }
 
 // oidcGroups returns the groups for the user from the OIDC claims.
-func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interface{}) (bool, []string, *httpError) {
+func (api *API) oidcGroups(ctx context.Context, cfg *OIDCConfig, mergedClaims map[string]interface{}) (bool, []string, *httpError) {
 	logger := api.Logger.Named(userAuthLoggerName)
 	usingGroups := false
 	var groups []string
 
 	// If the GroupField is the empty string, then groups from OIDC are not used.
 	// This is so we can support manual group assignment.
-	if api.OIDCConfig.GroupField != "" {
+	if cfg.GroupField != "" {
 		// If the allow list is empty, then the user is allowed to log in.
 		// Otherwise, they must belong to at least 1 group in the allow list.
-		inAllowList := len(api.OIDCConfig.GroupAllowList) == 0
+		inAllowList := len(cfg.GroupAllowList) == 0
 
 		usingGroups = true
-		groupsRaw, ok := mergedClaims[api.OIDCConfig.GroupField]
+		groupsRaw, ok := mergedClaims[cfg.GroupField]
 		if ok {
 			parsedGroups, err := parseStringSliceClaim(groupsRaw)
 			if err != nil {
@@ -1042,10 +1146,10 @@ func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interfac
 			)
 
 			for _, group := range parsedGroups {
-				if mappedGroup, ok := api.OIDCConfig.GroupMapping[group]; ok {
+				if mappedGroup, ok := cfg.GroupMapping[group]; ok {
 					group = mappedGroup
 				}
-				if _, ok := api.OIDCConfig.GroupAllowList[group]; ok {
+				if _, ok := cfg.GroupAllowList[group]; ok {
 					inAllowList = true
 				}
 				groups = append(groups, group)
@@ -1054,7 +1158,7 @@ func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interfac
 
 		if !inAllowList {
 			logger.Debug(ctx, "oidc group claim not in allow list, rejecting login",
-				slog.F("allow_list_count", len(api.OIDCConfig.GroupAllowList)),
+				slog.F("allow_list_count", len(cfg.GroupAllowList)),
 				slog.F("user_group_count", len(groups)),
 			)
 			detail := "Ask an administrator to add one of your groups to the whitelist"
@@ -1085,13 +1189,13 @@ func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interfac
 // It would be preferred to just return an error, however this function
 // decorates returned errors with the appropriate HTTP status codes and details
 // that are hard to carry in a standard `error` without more work.
-func (api *API) oidcRoles(ctx context.Context, mergedClaims map[string]interface{}) ([]string, *httpError) {
-	roles := api.OIDCConfig.UserRolesDefault
-	if !api.OIDCConfig.RoleSyncEnabled() {
+func (api *API) oidcRoles(ctx context.Context, cfg *OIDCConfig, mergedClaims map[string]interface{}) ([]string, *httpError) {
+	roles := cfg.UserRolesDefault
+	if !cfg.RoleSyncEnabled() {
 		return roles, nil
 	}
 
-	rolesRow, ok := mergedClaims[api.OIDCConfig.UserRoleField]
+	rolesRow, ok := mergedClaims[cfg.UserRoleField]
 	if !ok {
 		// If no claim is provided than we can assume the user is just
 		// a member. This is because there is no way to tell the difference
@@ -1120,7 +1224,7 @@ func (api *API) oidcRoles(ctx context.Context, mergedClaims map[string]interface
 		slog.F("roles", parsedRoles),
 	)
 	for _, role := range parsedRoles {
-		if mappedRoles, ok := api.OIDCConfig.UserRoleMapping[role]; ok {
+		if mappedRoles, ok := cfg.UserRoleMapping[role]; ok {
 			if len(mappedRoles) == 0 {
 				continue
 			}
@@ -1134,6 +1238,49 @@ func (api *API) oidcRoles(ctx context.Context, mergedClaims map[string]interface
 	return roles, nil
 }
 
+// oidcSyncRules evaluates the OIDC sync rules against the claims. It returns
+// nil if no rules are set, in which case groups and roles are synced as
+// configured by flags.
+func (api *API) oidcSyncRules(ctx context.Context, mergedClaims map[string]interface{}) (*idpsync.Result, *httpError) {
+	//nolint:gocritic // The user is not authenticated yet.
+	rulesJSON, err := api.Database.GetOIDCSyncRules(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return nil, &httpError{
+			code:   http.StatusInternalServerError,
+			msg:    "Failed to get OIDC sync rules",
+			detail: err.Error(),
+		}
+	}
+	var rules codersdk.OIDCSyncRules
+	err = json.Unmarshal([]byte(rulesJSON), &rules)
+	if err != nil {
+		return nil, &httpError{
+			code:   http.StatusInternalServerError,
+			msg:    "Failed to parse OIDC sync rules",
+			detail: err.Error(),
+		}
+	}
+	engine, err := idpsync.New(rules)
+	if err != nil {
+		return nil, &httpError{
+			code:   http.StatusInternalServerError,
+			msg:    "Login disabled until OIDC sync rules are fixed",
+			detail: err.Error(),
+		}
+	}
+	if engine.Empty() {
+		return nil, nil
+	}
+
+	result := engine.Evaluate(mergedClaims)
+	api.Logger.Debug(ctx, "oidc sync rules evaluated",
+		slog.F("matched_rules", result.MatchedRules),
+		slog.F("site_roles", result.SiteRoles),
+		slog.F("organizations", len(result.Organizations)),
+	)
+	return &result, nil
+}
+
 // claimFields returns the sorted list of fields in the claims map.
 func claimFields(claims map[string]interface{}) []string {
 	fields := []string{}
@@ -1183,6 +1330,9 @@ type oauthLoginParams struct {
 	State     httpmw.OAuth2State
 	LinkedID  string
 	LoginType database.LoginType
+	// LoginProvider is the ID of the named login provider the user logs in
+	// with, or empty for the default provider of the login type.
+	LoginProvider string
 
 	// The following are necessary in order to
 	// create new users.
@@ -1200,6 +1350,9 @@ type oauthLoginParams struct {
 	// the roles provided.
 	UsingRoles bool
 	Roles      []string
+	// If SyncResult is set, the user is assigned the organizations, roles
+	// and groups the OIDC sync rules produced.
+	SyncResult *idpsync.Result
 
 	DebugContext OauthDebugContext
 
@@ -1303,6 +1456,12 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			return wrongLoginTypeHTTPError(user.LoginType, params.LoginType)
 		}
 
+		// Users are linked to the provider they first logged in with, so
+		// another provider of the same type can't take over the account.
+		if link.UserID != uuid.Nil && link.LoginProvider != params.LoginProvider {
+			return wrongLoginProviderHTTPError(link.LoginProvider, params.LoginProvider)
+		}
+
 		// This can happen if a user is a built-in user but is signing in
 		// with OIDC for the first time.
 		if user.ID == uuid.Nil {
@@ -1399,6 +1558,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 				OAuthRefreshTokenKeyID: sql.NullString{}, // set by dbcrypt if required
 				OAuthExpiry:            params.State.Token.Expiry,
 				DebugContext:           debugContext,
+				LoginProvider:          params.LoginProvider,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert user link: %w", err)
@@ -1443,10 +1603,20 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 
 		// Ensure roles are correct.
 		if params.UsingRoles {
+			//nolint:gocritic // Custom roles must be read to know which roles exist.
+			existing, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), tx, params.Roles)
+			if err != nil {
+				return xerrors.Errorf("expand roles: %w", err)
+			}
+			exists := make(map[string]bool, len(existing))
+			for _, role := range existing {
+				exists[role.Name] = true
+			}
+
 			ignored := make([]string, 0)
 			filtered := make([]string, 0, len(params.Roles))
 			for _, role := range params.Roles {
-				if _, err := rbac.RoleByName(role); err == nil {
+				if exists[role] {
 					filtered = append(filtered, role)
 				} else {
 					ignored = append(ignored, role)
@@ -1454,7 +1624,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			}
 
 			//nolint:gocritic
-			err := api.Options.SetUserSiteRoles(dbauthz.AsSystemRestricted(ctx), logger, tx, user.ID, filtered)
+			err = api.Options.SetUserSiteRoles(dbauthz.AsSystemRestricted(ctx), logger, tx, user.ID, filtered)
 			if err != nil {
 				return httpError{
 					code:             http.StatusBadRequest,
@@ -1472,6 +1642,14 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			}
 		}
 
+		if params.SyncResult != nil {
+			//nolint:gocritic // System needs to assign organizations, roles and groups.
+			err := api.Options.ApplyOIDCSyncRules(dbauthz.AsSystemRestricted(ctx), logger, tx, user.ID, *params.SyncResult)
+			if err != nil {
+				return xerrors.Errorf("apply oidc sync rules: %w", err)
+			}
+		}
+
 		needsUpdate := false
 		if user.AvatarURL != params.AvatarURL {
 			user.AvatarURL = params.AvatarURL
@@ -1751,6 +1929,22 @@ func wrongLoginTypeHTTPError(user database.LoginType, params database.LoginType)
 	}
 }
 
+func wrongLoginProviderHTTPError(user string, params string) httpError {
+	name := func(provider string) string {
+		if provider == "" {
+			return "default"
+		}
+		return provider
+	}
+	return httpError{
+		code:             http.StatusForbidden,
+		renderStaticPage: true,
+		msg:              "Incorrect login provider",
+		detail: fmt.Sprintf("Attempting to use the %q login provider, but the user is linked to the %q login provider.",
+			name(params), name(user)),
+	}
+}
+
 // parseStringSliceClaim parses the claim for groups and roles, expected []string.
 //
 // Some providers like ADFS return a single string instead of an array if there
@@ -23,6 +23,7 @@ import (
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/codersdk"
@@ -160,6 +161,39 @@ func TestUserAuthMethods(t *testing.T) {
 		require.True(t, methods.Password.Enabled)
 		require.True(t, methods.Github.Enabled)
 	})
+	t.Run("LoginProviders", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LoginProviders: []*coderd.LoginProvider{{
+				ID:          "partner",
+				DisplayName: "Partner SSO",
+				IconURL:     "/icon/partner.svg",
+				OIDC:        &coderd.OIDCConfig{OAuth2Config: &testutil.OAuth2Config{}},
+			}, {
+				ID:     "ghe",
+				Github: &coderd.GithubOAuth2Config{OAuth2Config: &testutil.OAuth2Config{}},
+			}},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.False(t, methods.Github.Enabled)
+		require.False(t, methods.OIDC.Enabled)
+		require.Equal(t, []codersdk.LoginProviderAuthMethod{{
+			ID:          "partner",
+			Type:        codersdk.LoginTypeOIDC,
+			DisplayName: "Partner SSO",
+			IconURL:     "/icon/partner.svg",
+			CallbackURL: "/api/v2/users/login-providers/partner/callback",
+		}, {
+			ID:          "ghe",
+			Type:        codersdk.LoginTypeGithub,
+			CallbackURL: "/api/v2/users/login-providers/ghe/callback",
+		}}, methods.LoginProviders)
+	})
 }
 
 // nolint:bodyclose
@@ -1004,6 +1038,114 @@ func TestUserOIDC(t *testing.T) {
 	})
 }
 
+// nolint:bodyclose
+func TestUserLoginProviders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OIDC", func(t *testing.T) {
+		t.Parallel()
+
+		defaultIDP := oidctest.NewFakeIDP(t, oidctest.WithServing())
+		partnerIDP := oidctest.NewFakeIDP(t,
+			oidctest.WithServing(),
+			oidctest.WithLoginProvider("partner"),
+		)
+		client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+			OIDCConfig: defaultIDP.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+				cfg.AllowSignups = true
+			}),
+			LoginProviders: []*coderd.LoginProvider{{
+				ID: "partner",
+				OIDC: partnerIDP.OIDCConfig(t, nil, func(cfg *coderd.OIDCConfig) {
+					cfg.AllowSignups = true
+					// Each provider has its own claim mapping.
+					cfg.EmailField = "mail"
+				}),
+			}},
+		})
+
+		// Users are linked to the provider they sign up with.
+		userClient, resp := partnerIDP.Login(t, client, jwt.MapClaims{
+			"mail": "alice@partner.com",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "alice@partner.com", user.Email)
+		require.Equal(t, codersdk.LoginTypeOIDC, user.LoginType)
+
+		//nolint:gocritic // Unit test
+		link, err := api.Database.GetUserLinkByUserIDLoginType(dbauthz.AsSystemRestricted(ctx), database.GetUserLinkByUserIDLoginTypeParams{
+			UserID:    user.ID,
+			LoginType: database.LoginTypeOIDC,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "partner", link.LoginProvider)
+
+		// The default provider can't log in as a user linked to another
+		// provider, even with the same email.
+		_, resp = defaultIDP.AttemptLogin(t, client, jwt.MapClaims{
+			"email": "alice@partner.com",
+		})
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		// Users of the default provider are unaffected.
+		_, resp = defaultIDP.Login(t, client, jwt.MapClaims{
+			"email": "bob@coder.com",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		_, resp = partnerIDP.Login(t, client, jwt.MapClaims{
+			"mail": "alice@partner.com",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Github", func(t *testing.T) {
+		t.Parallel()
+
+		githubConfig := func() *coderd.GithubOAuth2Config {
+			return &coderd.GithubOAuth2Config{
+				OAuth2Config:  &testutil.OAuth2Config{},
+				AllowEveryone: true,
+				AllowSignups:  true,
+				AuthenticatedUser: func(ctx context.Context, _ *http.Client) (*github.User, error) {
+					return &github.User{
+						Login: github.String("kyle"),
+						ID:    i64ptr(1234),
+					}, nil
+				},
+				ListEmails: func(ctx context.Context, client *http.Client) ([]*github.UserEmail, error) {
+					return []*github.UserEmail{{
+						Email:    github.String("kyle@coder.com"),
+						Verified: github.Bool(true),
+						Primary:  github.Bool(true),
+					}}, nil
+				},
+			}
+		}
+		client := coderdtest.New(t, &coderdtest.Options{
+			GithubOAuth2Config: githubConfig(),
+			LoginProviders: []*coderd.LoginProvider{{
+				ID:     "ghe",
+				Github: githubConfig(),
+			}},
+		})
+
+		resp := oauth2CallbackPath(t, client, "/api/v2/users/login-providers/ghe/callback")
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+		client.SetSessionToken(authCookieValue(resp.Cookies()))
+		user, err := client.User(context.Background(), "me")
+		require.NoError(t, err)
+		require.Equal(t, "kyle@coder.com", user.Email)
+		require.Equal(t, codersdk.LoginTypeGithub, user.LoginType)
+
+		resp = oauth2Callback(t, client)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
 func TestUserLogout(t *testing.T) {
 	t.Parallel()
 
@@ -1109,12 +1251,16 @@ func TestUserLogout(t *testing.T) {
 }
 
 func oauth2Callback(t *testing.T, client *codersdk.Client) *http.Response {
+	return oauth2CallbackPath(t, client, "/api/v2/users/oauth2/github/callback")
+}
+
+func oauth2CallbackPath(t *testing.T, client *codersdk.Client, callbackPath string) *http.Response {
 	client.HTTPClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
 
 	state := "somestate"
-	oauthURL, err := client.URL.Parse("/api/v2/users/oauth2/github/callback?code=asd&state=" + state)
+	oauthURL, err := client.URL.Parse(callbackPath + "?code=asd&state=" + state)
 	require.NoError(t, err)
 	req, err := http.NewRequestWithContext(context.Background(), "GET", oauthURL.String(), nil)
 	require.NoError(t, err)
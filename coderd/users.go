@@ -21,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/searchquery"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/userpassword"
@@ -1054,10 +1055,9 @@ func UpdateSiteUserRoles(ctx context.Context, db database.Store, args database.U
 		if _, ok := rbac.IsOrgRole(r); ok {
 			return database.User{}, xerrors.Errorf("Must only update site wide roles")
 		}
-
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.User{}, xerrors.Errorf("%q is not a supported role", r)
-		}
+	}
+	if err := rolestore.Validate(ctx, db, args.GrantedRoles); err != nil {
+		return database.User{}, err
 	}
 
 	updatedUser, err := db.UpdateUserRoles(ctx, args)
@@ -1271,7 +1271,7 @@ func usernameWithID(id uuid.UUID, users []database.User) (string, bool) {
 }
 
 func convertAPIKey(k database.APIKey) codersdk.APIKey {
-	return codersdk.APIKey{
+	key := codersdk.APIKey{
 		ID:              k.ID,
 		UserID:          k.UserID,
 		LastUsed:        k.LastUsed,
@@ -1283,4 +1283,15 @@ func convertAPIKey(k database.APIKey) codersdk.APIKey {
 		LifetimeSeconds: k.LifetimeSeconds,
 		TokenName:       k.TokenName,
 	}
+	if k.Scope == database.APIKeyScopeCustom {
+		key.ScopePermissions = db2sdk.Permissions(k.ScopePermissions)
+		for _, id := range k.ScopeAllowList {
+			// Only valid UUIDs are accepted when creating the key.
+			parsed, err := uuid.Parse(id)
+			if err == nil {
+				key.ScopeAllowList = append(key.ScopeAllowList, parsed)
+			}
+		}
+	}
+	return key
 }
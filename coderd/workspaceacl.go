@@ -0,0 +1,307 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace ACL
+// @ID get-workspace-acl
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceACL
+// @Router /workspaces/{workspace}/acl [get]
+func (api *API) workspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	// The caller can read the workspace if the function got this far, so we
+	// let them read the users and groups it has been shared with.
+	// nolint:gocritic
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	userIDs := make([]uuid.UUID, 0, len(workspace.UserACL))
+	for id := range workspace.UserACL {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+	}
+	users, err := api.Database.GetUsersByIDs(sysCtx, userIDs)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	acl := codersdk.WorkspaceACL{
+		Users:  make([]codersdk.WorkspaceUser, 0, len(users)),
+		Groups: make([]codersdk.WorkspaceGroup, 0, len(workspace.GroupACL)),
+	}
+	for _, user := range users {
+		acl.Users = append(acl.Users, codersdk.WorkspaceUser{
+			MinimalUser: codersdk.MinimalUser{
+				ID:        user.ID,
+				Username:  user.Username,
+				AvatarURL: user.AvatarURL,
+			},
+			Role: convertToWorkspaceRole(workspace.UserACL[user.ID.String()]),
+		})
+	}
+	for id, actions := range workspace.GroupACL {
+		groupID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		group, err := api.Database.GetGroupByID(sysCtx, groupID)
+		if httpapi.Is404Error(err) {
+			// The group has been deleted since the workspace was shared.
+			continue
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		acl.Groups = append(acl.Groups, codersdk.WorkspaceGroup{
+			ID:          group.ID,
+			Name:        group.Name,
+			DisplayName: group.DisplayName,
+			AvatarURL:   group.AvatarURL,
+			Role:        convertToWorkspaceRole(actions),
+		})
+	}
+	sort.Slice(acl.Users, func(i, j int) bool {
+		return acl.Users[i].Username < acl.Users[j].Username
+	})
+	sort.Slice(acl.Groups, func(i, j int) bool {
+		return acl.Groups[i].Name < acl.Groups[j].Name
+	})
+
+	httpapi.Write(ctx, rw, http.StatusOK, acl)
+}
+
+// @Summary Update workspace ACL
+// @ID update-workspace-acl
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceACL true "Update workspace ACL request"
+// @Success 200 {object} codersdk.Response
+// @Router /workspaces/{workspace}/acl [patch]
+func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	// Users the workspace has been shared with can read it, but only those
+	// who could have created it may change who it is shared with.
+	if !api.Authorize(r, rbac.ActionCreate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateWorkspaceACL
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	userRoles, validErrs := resolveWorkspaceACLRoles(ctx, api.Database, workspace, req.UserRoles, "user_roles", true)
+	groupRoles, groupErrs := resolveWorkspaceACLRoles(ctx, api.Database, workspace, req.GroupRoles, "group_roles", false)
+	validErrs = append(validErrs, groupErrs...)
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update workspace ACL.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		workspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by ID: %w", err)
+		}
+		if workspace.UserACL == nil {
+			workspace.UserACL = database.WorkspaceACL{}
+		}
+		if workspace.GroupACL == nil {
+			workspace.GroupACL = database.WorkspaceACL{}
+		}
+
+		for id, role := range userRoles {
+			// An empty role implies deletion.
+			if role == codersdk.WorkspaceRoleDeleted {
+				delete(workspace.UserACL, id)
+				continue
+			}
+			workspace.UserACL[id] = convertSDKWorkspaceRole(role)
+		}
+		for id, role := range groupRoles {
+			if role == codersdk.WorkspaceRoleDeleted {
+				delete(workspace.GroupACL, id)
+				continue
+			}
+			workspace.GroupACL[id] = convertSDKWorkspaceRole(role)
+		}
+
+		err = tx.UpdateWorkspaceACLByID(ctx, database.UpdateWorkspaceACLByIDParams{
+			ID:       workspace.ID,
+			UserACL:  workspace.UserACL,
+			GroupACL: workspace.GroupACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace ACL by ID: %w", err)
+		}
+		return nil
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = workspace
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Successfully updated workspace ACL list.",
+	})
+}
+
+// resolveWorkspaceACLRoles resolves every key to the ID of a user or group in
+// the workspace's organization, and checks every value is a valid workspace
+// role. Keys may be IDs or names, since the caller may not be able to read
+// other users.
+func resolveWorkspaceACLRoles(ctx context.Context, db database.Store, workspace database.Workspace, roles map[string]codersdk.WorkspaceRole, field string, isUser bool) (map[string]codersdk.WorkspaceRole, []codersdk.ValidationError) {
+	// Resolving requires full read access to users and groups
+	// nolint:gocritic
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	var (
+		resolved  = make(map[string]codersdk.WorkspaceRole, len(roles))
+		validErrs []codersdk.ValidationError
+	)
+	for k, v := range roles {
+		if v != codersdk.WorkspaceRoleDeleted && convertSDKWorkspaceRole(v) == nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Role %q is not a valid workspace role.", v)})
+			continue
+		}
+
+		var (
+			id    uuid.UUID
+			orgID uuid.UUID
+			err   error
+		)
+		if isUser {
+			var user database.User
+			if parsed, perr := uuid.Parse(k); perr == nil {
+				user, err = db.GetUserByID(ctx, parsed)
+			} else {
+				user, err = db.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+					Username: k,
+				})
+			}
+			id = user.ID
+			if err == nil {
+				_, err = db.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+					OrganizationID: workspace.OrganizationID,
+					UserID:         id,
+				})
+			}
+			orgID = workspace.OrganizationID
+		} else {
+			var group database.Group
+			if parsed, perr := uuid.Parse(k); perr == nil {
+				group, err = db.GetGroupByID(ctx, parsed)
+			} else {
+				group, err = db.GetGroupByOrgAndName(ctx, database.GetGroupByOrgAndNameParams{
+					OrganizationID: workspace.OrganizationID,
+					Name:           k,
+				})
+			}
+			id, orgID = group.ID, group.OrganizationID
+		}
+		if err == nil && orgID != workspace.OrganizationID {
+			err = sql.ErrNoRows
+		}
+		if err != nil {
+			if v == codersdk.WorkspaceRoleDeleted {
+				// Removing a user or group that no longer exists is fine.
+				resolved[k] = v
+				continue
+			}
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: fmt.Sprintf("Failed to find %q in the workspace's organization: %v", k, err.Error())})
+			continue
+		}
+		if isUser && id == workspace.OwnerID {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: field, Detail: "A workspace cannot be shared with its owner."})
+			continue
+		}
+		resolved[id.String()] = v
+	}
+
+	return resolved, validErrs
+}
+
+func convertToWorkspaceRole(actions []rbac.Action) codersdk.WorkspaceRole {
+	for _, role := range []codersdk.WorkspaceRole{
+		codersdk.WorkspaceRoleUse,
+		codersdk.WorkspaceRoleSSH,
+		codersdk.WorkspaceRoleApp,
+	} {
+		if slices.Equal(actions, convertSDKWorkspaceRole(role)) {
+			return role
+		}
+	}
+
+	return ""
+}
+
+// convertSDKWorkspaceRole returns the actions granted on the workspace by a
+// role. See database.WorkspaceACL for how these are applied.
+func convertSDKWorkspaceRole(role codersdk.WorkspaceRole) []rbac.Action {
+	switch role {
+	case codersdk.WorkspaceRoleUse:
+		return []rbac.Action{rbac.ActionRead, database.WorkspaceACLActionBuild, database.WorkspaceACLActionSSH, database.WorkspaceACLActionApp}
+	case codersdk.WorkspaceRoleSSH:
+		return []rbac.Action{rbac.ActionRead, database.WorkspaceACLActionSSH}
+	case codersdk.WorkspaceRoleApp:
+		return []rbac.Action{rbac.ActionRead, database.WorkspaceACLActionApp}
+	}
+
+	return nil
+}
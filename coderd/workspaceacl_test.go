@@ -0,0 +1,236 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceACL(t *testing.T) {
+	t.Parallel()
+
+	// setup creates a workspace owned by a member, and a second member to
+	// share it with.
+	setup := func(t *testing.T, opts *coderdtest.Options) (owner, sharee *codersdk.Client, shareeUser codersdk.User, workspace codersdk.Workspace) {
+		t.Helper()
+		if opts == nil {
+			opts = &coderdtest.Options{}
+		}
+		opts.IncludeProvisionerDaemon = true
+		client := coderdtest.New(t, opts)
+		first := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, first.OrganizationID, version.ID)
+
+		owner, _ = coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		sharee, shareeUser = coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+		workspace = coderdtest.CreateWorkspace(t, owner, first.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, owner, workspace.LatestBuild.ID)
+		return owner, sharee, shareeUser, workspace
+	}
+
+	connectChecks := func(workspace codersdk.Workspace) codersdk.AuthorizationRequest {
+		return codersdk.AuthorizationRequest{
+			Checks: map[string]codersdk.AuthorizationCheck{
+				"ssh": {
+					Object: codersdk.AuthorizationObject{
+						ResourceType: codersdk.ResourceWorkspaceExecution,
+						ResourceID:   workspace.ID.String(),
+					},
+					Action: "create",
+				},
+				"app": {
+					Object: codersdk.AuthorizationObject{
+						ResourceType: codersdk.ResourceWorkspaceApplicationConnect,
+						ResourceID:   workspace.ID.String(),
+					},
+					Action: "create",
+				},
+			},
+		}
+	}
+
+	t.Run("ShareSSH", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		owner, sharee, shareeUser, workspace := setup(t, &coderdtest.Options{Auditor: auditor})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The workspace is private until it is shared.
+		_, err := sharee.Workspace(ctx, workspace.ID)
+		require.Error(t, err)
+
+		auditor.ResetLogs()
+		err = owner.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				shareeUser.ID.String(): codersdk.WorkspaceRoleSSH,
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action:       database.AuditActionWrite,
+			ResourceType: database.ResourceTypeWorkspace,
+			ResourceID:   workspace.ID,
+		}))
+
+		acl, err := owner.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, shareeUser.ID, acl.Users[0].ID)
+		require.Equal(t, codersdk.WorkspaceRoleSSH, acl.Users[0].Role)
+
+		got, err := sharee.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, workspace.ID, got.ID)
+
+		got, err = sharee.WorkspaceByOwnerAndName(ctx, workspace.OwnerName, workspace.Name, codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+		require.Equal(t, workspace.ID, got.ID)
+
+		resp, err := sharee.AuthCheck(ctx, connectChecks(workspace))
+		require.NoError(t, err)
+		require.True(t, resp["ssh"], "sharee can ssh")
+		require.False(t, resp["app"], "sharee cannot open apps")
+
+		// Sharees cannot re-share the workspace.
+		err = sharee.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				shareeUser.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Nor can they stop it.
+		_, err = sharee.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.Error(t, err)
+
+		err = owner.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				shareeUser.ID.String(): codersdk.WorkspaceRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = sharee.Workspace(ctx, workspace.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("ShareUse", func(t *testing.T) {
+		t.Parallel()
+		owner, sharee, shareeUser, workspace := setup(t, nil)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Users can be referenced by username, since members cannot read
+		// other users to find their IDs.
+		err := owner.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				shareeUser.Username: codersdk.WorkspaceRoleUse,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := owner.WorkspaceACL(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, shareeUser.ID, acl.Users[0].ID)
+		require.Equal(t, codersdk.WorkspaceRoleUse, acl.Users[0].Role)
+
+		resp, err := sharee.AuthCheck(ctx, connectChecks(workspace))
+		require.NoError(t, err)
+		require.True(t, resp["ssh"], "sharee can ssh")
+		require.True(t, resp["app"], "sharee can open apps")
+
+		build, err := sharee.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, owner, build.ID)
+
+		_, err = sharee.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.Error(t, err, "sharees cannot delete the workspace")
+
+		// Sharees cannot change the workspace itself.
+		var apiErr *codersdk.Error
+		err = sharee.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{
+			Name: "renamed",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = sharee.UpdateWorkspaceAutostart(ctx, workspace.ID, codersdk.UpdateWorkspaceAutostartRequest{
+			Schedule: ptr.Ref("CRON_TZ=UTC 0 9 * * 1-5"),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = sharee.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
+			TTLMillis: ptr.Ref(time.Hour.Milliseconds()),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		owner, _, shareeUser, workspace := setup(t, nil)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, req := range []codersdk.UpdateWorkspaceACL{
+			{UserRoles: map[string]codersdk.WorkspaceRole{shareeUser.ID.String(): "admin"}},
+			{UserRoles: map[string]codersdk.WorkspaceRole{"not-a-user": codersdk.WorkspaceRoleUse}},
+			{UserRoles: map[string]codersdk.WorkspaceRole{uuid.NewString(): codersdk.WorkspaceRoleUse}},
+			{UserRoles: map[string]codersdk.WorkspaceRole{workspace.OwnerID.String(): codersdk.WorkspaceRoleUse}},
+			{GroupRoles: map[string]codersdk.WorkspaceRole{uuid.NewString(): codersdk.WorkspaceRoleUse}},
+		} {
+			err := owner.UpdateWorkspaceACL(ctx, workspace.ID, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+
+	t.Run("OtherOrganization", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		first := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, first.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, first.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, first.OrganizationID, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "other"})
+		require.NoError(t, err)
+		_, outsider := coderdtest.CreateAnotherUser(t, client, org.ID)
+
+		err = client.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				outsider.ID.String(): codersdk.WorkspaceRoleUse,
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -168,6 +168,7 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		resource  database.WorkspaceResource
 		build     database.WorkspaceBuild
 		workspace database.Workspace
+		template  database.Template
 		owner     database.User
 	)
 
@@ -208,6 +209,12 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		if err != nil {
 			return xerrors.Errorf("getting workspace owner by id: %w", err)
 		}
+		// nolint:gocritic // The agent cannot read the template, but needs
+		// to know whether sessions are recorded.
+		template, err = api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("getting template by id: %w", err)
+		}
 		return err
 	})
 	err = eg.Wait()
@@ -255,6 +262,7 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		MOTDFile:                 workspaceAgent.MOTDFile,
 		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		Metadata:                 convertWorkspaceAgentMetadataDesc(metadata),
+		RecordSessions:           api.DeploymentValues.RecordSessions.Value() || template.RecordSessions,
 	})
 }
 
@@ -882,6 +890,66 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusOK, portsResponse)
 }
 
+// @Summary Get reconnecting PTYs for workspace agent
+// @ID get-reconnecting-ptys-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentReconnectingPTYsResponse
+// @Router /workspaceagents/{workspaceagent}/reconnecting-ptys [get]
+func (api *API) workspaceAgentReconnectingPTYs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	// The sessions show what the owner is running, so require the access that
+	// is needed to observe them.
+	if _, ok := httpmw.UserAuthorizationOptional(r); !ok || !api.Authorize(r, rbac.ActionCreate, workspace.ApplicationConnectRBAC()) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	apiAgent, err := convertWorkspaceAgent(
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	agentConn, release, err := api.agentProvider.AgentConn(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	sessions, err := agentConn.ReconnectingPTYs(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching reconnecting PTYs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sessions)
+}
+
 // Deprecated: use api.tailnet.AgentConn instead.
 // See: https://github.com/coder/coder/issues/8218
 func (api *API) _dialWorkspaceAgentTailnet(agentID uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
@@ -2194,6 +2262,71 @@ func (api *API) workspaceAgentReportLifecycle(rw http.ResponseWriter, r *http.Re
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// @Summary Submit workspace agent script timing
+// @ID submit-workspace-agent-script-timing
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostScriptTimingRequest true "Script timing"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/script-timings [post]
+func (api *API) postWorkspaceAgentScriptTiming(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	var req agentsdk.PostScriptTimingRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	stage := database.WorkspaceAgentScriptTimingStage(req.Stage)
+	if !stage.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid script timing stage.",
+			Detail:  fmt.Sprintf("stage %q is not one of %v", req.Stage, database.AllWorkspaceAgentScriptTimingStageValues()),
+		})
+		return
+	}
+	status := database.WorkspaceAgentScriptTimingStatus(req.Status)
+	if !status.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid script timing status.",
+			Detail:  fmt.Sprintf("status %q is not one of %v", req.Status, database.AllWorkspaceAgentScriptTimingStatusValues()),
+		})
+		return
+	}
+
+	scripts, err := api.Database.GetWorkspaceAgentScriptsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if !slices.ContainsFunc(scripts, func(script database.WorkspaceAgentScript) bool {
+		return script.LogSourceID == req.LogSourceID
+	}) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Unknown script.",
+			Detail:  fmt.Sprintf("no script with log source %s exists for this agent", req.LogSourceID),
+		})
+		return
+	}
+
+	_, err = api.Database.InsertWorkspaceAgentScriptTiming(ctx, database.InsertWorkspaceAgentScriptTimingParams{
+		AgentID:     workspaceAgent.ID,
+		LogSourceID: req.LogSourceID,
+		Stage:       stage,
+		Status:      status,
+		ExitCode:    req.ExitCode,
+		StartedAt:   req.StartedAt,
+		EndedAt:     req.EndedAt,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // @Summary Submit workspace agent application health
 // @ID submit-workspace-agent-application-health
 // @Security CoderSessionToken
@@ -785,6 +785,85 @@ func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentReconnectingPTYs(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	first := coderdtest.CreateFirstUser(t, client)
+	owner, ownerUser := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+	observer, observerUser := coderdtest.CreateAnotherUser(t, client, first.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: first.OrganizationID,
+		OwnerID:        ownerUser.ID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, owner, r.Workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	// Sharing apps is enough to observe a terminal, but not to type into it.
+	err := owner.UpdateWorkspaceACL(ctx, r.Workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserRoles: map[string]codersdk.WorkspaceRole{
+			observerUser.ID.String(): codersdk.WorkspaceRoleApp,
+		},
+	})
+	require.NoError(t, err)
+
+	id := uuid.New()
+	ownerConn, err := owner.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+		AgentID:   agentID,
+		Reconnect: id,
+		Width:     80,
+		Height:    24,
+		Command:   "sh",
+	})
+	require.NoError(t, err)
+	defer ownerConn.Close()
+
+	var res codersdk.WorkspaceAgentReconnectingPTYsResponse
+	require.Eventually(t, func() bool {
+		res, err = observer.WorkspaceAgentReconnectingPTYs(ctx, agentID)
+		return assert.NoError(t, err) && len(res.Sessions) == 1 && res.Sessions[0].Connections == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Equal(t, id, res.Sessions[0].ID)
+	require.Equal(t, "sh", res.Sessions[0].Command)
+	require.Zero(t, res.Sessions[0].Observers)
+
+	for _, mode := range []codersdk.ReconnectingPTYMode{codersdk.ReconnectingPTYModeOwner, codersdk.ReconnectingPTYModeCollaborator} {
+		_, err = observer.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+			AgentID:   agentID,
+			Reconnect: id,
+			Mode:      mode,
+		})
+		require.Error(t, err, mode)
+	}
+
+	observerConn, err := observer.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+		AgentID:   agentID,
+		Reconnect: id,
+		Mode:      codersdk.ReconnectingPTYModeObserver,
+	})
+	require.NoError(t, err)
+	defer observerConn.Close()
+
+	require.Eventually(t, func() bool {
+		res, err = owner.WorkspaceAgentReconnectingPTYs(ctx, agentID)
+		return assert.NoError(t, err) && len(res.Sessions) == 1 && res.Sessions[0].Observers == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+		Data: "echo owner-$((1+1))\r",
+	})
+	require.NoError(t, err)
+	_, err = ownerConn.Write(data)
+	require.NoError(t, err)
+	tr := testutil.NewTerminalReader(t, observerConn)
+	require.NoError(t, tr.ReadUntilString(ctx, "owner-2"))
+}
+
 func TestWorkspaceAgentAppHealth(t *testing.T) {
 	t.Parallel()
 	client, db := coderdtest.NewWithDatabase(t, nil)
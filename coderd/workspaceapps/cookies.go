@@ -49,3 +49,17 @@ func AppConnectSessionTokenFromRequest(r *http.Request, accessMethod AccessMetho
 
 	return ""
 }
+
+// PortShareTokenFromRequest returns the port share token from the request if
+// it exists. Port share links only exist for ports on subdomains, so the cookie
+// is ignored for any other access method.
+func PortShareTokenFromRequest(r *http.Request, appReq Request) string {
+	if appReq.AccessMethod != AccessMethodSubdomain {
+		return ""
+	}
+	cookie, err := r.Cookie(codersdk.PortShareTokenCookie)
+	if err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+	return ""
+}
@@ -7,9 +7,11 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
@@ -120,6 +122,14 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "verify authz")
 		return nil, "", false
 	}
+	var portShareExpiry time.Time
+	if !authed && issueReq.PortShareToken != "" {
+		authed, portShareExpiry, err = p.authorizePortShare(dangerousSystemCtx, issueReq.PortShareToken, dbReq)
+		if err != nil {
+			WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "verify port share")
+			return nil, "", false
+		}
+	}
 	if !authed {
 		if apiKey != nil {
 			// The request has a valid API key but insufficient permissions.
@@ -210,6 +220,9 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 
 	// Sign the token.
 	token.Expiry = time.Now().Add(DefaultTokenExpiry)
+	if !portShareExpiry.IsZero() && portShareExpiry.Before(token.Expiry) {
+		token.Expiry = portShareExpiry
+	}
 	tokenStr, err := p.SigningKey.SignToken(token)
 	if err != nil {
 		WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "generate token")
@@ -219,6 +232,36 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 	return &token, tokenStr, true
 }
 
+// authorizePortShare returns true if the port share token was signed by us,
+// matches the requested port and the share has not been revoked. The share's
+// expiry is returned so the issued token does not outlive it.
+func (p *DBTokenProvider) authorizePortShare(ctx context.Context, tokenStr string, dbReq *databaseRequest) (bool, time.Time, error) {
+	shareToken, err := p.SigningKey.VerifySignedToken(tokenStr)
+	if err != nil || shareToken.PortShareID == uuid.Nil {
+		return false, time.Time{}, nil
+	}
+
+	share, err := p.Database.GetWorkspacePortShareByID(ctx, shareToken.PortShareID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// The share has been revoked.
+		return false, time.Time{}, nil
+	}
+	if err != nil {
+		return false, time.Time{}, xerrors.Errorf("get workspace port share: %w", err)
+	}
+
+	port, err := strconv.ParseUint(dbReq.AppSlugOrPort, 10, 16)
+	if err != nil ||
+		uint64(share.Port) != port ||
+		share.WorkspaceID != dbReq.Workspace.ID ||
+		share.AgentName != dbReq.Agent.Name ||
+		!share.ExpiresAt.After(time.Now()) {
+		return false, time.Time{}, nil
+	}
+
+	return true, share.ExpiresAt, nil
+}
+
 // authorizeRequest returns true/false if the request is authorized. The returned []string
 // are warnings that aid in debugging. These messages do not prevent authorization,
 // but may indicate that the request is not configured correctly.
@@ -278,7 +321,7 @@ func (p *DBTokenProvider) authorizeRequest(ctx context.Context, roles *httpmw.Au
 	}
 
 	// Figure out which RBAC resource to check. For terminals we use execution
-	// instead of application connect.
+	// instead of application connect, unless the terminal is only observed.
 	var (
 		rbacAction   rbac.Action = rbac.ActionCreate
 		rbacResource rbac.Object = dbReq.Workspace.ApplicationConnectRBAC()
@@ -287,7 +330,7 @@ func (p *DBTokenProvider) authorizeRequest(ctx context.Context, roles *httpmw.Au
 		// workspace. Scopes would prevent this.
 		rbacResourceOwned rbac.Object = rbac.ResourceWorkspaceApplicationConnect.WithOwner(roles.Actor.ID)
 	)
-	if dbReq.AccessMethod == AccessMethodTerminal {
+	if dbReq.AccessMethod == AccessMethodTerminal && dbReq.TerminalMode.CanWrite() {
 		rbacResource = dbReq.Workspace.ExecutionRBAC()
 		rbacResourceOwned = rbac.ResourceWorkspaceExecution.WithOwner(roles.Actor.ID)
 	}
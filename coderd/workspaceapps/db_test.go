@@ -621,6 +621,65 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Equal(t, "http://127.0.0.1:9090", token.AppURL)
 	})
 
+	t.Run("PortShare", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		share, err := client.CreateWorkspacePortShare(ctx, workspace.ID, codersdk.CreateWorkspacePortShareRequest{
+			AgentName: agentName,
+			Port:      9091,
+			TTLMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		shareURL, err := url.Parse(share.URL)
+		require.NoError(t, err)
+		shareToken := shareURL.Query().Get(codersdk.PortShareTokenQueryParameter)
+		require.NotEmpty(t, shareToken)
+
+		resolve := func(port string, cookie *http.Cookie) (*workspaceapps.SignedToken, bool) {
+			req := (workspaceapps.Request{
+				AccessMethod:      workspaceapps.AccessMethodSubdomain,
+				BasePath:          "/",
+				UsernameOrID:      me.Username,
+				WorkspaceNameOrID: workspace.Name,
+				AgentNameOrID:     agentName,
+				AppSlugOrPort:     port,
+			}).Normalize()
+
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			r.AddCookie(cookie)
+			return workspaceapps.ResolveRequest(rw, r, workspaceapps.ResolveRequestOptions{
+				Logger:              api.Logger,
+				SignedTokenProvider: api.WorkspaceAppsProvider,
+				DashboardURL:        api.AccessURL,
+				PathAppBaseURL:      api.AccessURL,
+				AppHostname:         api.AppHostname,
+				AppRequest:          req,
+			})
+		}
+		shareCookie := &http.Cookie{Name: codersdk.PortShareTokenCookie, Value: shareToken}
+
+		token, ok := resolve("9091", shareCookie)
+		require.True(t, ok)
+		require.Equal(t, "http://127.0.0.1:9091", token.AppURL)
+		require.Equal(t, uuid.Nil, token.PortShareID)
+		require.False(t, token.Expiry.After(share.ExpiresAt))
+
+		// The link only grants access to the shared port.
+		_, ok = resolve("9092", shareCookie)
+		require.False(t, ok)
+
+		// The share token cannot be used directly as an app token.
+		_, ok = resolve("9091", &http.Cookie{Name: codersdk.SignedAppTokenCookie, Value: shareToken})
+		require.False(t, ok)
+
+		err = client.DeleteWorkspacePortShare(ctx, workspace.ID, share.ID)
+		require.NoError(t, err)
+		_, ok = resolve("9091", shareCookie)
+		require.False(t, ok)
+	})
+
 	t.Run("Terminal", func(t *testing.T) {
 		t.Parallel()
 
@@ -58,6 +58,7 @@ func ResolveRequest(rw http.ResponseWriter, r *http.Request, opts ResolveRequest
 		PathAppBaseURL: opts.PathAppBaseURL.String(),
 		AppHostname:    opts.AppHostname,
 		SessionToken:   AppConnectSessionTokenFromRequest(r, appReq.AccessMethod),
+		PortShareToken: PortShareTokenFromRequest(r, appReq),
 		AppPath:        opts.AppPath,
 		AppQuery:       opts.AppQuery,
 	}
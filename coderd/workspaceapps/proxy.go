@@ -253,6 +253,63 @@ func (s *Server) handleAPIKeySmuggling(rw http.ResponseWriter, r *http.Request,
 	return false
 }
 
+// handlePortShareToken is called by the subdomain handler to exchange a port
+// share token in the query parameters for a cookie on the port's subdomain.
+// The token is only checked for a valid signature here; whether the share is
+// still active is checked each time an app token is issued.
+//
+// If false is returned, the request has been handled and the caller should not
+// continue.
+func (s *Server) handlePortShareToken(rw http.ResponseWriter, r *http.Request) bool {
+	ctx := r.Context()
+
+	tokenStr := r.URL.Query().Get(codersdk.PortShareTokenQueryParameter)
+	if tokenStr == "" {
+		return true
+	}
+
+	token, err := s.AppSecurityKey.VerifySignedToken(tokenStr)
+	if err != nil || token.PortShareID == uuid.Nil {
+		s.Logger.Debug(ctx, "could not verify port share token", slog.Error(err))
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:      http.StatusBadRequest,
+			Title:       "Bad Request",
+			Description: "This share link is invalid or has expired. Please ask for a new link.",
+			// Retry is disabled because the user needs a new link.
+			RetryEnabled: false,
+			DashboardURL: s.DashboardURL.String(),
+		})
+		return false
+	}
+
+	// The cookie is only set on the current subdomain, since the link only
+	// grants access to a single port.
+	http.SetCookie(rw, &http.Cookie{
+		Name:     codersdk.PortShareTokenCookie,
+		Value:    tokenStr,
+		Path:     "/",
+		Expires:  token.Expiry,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   s.SecureAuthCookie,
+	})
+
+	// Strip the query parameter.
+	path := r.URL.Path
+	if path == "" {
+		path = "/"
+	}
+	q := r.URL.Query()
+	q.Del(codersdk.PortShareTokenQueryParameter)
+	rawQuery := q.Encode()
+	if rawQuery != "" {
+		path += "?" + rawQuery
+	}
+
+	http.Redirect(rw, r, path, http.StatusSeeOther)
+	return false
+}
+
 // workspaceAppsProxyPath proxies requests to a workspace application
 // through a relative URL path.
 func (s *Server) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request) {
@@ -398,6 +455,9 @@ func (s *Server) HandleSubdomain(middlewares ...func(http.Handler) http.Handler)
 				if !s.handleAPIKeySmuggling(rw, r, AccessMethodSubdomain) {
 					return
 				}
+				if !s.handlePortShareToken(rw, r) {
+					return
+				}
 
 				token, ok := ResolveRequest(rw, r, ResolveRequestOptions{
 					Logger:              s.Logger,
@@ -629,6 +689,20 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	s.websocketWaitMutex.Unlock()
 	defer s.websocketWaitGroup.Done()
 
+	// The mode is part of the app request, since observing a terminal needs
+	// less access than typing into it.
+	mode := codersdk.ReconnectingPTYMode(r.URL.Query().Get("mode"))
+	if !mode.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid query parameters.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "mode",
+				Detail: fmt.Sprintf("Mode must be one of %q, %q or %q.", codersdk.ReconnectingPTYModeOwner, codersdk.ReconnectingPTYModeCollaborator, codersdk.ReconnectingPTYModeObserver),
+			}},
+		})
+		return
+	}
+
 	appToken, ok := ResolveRequest(rw, r, ResolveRequestOptions{
 		Logger:              s.Logger,
 		SignedTokenProvider: s.SignedTokenProvider,
@@ -639,6 +713,7 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 			AccessMethod:  AccessMethodTerminal,
 			BasePath:      r.URL.Path,
 			AgentNameOrID: chi.URLParam(r, "workspaceagent"),
+			TerminalMode:  mode,
 		},
 		AppPath:  "",
 		AppQuery: "",
@@ -692,7 +767,7 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	}
 	defer release()
 	log.Debug(ctx, "dialed workspace agent")
-	ptNetConn, err := agentConn.ReconnectingPTY(ctx, reconnect, uint16(height), uint16(width), r.URL.Query().Get("command"))
+	ptNetConn, err := agentConn.ReconnectingPTY(ctx, reconnect, uint16(height), uint16(width), r.URL.Query().Get("command"), codersdk.WithReconnectingPTYMode(mode))
 	if err != nil {
 		log.Debug(ctx, "dial reconnecting pty server in workspace agent", slog.Error(err))
 		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("dial: %s", err))
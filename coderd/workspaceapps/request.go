@@ -40,6 +40,9 @@ type IssueTokenRequest struct {
 	AppQuery string `json:"app_query"`
 	// SessionToken is the session token provided by the user.
 	SessionToken string `json:"session_token"`
+	// PortShareToken is the token from a port share link, if the user opened
+	// one. It authorizes the request in place of a session token.
+	PortShareToken string `json:"port_share_token,omitempty"`
 }
 
 // AppBaseURL returns the base URL of this specific app request. An error is
@@ -98,6 +101,9 @@ type Request struct {
 	// AgentNameOrID is not required if the workspace has only one agent.
 	AgentNameOrID string `json:"agent_name_or_id"`
 	AppSlugOrPort string `json:"app_slug_or_port"`
+	// TerminalMode is the mode the terminal is opened with. It is only set for
+	// AccessMethodTerminal, and is empty for the owner.
+	TerminalMode codersdk.ReconnectingPTYMode `json:"terminal_mode,omitempty"`
 }
 
 // Normalize replaces WorkspaceAndAgent with WorkspaceNameOrID and
@@ -145,6 +151,9 @@ func (r Request) Validate() error {
 		if r.AgentNameOrID == "" {
 			return xerrors.New("agent name or ID is required")
 		}
+		if !r.TerminalMode.Valid() {
+			return xerrors.Errorf("invalid terminal mode %q", r.TerminalMode)
+		}
 		if _, err := uuid.Parse(r.AgentNameOrID); err != nil {
 			return xerrors.Errorf("invalid agent name or ID %q, must be a UUID: %w", r.AgentNameOrID, err)
 		}
@@ -152,6 +161,9 @@ func (r Request) Validate() error {
 		return nil
 	}
 
+	if r.TerminalMode != "" {
+		return xerrors.New("terminal mode is only valid for terminals")
+	}
 	if r.UsernameOrID == "" {
 		return xerrors.New("username or ID is required")
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 func Test_RequestValidate(t *testing.T) {
@@ -249,6 +250,38 @@ func Test_RequestValidate(t *testing.T) {
 			},
 			errContains: `invalid agent name or ID "baz", must be a UUID`,
 		},
+		{
+			name: "Terminal/TerminalMode/Observer",
+			req: workspaceapps.Request{
+				AccessMethod:  workspaceapps.AccessMethodTerminal,
+				BasePath:      "/",
+				AgentNameOrID: uuid.New().String(),
+				TerminalMode:  codersdk.ReconnectingPTYModeObserver,
+			},
+		},
+		{
+			name: "Terminal/TerminalMode/Invalid",
+			req: workspaceapps.Request{
+				AccessMethod:  workspaceapps.AccessMethodTerminal,
+				BasePath:      "/",
+				AgentNameOrID: uuid.New().String(),
+				TerminalMode:  "spectator",
+			},
+			errContains: `invalid terminal mode "spectator"`,
+		},
+		{
+			name: "TerminalMode/NotTerminal",
+			req: workspaceapps.Request{
+				AccessMethod:      workspaceapps.AccessMethodPath,
+				BasePath:          "/",
+				UsernameOrID:      "foo",
+				WorkspaceNameOrID: "bar",
+				AgentNameOrID:     "baz",
+				AppSlugOrPort:     "qux",
+				TerminalMode:      codersdk.ReconnectingPTYModeObserver,
+			},
+			errContains: "terminal mode is only valid for terminals",
+		},
 	}
 
 	for _, c := range cases {
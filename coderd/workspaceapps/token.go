@@ -34,6 +34,9 @@ type SignedToken struct {
 	WorkspaceID uuid.UUID `json:"workspace_id"`
 	AgentID     uuid.UUID `json:"agent_id"`
 	AppURL      string    `json:"app_url"`
+	// PortShareID is set on tokens that back a port share link. These tokens
+	// are only accepted by the token issuer, never directly for app access.
+	PortShareID uuid.UUID `json:"port_share_id,omitempty"`
 }
 
 // MatchesRequest returns true if the token matches the request. Any token that
@@ -54,7 +57,8 @@ func (t SignedToken) MatchesRequest(req Request) bool {
 		t.UsernameOrID == req.UsernameOrID &&
 		t.WorkspaceNameOrID == req.WorkspaceNameOrID &&
 		t.AgentNameOrID == req.AgentNameOrID &&
-		t.AppSlugOrPort == req.AppSlugOrPort
+		t.AppSlugOrPort == req.AppSlugOrPort &&
+		t.TerminalMode == req.TerminalMode
 }
 
 // SecurityKey is used for signing and encrypting app tokens and API keys.
@@ -276,6 +280,12 @@ func FromRequest(r *http.Request, key SecurityKey) (*SignedToken, bool) {
 				return nil, false
 			}
 
+			if token.PortShareID != uuid.Nil {
+				// Port share tokens must be exchanged for an app token so
+				// revocation is checked.
+				continue
+			}
+
 			err := req.Validate()
 			if err == nil {
 				// The request has a valid signed app token, which is a valid
@@ -222,6 +222,23 @@ func Test_TokenMatchesRequest(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "DifferentTerminalMode",
+			req: workspaceapps.Request{
+				AccessMethod:  workspaceapps.AccessMethodTerminal,
+				BasePath:      "/",
+				AgentNameOrID: "baz",
+			},
+			token: workspaceapps.SignedToken{
+				Request: workspaceapps.Request{
+					AccessMethod:  workspaceapps.AccessMethodTerminal,
+					BasePath:      "/",
+					AgentNameOrID: "baz",
+					TerminalMode:  codersdk.ReconnectingPTYModeObserver,
+				},
+			},
+			want: false,
+		},
 	}
 
 	for _, c := range cases {
@@ -348,6 +348,10 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		builder = builder.VersionID(createBuild.TemplateVersionID)
 	}
 
+	if createBuild.Priority != "" {
+		builder = builder.Priority(database.ProvisionerJobPriority(createBuild.Priority))
+	}
+
 	if createBuild.Orphan {
 		if createBuild.Transition != codersdk.WorkspaceTransitionDelete {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -405,7 +409,10 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
 	}
 
-	users, err := api.Database.GetUsersByIDs(ctx, []uuid.UUID{
+	// Users the workspace has been shared with may not be able to read the
+	// owner, but they can see their name on the workspace.
+	// nolint:gocritic
+	users, err := api.Database.GetUsersByIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{
 		workspace.OwnerID,
 		workspaceBuild.InitiatorID,
 	})
@@ -664,6 +671,124 @@ func (api *API) workspaceBuildState(rw http.ResponseWriter, r *http.Request) {
 	_, _ = rw.Write(workspaceBuild.ProvisionerState)
 }
 
+// @Summary Get workspace build timings
+// @ID get-workspace-build-timings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBuildTimings
+// @Router /workspacebuilds/{workspacebuild}/timings [get]
+func (api *API) workspaceBuildTimings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceBuild := httpmw.WorkspaceBuildParam(r)
+
+	provisionerTimings, err := api.Database.GetProvisionerJobTimingsByJobID(ctx, workspaceBuild.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner timings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The workspace has already been authorized by the route middleware.
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(ctx, workspaceBuild.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	agentIDs := make([]uuid.UUID, 0, len(agents))
+	for _, agent := range agents {
+		agentIDs = append(agentIDs, agent.ID)
+	}
+	scriptTimings, err := api.Database.GetWorkspaceAgentScriptTimingsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching agent script timings.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	logSources, err := api.Database.GetWorkspaceAgentLogSourcesByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching agent log sources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceBuildTimings(provisionerTimings, agents, scriptTimings, logSources))
+}
+
+func convertWorkspaceBuildTimings(provisionerTimings []database.ProvisionerJobTiming, agents []database.WorkspaceAgent, scriptTimings []database.WorkspaceAgentScriptTiming, logSources []database.WorkspaceAgentLogSource) codersdk.WorkspaceBuildTimings {
+	res := codersdk.WorkspaceBuildTimings{
+		ProvisionerTimings:     make([]codersdk.ProvisionerTiming, 0, len(provisionerTimings)),
+		AgentScriptTimings:     make([]codersdk.AgentScriptTiming, 0, len(scriptTimings)),
+		AgentConnectionTimings: make([]codersdk.AgentConnectionTiming, 0, len(agents)),
+	}
+	for _, timing := range provisionerTimings {
+		res.ProvisionerTimings = append(res.ProvisionerTimings, codersdk.ProvisionerTiming{
+			JobID:     timing.JobID,
+			StartedAt: timing.StartedAt,
+			EndedAt:   timing.EndedAt,
+			Stage:     codersdk.ProvisionerTimingStage(timing.Stage),
+			Source:    timing.Source,
+			Action:    timing.Action,
+			Resource:  timing.Resource,
+		})
+	}
+
+	agentNames := make(map[uuid.UUID]string, len(agents))
+	for _, agent := range agents {
+		agentNames[agent.ID] = agent.Name
+		// Agents that haven't connected yet don't have a span.
+		if !agent.FirstConnectedAt.Valid {
+			continue
+		}
+		res.AgentConnectionTimings = append(res.AgentConnectionTimings, codersdk.AgentConnectionTiming{
+			StartedAt:          agent.CreatedAt,
+			EndedAt:            agent.FirstConnectedAt.Time,
+			WorkspaceAgentID:   agent.ID,
+			WorkspaceAgentName: agent.Name,
+		})
+	}
+
+	displayNames := make(map[uuid.UUID]string, len(logSources))
+	for _, logSource := range logSources {
+		displayNames[logSource.ID] = logSource.DisplayName
+	}
+	for _, timing := range scriptTimings {
+		res.AgentScriptTimings = append(res.AgentScriptTimings, codersdk.AgentScriptTiming{
+			StartedAt:          timing.StartedAt,
+			EndedAt:            timing.EndedAt,
+			ExitCode:           timing.ExitCode,
+			Stage:              codersdk.WorkspaceAgentScriptTimingStage(timing.Stage),
+			Status:             codersdk.WorkspaceAgentScriptTimingStatus(timing.Status),
+			DisplayName:        displayNames[timing.LogSourceID],
+			WorkspaceAgentID:   timing.AgentID,
+			WorkspaceAgentName: agentNames[timing.AgentID],
+		})
+	}
+	return res
+}
+
 type workspaceBuildsData struct {
 	users            []database.User
 	jobs             []database.GetProvisionerJobsByIDsWithQueuePositionRow
@@ -681,7 +806,10 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 	for _, workspace := range workspaces {
 		userIDs = append(userIDs, workspace.OwnerID)
 	}
-	users, err := api.Database.GetUsersByIDs(ctx, userIDs)
+	// The caller can read the workspaces, which includes the owner's name
+	// even when the workspace was shared with them by another user.
+	// nolint:gocritic
+	users, err := api.Database.GetUsersByIDs(dbauthz.AsSystemRestricted(ctx), userIDs)
 	if err != nil {
 		return workspaceBuildsData{}, xerrors.Errorf("get users: %w", err)
 	}
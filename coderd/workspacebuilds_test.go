@@ -24,6 +24,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
@@ -508,6 +509,95 @@ func TestWorkspaceBuildResources(t *testing.T) {
 	})
 }
 
+func TestWorkspaceBuildTimings(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	applyStart := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.PlanComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id:   uuid.NewString(),
+							Name: "dev",
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+							Scripts: []*proto.Script{{
+								DisplayName: "Install tools",
+								Script:      "echo hello",
+								RunOnStart:  true,
+							}},
+						}},
+					}},
+					Timings: []*proto.Timing{{
+						StartedAt: applyStart.UnixMilli(),
+						EndedAt:   applyStart.Add(5 * time.Second).UnixMilli(),
+						Stage:     "apply",
+						Source:    "aws",
+						Action:    "create",
+						Resource:  "aws_instance.example",
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	agent := build.Resources[0].Agents[0]
+	require.Len(t, agent.Scripts, 1)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	err := agentClient.PostScriptTiming(ctx, agentsdk.PostScriptTimingRequest{
+		LogSourceID: uuid.New(),
+		Stage:       codersdk.WorkspaceAgentScriptTimingStageStart,
+		Status:      codersdk.WorkspaceAgentScriptTimingStatusOK,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	scriptStart := time.Now().Truncate(time.Millisecond)
+	err = agentClient.PostScriptTiming(ctx, agentsdk.PostScriptTimingRequest{
+		LogSourceID: agent.Scripts[0].LogSourceID,
+		Stage:       codersdk.WorkspaceAgentScriptTimingStageStart,
+		Status:      codersdk.WorkspaceAgentScriptTimingStatusExitFailure,
+		ExitCode:    2,
+		StartedAt:   scriptStart,
+		EndedAt:     scriptStart.Add(time.Second),
+	})
+	require.NoError(t, err)
+
+	timings, err := client.WorkspaceBuildTimings(ctx, build.ID)
+	require.NoError(t, err)
+	require.Len(t, timings.ProvisionerTimings, 1)
+	require.Equal(t, codersdk.ProvisionerTimingStageApply, timings.ProvisionerTimings[0].Stage)
+	require.Equal(t, "aws_instance.example", timings.ProvisionerTimings[0].Resource)
+	require.Equal(t, 5*time.Second, timings.ProvisionerTimings[0].EndedAt.Sub(timings.ProvisionerTimings[0].StartedAt))
+
+	require.Len(t, timings.AgentScriptTimings, 1)
+	scriptTiming := timings.AgentScriptTimings[0]
+	require.Equal(t, "Install tools", scriptTiming.DisplayName)
+	require.Equal(t, "dev", scriptTiming.WorkspaceAgentName)
+	require.Equal(t, codersdk.WorkspaceAgentScriptTimingStatusExitFailure, scriptTiming.Status)
+	require.EqualValues(t, 2, scriptTiming.ExitCode)
+
+	// The agent hasn't connected, so there's no connection span yet.
+	require.Empty(t, timings.AgentConnectionTimings)
+}
+
 func TestWorkspaceBuildLogs(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -994,6 +1084,36 @@ func TestPostWorkspaceBuild(t *testing.T) {
 		require.Equal(t, workspace.LatestBuild.BuildNumber+1, build.BuildNumber)
 	})
 
+	t.Run("Priority", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.ProvisionerJobPriorityUser, workspace.LatestBuild.Job.Priority)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+			Priority:   codersdk.ProvisionerJobPriorityAutobuild,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+			Priority:   codersdk.ProvisionerJobPriorityBatch,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobPriorityBatch, build.Job.Priority)
+	})
+
 	t.Run("WithState", func(t *testing.T) {
 		t.Parallel()
 		client, closeDaemon := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
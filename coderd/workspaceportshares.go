@@ -0,0 +1,287 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxWorkspacePortShareTTL is the longest a port share link may be valid for.
+const maxWorkspacePortShareTTL = 30 * 24 * time.Hour
+
+// @Summary Get workspace port shares
+// @ID get-workspace-port-shares
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspacePortShare
+// @Router /workspaces/{workspace}/port-shares [get]
+func (api *API) workspacePortShares(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	// Share links expose the workspace's ports to anyone, so managing them
+	// requires more than access to the workspace's apps.
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	shares, err := api.Database.GetWorkspacePortSharesByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	owner, err := api.workspacePortShareOwner(r, workspace)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	res := make([]codersdk.WorkspacePortShare, 0, len(shares))
+	for _, share := range shares {
+		converted, err := api.convertWorkspacePortShare(share, workspace, owner)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		res = append(res, converted)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Create workspace port share
+// @ID create-workspace-port-share
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspacePortShareRequest true "Create port share request"
+// @Success 201 {object} codersdk.WorkspacePortShare
+// @Router /workspaces/{workspace}/port-shares [post]
+func (api *API) postWorkspacePortShare(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		apiKey    = httpmw.APIKey(r)
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateWorkspacePortShareRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// Ports are only served on subdomains, so there's nothing to link to
+	// without a wildcard app hostname.
+	if api.AppHostname == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Port sharing requires a wildcard access URL to be configured.",
+		})
+		return
+	}
+
+	var validErrs []codersdk.ValidationError
+	// Lower ports are reserved, and refused by the app proxy.
+	if req.Port < codersdk.WorkspaceAgentMinimumListeningPort || req.Port > 65535 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "port", Detail: fmt.Sprintf("Port must be between %d and 65535.", codersdk.WorkspaceAgentMinimumListeningPort)})
+	}
+	ttl := time.Duration(req.TTLMillis) * time.Millisecond
+	if ttl < time.Minute || ttl > maxWorkspacePortShareTTL {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "ttl_ms", Detail: fmt.Sprintf("TTL must be between 1 minute and %s.", maxWorkspacePortShareTTL)})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to create port share.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !httpapi.Is404Error(err) {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	found := false
+	for _, agent := range agents {
+		if agent.Name == req.AgentName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent %q does not exist in the workspace.", req.AgentName),
+			Validations: []codersdk.ValidationError{
+				{Field: "agent_name", Detail: "Agent does not exist."},
+			},
+		})
+		return
+	}
+
+	share, err := api.Database.InsertWorkspacePortShare(ctx, database.InsertWorkspacePortShareParams{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+		AgentName:   req.AgentName,
+		Port:        req.Port,
+		CreatedBy:   apiKey.UserID,
+		CreatedAt:   dbtime.Now(),
+		ExpiresAt:   dbtime.Now().Add(ttl),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	owner, err := api.workspacePortShareOwner(r, workspace)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	res, err := api.convertWorkspacePortShare(share, workspace, owner)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, res)
+}
+
+// @Summary Delete workspace port share
+// @ID delete-workspace-port-share
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param portshare path string true "Port share ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /workspaces/{workspace}/port-shares/{portshare} [delete]
+func (api *API) deleteWorkspacePortShare(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	shareID, ok := httpmw.ParseUUIDParam(rw, r, "portshare")
+	if !ok {
+		return
+	}
+
+	share, err := api.Database.GetWorkspacePortShareByID(ctx, shareID)
+	if httpapi.Is404Error(err) || (err == nil && share.WorkspaceID != workspace.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	err = api.Database.DeleteWorkspacePortShareByID(ctx, share.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Port share link revoked.",
+	})
+}
+
+// workspacePortShareOwner returns the owner of the workspace, which is needed
+// for the port's subdomain.
+func (api *API) workspacePortShareOwner(r *http.Request, workspace database.Workspace) (database.User, error) {
+	// The workspace may have been shared with the caller, who cannot read the
+	// owner.
+	// nolint:gocritic
+	owner, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(r.Context()), workspace.OwnerID)
+	if err != nil {
+		return database.User{}, xerrors.Errorf("get workspace owner: %w", err)
+	}
+	return owner, nil
+}
+
+// convertWorkspacePortShare mints the link for a port share. The link carries
+// a signed token rather than a secret stored in the database, so it can be
+// recreated whenever the share is listed. The token only identifies the share;
+// it is checked against the database each time it is used, so deleting the
+// share revokes the link.
+func (api *API) convertWorkspacePortShare(share database.WorkspacePortShare, workspace database.Workspace, owner database.User) (codersdk.WorkspacePortShare, error) {
+	port := strconv.Itoa(int(share.Port))
+	token, err := api.AppSecurityKey.SignToken(workspaceapps.SignedToken{
+		Request: workspaceapps.Request{
+			AccessMethod:      workspaceapps.AccessMethodSubdomain,
+			BasePath:          "/",
+			UsernameOrID:      owner.Username,
+			WorkspaceNameOrID: workspace.Name,
+			AgentNameOrID:     share.AgentName,
+			AppSlugOrPort:     port,
+		},
+		Expiry:      share.ExpiresAt,
+		UserID:      owner.ID,
+		WorkspaceID: workspace.ID,
+		PortShareID: share.ID,
+	})
+	if err != nil {
+		return codersdk.WorkspacePortShare{}, xerrors.Errorf("sign port share token: %w", err)
+	}
+
+	appHost := httpapi.ApplicationURL{
+		AppSlugOrPort: port,
+		AgentName:     share.AgentName,
+		WorkspaceName: workspace.Name,
+		Username:      owner.Username,
+	}
+	host := strings.ReplaceAll(api.AppHostname, "*", appHost.String())
+	if api.AccessURL.Port() != "" {
+		host += ":" + api.AccessURL.Port()
+	}
+	u := url.URL{
+		Scheme:   api.AccessURL.Scheme,
+		Host:     host,
+		Path:     "/",
+		RawQuery: url.Values{codersdk.PortShareTokenQueryParameter: {token}}.Encode(),
+	}
+
+	return codersdk.WorkspacePortShare{
+		ID:          share.ID,
+		WorkspaceID: share.WorkspaceID,
+		AgentName:   share.AgentName,
+		Port:        share.Port,
+		CreatedBy:   share.CreatedBy,
+		CreatedAt:   share.CreatedAt,
+		ExpiresAt:   share.ExpiresAt,
+		URL:         u.String(),
+	}, nil
+}
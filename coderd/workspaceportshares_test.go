@@ -0,0 +1,148 @@
+package coderd_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspacePortShares(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, appHostname string) (*codersdk.Client, codersdk.Workspace, string) {
+		t.Helper()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			AppHostname:              appHostname,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.PlanComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		// The agent is named by echo.ProvisionApplyWithAgent.
+		return client, workspace, "example"
+	}
+
+	t.Run("CreateListRevoke", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentName := setup(t, "*.apps.coder.test")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		share, err := client.CreateWorkspacePortShare(ctx, workspace.ID, codersdk.CreateWorkspacePortShareRequest{
+			AgentName: agentName,
+			Port:      8080,
+			TTLMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, agentName, share.AgentName)
+		require.EqualValues(t, 8080, share.Port)
+		require.WithinDuration(t, time.Now().Add(time.Hour), share.ExpiresAt, time.Minute)
+
+		u, err := url.Parse(share.URL)
+		require.NoError(t, err)
+		require.Contains(t, u.Hostname(), "8080--"+agentName+"--"+workspace.Name+"--"+workspace.OwnerName)
+		require.NotEmpty(t, u.Query().Get(codersdk.PortShareTokenQueryParameter))
+
+		shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, shares, 1)
+		require.Equal(t, share, shares[0])
+
+		err = client.DeleteWorkspacePortShare(ctx, workspace.ID, share.ID)
+		require.NoError(t, err)
+
+		shares, err = client.WorkspacePortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, shares)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentName := setup(t, "*.apps.coder.test")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		for _, req := range []codersdk.CreateWorkspacePortShareRequest{
+			{AgentName: agentName, Port: 0, TTLMillis: time.Hour.Milliseconds()},
+			{AgentName: agentName, Port: codersdk.WorkspaceAgentMinimumListeningPort - 1, TTLMillis: time.Hour.Milliseconds()},
+			{AgentName: agentName, Port: 70000, TTLMillis: time.Hour.Milliseconds()},
+			{AgentName: agentName, Port: 8080, TTLMillis: time.Second.Milliseconds()},
+			{AgentName: agentName, Port: 8080, TTLMillis: (365 * 24 * time.Hour).Milliseconds()},
+			{AgentName: "not-an-agent", Port: 8080, TTLMillis: time.Hour.Milliseconds()},
+		} {
+			_, err := client.CreateWorkspacePortShare(ctx, workspace.ID, req)
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		}
+	})
+
+	t.Run("SharedWithApps", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentName := setup(t, "*.apps.coder.test")
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, workspace.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		share, err := client.CreateWorkspacePortShare(ctx, workspace.ID, codersdk.CreateWorkspacePortShareRequest{
+			AgentName: agentName,
+			Port:      8080,
+			TTLMillis: time.Hour.Milliseconds(),
+		})
+		require.NoError(t, err)
+		err = client.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+			UserRoles: map[string]codersdk.WorkspaceRole{
+				memberUser.ID.String(): codersdk.WorkspaceRoleApp,
+			},
+		})
+		require.NoError(t, err)
+
+		// Access to the workspace's apps is not enough to manage share links.
+		var apiErr *codersdk.Error
+		_, err = member.CreateWorkspacePortShare(ctx, workspace.ID, codersdk.CreateWorkspacePortShareRequest{
+			AgentName: agentName,
+			Port:      8080,
+			TTLMillis: time.Hour.Milliseconds(),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = member.WorkspacePortShares(ctx, workspace.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		err = member.DeleteWorkspacePortShare(ctx, workspace.ID, share.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		shares, err := client.WorkspacePortShares(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, shares, 1)
+	})
+
+	t.Run("NoAppHostname", func(t *testing.T) {
+		t.Parallel()
+		client, workspace, agentName := setup(t, "")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateWorkspacePortShare(ctx, workspace.ID, codersdk.CreateWorkspacePortShareRequest{
+			AgentName: agentName,
+			Port:      8080,
+			TTLMillis: time.Hour.Milliseconds(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
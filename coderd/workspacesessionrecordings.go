@@ -0,0 +1,157 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// @Summary Submit workspace agent session recording
+// @ID submit-workspace-agent-session-recording
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostSessionRecordingRequest true "Session recording"
+// @Success 204 "Success"
+// @Router /workspaceagents/me/session-recordings [post]
+func (api *API) postWorkspaceAgentSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	// The data is base64 encoded in the request, so leave room for the
+	// encoding overhead.
+	r.Body = http.MaxBytesReader(rw, r.Body, 2*agentsdk.MaxSessionRecordingSize)
+	var req agentsdk.PostSessionRecordingRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	recordingType := database.WorkspaceSessionRecordingType(req.Type)
+	if !recordingType.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid session recording type.",
+			Detail:  fmt.Sprintf("type %q is not one of %v", req.Type, database.AllWorkspaceSessionRecordingTypeValues()),
+		})
+		return
+	}
+	if len(req.Data) == 0 || len(req.Data) > agentsdk.MaxSessionRecordingSize {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid session recording.",
+			Detail:  fmt.Sprintf("recordings must be between 1 and %d bytes, got %d", agentsdk.MaxSessionRecordingSize, len(req.Data)),
+		})
+		return
+	}
+
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	err = api.Database.InsertWorkspaceSessionRecording(ctx, database.InsertWorkspaceSessionRecordingParams{
+		ID:               uuid.New(),
+		WorkspaceID:      build.WorkspaceID,
+		WorkspaceBuildID: build.ID,
+		AgentID:          workspaceAgent.ID,
+		Type:             recordingType,
+		StartedAt:        req.StartedAt,
+		EndedAt:          req.EndedAt,
+		Size:             int64(len(req.Data)),
+		Data:             req.Data,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace session recordings
+// @ID get-workspace-session-recordings
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceSessionRecording
+// @Router /workspaces/{workspace}/session-recordings [get]
+func (api *API) workspaceSessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	recordings, err := api.Database.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, workspace.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	res := make([]codersdk.WorkspaceSessionRecording, 0, len(recordings))
+	for _, recording := range recordings {
+		res = append(res, codersdk.WorkspaceSessionRecording{
+			ID:               recording.ID,
+			WorkspaceID:      recording.WorkspaceID,
+			WorkspaceBuildID: recording.WorkspaceBuildID,
+			AgentID:          recording.AgentID,
+			Type:             codersdk.WorkspaceSessionRecordingType(recording.Type),
+			StartedAt:        recording.StartedAt,
+			EndedAt:          recording.EndedAt,
+			Size:             recording.Size,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Get workspace session recording cast
+// @ID get-workspace-session-recording-cast
+// @Security CoderSessionToken
+// @Produce application/x-asciicast
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param recording path string true "Recording ID" format(uuid)
+// @Success 200
+// @Router /workspaces/{workspace}/session-recordings/{recording} [get]
+func (api *API) workspaceSessionRecording(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+	)
+
+	recordingID, ok := httpmw.ParseUUIDParam(rw, r, "recording")
+	if !ok {
+		return
+	}
+
+	recording, err := api.Database.GetWorkspaceSessionRecordingByID(ctx, recordingID)
+	if httpapi.Is404Error(err) || (err == nil && recording.WorkspaceID != workspace.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.Header().Set("Content-Type", codersdk.WorkspaceSessionRecordingContentType)
+	rw.Header().Set("Content-Length", strconv.Itoa(len(recording.Data)))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(recording.Data)
+}
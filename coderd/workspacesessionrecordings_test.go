@@ -0,0 +1,140 @@
+package coderd_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PostListGet", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+		other := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).Do()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(r.AgentToken)
+
+		manifest, err := agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		require.False(t, manifest.RecordSessions)
+
+		_, err = client.UpdateTemplateMeta(ctx, r.Workspace.TemplateID, codersdk.UpdateTemplateMeta{
+			RecordSessions: true,
+		})
+		require.NoError(t, err)
+		manifest, err = agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		require.True(t, manifest.RecordSessions)
+
+		data := []byte(`{"version":2,"width":80,"height":24}` + "\n" + `[0.5,"o","hello"]` + "\n")
+		startedAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Millisecond)
+		err = agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+			Type:      codersdk.WorkspaceSessionRecordingTypeSSH,
+			StartedAt: startedAt,
+			EndedAt:   startedAt.Add(30 * time.Second),
+			Data:      data,
+		})
+		require.NoError(t, err)
+
+		recordings, err := client.WorkspaceSessionRecordings(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, recordings, 1)
+		recording := recordings[0]
+		require.Equal(t, r.Workspace.ID, recording.WorkspaceID)
+		require.Equal(t, r.Build.ID, recording.WorkspaceBuildID)
+		workspace, err := client.Workspace(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, workspace.LatestBuild.Resources[0].Agents[0].ID, recording.AgentID)
+		require.Equal(t, codersdk.WorkspaceSessionRecordingTypeSSH, recording.Type)
+		require.WithinDuration(t, startedAt, recording.StartedAt, time.Millisecond)
+		require.EqualValues(t, len(data), recording.Size)
+
+		cast, err := client.WorkspaceSessionRecordingCast(ctx, r.Workspace.ID, recording.ID)
+		require.NoError(t, err)
+		defer cast.Close()
+		got, err := io.ReadAll(cast)
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+
+		// Recordings are only found through the workspace they belong to.
+		_, err = client.WorkspaceSessionRecordingCast(ctx, other.Workspace.ID, recording.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+		_, err = client.WorkspaceSessionRecordingCast(ctx, r.Workspace.ID, uuid.New())
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Being able to read the workspace is not enough to read what was
+		// typed in its terminals.
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+		_, err = templateAdmin.Workspace(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		_, err = templateAdmin.WorkspaceSessionRecordings(ctx, r.Workspace.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+		_, err = templateAdmin.WorkspaceSessionRecordingCast(ctx, r.Workspace.ID, recording.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(r.AgentToken)
+
+		err := agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+			Type:      "telnet",
+			StartedAt: time.Now(),
+			EndedAt:   time.Now(),
+			Data:      []byte(`{"version":2}`),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		err = agentClient.PostSessionRecording(ctx, agentsdk.PostSessionRecordingRequest{
+			Type:      codersdk.WorkspaceSessionRecordingTypeReconnectingPTY,
+			StartedAt: time.Now(),
+			EndedAt:   time.Now(),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		recordings, err := client.WorkspaceSessionRecordings(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, recordings)
+	})
+}
@@ -48,6 +48,7 @@ type Builder struct {
 	richParameterValues []codersdk.WorkspaceBuildParameter
 	initiator           uuid.UUID
 	reason              database.BuildReason
+	priority            database.ProvisionerJobPriority
 
 	// used during build, makes function arguments less verbose
 	ctx   context.Context
@@ -152,6 +153,15 @@ func (b Builder) Reason(r database.BuildReason) Builder {
 	return b
 }
 
+// Priority overrides the priority of the build's provisioner job. By default,
+// builds started by users have the user priority and all other builds have the
+// autobuild priority.
+func (b Builder) Priority(p database.ProvisionerJobPriority) Builder {
+	// nolint: revive
+	b.priority = p
+	return b
+}
+
 func (b Builder) RichParameterValues(p []codersdk.WorkspaceBuildParameter) Builder {
 	// nolint: revive
 	b.richParameterValues = p
@@ -315,6 +325,8 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 			Valid:      true,
 			RawMessage: traceMetadataRaw,
 		},
+		Priority:   b.jobPriority(),
+		TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
 	})
 	if err != nil {
 		return nil, nil, BuildError{http.StatusInternalServerError, "insert provisioner job", err}
@@ -336,19 +348,20 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 	var workspaceBuild database.WorkspaceBuild
 	err = b.store.InTx(func(store database.Store) error {
 		err = store.InsertWorkspaceBuild(b.ctx, database.InsertWorkspaceBuildParams{
-			ID:                workspaceBuildID,
-			CreatedAt:         now,
-			UpdatedAt:         now,
-			WorkspaceID:       b.workspace.ID,
-			TemplateVersionID: templateVersionID,
-			BuildNumber:       buildNum,
-			ProvisionerState:  state,
-			InitiatorID:       b.initiator,
-			Transition:        b.trans,
-			JobID:             provisionerJob.ID,
-			Reason:            b.reason,
-			Deadline:          time.Time{}, // set by provisioner upon completion
-			MaxDeadline:       time.Time{}, // set by provisioner upon completion
+			ID:                    workspaceBuildID,
+			CreatedAt:             now,
+			UpdatedAt:             now,
+			WorkspaceID:           b.workspace.ID,
+			TemplateVersionID:     templateVersionID,
+			BuildNumber:           buildNum,
+			ProvisionerState:      state,
+			InitiatorID:           b.initiator,
+			Transition:            b.trans,
+			JobID:                 provisionerJob.ID,
+			Reason:                b.reason,
+			Deadline:              time.Time{},      // set by provisioner upon completion
+			MaxDeadline:           time.Time{},      // set by provisioner upon completion
+			ProvisionerStateKeyID: sql.NullString{}, // set by dbcrypt if required
 		})
 		if err != nil {
 			code := http.StatusInternalServerError
@@ -386,6 +399,17 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 	return &workspaceBuild, &provisionerJob, nil
 }
 
+// jobPriority returns the priority of the build's provisioner job.
+func (b *Builder) jobPriority() database.ProvisionerJobPriority {
+	if b.priority != "" {
+		return b.priority
+	}
+	if b.reason == database.BuildReasonInitiator {
+		return database.ProvisionerJobPriorityUser
+	}
+	return database.ProvisionerJobPriorityAutobuild
+}
+
 func (b *Builder) getTemplate() (*database.Template, error) {
 	if b.template != nil {
 		return b.template, nil
@@ -646,7 +670,7 @@ func (b *Builder) authorize(authFunc func(action rbac.Action, object rbac.Object
 		msg := fmt.Sprintf("Transition %q not supported.", b.trans)
 		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
 	}
-	if !authFunc(action, b.workspace) {
+	if !authFunc(action, b.workspace.WorkspaceBuildRBAC(b.trans)) {
 		// We use the same wording as the httpapi to avoid leaking the existence of the workspace
 		return BuildError{http.StatusNotFound, httpapi.ResourceNotFoundResponse.Message, xerrors.New(httpapi.ResourceNotFoundResponse.Message)}
 	}
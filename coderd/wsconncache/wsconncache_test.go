@@ -279,6 +279,14 @@ func (*client) PatchLogs(_ context.Context, _ agentsdk.PatchLogs) error {
 	return nil
 }
 
+func (*client) PostSessionRecording(_ context.Context, _ agentsdk.PostSessionRecordingRequest) error {
+	return nil
+}
+
+func (*client) PostScriptTiming(_ context.Context, _ agentsdk.PostScriptTimingRequest) error {
+	return nil
+}
+
 func (*client) GetServiceBanner(_ context.Context) (codersdk.ServiceBannerConfig, error) {
 	return codersdk.ServiceBannerConfig{}, nil
 }
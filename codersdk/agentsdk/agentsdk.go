@@ -117,6 +117,9 @@ type Manifest struct {
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
 	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
+	// RecordSessions instructs the agent to record PTY sessions and upload
+	// them with PostSessionRecording.
+	RecordSessions bool `json:"record_sessions"`
 }
 
 type LogSource struct {
@@ -710,6 +713,55 @@ func (c *Client) PostLogSource(ctx context.Context, req PostLogSource) (codersdk
 	return logSource, json.NewDecoder(res.Body).Decode(&logSource)
 }
 
+// MaxSessionRecordingSize is the largest recording the agent uploads. Output
+// beyond this is dropped from the recording.
+const MaxSessionRecordingSize = 16 << 20
+
+// PostSessionRecordingRequest is a terminal session recorded by the agent.
+type PostSessionRecordingRequest struct {
+	Type      codersdk.WorkspaceSessionRecordingType `json:"type"`
+	StartedAt time.Time                              `json:"started_at" format:"date-time"`
+	EndedAt   time.Time                              `json:"ended_at" format:"date-time"`
+	// Data is the recording in asciicast v2 format.
+	Data []byte `json:"data"`
+}
+
+// PostSessionRecording uploads a recorded terminal session.
+func (c *Client) PostSessionRecording(ctx context.Context, req PostSessionRecordingRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/session-recordings", req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// PostScriptTimingRequest is the time spent running a start or stop script.
+type PostScriptTimingRequest struct {
+	LogSourceID uuid.UUID                                 `json:"log_source_id" format:"uuid"`
+	Stage       codersdk.WorkspaceAgentScriptTimingStage  `json:"stage"`
+	Status      codersdk.WorkspaceAgentScriptTimingStatus `json:"status"`
+	ExitCode    int32                                     `json:"exit_code"`
+	StartedAt   time.Time                                 `json:"started_at" format:"date-time"`
+	EndedAt     time.Time                                 `json:"ended_at" format:"date-time"`
+}
+
+// PostScriptTiming reports how long a script took to run.
+func (c *Client) PostScriptTiming(ctx context.Context, req PostScriptTimingRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/script-timings", req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // GetServiceBanner relays the service banner config.
 func (c *Client) GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerConfig, error) {
 	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/appearance", nil)
@@ -19,9 +19,12 @@ type APIKey struct {
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
 	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
-	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
+	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect,custom"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
+	// ScopePermissions and ScopeAllowList are only set for the custom scope.
+	ScopePermissions []Permission `json:"scope_permissions,omitempty"`
+	ScopeAllowList   []uuid.UUID  `json:"scope_allow_list,omitempty" format:"uuid"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -48,12 +51,22 @@ const (
 	// APIKeyScopeApplicationConnect is a scope that allows the user
 	// to connect to applications in a workspace.
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
+	// APIKeyScopeCustom is a scope that only allows the permissions listed
+	// on the key, optionally restricted to an allow list of resources.
+	APIKeyScopeCustom APIKeyScope = "custom"
 )
 
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
-	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
+	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect,custom"`
 	TokenName string        `json:"token_name"`
+	// ScopePermissions restricts the token to the listed permissions. Setting
+	// them implies the custom scope.
+	ScopePermissions []Permission `json:"scope_permissions,omitempty"`
+	// ScopeAllowList restricts the token to the listed resource IDs, in
+	// addition to the user that owns the token. An empty list allows all
+	// resources.
+	ScopeAllowList []uuid.UUID `json:"scope_allow_list,omitempty" format:"uuid"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
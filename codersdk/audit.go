@@ -3,6 +3,7 @@ package codersdk
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/netip"
 	"strings"
@@ -27,6 +28,7 @@ const (
 	ResourceTypeHealthSettings  ResourceType = "health_settings"
 	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
 	ResourceTypeOrganization    ResourceType = "organization"
+	ResourceTypeOIDCSyncRules   ResourceType = "oidc_sync_rules"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -59,6 +61,8 @@ func (r ResourceType) FriendlyString() string {
 		return "organization"
 	case ResourceTypeHealthSettings:
 		return "health_settings"
+	case ResourceTypeOIDCSyncRules:
+		return "OIDC sync rules"
 	default:
 		return "unknown"
 	}
@@ -141,6 +145,28 @@ type AuditLogResponse struct {
 	Count     int64      `json:"count"`
 }
 
+// AuditLogExportFormat is the format audit logs are exported in.
+type AuditLogExportFormat string
+
+const (
+	// AuditLogExportFormatCSV exports audit logs as CSV with a header row.
+	AuditLogExportFormatCSV AuditLogExportFormat = "csv"
+	// AuditLogExportFormatNDJSON exports audit logs as newline-delimited
+	// JSON, with one AuditLog object per line.
+	AuditLogExportFormatNDJSON AuditLogExportFormat = "ndjson"
+)
+
+// AuditLogExportFormats are all valid export formats.
+var AuditLogExportFormats = []AuditLogExportFormat{
+	AuditLogExportFormatCSV,
+	AuditLogExportFormatNDJSON,
+}
+
+type AuditLogExportRequest struct {
+	SearchQuery string               `json:"q,omitempty"`
+	Format      AuditLogExportFormat `json:"format,omitempty"`
+}
+
 type CreateTestAuditLogRequest struct {
 	Action           AuditAction     `json:"action,omitempty" enums:"create,write,delete,start,stop"`
 	ResourceType     ResourceType    `json:"resource_type,omitempty" enums:"template,template_version,user,workspace,workspace_build,git_ssh_key,auditable_group"`
@@ -179,6 +205,29 @@ func (c *Client) AuditLogs(ctx context.Context, req AuditLogsRequest) (AuditLogR
 	return logRes, nil
 }
 
+// ExportAuditLogs streams every audit log matching the search query in the
+// requested format, newest first. The caller must close the returned reader.
+func (c *Client) ExportAuditLogs(ctx context.Context, req AuditLogExportRequest) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/export", nil, func(r *http.Request) {
+		q := r.URL.Query()
+		if req.SearchQuery != "" {
+			q.Set("q", req.SearchQuery)
+		}
+		if req.Format != "" {
+			q.Set("format", string(req.Format))
+		}
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
 // CreateTestAuditLog creates a fake audit log. Only owners of the organization
 // can perform this action. It's used for testing purposes.
 func (c *Client) CreateTestAuditLog(ctx context.Context, req CreateTestAuditLogRequest) error {
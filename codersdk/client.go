@@ -60,6 +60,18 @@ const (
 	// apps.
 	//nolint:gosec
 	SignedAppTokenQueryParameter = "coder_signed_app_token_23db1dde"
+	// PortShareTokenQueryParameter is the name of the query parameter that
+	// carries a port share link's token. It is exchanged for the
+	// PortShareTokenCookie on first use and stripped from the URL.
+	//
+	// It has a random suffix to avoid conflict with user query parameters on
+	// apps.
+	//nolint:gosec
+	PortShareTokenQueryParameter = "coder_port_share_token_8f3a91c2"
+	// PortShareTokenCookie is the name of the cookie that stores a port share
+	// link's token on the port's subdomain.
+	//nolint:gosec
+	PortShareTokenCookie = "coder_port_share_token"
 
 	// BypassRatelimitHeader is the custom header to use to bypass ratelimits.
 	// Only owners can bypass rate limits. This is typically used for scale testing.
@@ -78,6 +90,13 @@ const (
 
 	// ProvisionerDaemonPSK contains the authentication pre-shared key for an external provisioner daemon
 	ProvisionerDaemonPSK = "Coder-Provisioner-Daemon-PSK"
+
+	// ProvisionerDaemonKey contains the authentication key for an external provisioner daemon
+	ProvisionerDaemonKey = "Coder-Provisioner-Daemon-Key"
+
+	// BuildVersionHeader contains the build version of the sender. Servers
+	// set it on responses, and provisioner daemons set it when connecting.
+	BuildVersionHeader = "X-Coder-Build-Version"
 )
 
 // loggableMimeTypes is a list of MIME types that are safe to log
@@ -134,54 +134,59 @@ type DeploymentValues struct {
 	DocsURL             clibase.URL  `json:"docs_url,omitempty"`
 	RedirectToAccessURL clibase.Bool `json:"redirect_to_access_url,omitempty"`
 	// HTTPAddress is a string because it may be set to zero to disable.
-	HTTPAddress                     clibase.String                       `json:"http_address,omitempty" typescript:",notnull"`
-	AutobuildPollInterval           clibase.Duration                     `json:"autobuild_poll_interval,omitempty"`
-	JobHangDetectorInterval         clibase.Duration                     `json:"job_hang_detector_interval,omitempty"`
-	DERP                            DERP                                 `json:"derp,omitempty" typescript:",notnull"`
-	Prometheus                      PrometheusConfig                     `json:"prometheus,omitempty" typescript:",notnull"`
-	Pprof                           PprofConfig                          `json:"pprof,omitempty" typescript:",notnull"`
-	ProxyTrustedHeaders             clibase.StringArray                  `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
-	ProxyTrustedOrigins             clibase.StringArray                  `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
-	CacheDir                        clibase.String                       `json:"cache_directory,omitempty" typescript:",notnull"`
-	InMemoryDatabase                clibase.Bool                         `json:"in_memory_database,omitempty" typescript:",notnull"`
-	PostgresURL                     clibase.String                       `json:"pg_connection_url,omitempty" typescript:",notnull"`
-	OAuth2                          OAuth2Config                         `json:"oauth2,omitempty" typescript:",notnull"`
-	OIDC                            OIDCConfig                           `json:"oidc,omitempty" typescript:",notnull"`
-	Telemetry                       TelemetryConfig                      `json:"telemetry,omitempty" typescript:",notnull"`
-	TLS                             TLSConfig                            `json:"tls,omitempty" typescript:",notnull"`
-	Trace                           TraceConfig                          `json:"trace,omitempty" typescript:",notnull"`
-	SecureAuthCookie                clibase.Bool                         `json:"secure_auth_cookie,omitempty" typescript:",notnull"`
-	StrictTransportSecurity         clibase.Int64                        `json:"strict_transport_security,omitempty" typescript:",notnull"`
-	StrictTransportSecurityOptions  clibase.StringArray                  `json:"strict_transport_security_options,omitempty" typescript:",notnull"`
-	SSHKeygenAlgorithm              clibase.String                       `json:"ssh_keygen_algorithm,omitempty" typescript:",notnull"`
-	MetricsCacheRefreshInterval     clibase.Duration                     `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
-	AgentStatRefreshInterval        clibase.Duration                     `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
-	AgentFallbackTroubleshootingURL clibase.URL                          `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
-	BrowserOnly                     clibase.Bool                         `json:"browser_only,omitempty" typescript:",notnull"`
-	SCIMAPIKey                      clibase.String                       `json:"scim_api_key,omitempty" typescript:",notnull"`
-	ExternalTokenEncryptionKeys     clibase.StringArray                  `json:"external_token_encryption_keys,omitempty" typescript:",notnull"`
-	Provisioner                     ProvisionerConfig                    `json:"provisioner,omitempty" typescript:",notnull"`
-	RateLimit                       RateLimitConfig                      `json:"rate_limit,omitempty" typescript:",notnull"`
-	Experiments                     clibase.StringArray                  `json:"experiments,omitempty" typescript:",notnull"`
-	UpdateCheck                     clibase.Bool                         `json:"update_check,omitempty" typescript:",notnull"`
-	MaxTokenLifetime                clibase.Duration                     `json:"max_token_lifetime,omitempty" typescript:",notnull"`
-	Swagger                         SwaggerConfig                        `json:"swagger,omitempty" typescript:",notnull"`
-	Logging                         LoggingConfig                        `json:"logging,omitempty" typescript:",notnull"`
-	Dangerous                       DangerousConfig                      `json:"dangerous,omitempty" typescript:",notnull"`
-	DisablePathApps                 clibase.Bool                         `json:"disable_path_apps,omitempty" typescript:",notnull"`
-	SessionDuration                 clibase.Duration                     `json:"max_session_expiry,omitempty" typescript:",notnull"`
-	DisableSessionExpiryRefresh     clibase.Bool                         `json:"disable_session_expiry_refresh,omitempty" typescript:",notnull"`
-	DisablePasswordAuth             clibase.Bool                         `json:"disable_password_auth,omitempty" typescript:",notnull"`
-	Support                         SupportConfig                        `json:"support,omitempty" typescript:",notnull"`
-	ExternalAuthConfigs             clibase.Struct[[]ExternalAuthConfig] `json:"external_auth,omitempty" typescript:",notnull"`
-	SSHConfig                       SSHConfig                            `json:"config_ssh,omitempty" typescript:",notnull"`
-	WgtunnelHost                    clibase.String                       `json:"wgtunnel_host,omitempty" typescript:",notnull"`
-	DisableOwnerWorkspaceExec       clibase.Bool                         `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
-	ProxyHealthStatusInterval       clibase.Duration                     `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
-	EnableTerraformDebugMode        clibase.Bool                         `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
-	UserQuietHoursSchedule          UserQuietHoursScheduleConfig         `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
-	WebTerminalRenderer             clibase.String                       `json:"web_terminal_renderer,omitempty" typescript:",notnull"`
-	Healthcheck                     HealthcheckConfig                    `json:"healthcheck,omitempty" typescript:",notnull"`
+	HTTPAddress                     clibase.String                        `json:"http_address,omitempty" typescript:",notnull"`
+	AutobuildPollInterval           clibase.Duration                      `json:"autobuild_poll_interval,omitempty"`
+	JobHangDetectorInterval         clibase.Duration                      `json:"job_hang_detector_interval,omitempty"`
+	DERP                            DERP                                  `json:"derp,omitempty" typescript:",notnull"`
+	Prometheus                      PrometheusConfig                      `json:"prometheus,omitempty" typescript:",notnull"`
+	Pprof                           PprofConfig                           `json:"pprof,omitempty" typescript:",notnull"`
+	ProxyTrustedHeaders             clibase.StringArray                   `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
+	ProxyTrustedOrigins             clibase.StringArray                   `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
+	CacheDir                        clibase.String                        `json:"cache_directory,omitempty" typescript:",notnull"`
+	InMemoryDatabase                clibase.Bool                          `json:"in_memory_database,omitempty" typescript:",notnull"`
+	PostgresURL                     clibase.String                        `json:"pg_connection_url,omitempty" typescript:",notnull"`
+	OAuth2                          OAuth2Config                          `json:"oauth2,omitempty" typescript:",notnull"`
+	OIDC                            OIDCConfig                            `json:"oidc,omitempty" typescript:",notnull"`
+	Telemetry                       TelemetryConfig                       `json:"telemetry,omitempty" typescript:",notnull"`
+	TLS                             TLSConfig                             `json:"tls,omitempty" typescript:",notnull"`
+	Trace                           TraceConfig                           `json:"trace,omitempty" typescript:",notnull"`
+	SecureAuthCookie                clibase.Bool                          `json:"secure_auth_cookie,omitempty" typescript:",notnull"`
+	StrictTransportSecurity         clibase.Int64                         `json:"strict_transport_security,omitempty" typescript:",notnull"`
+	StrictTransportSecurityOptions  clibase.StringArray                   `json:"strict_transport_security_options,omitempty" typescript:",notnull"`
+	SSHKeygenAlgorithm              clibase.String                        `json:"ssh_keygen_algorithm,omitempty" typescript:",notnull"`
+	MetricsCacheRefreshInterval     clibase.Duration                      `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
+	AgentStatRefreshInterval        clibase.Duration                      `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
+	AgentFallbackTroubleshootingURL clibase.URL                           `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
+	BrowserOnly                     clibase.Bool                          `json:"browser_only,omitempty" typescript:",notnull"`
+	SCIMAPIKey                      clibase.String                        `json:"scim_api_key,omitempty" typescript:",notnull"`
+	ExternalTokenEncryptionKeys     clibase.StringArray                   `json:"external_token_encryption_keys,omitempty" typescript:",notnull"`
+	Provisioner                     ProvisionerConfig                     `json:"provisioner,omitempty" typescript:",notnull"`
+	RateLimit                       RateLimitConfig                       `json:"rate_limit,omitempty" typescript:",notnull"`
+	Experiments                     clibase.StringArray                   `json:"experiments,omitempty" typescript:",notnull"`
+	UpdateCheck                     clibase.Bool                          `json:"update_check,omitempty" typescript:",notnull"`
+	MaxTokenLifetime                clibase.Duration                      `json:"max_token_lifetime,omitempty" typescript:",notnull"`
+	Swagger                         SwaggerConfig                         `json:"swagger,omitempty" typescript:",notnull"`
+	Logging                         LoggingConfig                         `json:"logging,omitempty" typescript:",notnull"`
+	Dangerous                       DangerousConfig                       `json:"dangerous,omitempty" typescript:",notnull"`
+	DisablePathApps                 clibase.Bool                          `json:"disable_path_apps,omitempty" typescript:",notnull"`
+	SessionDuration                 clibase.Duration                      `json:"max_session_expiry,omitempty" typescript:",notnull"`
+	DisableSessionExpiryRefresh     clibase.Bool                          `json:"disable_session_expiry_refresh,omitempty" typescript:",notnull"`
+	DisablePasswordAuth             clibase.Bool                          `json:"disable_password_auth,omitempty" typescript:",notnull"`
+	Support                         SupportConfig                         `json:"support,omitempty" typescript:",notnull"`
+	ExternalAuthConfigs             clibase.Struct[[]ExternalAuthConfig]  `json:"external_auth,omitempty" typescript:",notnull"`
+	LoginProviders                  clibase.Struct[[]LoginProviderConfig] `json:"login_providers,omitempty" typescript:",notnull"`
+	SSHConfig                       SSHConfig                             `json:"config_ssh,omitempty" typescript:",notnull"`
+	WgtunnelHost                    clibase.String                        `json:"wgtunnel_host,omitempty" typescript:",notnull"`
+	DisableOwnerWorkspaceExec       clibase.Bool                          `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
+	RecordSessions                  clibase.Bool                          `json:"record_sessions,omitempty" typescript:",notnull"`
+	ProxyHealthStatusInterval       clibase.Duration                      `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
+	EnableTerraformDebugMode        clibase.Bool                          `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
+	UserQuietHoursSchedule          UserQuietHoursScheduleConfig          `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
+	WebTerminalRenderer             clibase.String                        `json:"web_terminal_renderer,omitempty" typescript:",notnull"`
+	Healthcheck                     HealthcheckConfig                     `json:"healthcheck,omitempty" typescript:",notnull"`
+	Notifications                   NotificationsConfig                   `json:"notifications,omitempty" typescript:",notnull"`
+	AuditLogging                    AuditLoggingConfig                    `json:"audit_logging,omitempty" typescript:",notnull"`
+	Retention                       RetentionConfig                       `json:"retention,omitempty" typescript:",notnull"`
 
 	Config      clibase.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig clibase.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -357,6 +362,45 @@ type ExternalAuthConfig struct {
 	DisplayIcon string `json:"display_icon"`
 }
 
+// LoginProviderConfig configures an identity provider users can log in with,
+// in addition to the OIDC and GitHub providers configured by the --oidc-* and
+// --oauth2-github-* flags.
+type LoginProviderConfig struct {
+	// ID identifies the provider in its callback URL,
+	// /api/v2/users/login-providers/<id>/callback, and in the links of users
+	// that log in with it, so it must not change.
+	ID string `json:"id" yaml:"id"`
+	// Type is either "oidc" or "github".
+	Type         string `json:"type" yaml:"type"`
+	ClientID     string `json:"client_id" yaml:"client_id"`
+	ClientSecret string `json:"-" yaml:"client_secret"`
+	AllowSignups bool   `json:"allow_signups" yaml:"allow_signups"`
+	// DisplayName is shown on the login button.
+	DisplayName string `json:"display_name" yaml:"display_name"`
+	IconURL     string `json:"icon_url" yaml:"icon_url"`
+
+	// The following only apply to OIDC providers, and behave like their
+	// --oidc-* flag equivalents.
+	IssuerURL           string              `json:"issuer_url" yaml:"issuer_url"`
+	Scopes              []string            `json:"scopes" yaml:"scopes"`
+	EmailDomain         []string            `json:"email_domain" yaml:"email_domain"`
+	EmailField          string              `json:"email_field" yaml:"email_field"`
+	UsernameField       string              `json:"username_field" yaml:"username_field"`
+	IgnoreEmailVerified bool                `json:"ignore_email_verified" yaml:"ignore_email_verified"`
+	IgnoreUserInfo      bool                `json:"ignore_user_info" yaml:"ignore_user_info"`
+	GroupField          string              `json:"group_field" yaml:"group_field"`
+	GroupMapping        map[string]string   `json:"group_mapping" yaml:"group_mapping"`
+	UserRoleField       string              `json:"user_role_field" yaml:"user_role_field"`
+	UserRoleMapping     map[string][]string `json:"user_role_mapping" yaml:"user_role_mapping"`
+
+	// The following only apply to GitHub providers, and behave like their
+	// --oauth2-github-* flag equivalents.
+	AllowEveryone     bool     `json:"allow_everyone" yaml:"allow_everyone"`
+	AllowedOrgs       []string `json:"allowed_orgs" yaml:"allowed_orgs"`
+	AllowedTeams      []string `json:"allowed_teams" yaml:"allowed_teams"`
+	EnterpriseBaseURL string   `json:"enterprise_base_url" yaml:"enterprise_base_url"`
+}
+
 type ProvisionerConfig struct {
 	Daemons             clibase.Int64    `json:"daemons" typescript:",notnull"`
 	DaemonsEcho         clibase.Bool     `json:"daemons_echo" typescript:",notnull"`
@@ -364,6 +408,10 @@ type ProvisionerConfig struct {
 	DaemonPollJitter    clibase.Duration `json:"daemon_poll_jitter" typescript:",notnull"`
 	ForceCancelInterval clibase.Duration `json:"force_cancel_interval" typescript:",notnull"`
 	DaemonPSK           clibase.String   `json:"daemon_psk" typescript:",notnull"`
+	// MaxConcurrentJobsPerUser and MaxConcurrentJobsPerTemplate limit how many
+	// jobs run at the same time. Zero means there is no limit.
+	MaxConcurrentJobsPerUser     clibase.Int64 `json:"max_concurrent_jobs_per_user" typescript:",notnull"`
+	MaxConcurrentJobsPerTemplate clibase.Int64 `json:"max_concurrent_jobs_per_template" typescript:",notnull"`
 }
 
 type RateLimitConfig struct {
@@ -402,6 +450,68 @@ type HealthcheckConfig struct {
 	ThresholdDatabase clibase.Duration `json:"threshold_database" typescript:",notnull"`
 }
 
+// NotificationsConfig configures the delivery of notifications about
+// workspace lifecycle events to users.
+type NotificationsConfig struct {
+	// Method is the delivery method to use. Notifications are disabled when
+	// empty.
+	Method            clibase.String   `json:"method" typescript:",notnull"`
+	MaxSendAttempts   clibase.Int64    `json:"max_send_attempts" typescript:",notnull"`
+	RetryInterval     clibase.Duration `json:"retry_interval" typescript:",notnull"`
+	DispatchTimeout   clibase.Duration `json:"dispatch_timeout" typescript:",notnull"`
+	FetchInterval     clibase.Duration `json:"fetch_interval" typescript:",notnull"`
+	LeasePeriod       clibase.Duration `json:"lease_period" typescript:",notnull"`
+	LeaseCount        clibase.Int64    `json:"lease_count" typescript:",notnull"`
+	AutostopWarning   clibase.Duration `json:"autostop_warning" typescript:",notnull"`
+	AutodeleteWarning clibase.Duration `json:"autodelete_warning" typescript:",notnull"`
+
+	SMTP    NotificationsEmailConfig   `json:"email" typescript:",notnull"`
+	Webhook NotificationsWebhookConfig `json:"webhook" typescript:",notnull"`
+}
+
+type NotificationsEmailConfig struct {
+	From      clibase.String   `json:"from" typescript:",notnull"`
+	Smarthost clibase.HostPort `json:"smarthost" typescript:",notnull"`
+	Hello     clibase.String   `json:"hello" typescript:",notnull"`
+	Username  clibase.String   `json:"username" typescript:",notnull"`
+	Password  clibase.String   `json:"password" typescript:",notnull"`
+	ForceTLS  clibase.Bool     `json:"force_tls" typescript:",notnull"`
+}
+
+type NotificationsWebhookConfig struct {
+	Endpoint clibase.URL `json:"endpoint" typescript:",notnull"`
+}
+
+// AuditLoggingConfig configures external destinations that audit logs are
+// streamed to in addition to the database.
+type AuditLoggingConfig struct {
+	Webhook AuditLoggingWebhookConfig `json:"webhook" typescript:",notnull"`
+	Syslog  AuditLoggingSyslogConfig  `json:"syslog" typescript:",notnull"`
+}
+
+type AuditLoggingWebhookConfig struct {
+	URL           clibase.URL      `json:"url" typescript:",notnull"`
+	Secret        clibase.String   `json:"secret" typescript:",notnull"`
+	BatchSize     clibase.Int64    `json:"batch_size" typescript:",notnull"`
+	FlushInterval clibase.Duration `json:"flush_interval" typescript:",notnull"`
+	MaxRetries    clibase.Int64    `json:"max_retries" typescript:",notnull"`
+}
+
+type AuditLoggingSyslogConfig struct {
+	Address clibase.String `json:"address" typescript:",notnull"`
+	TLS     clibase.Bool   `json:"tls" typescript:",notnull"`
+}
+
+// RetentionConfig configures how long records are kept in the database before
+// they are purged.
+type RetentionConfig struct {
+	AuditLogs          clibase.Duration `json:"audit_logs" typescript:",notnull"`
+	ProvisionerJobLogs clibase.Duration `json:"provisioner_job_logs" typescript:",notnull"`
+	WorkspaceBuilds    clibase.Int64    `json:"workspace_builds" typescript:",notnull"`
+	SessionRecordings  clibase.Duration `json:"session_recordings" typescript:",notnull"`
+	DryRun             clibase.Bool     `json:"dry_run" typescript:",notnull"`
+}
+
 const (
 	annotationFormatDuration = "format_duration"
 	annotationEnterpriseKey  = "enterprise"
@@ -502,6 +612,41 @@ func (c *DeploymentValues) Options() clibase.OptionSet {
 			Name:   "Health Check",
 			YAML:   "healthcheck",
 		}
+		deploymentGroupAuditLogging = clibase.Group{
+			Name:        "Audit Logging",
+			Description: "Stream audit logs to external destinations in addition to the database.",
+			YAML:        "auditLogging",
+		}
+		deploymentGroupAuditLoggingWebhook = clibase.Group{
+			Parent: &deploymentGroupAuditLogging,
+			Name:   "Webhook",
+			YAML:   "webhook",
+		}
+		deploymentGroupAuditLoggingSyslog = clibase.Group{
+			Parent: &deploymentGroupAuditLogging,
+			Name:   "Syslog",
+			YAML:   "syslog",
+		}
+		deploymentGroupRetention = clibase.Group{
+			Name:        "Retention",
+			Description: "Configure how long old records are kept in the database. Expired records are purged periodically in small batches.",
+			YAML:        "retention",
+		}
+		deploymentGroupNotifications = clibase.Group{
+			Name:        "Notifications",
+			Description: "Configure how users are notified about events affecting their workspaces, such as an upcoming autostop or deletion.",
+			YAML:        "notifications",
+		}
+		deploymentGroupNotificationsEmail = clibase.Group{
+			Parent: &deploymentGroupNotifications,
+			Name:   "Email",
+			YAML:   "email",
+		}
+		deploymentGroupNotificationsWebhook = clibase.Group{
+			Parent: &deploymentGroupNotifications,
+			Name:   "Webhook",
+			YAML:   "webhook",
+		}
 		deploymentGroupOAuth2 = clibase.Group{
 			Name:        "OAuth2",
 			Description: `Configure login and user-provisioning with GitHub via oAuth2.`,
@@ -1247,6 +1392,16 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "iconURL",
 		},
+		{
+			// Env handling is done in cli.ReadLoginProvidersFromEnv
+			Name:        "Login Providers",
+			Description: "Additional OIDC and GitHub identity providers users can log in with.",
+			YAML:        "loginProviders",
+			Value:       &c.LoginProviders,
+			// Login providers are hidden until they are defined in the
+			// YAML.
+			Hidden: true,
+		},
 		// Telemetry settings
 		{
 			Name:        "Telemetry Enable",
@@ -1379,6 +1534,26 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupProvisioning,
 			YAML:        "daemonPSK",
 		},
+		{
+			Name:        "Max Concurrent Jobs Per User",
+			Description: "The maximum number of provisioner jobs started by a single user that may run at the same time. Further jobs wait in the queue while other users' jobs run. 0 means there is no limit.",
+			Flag:        "provisioner-max-concurrent-jobs-per-user",
+			Env:         "CODER_PROVISIONER_MAX_CONCURRENT_JOBS_PER_USER",
+			Default:     "0",
+			Value:       &c.Provisioner.MaxConcurrentJobsPerUser,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "maxConcurrentJobsPerUser",
+		},
+		{
+			Name:        "Max Concurrent Jobs Per Template",
+			Description: "The maximum number of provisioner jobs for a single template that may run at the same time. Further jobs wait in the queue while jobs for other templates run. 0 means there is no limit.",
+			Flag:        "provisioner-max-concurrent-jobs-per-template",
+			Env:         "CODER_PROVISIONER_MAX_CONCURRENT_JOBS_PER_TEMPLATE",
+			Default:     "0",
+			Value:       &c.Provisioner.MaxConcurrentJobsPerTemplate,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "maxConcurrentJobsPerTemplate",
+		},
 		// RateLimit settings
 		{
 			Name:        "Disable All Rate Limits",
@@ -1672,7 +1847,7 @@ when required by your organization's security policy.`,
 		},
 		{
 			Name:        "External Token Encryption Keys",
-			Description: "Encrypt OIDC and Git authentication tokens with AES-256-GCM in the database. The value must be a comma-separated list of base64-encoded keys. Each key, when base64-decoded, must be exactly 32 bytes in length. The first key will be used to encrypt new values. Subsequent keys will be used as a fallback when decrypting. During normal operation it is recommended to only set one key unless you are in the process of rotating keys with the `coder server dbcrypt rotate` command.",
+			Description: "Encrypt OIDC and Git authentication tokens with AES-256-GCM in the database. The value must be a comma-separated list of base64-encoded keys. Each key, when base64-decoded, must be exactly 32 bytes in length. A key may instead be wrapped by an external key service with `vault-transit:<mount>/<name>`, using the VAULT_ADDR and VAULT_TOKEN environment variables. The first key will be used to encrypt new values. Subsequent keys will be used as a fallback when decrypting. During normal operation it is recommended to only set one key unless you are in the process of rotating keys with the `coder server dbcrypt rotate` command.",
 			Flag:        "external-token-encryption-keys",
 			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_KEYS",
 			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
@@ -1698,6 +1873,15 @@ when required by your organization's security policy.`,
 			YAML:        "disableOwnerWorkspaceAccess",
 			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Record Sessions",
+			Description: "Record terminal sessions in all workspaces, regardless of the template setting. Recordings are stored with the workspace build and can be replayed with \"coder sessions replay\".",
+			Flag:        "record-sessions",
+			Env:         "CODER_RECORD_SESSIONS",
+
+			Value: &c.RecordSessions,
+			YAML:  "recordSessions",
+		},
 		{
 			Name:        "Session Duration",
 			Description: "The token expiry duration for browser sessions. Sessions may last longer if they are actively making requests, but this functionality can be disabled via --disable-session-expiry-refresh.",
@@ -1865,6 +2049,297 @@ Write out the current server config as YAML to stdout.`,
 			YAML:        "thresholdDatabase",
 			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
 		},
+		// Notifications Options
+		{
+			Name:        "Notifications: Method",
+			Description: "Which delivery method to use for notifications. Valid values are 'smtp' or 'webhook'. Notifications are disabled if unset.",
+			Flag:        "notifications-method",
+			Env:         "CODER_NOTIFICATIONS_METHOD",
+			Value:       &c.Notifications.Method,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "method",
+		},
+		{
+			Name:        "Notifications: Max Send Attempts",
+			Description: "The upper limit of attempts to send a notification.",
+			Flag:        "notifications-max-send-attempts",
+			Env:         "CODER_NOTIFICATIONS_MAX_SEND_ATTEMPTS",
+			Default:     "5",
+			Value:       &c.Notifications.MaxSendAttempts,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "maxSendAttempts",
+		},
+		{
+			Name:        "Notifications: Retry Interval",
+			Description: "The minimum time between retries of a failed notification.",
+			Flag:        "notifications-retry-interval",
+			Env:         "CODER_NOTIFICATIONS_RETRY_INTERVAL",
+			Default:     (5 * time.Minute).String(),
+			Value:       &c.Notifications.RetryInterval,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "retryInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Dispatch Timeout",
+			Description: "How long to wait for a notification to be delivered before considering the attempt failed.",
+			Flag:        "notifications-dispatch-timeout",
+			Env:         "CODER_NOTIFICATIONS_DISPATCH_TIMEOUT",
+			Default:     time.Minute.String(),
+			Value:       &c.Notifications.DispatchTimeout,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "dispatchTimeout",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Fetch Interval",
+			Description: "How often to check the queue for notifications which are ready to be sent.",
+			Flag:        "notifications-fetch-interval",
+			Env:         "CODER_NOTIFICATIONS_FETCH_INTERVAL",
+			Default:     (15 * time.Second).String(),
+			Value:       &c.Notifications.FetchInterval,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "fetchInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+			Hidden:      true,
+		},
+		{
+			Name:        "Notifications: Lease Period",
+			Description: "How long a replica holds a lease on a notification while sending it. Notifications which are not sent within this period may be picked up by another replica.",
+			Flag:        "notifications-lease-period",
+			Env:         "CODER_NOTIFICATIONS_LEASE_PERIOD",
+			Default:     (2 * time.Minute).String(),
+			Value:       &c.Notifications.LeasePeriod,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "leasePeriod",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+			Hidden:      true,
+		},
+		{
+			Name:        "Notifications: Lease Count",
+			Description: "How many notifications a replica leases from the queue at a time.",
+			Flag:        "notifications-lease-count",
+			Env:         "CODER_NOTIFICATIONS_LEASE_COUNT",
+			Default:     "20",
+			Value:       &c.Notifications.LeaseCount,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "leaseCount",
+			Hidden:      true,
+		},
+		{
+			Name:        "Notifications: Autostop Warning",
+			Description: "How long before a workspace is automatically stopped to notify its owner.",
+			Flag:        "notifications-autostop-warning",
+			Env:         "CODER_NOTIFICATIONS_AUTOSTOP_WARNING",
+			Default:     time.Hour.String(),
+			Value:       &c.Notifications.AutostopWarning,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "autostopWarning",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Autodelete Warning",
+			Description: "How long before a dormant workspace is automatically deleted to notify its owner.",
+			Flag:        "notifications-autodelete-warning",
+			Env:         "CODER_NOTIFICATIONS_AUTODELETE_WARNING",
+			Default:     (24 * time.Hour).String(),
+			Value:       &c.Notifications.AutodeleteWarning,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "autodeleteWarning",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Email: From Address",
+			Description: "The sender's address to use.",
+			Flag:        "notifications-email-from",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FROM",
+			Value:       &c.Notifications.SMTP.From,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "from",
+		},
+		{
+			Name:        "Notifications: Email: Smarthost",
+			Description: "The intermediary SMTP host through which emails are sent.",
+			Flag:        "notifications-email-smarthost",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_SMARTHOST",
+			Default:     "localhost:587",
+			Value:       &c.Notifications.SMTP.Smarthost,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "smarthost",
+		},
+		{
+			Name:        "Notifications: Email: Hello",
+			Description: "The hostname identifying the SMTP server.",
+			Flag:        "notifications-email-hello",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_HELLO",
+			Default:     "localhost",
+			Value:       &c.Notifications.SMTP.Hello,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "hello",
+		},
+		{
+			Name:        "Notifications: Email: Auth Username",
+			Description: "Username to use with PLAIN authentication.",
+			Flag:        "notifications-email-auth-username",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_AUTH_USERNAME",
+			Value:       &c.Notifications.SMTP.Username,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "authUsername",
+		},
+		{
+			Name:        "Notifications: Email: Auth Password",
+			Description: "Password to use with PLAIN authentication.",
+			Flag:        "notifications-email-auth-password",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_AUTH_PASSWORD",
+			Value:       &c.Notifications.SMTP.Password,
+			Group:       &deploymentGroupNotificationsEmail,
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+		},
+		{
+			Name:        "Notifications: Email: Force TLS",
+			Description: "Force a TLS connection to the configured SMTP smarthost.",
+			Flag:        "notifications-email-force-tls",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FORCE_TLS",
+			Default:     "false",
+			Value:       &c.Notifications.SMTP.ForceTLS,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "forceTLS",
+		},
+		{
+			Name:        "Notifications: Webhook: Endpoint",
+			Description: "The endpoint to which to send webhooks.",
+			Flag:        "notifications-webhook-endpoint",
+			Env:         "CODER_NOTIFICATIONS_WEBHOOK_ENDPOINT",
+			Value:       &c.Notifications.Webhook.Endpoint,
+			Group:       &deploymentGroupNotificationsWebhook,
+			YAML:        "endpoint",
+		},
+		{
+			Name:        "Audit Logging: Webhook: URL",
+			Description: "The URL to POST batches of audit logs to. Audit logs are not sent to a webhook if unset.",
+			Flag:        "audit-webhook-url",
+			Env:         "CODER_AUDIT_WEBHOOK_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogging.Webhook.URL,
+			Group:       &deploymentGroupAuditLoggingWebhook,
+			YAML:        "url",
+		},
+		{
+			Name:        "Audit Logging: Webhook: Secret",
+			Description: "The secret used to sign webhook requests. The hex-encoded HMAC-SHA256 of the request body is sent in the X-Coder-Signature header.",
+			Flag:        "audit-webhook-secret",
+			Env:         "CODER_AUDIT_WEBHOOK_SECRET",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+			Value:       &c.AuditLogging.Webhook.Secret,
+			Group:       &deploymentGroupAuditLoggingWebhook,
+		},
+		{
+			Name:        "Audit Logging: Webhook: Batch Size",
+			Description: "The maximum number of audit logs to send in a single webhook request.",
+			Flag:        "audit-webhook-batch-size",
+			Env:         "CODER_AUDIT_WEBHOOK_BATCH_SIZE",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogging.Webhook.BatchSize,
+			Default:     "100",
+			Group:       &deploymentGroupAuditLoggingWebhook,
+			YAML:        "batchSize",
+		},
+		{
+			Name:        "Audit Logging: Webhook: Flush Interval",
+			Description: "The maximum time to wait before sending a partial batch of audit logs.",
+			Flag:        "audit-webhook-flush-interval",
+			Env:         "CODER_AUDIT_WEBHOOK_FLUSH_INTERVAL",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationFormatDuration, "true"),
+			Value:       &c.AuditLogging.Webhook.FlushInterval,
+			Default:     (5 * time.Second).String(),
+			Group:       &deploymentGroupAuditLoggingWebhook,
+			YAML:        "flushInterval",
+		},
+		{
+			Name:        "Audit Logging: Webhook: Max Retries",
+			Description: "The number of times to retry a failed webhook request, with exponential backoff, before dropping the batch.",
+			Flag:        "audit-webhook-max-retries",
+			Env:         "CODER_AUDIT_WEBHOOK_MAX_RETRIES",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogging.Webhook.MaxRetries,
+			Default:     "5",
+			Group:       &deploymentGroupAuditLoggingWebhook,
+			YAML:        "maxRetries",
+		},
+		{
+			Name:        "Audit Logging: Syslog: Address",
+			Description: "The host:port of a syslog server to send RFC 5424 formatted audit logs to over TCP. Audit logs are not sent to syslog if unset.",
+			Flag:        "audit-syslog-address",
+			Env:         "CODER_AUDIT_SYSLOG_ADDRESS",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogging.Syslog.Address,
+			Group:       &deploymentGroupAuditLoggingSyslog,
+			YAML:        "address",
+		},
+		{
+			Name:        "Audit Logging: Syslog: TLS",
+			Description: "Connect to the syslog server using TLS.",
+			Flag:        "audit-syslog-tls",
+			Env:         "CODER_AUDIT_SYSLOG_TLS",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogging.Syslog.TLS,
+			Default:     "false",
+			Group:       &deploymentGroupAuditLoggingSyslog,
+			YAML:        "tls",
+		},
+		{
+			Name:        "Audit Logs Retention",
+			Description: "How long audit logs are kept before they are deleted. Audit logs are kept forever if set to 0.",
+			Flag:        "audit-logs-retention",
+			Env:         "CODER_AUDIT_LOGS_RETENTION",
+			Value:       &c.Retention.AuditLogs,
+			Default:     "0",
+			Group:       &deploymentGroupRetention,
+			YAML:        "auditLogs",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Provisioner Job Logs Retention",
+			Description: "How long the logs of completed provisioner jobs are kept before they are deleted. Logs are kept forever if set to 0.",
+			Flag:        "provisioner-job-logs-retention",
+			Env:         "CODER_PROVISIONER_JOB_LOGS_RETENTION",
+			Value:       &c.Retention.ProvisionerJobLogs,
+			Default:     "0",
+			Group:       &deploymentGroupRetention,
+			YAML:        "provisionerJobLogs",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Workspace Builds Retention",
+			Description: "The number of most recent builds to keep for each workspace. Older completed builds, along with their logs and resources, are deleted. Builds with session recordings are kept until the recordings are deleted. All builds are kept if set to 0.",
+			Flag:        "workspace-builds-retention",
+			Env:         "CODER_WORKSPACE_BUILDS_RETENTION",
+			Value:       &c.Retention.WorkspaceBuilds,
+			Default:     "0",
+			Group:       &deploymentGroupRetention,
+			YAML:        "workspaceBuilds",
+		},
+		{
+			Name:        "Session Recordings Retention",
+			Description: "How long terminal session recordings are kept after the session ends before they are deleted. Recordings are kept forever if set to 0.",
+			Flag:        "session-recordings-retention",
+			Env:         "CODER_SESSION_RECORDINGS_RETENTION",
+			Value:       &c.Retention.SessionRecordings,
+			Default:     "0",
+			Group:       &deploymentGroupRetention,
+			YAML:        "sessionRecordings",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Retention Dry Run",
+			Description: "Report the number of records that have expired under the retention settings in the coderd_dbpurge_expired_rows metric instead of deleting them.",
+			Flag:        "retention-dry-run",
+			Env:         "CODER_RETENTION_DRY_RUN",
+			Value:       &c.Retention.DryRun,
+			Default:     "false",
+			Group:       &deploymentGroupRetention,
+			YAML:        "dryRun",
+		},
 	}
 
 	return opts
@@ -60,6 +60,12 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"External Token Encryption Keys": {
 			yaml: true,
 		},
+		"Notifications: Email: Auth Password": {
+			yaml: true,
+		},
+		"Audit Logging: Webhook: Secret": {
+			yaml: true,
+		},
 		// These complex objects should be configured through YAML.
 		"Support Links": {
 			flag: true,
@@ -0,0 +1,117 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// OIDCSyncRules are evaluated against the claims of users that log in with
+// OIDC to assign them to organizations, roles and groups. When any rules are
+// set, they replace the group and role sync configured by the
+// --oidc-group-field and --oidc-user-role-field flags.
+//
+// Sync is declarative, but only for the kinds of assignments at least one
+// rule makes: on every login, the site roles of the user, and the
+// organization roles and groups in every organization the user is a member
+// of, are set to exactly what the rules assign. Kinds no rule assigns are left
+// unchanged.
+type OIDCSyncRules struct {
+	// SyncRoles must be enabled for rules to assign site and organization
+	// roles. Even then, the owner role is only removed from users if a rule
+	// assigns it by name.
+	SyncRoles bool           `json:"sync_roles"`
+	Rules     []OIDCSyncRule `json:"rules"`
+}
+
+// OIDCSyncRule assigns roles, groups and quota allowances to users whose
+// claim has a matching value.
+type OIDCSyncRule struct {
+	// Name identifies the rule in dry runs.
+	Name string `json:"name"`
+	// Claim is the path of the claim whose values are matched, with fields of
+	// nested objects separated by dots, like "realm_access.roles". Arrays are
+	// flattened, so each of their elements is matched.
+	Claim string `json:"claim"`
+	// Match is a regular expression that values of the claim must match. If
+	// empty, every value matches. Assigned names may refer to its capture
+	// groups, like "$1", and to the whole value with "$0".
+	Match string `json:"match,omitempty"`
+	// Organization is the name of the organization the user is added to, and
+	// that organization roles, groups and quota allowances are assigned in.
+	// Defaults to the default organization.
+	Organization      string   `json:"organization,omitempty"`
+	SiteRoles         []string `json:"site_roles,omitempty"`
+	OrganizationRoles []string `json:"organization_roles,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+	// QuotaAllowance is the quota allowance of the groups the rule creates.
+	// If multiple rules assign the same group, the largest allowance is used.
+	// Groups that already exist keep their allowance.
+	QuotaAllowance *int `json:"quota_allowance,omitempty"`
+}
+
+// OIDCSyncDryRunRequest evaluates OIDC sync rules against a set of claims
+// without changing any users.
+type OIDCSyncDryRunRequest struct {
+	Claims map[string]interface{} `json:"claims"`
+	// Rules are evaluated instead of the saved rules, if set.
+	Rules *OIDCSyncRules `json:"rules,omitempty"`
+}
+
+// OIDCSyncDryRunResponse is what OIDC sync rules assign for a set of claims.
+type OIDCSyncDryRunResponse struct {
+	MatchedRules  []string                     `json:"matched_rules"`
+	SiteRoles     []string                     `json:"site_roles"`
+	Organizations []OIDCSyncOrganizationResult `json:"organizations"`
+}
+
+type OIDCSyncOrganizationResult struct {
+	OrganizationName string `json:"organization_name"`
+	// OrganizationID is the nil UUID if the organization does not exist.
+	// Assignments in organizations that do not exist are ignored.
+	OrganizationID  uuid.UUID      `json:"organization_id" format:"uuid"`
+	Roles           []string       `json:"roles"`
+	Groups          []string       `json:"groups"`
+	QuotaAllowances map[string]int `json:"quota_allowances"`
+}
+
+func (c *Client) OIDCSyncRules(ctx context.Context) (OIDCSyncRules, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/oidc/sync-rules", nil)
+	if err != nil {
+		return OIDCSyncRules{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OIDCSyncRules{}, ReadBodyAsError(res)
+	}
+	var rules OIDCSyncRules
+	return rules, json.NewDecoder(res.Body).Decode(&rules)
+}
+
+func (c *Client) UpdateOIDCSyncRules(ctx context.Context, rules OIDCSyncRules) (OIDCSyncRules, error) {
+	res, err := c.Request(ctx, http.MethodPut, "/api/v2/users/oidc/sync-rules", rules)
+	if err != nil {
+		return OIDCSyncRules{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OIDCSyncRules{}, ReadBodyAsError(res)
+	}
+	var updated OIDCSyncRules
+	return updated, json.NewDecoder(res.Body).Decode(&updated)
+}
+
+func (c *Client) OIDCSyncDryRun(ctx context.Context, req OIDCSyncDryRunRequest) (OIDCSyncDryRunResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/oidc/sync-rules/dry-run", req)
+	if err != nil {
+		return OIDCSyncDryRunResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OIDCSyncDryRunResponse{}, ReadBodyAsError(res)
+	}
+	var resp OIDCSyncDryRunResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -26,10 +26,10 @@ const (
 
 // Organization is the JSON representation of a Coder organization.
 type Organization struct {
-	ID        uuid.UUID `json:"id" validate:"required" format:"uuid"`
-	Name      string    `json:"name" validate:"required"`
-	CreatedAt time.Time `json:"created_at" validate:"required" format:"date-time"`
-	UpdatedAt time.Time `json:"updated_at" validate:"required" format:"date-time"`
+	ID        uuid.UUID `json:"id" validate:"required" table:"id" format:"uuid"`
+	Name      string    `json:"name" validate:"required" table:"name,default_sort"`
+	CreatedAt time.Time `json:"created_at" validate:"required" table:"created at" format:"date-time"`
+	UpdatedAt time.Time `json:"updated_at" validate:"required" table:"updated at" format:"date-time"`
 }
 
 type OrganizationMember struct {
@@ -40,6 +40,13 @@ type OrganizationMember struct {
 	Roles          []Role    `db:"roles" json:"roles"`
 }
 
+// OrganizationMemberWithName is an organization member along with the
+// username of the user it belongs to.
+type OrganizationMemberWithName struct {
+	Username string `json:"username"`
+	OrganizationMember
+}
+
 // CreateTemplateVersionRequest enables callers to create a new Template Version.
 type CreateTemplateVersionRequest struct {
 	Name    string `json:"name,omitempty" validate:"omitempty,template_version_name"`
@@ -163,6 +170,52 @@ func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization,
 	return organization, json.NewDecoder(res.Body).Decode(&organization)
 }
 
+// OrganizationMembers lists all members of an organization.
+func (c *Client) OrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]OrganizationMemberWithName, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/members", organizationID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var members []OrganizationMemberWithName
+	return members, json.NewDecoder(res.Body).Decode(&members)
+}
+
+// PostOrganizationMember adds a user to an organization.
+func (c *Client) PostOrganizationMember(ctx context.Context, organizationID uuid.UUID, user string) (OrganizationMember, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/members/%s", organizationID, user), nil)
+	if err != nil {
+		return OrganizationMember{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return OrganizationMember{}, ReadBodyAsError(res)
+	}
+
+	var member OrganizationMember
+	return member, json.NewDecoder(res.Body).Decode(&member)
+}
+
+// DeleteOrganizationMember removes a user from an organization.
+func (c *Client) DeleteOrganizationMember(ctx context.Context, organizationID uuid.UUID, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/members/%s", organizationID, user), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // ProvisionerDaemons returns provisioner daemons available.
 //
 // Deprecated: We no longer track provisioner daemons as they connect.  This function may return historical data
@@ -15,6 +15,7 @@ import (
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 
+	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/codersdk/drpc"
 	"github.com/coder/coder/v2/provisionerd/proto"
 	"github.com/coder/coder/v2/provisionerd/runner"
@@ -43,6 +44,10 @@ type ProvisionerDaemon struct {
 	Version      string            `json:"version"`
 	Provisioners []ProvisionerType `json:"provisioners"`
 	Tags         map[string]string `json:"tags"`
+	// KeyID is the ID of the provisioner key the daemon connected with, if any.
+	KeyID *uuid.UUID `json:"key_id,omitempty" format:"uuid"`
+	// KeyName is the name of the provisioner key the daemon connected with, if any.
+	KeyName string `json:"key_name,omitempty"`
 }
 
 // ProvisionerJobStatus represents the at-time state of a job.
@@ -67,6 +72,18 @@ const (
 	ProvisionerJobUnknown   ProvisionerJobStatus = "unknown"
 )
 
+// ProvisionerJobPriority determines the order in which queued jobs are
+// acquired. Jobs with a higher priority are acquired first.
+type ProvisionerJobPriority string
+
+// Priorities from highest to lowest.
+const (
+	ProvisionerJobPriorityUser      ProvisionerJobPriority = "user"
+	ProvisionerJobPriorityAutobuild ProvisionerJobPriority = "autobuild"
+	ProvisionerJobPriorityDryRun    ProvisionerJobPriority = "dry_run"
+	ProvisionerJobPriorityBatch     ProvisionerJobPriority = "batch"
+)
+
 // JobErrorCode defines the error code returned by job runner.
 type JobErrorCode string
 
@@ -82,19 +99,22 @@ func JobIsMissingParameterErrorCode(code JobErrorCode) bool {
 
 // ProvisionerJob describes the job executed by the provisioning daemon.
 type ProvisionerJob struct {
-	ID            uuid.UUID            `json:"id" format:"uuid"`
-	CreatedAt     time.Time            `json:"created_at" format:"date-time"`
-	StartedAt     *time.Time           `json:"started_at,omitempty" format:"date-time"`
-	CompletedAt   *time.Time           `json:"completed_at,omitempty" format:"date-time"`
-	CanceledAt    *time.Time           `json:"canceled_at,omitempty" format:"date-time"`
-	Error         string               `json:"error,omitempty"`
-	ErrorCode     JobErrorCode         `json:"error_code,omitempty" enums:"REQUIRED_TEMPLATE_VARIABLES"`
-	Status        ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
-	WorkerID      *uuid.UUID           `json:"worker_id,omitempty" format:"uuid"`
-	FileID        uuid.UUID            `json:"file_id" format:"uuid"`
-	Tags          map[string]string    `json:"tags"`
-	QueuePosition int                  `json:"queue_position"`
-	QueueSize     int                  `json:"queue_size"`
+	ID          uuid.UUID            `json:"id" format:"uuid"`
+	CreatedAt   time.Time            `json:"created_at" format:"date-time"`
+	StartedAt   *time.Time           `json:"started_at,omitempty" format:"date-time"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty" format:"date-time"`
+	CanceledAt  *time.Time           `json:"canceled_at,omitempty" format:"date-time"`
+	Error       string               `json:"error,omitempty"`
+	ErrorCode   JobErrorCode         `json:"error_code,omitempty" enums:"REQUIRED_TEMPLATE_VARIABLES"`
+	Status      ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
+	WorkerID    *uuid.UUID           `json:"worker_id,omitempty" format:"uuid"`
+	FileID      uuid.UUID            `json:"file_id" format:"uuid"`
+	Tags        map[string]string    `json:"tags"`
+	// QueuePosition is the position of a pending job in the queue, starting
+	// at 1. Jobs with a higher priority are ahead of older jobs.
+	QueuePosition int                    `json:"queue_position"`
+	QueueSize     int                    `json:"queue_size"`
+	Priority      ProvisionerJobPriority `json:"priority" enums:"user,autobuild,dry_run,batch"`
 }
 
 // ProvisionerJobLog represents the provisioner log entry annotated with source and level.
@@ -187,6 +207,10 @@ type ServeProvisionerDaemonRequest struct {
 	Tags map[string]string `json:"tags"`
 	// PreSharedKey is an authentication key to use on the API instead of the normal session token from the client.
 	PreSharedKey string `json:"pre_shared_key"`
+	// ProvisionerKey is a provisioner key to authenticate with instead of the
+	// normal session token from the client. The tags of the daemon are set by
+	// the key.
+	ProvisionerKey string `json:"provisioner_key"`
 }
 
 // ServeProvisionerDaemon returns the gRPC service for a provisioner daemon
@@ -200,6 +224,7 @@ func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisione
 	query := serverURL.Query()
 	query.Add("id", req.ID.String())
 	query.Add("name", req.Name)
+	query.Add("version", proto.CurrentVersion)
 	for _, provisioner := range req.Provisioners {
 		query.Add("provisioner", string(provisioner))
 	}
@@ -211,9 +236,15 @@ func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisione
 		Transport: c.HTTPClient.Transport,
 	}
 	headers := http.Header{}
+	headers.Set(BuildVersionHeader, buildinfo.Version())
 
-	if req.PreSharedKey == "" {
-		// use session token if we don't have a PSK.
+	switch {
+	case req.ProvisionerKey != "":
+		headers.Set(ProvisionerDaemonKey, req.ProvisionerKey)
+	case req.PreSharedKey != "":
+		headers.Set(ProvisionerDaemonPSK, req.PreSharedKey)
+	default:
+		// use session token if we don't have a PSK or key.
 		jar, err := cookiejar.New(nil)
 		if err != nil {
 			return nil, xerrors.Errorf("create cookie jar: %w", err)
@@ -223,8 +254,6 @@ func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisione
 			Value: c.SessionToken(),
 		}})
 		httpClient.Jar = jar
-	} else {
-		headers.Set(ProvisionerDaemonPSK, req.PreSharedKey)
 	}
 
 	conn, res, err := websocket.Dial(ctx, serverURL.String(), &websocket.DialOptions{
@@ -5,18 +5,114 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 )
 
+// Role is a set of permissions that can be assigned to users. Custom roles
+// also include the permissions they grant.
 type Role struct {
-	Name        string `json:"name"`
-	DisplayName string `json:"display_name"`
+	Name                    string       `json:"name"`
+	DisplayName             string       `json:"display_name"`
+	SitePermissions         []Permission `json:"site_permissions,omitempty"`
+	OrganizationPermissions []Permission `json:"organization_permissions,omitempty"`
+	UserPermissions         []Permission `json:"user_permissions,omitempty"`
+}
+
+// Permission is the format passed into the rego.
+type Permission struct {
+	// Negate makes this a negative permission.
+	Negate       bool         `json:"negate"`
+	ResourceType RBACResource `json:"resource_type"`
+	Action       string       `json:"action"`
+}
+
+// ParsePermission parses a permission in the form "[!]<resource>:<action>",
+// where a leading "!" negates the permission.
+func ParsePermission(s string) (Permission, error) {
+	negate := strings.HasPrefix(s, "!")
+	resource, action, ok := strings.Cut(strings.TrimPrefix(s, "!"), ":")
+	if !ok || resource == "" || action == "" {
+		return Permission{}, xerrors.Errorf("invalid permission %q, expected <resource>:<action>", s)
+	}
+	return Permission{
+		Negate:       negate,
+		ResourceType: RBACResource(resource),
+		Action:       action,
+	}, nil
+}
+
+// String returns the permission in the format accepted by ParsePermission.
+func (p Permission) String() string {
+	prefix := ""
+	if p.Negate {
+		prefix = "!"
+	}
+	return fmt.Sprintf("%s%s:%s", prefix, p.ResourceType, p.Action)
 }
 
 type AssignableRoles struct {
 	Role
 	Assignable bool `json:"assignable"`
+	// BuiltIn roles are compiled into coder and cannot be changed.
+	BuiltIn bool `json:"built_in"`
+}
+
+// PatchRole creates or updates a custom site wide role.
+func (c *Client) PatchRole(ctx context.Context, req Role) (Role, error) {
+	res, err := c.Request(ctx, http.MethodPatch, "/api/v2/users/roles", req)
+	if err != nil {
+		return Role{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Role{}, ReadBodyAsError(res)
+	}
+	var role Role
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// DeleteRole deletes a custom site wide role.
+func (c *Client) DeleteRole(ctx context.Context, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/roles/%s", name), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// PatchOrganizationRole creates or updates a custom role scoped to an
+// organization.
+func (c *Client) PatchOrganizationRole(ctx context.Context, org uuid.UUID, req Role) (Role, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s/members/roles", org.String()), req)
+	if err != nil {
+		return Role{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Role{}, ReadBodyAsError(res)
+	}
+	var role Role
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
+
+// DeleteOrganizationRole deletes a custom role scoped to an organization.
+func (c *Client) DeleteOrganizationRole(ctx context.Context, org uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/members/roles/%s", org.String(), name), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
 }
 
 // ListSiteRoles lists all assignable site wide roles.
@@ -0,0 +1,109 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateGitSource is a git branch that a template tracks. Pushes to the
+// branch create new template versions.
+type TemplateGitSource struct {
+	TemplateID    uuid.UUID `json:"template_id" format:"uuid"`
+	RepositoryURL string    `json:"repository_url"`
+	Branch        string    `json:"branch"`
+	// Subdirectory is the directory within the repository that contains the
+	// template. It is empty if the template is at the root.
+	Subdirectory string `json:"subdirectory"`
+	// AutoPromote makes versions created from the branch active once they
+	// import successfully.
+	AutoPromote bool `json:"auto_promote"`
+	// PollIntervalMillis is how often the branch is checked for new commits.
+	// Zero disables polling, in which case only webhooks trigger syncs.
+	PollIntervalMillis int64 `json:"poll_interval_ms"`
+	// WebhookURL accepts push events from GitHub and GitLab. Deliveries are
+	// verified with WebhookSecret.
+	WebhookURL            string     `json:"webhook_url"`
+	WebhookSecret         string     `json:"webhook_secret"`
+	LastCheckedAt         *time.Time `json:"last_checked_at,omitempty" format:"date-time"`
+	LastCommitSHA         string     `json:"last_commit_sha"`
+	LastTemplateVersionID *uuid.UUID `json:"last_template_version_id,omitempty" format:"uuid"`
+	// LastError is the error from the most recent sync, if it failed.
+	LastError string    `json:"last_error"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+// UpdateTemplateGitSourceRequest configures the git source of a template.
+type UpdateTemplateGitSourceRequest struct {
+	RepositoryURL      string `json:"repository_url" validate:"required"`
+	Branch             string `json:"branch" validate:"required"`
+	Subdirectory       string `json:"subdirectory,omitempty"`
+	AutoPromote        bool   `json:"auto_promote,omitempty"`
+	PollIntervalMillis int64  `json:"poll_interval_ms,omitempty"`
+	// RegenerateWebhookSecret replaces the webhook secret of an existing
+	// source. A secret is always generated for new sources.
+	RegenerateWebhookSecret bool `json:"regenerate_webhook_secret,omitempty"`
+}
+
+// TemplateGitSource returns the git source of a template.
+func (c *Client) TemplateGitSource(ctx context.Context, templateID uuid.UUID) (TemplateGitSource, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/git", templateID), nil)
+	if err != nil {
+		return TemplateGitSource{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateGitSource{}, ReadBodyAsError(res)
+	}
+	var source TemplateGitSource
+	return source, json.NewDecoder(res.Body).Decode(&source)
+}
+
+// UpdateTemplateGitSource creates or replaces the git source of a template.
+func (c *Client) UpdateTemplateGitSource(ctx context.Context, templateID uuid.UUID, req UpdateTemplateGitSourceRequest) (TemplateGitSource, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/git", templateID), req)
+	if err != nil {
+		return TemplateGitSource{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateGitSource{}, ReadBodyAsError(res)
+	}
+	var source TemplateGitSource
+	return source, json.NewDecoder(res.Body).Decode(&source)
+}
+
+// DeleteTemplateGitSource stops a template from tracking a git branch.
+// Existing template versions are kept.
+func (c *Client) DeleteTemplateGitSource(ctx context.Context, templateID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/git", templateID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// SyncTemplateGitSource checks the template's branch immediately and creates
+// a template version if it has new commits. The returned source records the
+// outcome of the sync.
+func (c *Client) SyncTemplateGitSource(ctx context.Context, templateID uuid.UUID) (TemplateGitSource, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/git/sync", templateID), nil)
+	if err != nil {
+		return TemplateGitSource{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateGitSource{}, ReadBodyAsError(res)
+	}
+	var source TemplateGitSource
+	return source, json.NewDecoder(res.Body).Decode(&source)
+}
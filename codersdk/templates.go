@@ -61,6 +61,10 @@ type Template struct {
 	// RequireActiveVersion mandates that workspaces are built with the active
 	// template version.
 	RequireActiveVersion bool `json:"require_active_version"`
+	// RecordSessions records terminal sessions in workspaces created from
+	// this template. Recordings can be listed and replayed by anyone who can
+	// read the workspace.
+	RecordSessions bool `json:"record_sessions"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -241,6 +245,9 @@ type UpdateTemplateMeta struct {
 	// If passed an empty string, will remove the deprecated message, making
 	// the template usable for new workspaces again.
 	DeprecationMessage *string `json:"deprecation_message"`
+	// RecordSessions records terminal sessions in workspaces created from
+	// this template.
+	RecordSessions bool `json:"record_sessions,omitempty"`
 }
 
 type TemplateExample struct {
@@ -30,10 +30,18 @@ type TemplateVersion struct {
 	Readme         string         `json:"readme"`
 	CreatedBy      MinimalUser    `json:"created_by"`
 	Archived       bool           `json:"archived"`
+	// GitCommit is set for versions created from a template's git source.
+	GitCommit *TemplateVersionGitCommit `json:"git_commit,omitempty"`
 
 	Warnings []TemplateVersionWarning `json:"warnings,omitempty" enums:"DEPRECATED_PARAMETERS"`
 }
 
+// TemplateVersionGitCommit is the commit a template version was created from.
+type TemplateVersionGitCommit struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+}
+
 type TemplateVersionExternalAuth struct {
 	ID              string `json:"id"`
 	Type            string `json:"type"`
@@ -188,6 +196,28 @@ type CreateTemplateVersionDryRunRequest struct {
 	WorkspaceName       string                    `json:"workspace_name"`
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values"`
 	UserVariableValues  []VariableValue           `json:"user_variable_values,omitempty"`
+	// WorkspaceID plans the dry-run against the state of an existing
+	// workspace of the template, to preview updating it to the version.
+	// The resource changes of the dry-run are only available if it is set.
+	WorkspaceID uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+}
+
+type ResourceChangeAction string
+
+const (
+	ResourceChangeActionCreate  ResourceChangeAction = "create"
+	ResourceChangeActionUpdate  ResourceChangeAction = "update"
+	ResourceChangeActionReplace ResourceChangeAction = "replace"
+	ResourceChangeActionDelete  ResourceChangeAction = "delete"
+)
+
+// ResourceChange is a change a template version dry-run plans to make to a
+// resource of an existing workspace.
+type ResourceChange struct {
+	Address string               `json:"address"`
+	Type    string               `json:"type"`
+	Name    string               `json:"name"`
+	Action  ResourceChangeAction `json:"action" enums:"create,update,replace,delete"`
 }
 
 // CreateTemplateVersionDryRun begins a dry-run provisioner job against the
@@ -238,6 +268,23 @@ func (c *Client) TemplateVersionDryRunResources(ctx context.Context, version, jo
 	return resources, json.NewDecoder(res.Body).Decode(&resources)
 }
 
+// TemplateVersionDryRunResourceChanges returns the changes a finished
+// template version dry-run plans to make to the resources of the workspace
+// it was created for.
+func (c *Client) TemplateVersionDryRunResourceChanges(ctx context.Context, version, job uuid.UUID) ([]ResourceChange, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/dry-run/%s/resource-changes", version, job), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var changes []ResourceChange
+	return changes, json.NewDecoder(res.Body).Decode(&changes)
+}
+
 // TemplateVersionDryRunLogsAfter streams logs for a template version dry-run
 // that occurred after a specific log ID.
 func (c *Client) TemplateVersionDryRunLogsAfter(ctx context.Context, version, job uuid.UUID, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
@@ -176,6 +176,9 @@ type AuthMethods struct {
 	Password AuthMethod     `json:"password"`
 	Github   AuthMethod     `json:"github"`
 	OIDC     OIDCAuthMethod `json:"oidc"`
+	// LoginProviders are the named identity providers users can log in with,
+	// in addition to the GitHub and OIDC providers.
+	LoginProviders []LoginProviderAuthMethod `json:"login_providers"`
 }
 
 type AuthMethod struct {
@@ -192,6 +195,16 @@ type OIDCAuthMethod struct {
 	IconURL    string `json:"iconUrl"`
 }
 
+type LoginProviderAuthMethod struct {
+	ID          string    `json:"id"`
+	Type        LoginType `json:"type" enums:"github,oidc"`
+	DisplayName string    `json:"display_name"`
+	IconURL     string    `json:"icon_url"`
+	// CallbackURL is the path that starts the login flow. It accepts a
+	// "redirect" query parameter with the path to return to after logging in.
+	CallbackURL string `json:"callback_url"`
+}
+
 // HasFirstUser returns whether the first user has been created.
 func (c *Client) HasFirstUser(ctx context.Context) (bool, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/first", nil)
@@ -1,6 +1,7 @@
 package codersdk
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/json"
@@ -194,6 +195,44 @@ func (c *WorkspaceAgentConn) Close() error {
 	return c.Conn.Close()
 }
 
+// ReconnectingPTYMode is what a connection to a reconnecting PTY is allowed to
+// do.
+type ReconnectingPTYMode string
+
+const (
+	// ReconnectingPTYModeOwner connections can type into and resize the PTY,
+	// and start the session if it is not running.  An empty mode is the same
+	// as the owner mode.
+	ReconnectingPTYModeOwner ReconnectingPTYMode = "owner"
+	// ReconnectingPTYModeCollaborator connections can type into a running
+	// session, but do not resize it.
+	ReconnectingPTYModeCollaborator ReconnectingPTYMode = "collaborator"
+	// ReconnectingPTYModeObserver connections only receive the output of a
+	// running session.
+	ReconnectingPTYModeObserver ReconnectingPTYMode = "observer"
+)
+
+// Valid returns whether the mode is empty or a known mode.
+func (m ReconnectingPTYMode) Valid() bool {
+	switch m {
+	case "", ReconnectingPTYModeOwner, ReconnectingPTYModeCollaborator, ReconnectingPTYModeObserver:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanWrite returns whether input from the connection is sent to the PTY.
+func (m ReconnectingPTYMode) CanWrite() bool {
+	return m != ReconnectingPTYModeObserver
+}
+
+// CanResize returns whether the connection resizes the PTY.  It also decides
+// whether the connection can start a new session.
+func (m ReconnectingPTYMode) CanResize() bool {
+	return m == "" || m == ReconnectingPTYModeOwner
+}
+
 // WorkspaceAgentReconnectingPTYInit initializes a new reconnecting PTY session.
 // @typescript-ignore WorkspaceAgentReconnectingPTYInit
 type WorkspaceAgentReconnectingPTYInit struct {
@@ -201,6 +240,22 @@ type WorkspaceAgentReconnectingPTYInit struct {
 	Height  uint16
 	Width   uint16
 	Command string
+	// Mode is empty for connections from older clients, which are treated as
+	// the owner.
+	Mode ReconnectingPTYMode
+}
+
+// WorkspaceAgentReconnectingPTYInitOption sets an optional field of the
+// reconnecting PTY init message.
+// @typescript-ignore WorkspaceAgentReconnectingPTYInitOption
+type WorkspaceAgentReconnectingPTYInitOption func(*WorkspaceAgentReconnectingPTYInit)
+
+// WithReconnectingPTYMode attaches to the reconnecting PTY with the provided
+// mode instead of as the owner.
+func WithReconnectingPTYMode(mode ReconnectingPTYMode) WorkspaceAgentReconnectingPTYInitOption {
+	return func(init *WorkspaceAgentReconnectingPTYInit) {
+		init.Mode = mode
+	}
 }
 
 // ReconnectingPTYRequest is sent from the client to the server
@@ -215,7 +270,7 @@ type ReconnectingPTYRequest struct {
 // ReconnectingPTY spawns a new reconnecting terminal session.
 // `ReconnectingPTYRequest` should be JSON marshaled and written to the returned net.Conn.
 // Raw terminal output will be read from the returned net.Conn.
-func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16, command string) (net.Conn, error) {
+func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16, command string, initOpts ...WorkspaceAgentReconnectingPTYInitOption) (net.Conn, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
@@ -227,12 +282,16 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	data, err := json.Marshal(WorkspaceAgentReconnectingPTYInit{
+	init := WorkspaceAgentReconnectingPTYInit{
 		ID:      id,
 		Height:  height,
 		Width:   width,
 		Command: command,
-	})
+	}
+	for _, opt := range initOpts {
+		opt(&init)
+	}
+	data, err := json.Marshal(init)
 	if err != nil {
 		_ = conn.Close()
 		return nil, err
@@ -362,6 +421,132 @@ func (c *WorkspaceAgentConn) ListeningPorts(ctx context.Context) (WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+type WorkspaceAgentReconnectingPTYsResponse struct {
+	Sessions []WorkspaceAgentReconnectingPTY `json:"sessions"`
+}
+
+// WorkspaceAgentReconnectingPTY is an active reconnecting PTY session, such as
+// a web terminal.
+type WorkspaceAgentReconnectingPTY struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	Command   string    `json:"command"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	// Connections is the number of connections attached to the session,
+	// including observers.
+	Connections int `json:"connections"`
+	Observers   int `json:"observers"`
+}
+
+// ReconnectingPTYs lists the reconnecting PTY sessions that are running in the
+// workspace.
+func (c *WorkspaceAgentConn) ReconnectingPTYs(ctx context.Context) (WorkspaceAgentReconnectingPTYsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/reconnecting-ptys", nil)
+	if err != nil {
+		return WorkspaceAgentReconnectingPTYsResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentReconnectingPTYsResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentReconnectingPTYsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentExecRequest runs a command in the workspace without a PTY.
+type WorkspaceAgentExecRequest struct {
+	// Command is run with the user's shell, like an SSH command.
+	Command string            `json:"command"`
+	Env     map[string]string `json:"env,omitempty"`
+	// Dir is the working directory. Relative paths are resolved against the
+	// agent's directory, or the user's home directory if unset.
+	Dir string `json:"dir,omitempty"`
+	// TimeoutMillis kills the command if it runs for longer. Zero means no
+	// timeout.
+	TimeoutMillis int64 `json:"timeout_ms,omitempty"`
+}
+
+type WorkspaceAgentExecFrameType string
+
+const (
+	WorkspaceAgentExecFrameStdout WorkspaceAgentExecFrameType = "stdout"
+	WorkspaceAgentExecFrameStderr WorkspaceAgentExecFrameType = "stderr"
+	WorkspaceAgentExecFrameExit   WorkspaceAgentExecFrameType = "exit"
+)
+
+// WorkspaceAgentExecFrame is a chunk of output from an exec, or its exit
+// status. The exit frame is always the last one sent.
+type WorkspaceAgentExecFrame struct {
+	Type WorkspaceAgentExecFrameType `json:"type"`
+	Data []byte                      `json:"data,omitempty"`
+	// ExitCode is only set on the exit frame.
+	ExitCode int `json:"exit_code,omitempty"`
+	// Error is set on the exit frame if the command could not be started,
+	// or was killed before it exited, e.g. by the timeout.
+	Error string `json:"error,omitempty"`
+}
+
+// Exec runs a command in the workspace. The output is streamed back as it's
+// written, so callers must read the stream until the exit frame and then close
+// it.
+func (c *WorkspaceAgentConn) Exec(ctx context.Context, req WorkspaceAgentExecRequest) (*WorkspaceAgentExecStream, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal request: %w", err)
+	}
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/exec", bytes.NewReader(body))
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return &WorkspaceAgentExecStream{
+		body:    res.Body,
+		decoder: json.NewDecoder(res.Body),
+	}, nil
+}
+
+// WorkspaceAgentExecStream reads the frames of a running exec.
+// @typescript-ignore WorkspaceAgentExecStream
+type WorkspaceAgentExecStream struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+	exited  bool
+}
+
+// Recv returns the next frame. After the exit frame it returns io.EOF.
+func (s *WorkspaceAgentExecStream) Recv() (WorkspaceAgentExecFrame, error) {
+	if s.exited {
+		return WorkspaceAgentExecFrame{}, io.EOF
+	}
+	var frame WorkspaceAgentExecFrame
+	err := s.decoder.Decode(&frame)
+	if err != nil {
+		if xerrors.Is(err, io.EOF) {
+			// The agent always ends with an exit frame, so the connection
+			// was lost.
+			err = io.ErrUnexpectedEOF
+		}
+		return WorkspaceAgentExecFrame{}, xerrors.Errorf("read exec frame: %w", err)
+	}
+	if frame.Type == WorkspaceAgentExecFrameExit {
+		s.exited = true
+	}
+	return frame, nil
+}
+
+// Close stops reading the stream. If the command is still running, it's
+// killed.
+func (s *WorkspaceAgentExecStream) Close() error {
+	return s.body.Close()
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *WorkspaceAgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -575,6 +575,8 @@ type IssueReconnectingPTYSignedTokenRequest struct {
 	// URL is the URL of the reconnecting-pty endpoint you are connecting to.
 	URL     string    `json:"url" validate:"required"`
 	AgentID uuid.UUID `json:"agentID" format:"uuid" validate:"required"`
+	// Mode must match the mode the reconnecting PTY is opened with.
+	Mode ReconnectingPTYMode `json:"mode,omitempty" enums:"owner,collaborator,observer"`
 }
 
 type IssueReconnectingPTYSignedTokenResponse struct {
@@ -601,6 +603,9 @@ type WorkspaceAgentReconnectingPTYOpts struct {
 	Width     uint16
 	Height    uint16
 	Command   string
+	// Mode is the mode to attach with.  Connections other than the owner
+	// can only attach to a session that is already running.
+	Mode ReconnectingPTYMode
 
 	// SignedToken is an optional signed token from the
 	// issue-reconnecting-pty-signed-token endpoint. If set, the session token
@@ -621,6 +626,9 @@ func (c *Client) WorkspaceAgentReconnectingPTY(ctx context.Context, opts Workspa
 	q.Set("width", strconv.Itoa(int(opts.Width)))
 	q.Set("height", strconv.Itoa(int(opts.Height)))
 	q.Set("command", opts.Command)
+	if opts.Mode != "" {
+		q.Set("mode", string(opts.Mode))
+	}
 	// If we're using a signed token, set the query parameter.
 	if opts.SignedToken != "" {
 		q.Set(SignedAppTokenQueryParameter, opts.SignedToken)
@@ -656,6 +664,21 @@ func (c *Client) WorkspaceAgentReconnectingPTY(ctx context.Context, opts Workspa
 	return websocket.NetConn(context.Background(), conn, websocket.MessageBinary), nil
 }
 
+// WorkspaceAgentReconnectingPTYs returns the reconnecting PTY sessions, such as
+// web terminals, that are running on the agent.
+func (c *Client) WorkspaceAgentReconnectingPTYs(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentReconnectingPTYsResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/reconnecting-ptys", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentReconnectingPTYsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentReconnectingPTYsResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentReconnectingPTYsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // WorkspaceAgentListeningPorts returns a list of ports that are currently being
 // listened on inside the workspace agent's network namespace.
 func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentListeningPortsResponse, error) {
@@ -173,3 +173,89 @@ func (c *Client) WorkspaceBuildParameters(ctx context.Context, build uuid.UUID)
 	var params []WorkspaceBuildParameter
 	return params, json.NewDecoder(res.Body).Decode(&params)
 }
+
+// ProvisionerTimingStage is a stage of provisioning a workspace build.
+type ProvisionerTimingStage string
+
+const (
+	ProvisionerTimingStageInit  ProvisionerTimingStage = "init"
+	ProvisionerTimingStagePlan  ProvisionerTimingStage = "plan"
+	ProvisionerTimingStageGraph ProvisionerTimingStage = "graph"
+	ProvisionerTimingStageApply ProvisionerTimingStage = "apply"
+)
+
+// WorkspaceAgentScriptTimingStage is the part of the agent lifecycle a script
+// ran in.
+type WorkspaceAgentScriptTimingStage string
+
+const (
+	WorkspaceAgentScriptTimingStageStart WorkspaceAgentScriptTimingStage = "start"
+	WorkspaceAgentScriptTimingStageStop  WorkspaceAgentScriptTimingStage = "stop"
+)
+
+// WorkspaceAgentScriptTimingStatus is how a script run ended.
+type WorkspaceAgentScriptTimingStatus string
+
+const (
+	WorkspaceAgentScriptTimingStatusOK            WorkspaceAgentScriptTimingStatus = "ok"
+	WorkspaceAgentScriptTimingStatusExitFailure   WorkspaceAgentScriptTimingStatus = "exit_failure"
+	WorkspaceAgentScriptTimingStatusTimedOut      WorkspaceAgentScriptTimingStatus = "timed_out"
+	WorkspaceAgentScriptTimingStatusPipesLeftOpen WorkspaceAgentScriptTimingStatus = "pipes_left_open"
+)
+
+// ProvisionerTiming is the time spent on a whole provisioning stage, or on a
+// single resource within a stage. Stage timings have an empty resource.
+type ProvisionerTiming struct {
+	JobID     uuid.UUID              `json:"job_id" format:"uuid"`
+	StartedAt time.Time              `json:"started_at" format:"date-time"`
+	EndedAt   time.Time              `json:"ended_at" format:"date-time"`
+	Stage     ProvisionerTimingStage `json:"stage"`
+	// Source is the provider that managed the resource, e.g. "docker".
+	Source string `json:"source"`
+	// Action is what was done to the resource, e.g. "create" or "refresh".
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+}
+
+// AgentScriptTiming is the time spent running a start or stop script.
+type AgentScriptTiming struct {
+	StartedAt          time.Time                        `json:"started_at" format:"date-time"`
+	EndedAt            time.Time                        `json:"ended_at" format:"date-time"`
+	ExitCode           int32                            `json:"exit_code"`
+	Stage              WorkspaceAgentScriptTimingStage  `json:"stage"`
+	Status             WorkspaceAgentScriptTimingStatus `json:"status"`
+	DisplayName        string                           `json:"display_name"`
+	WorkspaceAgentID   uuid.UUID                        `json:"workspace_agent_id" format:"uuid"`
+	WorkspaceAgentName string                           `json:"workspace_agent_name"`
+}
+
+// AgentConnectionTiming is the time between an agent being created by a build
+// and it first connecting.
+type AgentConnectionTiming struct {
+	StartedAt          time.Time `json:"started_at" format:"date-time"`
+	EndedAt            time.Time `json:"ended_at" format:"date-time"`
+	WorkspaceAgentID   uuid.UUID `json:"workspace_agent_id" format:"uuid"`
+	WorkspaceAgentName string    `json:"workspace_agent_name"`
+}
+
+// WorkspaceBuildTimings is a timeline of where the time in a workspace build
+// went, from provisioning to the agents finishing their startup scripts.
+type WorkspaceBuildTimings struct {
+	ProvisionerTimings     []ProvisionerTiming     `json:"provisioner_timings"`
+	AgentScriptTimings     []AgentScriptTiming     `json:"agent_script_timings"`
+	AgentConnectionTimings []AgentConnectionTiming `json:"agent_connection_timings"`
+}
+
+// WorkspaceBuildTimings returns the timeline of a workspace build.
+func (c *Client) WorkspaceBuildTimings(ctx context.Context, build uuid.UUID) (WorkspaceBuildTimings, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/timings", build), nil)
+	if err != nil {
+		return WorkspaceBuildTimings{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildTimings{}, ReadBodyAsError(res)
+	}
+	var timings WorkspaceBuildTimings
+	return timings, json.NewDecoder(res.Body).Decode(&timings)
+}
@@ -0,0 +1,73 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspacePortShare is a signed link that lets anyone holding it open a port
+// in a workspace until the link expires or is revoked.
+type WorkspacePortShare struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	AgentName   string    `json:"agent_name"`
+	Port        int32     `json:"port"`
+	CreatedBy   uuid.UUID `json:"created_by" format:"uuid"`
+	CreatedAt   time.Time `json:"created_at" format:"date-time"`
+	ExpiresAt   time.Time `json:"expires_at" format:"date-time"`
+	// URL is the link to share. It works without signing in to Coder.
+	URL string `json:"url"`
+}
+
+type CreateWorkspacePortShareRequest struct {
+	AgentName string `json:"agent_name" validate:"required"`
+	Port      int32  `json:"port" validate:"required"`
+	// TTLMillis is how long the link is valid for.
+	TTLMillis int64 `json:"ttl_ms" validate:"required"`
+}
+
+// WorkspacePortShares returns the active port share links of a workspace.
+func (c *Client) WorkspacePortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspacePortShare, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/port-shares", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var shares []WorkspacePortShare
+	return shares, json.NewDecoder(res.Body).Decode(&shares)
+}
+
+// CreateWorkspacePortShare creates a link to a port in a workspace.
+func (c *Client) CreateWorkspacePortShare(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspacePortShareRequest) (WorkspacePortShare, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/port-shares", workspaceID), req)
+	if err != nil {
+		return WorkspacePortShare{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspacePortShare{}, ReadBodyAsError(res)
+	}
+	var share WorkspacePortShare
+	return share, json.NewDecoder(res.Body).Decode(&share)
+}
+
+// DeleteWorkspacePortShare revokes a port share link.
+func (c *Client) DeleteWorkspacePortShare(ctx context.Context, workspaceID, shareID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/port-shares/%s", workspaceID, shareID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -99,6 +99,10 @@ type CreateWorkspaceBuildRequest struct {
 
 	// Log level changes the default logging verbosity of a provider ("info" if empty).
 	LogLevel ProvisionerLogLevel `json:"log_level,omitempty" validate:"omitempty,oneof=debug"`
+	// Priority lowers the priority of the build so that it doesn't delay other
+	// users' builds, e.g. when updating many workspaces at once. Only "batch"
+	// may be requested.
+	Priority ProvisionerJobPriority `json:"priority,omitempty" validate:"omitempty,oneof=batch"`
 }
 
 type WorkspaceOptions struct {
@@ -344,6 +348,77 @@ func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UU
 	return nil
 }
 
+// WorkspaceRole is the level of access granted to a user or group that a
+// workspace has been shared with.
+type WorkspaceRole string
+
+const (
+	// WorkspaceRoleUse grants access to the workspace agent and apps, and
+	// allows starting and stopping the workspace.
+	WorkspaceRoleUse WorkspaceRole = "use"
+	// WorkspaceRoleSSH grants access to the workspace agent over SSH and the
+	// web terminal.
+	WorkspaceRoleSSH WorkspaceRole = "ssh"
+	// WorkspaceRoleApp grants access to the workspace apps.
+	WorkspaceRoleApp     WorkspaceRole = "app"
+	WorkspaceRoleDeleted WorkspaceRole = ""
+)
+
+type WorkspaceACL struct {
+	Users  []WorkspaceUser  `json:"users"`
+	Groups []WorkspaceGroup `json:"groups"`
+}
+
+type WorkspaceUser struct {
+	MinimalUser
+	Role WorkspaceRole `json:"role" enums:"use,ssh,app"`
+}
+
+type WorkspaceGroup struct {
+	ID          uuid.UUID     `json:"id" format:"uuid"`
+	Name        string        `json:"name"`
+	DisplayName string        `json:"display_name"`
+	AvatarURL   string        `json:"avatar_url"`
+	Role        WorkspaceRole `json:"role" enums:"use,ssh,app"`
+}
+
+type UpdateWorkspaceACL struct {
+	// UserRoles should be a mapping of user id or username to role. An empty
+	// role removes the user from the ACL.
+	UserRoles map[string]WorkspaceRole `json:"user_roles,omitempty" example:"<user_id>:use,4df59e74-c027-470b-ab4d-cbba8963a5e9:ssh"`
+	// GroupRoles should be a mapping of group id or name to role. An empty
+	// role removes the group from the ACL.
+	GroupRoles map[string]WorkspaceRole `json:"group_roles,omitempty" example:"<group_id>:use,8bd26b20-f3e8-48be-a903-46bb920cf671:app"`
+}
+
+// WorkspaceACL returns the users and groups a workspace has been shared with.
+func (c *Client) WorkspaceACL(ctx context.Context, id uuid.UUID) (WorkspaceACL, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/acl", id), nil)
+	if err != nil {
+		return WorkspaceACL{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceACL{}, ReadBodyAsError(res)
+	}
+	var acl WorkspaceACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
+// UpdateWorkspaceACL shares a workspace with, or stops sharing it with, the
+// users and groups in the request.
+func (c *Client) UpdateWorkspaceACL(ctx context.Context, id uuid.UUID, req UpdateWorkspaceACL) error {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/workspaces/%s/acl", id), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type WorkspaceFilter struct {
 	// Owner can be "me" or a username
 	Owner string `json:"owner,omitempty" typescript:"-"`
@@ -0,0 +1,71 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceSessionRecordingType string
+
+const (
+	// WorkspaceSessionRecordingTypeSSH is a session started with a PTY over
+	// SSH, e.g. "coder ssh".
+	WorkspaceSessionRecordingTypeSSH WorkspaceSessionRecordingType = "ssh"
+	// WorkspaceSessionRecordingTypeReconnectingPTY is a connection to a web
+	// terminal.
+	WorkspaceSessionRecordingTypeReconnectingPTY WorkspaceSessionRecordingType = "reconnecting_pty"
+)
+
+// WorkspaceSessionRecordingContentType is the media type of a recording. They
+// are stored in asciinema's asciicast v2 format, which can be played with
+// "coder sessions replay" or "asciinema play".
+const WorkspaceSessionRecordingContentType = "application/x-asciicast"
+
+// WorkspaceSessionRecording is a terminal session that was recorded by a
+// workspace agent.
+type WorkspaceSessionRecording struct {
+	ID               uuid.UUID                     `json:"id" format:"uuid"`
+	WorkspaceID      uuid.UUID                     `json:"workspace_id" format:"uuid"`
+	WorkspaceBuildID uuid.UUID                     `json:"workspace_build_id" format:"uuid"`
+	AgentID          uuid.UUID                     `json:"agent_id" format:"uuid"`
+	Type             WorkspaceSessionRecordingType `json:"type" enums:"ssh,reconnecting_pty"`
+	StartedAt        time.Time                     `json:"started_at" format:"date-time"`
+	EndedAt          time.Time                     `json:"ended_at" format:"date-time"`
+	// Size is the size of the recording in bytes.
+	Size int64 `json:"size"`
+}
+
+// WorkspaceSessionRecordings returns the recorded sessions of a workspace,
+// most recent first.
+func (c *Client) WorkspaceSessionRecordings(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []WorkspaceSessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// WorkspaceSessionRecordingCast returns the asciicast v2 contents of a
+// recorded session. The caller must close the returned reader.
+func (c *Client) WorkspaceSessionRecordingCast(ctx context.Context, workspaceID, recordingID uuid.UUID) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/session-recordings/%s", workspaceID, recordingID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
@@ -0,0 +1,64 @@
+package backends
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+// jsonLog is the representation of an audit log sent to external backends.
+// Its fields must not be changed in a backwards-incompatible way, since
+// consumers parse it.
+type jsonLog struct {
+	ID               uuid.UUID             `json:"id"`
+	Time             time.Time             `json:"time"`
+	UserID           uuid.UUID             `json:"user_id"`
+	OrganizationID   uuid.UUID             `json:"organization_id"`
+	IP               string                `json:"ip"`
+	UserAgent        string                `json:"user_agent"`
+	ResourceType     database.ResourceType `json:"resource_type"`
+	ResourceID       uuid.UUID             `json:"resource_id"`
+	ResourceTarget   string                `json:"resource_target"`
+	ResourceIcon     string                `json:"resource_icon"`
+	Action           database.AuditAction  `json:"action"`
+	Diff             json.RawMessage       `json:"diff"`
+	StatusCode       int32                 `json:"status_code"`
+	AdditionalFields json.RawMessage       `json:"additional_fields"`
+	RequestID        uuid.UUID             `json:"request_id"`
+	Actor            *audit.Actor          `json:"actor,omitempty"`
+}
+
+func newJSONLog(alog database.AuditLog, details audit.BackendDetails) jsonLog {
+	l := jsonLog{
+		ID:               alog.ID,
+		Time:             alog.Time,
+		UserID:           alog.UserID,
+		OrganizationID:   alog.OrganizationID,
+		UserAgent:        alog.UserAgent.String,
+		ResourceType:     alog.ResourceType,
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		ResourceIcon:     alog.ResourceIcon,
+		Action:           alog.Action,
+		Diff:             alog.Diff,
+		StatusCode:       alog.StatusCode,
+		AdditionalFields: alog.AdditionalFields,
+		RequestID:        alog.RequestID,
+		Actor:            details.Actor,
+	}
+	if alog.Ip.Valid {
+		l.IP = alog.Ip.IPNet.IP.String()
+	}
+	// Empty raw messages are invalid JSON.
+	if len(l.Diff) == 0 {
+		l.Diff = json.RawMessage("{}")
+	}
+	if len(l.AdditionalFields) == 0 {
+		l.AdditionalFields = json.RawMessage("{}")
+	}
+	return l
+}
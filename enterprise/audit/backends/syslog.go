@@ -0,0 +1,222 @@
+package backends
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+const (
+	// syslogFacilityLogAudit is the "log audit" facility from RFC 5424.
+	syslogFacilityLogAudit = 13
+	syslogSeverityInfo     = 6
+	syslogAppName          = "coder"
+	syslogMsgID            = "audit"
+	// syslogTimestampFormat is RFC 3339 limited to microsecond precision,
+	// as required by RFC 5424.
+	syslogTimestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// syslogTimeout bounds how long dialing the syslog server and writing a
+// message may take.
+const syslogTimeout = 10 * time.Second
+
+// SyslogOptions configures a syslog backend.
+type SyslogOptions struct {
+	// Address is the host:port of the syslog server.
+	Address string
+	// TLSConfig enables TLS when set.
+	TLSConfig *tls.Config
+	// Hostname is sent as the HOSTNAME of each message. It defaults to the
+	// hostname of the machine.
+	Hostname string
+	// QueueSize is the number of messages held while the syslog server is
+	// slow or unavailable. Messages are dropped when the queue is full.
+	QueueSize int
+	// Dial connects to the syslog server. It defaults to dialing TCP, or TLS
+	// if TLSConfig is set.
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// SyslogBackend sends audit logs as RFC 5424 messages to a syslog server
+// over TCP, optionally with TLS. Messages are framed using octet counting
+// as described in RFC 6587 and RFC 5425. Export never blocks on the network;
+// messages are queued and sent in the background.
+type SyslogBackend struct {
+	opts     SyslogOptions
+	log      slog.Logger
+	hostname string
+	procID   string
+
+	queue   chan []byte
+	closing chan struct{}
+	done    chan struct{}
+
+	// conn is only used by the goroutine sending messages.
+	conn net.Conn
+}
+
+func NewSyslog(logger slog.Logger, opts SyslogOptions) *SyslogBackend {
+	hostname := opts.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+	if hostname == "" {
+		hostname = "-"
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.Dial == nil {
+		if opts.TLSConfig != nil {
+			d := &tls.Dialer{NetDialer: &net.Dialer{Timeout: syslogTimeout}, Config: opts.TLSConfig}
+			opts.Dial = d.DialContext
+		} else {
+			d := &net.Dialer{Timeout: syslogTimeout}
+			opts.Dial = d.DialContext
+		}
+	}
+	b := &SyslogBackend{
+		opts:     opts,
+		log:      logger.Named("audit_syslog"),
+		hostname: hostname,
+		procID:   strconv.Itoa(os.Getpid()),
+		queue:    make(chan []byte, opts.QueueSize),
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (*SyslogBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *SyslogBackend) Export(_ context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	msg, err := b.format(alog, details)
+	if err != nil {
+		return err
+	}
+	frame := []byte(fmt.Sprintf("%d %s", len(msg), msg))
+
+	select {
+	case <-b.closing:
+		return xerrors.New("syslog backend is closed")
+	default:
+	}
+	select {
+	case b.queue <- frame:
+		return nil
+	default:
+		return xerrors.New("syslog queue is full, dropping audit log")
+	}
+}
+
+// Close sends any queued audit logs and closes the connection to the syslog
+// server.
+func (b *SyslogBackend) Close() error {
+	select {
+	case <-b.closing:
+	default:
+		close(b.closing)
+	}
+	<-b.done
+	return nil
+}
+
+func (b *SyslogBackend) run() {
+	defer close(b.done)
+	defer func() {
+		if b.conn != nil {
+			_ = b.conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case <-b.closing:
+			// Send whatever is left, but give up on the first failure so
+			// shutdown isn't held up by an unavailable server.
+			for {
+				select {
+				case frame := <-b.queue:
+					if err := b.send(frame); err != nil {
+						b.log.Error(context.Background(), "dropping audit logs after failing to send them to syslog server",
+							slog.F("count", len(b.queue)+1),
+							slog.Error(err),
+						)
+						return
+					}
+				default:
+					return
+				}
+			}
+		case frame := <-b.queue:
+			if err := b.send(frame); err != nil {
+				b.log.Error(context.Background(), "dropping audit log after failing to send it to syslog server", slog.Error(err))
+			}
+		}
+	}
+}
+
+// send writes a framed message to the syslog server. The server may have
+// closed an idle connection, so it reconnects and retries once before giving
+// up.
+func (b *SyslogBackend) send(frame []byte) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if b.conn == nil {
+			b.conn, err = b.dial()
+			if err != nil {
+				return xerrors.Errorf("dial syslog server: %w", err)
+			}
+		}
+		_ = b.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+		_, err = b.conn.Write(frame)
+		if err == nil {
+			return nil
+		}
+		_ = b.conn.Close()
+		b.conn = nil
+		if attempt > 0 {
+			return xerrors.Errorf("write to syslog server: %w", err)
+		}
+	}
+}
+
+func (b *SyslogBackend) dial() (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), syslogTimeout)
+	defer cancel()
+	return b.opts.Dial(ctx, "tcp", b.opts.Address)
+}
+
+// format returns the audit log as an RFC 5424 message with the JSON
+// encoded log as its MSG.
+func (b *SyslogBackend) format(alog database.AuditLog, details audit.BackendDetails) (string, error) {
+	body, err := json.Marshal(newJSONLog(alog, details))
+	if err != nil {
+		return "", xerrors.Errorf("marshal audit log: %w", err)
+	}
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s - %s",
+		syslogFacilityLogAudit*8+syslogSeverityInfo,
+		alog.Time.UTC().Format(syslogTimestampFormat),
+		b.hostname,
+		syslogAppName,
+		b.procID,
+		syslogMsgID,
+		body,
+	), nil
+}
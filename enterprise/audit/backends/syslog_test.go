@@ -0,0 +1,155 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSyslogBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		addr, msgs := fakeSyslogServer(t)
+
+		backend := backends.NewSyslog(slogtest.Make(t, nil), backends.SyslogOptions{
+			Address:  addr,
+			Hostname: "coder-0",
+		})
+		t.Cleanup(func() { _ = backend.Close() })
+		require.Equal(t, audit.FilterDecisionExport, backend.Decision())
+
+		alog := audittest.RandomLog()
+		alog.Time = time.Date(2023, 11, 10, 23, 0, 0, 123456789, time.UTC)
+		require.NoError(t, backend.Export(ctx, alog, audit.BackendDetails{
+			Actor: &audit.Actor{ID: alog.UserID, Username: "alice"},
+		}))
+
+		msg := testutil.RequireRecvCtx(ctx, t, msgs)
+		// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+		re := regexp.MustCompile(`^<110>1 2023-11-10T23:00:00\.123456Z coder-0 coder \d+ audit - (\{.*\})$`)
+		matches := re.FindStringSubmatch(msg)
+		require.Len(t, matches, 2, "unexpected message %q", msg)
+
+		var got struct {
+			ID    uuid.UUID    `json:"id"`
+			Actor *audit.Actor `json:"actor"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(matches[1]), &got))
+		require.Equal(t, alog.ID, got.ID)
+		require.Equal(t, "alice", got.Actor.Username)
+
+		// Subsequent logs reuse the connection.
+		require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		_ = testutil.RequireRecvCtx(ctx, t, msgs)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := l.Addr().String()
+		require.NoError(t, l.Close())
+
+		// Logs are queued, so an unreachable server doesn't fail or block
+		// the audited request.
+		backend := backends.NewSyslog(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), backends.SyslogOptions{Address: addr})
+		require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		require.NoError(t, backend.Close())
+		require.Error(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+	})
+
+	t.Run("QueueFull", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		release := make(chan struct{})
+		backend := backends.NewSyslog(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), backends.SyslogOptions{
+			Address:   "syslog.invalid:6514",
+			QueueSize: 1,
+			Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				// A slow server holds up sending, but not exporting.
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return nil, xerrors.New("unreachable")
+			},
+		})
+
+		// At most one log is being sent and one is queued, so the third is
+		// dropped.
+		var errs int
+		for i := 0; i < 3; i++ {
+			if backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}) != nil {
+				errs++
+			}
+		}
+		require.Positive(t, errs)
+		close(release)
+		require.NoError(t, backend.Close())
+	})
+}
+
+// fakeSyslogServer accepts octet-counted syslog messages over TCP and sends
+// them on the returned channel.
+func fakeSyslogServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	msgs := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					length, err := r.ReadString(' ')
+					if err != nil {
+						return
+					}
+					n, err := strconv.Atoi(strings.TrimSpace(length))
+					if err != nil {
+						return
+					}
+					buf := make([]byte, n)
+					if _, err := io.ReadFull(r, buf); err != nil {
+						return
+					}
+					msgs <- string(buf)
+				}
+			}()
+		}
+	}()
+	return l.Addr().String(), msgs
+}
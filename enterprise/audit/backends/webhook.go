@@ -0,0 +1,226 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+// WebhookSignatureHeader contains the hex-encoded HMAC-SHA256 of the request
+// body, keyed with the configured secret.
+const WebhookSignatureHeader = "X-Coder-Signature"
+
+// WebhookOptions configures a webhook backend.
+type WebhookOptions struct {
+	URL *url.URL
+	// Secret is used to sign request bodies. Requests are not signed if it
+	// is empty.
+	Secret string
+	// BatchSize is the maximum number of audit logs sent in one request.
+	BatchSize int
+	// FlushInterval is the maximum time an audit log is held before a
+	// partial batch is sent.
+	FlushInterval time.Duration
+	// MaxRetries is the number of times a failed request is retried before
+	// the batch is dropped.
+	MaxRetries int
+	Client     *http.Client
+}
+
+// WebhookPayload is the body POSTed to the webhook.
+type WebhookPayload struct {
+	AuditLogs []json.RawMessage `json:"audit_logs"`
+}
+
+// WebhookBackend streams audit logs to an HTTP endpoint in batches. Export
+// never blocks on the network; logs are queued and sent in the background.
+type WebhookBackend struct {
+	opts WebhookOptions
+	log  slog.Logger
+
+	queue   chan json.RawMessage
+	closing chan struct{}
+	done    chan struct{}
+}
+
+func NewWebhook(logger slog.Logger, opts WebhookOptions) *WebhookBackend {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	b := &WebhookBackend{
+		opts: opts,
+		log:  logger.Named("audit_webhook"),
+		// Buffer a few batches so a slow endpoint doesn't cause audit logs
+		// to be dropped immediately.
+		queue:   make(chan json.RawMessage, opts.BatchSize*10),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (*WebhookBackend) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+func (b *WebhookBackend) Export(_ context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	raw, err := json.Marshal(newJSONLog(alog, details))
+	if err != nil {
+		return xerrors.Errorf("marshal audit log: %w", err)
+	}
+
+	select {
+	case <-b.closing:
+		return xerrors.New("webhook backend is closed")
+	default:
+	}
+	select {
+	case b.queue <- raw:
+		return nil
+	default:
+		return xerrors.New("webhook queue is full, dropping audit log")
+	}
+}
+
+// Close sends any queued audit logs and stops the backend.
+func (b *WebhookBackend) Close() error {
+	select {
+	case <-b.closing:
+	default:
+		close(b.closing)
+	}
+	<-b.done
+	return nil
+}
+
+func (b *WebhookBackend) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]json.RawMessage, 0, b.opts.BatchSize)
+	flush := func(retry bool) {
+		if len(batch) == 0 {
+			return
+		}
+		err := b.send(batch, retry)
+		if err != nil {
+			b.log.Error(context.Background(), "dropping audit logs after failing to send them to webhook",
+				slog.F("count", len(batch)),
+				slog.Error(err),
+			)
+		}
+		batch = make([]json.RawMessage, 0, b.opts.BatchSize)
+	}
+
+	for {
+		select {
+		case <-b.closing:
+			// Drain whatever is left without retrying so shutdown isn't
+			// held up by an unavailable endpoint.
+			for {
+				select {
+				case raw := <-b.queue:
+					batch = append(batch, raw)
+					if len(batch) >= b.opts.BatchSize {
+						flush(false)
+					}
+				default:
+					flush(false)
+					return
+				}
+			}
+		case raw := <-b.queue:
+			batch = append(batch, raw)
+			if len(batch) >= b.opts.BatchSize {
+				flush(true)
+			}
+		case <-ticker.C:
+			flush(true)
+		}
+	}
+}
+
+func (b *WebhookBackend) send(batch []json.RawMessage, retry bool) error {
+	body, err := json.Marshal(WebhookPayload{AuditLogs: batch})
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	maxRetries := uint64(0)
+	if retry {
+		maxRetries = uint64(b.opts.MaxRetries)
+	}
+	eb := backoff.NewExponentialBackOff()
+	eb.InitialInterval = 500 * time.Millisecond
+	eb.MaxInterval = 30 * time.Second
+	eb.MaxElapsedTime = 0 // bounded by MaxRetries instead
+
+	return backoff.Retry(func() error {
+		return b.post(body)
+	}, backoff.WithMaxRetries(eb, maxRetries))
+}
+
+func (b *WebhookBackend) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.opts.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(xerrors.Errorf("create request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.opts.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(b.opts.Secret, body))
+	}
+
+	resp, err := b.opts.Client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = xerrors.Errorf("non-2xx response (%d): %s", resp.StatusCode, respBody)
+	// Client errors other than rate limiting will not succeed on retry.
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return backoff.Permanent(err)
+	}
+	return err
+}
+
+// SignWebhookPayload returns the value of the WebhookSignatureHeader for the
+// given request body.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
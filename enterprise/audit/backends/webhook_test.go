@@ -0,0 +1,171 @@
+package backends_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebhookBackend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("BatchesAndSigns", func(t *testing.T) {
+		t.Parallel()
+
+		const secret = "hunter2"
+		payloads := make(chan backends.WebhookPayload, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, backends.SignWebhookPayload(secret, body), r.Header.Get(backends.WebhookSignatureHeader))
+
+			var payload backends.WebhookPayload
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			payloads <- payload
+			rw.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(srv.Close)
+
+		backend := backends.NewWebhook(slogtest.Make(t, nil), backends.WebhookOptions{
+			URL:       mustURL(t, srv.URL),
+			Secret:    secret,
+			BatchSize: 2,
+			// Only full batches should be sent during the test.
+			FlushInterval: time.Hour,
+		})
+		t.Cleanup(func() { _ = backend.Close() })
+		require.Equal(t, audit.FilterDecisionExport, backend.Decision())
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		first, second := audittest.RandomLog(), audittest.RandomLog()
+		require.NoError(t, backend.Export(ctx, first, audit.BackendDetails{}))
+		require.NoError(t, backend.Export(ctx, second, audit.BackendDetails{
+			Actor: &audit.Actor{Username: "alice"},
+		}))
+
+		payload := testutil.RequireRecvCtx(ctx, t, payloads)
+		require.Len(t, payload.AuditLogs, 2)
+
+		var got struct {
+			ID    string       `json:"id"`
+			IP    string       `json:"ip"`
+			Actor *audit.Actor `json:"actor"`
+		}
+		require.NoError(t, json.Unmarshal(payload.AuditLogs[0], &got))
+		require.Equal(t, first.ID.String(), got.ID)
+		require.Equal(t, "127.0.0.1", got.IP)
+		require.Nil(t, got.Actor)
+		require.NoError(t, json.Unmarshal(payload.AuditLogs[1], &got))
+		require.Equal(t, second.ID.String(), got.ID)
+		require.Equal(t, "alice", got.Actor.Username)
+	})
+
+	t.Run("FlushesOnClose", func(t *testing.T) {
+		t.Parallel()
+
+		payloads := make(chan backends.WebhookPayload, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get(backends.WebhookSignatureHeader))
+			var payload backends.WebhookPayload
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			payloads <- payload
+		}))
+		t.Cleanup(srv.Close)
+
+		backend := backends.NewWebhook(slogtest.Make(t, nil), backends.WebhookOptions{
+			URL:           mustURL(t, srv.URL),
+			BatchSize:     100,
+			FlushInterval: time.Hour,
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+		require.NoError(t, backend.Close())
+
+		payload := testutil.RequireRecvCtx(ctx, t, payloads)
+		require.Len(t, payload.AuditLogs, 1)
+
+		require.Error(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+	})
+
+	t.Run("Retries", func(t *testing.T) {
+		t.Parallel()
+
+		var attempts atomic.Int64
+		payloads := make(chan backends.WebhookPayload, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var payload backends.WebhookPayload
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			payloads <- payload
+		}))
+		t.Cleanup(srv.Close)
+
+		backend := backends.NewWebhook(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), backends.WebhookOptions{
+			URL:           mustURL(t, srv.URL),
+			BatchSize:     1,
+			FlushInterval: time.Hour,
+			MaxRetries:    5,
+		})
+		t.Cleanup(func() { _ = backend.Close() })
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		require.NoError(t, backend.Export(ctx, audittest.RandomLog(), audit.BackendDetails{}))
+
+		payload := testutil.RequireRecvCtx(ctx, t, payloads)
+		require.Len(t, payload.AuditLogs, 1)
+		require.EqualValues(t, 3, attempts.Load())
+	})
+
+	t.Run("DoesNotRetryClientErrors", func(t *testing.T) {
+		t.Parallel()
+
+		var attempts atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			rw.WriteHeader(http.StatusBadRequest)
+		}))
+		t.Cleanup(srv.Close)
+
+		backend := backends.NewWebhook(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), backends.WebhookOptions{
+			URL:           mustURL(t, srv.URL),
+			BatchSize:     1,
+			FlushInterval: time.Hour,
+			MaxRetries:    5,
+		})
+
+		require.NoError(t, backend.Export(context.Background(), audittest.RandomLog(), audit.BackendDetails{}))
+		require.Eventually(t, func() bool {
+			return attempts.Load() == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.NoError(t, backend.Close())
+		require.EqualValues(t, 1, attempts.Load())
+	})
+}
+
+func mustURL(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return u
+}
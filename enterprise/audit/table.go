@@ -88,6 +88,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"time_til_dormant_autodelete":       ActionTrack,
 		"require_active_version":            ActionTrack,
 		"deprecated":                        ActionTrack,
+		"record_sessions":                   ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -104,6 +105,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"created_by_avatar_url":   ActionIgnore,
 		"created_by_username":     ActionIgnore,
 		"archived":                ActionTrack,
+		"git_commit_sha":          ActionIgnore, // Never changes after creation.
+		"git_commit_message":      ActionIgnore, // Never changes after creation.
 	},
 	&database.User{}: {
 		"id":                   ActionTrack,
@@ -136,24 +139,27 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"dormant_at":         ActionTrack,
 		"deleting_at":        ActionTrack,
 		"automatic_updates":  ActionTrack,
+		"user_acl":           ActionTrack,
+		"group_acl":          ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
-		"id":                      ActionIgnore,
-		"created_at":              ActionIgnore,
-		"updated_at":              ActionIgnore,
-		"workspace_id":            ActionIgnore,
-		"template_version_id":     ActionTrack,
-		"build_number":            ActionIgnore,
-		"transition":              ActionIgnore,
-		"initiator_id":            ActionIgnore,
-		"provisioner_state":       ActionIgnore,
-		"job_id":                  ActionIgnore,
-		"deadline":                ActionIgnore,
-		"reason":                  ActionIgnore,
-		"daily_cost":              ActionIgnore,
-		"max_deadline":            ActionIgnore,
-		"initiator_by_avatar_url": ActionIgnore,
-		"initiator_by_username":   ActionIgnore,
+		"id":                       ActionIgnore,
+		"created_at":               ActionIgnore,
+		"updated_at":               ActionIgnore,
+		"workspace_id":             ActionIgnore,
+		"template_version_id":      ActionTrack,
+		"build_number":             ActionIgnore,
+		"transition":               ActionIgnore,
+		"initiator_id":             ActionIgnore,
+		"provisioner_state":        ActionIgnore,
+		"job_id":                   ActionIgnore,
+		"deadline":                 ActionIgnore,
+		"reason":                   ActionIgnore,
+		"daily_cost":               ActionIgnore,
+		"max_deadline":             ActionIgnore,
+		"provisioner_state_key_id": ActionIgnore,
+		"initiator_by_avatar_url":  ActionIgnore,
+		"initiator_by_username":    ActionIgnore,
 	},
 	&database.AuditableGroup{}: {
 		"id":              ActionTrack,
@@ -166,18 +172,20 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"source":          ActionIgnore,
 	},
 	&database.APIKey{}: {
-		"id":               ActionIgnore,
-		"hashed_secret":    ActionIgnore,
-		"user_id":          ActionTrack,
-		"last_used":        ActionTrack,
-		"expires_at":       ActionTrack,
-		"created_at":       ActionTrack,
-		"updated_at":       ActionIgnore,
-		"login_type":       ActionIgnore,
-		"lifetime_seconds": ActionIgnore,
-		"ip_address":       ActionIgnore,
-		"scope":            ActionIgnore,
-		"token_name":       ActionIgnore,
+		"id":                ActionIgnore,
+		"hashed_secret":     ActionIgnore,
+		"user_id":           ActionTrack,
+		"last_used":         ActionTrack,
+		"expires_at":        ActionTrack,
+		"created_at":        ActionTrack,
+		"updated_at":        ActionIgnore,
+		"login_type":        ActionIgnore,
+		"lifetime_seconds":  ActionIgnore,
+		"ip_address":        ActionIgnore,
+		"scope":             ActionIgnore,
+		"token_name":        ActionIgnore,
+		"scope_permissions": ActionIgnore,
+		"scope_allow_list":  ActionIgnore,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -190,6 +198,11 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"id":                     ActionIgnore,
 		"dismissed_healthchecks": ActionTrack,
 	},
+	&database.OIDCSyncRules{}: {
+		"id":         ActionIgnore,
+		"sync_roles": ActionTrack,
+		"rules":      ActionTrack,
+	},
 	// TODO: track an ID here when the below ticket is completed:
 	// https://github.com/coder/coder/pull/6012
 	&database.License{}: {
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) audit() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "audit",
+		Short: "Manage audit logs",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.auditExport(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) auditExport() *clibase.Cmd {
+	var (
+		query      string
+		format     string
+		outputPath string
+	)
+	client := new(codersdk.Client)
+
+	cmd := &clibase.Cmd{
+		Use:   "export",
+		Short: "Export audit logs",
+		Long: "Export audit logs as CSV or newline-delimited JSON. Every log matching the query is exported, newest first, " +
+			"which makes this suitable for exporting large numbers of logs for compliance purposes. " +
+			`For example, to export all logs from 2023 as CSV, run "coder audit export --format csv --query 'date_from:2023-01-01 date_to:2023-12-31' --output audit.csv".`,
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			body, err := client.ExportAuditLogs(inv.Context(), codersdk.AuditLogExportRequest{
+				SearchQuery: query,
+				Format:      codersdk.AuditLogExportFormat(format),
+			})
+			if err != nil {
+				return xerrors.Errorf("export audit logs: %w", err)
+			}
+			defer body.Close()
+
+			out := inv.Stdout
+			if outputPath != "" && outputPath != "-" {
+				f, err := os.Create(outputPath)
+				if err != nil {
+					return xerrors.Errorf("create output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			_, err = io.Copy(out, body)
+			if err != nil {
+				return xerrors.Errorf("write audit logs: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "query",
+			FlagShorthand: "q",
+			Description:   "Only export audit logs matching this search query. Uses the same syntax as the audit log page.",
+			Value:         clibase.StringOf(&query),
+		},
+		{
+			Flag:        "format",
+			Description: "Export format.",
+			Default:     string(codersdk.AuditLogExportFormatNDJSON),
+			Value:       clibase.EnumOf(&format, string(codersdk.AuditLogExportFormatCSV), string(codersdk.AuditLogExportFormatNDJSON)),
+		},
+		{
+			Flag:          "output",
+			FlagShorthand: "o",
+			Description:   "File to write the export to. Defaults to stdout.",
+			Value:         clibase.StringOf(&outputPath),
+		},
+	}
+
+	return cmd
+}
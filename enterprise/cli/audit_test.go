@@ -0,0 +1,84 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestAuditExport(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) *codersdk.Client {
+		t.Helper()
+
+		client, admin := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAuditLog: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitShort)
+		for _, action := range []codersdk.AuditAction{codersdk.AuditActionCreate, codersdk.AuditActionDelete} {
+			err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+				Action:       action,
+				ResourceType: codersdk.ResourceTypeWorkspace,
+				ResourceID:   admin.UserID,
+			})
+			require.NoError(t, err)
+		}
+		return client
+	}
+
+	t.Run("NDJSON", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t)
+		inv, conf := newCLI(t, "audit", "export", "--query", "resource_type:workspace")
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+
+		err := inv.Run()
+		require.NoError(t, err)
+
+		var actions []codersdk.AuditAction
+		dec := json.NewDecoder(&stdout)
+		for dec.More() {
+			var alog codersdk.AuditLog
+			require.NoError(t, dec.Decode(&alog))
+			actions = append(actions, alog.Action)
+		}
+		require.ElementsMatch(t, []codersdk.AuditAction{codersdk.AuditActionCreate, codersdk.AuditActionDelete}, actions)
+	})
+
+	t.Run("CSVToFile", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t)
+		out := filepath.Join(t.TempDir(), "audit.csv")
+		inv, conf := newCLI(t, "audit", "export", "--format", "csv", "-q", "action:delete", "-o", out)
+		clitest.SetupConfig(t, client, conf)
+
+		err := inv.Run()
+		require.NoError(t, err)
+
+		f, err := os.Open(out)
+		require.NoError(t, err)
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		require.NoError(t, err)
+		// Header plus the single deletion.
+		require.Len(t, records, 2)
+	})
+}
@@ -37,6 +37,7 @@ func (r *RootCmd) provisionerDaemons() *clibase.Cmd {
 		},
 		Children: []*clibase.Cmd{
 			r.provisionerDaemonStart(),
+			r.provisionerKeys(),
 		},
 	}
 
@@ -60,6 +61,7 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 		pollInterval time.Duration
 		pollJitter   time.Duration
 		preSharedKey string
+		key          string
 		name         string
 	)
 	client := new(codersdk.Client)
@@ -99,6 +101,16 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 				logger.Info(ctx, "see https://github.com/coder/coder/issues/6442 for details")
 			}
 
+			if key != "" && preSharedKey != "" {
+				return xerrors.New("cannot provide both a pre-shared key and a provisioner key")
+			}
+
+			// When authorizing with a provisioner key, the tags of the key are
+			// used and the daemon is scoped to the organization.
+			if key != "" {
+				logger.Info(ctx, "provisioner key auth sets the tags of the provisioner key")
+			}
+
 			// When authorizing with a PSK, we automatically scope the provisionerd
 			// to organization. Scoping to user with PSK auth is not a valid configuration.
 			if preSharedKey != "" {
@@ -156,8 +168,9 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 					Provisioners: []codersdk.ProvisionerType{
 						codersdk.ProvisionerTypeTerraform,
 					},
-					Tags:         tags,
-					PreSharedKey: preSharedKey,
+					Tags:           tags,
+					PreSharedKey:   preSharedKey,
+					ProvisionerKey: key,
 				})
 			}, &provisionerd.Options{
 				Logger:         logger,
@@ -227,6 +240,12 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 			Description: "Pre-shared key to authenticate with Coder server.",
 			Value:       clibase.StringOf(&preSharedKey),
 		},
+		{
+			Flag:        "key",
+			Env:         "CODER_PROVISIONER_DAEMON_KEY",
+			Description: "Provisioner key to authenticate with Coder server. The daemon gets the tags of the key.",
+			Value:       clibase.StringOf(&key),
+		},
 		{
 			Flag:        "name",
 			Env:         "CODER_PROVISIONER_DAEMON_NAME",
@@ -1,13 +1,16 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
@@ -79,3 +82,78 @@ func TestProvisionerDaemon_SessionToken(t *testing.T) {
 		pty.ExpectMatchContext(ctx, "starting provisioner daemon")
 	})
 }
+
+func TestProvisionerDaemon_ProvisionerKey(t *testing.T) {
+	t.Parallel()
+
+	client, admin := coderdenttest.New(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		},
+	})
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:gocritic // Not testing RBAC here.
+	res, err := client.CreateProvisionerKey(ctx, admin.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+		Name: "chicago",
+		Tags: map[string]string{"data_center": "chicago"},
+	})
+	require.NoError(t, err)
+
+	inv, conf := newCLI(t, "provisionerd", "start", "--key", res.Key, "--name=matt-daemon")
+	err = conf.URL().Write(client.URL.String())
+	require.NoError(t, err)
+	pty := ptytest.New(t).Attach(inv)
+	clitest.Start(t, inv)
+	pty.ExpectMatchContext(ctx, "starting provisioner daemon")
+
+	require.Eventually(t, func() bool {
+		daemons, err := client.ProvisionerDaemons(ctx) //nolint:gocritic // Test assertion.
+		if err != nil || len(daemons) != 1 {
+			return false
+		}
+		return daemons[0].Name == "matt-daemon" && daemons[0].KeyName == "chicago" &&
+			daemons[0].Tags["data_center"] == "chicago"
+	}, testutil.WaitLong, testutil.IntervalFast)
+}
+
+func TestProvisionerKeys(t *testing.T) {
+	t.Parallel()
+
+	client, admin := coderdenttest.New(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		},
+	})
+	templateAdminClient, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID, rbac.RoleTemplateAdmin())
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, conf := newCLI(t, "provisionerd", "keys", "create", "chicago", "--tag", "data_center=chicago")
+	clitest.SetupConfig(t, templateAdminClient, conf)
+	var stdout bytes.Buffer
+	inv.Stdout = &stdout
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.NotEmpty(t, strings.TrimSpace(stdout.String()))
+
+	inv, conf = newCLI(t, "provisionerd", "keys", "list")
+	clitest.SetupConfig(t, templateAdminClient, conf)
+	stdout.Reset()
+	inv.Stdout = &stdout
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, stdout.String(), "chicago")
+	require.Contains(t, stdout.String(), "data_center=chicago")
+
+	inv, conf = newCLI(t, "provisionerd", "keys", "delete", "chicago", "--yes")
+	clitest.SetupConfig(t, templateAdminClient, conf)
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+
+	keys, err := templateAdminClient.ListProvisionerKeys(ctx, admin.OrganizationID)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/pretty"
+
+	agpl "github.com/coder/coder/v2/cli"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) provisionerKeys() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:     "keys",
+		Short:   "Manage provisioner keys",
+		Aliases: []string{"key"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.provisionerKeyCreate(),
+			r.provisionerKeyList(),
+			r.provisionerKeyDelete(),
+		},
+	}
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerKeyCreate() *clibase.Cmd {
+	var rawTags []string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a provisioner key",
+		Long: "Create a key that provisioner daemons can authenticate with. " +
+			"Daemons started with the key only pick up jobs matching the tags of the key.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			tags, err := agpl.ParseProvisionerTags(rawTags)
+			if err != nil {
+				return err
+			}
+
+			res, err := client.CreateProvisionerKey(ctx, org.ID, codersdk.CreateProvisionerKeyRequest{
+				Name: inv.Args[0],
+				Tags: tags,
+			})
+			if err != nil {
+				return xerrors.Errorf("create provisioner key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Successfully created provisioner key %s! Start a provisioner daemon with it:\n\n", pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[0]))
+			_, _ = fmt.Fprintln(inv.Stderr, color.HiMagentaString("  $ coder provisionerd start --key <key>\n"))
+			_, _ = fmt.Fprintln(inv.Stdout, res.Key)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "tag",
+			FlagShorthand: "t",
+			Env:           "CODER_PROVISIONERD_TAGS",
+			Description:   "Tags of the provisioner daemons that authenticate with the key.",
+			Value:         clibase.StringArrayOf(&rawTags),
+		},
+	}
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerKeyList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]provisionerKeyTableRow{}, nil),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Short:   "List provisioner keys",
+		Aliases: []string{"ls"},
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			keys, err := client.ListProvisionerKeys(ctx, org.ID)
+			if err != nil {
+				return xerrors.Errorf("list provisioner keys: %w", err)
+			}
+
+			if len(keys) == 0 {
+				_, _ = fmt.Fprintf(inv.Stderr, "%s No provisioner keys found in %s! Create one:\n\n", agpl.Caret, color.HiWhiteString(org.Name))
+				_, _ = fmt.Fprintln(inv.Stderr, color.HiMagentaString("  $ coder provisionerd keys create <name>\n"))
+				return nil
+			}
+
+			rows := make([]provisionerKeyTableRow, 0, len(keys))
+			for _, key := range keys {
+				tagKeys := maps.Keys(key.Tags)
+				slices.Sort(tagKeys)
+				tags := make([]string, 0, len(tagKeys))
+				for _, k := range tagKeys {
+					tags = append(tags, fmt.Sprintf("%s=%s", k, key.Tags[k]))
+				}
+				rows = append(rows, provisionerKeyTableRow{
+					ProvisionerKey: key,
+					Name:           key.Name,
+					CreatedAt:      key.CreatedAt,
+					Tags:           strings.Join(tags, " "),
+				})
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return xerrors.Errorf("display provisioner keys: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type provisionerKeyTableRow struct {
+	// For json output:
+	ProvisionerKey codersdk.ProvisionerKey `table:"-"`
+
+	// For table output:
+	Name      string    `json:"-" table:"name,default_sort"`
+	CreatedAt time.Time `json:"-" table:"created at"`
+	Tags      string    `json:"-" table:"tags"`
+}
+
+func (r *RootCmd) provisionerKeyDelete() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <name>",
+		Short: "Delete a provisioner key",
+		Long:  "Delete a provisioner key. Provisioner daemons that are connected with the key are disconnected.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Options: clibase.OptionSet{
+			cliui.SkipPromptOption(),
+		},
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete provisioner key %s? Daemons using it will be disconnected.", pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[0])),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = client.DeleteProvisionerKey(ctx, org.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("delete provisioner key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully deleted provisioner key %s!\n", pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[0]))
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/pretty"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) roleEdit() *clibase.Cmd {
+	var (
+		displayName     string
+		sitePermissions []string
+		userPermissions []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "edit <name>",
+		Short: "Create or replace a custom site wide role",
+		Long: "Permissions are given as \"<resource>:<action>\", for example \"template:read\". " +
+			"Prefix a permission with \"!\" to negate it. The role is replaced with exactly the " +
+			"permissions provided.\n\n" +
+			"  $ coder roles edit template-viewer --display-name \"Template Viewer\" --site-permission template:read",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			req := codersdk.Role{
+				Name:        inv.Args[0],
+				DisplayName: displayName,
+			}
+
+			var err error
+			req.SitePermissions, err = parsePermissions(sitePermissions)
+			if err != nil {
+				return xerrors.Errorf("parse site-permission: %w", err)
+			}
+			req.UserPermissions, err = parsePermissions(userPermissions)
+			if err != nil {
+				return xerrors.Errorf("parse user-permission: %w", err)
+			}
+
+			role, err := client.PatchRole(inv.Context(), req)
+			if err != nil {
+				return xerrors.Errorf("patch role: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully updated role %s!\n", pretty.Sprint(cliui.DefaultStyles.Keyword, role.Name))
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "display-name",
+			Description: "Optional human friendly name for the role.",
+			Value:       clibase.StringOf(&displayName),
+		},
+		{
+			Flag:        "site-permission",
+			Description: "Permission granted on every resource of the type. Can be repeated.",
+			Value:       clibase.StringArrayOf(&sitePermissions),
+		},
+		{
+			Flag:        "user-permission",
+			Description: "Permission granted on resources owned by the user. Can be repeated.",
+			Value:       clibase.StringArrayOf(&userPermissions),
+		},
+	}
+
+	return cmd
+}
+
+func parsePermissions(permissions []string) ([]codersdk.Permission, error) {
+	parsed := make([]codersdk.Permission, 0, len(permissions))
+	for _, permission := range permissions {
+		perm, err := codersdk.ParsePermission(permission)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, perm)
+	}
+	return parsed, nil
+}
+
+func formatPermissions(permissions []codersdk.Permission) []string {
+	formatted := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		formatted = append(formatted, permission.String())
+	}
+	return formatted
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) roleList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]roleTableRow{}, nil),
+		cliui.JSONFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "list",
+		Short: "List site wide roles",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			roles, err := client.ListSiteRoles(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("list roles: %w", err)
+			}
+
+			out, err := formatter.Format(inv.Context(), rolesToRows(roles...))
+			if err != nil {
+				return xerrors.Errorf("display roles: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type roleTableRow struct {
+	// For json output:
+	Role codersdk.AssignableRoles `table:"-"`
+
+	// For table output:
+	Name            string   `json:"-" table:"name,default_sort"`
+	DisplayName     string   `json:"-" table:"display_name"`
+	BuiltIn         bool     `json:"-" table:"built_in"`
+	Assignable      bool     `json:"-" table:"assignable"`
+	SitePermissions []string `json:"-" table:"site_permissions"`
+	UserPermissions []string `json:"-" table:"user_permissions"`
+}
+
+func rolesToRows(roles ...codersdk.AssignableRoles) []roleTableRow {
+	rows := make([]roleTableRow, 0, len(roles))
+	for _, role := range roles {
+		rows = append(rows, roleTableRow{
+			Role:            role,
+			Name:            role.Name,
+			DisplayName:     role.DisplayName,
+			BuiltIn:         role.BuiltIn,
+			Assignable:      role.Assignable,
+			SitePermissions: formatPermissions(role.SitePermissions),
+			UserPermissions: formatPermissions(role.UserPermissions),
+		})
+	}
+
+	return rows
+}
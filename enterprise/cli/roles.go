@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"github.com/coder/coder/v2/cli/clibase"
+)
+
+func (r *RootCmd) roles() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:     "roles",
+		Short:   "Manage custom site wide roles",
+		Aliases: []string{"role"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.roleList(),
+			r.roleEdit(),
+			r.roleDelete(),
+		},
+	}
+
+	return cmd
+}
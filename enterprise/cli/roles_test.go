@@ -0,0 +1,94 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("EditListDelete", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserRoleManagement: 1,
+			},
+		}})
+
+		inv, conf := newCLI(t,
+			"roles", "edit", "template-viewer",
+			"--display-name", "Template Viewer",
+			"--site-permission", "template:read",
+			"--site-permission", "!workspace:delete",
+		)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+		err := inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "Successfully updated role")
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		roles, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Contains(t, roles, codersdk.AssignableRoles{
+			Role: codersdk.Role{
+				Name:        "template-viewer",
+				DisplayName: "Template Viewer",
+				SitePermissions: []codersdk.Permission{
+					{ResourceType: codersdk.ResourceTemplate, Action: "read"},
+					{ResourceType: codersdk.ResourceWorkspace, Action: "delete", Negate: true},
+				},
+			},
+			Assignable: true,
+		})
+
+		inv, conf = newCLI(t, "roles", "list")
+		stdout.Reset()
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "template-viewer")
+		require.Contains(t, stdout.String(), "!workspace:delete")
+
+		inv, conf = newCLI(t, "roles", "delete", "template-viewer")
+		stdout.Reset()
+		inv.Stdout = &stdout
+		clitest.SetupConfig(t, client, conf)
+		err = inv.Run()
+		require.NoError(t, err)
+		require.Contains(t, stdout.String(), "Successfully deleted role")
+
+		roles, err = client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		for _, role := range roles {
+			require.NotEqual(t, "template-viewer", role.Name)
+		}
+	})
+
+	t.Run("InvalidPermission", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserRoleManagement: 1,
+			},
+		}})
+
+		inv, conf := newCLI(t, "roles", "edit", "bad", "--site-permission", "template")
+		clitest.SetupConfig(t, client, conf)
+		err := inv.Run()
+		require.ErrorContains(t, err, "expected <resource>:<action>")
+	})
+}
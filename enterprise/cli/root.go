@@ -16,7 +16,9 @@ func (r *RootCmd) enterpriseOnly() []*clibase.Cmd {
 		r.features(),
 		r.licenses(),
 		r.groups(),
+		r.roles(),
 		r.provisionerDaemons(),
+		r.audit(),
 	}
 }
 
@@ -4,11 +4,13 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
-	"encoding/base64"
 	"errors"
 	"io"
+	"net"
 	"net/url"
+	"os"
 
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
@@ -52,11 +54,43 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 			}
 		}
 		options.DERPServer.SetMeshKey(meshKey)
+		auditBackends := []audit.Backend{
+			backends.NewPostgres(options.Database, true),
+			backends.NewSlog(options.Logger),
+		}
+		var auditClosers []io.Closer
+		auditCfg := options.DeploymentValues.AuditLogging
+		if u := auditCfg.Webhook.URL.Value(); u != nil && u.String() != "" {
+			webhook := backends.NewWebhook(options.Logger, backends.WebhookOptions{
+				URL:           u,
+				Secret:        auditCfg.Webhook.Secret.Value(),
+				BatchSize:     int(auditCfg.Webhook.BatchSize.Value()),
+				FlushInterval: auditCfg.Webhook.FlushInterval.Value(),
+				MaxRetries:    int(auditCfg.Webhook.MaxRetries.Value()),
+			})
+			auditBackends = append(auditBackends, webhook)
+			auditClosers = append(auditClosers, webhook)
+		}
+		if addr := auditCfg.Syslog.Address.Value(); addr != "" {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("parse audit-syslog-address: %w", err)
+			}
+			syslogOpts := backends.SyslogOptions{Address: addr}
+			if auditCfg.Syslog.TLS.Value() {
+				syslogOpts.TLSConfig = &tls.Config{
+					ServerName: host,
+					MinVersion: tls.VersionTLS12,
+				}
+			}
+			syslog := backends.NewSyslog(options.Logger, syslogOpts)
+			auditBackends = append(auditBackends, syslog)
+			auditClosers = append(auditClosers, syslog)
+		}
 		options.Auditor = audit.NewAuditor(
 			options.Database,
 			audit.DefaultFilter,
-			backends.NewPostgres(options.Database, true),
-			backends.NewSlog(options.Logger),
+			auditBackends...,
 		)
 
 		options.TrialGenerator = trialer.New(options.Database, "https://v2-licensor.coder.com/trial", coderd.Keys)
@@ -77,15 +111,7 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 		}
 
 		if encKeys := options.DeploymentValues.ExternalTokenEncryptionKeys.Value(); len(encKeys) != 0 {
-			keys := make([][]byte, 0, len(encKeys))
-			for idx, ek := range encKeys {
-				dk, err := base64.StdEncoding.DecodeString(ek)
-				if err != nil {
-					return nil, nil, xerrors.Errorf("decode external-token-encryption-key %d: %w", idx, err)
-				}
-				keys = append(keys, dk)
-			}
-			cs, err := dbcrypt.NewCiphers(keys...)
+			cs, err := dbcrypt.ParseCiphers(ctx, os.Getenv, encKeys...)
 			if err != nil {
 				return nil, nil, xerrors.Errorf("initialize encryption: %w", err)
 			}
@@ -96,7 +122,15 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 		if err != nil {
 			return nil, nil, err
 		}
-		return api.AGPL, api, nil
+		// Close the audit backends after the API so that audit logs
+		// produced while shutting down are still delivered.
+		return api.AGPL, closeFunc(func() error {
+			errs := api.Close()
+			for _, closer := range auditClosers {
+				errs = errors.Join(errs, closer.Close())
+			}
+			return errs
+		}), nil
 	})
 
 	cmd.AddSubcommands(
@@ -104,3 +138,9 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 	)
 	return cmd
 }
+
+type closeFunc func() error
+
+func (c closeFunc) Close() error {
+	return c()
+}
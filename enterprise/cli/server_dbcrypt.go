@@ -51,22 +51,7 @@ func (*RootCmd) dbcryptRotateCmd() *clibase.Cmd {
 				return err
 			}
 
-			ks := [][]byte{}
-			dk, err := base64.StdEncoding.DecodeString(flags.New)
-			if err != nil {
-				return xerrors.Errorf("decode new key: %w", err)
-			}
-			ks = append(ks, dk)
-
-			for _, k := range flags.Old {
-				dk, err := base64.StdEncoding.DecodeString(k)
-				if err != nil {
-					return xerrors.Errorf("decode old key: %w", err)
-				}
-				ks = append(ks, dk)
-			}
-
-			ciphers, err := dbcrypt.NewCiphers(ks...)
+			ciphers, err := dbcrypt.ParseCiphers(ctx, inv.Environ.Get, append([]string{flags.New}, flags.Old...)...)
 			if err != nil {
 				return xerrors.Errorf("create ciphers: %w", err)
 			}
@@ -124,16 +109,7 @@ func (*RootCmd) dbcryptDecryptCmd() *clibase.Cmd {
 				return err
 			}
 
-			ks := make([][]byte, 0, len(flags.Keys))
-			for _, k := range flags.Keys {
-				dk, err := base64.StdEncoding.DecodeString(k)
-				if err != nil {
-					return xerrors.Errorf("decode key: %w", err)
-				}
-				ks = append(ks, dk)
-			}
-
-			ciphers, err := dbcrypt.NewCiphers(ks...)
+			ciphers, err := dbcrypt.ParseCiphers(ctx, inv.Environ.Get, flags.Keys...)
 			if err != nil {
 				return xerrors.Errorf("create ciphers: %w", err)
 			}
@@ -183,6 +159,8 @@ func (*RootCmd) dbcryptDeleteCmd() *clibase.Cmd {
 			msg := `All encrypted data will be deleted from the database:
 - Encrypted user OAuth access and refresh tokens
 - Encrypted user Git authentication access and refresh tokens
+- Encrypted workspace build state
+- Encrypted values of sensitive template variables
 
 Are you sure you want to continue?`
 			if _, err := cliui.Prompt(inv, cliui.PromptOptions{
@@ -229,13 +207,13 @@ func (f *rotateFlags) attach(opts *clibase.OptionSet) {
 		clibase.Option{
 			Flag:        "new-key",
 			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_ENCRYPT_NEW_KEY",
-			Description: "The new external token encryption key. Must be base64-encoded.",
+			Description: "The new external token encryption key. Must be base64-encoded, or a key provider URL like vault-transit:<mount>/<name>.",
 			Value:       clibase.StringOf(&f.New),
 		},
 		clibase.Option{
 			Flag:        "old-keys",
 			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_ENCRYPT_OLD_KEYS",
-			Description: "The old external token encryption keys. Must be a comma-separated list of base64-encoded keys.",
+			Description: "The old external token encryption keys. Must be a comma-separated list of base64-encoded keys or key provider URLs.",
 			Value:       clibase.StringArrayOf(&f.Old),
 		},
 		cliui.SkipPromptOption(),
@@ -251,17 +229,13 @@ func (f *rotateFlags) valid() error {
 		return xerrors.Errorf("no new key provided")
 	}
 
-	if val, err := base64.StdEncoding.DecodeString(f.New); err != nil {
-		return xerrors.Errorf("new key must be base64-encoded")
-	} else if len(val) != 32 {
-		return xerrors.Errorf("new key must be exactly 32 bytes in length")
+	if err := validKey(f.New); err != nil {
+		return xerrors.Errorf("new key %w", err)
 	}
 
 	for i, k := range f.Old {
-		if val, err := base64.StdEncoding.DecodeString(k); err != nil {
-			return xerrors.Errorf("old key at index %d must be base64-encoded", i)
-		} else if len(val) != 32 {
-			return xerrors.Errorf("old key at index %d must be exactly 32 bytes in length", i)
+		if err := validKey(k); err != nil {
+			return xerrors.Errorf("old key at index %d %w", i, err)
 		}
 
 		// Pedantic, but typos here will ruin your day.
@@ -290,7 +264,7 @@ func (f *decryptFlags) attach(opts *clibase.OptionSet) {
 		clibase.Option{
 			Flag:        "keys",
 			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_DECRYPT_KEYS",
-			Description: "Keys required to decrypt existing data. Must be a comma-separated list of base64-encoded keys.",
+			Description: "Keys required to decrypt existing data. Must be a comma-separated list of base64-encoded keys or key provider URLs.",
 			Value:       clibase.StringArrayOf(&f.Keys),
 		},
 		cliui.SkipPromptOption(),
@@ -307,16 +281,28 @@ func (f *decryptFlags) valid() error {
 	}
 
 	for i, k := range f.Keys {
-		if val, err := base64.StdEncoding.DecodeString(k); err != nil {
-			return xerrors.Errorf("key at index %d must be base64-encoded", i)
-		} else if len(val) != 32 {
-			return xerrors.Errorf("key at index %d must be exactly 32 bytes in length", i)
+		if err := validKey(k); err != nil {
+			return xerrors.Errorf("key at index %d %w", i, err)
 		}
 	}
 
 	return nil
 }
 
+// validKey checks the format of a raw key. Key provider URLs are checked
+// when the ciphers are created.
+func validKey(k string) error {
+	if strings.HasPrefix(k, "vault-transit:") || strings.HasPrefix(k, "file:") {
+		return nil
+	}
+	if val, err := base64.StdEncoding.DecodeString(k); err != nil {
+		return xerrors.New("must be base64-encoded")
+	} else if len(val) != 32 {
+		return xerrors.New("must be exactly 32 bytes in length")
+	}
+	return nil
+}
+
 type deleteFlags struct {
 	PostgresURL string
 	Confirm     bool
@@ -12,8 +12,10 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/postgres"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/dbcrypt"
@@ -21,7 +23,8 @@ import (
 )
 
 // TestServerDBCrypt tests end-to-end encryption, decryption, and deletion
-// of encrypted user data.
+// of encrypted user data, workspace build state, sensitive template
+// variables, session recordings and template git source webhook secrets.
 //
 // nolint: paralleltest // use of t.Setenv
 func TestServerDBCrypt(t *testing.T) {
@@ -195,7 +198,8 @@ func TestServerDBCrypt(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, pty.Close())
 
-	// Assert that no user links remain.
+	// Assert that no user links, build state, sensitive values, session
+	// recordings or webhook secrets remain.
 	for _, usr := range users {
 		userLinks, err := db.GetUserLinksByUserID(ctx, usr.ID)
 		require.NoError(t, err, "failed to get user links for user %s", usr.ID)
@@ -203,6 +207,22 @@ func TestServerDBCrypt(t *testing.T) {
 		gitAuthLinks, err := db.GetExternalAuthLinksByUserID(ctx, usr.ID)
 		require.NoError(t, err, "failed to get git auth links for user %s", usr.ID)
 		require.Empty(t, gitAuthLinks)
+		for _, build := range getLatestBuilds(ctx, t, db, usr.ID) {
+			require.Empty(t, build.ProvisionerState)
+			require.False(t, build.ProvisionerStateKeyID.Valid)
+			variables, err := db.GetTemplateVersionVariables(ctx, build.TemplateVersionID)
+			require.NoError(t, err, "failed to get template variables for build %s", build.ID)
+			for _, variable := range variables {
+				require.Empty(t, variable.Value)
+				require.False(t, variable.ValueKeyID.Valid)
+			}
+			recordings, err := db.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, build.WorkspaceID)
+			require.NoError(t, err, "failed to get session recordings for build %s", build.ID)
+			require.Empty(t, recordings)
+			source := getTemplateGitSource(ctx, t, db, build)
+			require.Empty(t, source.WebhookSecret)
+			require.False(t, source.WebhookSecretKeyID.Valid)
+		}
 	}
 
 	// Validate that the key has been revoked in the database.
@@ -218,6 +238,7 @@ func TestServerDBCrypt(t *testing.T) {
 func genData(t *testing.T, db database.Store) []database.User {
 	t.Helper()
 	var users []database.User
+	org := dbgen.Organization(t, db, database.Organization{})
 	// Make some users
 	for _, status := range database.AllUserStatusValues() {
 		for _, loginType := range database.AllLoginTypeValues() {
@@ -245,6 +266,38 @@ func genData(t *testing.T, db database.Store) []database.User {
 					OAuthAccessToken:  "access-" + usr.ID.String(),
 					OAuthRefreshToken: "refresh-" + usr.ID.String(),
 				})
+				r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+					OrganizationID: org.ID,
+					OwnerID:        usr.ID,
+				}).Seed(database.WorkspaceBuild{
+					ProvisionerState: []byte("state-" + usr.ID.String()),
+				}).WithAgent().Do()
+				_ = dbgen.TemplateVersionVariable(t, db, database.TemplateVersionVariable{
+					TemplateVersionID: r.Build.TemplateVersionID,
+					Value:             "variable-" + usr.ID.String(),
+					Sensitive:         true,
+				})
+				recording := []byte("recording-" + usr.ID.String())
+				agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(context.Background(), r.Workspace.ID)
+				require.NoError(t, err)
+				require.Len(t, agents, 1)
+				err = db.InsertWorkspaceSessionRecording(context.Background(), database.InsertWorkspaceSessionRecordingParams{
+					ID:               uuid.New(),
+					WorkspaceID:      r.Workspace.ID,
+					WorkspaceBuildID: r.Build.ID,
+					AgentID:          agents[0].ID,
+					Type:             database.WorkspaceSessionRecordingTypeSsh,
+					StartedAt:        dbtime.Now(),
+					EndedAt:          dbtime.Now(),
+					Size:             int64(len(recording)),
+					Data:             recording,
+				})
+				require.NoError(t, err)
+				_ = dbgen.TemplateGitSource(t, db, database.TemplateGitSource{
+					TemplateID:    r.Workspace.TemplateID,
+					CreatedBy:     usr.ID,
+					WebhookSecret: "webhook-" + usr.ID.String(),
+				})
 				users = append(users, usr)
 			}
 		}
@@ -293,6 +346,56 @@ func requireEncryptedWithCipher(ctx context.Context, t *testing.T, db database.S
 		require.Equal(t, c.HexDigest(), gal.OAuthAccessTokenKeyID.String)
 		require.Equal(t, c.HexDigest(), gal.OAuthRefreshTokenKeyID.String)
 	}
+	for _, build := range getLatestBuilds(ctx, t, db, userID) {
+		// Build state is stored as raw bytes rather than base64.
+		state, err := c.Decrypt(build.ProvisionerState)
+		require.NoError(t, err, "failed to decrypt state of build %s", build.ID)
+		require.Equal(t, "state-"+userID.String(), string(state))
+		require.Equal(t, c.HexDigest(), build.ProvisionerStateKeyID.String)
+
+		variables, err := db.GetTemplateVersionVariables(ctx, build.TemplateVersionID)
+		require.NoError(t, err, "failed to get template variables for build %s", build.ID)
+		require.Len(t, variables, 1)
+		requireEncryptedEquals(t, c, "variable-"+userID.String(), variables[0].Value)
+		require.Equal(t, c.HexDigest(), variables[0].ValueKeyID.String)
+
+		recordings, err := db.GetWorkspaceSessionRecordingsByWorkspaceID(ctx, build.WorkspaceID)
+		require.NoError(t, err, "failed to get session recordings for build %s", build.ID)
+		require.Len(t, recordings, 1)
+		recording, err := db.GetWorkspaceSessionRecordingByID(ctx, recordings[0].ID)
+		require.NoError(t, err, "failed to get session recording %s", recordings[0].ID)
+		// Recording data is stored as raw bytes rather than base64.
+		data, err := c.Decrypt(recording.Data)
+		require.NoError(t, err, "failed to decrypt session recording %s", recording.ID)
+		require.Equal(t, "recording-"+userID.String(), string(data))
+		require.Equal(t, c.HexDigest(), recording.DataKeyID.String)
+
+		source := getTemplateGitSource(ctx, t, db, build)
+		requireEncryptedEquals(t, c, "webhook-"+userID.String(), source.WebhookSecret)
+		require.Equal(t, c.HexDigest(), source.WebhookSecretKeyID.String)
+	}
+}
+
+func getTemplateGitSource(ctx context.Context, t *testing.T, db database.Store, build database.WorkspaceBuild) database.TemplateGitSource {
+	t.Helper()
+	workspace, err := db.GetWorkspaceByID(ctx, build.WorkspaceID)
+	require.NoError(t, err, "failed to get workspace for build %s", build.ID)
+	source, err := db.GetTemplateGitSourceByTemplateID(ctx, workspace.TemplateID)
+	require.NoError(t, err, "failed to get template git source for build %s", build.ID)
+	return source
+}
+
+func getLatestBuilds(ctx context.Context, t *testing.T, db database.Store, userID uuid.UUID) []database.WorkspaceBuild {
+	t.Helper()
+	workspaces, err := db.GetWorkspaces(ctx, database.GetWorkspacesParams{OwnerID: userID})
+	require.NoError(t, err, "failed to get workspaces for user %s", userID)
+	workspaceIDs := make([]uuid.UUID, 0, len(workspaces))
+	for _, ws := range workspaces {
+		workspaceIDs = append(workspaceIDs, ws.ID)
+	}
+	builds, err := db.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, workspaceIDs)
+	require.NoError(t, err, "failed to get workspace builds for user %s", userID)
+	return builds
 }
 
 // nullCipher is a dbcrypt.Cipher that does not encrypt or decrypt.
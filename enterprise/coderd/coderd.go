@@ -50,6 +50,9 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	if options.EntitlementsUpdateInterval == 0 {
 		options.EntitlementsUpdateInterval = 10 * time.Minute
 	}
+	if options.ProvisionerDaemonHeartbeatInterval == 0 {
+		options.ProvisionerDaemonHeartbeatInterval = time.Minute
+	}
 	if options.LicenseKeys == nil {
 		options.LicenseKeys = Keys
 	}
@@ -118,6 +121,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	}
 	api.AGPL.Options.SetUserGroups = api.setUserGroups
 	api.AGPL.Options.SetUserSiteRoles = api.setUserSiteRoles
+	api.AGPL.Options.ApplyOIDCSyncRules = api.applyOIDCSyncRules
 	api.AGPL.SiteHandler.AppearanceFetcher = api.fetchAppearanceConfig
 	api.AGPL.SiteHandler.RegionsFetcher = func(ctx context.Context) (any, error) {
 		// If the user can read the workspace proxy resource, return that.
@@ -253,6 +257,16 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.With(apiKeyMiddleware).Get("/", api.provisionerDaemons)
 			r.With(apiKeyMiddlewareOptional).Get("/serve", api.provisionerDaemonServe)
 		})
+		r.Route("/organizations/{organization}/provisionerkeys", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.provisionerDaemonsEnabledMW,
+				httpmw.ExtractOrganizationParam(api.Database),
+			)
+			r.Get("/", api.provisionerKeys)
+			r.Post("/", api.postProvisionerKey)
+			r.Delete("/{provisionerkey}", api.deleteProvisionerKey)
+		})
 		r.Route("/templates/{template}/acl", func(r chi.Router) {
 			r.Use(
 				api.templateRBACEnabledMW,
@@ -301,6 +315,36 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Put("/", api.putAppearance)
 			})
 		})
+		// The site and organization role listings are registered by the
+		// AGPL API, so only the methods that modify custom roles are
+		// registered here.
+		r.With(
+			apiKeyMiddleware,
+			api.customRolesEnabledMW,
+		).Patch("/users/roles", api.patchRole)
+		r.With(
+			apiKeyMiddleware,
+			api.customRolesEnabledMW,
+		).Delete("/users/roles/{roleName}", api.deleteRole)
+		r.With(
+			apiKeyMiddleware,
+			api.customRolesEnabledMW,
+			httpmw.ExtractOrganizationParam(api.Database),
+		).Patch("/organizations/{organization}/members/roles", api.patchOrganizationRole)
+		r.With(
+			apiKeyMiddleware,
+			api.customRolesEnabledMW,
+			httpmw.ExtractOrganizationParam(api.Database),
+		).Delete("/organizations/{organization}/members/roles/{roleName}", api.deleteOrganizationRole)
+		r.Route("/users/oidc/sync-rules", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.templateRBACEnabledMW,
+			)
+			r.Get("/", api.oidcSyncRules)
+			r.Put("/", api.putOIDCSyncRules)
+			r.Post("/dry-run", api.oidcSyncRulesDryRun)
+		})
 		r.Route("/users/{user}/quiet-hours", func(r chi.Router) {
 			r.Use(
 				api.autostopRequirementEnabledMW,
@@ -318,12 +362,27 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Use(
 				api.scimEnabledMW,
 			)
+			r.Get("/ServiceProviderConfig", api.scimGetServiceProviderConfig)
+			r.Route("/Schemas", func(r chi.Router) {
+				r.Get("/", api.scimGetSchemas)
+				r.Get("/{id}", api.scimGetSchema)
+			})
 			r.Post("/Users", api.scimPostUser)
 			r.Route("/Users", func(r chi.Router) {
 				r.Get("/", api.scimGetUsers)
 				r.Post("/", api.scimPostUser)
 				r.Get("/{id}", api.scimGetUser)
+				r.Put("/{id}", api.scimPutUser)
 				r.Patch("/{id}", api.scimPatchUser)
+				r.Delete("/{id}", api.scimDeleteUser)
+			})
+			r.Post("/Groups", api.scimPostGroup)
+			r.Route("/Groups", func(r chi.Router) {
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
 			})
 		})
 	}
@@ -430,6 +489,8 @@ type Options struct {
 
 	// optional pre-shared key for authentication of external provisioner daemons
 	ProvisionerDaemonPSK string
+	// How often connected external provisioner daemons are marked as seen.
+	ProvisionerDaemonHeartbeatInterval time.Duration
 
 	CheckInactiveUsersCancelFunc func()
 }
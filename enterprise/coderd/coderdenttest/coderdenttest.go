@@ -47,18 +47,19 @@ func init() {
 
 type Options struct {
 	*coderdtest.Options
-	AuditLogging               bool
-	BrowserOnly                bool
-	EntitlementsUpdateInterval time.Duration
-	SCIMAPIKey                 []byte
-	UserWorkspaceQuota         int
-	ProxyHealthInterval        time.Duration
-	LicenseOptions             *LicenseOptions
-	DontAddLicense             bool
-	DontAddFirstUser           bool
-	ReplicaSyncUpdateInterval  time.Duration
-	ExternalTokenEncryption    []dbcrypt.Cipher
-	ProvisionerDaemonPSK       string
+	AuditLogging                       bool
+	BrowserOnly                        bool
+	EntitlementsUpdateInterval         time.Duration
+	SCIMAPIKey                         []byte
+	UserWorkspaceQuota                 int
+	ProxyHealthInterval                time.Duration
+	LicenseOptions                     *LicenseOptions
+	DontAddLicense                     bool
+	DontAddFirstUser                   bool
+	ReplicaSyncUpdateInterval          time.Duration
+	ExternalTokenEncryption            []dbcrypt.Cipher
+	ProvisionerDaemonPSK               string
+	ProvisionerDaemonHeartbeatInterval time.Duration
 }
 
 // New constructs a codersdk client connected to an in-memory Enterprise API instance.
@@ -86,20 +87,21 @@ func NewWithAPI(t *testing.T, options *Options) (
 	require.False(t, options.DontAddFirstUser && !options.DontAddLicense, "DontAddFirstUser requires DontAddLicense")
 	setHandler, cancelFunc, serverURL, oop := coderdtest.NewOptions(t, options.Options)
 	coderAPI, err := coderd.New(context.Background(), &coderd.Options{
-		RBAC:                       true,
-		AuditLogging:               options.AuditLogging,
-		BrowserOnly:                options.BrowserOnly,
-		SCIMAPIKey:                 options.SCIMAPIKey,
-		DERPServerRelayAddress:     oop.AccessURL.String(),
-		DERPServerRegionID:         oop.BaseDERPMap.RegionIDs()[0],
-		ReplicaSyncUpdateInterval:  options.ReplicaSyncUpdateInterval,
-		Options:                    oop,
-		EntitlementsUpdateInterval: options.EntitlementsUpdateInterval,
-		LicenseKeys:                Keys,
-		ProxyHealthInterval:        options.ProxyHealthInterval,
-		DefaultQuietHoursSchedule:  oop.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value(),
-		ProvisionerDaemonPSK:       options.ProvisionerDaemonPSK,
-		ExternalTokenEncryption:    options.ExternalTokenEncryption,
+		RBAC:                               true,
+		AuditLogging:                       options.AuditLogging,
+		BrowserOnly:                        options.BrowserOnly,
+		SCIMAPIKey:                         options.SCIMAPIKey,
+		DERPServerRelayAddress:             oop.AccessURL.String(),
+		DERPServerRegionID:                 oop.BaseDERPMap.RegionIDs()[0],
+		ReplicaSyncUpdateInterval:          options.ReplicaSyncUpdateInterval,
+		Options:                            oop,
+		EntitlementsUpdateInterval:         options.EntitlementsUpdateInterval,
+		LicenseKeys:                        Keys,
+		ProxyHealthInterval:                options.ProxyHealthInterval,
+		DefaultQuietHoursSchedule:          oop.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value(),
+		ProvisionerDaemonPSK:               options.ProvisionerDaemonPSK,
+		ExternalTokenEncryption:            options.ExternalTokenEncryption,
+		ProvisionerDaemonHeartbeatInterval: options.ProvisionerDaemonHeartbeatInterval,
 	})
 	require.NoError(t, err)
 	setHandler(coderAPI.AGPL.RootHandler)
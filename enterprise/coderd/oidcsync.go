@@ -0,0 +1,448 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/idpsync"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get OIDC sync rules
+// @ID get-oidc-sync-rules
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {object} codersdk.OIDCSyncRules
+// @Router /users/oidc/sync-rules [get]
+func (api *API) oidcSyncRules(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rules, err := getOIDCSyncRules(ctx, api.Database)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch OIDC sync rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, rules)
+}
+
+// @Summary Update OIDC sync rules
+// @ID update-oidc-sync-rules
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body codersdk.OIDCSyncRules true "OIDC sync rules"
+// @Success 200 {object} codersdk.OIDCSyncRules
+// @Router /users/oidc/sync-rules [put]
+func (api *API) putOIDCSyncRules(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		auditor = api.AGPL.Auditor.Load()
+	)
+
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceDeploymentValues) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Insufficient permissions to update OIDC sync rules.",
+		})
+		return
+	}
+
+	var rules codersdk.OIDCSyncRules
+	if !httpapi.Read(ctx, rw, r, &rules) {
+		return
+	}
+	if rules.Rules == nil {
+		rules.Rules = []codersdk.OIDCSyncRule{}
+	}
+	if _, err := idpsync.New(rules); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid OIDC sync rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	old, err := getOIDCSyncRules(ctx, api.Database)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch current OIDC sync rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to marshal OIDC sync rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.OIDCSyncRules](rw, &audit.RequestParams{
+		Audit:   *auditor,
+		Log:     api.Logger,
+		Request: r,
+		Action:  database.AuditActionWrite,
+	})
+	defer commitAudit()
+	// The rules are a singleton, so they share an artificial ID.
+	auditID := uuid.New()
+	aReq.Old = database.OIDCSyncRules{ID: auditID, SyncRoles: old.SyncRoles, Rules: old.Rules}
+	aReq.New = database.OIDCSyncRules{ID: auditID, SyncRoles: rules.SyncRoles, Rules: rules.Rules}
+
+	err = api.Database.UpsertOIDCSyncRules(ctx, string(rulesJSON))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update OIDC sync rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, rules)
+}
+
+// @Summary Evaluate OIDC sync rules against claims
+// @ID evaluate-oidc-sync-rules-against-claims
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body codersdk.OIDCSyncDryRunRequest true "Claims, and optionally rules to evaluate"
+// @Success 200 {object} codersdk.OIDCSyncDryRunResponse
+// @Router /users/oidc/sync-rules/dry-run [post]
+func (api *API) oidcSyncRulesDryRun(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req codersdk.OIDCSyncDryRunRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// Reading the saved rules checks that the user may see them, so the
+	// same check is made for rules in the request.
+	rules, err := getOIDCSyncRules(ctx, api.Database)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch OIDC sync rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if req.Rules != nil {
+		rules = *req.Rules
+	}
+	engine, err := idpsync.New(rules)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid OIDC sync rules.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	result := engine.Evaluate(req.Claims)
+
+	orgs, err := syncOrganizations(ctx, api.Database, result)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to fetch organizations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.OIDCSyncDryRunResponse{
+		MatchedRules:  result.MatchedRules,
+		SiteRoles:     result.SiteRoles,
+		Organizations: make([]codersdk.OIDCSyncOrganizationResult, 0, len(orgs)),
+	}
+	if resp.MatchedRules == nil {
+		resp.MatchedRules = []string{}
+	}
+	for _, org := range orgs {
+		resp.Organizations = append(resp.Organizations, codersdk.OIDCSyncOrganizationResult{
+			OrganizationName: org.name,
+			OrganizationID:   org.id,
+			Roles:            org.Roles,
+			Groups:           org.Groups,
+			QuotaAllowances:  org.QuotaAllowances,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+func getOIDCSyncRules(ctx context.Context, db database.Store) (codersdk.OIDCSyncRules, error) {
+	rulesJSON, err := db.GetOIDCSyncRules(ctx)
+	if err != nil {
+		return codersdk.OIDCSyncRules{}, err
+	}
+	var rules codersdk.OIDCSyncRules
+	err = json.Unmarshal([]byte(rulesJSON), &rules)
+	if err != nil {
+		return codersdk.OIDCSyncRules{}, xerrors.Errorf("unmarshal oidc sync rules: %w", err)
+	}
+	if rules.Rules == nil {
+		rules.Rules = []codersdk.OIDCSyncRule{}
+	}
+	return rules, nil
+}
+
+type syncOrganization struct {
+	idpsync.Organization
+	name string
+	// id is uuid.Nil if the organization does not exist.
+	id uuid.UUID
+}
+
+// syncOrganizations resolves the organizations of a sync result by name,
+// sorted by name. The empty name refers to the default organization, which
+// is the oldest one.
+func syncOrganizations(ctx context.Context, db database.Store, result idpsync.Result) ([]syncOrganization, error) {
+	orgs := make([]syncOrganization, 0, len(result.Organizations))
+	for name, assigned := range result.Organizations {
+		var org database.Organization
+		if name == "" {
+			all, err := db.GetOrganizations(ctx)
+			if err != nil {
+				return nil, xerrors.Errorf("get organizations: %w", err)
+			}
+			for _, o := range all {
+				if org.ID == uuid.Nil || o.CreatedAt.Before(org.CreatedAt) {
+					org = o
+				}
+			}
+			name = org.Name
+		} else {
+			var err error
+			org, err = db.GetOrganizationByName(ctx, name)
+			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+				return nil, xerrors.Errorf("get organization %q: %w", name, err)
+			}
+		}
+		orgs = append(orgs, syncOrganization{Organization: assigned, name: name, id: org.ID})
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].name < orgs[j].name
+	})
+	return orgs, nil
+}
+
+// applyOIDCSyncRules adds the user to the organizations the sync rules
+// assigned, and sets their site roles, and their organization roles and
+// groups in every organization they are a member of. Only the kinds of
+// assignments the rules make are changed.
+func (api *API) applyOIDCSyncRules(ctx context.Context, logger slog.Logger, db database.Store, userID uuid.UUID, result idpsync.Result) error {
+	api.entitlementsMu.RLock()
+	groupsEnabled := api.entitlements.Features[codersdk.FeatureTemplateRBAC].Enabled
+	rolesEnabled := api.entitlements.Features[codersdk.FeatureUserRoleManagement].Enabled
+	api.entitlementsMu.RUnlock()
+
+	logger = logger.With(slog.F("user_id", userID), slog.F("matched_rules", result.MatchedRules))
+	if !rolesEnabled {
+		logger.Warn(ctx, "attempted to assign roles with OIDC sync rules without enterprise entitlement, roles left unchanged")
+	}
+	if !groupsEnabled {
+		logger.Warn(ctx, "attempted to assign groups with OIDC sync rules without enterprise entitlement, groups left unchanged")
+	}
+
+	return db.InTx(func(tx database.Store) error {
+		orgs, err := syncOrganizations(ctx, tx, result)
+		if err != nil {
+			return err
+		}
+		assigned := make(map[uuid.UUID]idpsync.Organization, len(orgs))
+		for _, org := range orgs {
+			if org.id == uuid.Nil {
+				logger.Warn(ctx, "OIDC sync rules assigned an organization that does not exist",
+					slog.F("organization", org.name),
+				)
+				continue
+			}
+			assigned[org.id] = org.Organization
+
+			_, err := tx.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+				OrganizationID: org.id,
+				UserID:         userID,
+			})
+			if err == nil {
+				continue
+			}
+			if !xerrors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get organization member: %w", err)
+			}
+			_, err = tx.InsertOrganizationMember(ctx, database.InsertOrganizationMemberParams{
+				OrganizationID: org.id,
+				UserID:         userID,
+				CreatedAt:      dbtime.Now(),
+				UpdatedAt:      dbtime.Now(),
+				Roles:          []string{},
+			})
+			if err != nil {
+				return xerrors.Errorf("insert organization member: %w", err)
+			}
+		}
+
+		memberships, err := tx.GetOrganizationsByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf("get user organizations: %w", err)
+		}
+		for _, org := range memberships {
+			assignment := assigned[org.ID]
+			if rolesEnabled && result.SyncOrganizationRoles {
+				roles := make([]string, 0, len(assignment.Roles))
+				for _, role := range assignment.Roles {
+					roles = append(roles, role+":"+org.ID.String())
+				}
+				roles, err = existingRoles(ctx, logger, tx, roles)
+				if err != nil {
+					return err
+				}
+				_, err = tx.UpdateMemberRoles(ctx, database.UpdateMemberRolesParams{
+					GrantedRoles: roles,
+					UserID:       userID,
+					OrgID:        org.ID,
+				})
+				if err != nil {
+					return xerrors.Errorf("update organization %q roles: %w", org.Name, err)
+				}
+			}
+			if groupsEnabled && result.SyncGroups {
+				err = syncOrganizationGroups(ctx, tx, userID, org.ID, assignment)
+				if err != nil {
+					return xerrors.Errorf("sync organization %q groups: %w", org.Name, err)
+				}
+			}
+		}
+
+		if rolesEnabled && result.SyncSiteRoles {
+			roles, err := existingRoles(ctx, logger, tx, result.SiteRoles)
+			if err != nil {
+				return err
+			}
+			if !result.OwnerManaged && !slices.Contains(roles, rbac.RoleOwner()) {
+				// Owners are only demoted by rules that manage the owner role,
+				// so that unrelated rules can't lock out the deployment.
+				user, err := tx.GetUserByID(ctx, userID)
+				if err != nil {
+					return xerrors.Errorf("get user: %w", err)
+				}
+				if slices.Contains(user.RBACRoles, rbac.RoleOwner()) {
+					roles = append(roles, rbac.RoleOwner())
+				}
+			}
+			_, err = coderd.UpdateSiteUserRoles(ctx, tx, database.UpdateUserRolesParams{
+				GrantedRoles: roles,
+				ID:           userID,
+			})
+			if err != nil {
+				return xerrors.Errorf("update site roles: %w", err)
+			}
+		}
+		return nil
+	}, nil)
+}
+
+// existingRoles filters out roles that do not exist, since rules may refer to
+// custom roles that have not been created yet.
+func existingRoles(ctx context.Context, logger slog.Logger, db database.Store, names []string) ([]string, error) {
+	existing, err := rolestore.Expand(ctx, db, names)
+	if err != nil {
+		return nil, xerrors.Errorf("expand roles: %w", err)
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, role := range existing {
+		exists[role.Name] = true
+	}
+	filtered := make([]string, 0, len(names))
+	for _, name := range names {
+		if exists[name] {
+			filtered = append(filtered, name)
+			continue
+		}
+		logger.Debug(ctx, "OIDC sync rules assigned a role that does not exist", slog.F("role", name))
+	}
+	return filtered, nil
+}
+
+// syncOrganizationGroups replaces the groups of the user in the organization
+// with the assigned groups, creating groups that are missing with their quota
+// allowance.
+func syncOrganizationGroups(ctx context.Context, tx database.Store, userID, orgID uuid.UUID, assignment idpsync.Organization) error {
+	err := tx.DeleteGroupMembersByOrgAndUser(ctx, database.DeleteGroupMembersByOrgAndUserParams{
+		UserID:         userID,
+		OrganizationID: orgID,
+	})
+	if err != nil {
+		return xerrors.Errorf("delete user groups: %w", err)
+	}
+	if len(assignment.Groups) == 0 {
+		return nil
+	}
+
+	created, err := tx.InsertMissingGroups(ctx, database.InsertMissingGroupsParams{
+		OrganizationID: orgID,
+		GroupNames:     assignment.Groups,
+		Source:         database.GroupSourceOidc,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert missing groups: %w", err)
+	}
+	err = tx.InsertUserGroupsByName(ctx, database.InsertUserGroupsByNameParams{
+		UserID:         userID,
+		OrganizationID: orgID,
+		GroupNames:     assignment.Groups,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert user groups: %w", err)
+	}
+
+	// Quota allowances are only set on groups created by sync, so that
+	// allowances changed by admins are kept.
+	for _, group := range created {
+		allowance, ok := assignment.QuotaAllowances[group.Name]
+		if !ok || int(group.QuotaAllowance) == allowance {
+			continue
+		}
+		_, err = tx.UpdateGroupByID(ctx, database.UpdateGroupByIDParams{
+			ID:             group.ID,
+			Name:           group.Name,
+			DisplayName:    group.DisplayName,
+			AvatarURL:      group.AvatarURL,
+			QuotaAllowance: int32(allowance),
+		})
+		if err != nil {
+			return xerrors.Errorf("update group %q quota allowance: %w", group.Name, err)
+		}
+	}
+	return nil
+}
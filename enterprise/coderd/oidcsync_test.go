@@ -0,0 +1,319 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOIDCSyncRules(t *testing.T) {
+	t.Parallel()
+
+	// Rules assign organization roles by their unqualified name.
+	const orgAdmin = "organization-admin"
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		rules, err := client.OIDCSyncRules(ctx)
+		require.NoError(t, err)
+		require.Empty(t, rules.Rules)
+
+		expected := codersdk.OIDCSyncRules{
+			Rules: []codersdk.OIDCSyncRule{{
+				Name:   "teams",
+				Claim:  "groups",
+				Match:  "^team-(.+)$",
+				Groups: []string{"$1"},
+			}},
+		}
+		updated, err := client.UpdateOIDCSyncRules(ctx, expected)
+		require.NoError(t, err)
+		require.Equal(t, expected, updated)
+
+		rules, err = client.OIDCSyncRules(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, rules)
+
+		// Invalid rules are rejected.
+		_, err = client.UpdateOIDCSyncRules(ctx, codersdk.OIDCSyncRules{
+			Rules: []codersdk.OIDCSyncRule{{Name: "invalid", Claim: "groups", Match: "(", Groups: []string{"a"}}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Rules can only assign roles when role sync is enabled.
+		_, err = client.UpdateOIDCSyncRules(ctx, codersdk.OIDCSyncRules{
+			Rules: []codersdk.OIDCSyncRule{{Name: "admins", Claim: "groups", SiteRoles: []string{rbac.RoleOwner()}}},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Members can neither read nor update the rules.
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, err = member.OIDCSyncRules(ctx)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+		_, err = member.UpdateOIDCSyncRules(ctx, expected)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("NotEntitled", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.OIDCSyncRules(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, api, owner := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		defaultOrg, err := client.Organization(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		eng := dbgen.Organization(t, api.Database, database.Organization{Name: "eng"})
+
+		_, err = client.UpdateOIDCSyncRules(ctx, codersdk.OIDCSyncRules{
+			SyncRoles: true,
+			Rules: []codersdk.OIDCSyncRule{{
+				Name:      "admins",
+				Claim:     "groups",
+				Match:     "^admins$",
+				SiteRoles: []string{rbac.RoleOwner()},
+			}},
+		})
+		require.NoError(t, err)
+
+		// The saved rules are evaluated by default.
+		resp, err := client.OIDCSyncDryRun(ctx, codersdk.OIDCSyncDryRunRequest{
+			Claims: map[string]interface{}{"groups": []string{"admins"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.OIDCSyncDryRunResponse{
+			MatchedRules:  []string{"admins"},
+			SiteRoles:     []string{rbac.RoleOwner()},
+			Organizations: []codersdk.OIDCSyncOrganizationResult{},
+		}, resp)
+
+		// Rules in the request are evaluated instead.
+		resp, err = client.OIDCSyncDryRun(ctx, codersdk.OIDCSyncDryRunRequest{
+			Claims: map[string]interface{}{
+				"groups": []string{"team-infra"},
+				"org":    map[string]interface{}{"departments": []string{"eng", "sales"}},
+			},
+			Rules: &codersdk.OIDCSyncRules{
+				SyncRoles: true,
+				Rules: []codersdk.OIDCSyncRule{{
+					Name:           "teams",
+					Claim:          "groups",
+					Match:          "^team-(.+)$",
+					Groups:         []string{"$1"},
+					QuotaAllowance: ptr.Ref(5),
+				}, {
+					Name:              "departments",
+					Claim:             "org.departments",
+					Organization:      "$0",
+					OrganizationRoles: []string{orgAdmin},
+				}},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"teams", "departments"}, resp.MatchedRules)
+		require.Empty(t, resp.SiteRoles)
+		require.ElementsMatch(t, []codersdk.OIDCSyncOrganizationResult{{
+			OrganizationName: eng.Name,
+			OrganizationID:   eng.ID,
+			Roles:            []string{orgAdmin},
+			Groups:           []string{},
+			QuotaAllowances:  map[string]int{},
+		}, {
+			OrganizationName: defaultOrg.Name,
+			OrganizationID:   defaultOrg.ID,
+			Roles:            []string{},
+			Groups:           []string{"infra"},
+			QuotaAllowances:  map[string]int{"infra": 5},
+		}, {
+			OrganizationName: "sales",
+			OrganizationID:   uuid.Nil,
+			Roles:            []string{orgAdmin},
+			Groups:           []string{},
+			QuotaAllowances:  map[string]int{},
+		}}, resp.Organizations)
+	})
+
+	t.Run("Login", func(t *testing.T) {
+		t.Parallel()
+
+		runner := setupOIDCTest(t, oidcTestConfig{
+			Config: func(cfg *coderd.OIDCConfig) {
+				cfg.AllowSignups = true
+				// Rules replace group sync configured by flags.
+				cfg.GroupField = "groups"
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		eng := dbgen.Organization(t, runner.API.Database, database.Organization{Name: "eng"})
+
+		_, err := runner.AdminClient.UpdateOIDCSyncRules(ctx, codersdk.OIDCSyncRules{
+			SyncRoles: true,
+			Rules: []codersdk.OIDCSyncRule{{
+				Name:      "admins",
+				Claim:     "groups",
+				Match:     "^admins$",
+				SiteRoles: []string{rbac.RoleTemplateAdmin()},
+			}, {
+				Name:           "teams",
+				Claim:          "groups",
+				Match:          "^team-(.+)$",
+				Groups:         []string{"$1"},
+				QuotaAllowance: ptr.Ref(7),
+			}, {
+				Name:              "departments",
+				Claim:             "department",
+				Organization:      "$0",
+				OrganizationRoles: []string{orgAdmin},
+			}},
+		})
+		require.NoError(t, err)
+
+		_, resp := runner.Login(t, jwt.MapClaims{
+			"email":      "alice@coder.com",
+			"groups":     []string{"admins", "team-infra", "unmatched"},
+			"department": "eng",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		runner.AssertRoles(t, "alice", []string{rbac.RoleTemplateAdmin()})
+		runner.AssertGroups(t, "alice", []string{"infra"})
+
+		group, err := runner.AdminClient.GroupByOrgAndName(ctx, runner.AdminUser.OrganizationIDs[0], "infra")
+		require.NoError(t, err)
+		require.Equal(t, 7, group.QuotaAllowance)
+		require.Equal(t, codersdk.GroupSourceOIDC, group.Source)
+
+		roles, err := runner.AdminClient.UserRoles(ctx, "alice")
+		require.NoError(t, err)
+		require.Contains(t, roles.OrganizationRoles, eng.ID)
+		require.Contains(t, roles.OrganizationRoles[eng.ID], rbac.RoleOrgAdmin(eng.ID))
+
+		// Roles and groups are removed on the next login when the claims no
+		// longer match.
+		_, resp = runner.Login(t, jwt.MapClaims{
+			"email":      "alice@coder.com",
+			"groups":     []string{},
+			"department": "sales",
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		runner.AssertRoles(t, "alice", []string{})
+		runner.AssertGroups(t, "alice", []string{})
+		roles, err = runner.AdminClient.UserRoles(ctx, "alice")
+		require.NoError(t, err)
+		require.NotContains(t, roles.OrganizationRoles[eng.ID], rbac.RoleOrgAdmin(eng.ID))
+	})
+
+	t.Run("PreservesRoles", func(t *testing.T) {
+		t.Parallel()
+
+		runner := setupOIDCTest(t, oidcTestConfig{
+			Config: func(cfg *coderd.OIDCConfig) {
+				cfg.AllowSignups = true
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, resp := runner.Login(t, jwt.MapClaims{
+			"email":  "alice@coder.com",
+			"groups": []string{},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		_, err := runner.AdminClient.UpdateUserRoles(ctx, "alice", codersdk.UpdateRoles{
+			Roles: []string{rbac.RoleOwner(), rbac.RoleUserAdmin()},
+		})
+		require.NoError(t, err)
+		infra, err := runner.AdminClient.CreateGroup(ctx, runner.AdminUser.OrganizationIDs[0], codersdk.CreateGroupRequest{
+			Name:           "infra",
+			QuotaAllowance: 3,
+		})
+		require.NoError(t, err)
+
+		// A group-only rule leaves roles and the allowance of existing groups
+		// alone.
+		_, err = runner.AdminClient.UpdateOIDCSyncRules(ctx, codersdk.OIDCSyncRules{
+			Rules: []codersdk.OIDCSyncRule{{
+				Name:           "teams",
+				Claim:          "groups",
+				Match:          "^team-(.+)$",
+				Groups:         []string{"$1"},
+				QuotaAllowance: ptr.Ref(7),
+			}},
+		})
+		require.NoError(t, err)
+		_, resp = runner.Login(t, jwt.MapClaims{
+			"email":  "alice@coder.com",
+			"groups": []string{"team-infra"},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		runner.AssertRoles(t, "alice", []string{rbac.RoleOwner(), rbac.RoleUserAdmin()})
+		runner.AssertGroups(t, "alice", []string{"infra"})
+		group, err := runner.AdminClient.Group(ctx, infra.ID)
+		require.NoError(t, err)
+		require.Equal(t, 3, group.QuotaAllowance)
+
+		// Rules that sync site roles only remove the owner role if they
+		// assign it by name.
+		_, err = runner.AdminClient.UpdateOIDCSyncRules(ctx, codersdk.OIDCSyncRules{
+			SyncRoles: true,
+			Rules: []codersdk.OIDCSyncRule{{
+				Name:      "template-admins",
+				Claim:     "groups",
+				Match:     "^template-admins$",
+				SiteRoles: []string{rbac.RoleTemplateAdmin()},
+			}},
+		})
+		require.NoError(t, err)
+		_, resp = runner.Login(t, jwt.MapClaims{
+			"email":  "alice@coder.com",
+			"groups": []string{"template-admins"},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		runner.AssertRoles(t, "alice", []string{rbac.RoleOwner(), rbac.RoleTemplateAdmin()})
+	})
+}
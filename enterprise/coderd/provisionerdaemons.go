@@ -14,10 +14,12 @@ import (
 
 	"github.com/coder/coder/v2/provisionersdk"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/hashicorp/yamux"
 	"github.com/moby/moby/pkg/namesgenerator"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/maps"
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 	"storj.io/drpc/drpcmux"
@@ -26,6 +28,8 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
@@ -85,9 +89,27 @@ func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	keyNames := map[uuid.UUID]string{}
 	apiDaemons := make([]codersdk.ProvisionerDaemon, 0)
 	for _, daemon := range daemons {
-		apiDaemons = append(apiDaemons, convertProvisionerDaemon(daemon))
+		apiDaemon := convertProvisionerDaemon(daemon)
+		if daemon.KeyID.Valid {
+			name, ok := keyNames[daemon.KeyID.UUID]
+			if !ok {
+				key, err := api.Database.GetProvisionerKeyByID(ctx, daemon.KeyID.UUID)
+				if err != nil && !httpapi.Is404Error(err) {
+					httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+						Message: "Internal error fetching provisioner keys.",
+						Detail:  err.Error(),
+					})
+					return
+				}
+				name = key.Name
+				keyNames[daemon.KeyID.UUID] = name
+			}
+			apiDaemon.KeyName = name
+		}
+		apiDaemons = append(apiDaemons, apiDaemon)
 	}
 	httpapi.Write(ctx, rw, http.StatusOK, apiDaemons)
 }
@@ -165,6 +187,15 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		id = uuid.New()
 	}
 
+	apiVersion, err := proto.NegotiateVersion(r.URL.Query().Get("version"))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Incompatible provisioner daemon version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	provisionersMap := map[codersdk.ProvisionerType]struct{}{}
 	for _, provisioner := range r.URL.Query()["provisioner"] {
 		switch provisioner {
@@ -187,12 +218,52 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		api.Logger.Warn(ctx, "unnamed provisioner daemon")
 	}
 
-	tags, authorized := api.provisionerDaemonAuth.authorize(r, tags)
-	if !authorized {
-		api.Logger.Warn(ctx, "unauthorized provisioner daemon serve request", slog.F("tags", tags))
-		httpapi.Write(ctx, rw, http.StatusForbidden,
-			codersdk.Response{Message: "You aren't allowed to create provisioner daemons"})
-		return
+	var keyID uuid.NullUUID
+	if r.Header.Get(codersdk.ProvisionerDaemonKey) != "" {
+		key, ok, err := api.provisionerKeyFromRequest(r)
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+		if !ok {
+			api.Logger.Warn(ctx, "provisioner daemon serve request with invalid provisioner key")
+			httpapi.Write(ctx, rw, http.StatusForbidden,
+				codersdk.Response{Message: "Invalid provisioner key"})
+			return
+		}
+		// Daemons connecting with a key get exactly the tags of the key.
+		for k, v := range tags {
+			if key.Tags[k] != v {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: "Tags are set by the provisioner key and can't be changed.",
+					Validations: []codersdk.ValidationError{
+						{Field: "tags", Detail: fmt.Sprintf("tag %q does not match the provisioner key %q", k, key.Name)},
+					},
+				})
+				return
+			}
+		}
+		// Keys belong to a single organization. The nil organization is
+		// sent by daemons that don't specify one, and they serve the
+		// organization of their key.
+		orgID, _ := uuid.Parse(chi.URLParam(r, "organization"))
+		if orgID != uuid.Nil && orgID != key.OrganizationID {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("Provisioner key %q does not belong to this organization.", key.Name),
+			})
+			return
+		}
+		tags = maps.Clone(key.Tags)
+		keyID = uuid.NullUUID{UUID: key.ID, Valid: true}
+	} else {
+		var authorized bool
+		tags, authorized = api.provisionerDaemonAuth.authorize(r, tags)
+		if !authorized {
+			api.Logger.Warn(ctx, "unauthorized provisioner daemon serve request", slog.F("tags", tags))
+			httpapi.Write(ctx, rw, http.StatusForbidden,
+				codersdk.Response{Message: "You aren't allowed to create provisioner daemons"})
+			return
+		}
 	}
 	api.Logger.Debug(ctx, "provisioner authorized", slog.F("tags", tags))
 	if err := provisionerdserver.Tags(tags).Valid(); err != nil {
@@ -221,6 +292,24 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		slog.F("tags", tags),
 	)
 
+	// Record the daemon so that operators can see which daemons are
+	// connected, and with which key.
+	now := dbtime.Now()
+	// nolint:gocritic // The daemon has been authorized above.
+	daemon, err := api.Database.UpsertProvisionerDaemon(dbauthz.AsProvisionerd(ctx), database.UpsertProvisionerDaemonParams{
+		CreatedAt:    now,
+		Name:         name,
+		Provisioners: provisioners,
+		Tags:         tags,
+		LastSeenAt:   sql.NullTime{Time: now, Valid: true},
+		Version:      r.Header.Get(codersdk.BuildVersionHeader),
+		APIVersion:   apiVersion,
+		KeyID:        keyID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Warn(ctx, "failed to record provisioner daemon", slog.Error(err))
+	}
+
 	api.AGPL.WebsocketWaitMutex.Lock()
 	api.AGPL.WebsocketWaitGroup.Add(1)
 	api.AGPL.WebsocketWaitMutex.Unlock()
@@ -283,8 +372,9 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		api.AGPL.UserQuietHoursScheduleStore,
 		api.DeploymentValues,
 		provisionerdserver.Options{
-			ExternalAuthConfigs: api.ExternalAuthConfigs,
-			OIDCConfig:          api.OIDCConfig,
+			ExternalAuthConfigs:   api.ExternalAuthConfigs,
+			OIDCConfig:            api.OIDCConfig,
+			NotificationsEnqueuer: api.NotificationsEnqueuer,
 		},
 	)
 	if err != nil {
@@ -307,8 +397,37 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 			logger.Debug(ctx, "drpc server error", slog.Error(err))
 		},
 	})
+	if keyID.Valid {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		unsubscribe, err := api.Pubsub.Subscribe(provisionerKeyDeletedChannel(keyID.UUID), func(context.Context, []byte) {
+			cancel(errProvisionerKeyDeleted)
+		})
+		if err != nil {
+			_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("subscribe to provisioner key: %s", err))
+			return
+		}
+		defer unsubscribe()
+	}
+	if daemon.ID != uuid.Nil {
+		heartbeatCtx, heartbeatCancel := context.WithCancel(ctx)
+		heartbeatDone := make(chan struct{})
+		go func() {
+			defer close(heartbeatDone)
+			api.provisionerDaemonHeartbeat(heartbeatCtx, logger, daemon.ID)
+		}()
+		defer func() {
+			heartbeatCancel()
+			<-heartbeatDone
+		}()
+	}
 	err = server.Serve(ctx, session)
 	logger.Info(ctx, "provisioner daemon disconnected", slog.Error(err))
+	if errors.Is(context.Cause(ctx), errProvisionerKeyDeleted) {
+		_ = conn.Close(websocket.StatusPolicyViolation, "provisioner key was deleted")
+		return
+	}
 	if err != nil && !xerrors.Is(err, io.EOF) {
 		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("serve: %s", err))
 		return
@@ -316,6 +435,28 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 	_ = conn.Close(websocket.StatusGoingAway, "")
 }
 
+// provisionerDaemonHeartbeat marks a connected daemon as seen until ctx is
+// done, so that connected daemons aren't mistaken for stale ones.
+func (api *API) provisionerDaemonHeartbeat(ctx context.Context, logger slog.Logger, id uuid.UUID) {
+	ticker := time.NewTicker(api.ProvisionerDaemonHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// nolint:gocritic // The daemon was authorized when it connected.
+		err := api.Database.UpdateProvisionerDaemonLastSeenAt(dbauthz.AsProvisionerd(ctx), database.UpdateProvisionerDaemonLastSeenAtParams{
+			ID:         id,
+			LastSeenAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		if err != nil && ctx.Err() == nil {
+			logger.Warn(ctx, "failed to update provisioner daemon last seen at", slog.Error(err))
+		}
+	}
+}
+
 func convertProvisionerDaemon(daemon database.ProvisionerDaemon) codersdk.ProvisionerDaemon {
 	result := codersdk.ProvisionerDaemon{
 		ID:         daemon.ID,
@@ -325,6 +466,9 @@ func convertProvisionerDaemon(daemon database.ProvisionerDaemon) codersdk.Provis
 		Tags:       daemon.Tags,
 		Version:    daemon.Version,
 	}
+	if daemon.KeyID.Valid {
+		result.KeyID = &daemon.KeyID.UUID
+	}
 	for _, provisionerType := range daemon.Provisioners {
 		result.Provisioners = append(result.Provisioners, codersdk.ProvisionerType(provisionerType))
 	}
@@ -7,12 +7,15 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
@@ -317,6 +320,212 @@ func TestProvisionerDaemonServe(t *testing.T) {
 		}
 	})
 
+	t.Run("ProvisionerKey", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:gocritic // Not testing RBAC here.
+		res, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "chicago",
+			Tags: map[string]string{"data_center": "chicago"},
+		})
+		require.NoError(t, err)
+
+		another := codersdk.New(client.URL)
+		srv, err := another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:           uuid.New(),
+			Name:         t.Name(),
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			ProvisionerKey: res.Key,
+		})
+		require.NoError(t, err)
+
+		daemons, err := client.ProvisionerDaemons(ctx) //nolint:gocritic // Test assertion.
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.Equal(t, t.Name(), daemons[0].Name)
+		require.NotNil(t, daemons[0].KeyID)
+		require.Equal(t, "chicago", daemons[0].KeyName)
+		require.Equal(t, map[string]string{
+			"data_center":           "chicago",
+			provisionersdk.TagScope: provisionersdk.ScopeOrganization,
+		}, daemons[0].Tags)
+		require.Equal(t, buildinfo.Version(), daemons[0].Version)
+
+		// Deleting the key disconnects the daemon.
+		//nolint:gocritic // Not testing RBAC here.
+		err = client.DeleteProvisionerKey(ctx, user.OrganizationID, "chicago")
+		require.NoError(t, err)
+		select {
+		case <-srv.DRPCConn().Closed():
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for daemon to be disconnected")
+		}
+
+		// The key can't be used anymore.
+		_, err = another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:           uuid.New(),
+			Name:         t.Name(),
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			ProvisionerKey: res.Key,
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusForbidden, apiError.StatusCode())
+	})
+
+	t.Run("ProvisionerKeyHeartbeat", func(t *testing.T) {
+		t.Parallel()
+		client, db, user := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
+			ProvisionerDaemonHeartbeatInterval: testutil.IntervalFast,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureExternalProvisionerDaemons: 1,
+				},
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:gocritic // Not testing RBAC here.
+		res, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "chicago",
+		})
+		require.NoError(t, err)
+
+		another := codersdk.New(client.URL)
+		srv, err := another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:           uuid.New(),
+			Name:         t.Name(),
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			ProvisionerKey: res.Key,
+		})
+		require.NoError(t, err)
+		defer srv.DRPCConn().Close()
+
+		//nolint:gocritic // Test assertion.
+		daemons, err := db.GetProvisionerDaemons(dbauthz.AsSystemRestricted(ctx))
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.Equal(t, provisionerdproto.CurrentVersion, daemons[0].APIVersion)
+		require.True(t, daemons[0].LastSeenAt.Valid)
+		connectedAt := daemons[0].LastSeenAt.Time
+
+		// Connected daemons are marked as seen periodically.
+		require.Eventually(t, func() bool {
+			//nolint:gocritic // Test assertion.
+			daemons, err := db.GetProvisionerDaemons(dbauthz.AsSystemRestricted(ctx))
+			if !assert.NoError(t, err) || !assert.Len(t, daemons, 1) {
+				return false
+			}
+			return daemons[0].LastSeenAt.Time.After(connectedAt)
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("ProvisionerKeyOtherOrganization", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:gocritic // Not testing RBAC here.
+		res, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "chicago",
+		})
+		require.NoError(t, err)
+		//nolint:gocritic // Not testing RBAC here.
+		other, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{Name: "other"})
+		require.NoError(t, err)
+
+		another := codersdk.New(client.URL)
+		_, err = another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:           uuid.New(),
+			Name:         t.Name(),
+			Organization: other.ID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			ProvisionerKey: res.Key,
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusForbidden, apiError.StatusCode())
+
+		daemons, err := client.ProvisionerDaemons(ctx) //nolint:gocritic // Test assertion.
+		require.NoError(t, err)
+		require.Empty(t, daemons)
+	})
+
+	t.Run("ProvisionerKeyTags", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		//nolint:gocritic // Not testing RBAC here.
+		res, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "chicago",
+			Tags: map[string]string{"data_center": "chicago"},
+		})
+		require.NoError(t, err)
+
+		another := codersdk.New(client.URL)
+		_, err = another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:           uuid.New(),
+			Name:         t.Name(),
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{
+				"data_center": "new_york",
+			},
+			ProvisionerKey: res.Key,
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+	})
+
+	t.Run("BadProvisionerKey", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		another := codersdk.New(client.URL)
+		_, err := another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:           uuid.New(),
+			Name:         t.Name(),
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			ProvisionerKey: uuid.NewString() + ":secret",
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusForbidden, apiError.StatusCode())
+	})
+
 	t.Run("BadPSK", func(t *testing.T) {
 		t.Parallel()
 		client, user := coderdenttest.New(t, &coderdenttest.Options{
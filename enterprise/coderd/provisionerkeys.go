@@ -0,0 +1,249 @@
+package coderd
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+// @Summary Create provisioner key
+// @ID create-provisioner-key
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateProvisionerKeyRequest true "Create provisioner key request"
+// @Success 201 {object} codersdk.CreateProvisionerKeyResponse
+// @Router /organizations/{organization}/provisionerkeys [post]
+func (api *API) postProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		org = httpmw.OrganizationParam(r)
+	)
+
+	var req codersdk.CreateProvisionerKeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	tags := database.StringMap{}
+	for k, v := range req.Tags {
+		tags[k] = v
+	}
+	switch tags[provisionersdk.TagScope] {
+	case "", provisionersdk.ScopeOrganization:
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Provisioner keys can only be scoped to the organization.",
+			Validations: []codersdk.ValidationError{
+				{Field: "tags", Detail: fmt.Sprintf("%s must be %q", provisionersdk.TagScope, provisionersdk.ScopeOrganization)},
+			},
+		})
+		return
+	}
+	if _, ok := tags[provisionersdk.TagOwner]; ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Provisioner keys can only be scoped to the organization.",
+			Validations: []codersdk.ValidationError{
+				{Field: "tags", Detail: fmt.Sprintf("%s can't be set", provisionersdk.TagOwner)},
+			},
+		})
+		return
+	}
+	tags[provisionersdk.TagScope] = provisionersdk.ScopeOrganization
+	if err := provisionerdserver.Tags(tags).Valid(); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Given tags are not acceptable to the service",
+			Validations: []codersdk.ValidationError{
+				{Field: "tags", Detail: err.Error()},
+			},
+		})
+		return
+	}
+
+	id := uuid.New()
+	token, hashedSecret, err := generateProvisionerKey(id)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	_, err = api.Database.InsertProvisionerKey(ctx, database.InsertProvisionerKeyParams{
+		ID:             id,
+		CreatedAt:      dbtime.Now(),
+		OrganizationID: org.ID,
+		Name:           req.Name,
+		HashedSecret:   hashedSecret,
+		Tags:           tags,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Provisioner key with name %q already exists.", req.Name),
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateProvisionerKeyResponse{
+		Key: token,
+	})
+}
+
+// @Summary List provisioner keys
+// @ID list-provisioner-keys
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.ProvisionerKey
+// @Router /organizations/{organization}/provisionerkeys [get]
+func (api *API) provisionerKeys(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		org = httpmw.OrganizationParam(r)
+	)
+
+	keys, err := api.Database.ListProvisionerKeysByOrganization(ctx, org.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerKeys(keys))
+}
+
+// @Summary Delete provisioner key
+// @ID delete-provisioner-key
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param provisionerkey path string true "Provisioner key name"
+// @Success 204
+// @Router /organizations/{organization}/provisionerkeys/{provisionerkey} [delete]
+func (api *API) deleteProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		org  = httpmw.OrganizationParam(r)
+		name = chi.URLParam(r, "provisionerkey")
+	)
+
+	key, err := api.Database.GetProvisionerKeyByName(ctx, database.GetProvisionerKeyByNameParams{
+		OrganizationID: org.ID,
+		Name:           name,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	err = api.Database.DeleteProvisionerKey(ctx, key.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	// Disconnect the daemons that are connected with the key, wherever
+	// they are served.
+	err = api.Pubsub.Publish(provisionerKeyDeletedChannel(key.ID), []byte{})
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to publish provisioner key deletion", slog.F("key_id", key.ID), slog.Error(err))
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+var errProvisionerKeyDeleted = xerrors.New("provisioner key deleted")
+
+// provisionerKeyDeletedChannel is published to when a provisioner key is
+// deleted.
+func provisionerKeyDeletedChannel(id uuid.UUID) string {
+	return fmt.Sprintf("provisioner_key_deleted:%s", id)
+}
+
+// provisionerKeyFromRequest returns the provisioner key the request is
+// authenticated with. It returns false if the key is invalid.
+func (api *API) provisionerKeyFromRequest(r *http.Request) (database.ProvisionerKey, bool, error) {
+	token := r.Header.Get(codersdk.ProvisionerDaemonKey)
+	parts := strings.Split(token, ":")
+	if len(parts) != 2 {
+		return database.ProvisionerKey{}, false, nil
+	}
+	id, err := uuid.Parse(parts[0])
+	if err != nil {
+		return database.ProvisionerKey{}, false, nil
+	}
+
+	// nolint:gocritic // Get the key by ID to check the secret.
+	key, err := api.Database.GetProvisionerKeyByID(dbauthz.AsSystemRestricted(r.Context()), id)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// Key IDs are public so we don't care about leaking them via
+		// timing attacks.
+		return database.ProvisionerKey{}, false, nil
+	}
+	if err != nil {
+		return database.ProvisionerKey{}, false, xerrors.Errorf("get provisioner key: %w", err)
+	}
+
+	hashedSecret := sha256.Sum256([]byte(parts[1]))
+	if subtle.ConstantTimeCompare(key.HashedSecret, hashedSecret[:]) != 1 {
+		return database.ProvisionerKey{}, false, nil
+	}
+	return key, true, nil
+}
+
+func generateProvisionerKey(id uuid.UUID) (token string, hashed []byte, err error) {
+	secret, err := cryptorand.HexString(64)
+	if err != nil {
+		return "", nil, xerrors.Errorf("generate secret: %w", err)
+	}
+	hashedSecret := sha256.Sum256([]byte(secret))
+	return fmt.Sprintf("%s:%s", id, secret), hashedSecret[:], nil
+}
+
+func convertProvisionerKeys(keys []database.ProvisionerKey) []codersdk.ProvisionerKey {
+	converted := make([]codersdk.ProvisionerKey, 0, len(keys))
+	for _, key := range keys {
+		converted = append(converted, codersdk.ProvisionerKey{
+			ID:             key.ID,
+			CreatedAt:      key.CreatedAt,
+			OrganizationID: key.OrganizationID,
+			Name:           key.Name,
+			Tags:           key.Tags,
+		})
+	}
+	return converted
+}
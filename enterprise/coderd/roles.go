@@ -0,0 +1,261 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// patchRole will allow creating a custom site wide role.
+//
+// @Summary Upsert a custom site-wide role
+// @ID upsert-a-custom-site-wide-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param request body codersdk.Role true "Upsert role request"
+// @Success 200 {object} codersdk.Role
+// @Router /users/roles [patch]
+func (api *API) patchRole(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req codersdk.Role
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if len(req.OrganizationPermissions) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Site roles cannot grant organization permissions.",
+			Validations: []codersdk.ValidationError{
+				{Field: "organization_permissions", Detail: "must be empty for site roles"},
+			},
+		})
+		return
+	}
+
+	api.upsertCustomRole(ctx, rw, uuid.NullUUID{}, req)
+}
+
+// deleteRole will delete a custom site wide role.
+//
+// @Summary Delete a custom site-wide role
+// @ID delete-a-custom-site-wide-role
+// @Security CoderSessionToken
+// @Tags Members
+// @Param roleName path string true "Role name"
+// @Success 204
+// @Router /users/roles/{roleName} [delete]
+func (api *API) deleteRole(rw http.ResponseWriter, r *http.Request) {
+	api.deleteCustomRole(r.Context(), rw, uuid.NullUUID{}, chi.URLParam(r, "roleName"))
+}
+
+// patchOrganizationRole will allow creating a custom role scoped to an
+// organization.
+//
+// @Summary Upsert a custom organization role
+// @ID upsert-a-custom-organization-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.Role true "Upsert role request"
+// @Success 200 {object} codersdk.Role
+// @Router /organizations/{organization}/members/roles [patch]
+func (api *API) patchOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	organization := httpmw.OrganizationParam(r)
+	var req codersdk.Role
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if len(req.SitePermissions) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Organization roles cannot grant site permissions.",
+			Validations: []codersdk.ValidationError{
+				{Field: "site_permissions", Detail: "must be empty for organization roles"},
+			},
+		})
+		return
+	}
+	if len(req.UserPermissions) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Organization roles cannot grant user permissions.",
+			Validations: []codersdk.ValidationError{
+				{Field: "user_permissions", Detail: "must be empty for organization roles"},
+			},
+		})
+		return
+	}
+
+	api.upsertCustomRole(ctx, rw, uuid.NullUUID{UUID: organization.ID, Valid: true}, req)
+}
+
+// deleteOrganizationRole will delete a custom role scoped to an organization.
+//
+// @Summary Delete a custom organization role
+// @ID delete-a-custom-organization-role
+// @Security CoderSessionToken
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param roleName path string true "Role name"
+// @Success 204
+// @Router /organizations/{organization}/members/roles/{roleName} [delete]
+func (api *API) deleteOrganizationRole(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.deleteCustomRole(r.Context(), rw, uuid.NullUUID{UUID: organization.ID, Valid: true}, chi.URLParam(r, "roleName"))
+}
+
+func (api *API) upsertCustomRole(ctx context.Context, rw http.ResponseWriter, orgID uuid.NullUUID, req codersdk.Role) {
+	// Organization roles are returned with the organization id appended,
+	// so accept the name in either form.
+	name := strings.TrimSuffix(req.Name, ":"+orgID.UUID.String())
+	if err := validateCustomRoleName(name); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid role name.",
+			Validations: []codersdk.ValidationError{
+				{Field: "name", Detail: err.Error()},
+			},
+		})
+		return
+	}
+
+	var validations []codersdk.ValidationError
+	site := convertPermissions("site_permissions", req.SitePermissions, &validations)
+	org := convertPermissions("organization_permissions", req.OrganizationPermissions, &validations)
+	user := convertPermissions("user_permissions", req.UserPermissions, &validations)
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid role permissions.",
+			Validations: validations,
+		})
+		return
+	}
+
+	role, err := api.Database.UpsertCustomRole(ctx, database.UpsertCustomRoleParams{
+		Name:            name,
+		DisplayName:     req.DisplayName,
+		OrganizationID:  orgID,
+		SitePermissions: site,
+		OrgPermissions:  org,
+		UserPermissions: user,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.CustomRole(role))
+}
+
+func (api *API) deleteCustomRole(ctx context.Context, rw http.ResponseWriter, orgID uuid.NullUUID, name string) {
+	lookup := name
+	if orgID.Valid {
+		lookup = name + ":" + orgID.UUID.String()
+	}
+	roles, err := api.Database.CustomRoles(ctx, database.CustomRolesParams{
+		LookupRoles: []string{lookup},
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(roles) == 0 {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	err = api.Database.DeleteCustomRole(ctx, database.DeleteCustomRoleParams{
+		Name:           name,
+		OrganizationID: orgID,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func validateCustomRoleName(name string) error {
+	if err := httpapi.NameValid(name); err != nil {
+		return err
+	}
+	if rbac.ReservedRoleName(name) {
+		return xerrors.Errorf("%q is a reserved role name", name)
+	}
+	return nil
+}
+
+func convertPermissions(field string, permissions []codersdk.Permission, validations *[]codersdk.ValidationError) database.CustomRolePermissions {
+	converted := make(database.CustomRolePermissions, 0, len(permissions))
+	for i, permission := range permissions {
+		perm := rbac.Permission{
+			Negate:       permission.Negate,
+			ResourceType: string(permission.ResourceType),
+			Action:       rbac.Action(permission.Action),
+		}
+		if err := perm.Valid(); err != nil {
+			*validations = append(*validations, codersdk.ValidationError{
+				Field:  fmt.Sprintf("%s[%d]", field, i),
+				Detail: err.Error(),
+			})
+			continue
+		}
+		converted = append(converted, perm)
+	}
+	return converted
+}
+
+func (api *API) customRolesEnabledMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		api.entitlementsMu.RLock()
+		enabled := api.entitlements.Features[codersdk.FeatureUserRoleManagement].Enabled
+		api.entitlementsMu.RUnlock()
+
+		if !enabled {
+			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
+				Message: "Custom roles is an Enterprise feature. Contact sales!",
+			})
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
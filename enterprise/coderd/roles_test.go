@@ -0,0 +1,211 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	deploymentViewer := codersdk.Role{
+		Name:        "deployment-viewer",
+		DisplayName: "Deployment Viewer",
+		SitePermissions: []codersdk.Permission{
+			{ResourceType: codersdk.ResourceDeploymentValues, Action: "read"},
+		},
+	}
+
+	t.Run("Site", func(t *testing.T) {
+		t.Parallel()
+
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserRoleManagement: 1,
+			},
+		}})
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := client.PatchRole(ctx, deploymentViewer)
+		require.NoError(t, err)
+		require.Equal(t, deploymentViewer.Name, role.Name)
+		require.Equal(t, deploymentViewer.SitePermissions, role.SitePermissions)
+
+		roles, err := client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+		require.Contains(t, roles, codersdk.AssignableRoles{
+			Role:       role,
+			Assignable: true,
+		})
+
+		_, err = memberClient.DeploymentConfig(ctx)
+		require.Error(t, err, "member should not read deployment values")
+
+		_, err = client.UpdateUserRoles(ctx, member.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.Name},
+		})
+		require.NoError(t, err)
+
+		_, err = memberClient.DeploymentConfig(ctx)
+		require.NoError(t, err, "custom role grants deployment values")
+
+		err = client.DeleteRole(ctx, role.Name)
+		require.NoError(t, err)
+
+		// Deleted roles remain assigned, but no longer grant anything.
+		_, err = memberClient.DeploymentConfig(ctx)
+		require.Error(t, err)
+
+		err = client.DeleteRole(ctx, role.Name)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserRoleManagement: 1,
+			},
+		}})
+		orgAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleOrgAdmin(owner.OrganizationID))
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		role, err := orgAdminClient.PatchOrganizationRole(ctx, owner.OrganizationID, codersdk.Role{
+			Name:        "template-editor",
+			DisplayName: "Template Editor",
+			OrganizationPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceTemplate, Action: "update"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "template-editor:"+owner.OrganizationID.String(), role.Name)
+
+		roles, err := orgAdminClient.ListOrganizationRoles(ctx, owner.OrganizationID)
+		require.NoError(t, err)
+		require.Contains(t, roles, codersdk.AssignableRoles{
+			Role:       role,
+			Assignable: true,
+		})
+
+		_, err = orgAdminClient.UpdateOrganizationMemberRoles(ctx, owner.OrganizationID, member.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.Name},
+		})
+		require.NoError(t, err)
+
+		err = orgAdminClient.DeleteOrganizationRole(ctx, owner.OrganizationID, "template-editor")
+		require.NoError(t, err)
+	})
+
+	t.Run("Escalation", func(t *testing.T) {
+		t.Parallel()
+
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserRoleManagement: 1,
+			},
+		}})
+		orgAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleOrgAdmin(owner.OrganizationID))
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Organization admins cannot create site wide roles.
+		_, err := orgAdminClient.PatchRole(ctx, deploymentViewer)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Nor grant permissions they do not hold themselves.
+		_, err = orgAdminClient.PatchOrganizationRole(ctx, owner.OrganizationID, codersdk.Role{
+			Name: "workspace-exec",
+			OrganizationPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspaceExecution, Action: "create"},
+			},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Nor assign such a role when an owner has created it.
+		role, err := client.PatchOrganizationRole(ctx, owner.OrganizationID, codersdk.Role{
+			Name: "workspace-exec",
+			OrganizationPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspaceExecution, Action: "create"},
+			},
+		})
+		require.NoError(t, err)
+		_, err = orgAdminClient.UpdateOrganizationMemberRoles(ctx, owner.OrganizationID, member.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.Name},
+		})
+		require.Error(t, err)
+		_, err = client.UpdateOrganizationMemberRoles(ctx, owner.OrganizationID, member.ID.String(), codersdk.UpdateRoles{
+			Roles: []string{role.Name},
+		})
+		require.NoError(t, err, "owners can assign any custom role")
+
+		// Organization roles cannot grant user permissions, which apply
+		// outside of the organization.
+		_, err = client.PatchOrganizationRole(ctx, owner.OrganizationID, codersdk.Role{
+			Name: "user-editor",
+			UserPermissions: []codersdk.Permission{
+				{ResourceType: codersdk.ResourceWorkspace, Action: "update"},
+			},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserRoleManagement: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.PatchRole(ctx, codersdk.Role{Name: rbac.RoleOwner()})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.PatchRole(ctx, codersdk.Role{
+			Name: "bad-permission",
+			SitePermissions: []codersdk.Permission{
+				{ResourceType: "*", Action: "*"},
+			},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+	})
+
+	t.Run("NotEntitled", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := coderdenttest.New(t, nil)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.PatchRole(ctx, deploymentViewer)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		// Listing roles is not an enterprise feature.
+		_, err = client.ListSiteRoles(ctx)
+		require.NoError(t, err)
+	})
+}
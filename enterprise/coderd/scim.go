@@ -1,16 +1,18 @@
 package coderd
 
 import (
+	"context"
 	"crypto/subtle"
 	"database/sql"
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/imulab/go-scim/pkg/v2/handlerutil"
-	scimjson "github.com/imulab/go-scim/pkg/v2/json"
-	"github.com/imulab/go-scim/pkg/v2/service"
 	"github.com/imulab/go-scim/pkg/v2/spec"
 	"golang.org/x/xerrors"
 
@@ -20,6 +22,17 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+const (
+	scimSchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+
+	// scimMaxResults is the maximum number of resources returned in a
+	// single page of a list response.
+	scimMaxResults = 1000
 )
 
 func (api *API) scimEnabledMW(next http.Handler) http.Handler {
@@ -43,43 +56,250 @@ func (api *API) scimVerifyAuthHeader(r *http.Request) bool {
 	return len(api.SCIMAPIKey) != 0 && subtle.ConstantTimeCompare(hdr, api.SCIMAPIKey) == 1
 }
 
-// scimGetUsers intentionally always returns no users. This is done to always force
-// Okta to try and create each user individually, this way we don't need to
-// implement fetching users twice.
+// scimError exposes the SCIM error prototype wrapped somewhere in err to
+// handlerutil.WriteError, which only unwraps a single level.
+type scimError struct {
+	err       error
+	prototype *spec.Error
+}
+
+func (e scimError) Error() string { return e.err.Error() }
+func (e scimError) Unwrap() error { return e.prototype }
+
+// scimWriteError writes err with the status of the SCIM error prototype it
+// wraps, like spec.ErrNotFound. Other errors are internal errors.
+func scimWriteError(rw http.ResponseWriter, err error) {
+	var prototype *spec.Error
+	if !xerrors.As(err, &prototype) {
+		prototype = spec.ErrInternal
+	}
+	_ = handlerutil.WriteError(rw, scimError{err: err, prototype: prototype})
+}
+
+// scimListResponse is a page of resources, as described in RFC 7644 section
+// 3.4.2.
+type scimListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// scimPageParams returns the 1-based startIndex and the count query
+// parameters, with defaults applied.
+func scimPageParams(r *http.Request) (startIndex int, count int) {
+	startIndex, err := strconv.Atoi(r.URL.Query().Get("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	if startIndex > math.MaxInt32 {
+		startIndex = math.MaxInt32
+	}
+	count, err = strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count > scimMaxResults {
+		count = scimMaxResults
+	}
+	if count < 0 {
+		count = 0
+	}
+	return startIndex, count
+}
+
+// scimPage returns the page of resources requested with the 1-based
+// startIndex and the count query parameters.
+func scimPage[T any](r *http.Request, resources []T) scimListResponse {
+	startIndex, count := scimPageParams(r)
+
+	page := []T{}
+	if startIndex <= len(resources) {
+		end := startIndex - 1 + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+		page = resources[startIndex-1 : end]
+	}
+	return scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// scimParseFilter parses the filter query parameter. A nil filter is
+// returned if the parameter isn't set.
+func scimParseFilter(rw http.ResponseWriter, r *http.Request) (scim.Filter, bool) {
+	raw := r.URL.Query().Get("filter")
+	if raw == "" {
+		return nil, true
+	}
+	filter, err := scim.ParseFilter(raw)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("parse filter: %s: %w", err.Error(), spec.ErrInvalidFilter))
+		return nil, false
+	}
+	return filter, true
+}
+
+// scimOrganizationID returns the organization users and groups are
+// provisioned in. Once multi-organization support is added, we should enable
+// a configuration map of user email to organization.
+func (api *API) scimOrganizationID(ctx context.Context) (uuid.UUID, error) {
+	//nolint:gocritic // needed for SCIM
+	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(organizations) == 0 {
+		return uuid.Nil, nil
+	}
+	return organizations[0].ID, nil
+}
+
+// scimGetUsers returns the users matching the filter.
 //
 // @Summary SCIM 2.0: Get users
 // @ID scim-get-users
 // @Security CoderSessionToken
 // @Produce application/scim+json
 // @Tags Enterprise
+// @Param filter query string false "Filter expression"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
 // @Success 200
 // @Router /scim/v2/Users [get]
 //
 //nolint:revive
 func (api *API) scimGetUsers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	if !api.scimVerifyAuthHeader(r) {
 		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
 		return
 	}
 
-	_ = handlerutil.WriteSearchResultToResponse(rw, &service.QueryResponse{
-		TotalResults: 0,
-		StartIndex:   1,
-		ItemsPerPage: 0,
-		Resources:    []scimjson.Serializable{},
+	filter, ok := scimParseFilter(rw, r)
+	if !ok {
+		return
+	}
+	if filter == nil {
+		api.scimGetUsersPage(rw, r)
+		return
+	}
+	if _, ok := scim.Equal(filter, "externalid"); ok {
+		// External IDs aren't stored, so no user can match.
+		httpapi.Write(ctx, rw, http.StatusOK, scimPage(r, []SCIMUser{}))
+		return
+	}
+
+	// Filters are matched below, since most can't be expressed in SQL. An
+	// equality filter on the username still narrows the users that are
+	// loaded. The search also matches substrings of usernames and emails,
+	// which the filter then excludes.
+	params := database.GetUsersParams{}
+	if userName, ok := scim.Equal(filter, "username"); ok {
+		params.Search = userName
+	}
+	//nolint:gocritic // needed for SCIM
+	rows, err := api.Database.GetUsers(dbauthz.AsSystemRestricted(ctx), params)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	users := []SCIMUser{}
+	for _, dbUser := range database.ConvertUserRows(rows) {
+		if !filter.Matches(scimUserAttributes(dbUser)) {
+			continue
+		}
+		users = append(users, scimUser(dbUser))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimPage(r, users))
+}
+
+// scimGetUsersPage returns a page of all users, paginated by the database.
+func (api *API) scimGetUsersPage(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	startIndex, count := scimPageParams(r)
+
+	// A limit of 0 returns all users, so at least one is requested to get
+	// the total.
+	limit := count
+	if limit == 0 {
+		limit = 1
+	}
+	//nolint:gocritic // needed for SCIM
+	rows, err := api.Database.GetUsers(dbauthz.AsSystemRestricted(ctx), database.GetUsersParams{
+		OffsetOpt: int32(startIndex - 1),
+		LimitOpt:  int32(limit),
+	})
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	var total int64
+	if len(rows) > 0 {
+		total = rows[0].Count
+	} else if startIndex > 1 {
+		// The total isn't returned when the page is past the last user.
+		//nolint:gocritic // needed for SCIM
+		total, err = api.Database.GetUserCount(dbauthz.AsSystemRestricted(ctx))
+		if err != nil {
+			scimWriteError(rw, err)
+			return
+		}
+	}
+
+	users := []SCIMUser{}
+	for _, dbUser := range database.ConvertUserRows(rows) {
+		if len(users) == count {
+			break
+		}
+		users = append(users, scimUser(dbUser))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: int(total),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(users),
+		Resources:    users,
 	})
 }
 
-// scimGetUser intentionally always returns an error saying the user wasn't found.
-// This is done to always force Okta to try and create the user, this way we
-// don't need to implement fetching users twice.
-//
+// scimUserParam returns the user with the ID in the URL. It writes a not
+// found error if the user doesn't exist.
+func (api *API) scimUserParam(rw http.ResponseWriter, r *http.Request) (database.User, bool) {
+	id := chi.URLParam(r, "id")
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("user %q not found: %w", id, spec.ErrNotFound))
+		return database.User{}, false
+	}
+
+	//nolint:gocritic // needed for SCIM
+	dbUser, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(r.Context()), uid)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && dbUser.Deleted) {
+		scimWriteError(rw, xerrors.Errorf("user %q not found: %w", id, spec.ErrNotFound))
+		return database.User{}, false
+	}
+	if err != nil {
+		scimWriteError(rw, err)
+		return database.User{}, false
+	}
+	return dbUser, true
+}
+
 // @Summary SCIM 2.0: Get user by ID
 // @ID scim-get-user-by-id
 // @Security CoderSessionToken
 // @Produce application/scim+json
 // @Tags Enterprise
 // @Param id path string true "User ID" format(uuid)
+// @Success 200 {object} coderd.SCIMUser
 // @Failure 404
 // @Router /scim/v2/Users/{id} [get]
 //
@@ -90,7 +310,12 @@ func (api *API) scimGetUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_ = handlerutil.WriteError(rw, spec.ErrNotFound)
+	dbUser, ok := api.scimUserParam(rw, r)
+	if !ok {
+		return
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, scimUser(dbUser))
 }
 
 // We currently use our own struct instead of using the SCIM package. This was
@@ -114,10 +339,65 @@ type SCIMUser struct {
 	Active bool          `json:"active"`
 	Groups []interface{} `json:"groups"`
 	Meta   struct {
-		ResourceType string `json:"resourceType"`
+		ResourceType string     `json:"resourceType"`
+		Created      *time.Time `json:"created,omitempty" format:"date-time"`
+		LastModified *time.Time `json:"lastModified,omitempty" format:"date-time"`
 	} `json:"meta"`
 }
 
+// scimUser converts a database user to its SCIM representation.
+func scimUser(dbUser database.User) SCIMUser {
+	sUser := SCIMUser{
+		Schemas:  []string{scimSchemaUser},
+		ID:       dbUser.ID.String(),
+		UserName: dbUser.Username,
+		Active:   dbUser.Status != database.UserStatusSuspended,
+		Groups:   []interface{}{},
+	}
+	sUser.Emails = append(sUser.Emails, struct {
+		Primary bool   `json:"primary"`
+		Value   string `json:"value" format:"email"`
+		Type    string `json:"type"`
+		Display string `json:"display"`
+	}{Primary: true, Value: dbUser.Email, Type: "work"})
+	sUser.Meta.ResourceType = "User"
+	sUser.Meta.Created = &dbUser.CreatedAt
+	sUser.Meta.LastModified = &dbUser.UpdatedAt
+	return sUser
+}
+
+// scimUserAttributes returns the attributes of a user that can be filtered
+// by.
+func scimUserAttributes(dbUser database.User) scim.Attributes {
+	return func(path string) []string {
+		switch path {
+		case "id":
+			return []string{dbUser.ID.String()}
+		case "username":
+			return []string{dbUser.Username}
+		case "emails", "emails.value":
+			return []string{dbUser.Email}
+		case "active":
+			return []string{strconv.FormatBool(dbUser.Status != database.UserStatusSuspended)}
+		case "meta.created":
+			return []string{dbUser.CreatedAt.UTC().Format(time.RFC3339)}
+		case "meta.lastmodified":
+			return []string{dbUser.UpdatedAt.UTC().Format(time.RFC3339)}
+		}
+		return nil
+	}
+}
+
+// scimPrimaryEmail returns the primary email of a SCIM user.
+func scimPrimaryEmail(sUser SCIMUser) string {
+	for _, e := range sUser.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	return ""
+}
+
 // scimPostUser creates a new user, or returns the existing user if it exists.
 //
 // @Summary SCIM 2.0: Create new user
@@ -142,14 +422,7 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	email := ""
-	for _, e := range sUser.Emails {
-		if e.Primary {
-			email = e.Value
-			break
-		}
-	}
-
+	email := scimPrimaryEmail(sUser)
 	if email == "" {
 		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusBadRequest, Type: "invalidEmail"})
 		return
@@ -200,21 +473,12 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		sUser.UserName = httpapi.UsernameFrom(sUser.UserName)
 	}
 
-	var organizationID uuid.UUID
-	//nolint:gocritic
-	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	organizationID, err := api.scimOrganizationID(ctx)
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
 
-	if len(organizations) > 0 {
-		// Add the user to the first organization. Once multi-organization
-		// support is added, we should enable a configuration map of user
-		// email to organization.
-		organizationID = organizations[0].ID
-	}
-
 	//nolint:gocritic // needed for SCIM
 	dbUser, _, err = api.AGPL.CreateUser(dbauthz.AsSystemRestricted(ctx), api.Database, agpl.CreateUserRequest{
 		CreateUserRequest: codersdk.CreateUserRequest{
@@ -297,3 +561,345 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 
 	httpapi.Write(ctx, rw, http.StatusOK, sUser)
 }
+
+// scimPutUser replaces the username, email and status of a user.
+//
+// @Summary SCIM 2.0: Replace user account
+// @ID scim-replace-user
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "User ID" format(uuid)
+// @Param request body coderd.SCIMUser true "Replace user request"
+// @Success 200 {object} coderd.SCIMUser
+// @Router /scim/v2/Users/{id} [put]
+func (api *API) scimPutUser(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	var sUser SCIMUser
+	err := json.NewDecoder(r.Body).Decode(&sUser)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("decode user: %s: %w", err.Error(), spec.ErrInvalidSyntax))
+		return
+	}
+
+	dbUser, ok := api.scimUserParam(rw, r)
+	if !ok {
+		return
+	}
+
+	email := scimPrimaryEmail(sUser)
+	if email == "" {
+		email = dbUser.Email
+	}
+	username := sUser.UserName
+	if username == "" {
+		username = dbUser.Username
+	}
+	if httpapi.NameValid(username) != nil {
+		username = httpapi.UsernameFrom(username)
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		//nolint:gocritic // needed for SCIM
+		dbUser, err = tx.UpdateUserProfile(dbauthz.AsSystemRestricted(ctx), database.UpdateUserProfileParams{
+			ID:        dbUser.ID,
+			Email:     email,
+			Username:  username,
+			AvatarURL: dbUser.AvatarURL,
+			UpdatedAt: dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update user profile: %w", err)
+		}
+
+		var status database.UserStatus
+		switch {
+		case sUser.Active && dbUser.Status == database.UserStatusSuspended:
+			// The user will get transitioned to Active after logging in.
+			status = database.UserStatusDormant
+		case !sUser.Active && dbUser.Status != database.UserStatusSuspended:
+			status = database.UserStatusSuspended
+		default:
+			return nil
+		}
+		//nolint:gocritic // needed for SCIM
+		dbUser, err = tx.UpdateUserStatus(dbauthz.AsSystemRestricted(ctx), database.UpdateUserStatusParams{
+			ID:        dbUser.ID,
+			Status:    status,
+			UpdatedAt: dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update user status: %w", err)
+		}
+		return nil
+	}, nil)
+	if database.IsUniqueViolation(err) {
+		scimWriteError(rw, xerrors.Errorf("a user with username %q or email %q already exists: %w", username, email, spec.ErrUniqueness))
+		return
+	}
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimUser(dbUser))
+}
+
+// scimDeleteUser deletes a user. Users that own workspaces are suspended
+// instead, so their workspaces aren't left without an owner.
+//
+// @Summary SCIM 2.0: Delete user account
+// @ID scim-delete-user
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "User ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Users/{id} [delete]
+func (api *API) scimDeleteUser(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	dbUser, ok := api.scimUserParam(rw, r)
+	if !ok {
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	workspaces, err := api.Database.GetWorkspaces(dbauthz.AsSystemRestricted(ctx), database.GetWorkspacesParams{
+		OwnerID: dbUser.ID,
+	})
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	if len(workspaces) > 0 {
+		//nolint:gocritic // needed for SCIM
+		_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(ctx), database.UpdateUserStatusParams{
+			ID:        dbUser.ID,
+			Status:    database.UserStatusSuspended,
+			UpdatedAt: dbtime.Now(),
+		})
+	} else {
+		//nolint:gocritic // needed for SCIM
+		err = api.Database.UpdateUserDeletedByID(dbauthz.AsSystemRestricted(ctx), database.UpdateUserDeletedByIDParams{
+			ID:      dbUser.ID,
+			Deleted: true,
+		})
+	}
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+type scimSupported struct {
+	Supported bool `json:"supported"`
+}
+
+type scimServiceProviderConfig struct {
+	Schemas          []string      `json:"schemas"`
+	DocumentationURI string        `json:"documentationUri"`
+	Patch            scimSupported `json:"patch"`
+	Bulk             struct {
+		Supported      bool `json:"supported"`
+		MaxOperations  int  `json:"maxOperations"`
+		MaxPayloadSize int  `json:"maxPayloadSize"`
+	} `json:"bulk"`
+	Filter struct {
+		Supported  bool `json:"supported"`
+		MaxResults int  `json:"maxResults"`
+	} `json:"filter"`
+	ChangePassword        scimSupported `json:"changePassword"`
+	Sort                  scimSupported `json:"sort"`
+	ETag                  scimSupported `json:"etag"`
+	AuthenticationSchemes []struct {
+		Type        string `json:"type"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"authenticationSchemes"`
+	Meta struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+// @Summary SCIM 2.0: Get service provider config
+// @ID scim-get-service-provider-config
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (api *API) scimGetServiceProviderConfig(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	config := scimServiceProviderConfig{
+		Schemas:          []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+		DocumentationURI: "https://coder.com/docs/v2/latest/admin/auth#scim-enterprise",
+		Patch:            scimSupported{Supported: true},
+	}
+	config.Filter.Supported = true
+	config.Filter.MaxResults = scimMaxResults
+	config.AuthenticationSchemes = append(config.AuthenticationSchemes, struct {
+		Type        string `json:"type"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}{
+		Type:        "oauthbearertoken",
+		Name:        "API key",
+		Description: "The Authorization header must match the SCIM API key of the Coder server.",
+	})
+	config.Meta.ResourceType = "ServiceProviderConfig"
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, config)
+}
+
+// scimSchemaAttribute describes an attribute of a resource, as described in
+// RFC 7643 section 7.
+type scimSchemaAttribute struct {
+	Name          string                `json:"name"`
+	Type          string                `json:"type"`
+	MultiValued   bool                  `json:"multiValued"`
+	Required      bool                  `json:"required"`
+	CaseExact     bool                  `json:"caseExact"`
+	Mutability    string                `json:"mutability"`
+	Returned      string                `json:"returned"`
+	Uniqueness    string                `json:"uniqueness"`
+	SubAttributes []scimSchemaAttribute `json:"subAttributes,omitempty"`
+}
+
+// scimAttribute returns a single-valued, optional and writable attribute.
+func scimAttribute(name, attributeType string, subAttributes ...scimSchemaAttribute) scimSchemaAttribute {
+	return scimSchemaAttribute{
+		Name:          name,
+		Type:          attributeType,
+		Mutability:    "readWrite",
+		Returned:      "default",
+		Uniqueness:    "none",
+		SubAttributes: subAttributes,
+	}
+}
+
+func (a scimSchemaAttribute) multiValued() scimSchemaAttribute {
+	a.MultiValued = true
+	return a
+}
+
+func (a scimSchemaAttribute) required() scimSchemaAttribute {
+	a.Required = true
+	return a
+}
+
+func (a scimSchemaAttribute) unique() scimSchemaAttribute {
+	a.Uniqueness = "server"
+	return a
+}
+
+func (a scimSchemaAttribute) readOnly() scimSchemaAttribute {
+	a.Mutability = "readOnly"
+	return a
+}
+
+type scimSchema struct {
+	Schemas     []string              `json:"schemas"`
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Attributes  []scimSchemaAttribute `json:"attributes"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+// scimSchemas are the schemas of the resources Coder supports. Attributes
+// that are accepted but not stored by Coder are not listed.
+var scimSchemas = func() []scimSchema {
+	schemas := []scimSchema{{
+		ID:          scimSchemaUser,
+		Name:        "User",
+		Description: "User Account",
+		Attributes: []scimSchemaAttribute{
+			scimAttribute("userName", "string").required().unique(),
+			scimAttribute("emails", "complex",
+				scimAttribute("value", "string"),
+				scimAttribute("type", "string"),
+				scimAttribute("primary", "boolean"),
+			).multiValued().required(),
+			scimAttribute("active", "boolean"),
+			scimAttribute("groups", "complex",
+				scimAttribute("value", "string").readOnly(),
+				scimAttribute("display", "string").readOnly(),
+			).multiValued().readOnly(),
+		},
+	}, {
+		ID:          scimSchemaGroup,
+		Name:        "Group",
+		Description: "Group",
+		Attributes: []scimSchemaAttribute{
+			scimAttribute("displayName", "string").required().unique(),
+			scimAttribute("members", "complex",
+				scimAttribute("value", "string"),
+				scimAttribute("display", "string").readOnly(),
+			).multiValued(),
+		},
+	}}
+	for i := range schemas {
+		schemas[i].Schemas = []string{"urn:ietf:params:scim:schemas:core:2.0:Schema"}
+		schemas[i].Meta.ResourceType = "Schema"
+	}
+	return schemas
+}()
+
+// @Summary SCIM 2.0: Get schemas
+// @ID scim-get-schemas
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/Schemas [get]
+func (api *API) scimGetSchemas(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, scimPage(r, scimSchemas))
+}
+
+// @Summary SCIM 2.0: Get schema by ID
+// @ID scim-get-schema-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Schema URN"
+// @Success 200
+// @Failure 404
+// @Router /scim/v2/Schemas/{id} [get]
+func (api *API) scimGetSchema(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	for _, schema := range scimSchemas {
+		if schema.ID == id {
+			httpapi.Write(r.Context(), rw, http.StatusOK, schema)
+			return
+		}
+	}
+	scimWriteError(rw, xerrors.Errorf("schema %q not found: %w", id, spec.ErrNotFound))
+}
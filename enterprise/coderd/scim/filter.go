@@ -0,0 +1,387 @@
+// Package scim implements the parts of SCIM 2.0 (RFC 7643, RFC 7644) that
+// are independent of how resources are stored.
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"golang.org/x/xerrors"
+)
+
+// Attributes returns all values of an attribute of a resource. Paths are
+// lowercase, with sub-attributes separated by a dot, like "emails.value".
+// Booleans are returned as "true" or "false".
+type Attributes func(path string) []string
+
+// Filter is a parsed filter expression as described in RFC 7644 section
+// 3.4.2.2.
+type Filter interface {
+	// Matches returns whether the resource with the given attributes
+	// matches the filter.
+	Matches(attrs Attributes) bool
+}
+
+// Equal returns the value an attribute must be equal to for a resource to
+// match the filter. It's only found for equality comparisons that aren't
+// nested in "or" or "not", and lets stores narrow the resources they load
+// before matching the filter. The value is lowercase.
+func Equal(filter Filter, path string) (string, bool) {
+	switch f := filter.(type) {
+	case compareFilter:
+		if f.operator == "eq" && !f.null && f.path == path {
+			return f.value, true
+		}
+	case andFilter:
+		if value, ok := Equal(f.left, path); ok {
+			return value, true
+		}
+		return Equal(f.right, path)
+	}
+	return "", false
+}
+
+// ParseFilter parses a filter expression, like:
+//
+//	userName eq "bob" and (emails.value ew "@coder.com" or active eq false)
+//
+// Attribute names and values are compared case-insensitively. Value path
+// expressions, like `emails[type eq "work"]`, are not supported.
+func ParseFilter(s string) (Filter, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, xerrors.New("filter is empty")
+	}
+
+	p := &parser{tokens: tokens}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, xerrors.Errorf("unexpected %q", p.peek().value)
+	}
+	return filter, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, value: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, value: ")"})
+			i++
+		case c == '[' || c == ']':
+			return nil, xerrors.New("value path filters are not supported")
+		case c == '"':
+			end := i + 1
+			for ; end < len(s); end++ {
+				if s[end] == '\\' {
+					end++
+					continue
+				}
+				if s[end] == '"' {
+					break
+				}
+			}
+			if end >= len(s) {
+				return nil, xerrors.Errorf("unterminated string at position %d", i)
+			}
+			var value string
+			err := json.Unmarshal([]byte(s[i:end+1]), &value)
+			if err != nil {
+				return nil, xerrors.Errorf("invalid string at position %d: %w", i, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value})
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t\r\n()[]\"", rune(s[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: s[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() (token, error) {
+	if p.done() {
+		return token{}, xerrors.New("unexpected end of filter")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+// peekKeyword returns whether the next token is the given keyword.
+func (p *parser) peekKeyword(keyword string) bool {
+	return !p.done() && p.peek().kind == tokenWord && strings.EqualFold(p.peek().value, keyword)
+}
+
+func (p *parser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Filter, error) {
+	negate := p.peekKeyword("not")
+	if negate {
+		p.pos++
+	}
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	var filter Filter
+	switch {
+	case t.kind == tokenOpen:
+		filter, err = p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if closing.kind != tokenClose {
+			return nil, xerrors.Errorf("expected \")\", got %q", closing.value)
+		}
+	case negate:
+		// "not" must be followed by a parenthesized filter.
+		return nil, xerrors.Errorf("expected \"(\" after \"not\", got %q", t.value)
+	case t.kind == tokenWord:
+		filter, err = p.parseComparison(t.value)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, xerrors.Errorf("expected attribute, got %q", t.value)
+	}
+
+	if negate {
+		return notFilter{filter}, nil
+	}
+	return filter, nil
+}
+
+func (p *parser) parseComparison(attr string) (Filter, error) {
+	path := attributePath(attr)
+	if !validAttributePath(path) {
+		return nil, xerrors.Errorf("invalid attribute %q", attr)
+	}
+
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != tokenWord {
+		return nil, xerrors.Errorf("expected operator after %q, got %q", attr, op.value)
+	}
+	operator := strings.ToLower(op.value)
+	if operator == "pr" {
+		return presentFilter{path: path}, nil
+	}
+	if _, ok := comparisons[operator]; !ok {
+		return nil, xerrors.Errorf("unsupported operator %q", op.value)
+	}
+
+	value, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case value.kind == tokenString:
+	case value.kind == tokenWord && isLiteral(value.value):
+		// Booleans, null and numbers are compared by their literal value.
+	default:
+		return nil, xerrors.Errorf("invalid value %q for %q", value.value, attr)
+	}
+	return compareFilter{
+		path:     path,
+		operator: operator,
+		value:    strings.ToLower(value.value),
+		null:     value.kind == tokenWord && strings.EqualFold(value.value, "null"),
+	}, nil
+}
+
+// attributePath returns the normalized path of an attribute. The schema URN
+// attributes may be prefixed with is removed.
+func attributePath(attr string) string {
+	if i := strings.LastIndex(attr, ":"); i >= 0 {
+		attr = attr[i+1:]
+	}
+	return strings.ToLower(attr)
+}
+
+func validAttributePath(path string) bool {
+	if path == "" {
+		return false
+	}
+	for _, r := range path {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '_' && r != '-' && r != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+func isLiteral(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "null":
+		return true
+	}
+	var number json.Number
+	return json.Unmarshal([]byte(s), &number) == nil
+}
+
+var comparisons = map[string]func(attr, value string) bool{
+	"eq": func(attr, value string) bool { return attr == value },
+	"ne": func(attr, value string) bool { return attr != value },
+	"co": strings.Contains,
+	"sw": strings.HasPrefix,
+	"ew": strings.HasSuffix,
+	"gt": func(attr, value string) bool { return attr > value },
+	"ge": func(attr, value string) bool { return attr >= value },
+	"lt": func(attr, value string) bool { return attr < value },
+	"le": func(attr, value string) bool { return attr <= value },
+}
+
+type compareFilter struct {
+	path     string
+	operator string
+	value    string
+	null     bool
+}
+
+func (f compareFilter) Matches(attrs Attributes) bool {
+	values := attrs(f.path)
+	if f.null {
+		switch f.operator {
+		case "eq":
+			return len(values) == 0
+		case "ne":
+			return len(values) > 0
+		}
+	}
+	if f.operator == "ne" {
+		// An attribute that isn't set is not equal to any value.
+		for _, v := range values {
+			if strings.ToLower(v) == f.value {
+				return false
+			}
+		}
+		return true
+	}
+
+	compare := comparisons[f.operator]
+	for _, v := range values {
+		if compare(strings.ToLower(v), f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+type presentFilter struct {
+	path string
+}
+
+func (f presentFilter) Matches(attrs Attributes) bool {
+	for _, v := range attrs(f.path) {
+		if v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+type andFilter struct {
+	left, right Filter
+}
+
+func (f andFilter) Matches(attrs Attributes) bool {
+	return f.left.Matches(attrs) && f.right.Matches(attrs)
+}
+
+type orFilter struct {
+	left, right Filter
+}
+
+func (f orFilter) Matches(attrs Attributes) bool {
+	return f.left.Matches(attrs) || f.right.Matches(attrs)
+}
+
+type notFilter struct {
+	filter Filter
+}
+
+func (f notFilter) Matches(attrs Attributes) bool {
+	return !f.filter.Matches(attrs)
+}
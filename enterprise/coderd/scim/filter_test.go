@@ -0,0 +1,112 @@
+package scim_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	attrs := scim.Attributes(func(path string) []string {
+		switch path {
+		case "username":
+			return []string{"Bob"}
+		case "emails.value":
+			return []string{"bob@coder.com", "bob@example.com"}
+		case "active":
+			return []string{"true"}
+		case "meta.created":
+			return []string{"2024-01-02T03:04:05Z"}
+		}
+		return nil
+	})
+
+	testCases := []struct {
+		Name          string
+		Filter        string
+		Matches       bool
+		ErrorContains string
+	}{
+		{Name: "Equal", Filter: `userName eq "bob"`, Matches: true},
+		{Name: "NotEqual", Filter: `userName ne "bob"`, Matches: false},
+		{Name: "NotEqualUnset", Filter: `externalId ne "bob"`, Matches: true},
+		{Name: "OperatorCase", Filter: `userName EQ "bob"`, Matches: true},
+		{Name: "SchemaURN", Filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "bob"`, Matches: true},
+		{Name: "MultiValued", Filter: `emails.value eq "bob@example.com"`, Matches: true},
+		{Name: "Contains", Filter: `emails.value co "@example"`, Matches: true},
+		{Name: "StartsWith", Filter: `userName sw "bo"`, Matches: true},
+		{Name: "EndsWith", Filter: `userName ew "x"`, Matches: false},
+		{Name: "Present", Filter: `userName pr`, Matches: true},
+		{Name: "NotPresent", Filter: `externalId pr`, Matches: false},
+		{Name: "Boolean", Filter: `active eq true`, Matches: true},
+		{Name: "Null", Filter: `externalId eq null`, Matches: true},
+		{Name: "NotNull", Filter: `userName ne null`, Matches: true},
+		{Name: "GreaterThan", Filter: `meta.created gt "2024-01-01T00:00:00Z"`, Matches: true},
+		{Name: "LessThan", Filter: `meta.created lt "2024-01-01T00:00:00Z"`, Matches: false},
+		{Name: "Escaped", Filter: `userName eq "b\"ob"`, Matches: false},
+		{Name: "And", Filter: `userName eq "bob" and active eq false`, Matches: false},
+		{Name: "Or", Filter: `userName eq "alice" or active eq true`, Matches: true},
+		{Name: "Precedence", Filter: `userName eq "alice" and active eq true or userName eq "bob"`, Matches: true},
+		{Name: "Parentheses", Filter: `userName eq "alice" and (active eq true or userName eq "bob")`, Matches: false},
+		{Name: "Not", Filter: `not (userName eq "alice")`, Matches: true},
+		{Name: "Empty", Filter: ` `, ErrorContains: "empty"},
+		{Name: "UnknownOperator", Filter: `userName is "bob"`, ErrorContains: "unsupported operator"},
+		{Name: "MissingValue", Filter: `userName eq`, ErrorContains: "unexpected end"},
+		{Name: "UnquotedValue", Filter: `userName eq bob`, ErrorContains: "invalid value"},
+		{Name: "Unterminated", Filter: `userName eq "bob`, ErrorContains: "unterminated"},
+		{Name: "UnbalancedParentheses", Filter: `(userName eq "bob"`, ErrorContains: "unexpected end"},
+		{Name: "Trailing", Filter: `userName eq "bob" "alice"`, ErrorContains: "unexpected"},
+		{Name: "NotWithoutParentheses", Filter: `not userName eq "bob"`, ErrorContains: "after \"not\""},
+		{Name: "ValuePath", Filter: `emails[type eq "work"]`, ErrorContains: "not supported"},
+	}
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := scim.ParseFilter(c.Filter)
+			if c.ErrorContains != "" {
+				require.ErrorContains(t, err, c.ErrorContains)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.Matches, filter.Matches(attrs))
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		Name   string
+		Filter string
+		Path   string
+		Value  string
+		Found  bool
+	}{
+		{Name: "Equal", Filter: `userName eq "Bob"`, Path: "username", Value: "bob", Found: true},
+		{Name: "SchemaPrefix", Filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "bob"`, Path: "username", Value: "bob", Found: true},
+		{Name: "OtherAttribute", Filter: `userName eq "bob"`, Path: "externalid"},
+		{Name: "OtherOperator", Filter: `userName co "bob"`, Path: "username"},
+		{Name: "Null", Filter: `userName eq null`, Path: "username"},
+		{Name: "And", Filter: `active eq true and userName eq "bob"`, Path: "username", Value: "bob", Found: true},
+		{Name: "Or", Filter: `userName eq "bob" or userName eq "alice"`, Path: "username"},
+		{Name: "Not", Filter: `not (userName eq "bob")`, Path: "username"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			filter, err := scim.ParseFilter(tc.Filter)
+			require.NoError(t, err)
+			value, found := scim.Equal(filter, tc.Path)
+			require.Equal(t, tc.Found, found)
+			require.Equal(t, tc.Value, value)
+		})
+	}
+}
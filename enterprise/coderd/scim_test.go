@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/coderd"
@@ -340,3 +343,263 @@ func TestScim(t *testing.T) {
 		})
 	})
 }
+
+func newScimClient(t *testing.T) (*codersdk.Client, []byte) {
+	t.Helper()
+
+	scimAPIKey := []byte("hi")
+	client, _ := coderdenttest.New(t, &coderdenttest.Options{
+		SCIMAPIKey: scimAPIKey,
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			AccountID: "coolin",
+			Features: license.Features{
+				codersdk.FeatureSCIM: 1,
+				// Needed to check SCIM groups with the groups API.
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		},
+	})
+	return client, scimAPIKey
+}
+
+// scimRequest makes a SCIM request and decodes the response into res, if
+// it's not nil. The status code of the response is returned.
+func scimRequest(ctx context.Context, t *testing.T, client *codersdk.Client, key []byte, method, path string, body, res any) int {
+	t.Helper()
+
+	resp, err := client.Request(ctx, method, path, body, setScimAuth(key))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if res != nil && resp.StatusCode < http.StatusBadRequest {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+	return resp.StatusCode
+}
+
+type scimUserList struct {
+	TotalResults int               `json:"totalResults"`
+	StartIndex   int               `json:"startIndex"`
+	ItemsPerPage int               `json:"itemsPerPage"`
+	Resources    []coderd.SCIMUser `json:"Resources"`
+}
+
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestScimUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		sUser := makeScimUser(t)
+		status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Users", sUser, &sUser)
+		require.Equal(t, http.StatusOK, status)
+
+		var list scimUserList
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users", nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		// The first user and the SCIM user.
+		require.Equal(t, 2, list.TotalResults)
+		require.Len(t, list.Resources, 2)
+
+		query := url.Values{"filter": {fmt.Sprintf("userName eq %q", sUser.UserName)}}
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users?"+query.Encode(), nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, sUser.ID, list.Resources[0].ID)
+		assert.Equal(t, sUser.Emails[0].Value, list.Resources[0].Emails[0].Value)
+		assert.True(t, list.Resources[0].Active)
+
+		query = url.Values{"filter": {`emails.value ew "@coder.com" and active eq false`}}
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users?"+query.Encode(), nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Empty(t, list.Resources)
+
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users?startIndex=2&count=1", nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 2, list.TotalResults)
+		assert.Equal(t, 2, list.StartIndex)
+		assert.Equal(t, 1, list.ItemsPerPage)
+
+		// Pages past the last user still report the total.
+		list = scimUserList{}
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users?startIndex=5&count=1", nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 2, list.TotalResults)
+		assert.Empty(t, list.Resources)
+
+		list = scimUserList{}
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users?count=0", nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 2, list.TotalResults)
+		assert.Empty(t, list.Resources)
+
+		query = url.Values{"filter": {fmt.Sprintf("externalId eq %q", sUser.ID)}}
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users?"+query.Encode(), nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Empty(t, list.Resources)
+
+		query = url.Values{"filter": {`userName eq`}}
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users?"+query.Encode(), nil, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		sUser := makeScimUser(t)
+		status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Users", sUser, &sUser)
+		require.Equal(t, http.StatusOK, status)
+
+		var got coderd.SCIMUser
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users/"+sUser.ID, nil, &got)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, sUser.ID, got.ID)
+		assert.Equal(t, sUser.UserName, got.UserName)
+		assert.Equal(t, "User", got.Meta.ResourceType)
+		assert.NotNil(t, got.Meta.Created)
+
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users/"+uuid.NewString(), nil, nil)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Put", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		sUser := makeScimUser(t)
+		status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Users", sUser, &sUser)
+		require.Equal(t, http.StatusOK, status)
+
+		sUser.UserName += "-renamed"
+		sUser.Emails[0].Value = "renamed-" + sUser.Emails[0].Value
+		sUser.Active = false
+		var got coderd.SCIMUser
+		status = scimRequest(ctx, t, client, key, "PUT", "/scim/v2/Users/"+sUser.ID, sUser, &got)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, sUser.UserName, got.UserName)
+		assert.False(t, got.Active)
+
+		user, err := client.User(ctx, sUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, sUser.UserName, user.Username)
+		assert.Equal(t, sUser.Emails[0].Value, user.Email)
+		assert.Equal(t, codersdk.UserStatusSuspended, user.Status)
+
+		// Reactivated users are dormant until they log in.
+		sUser.Active = true
+		status = scimRequest(ctx, t, client, key, "PUT", "/scim/v2/Users/"+sUser.ID, sUser, &got)
+		require.Equal(t, http.StatusOK, status)
+		user, err = client.User(ctx, sUser.ID)
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.UserStatusDormant, user.Status)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		sUser := makeScimUser(t)
+		status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Users", sUser, &sUser)
+		require.Equal(t, http.StatusOK, status)
+
+		status = scimRequest(ctx, t, client, key, "DELETE", "/scim/v2/Users/"+sUser.ID, nil, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Users/"+sUser.ID, nil, nil)
+		require.Equal(t, http.StatusNotFound, status)
+		userRes, err := client.Users(ctx, codersdk.UsersRequest{Search: sUser.Emails[0].Value})
+		require.NoError(t, err)
+		require.Empty(t, userRes.Users)
+	})
+
+	t.Run("DeleteWithWorkspaces", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		scimAPIKey := []byte("hi")
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{
+			SCIMAPIKey: scimAPIKey,
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				AccountID: "coolin",
+				Features: license.Features{
+					codersdk.FeatureSCIM: 1,
+				},
+			},
+		})
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+
+		status := scimRequest(ctx, t, client, scimAPIKey, "DELETE", "/scim/v2/Users/"+memberUser.ID.String(), nil, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		// The user is suspended instead, so the workspace keeps its owner.
+		user, err := client.User(ctx, memberUser.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, codersdk.UserStatusSuspended, user.Status)
+	})
+}
+
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestScimDiscovery(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, key := newScimClient(t)
+
+	var config struct {
+		Patch struct {
+			Supported bool `json:"supported"`
+		} `json:"patch"`
+		Filter struct {
+			Supported  bool `json:"supported"`
+			MaxResults int  `json:"maxResults"`
+		} `json:"filter"`
+	}
+	status := scimRequest(ctx, t, client, key, "GET", "/scim/v2/ServiceProviderConfig", nil, &config)
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, config.Patch.Supported)
+	assert.True(t, config.Filter.Supported)
+	assert.Positive(t, config.Filter.MaxResults)
+
+	var schemas struct {
+		TotalResults int `json:"totalResults"`
+		Resources    []struct {
+			ID string `json:"id"`
+		} `json:"Resources"`
+	}
+	status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Schemas", nil, &schemas)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 2, schemas.TotalResults)
+
+	for _, schema := range schemas.Resources {
+		var got struct {
+			ID         string `json:"id"`
+			Attributes []struct {
+				Name string `json:"name"`
+			} `json:"attributes"`
+		}
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Schemas/"+schema.ID, nil, &got)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, schema.ID, got.ID)
+		assert.NotEmpty(t, got.Attributes)
+	}
+
+	status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Schemas/unknown", nil, nil)
+	require.Equal(t, http.StatusNotFound, status)
+}
@@ -0,0 +1,542 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/imulab/go-scim/pkg/v2/handlerutil"
+	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/enterprise/coderd/scim"
+)
+
+// SCIMGroup is a group as described in RFC 7643 section 4.2. The display name
+// of a SCIM group is the name of the Coder group.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+// SCIMPatchRequest is a request to modify a resource, as described in RFC
+// 7644 section 3.5.2.
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op" enums:"add,remove,replace"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// scimGroup converts a database group and its members to its SCIM
+// representation.
+func scimGroup(group database.Group, members []database.User) SCIMGroup {
+	sGroup := SCIMGroup{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          group.ID.String(),
+		DisplayName: group.Name,
+		Members:     make([]SCIMGroupMember, 0, len(members)),
+	}
+	for _, member := range members {
+		sGroup.Members = append(sGroup.Members, SCIMGroupMember{
+			Value:   member.ID.String(),
+			Display: member.Username,
+		})
+	}
+	sGroup.Meta.ResourceType = "Group"
+	return sGroup
+}
+
+// scimGroupAttributes returns the attributes of a group that can be filtered
+// by.
+func scimGroupAttributes(sGroup SCIMGroup) scim.Attributes {
+	return func(path string) []string {
+		switch path {
+		case "id":
+			return []string{sGroup.ID}
+		case "displayname":
+			return []string{sGroup.DisplayName}
+		case "members", "members.value":
+			return scimMemberValues(sGroup.Members, func(m SCIMGroupMember) string { return m.Value })
+		case "members.display":
+			return scimMemberValues(sGroup.Members, func(m SCIMGroupMember) string { return m.Display })
+		}
+		return nil
+	}
+}
+
+func scimMemberValues(members []SCIMGroupMember, value func(SCIMGroupMember) string) []string {
+	values := make([]string, 0, len(members))
+	for _, member := range members {
+		values = append(values, value(member))
+	}
+	return values
+}
+
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param filter query string false "Filter expression"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
+// @Success 200
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	filter, ok := scimParseFilter(rw, r)
+	if !ok {
+		return
+	}
+
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	groups, err := api.Database.GetGroupsByOrganizationID(dbauthz.AsSystemRestricted(ctx), organizationID)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+	slices.SortFunc(groups, func(a, b database.Group) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	sGroups := []SCIMGroup{}
+	for _, group := range groups {
+		// Membership of the Everyone group is managed by Coder.
+		if group.IsEveryone() {
+			continue
+		}
+		//nolint:gocritic // needed for SCIM
+		members, err := api.Database.GetGroupMembers(dbauthz.AsSystemRestricted(ctx), group.ID)
+		if err != nil {
+			scimWriteError(rw, err)
+			return
+		}
+		sGroup := scimGroup(group, members)
+		if filter != nil && !filter.Matches(scimGroupAttributes(sGroup)) {
+			continue
+		}
+		sGroups = append(sGroups, sGroup)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimPage(r, sGroups))
+}
+
+// scimGroupParam returns the group with the ID in the URL. It writes a not
+// found error if the group doesn't exist.
+func (api *API) scimGroupParam(rw http.ResponseWriter, r *http.Request) (database.Group, bool) {
+	id := chi.URLParam(r, "id")
+	gid, err := uuid.Parse(id)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("group %q not found: %w", id, spec.ErrNotFound))
+		return database.Group{}, false
+	}
+
+	//nolint:gocritic // needed for SCIM
+	group, err := api.Database.GetGroupByID(dbauthz.AsSystemRestricted(r.Context()), gid)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && group.IsEveryone()) {
+		scimWriteError(rw, xerrors.Errorf("group %q not found: %w", id, spec.ErrNotFound))
+		return database.Group{}, false
+	}
+	if err != nil {
+		scimWriteError(rw, err)
+		return database.Group{}, false
+	}
+	return group, true
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Failure 404
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	group, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	members, err := api.Database.GetGroupMembers(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, scimGroup(group, members))
+}
+
+// @Summary SCIM 2.0: Create group
+// @ID scim-create-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("decode group: %s: %w", err.Error(), spec.ErrInvalidSyntax))
+		return
+	}
+	if sGroup.DisplayName == "" || sGroup.DisplayName == database.EveryoneGroup {
+		scimWriteError(rw, xerrors.Errorf("invalid displayName %q: %w", sGroup.DisplayName, spec.ErrInvalidValue))
+		return
+	}
+
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+	if organizationID == uuid.Nil {
+		scimWriteError(rw, xerrors.Errorf("no organization to create the group in: %w", spec.ErrInternal))
+		return
+	}
+
+	members := make([]uuid.UUID, 0, len(sGroup.Members))
+	for _, member := range sGroup.Members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			scimWriteError(rw, xerrors.Errorf("invalid member %q: %w", member.Value, spec.ErrInvalidValue))
+			return
+		}
+		members = append(members, id)
+	}
+
+	var group database.Group
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		//nolint:gocritic // needed for SCIM
+		group, err = tx.InsertGroup(dbauthz.AsSystemRestricted(ctx), database.InsertGroupParams{
+			ID:             uuid.New(),
+			Name:           sGroup.DisplayName,
+			OrganizationID: organizationID,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert group: %w", err)
+		}
+		return scimUpdateGroupMembers(ctx, tx, group, nil, members)
+	}, nil)
+	if database.IsUniqueViolation(err) {
+		scimWriteError(rw, xerrors.Errorf("group %q already exists: %w", sGroup.DisplayName, spec.ErrUniqueness))
+		return
+	}
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	api.scimWriteGroup(rw, r, http.StatusCreated, group)
+}
+
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMPatchRequest true "Patch group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	var req SCIMPatchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		scimWriteError(rw, xerrors.Errorf("decode patch request: %s: %w", err.Error(), spec.ErrInvalidSyntax))
+		return
+	}
+
+	group, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		//nolint:gocritic // needed for SCIM
+		currentMembers, err := tx.GetGroupMembers(dbauthz.AsSystemRestricted(ctx), group.ID)
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+
+		patched := scimGroup(group, currentMembers)
+		for _, op := range req.Operations {
+			err = scimApplyGroupPatch(&patched, op)
+			if err != nil {
+				return err
+			}
+		}
+
+		if patched.DisplayName != group.Name {
+			//nolint:gocritic // needed for SCIM
+			group, err = tx.UpdateGroupByID(dbauthz.AsSystemRestricted(ctx), database.UpdateGroupByIDParams{
+				ID:             group.ID,
+				Name:           patched.DisplayName,
+				DisplayName:    group.DisplayName,
+				AvatarURL:      group.AvatarURL,
+				QuotaAllowance: group.QuotaAllowance,
+			})
+			if err != nil {
+				return xerrors.Errorf("update group: %w", err)
+			}
+		}
+
+		current := make([]uuid.UUID, 0, len(currentMembers))
+		for _, member := range currentMembers {
+			current = append(current, member.ID)
+		}
+		members := make([]uuid.UUID, 0, len(patched.Members))
+		for _, member := range patched.Members {
+			id, err := uuid.Parse(member.Value)
+			if err != nil {
+				return xerrors.Errorf("invalid member %q: %w", member.Value, spec.ErrInvalidValue)
+			}
+			members = append(members, id)
+		}
+		return scimUpdateGroupMembers(ctx, tx, group, current, members)
+	}, nil)
+	if database.IsUniqueViolation(err) {
+		scimWriteError(rw, xerrors.Errorf("a group with this displayName already exists: %w", spec.ErrUniqueness))
+		return
+	}
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	api.scimWriteGroup(rw, r, http.StatusOK, group)
+}
+
+// scimApplyGroupPatch applies a patch operation to a group. Only the
+// displayName and the members of a group can be modified.
+func scimApplyGroupPatch(sGroup *SCIMGroup, op SCIMPatchOperation) error {
+	operation := strings.ToLower(op.Op)
+	if operation != "add" && operation != "remove" && operation != "replace" {
+		return xerrors.Errorf("unsupported operation %q: %w", op.Op, spec.ErrInvalidSyntax)
+	}
+
+	path := strings.ToLower(op.Path)
+	switch {
+	case path == "":
+		// Without a path, the value contains the attributes to modify.
+		if operation == "remove" {
+			return xerrors.Errorf("remove requires a path: %w", spec.ErrNoTarget)
+		}
+		var value struct {
+			DisplayName *string           `json:"displayName"`
+			Members     []SCIMGroupMember `json:"members"`
+		}
+		err := json.Unmarshal(op.Value, &value)
+		if err != nil {
+			return xerrors.Errorf("decode value: %w", spec.ErrInvalidValue)
+		}
+		if value.DisplayName != nil {
+			sGroup.DisplayName = *value.DisplayName
+		}
+		if value.Members != nil {
+			sGroup.Members = scimPatchMembers(sGroup.Members, operation, value.Members)
+		}
+	case path == "displayname":
+		if operation == "remove" {
+			return xerrors.Errorf("displayName is required: %w", spec.ErrMutability)
+		}
+		err := json.Unmarshal(op.Value, &sGroup.DisplayName)
+		if err != nil {
+			return xerrors.Errorf("decode displayName: %w", spec.ErrInvalidValue)
+		}
+	case path == "members":
+		var members []SCIMGroupMember
+		if len(op.Value) > 0 {
+			err := json.Unmarshal(op.Value, &members)
+			if err != nil {
+				return xerrors.Errorf("decode members: %w", spec.ErrInvalidValue)
+			}
+		}
+		if operation == "remove" && len(members) == 0 {
+			// Removing the attribute removes all members.
+			operation = "replace"
+		}
+		sGroup.Members = scimPatchMembers(sGroup.Members, operation, members)
+	case strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]"):
+		// A value path selects members by a filter, like
+		// members[value eq "<id>"].
+		if operation != "remove" {
+			return xerrors.Errorf("only remove is supported for %q: %w", op.Path, spec.ErrInvalidPath)
+		}
+		filter, err := scim.ParseFilter(op.Path[len("members[") : len(op.Path)-1])
+		if err != nil {
+			return xerrors.Errorf("parse path %q: %s: %w", op.Path, err.Error(), spec.ErrInvalidPath)
+		}
+		sGroup.Members = slices.DeleteFunc(sGroup.Members, func(member SCIMGroupMember) bool {
+			return filter.Matches(func(path string) []string {
+				switch path {
+				case "value":
+					return []string{member.Value}
+				case "display":
+					return []string{member.Display}
+				}
+				return nil
+			})
+		})
+	default:
+		return xerrors.Errorf("unsupported path %q: %w", op.Path, spec.ErrInvalidPath)
+	}
+	return nil
+}
+
+// scimPatchMembers adds, removes or replaces the members of a group.
+func scimPatchMembers(members []SCIMGroupMember, operation string, changes []SCIMGroupMember) []SCIMGroupMember {
+	switch operation {
+	case "replace":
+		return changes
+	case "remove":
+		return slices.DeleteFunc(members, func(member SCIMGroupMember) bool {
+			return slices.ContainsFunc(changes, func(change SCIMGroupMember) bool {
+				return strings.EqualFold(change.Value, member.Value)
+			})
+		})
+	default:
+		return append(members, changes...)
+	}
+}
+
+// scimUpdateGroupMembers changes the members of a group from current to
+// members. Members must be part of the organization of the group.
+func scimUpdateGroupMembers(ctx context.Context, tx database.Store, group database.Group, current, members []uuid.UUID) error {
+	//nolint:gocritic // needed for SCIM
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	for _, id := range members {
+		if slices.Contains(current, id) {
+			continue
+		}
+		_, err := tx.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: group.OrganizationID,
+			UserID:         id,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("user %q is not a member of the organization: %w", id, spec.ErrInvalidValue)
+		}
+		if err != nil {
+			return xerrors.Errorf("get organization member: %w", err)
+		}
+		err = tx.InsertGroupMember(ctx, database.InsertGroupMemberParams{
+			GroupID: group.ID,
+			UserID:  id,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert group member %q: %w", id, err)
+		}
+		current = append(current, id)
+	}
+	for _, id := range current {
+		if slices.Contains(members, id) {
+			continue
+		}
+		err := tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+			GroupID: group.ID,
+			UserID:  id,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete group member %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (api *API) scimWriteGroup(rw http.ResponseWriter, r *http.Request, status int, group database.Group) {
+	//nolint:gocritic // needed for SCIM
+	members, err := api.Database.GetGroupMembers(dbauthz.AsSystemRestricted(r.Context()), group.ID)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(r.Context(), rw, status, scimGroup(group, members))
+}
+
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	group, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	err := api.Database.DeleteGroupByID(dbauthz.AsSystemRestricted(r.Context()), group.ID)
+	if err != nil {
+		scimWriteError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,224 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/coderd"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type scimGroupList struct {
+	TotalResults int                `json:"totalResults"`
+	Resources    []coderd.SCIMGroup `json:"Resources"`
+}
+
+func scimGroupMemberIDs(group coderd.SCIMGroup) []string {
+	ids := make([]string, 0, len(group.Members))
+	for _, member := range group.Members {
+		ids = append(ids, member.Value)
+	}
+	return ids
+}
+
+func scimPatch(ops ...coderd.SCIMPatchOperation) coderd.SCIMPatchRequest {
+	return coderd.SCIMPatchRequest{
+		Schemas:    []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+		Operations: ops,
+	}
+}
+
+//nolint:gocritic // SCIM authenticates via a special header and bypasses internal RBAC.
+func TestScimGroups(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		sUser := makeScimUser(t)
+		status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Users", sUser, &sUser)
+		require.Equal(t, http.StatusOK, status)
+
+		var sGroup coderd.SCIMGroup
+		status = scimRequest(ctx, t, client, key, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "developers",
+			Members:     []coderd.SCIMGroupMember{{Value: sUser.ID}},
+		}, &sGroup)
+		require.Equal(t, http.StatusCreated, status)
+		require.Equal(t, "developers", sGroup.DisplayName)
+		require.Equal(t, []string{sUser.ID}, scimGroupMemberIDs(sGroup))
+		require.Equal(t, sUser.UserName, sGroup.Members[0].Display)
+
+		// The group is a regular Coder group.
+		group, err := client.Group(ctx, uuid.MustParse(sGroup.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "developers", group.Name)
+		require.Len(t, group.Members, 1)
+		assert.Equal(t, sUser.ID, group.Members[0].ID.String())
+
+		var got coderd.SCIMGroup
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Groups/"+sGroup.ID, nil, &got)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, sGroup, got)
+
+		// Group names are unique.
+		status = scimRequest(ctx, t, client, key, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "developers",
+		}, nil)
+		require.Equal(t, http.StatusConflict, status)
+
+		// Members must exist.
+		status = scimRequest(ctx, t, client, key, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "testers",
+			Members:     []coderd.SCIMGroupMember{{Value: uuid.NewString()}},
+		}, nil)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		for _, name := range []string{"developers", "testers"} {
+			status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: name}, nil)
+			require.Equal(t, http.StatusCreated, status)
+		}
+
+		// The Everyone group is not listed.
+		var list scimGroupList
+		status := scimRequest(ctx, t, client, key, "GET", "/scim/v2/Groups", nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, 2, list.TotalResults)
+		assert.Equal(t, "developers", list.Resources[0].DisplayName)
+		assert.Equal(t, "testers", list.Resources[1].DisplayName)
+
+		query := url.Values{"filter": {`displayName eq "Testers"`}}
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Groups?"+query.Encode(), nil, &list)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, "testers", list.Resources[0].DisplayName)
+	})
+
+	t.Run("Patch", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		users := make([]coderd.SCIMUser, 3)
+		for i := range users {
+			users[i] = makeScimUser(t)
+			status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Users", users[i], &users[i])
+			require.Equal(t, http.StatusOK, status)
+		}
+
+		var sGroup coderd.SCIMGroup
+		status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+			DisplayName: "developers",
+			Members:     []coderd.SCIMGroupMember{{Value: users[0].ID}},
+		}, &sGroup)
+		require.Equal(t, http.StatusCreated, status)
+		path := "/scim/v2/Groups/" + sGroup.ID
+
+		// Adding an existing member is a no-op.
+		status = scimRequest(ctx, t, client, key, "PATCH", path, scimPatch(coderd.SCIMPatchOperation{
+			Op:    "Add",
+			Path:  "members",
+			Value: json.RawMessage(fmt.Sprintf(`[{"value":%q},{"value":%q}]`, users[0].ID, users[1].ID)),
+		}), &sGroup)
+		require.Equal(t, http.StatusOK, status)
+		assert.ElementsMatch(t, []string{users[0].ID, users[1].ID}, scimGroupMemberIDs(sGroup))
+
+		status = scimRequest(ctx, t, client, key, "PATCH", path, scimPatch(coderd.SCIMPatchOperation{
+			Op:   "remove",
+			Path: fmt.Sprintf(`members[value eq %q]`, users[0].ID),
+		}), &sGroup)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{users[1].ID}, scimGroupMemberIDs(sGroup))
+
+		// Without a path, the value holds the attributes to replace.
+		status = scimRequest(ctx, t, client, key, "PATCH", path, scimPatch(coderd.SCIMPatchOperation{
+			Op:    "replace",
+			Value: json.RawMessage(fmt.Sprintf(`{"displayName":"engineers","members":[{"value":%q}]}`, users[2].ID)),
+		}), &sGroup)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "engineers", sGroup.DisplayName)
+		assert.Equal(t, []string{users[2].ID}, scimGroupMemberIDs(sGroup))
+
+		status = scimRequest(ctx, t, client, key, "PATCH", path, scimPatch(coderd.SCIMPatchOperation{
+			Op:    "replace",
+			Path:  "displayName",
+			Value: json.RawMessage(`"platform"`),
+		}, coderd.SCIMPatchOperation{
+			Op:   "remove",
+			Path: "members",
+		}), &sGroup)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "platform", sGroup.DisplayName)
+		assert.Empty(t, sGroup.Members)
+
+		group, err := client.Group(ctx, uuid.MustParse(sGroup.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "platform", group.Name)
+		assert.Empty(t, group.Members)
+
+		// Unsupported attributes are rejected, and nothing is changed.
+		status = scimRequest(ctx, t, client, key, "PATCH", path, scimPatch(coderd.SCIMPatchOperation{
+			Op:    "replace",
+			Path:  "displayName",
+			Value: json.RawMessage(`"ignored"`),
+		}, coderd.SCIMPatchOperation{
+			Op:    "replace",
+			Path:  "owner",
+			Value: json.RawMessage(`"alice"`),
+		}), nil)
+		require.Equal(t, http.StatusBadRequest, status)
+		group, err = client.Group(ctx, uuid.MustParse(sGroup.ID))
+		require.NoError(t, err)
+		assert.Equal(t, "platform", group.Name)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		var sGroup coderd.SCIMGroup
+		status := scimRequest(ctx, t, client, key, "POST", "/scim/v2/Groups", coderd.SCIMGroup{DisplayName: "developers"}, &sGroup)
+		require.Equal(t, http.StatusCreated, status)
+
+		status = scimRequest(ctx, t, client, key, "DELETE", "/scim/v2/Groups/"+sGroup.ID, nil, nil)
+		require.Equal(t, http.StatusNoContent, status)
+
+		status = scimRequest(ctx, t, client, key, "GET", "/scim/v2/Groups/"+sGroup.ID, nil, nil)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Everyone", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, key := newScimClient(t)
+
+		user, err := client.User(ctx, "me")
+		require.NoError(t, err)
+
+		// The Everyone group has the ID of the organization, and is managed
+		// by Coder.
+		status := scimRequest(ctx, t, client, key, "DELETE", "/scim/v2/Groups/"+user.OrganizationIDs[0].String(), nil, nil)
+		require.Equal(t, http.StatusNotFound, status)
+	})
+}
@@ -879,6 +879,7 @@ func (api *API) reconnectingPTYSignedToken(rw http.ResponseWriter, r *http.Reque
 			AccessMethod:  workspaceapps.AccessMethodTerminal,
 			BasePath:      u.Path,
 			AgentNameOrID: req.AgentID.String(),
+			TerminalMode:  req.Mode,
 		},
 		SessionToken: httpmw.APITokenFromRequest(r),
 		// The following fields aren't required as long as the request is authed
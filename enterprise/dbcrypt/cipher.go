@@ -1,12 +1,15 @@
 package dbcrypt
 
 import (
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"strings"
 
 	"golang.org/x/xerrors"
 )
@@ -43,6 +46,61 @@ func NewCiphers(keys ...[]byte) ([]Cipher, error) {
 	return cs, nil
 }
 
+// ParseCiphers creates a cipher for each key. A key is one of:
+//
+//   - A base64-encoded 32-byte AES-256 key.
+//   - "vault-transit:<mount>/<name>" for an envelope cipher with a key of the
+//     Vault Transit secrets engine. The address, token and namespace of Vault
+//     are read from the VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE
+//     environment variables with getenv.
+//   - "file:<path>" for an envelope cipher with the key in a local file. See
+//     NewFileKeyProvider.
+func ParseCiphers(ctx context.Context, getenv func(string) string, keys ...string) ([]Cipher, error) {
+	var cs []Cipher
+	for idx, key := range keys {
+		var (
+			c   Cipher
+			err error
+		)
+		switch {
+		case strings.HasPrefix(key, "vault-transit:"):
+			path := strings.TrimPrefix(key, "vault-transit:")
+			mount, name := "", path
+			if i := strings.LastIndex(path, "/"); i >= 0 {
+				mount, name = path[:i], path[i+1:]
+			}
+			if name == "" {
+				return nil, xerrors.Errorf("key %d: vault transit key name is empty", idx)
+			}
+			c, err = NewEnvelopeCipher(ctx, &VaultTransit{
+				Address:   getenv("VAULT_ADDR"),
+				Token:     getenv("VAULT_TOKEN"),
+				Namespace: getenv("VAULT_NAMESPACE"),
+				Mount:     mount,
+				Key:       name,
+			})
+		case strings.HasPrefix(key, "file:"):
+			var provider KeyProvider
+			provider, err = NewFileKeyProvider(strings.TrimPrefix(key, "file:"))
+			if err == nil {
+				c, err = NewEnvelopeCipher(ctx, provider)
+			}
+		default:
+			var raw []byte
+			raw, err = base64.StdEncoding.DecodeString(key)
+			if err != nil {
+				return nil, xerrors.Errorf("key %d must be base64-encoded or a key provider URL: %w", idx, err)
+			}
+			c, err = cipherAES256(raw)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("key %d: %w", idx, err)
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
 // cipherAES256 returns a new AES-256 cipher.
 func cipherAES256(key []byte) (*aes256, error) {
 	if len(key) != 32 {
@@ -10,8 +10,10 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 )
 
-// Rotate rotates the database encryption keys by re-encrypting all user tokens
-// with the first cipher and revoking all other ciphers.
+// Rotate rotates the database encryption keys by re-encrypting all user tokens,
+// workspace build state, sensitive template variables, session recordings and
+// template git source webhook secrets with the first cipher and revoking all
+// other ciphers.
 func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
 	cryptDB, err := New(ctx, db, ciphers...)
@@ -82,6 +84,22 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 		log.Debug(ctx, "encrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	skip := func(keyID sql.NullString) bool {
+		return keyID.String == ciphers[0].HexDigest()
+	}
+	if err := updateWorkspaceBuildStates(ctx, log, db, cryptDB, skip); err != nil {
+		return err
+	}
+	if err := updateSensitiveTemplateVersionVariables(ctx, log, cryptDB, skip); err != nil {
+		return err
+	}
+	if err := updateWorkspaceSessionRecordings(ctx, log, db, cryptDB, skip); err != nil {
+		return err
+	}
+	if err := updateTemplateGitSourceWebhookSecrets(ctx, log, cryptDB, skip); err != nil {
+		return err
+	}
+
 	// Revoke old keys
 	for _, c := range ciphers[1:] {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -93,7 +111,9 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 	return nil
 }
 
-// Decrypt decrypts all user tokens and revokes all ciphers.
+// Decrypt decrypts all user tokens, workspace build state, sensitive template
+// variables, session recordings and template git source webhook secrets and
+// revokes all ciphers.
 func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
 	cdb, err := New(ctx, db, ciphers...)
@@ -172,6 +192,22 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 		log.Debug(ctx, "decrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	skip := func(keyID sql.NullString) bool {
+		return !keyID.Valid
+	}
+	if err := updateWorkspaceBuildStates(ctx, log, db, cryptDB, skip); err != nil {
+		return err
+	}
+	if err := updateSensitiveTemplateVersionVariables(ctx, log, cryptDB, skip); err != nil {
+		return err
+	}
+	if err := updateWorkspaceSessionRecordings(ctx, log, db, cryptDB, skip); err != nil {
+		return err
+	}
+	if err := updateTemplateGitSourceWebhookSecrets(ctx, log, cryptDB, skip); err != nil {
+		return err
+	}
+
 	// Revoke _all_ keys
 	for _, c := range ciphers {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -183,8 +219,145 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 	return nil
 }
 
+// updateWorkspaceBuildStates writes the provisioner state of all workspace
+// builds back through cryptDB, which encrypts it with its primary cipher, if
+// any. Builds for which skip returns true are left as-is.
+func updateWorkspaceBuildStates(ctx context.Context, log slog.Logger, db database.Store, cryptDB database.Store, skip func(keyID sql.NullString) bool) error {
+	buildIDs, err := db.GetWorkspaceBuildIDsWithProvisionerState(ctx)
+	if err != nil {
+		return xerrors.Errorf("get workspace builds: %w", err)
+	}
+	log.Info(ctx, "updating workspace build state", slog.F("build_count", len(buildIDs)))
+	for idx, id := range buildIDs {
+		err := cryptDB.InTx(func(cryptTx database.Store) error {
+			build, err := cryptTx.GetWorkspaceBuildByID(ctx, id)
+			if err != nil {
+				return xerrors.Errorf("get workspace build: %w", err)
+			}
+			if skip(build.ProvisionerStateKeyID) {
+				log.Debug(ctx, "skipping workspace build", slog.F("build_id", id), slog.F("current", idx+1))
+				return nil
+			}
+			if err := cryptTx.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
+				ID:                    id,
+				ProvisionerState:      build.ProvisionerState,
+				ProvisionerStateKeyID: sql.NullString{}, // dbcrypt will update as required
+				UpdatedAt:             build.UpdatedAt,
+			}); err != nil {
+				return xerrors.Errorf("update workspace build state: %w", err)
+			}
+			return nil
+		}, &sql.TxOptions{
+			Isolation: sql.LevelRepeatableRead,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace build build_id=%s: %w", id, err)
+		}
+		log.Debug(ctx, "updated workspace build state", slog.F("build_id", id), slog.F("current", idx+1))
+	}
+	return nil
+}
+
+// updateSensitiveTemplateVersionVariables writes the values of all sensitive
+// template variables back through cryptDB, which encrypts them with its
+// primary cipher, if any. Variables for which skip returns true are left
+// as-is.
+func updateSensitiveTemplateVersionVariables(ctx context.Context, log slog.Logger, cryptDB database.Store, skip func(keyID sql.NullString) bool) error {
+	return cryptDB.InTx(func(cryptTx database.Store) error {
+		variables, err := cryptTx.GetSensitiveTemplateVersionVariables(ctx)
+		if err != nil {
+			return xerrors.Errorf("get sensitive template version variables: %w", err)
+		}
+		log.Info(ctx, "updating sensitive template variables", slog.F("variable_count", len(variables)))
+		for _, variable := range variables {
+			if skip(variable.ValueKeyID) {
+				log.Debug(ctx, "skipping template variable", slog.F("template_version_id", variable.TemplateVersionID), slog.F("name", variable.Name))
+				continue
+			}
+			if err := cryptTx.UpdateTemplateVersionVariableValue(ctx, database.UpdateTemplateVersionVariableValueParams{
+				TemplateVersionID: variable.TemplateVersionID,
+				Name:              variable.Name,
+				Value:             variable.Value,
+				ValueKeyID:        sql.NullString{}, // dbcrypt will update as required
+			}); err != nil {
+				return xerrors.Errorf("update template variable template_version_id=%s name=%s: %w", variable.TemplateVersionID, variable.Name, err)
+			}
+		}
+		return nil
+	}, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+}
+
+// updateWorkspaceSessionRecordings writes the data of all session recordings
+// back through cryptDB, which encrypts it with its primary cipher, if any.
+// Recordings for which skip returns true are left as-is.
+func updateWorkspaceSessionRecordings(ctx context.Context, log slog.Logger, db database.Store, cryptDB database.Store, skip func(keyID sql.NullString) bool) error {
+	recordingIDs, err := db.GetWorkspaceSessionRecordingIDs(ctx)
+	if err != nil {
+		return xerrors.Errorf("get workspace session recordings: %w", err)
+	}
+	log.Info(ctx, "updating workspace session recordings", slog.F("recording_count", len(recordingIDs)))
+	for idx, id := range recordingIDs {
+		err := cryptDB.InTx(func(cryptTx database.Store) error {
+			recording, err := cryptTx.GetWorkspaceSessionRecordingByID(ctx, id)
+			if err != nil {
+				return xerrors.Errorf("get workspace session recording: %w", err)
+			}
+			if skip(recording.DataKeyID) {
+				log.Debug(ctx, "skipping workspace session recording", slog.F("recording_id", id), slog.F("current", idx+1))
+				return nil
+			}
+			if err := cryptTx.UpdateWorkspaceSessionRecordingDataByID(ctx, database.UpdateWorkspaceSessionRecordingDataByIDParams{
+				ID:        id,
+				Data:      recording.Data,
+				DataKeyID: sql.NullString{}, // dbcrypt will update as required
+			}); err != nil {
+				return xerrors.Errorf("update workspace session recording data: %w", err)
+			}
+			return nil
+		}, &sql.TxOptions{
+			Isolation: sql.LevelRepeatableRead,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace session recording recording_id=%s: %w", id, err)
+		}
+		log.Debug(ctx, "updated workspace session recording", slog.F("recording_id", id), slog.F("current", idx+1))
+	}
+	return nil
+}
+
+// updateTemplateGitSourceWebhookSecrets writes the webhook secrets of all
+// template git sources back through cryptDB, which encrypts them with its
+// primary cipher, if any. Sources for which skip returns true are left as-is.
+func updateTemplateGitSourceWebhookSecrets(ctx context.Context, log slog.Logger, cryptDB database.Store, skip func(keyID sql.NullString) bool) error {
+	return cryptDB.InTx(func(cryptTx database.Store) error {
+		sources, err := cryptTx.GetTemplateGitSources(ctx)
+		if err != nil {
+			return xerrors.Errorf("get template git sources: %w", err)
+		}
+		log.Info(ctx, "updating template git source webhook secrets", slog.F("source_count", len(sources)))
+		for _, source := range sources {
+			if skip(source.WebhookSecretKeyID) {
+				log.Debug(ctx, "skipping template git source", slog.F("template_id", source.TemplateID))
+				continue
+			}
+			if err := cryptTx.UpdateTemplateGitSourceWebhookSecret(ctx, database.UpdateTemplateGitSourceWebhookSecretParams{
+				TemplateID:         source.TemplateID,
+				WebhookSecret:      source.WebhookSecret,
+				WebhookSecretKeyID: sql.NullString{}, // dbcrypt will update as required
+			}); err != nil {
+				return xerrors.Errorf("update template git source template_id=%s: %w", source.TemplateID, err)
+			}
+		}
+		return nil
+	}, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+}
+
 // nolint: gosec
-const sqlDeleteEncryptedUserTokens = `
+const sqlDeleteEncryptedData = `
 BEGIN;
 DELETE FROM user_links
   WHERE oauth_access_token_key_id IS NOT NULL
@@ -192,20 +365,33 @@ DELETE FROM user_links
 DELETE FROM external_auth_links
 	WHERE oauth_access_token_key_id IS NOT NULL
 	OR oauth_refresh_token_key_id IS NOT NULL;
+UPDATE workspace_builds
+	SET provisioner_state = NULL, provisioner_state_key_id = NULL
+	WHERE provisioner_state_key_id IS NOT NULL;
+UPDATE template_version_variables
+	SET value = '', value_key_id = NULL
+	WHERE value_key_id IS NOT NULL;
+DELETE FROM workspace_session_recordings
+	WHERE data_key_id IS NOT NULL;
+UPDATE template_git_sources
+	SET webhook_secret = '', webhook_secret_key_id = NULL
+	WHERE webhook_secret_key_id IS NOT NULL;
 COMMIT;
 `
 
-// Delete deletes all user tokens and revokes all ciphers.
+// Delete deletes all user tokens, workspace build state, sensitive template
+// variable values, session recordings and template git source webhook secrets
+// that are encrypted, and revokes all ciphers.
 // This is a destructive operation and should only be used
 // as a last resort, for example, if the database encryption key has been
 // lost.
 func Delete(ctx context.Context, log slog.Logger, sqlDB *sql.DB) error {
 	store := database.New(sqlDB)
-	_, err := sqlDB.ExecContext(ctx, sqlDeleteEncryptedUserTokens)
+	_, err := sqlDB.ExecContext(ctx, sqlDeleteEncryptedData)
 	if err != nil {
-		return xerrors.Errorf("delete user links: %w", err)
+		return xerrors.Errorf("delete encrypted data: %w", err)
 	}
-	log.Info(ctx, "deleted encrypted user tokens")
+	log.Info(ctx, "deleted encrypted data")
 
 	log.Info(ctx, "revoking all active keys")
 	keys, err := store.GetDBCryptKeys(ctx)
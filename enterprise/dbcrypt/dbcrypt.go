@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"time"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -261,6 +262,250 @@ func (db *dbCrypt) UpdateExternalAuthLink(ctx context.Context, params database.U
 	return link, nil
 }
 
+func (db *dbCrypt) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspaceBuild, error) {
+	builds, err := db.Store.GetActiveWorkspaceBuildsByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range builds {
+		if err := db.decryptBytes(&builds[idx].ProvisionerState, builds[idx].ProvisionerStateKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return builds, nil
+}
+
+func (db *dbCrypt) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	build, err := db.Store.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	if err := db.decryptBytes(&build.ProvisionerState, build.ProvisionerStateKeyID); err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	return build, nil
+}
+
+func (db *dbCrypt) GetLatestWorkspaceBuilds(ctx context.Context) ([]database.WorkspaceBuild, error) {
+	builds, err := db.Store.GetLatestWorkspaceBuilds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range builds {
+		if err := db.decryptBytes(&builds[idx].ProvisionerState, builds[idx].ProvisionerStateKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return builds, nil
+}
+
+func (db *dbCrypt) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceBuild, error) {
+	builds, err := db.Store.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range builds {
+		if err := db.decryptBytes(&builds[idx].ProvisionerState, builds[idx].ProvisionerStateKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return builds, nil
+}
+
+func (db *dbCrypt) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
+	build, err := db.Store.GetWorkspaceBuildByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	if err := db.decryptBytes(&build.ProvisionerState, build.ProvisionerStateKeyID); err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	return build, nil
+}
+
+func (db *dbCrypt) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (database.WorkspaceBuild, error) {
+	build, err := db.Store.GetWorkspaceBuildByJobID(ctx, jobID)
+	if err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	if err := db.decryptBytes(&build.ProvisionerState, build.ProvisionerStateKeyID); err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	return build, nil
+}
+
+func (db *dbCrypt) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (database.WorkspaceBuild, error) {
+	build, err := db.Store.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg)
+	if err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	if err := db.decryptBytes(&build.ProvisionerState, build.ProvisionerStateKeyID); err != nil {
+		return database.WorkspaceBuild{}, err
+	}
+	return build, nil
+}
+
+func (db *dbCrypt) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
+	builds, err := db.Store.GetWorkspaceBuildsByWorkspaceID(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range builds {
+		if err := db.decryptBytes(&builds[idx].ProvisionerState, builds[idx].ProvisionerStateKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return builds, nil
+}
+
+func (db *dbCrypt) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceBuild, error) {
+	builds, err := db.Store.GetWorkspaceBuildsCreatedAfter(ctx, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range builds {
+		if err := db.decryptBytes(&builds[idx].ProvisionerState, builds[idx].ProvisionerStateKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return builds, nil
+}
+
+func (db *dbCrypt) InsertWorkspaceBuild(ctx context.Context, params database.InsertWorkspaceBuildParams) error {
+	if err := db.encryptBytes(&params.ProvisionerState, &params.ProvisionerStateKeyID); err != nil {
+		return err
+	}
+	return db.Store.InsertWorkspaceBuild(ctx, params)
+}
+
+func (db *dbCrypt) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, params database.UpdateWorkspaceBuildProvisionerStateByIDParams) error {
+	if err := db.encryptBytes(&params.ProvisionerState, &params.ProvisionerStateKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateWorkspaceBuildProvisionerStateByID(ctx, params)
+}
+
+func (db *dbCrypt) GetWorkspaceSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSessionRecording, error) {
+	recording, err := db.Store.GetWorkspaceSessionRecordingByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	if err := db.decryptBytes(&recording.Data, recording.DataKeyID); err != nil {
+		return database.WorkspaceSessionRecording{}, err
+	}
+	return recording, nil
+}
+
+func (db *dbCrypt) InsertWorkspaceSessionRecording(ctx context.Context, params database.InsertWorkspaceSessionRecordingParams) error {
+	if err := db.encryptBytes(&params.Data, &params.DataKeyID); err != nil {
+		return err
+	}
+	return db.Store.InsertWorkspaceSessionRecording(ctx, params)
+}
+
+func (db *dbCrypt) UpdateWorkspaceSessionRecordingDataByID(ctx context.Context, params database.UpdateWorkspaceSessionRecordingDataByIDParams) error {
+	if err := db.encryptBytes(&params.Data, &params.DataKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateWorkspaceSessionRecordingDataByID(ctx, params)
+}
+
+func (db *dbCrypt) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
+	variables, err := db.Store.GetTemplateVersionVariables(ctx, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range variables {
+		if err := db.decryptField(&variables[idx].Value, variables[idx].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return variables, nil
+}
+
+func (db *dbCrypt) GetSensitiveTemplateVersionVariables(ctx context.Context) ([]database.TemplateVersionVariable, error) {
+	variables, err := db.Store.GetSensitiveTemplateVersionVariables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range variables {
+		if err := db.decryptField(&variables[idx].Value, variables[idx].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return variables, nil
+}
+
+func (db *dbCrypt) InsertTemplateVersionVariable(ctx context.Context, params database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
+	// Only the values of sensitive variables are encrypted.
+	if params.Sensitive {
+		if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+			return database.TemplateVersionVariable{}, err
+		}
+	}
+	variable, err := db.Store.InsertTemplateVersionVariable(ctx, params)
+	if err != nil {
+		return database.TemplateVersionVariable{}, err
+	}
+	if err := db.decryptField(&variable.Value, variable.ValueKeyID); err != nil {
+		return database.TemplateVersionVariable{}, err
+	}
+	return variable, nil
+}
+
+func (db *dbCrypt) UpdateTemplateVersionVariableValue(ctx context.Context, params database.UpdateTemplateVersionVariableValueParams) error {
+	// This only ever updates sensitive variables.
+	if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateTemplateVersionVariableValue(ctx, params)
+}
+
+func (db *dbCrypt) GetTemplateGitSourceByTemplateID(ctx context.Context, templateID uuid.UUID) (database.TemplateGitSource, error) {
+	source, err := db.Store.GetTemplateGitSourceByTemplateID(ctx, templateID)
+	if err != nil {
+		return database.TemplateGitSource{}, err
+	}
+	if err := db.decryptField(&source.WebhookSecret, source.WebhookSecretKeyID); err != nil {
+		return database.TemplateGitSource{}, err
+	}
+	return source, nil
+}
+
+func (db *dbCrypt) GetTemplateGitSources(ctx context.Context) ([]database.TemplateGitSource, error) {
+	sources, err := db.Store.GetTemplateGitSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range sources {
+		if err := db.decryptField(&sources[idx].WebhookSecret, sources[idx].WebhookSecretKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+func (db *dbCrypt) UpsertTemplateGitSource(ctx context.Context, params database.UpsertTemplateGitSourceParams) (database.TemplateGitSource, error) {
+	if err := db.encryptField(&params.WebhookSecret, &params.WebhookSecretKeyID); err != nil {
+		return database.TemplateGitSource{}, err
+	}
+	source, err := db.Store.UpsertTemplateGitSource(ctx, params)
+	if err != nil {
+		return database.TemplateGitSource{}, err
+	}
+	if err := db.decryptField(&source.WebhookSecret, source.WebhookSecretKeyID); err != nil {
+		return database.TemplateGitSource{}, err
+	}
+	return source, nil
+}
+
+func (db *dbCrypt) UpdateTemplateGitSourceWebhookSecret(ctx context.Context, params database.UpdateTemplateGitSourceWebhookSecretParams) error {
+	if err := db.encryptField(&params.WebhookSecret, &params.WebhookSecretKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateTemplateGitSourceWebhookSecret(ctx, params)
+}
+
 func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 	// If no cipher is loaded, then we can't encrypt anything!
 	if db.ciphers == nil || db.primaryCipherDigest == "" {
@@ -318,6 +563,60 @@ func (db *dbCrypt) decryptField(field *string, digest sql.NullString) error {
 	return nil
 }
 
+// encryptBytes is like encryptField, but for binary columns. Empty values
+// are left unencrypted, as there is nothing to protect.
+func (db *dbCrypt) encryptBytes(field *[]byte, digest *sql.NullString) error {
+	// If no cipher is loaded, then we can't encrypt anything!
+	if db.ciphers == nil || db.primaryCipherDigest == "" {
+		return nil
+	}
+
+	if field == nil {
+		return xerrors.Errorf("developer error: encryptBytes called with nil field")
+	}
+	if digest == nil {
+		return xerrors.Errorf("developer error: encryptBytes called with nil digest")
+	}
+	if len(*field) == 0 {
+		return nil
+	}
+
+	encrypted, err := db.ciphers[db.primaryCipherDigest].Encrypt(*field)
+	if err != nil {
+		return err
+	}
+	// Binary columns can hold the encrypted value as-is.
+	*field = encrypted
+	*digest = sql.NullString{String: db.primaryCipherDigest, Valid: true}
+	return nil
+}
+
+// decryptBytes is like decryptField, but for binary columns.
+func (db *dbCrypt) decryptBytes(field *[]byte, digest sql.NullString) error {
+	if field == nil {
+		return xerrors.Errorf("developer error: decryptBytes called with nil field")
+	}
+
+	if !digest.Valid || digest.String == "" {
+		// This field is not encrypted.
+		return nil
+	}
+
+	key, ok := db.ciphers[digest.String]
+	if !ok {
+		return &DecryptFailedError{
+			Inner: xerrors.Errorf("no cipher with digest %q", digest.String),
+		}
+	}
+
+	decrypted, err := key.Decrypt(*field)
+	if err != nil {
+		return &DecryptFailedError{Inner: err}
+	}
+	*field = decrypted
+	return nil
+}
+
 func (db *dbCrypt) ensureEncryptedWithRetry(ctx context.Context) error {
 	var err error
 	for i := 0; i < 3; i++ {
@@ -10,13 +10,16 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmock"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 )
 
 func TestUserLinks(t *testing.T) {
@@ -349,6 +352,302 @@ func TestExternalAuthLinks(t *testing.T) {
 	})
 }
 
+func TestWorkspaceBuilds(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("InsertWorkspaceBuild", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		build := genWorkspaceBuild(t, crypt, []byte("state"))
+		require.Equal(t, "state", string(build.ProvisionerState))
+		require.Equal(t, ciphers[0].HexDigest(), build.ProvisionerStateKeyID.String)
+
+		rawBuild, err := db.GetWorkspaceBuildByID(ctx, build.ID)
+		require.NoError(t, err)
+		requireEncryptedBytesEquals(t, ciphers[0], rawBuild.ProvisionerState, "state")
+	})
+
+	t.Run("InsertWorkspaceBuildEmptyState", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, _ := setup(t)
+		build := genWorkspaceBuild(t, crypt, nil)
+		require.Empty(t, build.ProvisionerState)
+		require.False(t, build.ProvisionerStateKeyID.Valid)
+
+		rawBuild, err := db.GetWorkspaceBuildByID(ctx, build.ID)
+		require.NoError(t, err)
+		require.Empty(t, rawBuild.ProvisionerState)
+	})
+
+	t.Run("UpdateWorkspaceBuildProvisionerStateByID", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		build := genWorkspaceBuild(t, crypt, nil)
+
+		err := crypt.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
+			ID:               build.ID,
+			ProvisionerState: []byte("state"),
+			UpdatedAt:        build.UpdatedAt,
+		})
+		require.NoError(t, err)
+
+		updated, err := crypt.GetWorkspaceBuildByJobID(ctx, build.JobID)
+		require.NoError(t, err)
+		require.Equal(t, "state", string(updated.ProvisionerState))
+		require.Equal(t, ciphers[0].HexDigest(), updated.ProvisionerStateKeyID.String)
+
+		rawBuild, err := db.GetWorkspaceBuildByID(ctx, build.ID)
+		require.NoError(t, err)
+		requireEncryptedBytesEquals(t, ciphers[0], rawBuild.ProvisionerState, "state")
+	})
+
+	t.Run("GetLatestWorkspaceBuildsByWorkspaceIDs", func(t *testing.T) {
+		t.Parallel()
+		_, crypt, _ := setup(t)
+		build := genWorkspaceBuild(t, crypt, []byte("state"))
+
+		builds, err := crypt.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, []uuid.UUID{build.WorkspaceID})
+		require.NoError(t, err)
+		require.Len(t, builds, 1)
+		require.Equal(t, "state", string(builds[0].ProvisionerState))
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		build := genWorkspaceBuild(t, db, nil)
+		err := db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
+			ID:                    build.ID,
+			ProvisionerState:      fakeRandomData(t, 32),
+			ProvisionerStateKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+			UpdatedAt:             build.UpdatedAt,
+		})
+		require.NoError(t, err)
+
+		_, err = crypt.GetWorkspaceBuildByID(ctx, build.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
+func TestWorkspaceSessionRecordings(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("InsertWorkspaceSessionRecording", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		recording := genWorkspaceSessionRecording(t, crypt, []byte("recording"))
+
+		got, err := crypt.GetWorkspaceSessionRecordingByID(ctx, recording.ID)
+		require.NoError(t, err)
+		require.Equal(t, "recording", string(got.Data))
+		require.Equal(t, ciphers[0].HexDigest(), got.DataKeyID.String)
+
+		rawRecording, err := db.GetWorkspaceSessionRecordingByID(ctx, recording.ID)
+		require.NoError(t, err)
+		requireEncryptedBytesEquals(t, ciphers[0], rawRecording.Data, "recording")
+	})
+
+	t.Run("UpdateWorkspaceSessionRecordingDataByID", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		recording := genWorkspaceSessionRecording(t, db, []byte("recording"))
+
+		err := crypt.UpdateWorkspaceSessionRecordingDataByID(ctx, database.UpdateWorkspaceSessionRecordingDataByIDParams{
+			ID:   recording.ID,
+			Data: recording.Data,
+		})
+		require.NoError(t, err)
+
+		got, err := crypt.GetWorkspaceSessionRecordingByID(ctx, recording.ID)
+		require.NoError(t, err)
+		require.Equal(t, "recording", string(got.Data))
+		require.Equal(t, ciphers[0].HexDigest(), got.DataKeyID.String)
+
+		rawRecording, err := db.GetWorkspaceSessionRecordingByID(ctx, recording.ID)
+		require.NoError(t, err)
+		requireEncryptedBytesEquals(t, ciphers[0], rawRecording.Data, "recording")
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		recording := genWorkspaceSessionRecording(t, db, []byte("recording"))
+		err := db.UpdateWorkspaceSessionRecordingDataByID(ctx, database.UpdateWorkspaceSessionRecordingDataByIDParams{
+			ID:        recording.ID,
+			Data:      fakeRandomData(t, 32),
+			DataKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+		require.NoError(t, err)
+
+		_, err = crypt.GetWorkspaceSessionRecordingByID(ctx, recording.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
+func TestTemplateVersionVariables(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("InsertSensitive", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		version := genTemplateVersion(t, crypt)
+		variable := dbgen.TemplateVersionVariable(t, crypt, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Value:             "secret",
+			Sensitive:         true,
+		})
+		require.Equal(t, "secret", variable.Value)
+		require.Equal(t, ciphers[0].HexDigest(), variable.ValueKeyID.String)
+
+		variables, err := crypt.GetTemplateVersionVariables(ctx, version.ID)
+		require.NoError(t, err)
+		require.Len(t, variables, 1)
+		require.Equal(t, "secret", variables[0].Value)
+
+		rawVariables, err := db.GetTemplateVersionVariables(ctx, version.ID)
+		require.NoError(t, err)
+		require.Len(t, rawVariables, 1)
+		requireEncryptedEquals(t, ciphers[0], rawVariables[0].Value, "secret")
+	})
+
+	t.Run("InsertNotSensitive", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, _ := setup(t)
+		version := genTemplateVersion(t, crypt)
+		variable := dbgen.TemplateVersionVariable(t, crypt, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Value:             "public",
+		})
+		require.Equal(t, "public", variable.Value)
+		require.False(t, variable.ValueKeyID.Valid)
+
+		rawVariables, err := db.GetTemplateVersionVariables(ctx, version.ID)
+		require.NoError(t, err)
+		require.Len(t, rawVariables, 1)
+		require.Equal(t, "public", rawVariables[0].Value)
+	})
+
+	t.Run("UpdateTemplateVersionVariableValue", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		version := genTemplateVersion(t, crypt)
+		variable := dbgen.TemplateVersionVariable(t, db, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Value:             "secret",
+			Sensitive:         true,
+		})
+		require.False(t, variable.ValueKeyID.Valid)
+
+		err := crypt.UpdateTemplateVersionVariableValue(ctx, database.UpdateTemplateVersionVariableValueParams{
+			TemplateVersionID: version.ID,
+			Name:              variable.Name,
+			Value:             variable.Value,
+		})
+		require.NoError(t, err)
+
+		variables, err := crypt.GetSensitiveTemplateVersionVariables(ctx)
+		require.NoError(t, err)
+		require.Len(t, variables, 1)
+		require.Equal(t, "secret", variables[0].Value)
+		require.Equal(t, ciphers[0].HexDigest(), variables[0].ValueKeyID.String)
+
+		rawVariables, err := db.GetTemplateVersionVariables(ctx, version.ID)
+		require.NoError(t, err)
+		require.Len(t, rawVariables, 1)
+		requireEncryptedEquals(t, ciphers[0], rawVariables[0].Value, "secret")
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		version := genTemplateVersion(t, db)
+		_ = dbgen.TemplateVersionVariable(t, db, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Value:             fakeBase64RandomData(t, 32),
+			Sensitive:         true,
+			ValueKeyID:        sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+
+		_, err := crypt.GetTemplateVersionVariables(ctx, version.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
+func TestTemplateGitSources(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("UpsertTemplateGitSource", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		source := genTemplateGitSource(t, crypt, "secret")
+		require.Equal(t, "secret", source.WebhookSecret)
+		require.Equal(t, ciphers[0].HexDigest(), source.WebhookSecretKeyID.String)
+
+		got, err := crypt.GetTemplateGitSourceByTemplateID(ctx, source.TemplateID)
+		require.NoError(t, err)
+		require.Equal(t, "secret", got.WebhookSecret)
+		require.Equal(t, ciphers[0].HexDigest(), got.WebhookSecretKeyID.String)
+
+		sources, err := crypt.GetTemplateGitSources(ctx)
+		require.NoError(t, err)
+		require.Len(t, sources, 1)
+		require.Equal(t, "secret", sources[0].WebhookSecret)
+
+		rawSource, err := db.GetTemplateGitSourceByTemplateID(ctx, source.TemplateID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], rawSource.WebhookSecret, "secret")
+	})
+
+	t.Run("UpdateTemplateGitSourceWebhookSecret", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		source := genTemplateGitSource(t, db, "secret")
+		require.False(t, source.WebhookSecretKeyID.Valid)
+
+		err := crypt.UpdateTemplateGitSourceWebhookSecret(ctx, database.UpdateTemplateGitSourceWebhookSecretParams{
+			TemplateID:    source.TemplateID,
+			WebhookSecret: source.WebhookSecret,
+		})
+		require.NoError(t, err)
+
+		got, err := crypt.GetTemplateGitSourceByTemplateID(ctx, source.TemplateID)
+		require.NoError(t, err)
+		require.Equal(t, "secret", got.WebhookSecret)
+		require.Equal(t, ciphers[0].HexDigest(), got.WebhookSecretKeyID.String)
+
+		rawSource, err := db.GetTemplateGitSourceByTemplateID(ctx, source.TemplateID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], rawSource.WebhookSecret, "secret")
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		source := genTemplateGitSource(t, db, "secret")
+		err := db.UpdateTemplateGitSourceWebhookSecret(ctx, database.UpdateTemplateGitSourceWebhookSecretParams{
+			TemplateID:         source.TemplateID,
+			WebhookSecret:      fakeBase64RandomData(t, 32),
+			WebhookSecretKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+		require.NoError(t, err)
+
+		_, err = crypt.GetTemplateGitSourceByTemplateID(ctx, source.TemplateID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 
@@ -618,6 +917,65 @@ func TestEncryptDecryptField(t *testing.T) {
 	})
 }
 
+func TestEncryptDecryptBytes(t *testing.T) {
+	t.Parallel()
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		_, cryptDB, ciphers := setup(t)
+		field := []byte("coder")
+		digest := sql.NullString{}
+		require.NoError(t, cryptDB.encryptBytes(&field, &digest))
+		require.Equal(t, ciphers[0].HexDigest(), digest.String)
+		requireEncryptedBytesEquals(t, ciphers[0], field, "coder")
+		require.NoError(t, cryptDB.decryptBytes(&field, digest))
+		require.Equal(t, "coder", string(field))
+	})
+
+	t.Run("NoKeys", func(t *testing.T) {
+		t.Parallel()
+		// With no keys, encryption and decryption are both no-ops.
+		_, cryptDB := setupNoCiphers(t)
+		field := []byte("coder")
+		digest := sql.NullString{}
+		require.NoError(t, cryptDB.encryptBytes(&field, &digest))
+		require.Empty(t, digest.String)
+		require.Equal(t, "coder", string(field))
+		require.NoError(t, cryptDB.decryptBytes(&field, digest))
+		require.Equal(t, "coder", string(field))
+	})
+
+	t.Run("EmptyNotEncrypted", func(t *testing.T) {
+		t.Parallel()
+		_, cryptDB, _ := setup(t)
+		var field []byte
+		digest := sql.NullString{}
+		require.NoError(t, cryptDB.encryptBytes(&field, &digest))
+		require.False(t, digest.Valid)
+		require.Empty(t, field)
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		t.Parallel()
+		_, cryptDB, _ := setup(t)
+		field := []byte("coder")
+		digest := sql.NullString{}
+		require.NoError(t, cryptDB.encryptBytes(&field, &digest))
+
+		digest = sql.NullString{String: "missing", Valid: true}
+		var derr *DecryptFailedError
+		err := cryptDB.decryptBytes(&field, digest)
+		require.Error(t, err)
+		require.ErrorAs(t, err, &derr)
+	})
+
+	t.Run("CantEncryptOrDecryptNil", func(t *testing.T) {
+		t.Parallel()
+		_, cryptDB, _ := setup(t)
+		require.ErrorContains(t, cryptDB.encryptBytes(nil, nil), "developer error")
+		require.ErrorContains(t, cryptDB.decryptBytes(nil, sql.NullString{}), "developer error")
+	})
+}
+
 func expectInTx(mdb *dbmock.MockStore) *gomock.Call {
 	return mdb.EXPECT().InTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
 		func(f func(store database.Store) error, _ *sql.TxOptions) error {
@@ -635,6 +993,13 @@ func requireEncryptedEquals(t *testing.T, c Cipher, value, expected string) {
 	require.Equal(t, expected, string(got), "decrypted data does not match")
 }
 
+func requireEncryptedBytesEquals(t *testing.T, c Cipher, value []byte, expected string) {
+	t.Helper()
+	got, err := c.Decrypt(value)
+	require.NoError(t, err, "failed to decrypt data")
+	require.Equal(t, expected, string(got), "decrypted data does not match")
+}
+
 func initCipher(t *testing.T) *aes256 {
 	t.Helper()
 	key := make([]byte, 32) // AES-256 key size is 32 bytes
@@ -673,3 +1038,81 @@ func fakeBase64RandomData(t *testing.T, n int) string {
 	require.NoError(t, err)
 	return base64.StdEncoding.EncodeToString(b)
 }
+
+func fakeRandomData(t *testing.T, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	_, err := io.ReadFull(rand.Reader, b)
+	require.NoError(t, err)
+	return b
+}
+
+// genWorkspaceBuild creates a workspace build, along with everything it
+// references, with the given provisioner state.
+func genWorkspaceBuild(t *testing.T, db database.Store, state []byte) database.WorkspaceBuild {
+	t.Helper()
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).Seed(database.WorkspaceBuild{
+		ProvisionerState: state,
+	}).Do()
+	return r.Build
+}
+
+// genWorkspaceSessionRecording creates a session recording, along with
+// everything it references, with the given data.
+func genWorkspaceSessionRecording(t *testing.T, db database.Store, data []byte) database.InsertWorkspaceSessionRecordingParams {
+	t.Helper()
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	}).WithAgent().Do()
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(context.Background(), r.Workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	recording := database.InsertWorkspaceSessionRecordingParams{
+		ID:               uuid.New(),
+		WorkspaceID:      r.Workspace.ID,
+		WorkspaceBuildID: r.Build.ID,
+		AgentID:          agents[0].ID,
+		Type:             database.WorkspaceSessionRecordingTypeSsh,
+		StartedAt:        dbtime.Now(),
+		EndedAt:          dbtime.Now(),
+		Size:             int64(len(data)),
+		Data:             data,
+	}
+	err = db.InsertWorkspaceSessionRecording(context.Background(), recording)
+	require.NoError(t, err)
+	return recording
+}
+
+func genTemplateVersion(t *testing.T, db database.Store) database.TemplateVersion {
+	t.Helper()
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	r := dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	}).Do()
+	return r.TemplateVersion
+}
+
+func genTemplateGitSource(t *testing.T, db database.Store, webhookSecret string) database.TemplateGitSource {
+	t.Helper()
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	r := dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	}).Do()
+	return dbgen.TemplateGitSource(t, db, database.TemplateGitSource{
+		TemplateID:    r.Template.ID,
+		CreatedBy:     user.ID,
+		WebhookSecret: webhookSecret,
+	})
+}
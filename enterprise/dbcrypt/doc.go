@@ -5,12 +5,22 @@
 // encryption keys. Each key has a unique identifier, which is used to
 // uniquely identify the key whilst maintaining secrecy.
 //
-// Currently, AES-256-GCM is the only implemented cipher mode.
+// Values are encrypted with AES-256-GCM, either directly with a configured key,
+// or with a data key that is wrapped by an external key service (envelope
+// encryption). Key services implement KeyProvider; VaultTransit uses a key of
+// the HashiCorp Vault Transit secrets engine. The digest of an envelope cipher
+// is derived from the key provider, so keys held by a key service are rotated
+// and revoked like any other key.
+//
 // The Cipher is currently used to encrypt/decrypt the following fields:
 // - database.UserLink.OAuthAccessToken
 // - database.UserLink.OAuthRefreshToken
 // - database.GitAuthLink.OAuthAccessToken
 // - database.GitAuthLink.OAuthRefreshToken
+// - database.WorkspaceBuild.ProvisionerState
+// - database.WorkspaceSessionRecording.Data
+// - database.TemplateGitSource.WebhookSecret
+// - database.TemplateVersionVariable.Value (only if the variable is sensitive)
 // - database.DBCryptSentinelValue
 //
 // Multiple ciphers can be provided to support key rotation. The primary cipher
@@ -27,7 +37,8 @@
 //   - revoked_at: the time the key was revoked. If null, the key has not been revoked.
 //   - test: the encrypted value of the string "coder". This is used to ensure that the key is valid.
 //
-// Encrypted fields are stored in the database as a base64-encoded string.
+// Encrypted fields are stored in the database as a base64-encoded string,
+// except for binary columns, which store the encrypted value as-is.
 // Each encrypted column MUST have a corresponding _key_id column that is a foreign key
 // reference to `dbcrypt_keys.active_key_digest`. This ensures that a key cannot be
 // revoked until all rows that use that key have been migrated to a new key.
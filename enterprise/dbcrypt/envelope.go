@@ -0,0 +1,198 @@
+package dbcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/xerrors"
+)
+
+// cipherEnvelope is the name of the envelope cipher. Like cipherAES256GCM,
+// it is added to the digest of the cipher.
+const cipherEnvelope = "envelope"
+
+// unwrapTimeout is how long the envelope cipher waits for the key provider
+// to unwrap a data key.
+const unwrapTimeout = 30 * time.Second
+
+// KeyProvider wraps and unwraps data keys with a key encryption key that is
+// held by an external key service, like a KMS or an HSM. The key encryption
+// key never leaves the key service.
+type KeyProvider interface {
+	// ID identifies the key encryption key. It is used to derive the digest
+	// of the envelope cipher, so it must be stable across restarts and must
+	// not depend on how the key service is reached.
+	ID() string
+	// WrapKey encrypts a data key.
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key that was encrypted with WrapKey.
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// NewEnvelopeCipher returns a cipher that encrypts values with AES-256-GCM
+// using a random data key. The data key is wrapped by the provider and stored
+// alongside each value, so only values encrypted by another instance of the
+// cipher require a call to the provider to decrypt.
+//
+// The digest of the cipher is derived from the ID of the provider. Rotating
+// to a new key encryption key is done like rotating a raw key: configure a
+// cipher for the new key first and run "coder server dbcrypt rotate".
+func NewEnvelopeCipher(ctx context.Context, provider KeyProvider) (Cipher, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, xerrors.Errorf("generate data key: %w", err)
+	}
+	wrapped, err := provider.WrapKey(ctx, key)
+	if err != nil {
+		return nil, xerrors.Errorf("wrap data key with %q: %w", provider.ID(), err)
+	}
+	if len(wrapped) == 0 || len(wrapped) > 0xffff {
+		return nil, xerrors.Errorf("wrapped data key must be between 1 and %d bytes, got %d", 0xffff, len(wrapped))
+	}
+	dataCipher, err := cipherAES256(key)
+	if err != nil {
+		return nil, err
+	}
+
+	toDigest := []byte(cipherEnvelope)
+	toDigest = append(toDigest, provider.ID()...)
+	return &envelope{
+		provider: provider,
+		digest:   fmt.Sprintf("%x", sha256.Sum256(toDigest))[:7],
+		wrapped:  wrapped,
+		data:     dataCipher,
+		unwrapped: map[string]*aes256{
+			string(wrapped): dataCipher,
+		},
+	}, nil
+}
+
+// envelope encrypts values as the length of the wrapped data key as a
+// big-endian uint16, followed by the wrapped data key and the value
+// encrypted with the data key.
+type envelope struct {
+	provider KeyProvider
+	digest   string
+	wrapped  []byte
+	data     *aes256
+
+	mu sync.RWMutex
+	// unwrapped caches ciphers for data keys by their wrapped form.
+	unwrapped map[string]*aes256
+	// unwrapping deduplicates concurrent calls to the provider for the same
+	// wrapped data key.
+	unwrapping singleflight.Group
+}
+
+func (e *envelope) Encrypt(plaintext []byte) ([]byte, error) {
+	encrypted, err := e.data.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	dst := make([]byte, 2, 2+len(e.wrapped)+len(encrypted))
+	binary.BigEndian.PutUint16(dst, uint16(len(e.wrapped)))
+	dst = append(dst, e.wrapped...)
+	return append(dst, encrypted...), nil
+}
+
+func (e *envelope) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2 {
+		return nil, xerrors.Errorf("ciphertext too short")
+	}
+	n := int(binary.BigEndian.Uint16(ciphertext))
+	if len(ciphertext) < 2+n {
+		return nil, xerrors.Errorf("ciphertext too short")
+	}
+	dataCipher, err := e.dataCipher(ciphertext[2 : 2+n])
+	if err != nil {
+		return nil, &DecryptFailedError{Inner: err}
+	}
+	return dataCipher.Decrypt(ciphertext[2+n:])
+}
+
+func (e *envelope) HexDigest() string {
+	return e.digest
+}
+
+// dataCipher returns the cipher for a wrapped data key, unwrapping it with
+// the provider if it hasn't been seen before. The provider may be slow, so it
+// is called without holding the lock; values with cached data keys can be
+// decrypted while another key is being unwrapped.
+func (e *envelope) dataCipher(wrapped []byte) (*aes256, error) {
+	e.mu.RLock()
+	c, ok := e.unwrapped[string(wrapped)]
+	e.mu.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	v, err, _ := e.unwrapping.Do(string(wrapped), func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), unwrapTimeout)
+		defer cancel()
+		key, err := e.provider.UnwrapKey(ctx, wrapped)
+		if err != nil {
+			return nil, xerrors.Errorf("unwrap data key with %q: %w", e.provider.ID(), err)
+		}
+		c, err := cipherAES256(key)
+		if err != nil {
+			return nil, err
+		}
+
+		e.mu.Lock()
+		e.unwrapped[string(wrapped)] = c
+		e.mu.Unlock()
+		return c, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*aes256), nil
+}
+
+// NewFileKeyProvider returns a key provider that wraps data keys with
+// AES-256-GCM using the base64-encoded 32-byte key in the file at path. It
+// stands in for a key service, like an HSM accessed over PKCS#11, where one
+// isn't available, and is mostly useful for testing.
+func NewFileKeyProvider(path string) (KeyProvider, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("read key file: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, xerrors.Errorf("key file %q must contain a base64-encoded key: %w", path, err)
+	}
+	c, err := cipherAES256(key)
+	if err != nil {
+		return nil, xerrors.Errorf("key file %q: %w", path, err)
+	}
+	return &fileKeyProvider{cipher: c}, nil
+}
+
+type fileKeyProvider struct {
+	cipher *aes256
+}
+
+// ID is derived from the key rather than the path of the file, so the file
+// can be moved.
+func (f *fileKeyProvider) ID() string {
+	return "file:" + f.cipher.HexDigest()
+}
+
+func (f *fileKeyProvider) WrapKey(_ context.Context, key []byte) ([]byte, error) {
+	return f.cipher.Encrypt(key)
+}
+
+func (f *fileKeyProvider) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	return f.cipher.Decrypt(wrapped)
+}
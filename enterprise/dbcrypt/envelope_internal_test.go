@@ -0,0 +1,259 @@
+package dbcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestEnvelopeCipher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+		provider := &countingKeyProvider{KeyProvider: fileKeyProviderForTest(t, 'a')}
+		cipher, err := NewEnvelopeCipher(context.Background(), provider)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, provider.wraps.Load())
+
+		encrypted1, err := cipher.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+		encrypted2, err := cipher.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+		require.NotEqual(t, encrypted1, encrypted2, "nonce should be different for each encryption")
+
+		decrypted, err := cipher.Decrypt(encrypted1)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(decrypted))
+		// The data key of the cipher is already known.
+		require.EqualValues(t, 0, provider.unwraps.Load())
+	})
+
+	t.Run("OtherInstance", func(t *testing.T) {
+		t.Parallel()
+		provider := &countingKeyProvider{KeyProvider: fileKeyProviderForTest(t, 'a')}
+		cipher1, err := NewEnvelopeCipher(context.Background(), provider)
+		require.NoError(t, err)
+		cipher2, err := NewEnvelopeCipher(context.Background(), provider)
+		require.NoError(t, err)
+		require.Equal(t, cipher1.HexDigest(), cipher2.HexDigest(), "digest must be stable across instances")
+
+		encrypted, err := cipher1.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			decrypted, err := cipher2.Decrypt(encrypted)
+			require.NoError(t, err)
+			require.Equal(t, "hello world", string(decrypted))
+		}
+		// Unwrapped data keys are cached.
+		require.EqualValues(t, 1, provider.unwraps.Load())
+	})
+
+	t.Run("SlowUnwrap", func(t *testing.T) {
+		t.Parallel()
+		provider := &blockingKeyProvider{
+			countingKeyProvider: countingKeyProvider{KeyProvider: fileKeyProviderForTest(t, 'a')},
+			release:             make(chan struct{}),
+		}
+		cipher1, err := NewEnvelopeCipher(context.Background(), provider)
+		require.NoError(t, err)
+		cipher2, err := NewEnvelopeCipher(context.Background(), provider)
+		require.NoError(t, err)
+		foreign, err := cipher1.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+		own, err := cipher2.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+
+		// Decrypting the value of the other instance requires unwrapping
+		// its data key, which blocks until released.
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				decrypted, err := cipher2.Decrypt(foreign)
+				assert.NoError(t, err)
+				assert.Equal(t, "hello world", string(decrypted))
+			}()
+		}
+		require.Eventually(t, func() bool {
+			return provider.unwraps.Load() == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+
+		// Values with a cached data key don't wait for the provider.
+		decrypted, err := cipher2.Decrypt(own)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(decrypted))
+
+		close(provider.release)
+		wg.Wait()
+		// Concurrent decryptions share a single call to the provider.
+		require.EqualValues(t, 1, provider.unwraps.Load())
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		t.Parallel()
+		cipher1, err := NewEnvelopeCipher(context.Background(), fileKeyProviderForTest(t, 'a'))
+		require.NoError(t, err)
+		cipher2, err := NewEnvelopeCipher(context.Background(), fileKeyProviderForTest(t, 'b'))
+		require.NoError(t, err)
+		require.NotEqual(t, cipher1.HexDigest(), cipher2.HexDigest())
+
+		encrypted, err := cipher1.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+		_, err = cipher2.Decrypt(encrypted)
+		var decryptErr *DecryptFailedError
+		require.ErrorAs(t, err, &decryptErr)
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		t.Parallel()
+		cipher, err := NewEnvelopeCipher(context.Background(), fileKeyProviderForTest(t, 'a'))
+		require.NoError(t, err)
+
+		_, err = cipher.Decrypt([]byte{0})
+		require.ErrorContains(t, err, "ciphertext too short")
+		_, err = cipher.Decrypt([]byte{0xff, 0xff, 'a'})
+		require.ErrorContains(t, err, "ciphertext too short")
+
+		encrypted, err := cipher.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+		munged := bytes.Clone(encrypted)
+		munged[len(munged)-1] ^= 0xff
+		_, err = cipher.Decrypt(munged)
+		var decryptErr *DecryptFailedError
+		require.ErrorAs(t, err, &decryptErr)
+	})
+
+	t.Run("DigestDiffersFromRawKey", func(t *testing.T) {
+		t.Parallel()
+		key := bytes.Repeat([]byte{'a'}, 32)
+		raw, err := cipherAES256(key)
+		require.NoError(t, err)
+		provider := fileKeyProviderForTest(t, 'a')
+		envelope, err := NewEnvelopeCipher(context.Background(), provider)
+		require.NoError(t, err)
+		require.Equal(t, "file:"+raw.HexDigest(), provider.ID())
+		require.NotEqual(t, raw.HexDigest(), envelope.HexDigest())
+	})
+}
+
+func TestFileKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotBase64", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "key")
+		require.NoError(t, os.WriteFile(path, []byte("not base64!"), 0o600))
+		_, err := NewFileKeyProvider(path)
+		require.ErrorContains(t, err, "must contain a base64-encoded key")
+	})
+
+	t.Run("InvalidKeySize", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "key")
+		key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'a'}, 31))
+		require.NoError(t, os.WriteFile(path, []byte(key), 0o600))
+		_, err := NewFileKeyProvider(path)
+		require.ErrorContains(t, err, "key must be 32 bytes")
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewFileKeyProvider(filepath.Join(t.TempDir(), "key"))
+		require.ErrorContains(t, err, "read key file")
+	})
+}
+
+func TestParseCiphers(t *testing.T) {
+	t.Parallel()
+
+	vault := newFakeVault(t, "coder")
+	env := map[string]string{
+		"VAULT_ADDR":  vault.URL,
+		"VAULT_TOKEN": fakeVaultToken,
+	}
+	rawKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{'a'}, 32))
+	filePath := writeKeyFile(t, 'b')
+
+	ciphers, err := ParseCiphers(context.Background(), func(k string) string { return env[k] },
+		"vault-transit:transit/coder",
+		rawKey,
+		"file:"+filePath,
+	)
+	require.NoError(t, err)
+	require.Len(t, ciphers, 3)
+	require.IsType(t, &envelope{}, ciphers[0])
+	require.Equal(t, "vault-transit:transit/coder", ciphers[0].(*envelope).provider.ID())
+	require.Equal(t, "864f702", ciphers[1].HexDigest())
+	require.IsType(t, &envelope{}, ciphers[2])
+
+	for _, c := range ciphers {
+		encrypted, err := c.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+		decrypted, err := c.Decrypt(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(decrypted))
+	}
+
+	_, err = ParseCiphers(context.Background(), func(k string) string { return env[k] }, "vault-transit:transit/")
+	require.ErrorContains(t, err, "vault transit key name is empty")
+	_, err = ParseCiphers(context.Background(), func(k string) string { return env[k] }, "not a key")
+	require.ErrorContains(t, err, "must be base64-encoded")
+	_, err = ParseCiphers(context.Background(), func(string) string { return "" }, "vault-transit:coder")
+	require.ErrorContains(t, err, "vault address is not set")
+}
+
+// countingKeyProvider counts the calls to the wrapped key provider.
+type countingKeyProvider struct {
+	KeyProvider
+	wraps   atomic.Int64
+	unwraps atomic.Int64
+}
+
+func (c *countingKeyProvider) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	c.wraps.Add(1)
+	return c.KeyProvider.WrapKey(ctx, key)
+}
+
+func (c *countingKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	c.unwraps.Add(1)
+	return c.KeyProvider.UnwrapKey(ctx, wrapped)
+}
+
+// blockingKeyProvider blocks unwrapping until release is closed.
+type blockingKeyProvider struct {
+	countingKeyProvider
+	release chan struct{}
+}
+
+func (b *blockingKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	b.unwraps.Add(1)
+	<-b.release
+	return b.KeyProvider.UnwrapKey(ctx, wrapped)
+}
+
+func writeKeyFile(t *testing.T, b byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key")
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+	require.NoError(t, os.WriteFile(path, []byte(key+"\n"), 0o600))
+	return path
+}
+
+func fileKeyProviderForTest(t *testing.T, b byte) KeyProvider {
+	t.Helper()
+	provider, err := NewFileKeyProvider(writeKeyFile(t, b))
+	require.NoError(t, err)
+	return provider
+}
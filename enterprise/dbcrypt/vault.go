@@ -0,0 +1,148 @@
+package dbcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// VaultTransit is a key provider that wraps data keys with a key of the
+// HashiCorp Vault Transit secrets engine.
+type VaultTransit struct {
+	// Address is the URL of the Vault server, like
+	// "https://vault.example.com:8200".
+	Address string
+	// Token authenticates requests to Vault. It must be allowed to update
+	// "<Mount>/encrypt/<Key>" and "<Mount>/decrypt/<Key>".
+	Token string
+	// Namespace is the Vault Enterprise namespace of the mount, if any.
+	Namespace string
+	// Mount is the path the Transit secrets engine is mounted at. Defaults
+	// to "transit".
+	Mount string
+	// Key is the name of the Transit key.
+	Key string
+	// HTTPClient is used to make requests to Vault. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ KeyProvider = &VaultTransit{}
+
+// ID does not include the address of the server, so Vault can be moved
+// without changing the digest of ciphers. Rotating the Transit key in Vault
+// does not change the ID either, since Vault can still decrypt data keys
+// wrapped by earlier versions of the key.
+func (v *VaultTransit) ID() string {
+	return fmt.Sprintf("vault-transit:%s/%s", v.mount(), v.Key)
+}
+
+func (v *VaultTransit) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	var resp struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	err := v.request(ctx, "encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(key),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Ciphertext == "" {
+		return nil, xerrors.New("vault returned an empty ciphertext")
+	}
+	return []byte(resp.Ciphertext), nil
+}
+
+func (v *VaultTransit) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	err := v.request(ctx, "decrypt", map[string]string{
+		"ciphertext": string(wrapped),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, xerrors.Errorf("decode plaintext: %w", err)
+	}
+	return key, nil
+}
+
+func (v *VaultTransit) mount() string {
+	if v.Mount == "" {
+		return "transit"
+	}
+	return strings.Trim(v.Mount, "/")
+}
+
+// request calls the Transit endpoint "<Mount>/<operation>/<Key>" and decodes
+// the data of the response into data.
+func (v *VaultTransit) request(ctx context.Context, operation string, body any, data any) error {
+	if v.Address == "" {
+		return xerrors.New("vault address is not set")
+	}
+	if v.Key == "" {
+		return xerrors.New("vault transit key is not set")
+	}
+	u, err := url.Parse(v.Address)
+	if err != nil {
+		return xerrors.Errorf("parse vault address: %w", err)
+	}
+	u = u.JoinPath("v1", v.mount(), operation, v.Key)
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Request", "true")
+	if v.Token != "" {
+		req.Header.Set("X-Vault-Token", v.Token)
+	}
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("%s with vault transit key %q: %w", operation, v.Key, err)
+	}
+	defer res.Body.Close()
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []string        `json:"errors"`
+	}
+	err = json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&resp)
+	if res.StatusCode != http.StatusOK {
+		if err == nil && len(resp.Errors) > 0 {
+			return xerrors.Errorf("%s with vault transit key %q: status %d: %s", operation, v.Key, res.StatusCode, strings.Join(resp.Errors, "; "))
+		}
+		return xerrors.Errorf("%s with vault transit key %q: unexpected status %d", operation, v.Key, res.StatusCode)
+	}
+	if err != nil {
+		return xerrors.Errorf("decode vault response: %w", err)
+	}
+	if err := json.Unmarshal(resp.Data, data); err != nil {
+		return xerrors.Errorf("decode vault response data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package dbcrypt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultTransit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WrapUnwrap", func(t *testing.T) {
+		t.Parallel()
+		vault := newFakeVault(t, "coder")
+		provider := &VaultTransit{
+			Address: vault.URL,
+			Token:   fakeVaultToken,
+			Key:     "coder",
+		}
+		require.Equal(t, "vault-transit:transit/coder", provider.ID())
+
+		key := []byte("0123456789abcdef0123456789abcdef")
+		wrapped, err := provider.WrapKey(context.Background(), key)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(wrapped), "vault:v1:"))
+
+		unwrapped, err := provider.UnwrapKey(context.Background(), wrapped)
+		require.NoError(t, err)
+		require.Equal(t, key, unwrapped)
+	})
+
+	t.Run("Envelope", func(t *testing.T) {
+		t.Parallel()
+		vault := newFakeVault(t, "coder")
+		provider := &VaultTransit{
+			Address: vault.URL,
+			Token:   fakeVaultToken,
+			Mount:   "/transit/",
+			Key:     "coder",
+		}
+		cipher1, err := NewEnvelopeCipher(context.Background(), provider)
+		require.NoError(t, err)
+		cipher2, err := NewEnvelopeCipher(context.Background(), provider)
+		require.NoError(t, err)
+		require.Equal(t, cipher1.HexDigest(), cipher2.HexDigest())
+
+		encrypted, err := cipher1.Encrypt([]byte("hello world"))
+		require.NoError(t, err)
+		decrypted, err := cipher2.Decrypt(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(decrypted))
+	})
+
+	t.Run("PermissionDenied", func(t *testing.T) {
+		t.Parallel()
+		vault := newFakeVault(t, "coder")
+		provider := &VaultTransit{
+			Address: vault.URL,
+			Token:   "wrong",
+			Key:     "coder",
+		}
+		_, err := NewEnvelopeCipher(context.Background(), provider)
+		require.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		t.Parallel()
+		vault := newFakeVault(t, "coder")
+		provider := &VaultTransit{
+			Address: vault.URL,
+			Token:   fakeVaultToken,
+			Key:     "other",
+		}
+		_, err := provider.WrapKey(context.Background(), []byte("key"))
+		require.ErrorContains(t, err, "status 400")
+	})
+}
+
+const fakeVaultToken = "s.fake-vault-token"
+
+// newFakeVault starts a server that implements the encrypt and decrypt
+// endpoints of the Transit secrets engine mounted at "transit" for the given
+// keys. Ciphertexts are opaque references to the plaintext, like they are
+// for a real Vault server.
+func newFakeVault(t *testing.T, keys ...string) *httptest.Server {
+	t.Helper()
+
+	var (
+		mu          sync.Mutex
+		plaintexts  = map[string]string{}
+		writeErrors = func(rw http.ResponseWriter, status int, errs ...string) {
+			rw.WriteHeader(status)
+			_ = json.NewEncoder(rw).Encode(map[string]any{"errors": errs})
+		}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost {
+			writeErrors(rw, http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("X-Vault-Token") != fakeVaultToken {
+			writeErrors(rw, http.StatusForbidden, "permission denied")
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/transit/"), "/")
+		if len(parts) != 2 {
+			writeErrors(rw, http.StatusNotFound)
+			return
+		}
+		known := false
+		for _, k := range keys {
+			known = known || k == parts[1]
+		}
+		if !known {
+			writeErrors(rw, http.StatusBadRequest, "encryption key not found")
+			return
+		}
+
+		var req struct {
+			Plaintext  string `json:"plaintext"`
+			Ciphertext string `json:"ciphertext"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrors(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		var data map[string]string
+		switch parts[0] {
+		case "encrypt":
+			id := make([]byte, 16)
+			_, _ = io.ReadFull(rand.Reader, id)
+			ciphertext := "vault:v1:" + base64.StdEncoding.EncodeToString(id)
+			plaintexts[parts[1]+ciphertext] = req.Plaintext
+			data = map[string]string{"ciphertext": ciphertext}
+		case "decrypt":
+			plaintext, ok := plaintexts[parts[1]+req.Ciphertext]
+			if !ok {
+				writeErrors(rw, http.StatusBadRequest, "cipher: message authentication failed")
+				return
+			}
+			data = map[string]string{"plaintext": plaintext}
+		default:
+			writeErrors(rw, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(rw).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
@@ -13,6 +13,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	tfjson "github.com/hashicorp/terraform-json"
@@ -32,6 +33,8 @@ type executor struct {
 	// cachePath and workdir must not be used by multiple processes at once.
 	cachePath string
 	workdir   string
+	// timings is shared by the stages run with this executor.
+	timings *timingAggregator
 }
 
 func (e *executor) basicEnv() []string {
@@ -211,7 +214,10 @@ func (e *executor) init(ctx, killCtx context.Context, logr logSink) error {
 		"-input=false",
 	}
 
-	return e.execWriteOutput(ctx, killCtx, args, e.basicEnv(), outWriter, errWriter)
+	start := time.Now()
+	err := e.execWriteOutput(ctx, killCtx, args, e.basicEnv(), outWriter, errWriter)
+	e.timings.stage(timingStageInit, start, time.Now())
+	return err
 }
 
 func getPlanFilePath(workdir string) string {
@@ -246,7 +252,7 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		args = append(args, "-var", variable)
 	}
 
-	outWriter, doneOut := provisionLogWriter(logr)
+	outWriter, doneOut := provisionLogWriter(logr, e.timingIngester(timingStagePlan))
 	errWriter, doneErr := logWriter(logr, proto.LogLevel_ERROR)
 	defer func() {
 		_ = outWriter.Close()
@@ -255,11 +261,13 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		<-doneErr
 	}()
 
+	start := time.Now()
 	err := e.execWriteOutput(ctx, killCtx, args, env, outWriter, errWriter)
+	e.timings.stage(timingStagePlan, start, time.Now())
 	if err != nil {
 		return nil, xerrors.Errorf("terraform plan: %w", err)
 	}
-	state, err := e.planResources(ctx, killCtx, planfilePath)
+	state, changes, err := e.planResources(ctx, killCtx, planfilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -267,6 +275,8 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		Parameters:            state.Parameters,
 		Resources:             state.Resources,
 		ExternalAuthProviders: state.ExternalAuthProviders,
+		Timings:               e.timings.aggregate(),
+		ResourceChanges:       changes,
 	}, nil
 }
 
@@ -288,18 +298,18 @@ func onlyDataResources(sm tfjson.StateModule) tfjson.StateModule {
 }
 
 // planResources must only be called while the lock is held.
-func (e *executor) planResources(ctx, killCtx context.Context, planfilePath string) (*State, error) {
+func (e *executor) planResources(ctx, killCtx context.Context, planfilePath string) (*State, []*proto.ResourceChange, error) {
 	ctx, span := e.server.startTrace(ctx, tracing.FuncName())
 	defer span.End()
 
 	plan, err := e.showPlan(ctx, killCtx, planfilePath)
 	if err != nil {
-		return nil, xerrors.Errorf("show terraform plan file: %w", err)
+		return nil, nil, xerrors.Errorf("show terraform plan file: %w", err)
 	}
 
 	rawGraph, err := e.graph(ctx, killCtx)
 	if err != nil {
-		return nil, xerrors.Errorf("graph: %w", err)
+		return nil, nil, xerrors.Errorf("graph: %w", err)
 	}
 	modules := []*tfjson.StateModule{}
 	if plan.PriorState != nil {
@@ -317,9 +327,41 @@ func (e *executor) planResources(ctx, killCtx context.Context, planfilePath stri
 
 	state, err := ConvertState(modules, rawGraph)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return state, nil
+	return state, convertResourceChanges(plan.ResourceChanges), nil
+}
+
+// convertResourceChanges returns the changes a plan makes to managed
+// resources. Resources that are left as they are, and data sources, are
+// omitted.
+func convertResourceChanges(changes []*tfjson.ResourceChange) []*proto.ResourceChange {
+	converted := make([]*proto.ResourceChange, 0, len(changes))
+	for _, change := range changes {
+		if change.Mode != tfjson.ManagedResourceMode || change.Change == nil {
+			continue
+		}
+		var action string
+		switch actions := change.Change.Actions; {
+		case actions.Replace():
+			action = "replace"
+		case actions.Create():
+			action = "create"
+		case actions.Update():
+			action = "update"
+		case actions.Delete():
+			action = "delete"
+		default:
+			continue
+		}
+		converted = append(converted, &proto.ResourceChange{
+			Address: change.Address,
+			Type:    change.Type,
+			Name:    change.Name,
+			Action:  action,
+		})
+	}
+	return converted
 }
 
 // showPlan must only be called while the lock is held.
@@ -342,6 +384,11 @@ func (e *executor) graph(ctx, killCtx context.Context) (string, error) {
 		return "", ctx.Err()
 	}
 
+	start := time.Now()
+	defer func() {
+		e.timings.stage(timingStageGraph, start, time.Now())
+	}()
+
 	var out strings.Builder
 	cmd := exec.CommandContext(killCtx, e.binaryPath, "graph") // #nosec
 	cmd.Stdout = &out
@@ -381,7 +428,7 @@ func (e *executor) apply(
 		getPlanFilePath(e.workdir),
 	}
 
-	outWriter, doneOut := provisionLogWriter(logr)
+	outWriter, doneOut := provisionLogWriter(logr, e.timingIngester(timingStageApply))
 	errWriter, doneErr := logWriter(logr, proto.LogLevel_ERROR)
 	defer func() {
 		_ = outWriter.Close()
@@ -390,7 +437,9 @@ func (e *executor) apply(
 		<-doneErr
 	}()
 
+	start := time.Now()
 	err := e.execWriteOutput(ctx, killCtx, args, env, outWriter, errWriter)
+	e.timings.stage(timingStageApply, start, time.Now())
 	if err != nil {
 		return nil, xerrors.Errorf("terraform apply: %w", err)
 	}
@@ -408,6 +457,7 @@ func (e *executor) apply(
 		Resources:             state.Resources,
 		ExternalAuthProviders: state.ExternalAuthProviders,
 		State:                 stateContent,
+		Timings:               e.timings.aggregate(),
 	}, nil
 }
 
@@ -521,14 +571,15 @@ func readAndLog(sink logSink, r io.Reader, done chan<- any, level proto.LogLevel
 // provisionLogWriter creates a WriteCloser that will log each JSON formatted terraform log.  The WriteCloser must be
 // closed by the caller to end logging, after which the returned channel will be closed to indicate that logging of the
 // written data has finished.  Failure to close the WriteCloser will leak a goroutine.
-func provisionLogWriter(sink logSink) (io.WriteCloser, <-chan any) {
+// Each log is also passed to ingest, if it's not nil.
+func provisionLogWriter(sink logSink, ingest func(terraformProvisionLog)) (io.WriteCloser, <-chan any) {
 	r, w := io.Pipe()
 	done := make(chan any)
-	go provisionReadAndLog(sink, r, done)
+	go provisionReadAndLog(sink, r, done, ingest)
 	return w, done
 }
 
-func provisionReadAndLog(sink logSink, r io.Reader, done chan<- any) {
+func provisionReadAndLog(sink logSink, r io.Reader, done chan<- any, ingest func(terraformProvisionLog)) {
 	defer close(done)
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
@@ -557,6 +608,9 @@ func provisionReadAndLog(sink logSink, r io.Reader, done chan<- any) {
 			log.Level = "info"
 			log.Message = scanner.Text()
 		}
+		if ingest != nil {
+			ingest(log)
+		}
 
 		logLevel := convertTerraformLogLevel(log.Level, sink)
 		sink.ProvisionLog(logLevel, log.Message)
@@ -591,10 +645,31 @@ func convertTerraformLogLevel(logLevel string, sink logSink) proto.LogLevel {
 }
 
 type terraformProvisionLog struct {
-	Level   string `json:"@level"`
-	Message string `json:"@message"`
+	Level     string    `json:"@level"`
+	Message   string    `json:"@message"`
+	Timestamp time.Time `json:"@timestamp"`
+	Type      string    `json:"type"`
 
-	Diagnostic *tfjson.Diagnostic `json:"diagnostic,omitempty"`
+	Diagnostic *tfjson.Diagnostic      `json:"diagnostic,omitempty"`
+	Hook       *terraformProvisionHook `json:"hook,omitempty"`
+}
+
+// terraformProvisionHook is the subset of a hook in terraform's JSON UI output
+// that's used for timings.
+type terraformProvisionHook struct {
+	Resource struct {
+		Addr            string `json:"addr"`
+		ImpliedProvider string `json:"implied_provider"`
+	} `json:"resource"`
+	Action string `json:"action"`
+}
+
+// timingIngester returns a function that records resource timings for a
+// stage from terraform logs.
+func (e *executor) timingIngester(stage string) func(terraformProvisionLog) {
+	return func(log terraformProvisionLog) {
+		e.timings.ingest(stage, log)
+	}
 }
 
 // syncWriter wraps an io.Writer in a sync.Mutex.
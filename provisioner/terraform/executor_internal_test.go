@@ -173,3 +173,51 @@ func TestOnlyDataResources(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertResourceChanges(t *testing.T) {
+	t.Parallel()
+
+	changes := []*tfjson.ResourceChange{
+		{
+			Address: "docker_volume.home", Mode: tfjson.ManagedResourceMode, Type: "docker_volume", Name: "home",
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}},
+		},
+		{
+			Address: "docker_container.workspace[0]", Mode: tfjson.ManagedResourceMode, Type: "docker_container", Name: "workspace",
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionCreate, tfjson.ActionDelete}},
+		},
+		{
+			Address: "coder_agent.main", Mode: tfjson.ManagedResourceMode, Type: "coder_agent", Name: "main",
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}},
+		},
+		{
+			Address: "coder_app.code", Mode: tfjson.ManagedResourceMode, Type: "coder_app", Name: "code",
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}},
+		},
+		{
+			Address: "coder_app.web", Mode: tfjson.ManagedResourceMode, Type: "coder_app", Name: "web",
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionCreate}},
+		},
+		{
+			Address: "coder_metadata.info", Mode: tfjson.ManagedResourceMode, Type: "coder_metadata", Name: "info",
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete}},
+		},
+		{
+			Address: "data.coder_parameter.region", Mode: tfjson.DataResourceMode, Type: "coder_parameter", Name: "region",
+			Change: &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionRead}},
+		},
+	}
+
+	converted := convertResourceChanges(changes)
+	actions := make(map[string]string, len(converted))
+	for _, change := range converted {
+		actions[change.Address] = change.Action
+	}
+	require.Equal(t, map[string]string{
+		"docker_volume.home":            "replace",
+		"docker_container.workspace[0]": "replace",
+		"coder_app.code":                "update",
+		"coder_app.web":                 "create",
+		"coder_metadata.info":           "delete",
+	}, actions)
+}
@@ -0,0 +1,535 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcled"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisioner"
+)
+
+// Lint statically analyzes the Terraform files in dir for mistakes that
+// Terraform accepts but that break or degrade Coder workspaces. It neither
+// runs Terraform nor downloads providers, so only literal values are
+// checked; anything computed from variables or other resources is skipped.
+//
+// An error is only returned if the directory can't be read. Problems with
+// the template itself, including HCL syntax errors, are returned as
+// diagnostics ordered by their position in the source.
+func Lint(dir string) ([]*tfjson.Diagnostic, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, xerrors.Errorf("glob terraform files: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, xerrors.Errorf("no Terraform files found in %q", dir)
+	}
+
+	l := &linter{parser: hclparse.NewParser()}
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", path, err)
+		}
+		file, diags := l.parser.ParseHCL(src, filepath.Base(path))
+		l.addHCL(diags)
+		if diags.HasErrors() {
+			continue
+		}
+		body, ok := file.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if len(block.Labels) != 2 {
+				continue
+			}
+			switch {
+			case block.Type == "resource":
+				l.resources = append(l.resources, block)
+			case block.Type == "data" && block.Labels[0] == "coder_parameter":
+				l.parameters = append(l.parameters, block)
+			}
+		}
+	}
+
+	agents := l.checkAgents()
+	l.checkAgentReferences(agents)
+	l.checkAppSlugs()
+	l.checkParameters()
+	l.checkWorkspaceResources()
+
+	sort.SliceStable(l.diags, func(i, j int) bool {
+		a, b := l.diags[i].Range, l.diags[j].Range
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Start.Byte < b.Start.Byte
+	})
+	return l.diags, nil
+}
+
+type linter struct {
+	parser     *hclparse.Parser
+	resources  []*hclsyntax.Block
+	parameters []*hclsyntax.Block
+	diags      []*tfjson.Diagnostic
+}
+
+// checkAgents reports agents that share a name and returns every declared
+// agent by name.
+func (l *linter) checkAgents() map[string]*hclsyntax.Block {
+	agents := map[string]*hclsyntax.Block{}
+	for _, block := range l.resources {
+		if block.Labels[0] != "coder_agent" {
+			continue
+		}
+		name := block.Labels[1]
+		if existing, ok := agents[name]; ok {
+			l.add(tfjson.DiagnosticSeverityError, block.DefRange(), "Duplicate agent name",
+				"The agent name %q is already used by the agent declared at %s. Agent names must be unique within a template.",
+				name, existing.DefRange())
+			continue
+		}
+		agents[name] = block
+	}
+	return agents
+}
+
+// checkAgentReferences reports apps, scripts and environment variables
+// whose agent_id points at an agent that isn't declared.
+func (l *linter) checkAgentReferences(agents map[string]*hclsyntax.Block) {
+	for _, block := range l.resources {
+		switch block.Labels[0] {
+		case "coder_app", "coder_script", "coder_env":
+		default:
+			continue
+		}
+		attr, ok := block.Body.Attributes["agent_id"]
+		if !ok {
+			continue
+		}
+		for _, traversal := range attr.Expr.Variables() {
+			name, ok := agentName(traversal)
+			if !ok {
+				continue
+			}
+			if _, ok := agents[name]; !ok {
+				l.add(tfjson.DiagnosticSeverityError, attr.SrcRange, "Reference to undeclared agent",
+					"%s.%s refers to the agent %q, which is not declared in this template.",
+					block.Labels[0], block.Labels[1], name)
+			}
+		}
+	}
+}
+
+// checkAppSlugs reports invalid and duplicate coder_app slugs. Slugs are
+// used in app URLs, so they must be unique across the whole template.
+func (l *linter) checkAppSlugs() {
+	slugs := map[string]*hclsyntax.Block{}
+	for _, block := range l.resources {
+		if block.Labels[0] != "coder_app" {
+			continue
+		}
+		rng := block.DefRange()
+		slug := block.Labels[1]
+		if attr, ok := block.Body.Attributes["slug"]; ok {
+			rng = attr.SrcRange
+			slug, ok = literalString(attr)
+			if !ok {
+				continue
+			}
+		}
+		if !provisioner.AppSlugRegex.MatchString(slug) {
+			l.add(tfjson.DiagnosticSeverityError, rng, "Invalid app slug",
+				"The app slug %q must be lowercase alphanumeric, may contain single hyphens between characters, and must match %q.",
+				slug, provisioner.AppSlugRegex.String())
+			continue
+		}
+		if existing, ok := slugs[slug]; ok {
+			l.add(tfjson.DiagnosticSeverityError, rng, "Duplicate app slug",
+				"The app slug %q is already used by coder_app.%s. App slugs must be unique within a template.",
+				slug, existing.Labels[1])
+			continue
+		}
+		slugs[slug] = block
+	}
+}
+
+// checkParameters reports coder_parameter data sources that will be
+// rejected when the template is imported or when a workspace is created.
+func (l *linter) checkParameters() {
+	names := map[string]*hclsyntax.Block{}
+	for _, block := range l.parameters {
+		attrs := block.Body.Attributes
+		label := "coder_parameter." + block.Labels[1]
+
+		if attr, ok := attrs["name"]; ok {
+			if name, ok := literalString(attr); ok {
+				if existing, ok := names[name]; ok {
+					l.add(tfjson.DiagnosticSeverityError, attr.SrcRange, "Duplicate parameter name",
+						"The parameter name %q is already used by coder_parameter.%s. coder_parameter names must be unique.",
+						name, existing.Labels[1])
+				} else {
+					names[name] = block
+				}
+			}
+		}
+
+		typ := "string"
+		if attr, ok := attrs["type"]; ok {
+			var known bool
+			typ, known = literalString(attr)
+			if !known {
+				// Every type-dependent check below would be a guess.
+				continue
+			}
+			switch typ {
+			case "string", "number", "bool", "list(string)":
+			default:
+				l.add(tfjson.DiagnosticSeverityError, attr.SrcRange, "Invalid parameter type",
+					"%s has type %q, but the type must be one of \"string\", \"number\", \"bool\" or \"list(string)\".", label, typ)
+				continue
+			}
+		}
+
+		defaultAttr, hasDefault := attrs["default"]
+		var defaultValue string
+		var defaultKnown bool
+		if hasDefault {
+			defaultValue, defaultKnown = literalString(defaultAttr)
+		}
+
+		if ephemeral, ok := literalBool(attrs["ephemeral"]); ok && ephemeral {
+			rng := attrs["ephemeral"].SrcRange
+			if mutable, _ := literalBool(attrs["mutable"]); !mutable {
+				l.add(tfjson.DiagnosticSeverityError, rng, "Ephemeral parameter must be mutable",
+					"%s is ephemeral, so its value is reset on every build. Set mutable = true so users can change it.", label)
+			}
+			if !hasDefault {
+				l.add(tfjson.DiagnosticSeverityError, rng, "Ephemeral parameter requires a default",
+					"%s is ephemeral, so it needs a default value to fall back to on builds that don't set it.", label)
+			}
+		}
+
+		if typ == "number" && defaultKnown {
+			if _, err := strconv.ParseFloat(defaultValue, 64); err != nil {
+				l.add(tfjson.DiagnosticSeverityError, defaultAttr.SrcRange, "Invalid default value",
+					"%s is a number parameter, but its default value %q is not a number.", label, defaultValue)
+				defaultKnown = false
+			}
+		}
+
+		var options []string
+		optionsKnown := true
+		for _, option := range block.Body.Blocks {
+			if option.Type != "option" {
+				continue
+			}
+			value, ok := literalString(option.Body.Attributes["value"])
+			if !ok {
+				optionsKnown = false
+				break
+			}
+			options = append(options, value)
+		}
+		if len(options) > 0 && optionsKnown && defaultKnown && typ != "list(string)" {
+			found := false
+			for _, option := range options {
+				if option == defaultValue {
+					found = true
+					break
+				}
+			}
+			if !found {
+				l.add(tfjson.DiagnosticSeverityError, defaultAttr.SrcRange, "Default value is not an option",
+					"The default value %q of %s does not match the value of any of its options.", defaultValue, label)
+			}
+		}
+
+		for _, validation := range block.Body.Blocks {
+			if validation.Type != "validation" {
+				continue
+			}
+			l.checkParameterValidation(label, typ, validation, defaultAttr, defaultValue, defaultKnown)
+		}
+	}
+}
+
+func (l *linter) checkParameterValidation(label, typ string, validation *hclsyntax.Block, defaultAttr *hclsyntax.Attribute, defaultValue string, defaultKnown bool) {
+	attrs := validation.Body.Attributes
+
+	if attr, ok := attrs["regex"]; ok {
+		if typ != "string" {
+			l.add(tfjson.DiagnosticSeverityError, attr.SrcRange, "Regex validation requires a string parameter",
+				"%s has type %q, but a regex can only validate string parameters.", label, typ)
+		} else if pattern, ok := literalString(attr); ok {
+			re, err := regexp.Compile(pattern)
+			switch {
+			case err != nil:
+				l.add(tfjson.DiagnosticSeverityError, attr.SrcRange, "Invalid validation regex",
+					"The validation regex of %s does not compile: %s.", label, err)
+			case defaultKnown && !re.MatchString(defaultValue):
+				l.add(tfjson.DiagnosticSeverityError, defaultAttr.SrcRange, "Default value fails validation",
+					"The default value %q of %s does not match the validation regex %q.", defaultValue, label, pattern)
+			}
+		}
+	}
+
+	for _, name := range []string{"min", "max", "monotonic"} {
+		attr, ok := attrs[name]
+		if !ok || typ == "number" {
+			continue
+		}
+		l.add(tfjson.DiagnosticSeverityError, attr.SrcRange, "Numeric validation requires a number parameter",
+			"%s has type %q, but %q can only validate number parameters.", label, typ, name)
+	}
+	if typ != "number" {
+		return
+	}
+
+	minValue, hasMin := literalNumber(attrs["min"])
+	maxValue, hasMax := literalNumber(attrs["max"])
+	if hasMin && hasMax && minValue > maxValue {
+		l.add(tfjson.DiagnosticSeverityError, attrs["min"].SrcRange, "Invalid validation range",
+			"The validation min of %s (%g) is greater than its max (%g).", label, minValue, maxValue)
+		return
+	}
+	if !defaultKnown {
+		return
+	}
+	// The default was already checked to be a valid number.
+	value, _ := strconv.ParseFloat(defaultValue, 64)
+	if hasMin && value < minValue {
+		l.add(tfjson.DiagnosticSeverityError, defaultAttr.SrcRange, "Default value fails validation",
+			"The default value %s of %s is less than the validation min of %g.", defaultValue, label, minValue)
+	}
+	if hasMax && value > maxValue {
+		l.add(tfjson.DiagnosticSeverityError, defaultAttr.SrcRange, "Default value fails validation",
+			"The default value %s of %s is greater than the validation max of %g.", defaultValue, label, maxValue)
+	}
+}
+
+// checkWorkspaceResources warns about resources that run an agent but have
+// no count or for_each. Those resources can't be destroyed when the
+// workspace stops, so they keep running (and costing money) until the
+// workspace is deleted.
+//
+// Resources scaled by start_count some other way (a deployment's replicas,
+// or a service running a task definition) and templates that stop their
+// resources out of band based on the workspace transition are left alone.
+func (l *linter) checkWorkspaceResources() {
+	// Addresses of resources that follow the workspace's start_count, either
+	// directly or because a resource that does depends on them.
+	scaled := map[string]struct{}{}
+	for _, block := range l.resources {
+		traversals := bodyVariables(block.Body)
+		follows := false
+		for _, traversal := range traversals {
+			switch workspaceAttribute(traversal) {
+			case "transition":
+				return
+			case "start_count":
+				follows = true
+			}
+		}
+		if !follows {
+			continue
+		}
+		scaled[block.Labels[0]+"."+block.Labels[1]] = struct{}{}
+		for _, traversal := range traversals {
+			if len(traversal) < 2 {
+				continue
+			}
+			if attr, ok := traversal[1].(hcl.TraverseAttr); ok {
+				scaled[traversal.RootName()+"."+attr.Name] = struct{}{}
+			}
+		}
+	}
+
+	for _, block := range l.resources {
+		if _, ok := block.Body.Attributes["count"]; ok {
+			continue
+		}
+		if _, ok := block.Body.Attributes["for_each"]; ok {
+			continue
+		}
+		if _, ok := scaled[block.Labels[0]+"."+block.Labels[1]]; ok {
+			continue
+		}
+		agent := ""
+		for _, traversal := range bodyVariables(block.Body) {
+			name, ok := agentName(traversal)
+			if !ok || len(traversal) < 3 {
+				continue
+			}
+			attr, ok := traversal[2].(hcl.TraverseAttr)
+			if ok && (attr.Name == "token" || attr.Name == "init_script") {
+				agent = name
+				break
+			}
+		}
+		if agent == "" {
+			continue
+		}
+		l.add(tfjson.DiagnosticSeverityWarning, block.DefRange(), "Workspace resource has no count",
+			"%s.%s runs the %q agent but has no count or for_each, so it will keep running after the workspace is stopped. "+
+				"Set count = data.coder_workspace.me.start_count to stop it with the workspace.",
+			block.Labels[0], block.Labels[1], agent)
+	}
+}
+
+func (l *linter) add(severity tfjson.DiagnosticSeverity, rng hcl.Range, summary, format string, args ...any) {
+	l.diags = append(l.diags, l.diagnostic(severity, &rng, summary, fmt.Sprintf(format, args...)))
+}
+
+func (l *linter) addHCL(diags hcl.Diagnostics) {
+	for _, diag := range diags {
+		severity := tfjson.DiagnosticSeverityError
+		if diag.Severity == hcl.DiagWarning {
+			severity = tfjson.DiagnosticSeverityWarning
+		}
+		l.diags = append(l.diags, l.diagnostic(severity, diag.Subject, diag.Summary, diag.Detail))
+	}
+}
+
+// diagnostic builds a diagnostic with the same range and snippet that
+// Terraform itself would report, so FormatDiagnostic renders it the same
+// way as a failed plan.
+func (l *linter) diagnostic(severity tfjson.DiagnosticSeverity, rng *hcl.Range, summary, detail string) *tfjson.Diagnostic {
+	diag := &tfjson.Diagnostic{
+		Severity: severity,
+		Summary:  summary,
+		Detail:   detail,
+	}
+	if rng == nil {
+		return diag
+	}
+	diag.Range = &tfjson.Range{
+		Filename: rng.Filename,
+		Start:    tfjson.Pos{Line: rng.Start.Line, Column: rng.Start.Column, Byte: rng.Start.Byte},
+		End:      tfjson.Pos{Line: rng.End.Line, Column: rng.End.Column, Byte: rng.End.Byte},
+	}
+
+	file, ok := l.parser.Files()[rng.Filename]
+	if !ok || rng.End.Byte > len(file.Bytes) || rng.Start.Byte > rng.End.Byte {
+		return diag
+	}
+	src := file.Bytes
+	start := rng.Start.Byte
+	for start > 0 && src[start-1] != '\n' {
+		start--
+	}
+	end := rng.End.Byte
+	for end < len(src) && src[end] != '\n' {
+		end++
+	}
+	diag.Snippet = &tfjson.DiagnosticSnippet{
+		Code:                 string(src[start:end]),
+		StartLine:            rng.Start.Line,
+		HighlightStartOffset: rng.Start.Byte - start,
+		HighlightEndOffset:   rng.End.Byte - start,
+	}
+	if context := hcled.ContextString(file, rng.Start.Byte); context != "" {
+		diag.Snippet.Context = &context
+	}
+	return diag
+}
+
+// agentName returns the agent name if the traversal refers to a
+// coder_agent resource, e.g. coder_agent.main.id.
+func agentName(traversal hcl.Traversal) (string, bool) {
+	if traversal.RootName() != "coder_agent" || len(traversal) < 2 {
+		return "", false
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return attr.Name, true
+}
+
+// workspaceAttribute returns the attribute read from a coder_workspace data
+// source, e.g. "start_count" for data.coder_workspace.me.start_count.
+func workspaceAttribute(traversal hcl.Traversal) string {
+	if traversal.RootName() != "data" || len(traversal) < 4 {
+		return ""
+	}
+	typ, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok || typ.Name != "coder_workspace" {
+		return ""
+	}
+	attr, ok := traversal[3].(hcl.TraverseAttr)
+	if !ok {
+		return ""
+	}
+	return attr.Name
+}
+
+func bodyVariables(body *hclsyntax.Body) []hcl.Traversal {
+	var traversals []hcl.Traversal
+	for _, attr := range body.Attributes {
+		traversals = append(traversals, attr.Expr.Variables()...)
+	}
+	for _, block := range body.Blocks {
+		traversals = append(traversals, bodyVariables(block.Body)...)
+	}
+	return traversals
+}
+
+// literal evaluates attr without any variables in scope, so it only
+// succeeds for values written out in the template.
+func literal(attr *hclsyntax.Attribute, typ cty.Type) (cty.Value, bool) {
+	if attr == nil {
+		return cty.NilVal, false
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || !value.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	value, err := convert.Convert(value, typ)
+	if err != nil {
+		return cty.NilVal, false
+	}
+	return value, true
+}
+
+func literalString(attr *hclsyntax.Attribute) (string, bool) {
+	value, ok := literal(attr, cty.String)
+	if !ok {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+func literalBool(attr *hclsyntax.Attribute) (bool, bool) {
+	value, ok := literal(attr, cty.Bool)
+	if !ok {
+		return false, false
+	}
+	return value.True(), true
+}
+
+func literalNumber(attr *hclsyntax.Attribute) (float64, bool) {
+	value, ok := literal(attr, cty.Number)
+	if !ok {
+		return 0, false
+	}
+	f, _ := value.AsBigFloat().Float64()
+	return f, true
+}
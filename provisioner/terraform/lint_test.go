@@ -0,0 +1,303 @@
+package terraform_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisioner/terraform"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	type diagnostic struct {
+		severity tfjson.DiagnosticSeverity
+		summary  string
+		line     int
+	}
+
+	tests := map[string]struct {
+		files    map[string]string
+		expected []diagnostic
+	}{
+		"Clean": {
+			files: map[string]string{
+				"main.tf": `
+data "coder_workspace" "me" {}
+
+resource "coder_agent" "main" {
+  os   = "linux"
+  arch = "amd64"
+}
+
+resource "coder_app" "code" {
+  agent_id = coder_agent.main.id
+  slug     = "code-server"
+}
+
+data "coder_parameter" "region" {
+  name    = "region"
+  default = "us"
+  option {
+    name  = "US"
+    value = "us"
+  }
+  option {
+    name  = "EU"
+    value = "eu"
+  }
+}
+
+resource "docker_container" "workspace" {
+  count   = data.coder_workspace.me.start_count
+  command = ["sh", "-c", coder_agent.main.init_script]
+}
+`,
+			},
+		},
+		"SyntaxError": {
+			files: map[string]string{
+				"main.tf": `resource "coder_agent" "main" {`,
+			},
+			expected: []diagnostic{
+				{tfjson.DiagnosticSeverityError, "Unclosed configuration block", 1},
+			},
+		},
+		"UndeclaredAgent": {
+			files: map[string]string{
+				"main.tf": `
+resource "coder_agent" "main" {}
+
+resource "coder_app" "code" {
+  agent_id = coder_agent.dev.id
+  slug     = "code"
+}
+
+resource "coder_script" "setup" {
+  agent_id = coder_agent.main.id
+}
+`,
+			},
+			expected: []diagnostic{
+				{tfjson.DiagnosticSeverityError, "Reference to undeclared agent", 5},
+			},
+		},
+		"DuplicateAppSlug": {
+			// Slugs must be unique across files too.
+			files: map[string]string{
+				"main.tf": `
+resource "coder_agent" "main" {}
+
+resource "coder_app" "code" {
+  agent_id = coder_agent.main.id
+  slug     = "code"
+}
+`,
+				"other.tf": `
+resource "coder_app" "code2" {
+  agent_id = coder_agent.main.id
+  slug     = "code"
+}
+
+resource "coder_app" "Bad_Slug" {
+  agent_id = coder_agent.main.id
+}
+`,
+			},
+			expected: []diagnostic{
+				{tfjson.DiagnosticSeverityError, "Duplicate app slug", 4},
+				{tfjson.DiagnosticSeverityError, "Invalid app slug", 7},
+			},
+		},
+		"Parameters": {
+			files: map[string]string{
+				"main.tf": `
+data "coder_parameter" "a" {
+  name    = "a"
+  default = "c"
+  option {
+    name  = "A"
+    value = "a"
+  }
+}
+
+data "coder_parameter" "b" {
+  name    = "a"
+  type    = "number"
+  default = 12
+  validation {
+    min = 1
+    max = 10
+  }
+}
+
+data "coder_parameter" "c" {
+  name    = "c"
+  default = "UPPER"
+  validation {
+    regex = "^[a-z]+$"
+    min   = 1
+  }
+}
+
+data "coder_parameter" "d" {
+  name      = "d"
+  type      = "bool"
+  ephemeral = true
+  validation {
+    regex = ".*"
+  }
+}
+
+data "coder_parameter" "e" {
+  name    = "e"
+  type    = "number"
+  default = var.default
+  validation {
+    min = 1
+  }
+}
+`,
+			},
+			expected: []diagnostic{
+				{tfjson.DiagnosticSeverityError, "Default value is not an option", 4},
+				{tfjson.DiagnosticSeverityError, "Duplicate parameter name", 12},
+				{tfjson.DiagnosticSeverityError, "Default value fails validation", 14},
+				{tfjson.DiagnosticSeverityError, "Default value fails validation", 23},
+				{tfjson.DiagnosticSeverityError, "Numeric validation requires a number parameter", 26},
+				{tfjson.DiagnosticSeverityError, "Ephemeral parameter must be mutable", 33},
+				{tfjson.DiagnosticSeverityError, "Ephemeral parameter requires a default", 33},
+				{tfjson.DiagnosticSeverityError, "Regex validation requires a string parameter", 35},
+			},
+		},
+		"MissingCount": {
+			files: map[string]string{
+				"main.tf": `
+resource "coder_agent" "main" {}
+
+resource "docker_volume" "home" {
+  name = "home"
+}
+
+resource "docker_container" "workspace" {
+  env = ["CODER_AGENT_TOKEN=${coder_agent.main.token}"]
+}
+`,
+			},
+			expected: []diagnostic{
+				{tfjson.DiagnosticSeverityWarning, "Workspace resource has no count", 8},
+			},
+		},
+		"ScalesWithStartCount": {
+			files: map[string]string{
+				"main.tf": `
+resource "coder_agent" "main" {}
+
+resource "kubernetes_deployment" "workspace" {
+  spec {
+    replicas = data.coder_workspace.me.start_count
+  }
+  env = ["CODER_AGENT_TOKEN=${coder_agent.main.token}"]
+}
+`,
+			},
+		},
+		"ScaledByService": {
+			files: map[string]string{
+				"main.tf": `
+resource "coder_agent" "main" {}
+
+resource "aws_ecs_task_definition" "workspace" {
+  container_definitions = jsonencode([{
+    command = ["sh", "-c", coder_agent.main.init_script]
+  }])
+}
+
+resource "aws_ecs_service" "workspace" {
+  task_definition = aws_ecs_task_definition.workspace.arn
+  desired_count   = data.coder_workspace.me.start_count
+}
+`,
+			},
+		},
+		"StoppedByTransition": {
+			files: map[string]string{
+				"main.tf": `
+resource "coder_agent" "main" {}
+
+resource "azurerm_windows_virtual_machine" "main" {
+  custom_data = base64encode(coder_agent.main.init_script)
+}
+
+resource "null_resource" "stop_vm" {
+  count = data.coder_workspace.me.transition == "stop" ? 1 : 0
+}
+`,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			for name, content := range tc.files {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+				require.NoError(t, err)
+			}
+
+			diags, err := terraform.Lint(dir)
+			require.NoError(t, err)
+
+			actual := make([]diagnostic, 0, len(diags))
+			for _, diag := range diags {
+				actual = append(actual, diagnostic{
+					severity: diag.Severity,
+					summary:  diag.Summary,
+					line:     diag.Range.Start.Line,
+				})
+			}
+			if tc.expected == nil {
+				tc.expected = []diagnostic{}
+			}
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("Snippet", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`resource "coder_agent" "main" {}
+
+resource "coder_app" "code" {
+  agent_id = coder_agent.dev.id
+}
+`), 0o600)
+		require.NoError(t, err)
+
+		diags, err := terraform.Lint(dir)
+		require.NoError(t, err)
+		require.Len(t, diags, 1)
+		require.Equal(t, []string{
+			`on main.tf line 4, in resource "coder_app" "code":`,
+			`  4:   agent_id = coder_agent.dev.id`,
+			``,
+			`coder_app.code refers to the agent "dev", which is not declared in this template.`,
+		}, strings.Split(terraform.FormatDiagnostic(diags[0]), "\n"))
+	})
+
+	t.Run("NoFiles", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := terraform.Lint(t.TempDir())
+		require.Error(t, err)
+	})
+}
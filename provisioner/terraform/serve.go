@@ -128,5 +128,6 @@ func (s *server) executor(workdir string) *executor {
 		cachePath:  s.cachePath,
 		workdir:    workdir,
 		logger:     s.logger.Named("executor"),
+		timings:    newTimingAggregator(),
 	}
 }
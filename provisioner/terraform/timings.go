@@ -0,0 +1,93 @@
+package terraform
+
+import (
+	"sync"
+	"time"
+
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+const (
+	timingStageInit  = "init"
+	timingStagePlan  = "plan"
+	timingStageGraph = "graph"
+	timingStageApply = "apply"
+
+	// timingActionRefresh is used for resources that terraform refreshes
+	// while planning. The JSON UI doesn't include an action for them.
+	timingActionRefresh = "refresh"
+)
+
+// timingAggregator collects the time spent on each stage of provisioning, and
+// on each resource as reported by terraform's JSON UI output.
+type timingAggregator struct {
+	mu      sync.Mutex
+	started map[string]*proto.Timing
+	timings []*proto.Timing
+}
+
+func newTimingAggregator() *timingAggregator {
+	return &timingAggregator{
+		started: make(map[string]*proto.Timing),
+	}
+}
+
+// stage records the time spent on a whole stage.
+func (t *timingAggregator) stage(stage string, start, end time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timings = append(t.timings, &proto.Timing{
+		StartedAt: start.UnixMilli(),
+		EndedAt:   end.UnixMilli(),
+		Stage:     stage,
+		Action:    stage,
+	})
+}
+
+// ingest records resource timings from a terraform JSON UI log line. Lines
+// that aren't about a resource hook are ignored.
+func (t *timingAggregator) ingest(stage string, log terraformProvisionLog) {
+	if log.Hook == nil || log.Hook.Resource.Addr == "" || log.Timestamp.IsZero() {
+		return
+	}
+	action := log.Hook.Action
+	switch log.Type {
+	case "refresh_start", "refresh_complete":
+		action = timingActionRefresh
+	case "apply_start", "apply_complete", "apply_errored":
+	default:
+		return
+	}
+	// A resource can be refreshed and then applied, so the action is part of
+	// the key.
+	key := stage + "\x00" + action + "\x00" + log.Hook.Resource.Addr
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch log.Type {
+	case "refresh_start", "apply_start":
+		t.started[key] = &proto.Timing{
+			StartedAt: log.Timestamp.UnixMilli(),
+			Stage:     stage,
+			Source:    log.Hook.Resource.ImpliedProvider,
+			Action:    action,
+			Resource:  log.Hook.Resource.Addr,
+		}
+	default:
+		timing, ok := t.started[key]
+		if !ok {
+			return
+		}
+		delete(t.started, key)
+		timing.EndedAt = log.Timestamp.UnixMilli()
+		t.timings = append(t.timings, timing)
+	}
+}
+
+// aggregate returns the timings that have ended. Resources that never
+// finished, e.g. because the build was canceled, are left out.
+func (t *timingAggregator) aggregate() []*proto.Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*proto.Timing(nil), t.timings...)
+}
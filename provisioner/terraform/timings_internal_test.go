@@ -0,0 +1,69 @@
+package terraform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+func TestTimingAggregator(t *testing.T) {
+	t.Parallel()
+
+	// Lines taken from terraform's JSON UI output, trimmed down.
+	planLog := `{"@level":"info","@message":"data.coder_workspace.me: Refreshing...","@timestamp":"2024-01-02T10:00:00.000000Z","hook":{"resource":{"addr":"data.coder_workspace.me","implied_provider":"coder"}},"type":"refresh_start"}
+{"@level":"info","@message":"data.coder_workspace.me: Refresh complete after 0s","@timestamp":"2024-01-02T10:00:00.250000Z","hook":{"resource":{"addr":"data.coder_workspace.me","implied_provider":"coder"}},"type":"refresh_complete"}
+{"@level":"info","@message":"Plan: 1 to add, 0 to change, 0 to destroy.","@timestamp":"2024-01-02T10:00:01.000000Z","type":"change_summary"}
+`
+	applyLog := `{"@level":"info","@message":"docker_container.workspace[0]: Creating...","@timestamp":"2024-01-02T10:00:02.000000Z","hook":{"resource":{"addr":"docker_container.workspace[0]","implied_provider":"docker"},"action":"create"},"type":"apply_start"}
+{"@level":"info","@message":"docker_volume.home: Creating...","@timestamp":"2024-01-02T10:00:02.000000Z","hook":{"resource":{"addr":"docker_volume.home","implied_provider":"docker"},"action":"create"},"type":"apply_start"}
+{"@level":"info","@message":"docker_container.workspace[0]: Creation complete after 3s","@timestamp":"2024-01-02T10:00:05.000000Z","hook":{"resource":{"addr":"docker_container.workspace[0]","implied_provider":"docker"},"action":"create","elapsed_seconds":3},"type":"apply_complete"}
+not json at all
+`
+
+	timings := newTimingAggregator()
+	for _, stage := range []struct {
+		name string
+		log  string
+	}{
+		{timingStagePlan, planLog},
+		{timingStageApply, applyLog},
+	} {
+		w, done := provisionLogWriter(&mockLogger{}, func(log terraformProvisionLog) {
+			timings.ingest(stage.name, log)
+		})
+		_, err := w.Write([]byte(stage.log))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		<-done
+	}
+	start := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	timings.stage(timingStageInit, start.Add(-time.Second), start)
+
+	got := timings.aggregate()
+	// The volume never finished, so it isn't included.
+	require.Len(t, got, 3)
+	byResource := map[string]*proto.Timing{}
+	for _, timing := range got {
+		byResource[timing.Resource] = timing
+	}
+
+	refresh := byResource["data.coder_workspace.me"]
+	require.Equal(t, timingStagePlan, refresh.Stage)
+	require.Equal(t, timingActionRefresh, refresh.Action)
+	require.Equal(t, "coder", refresh.Source)
+	require.EqualValues(t, 250, refresh.EndedAt-refresh.StartedAt)
+
+	container := byResource["docker_container.workspace[0]"]
+	require.Equal(t, timingStageApply, container.Stage)
+	require.Equal(t, "create", container.Action)
+	require.Equal(t, "docker", container.Source)
+	require.EqualValues(t, 3000, container.EndedAt-container.StartedAt)
+
+	init := byResource[""]
+	require.Equal(t, timingStageInit, init.Stage)
+	require.Equal(t, start.UnixMilli(), init.EndedAt)
+	require.Equal(t, timingStageInit, init.Action)
+}
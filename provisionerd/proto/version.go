@@ -0,0 +1,54 @@
+package proto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Version history:
+//
+// API v1.0:
+//   - Initial release.
+const (
+	CurrentMajor = 1
+	CurrentMinor = 0
+)
+
+// CurrentVersion is the provisioner daemon API version spoken by this build.
+var CurrentVersion = fmt.Sprintf("%d.%d", CurrentMajor, CurrentMinor)
+
+// VersionUnspecified is assumed for daemons that predate version
+// negotiation and don't send a version when connecting.
+const VersionUnspecified = "1.0"
+
+// NegotiateVersion returns the API version used to serve a daemon that
+// requested the given version, or an error if this build can't serve it.
+func NegotiateVersion(version string) (string, error) {
+	if version == "" {
+		return VersionUnspecified, nil
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != 2 {
+		return "", xerrors.Errorf("invalid version string: %s", version)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", xerrors.Errorf("invalid major version: %s", version)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", xerrors.Errorf("invalid minor version: %s", version)
+	}
+	if major != CurrentMajor {
+		return "", xerrors.Errorf("server is at version %s, incompatible with requested version %s",
+			CurrentVersion, version)
+	}
+	if minor > CurrentMinor {
+		// Newer daemons fall back to the features this build supports.
+		return CurrentVersion, nil
+	}
+	return fmt.Sprintf("%d.%d", major, minor), nil
+}
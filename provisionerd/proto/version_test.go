@@ -0,0 +1,67 @@
+package proto_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisionerd/proto"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name     string
+		version  string
+		expected string
+	}{
+		{
+			name:     "Current",
+			version:  proto.CurrentVersion,
+			expected: proto.CurrentVersion,
+		},
+		{
+			name:     "Unspecified",
+			version:  "",
+			expected: proto.VersionUnspecified,
+		},
+		{
+			name:     "NewerMinor",
+			version:  fmt.Sprintf("%d.%d", proto.CurrentMajor, proto.CurrentMinor+1),
+			expected: proto.CurrentVersion,
+		},
+		{
+			name:    "NewerMajor",
+			version: fmt.Sprintf("%d.%d", proto.CurrentMajor+1, proto.CurrentMinor),
+		},
+		{
+			name:    "OlderMajor",
+			version: fmt.Sprintf("%d.%d", proto.CurrentMajor-1, proto.CurrentMinor),
+		},
+		{
+			name:    "Malformed0",
+			version: "cats",
+		},
+		{
+			name:    "Malformed1",
+			version: "cats.dogs",
+		},
+		{
+			name:    "Malformed2",
+			version: "1.0.1",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			version, err := proto.NegotiateVersion(tc.version)
+			if tc.expected == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, version)
+		})
+	}
+}
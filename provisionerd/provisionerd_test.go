@@ -331,6 +331,7 @@ func TestProvisionerd(t *testing.T) {
 				Type: &proto.AcquiredJob_TemplateDryRun_{
 					TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
 						Metadata: metadata,
+						State:    []byte("state"),
 					},
 				},
 			})
@@ -352,6 +353,11 @@ func TestProvisionerd(t *testing.T) {
 					return &proto.UpdateJobResponse{}, nil
 				},
 				completeJob: func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
+					changes := job.GetTemplateDryRun().GetResourceChanges()
+					assert.Len(t, changes, 1)
+					if len(changes) == 1 {
+						assert.Equal(t, "replace", changes[0].Action)
+					}
 					didComplete.Store(true)
 					return &proto.Empty{}, nil
 				},
@@ -359,12 +365,19 @@ func TestProvisionerd(t *testing.T) {
 		}, provisionerd.LocalProvisioners{
 			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
 				plan: func(
-					_ *provisionersdk.Session,
+					s *provisionersdk.Session,
 					_ *sdkproto.PlanRequest,
 					_ <-chan struct{},
 				) *sdkproto.PlanComplete {
+					assert.Equal(t, []byte("state"), s.Config.State)
 					return &sdkproto.PlanComplete{
 						Resources: []*sdkproto.Resource{},
+						ResourceChanges: []*sdkproto.ResourceChange{{
+							Address: "docker_volume.home",
+							Type:    "docker_volume",
+							Name:    "home",
+							Action:  "replace",
+						}},
 					}
 				},
 				apply: func(
@@ -514,6 +514,7 @@ const createTemplateVersionTar = async (
       resources: [],
       parameters: [],
       externalAuthProviders: [],
+      timings: [],
       ...response.apply,
     } as ApplyComplete;
     response.apply.resources = response.apply.resources?.map(fillResource);
@@ -529,6 +530,8 @@ const createTemplateVersionTar = async (
       resources: [],
       parameters: [],
       externalAuthProviders: [],
+      timings: [],
+      resourceChanges: [],
       ...response.plan,
     } as PlanComplete;
     response.plan.resources = response.plan.resources?.map(fillResource);
@@ -256,6 +256,8 @@ export interface PlanComplete {
   resources: Resource[];
   parameters: RichParameter[];
   externalAuthProviders: string[];
+  timings: Timing[];
+  resourceChanges: ResourceChange[];
 }
 
 /**
@@ -273,6 +275,44 @@ export interface ApplyComplete {
   resources: Resource[];
   parameters: RichParameter[];
   externalAuthProviders: string[];
+  timings: Timing[];
+}
+
+/**
+ * Timing is the time spent on a stage of provisioning, or on a single resource
+ * within a stage.
+ */
+export interface Timing {
+  /** started_at and ended_at are unix timestamps in milliseconds. */
+  startedAt: number;
+  endedAt: number;
+  /** stage is one of "init", "plan", "graph" or "apply". */
+  stage: string;
+  /**
+   * source is the provider of the resource, e.g. "docker". It is empty for
+   * timings that cover a whole stage.
+   */
+  source: string;
+  /** action is what happened to the resource, e.g. "create" or "refresh". */
+  action: string;
+  /**
+   * resource is the address of the resource. It is empty for timings that
+   * cover a whole stage.
+   */
+  resource: string;
+}
+
+/**
+ * ResourceChange is a change a plan makes to a managed resource of the
+ * existing state.
+ */
+export interface ResourceChange {
+  /** address is the address of the resource, e.g. "docker_volume.home[0]". */
+  address: string;
+  type: string;
+  name: string;
+  /** action is one of "create", "update", "replace" or "delete". */
+  action: string;
 }
 
 /** CancelRequest requests that the previous request be canceled gracefully. */
@@ -883,6 +923,12 @@ export const PlanComplete = {
     for (const v of message.externalAuthProviders) {
       writer.uint32(34).string(v!);
     }
+    for (const v of message.timings) {
+      Timing.encode(v!, writer.uint32(42).fork()).ldelim();
+    }
+    for (const v of message.resourceChanges) {
+      ResourceChange.encode(v!, writer.uint32(50).fork()).ldelim();
+    }
     return writer;
   },
 };
@@ -919,6 +965,57 @@ export const ApplyComplete = {
     for (const v of message.externalAuthProviders) {
       writer.uint32(42).string(v!);
     }
+    for (const v of message.timings) {
+      Timing.encode(v!, writer.uint32(50).fork()).ldelim();
+    }
+    return writer;
+  },
+};
+
+export const Timing = {
+  encode(
+    message: Timing,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.startedAt !== 0) {
+      writer.uint32(8).int64(message.startedAt);
+    }
+    if (message.endedAt !== 0) {
+      writer.uint32(16).int64(message.endedAt);
+    }
+    if (message.stage !== "") {
+      writer.uint32(26).string(message.stage);
+    }
+    if (message.source !== "") {
+      writer.uint32(34).string(message.source);
+    }
+    if (message.action !== "") {
+      writer.uint32(42).string(message.action);
+    }
+    if (message.resource !== "") {
+      writer.uint32(50).string(message.resource);
+    }
+    return writer;
+  },
+};
+
+export const ResourceChange = {
+  encode(
+    message: ResourceChange,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.address !== "") {
+      writer.uint32(10).string(message.address);
+    }
+    if (message.type !== "") {
+      writer.uint32(18).string(message.type);
+    }
+    if (message.name !== "") {
+      writer.uint32(26).string(message.name);
+    }
+    if (message.action !== "") {
+      writer.uint32(34).string(message.action);
+    }
     return writer;
   },
 };
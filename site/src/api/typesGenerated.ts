@@ -20,6 +20,8 @@ export interface APIKey {
   readonly scope: APIKeyScope;
   readonly token_name: string;
   readonly lifetime_seconds: number;
+  readonly scope_permissions?: Permission[];
+  readonly scope_allow_list?: string[];
 }
 
 // From codersdk/apikey.go
@@ -32,6 +34,26 @@ export interface AddLicenseRequest {
   readonly license: string;
 }
 
+// From codersdk/workspacebuilds.go
+export interface AgentConnectionTiming {
+  readonly started_at: string;
+  readonly ended_at: string;
+  readonly workspace_agent_id: string;
+  readonly workspace_agent_name: string;
+}
+
+// From codersdk/workspacebuilds.go
+export interface AgentScriptTiming {
+  readonly started_at: string;
+  readonly ended_at: string;
+  readonly exit_code: number;
+  readonly stage: WorkspaceAgentScriptTimingStage;
+  readonly status: WorkspaceAgentScriptTimingStatus;
+  readonly display_name: string;
+  readonly workspace_agent_id: string;
+  readonly workspace_agent_name: string;
+}
+
 // From codersdk/templates.go
 export interface AgentStatsReportResponse {
   readonly num_comms: number;
@@ -66,6 +88,7 @@ export interface ArchiveTemplateVersionsResponse {
 // From codersdk/roles.go
 export interface AssignableRoles extends Role {
   readonly assignable: boolean;
+  readonly built_in: boolean;
 }
 
 // From codersdk/audit.go
@@ -106,12 +129,39 @@ export interface AuditLog {
   readonly user?: User;
 }
 
+// From codersdk/audit.go
+export interface AuditLogExportRequest {
+  readonly q?: string;
+  readonly format?: AuditLogExportFormat;
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
   readonly audit_logs: AuditLog[];
   readonly count: number;
 }
 
+// From codersdk/deployment.go
+export interface AuditLoggingConfig {
+  readonly webhook: AuditLoggingWebhookConfig;
+  readonly syslog: AuditLoggingSyslogConfig;
+}
+
+// From codersdk/deployment.go
+export interface AuditLoggingSyslogConfig {
+  readonly address: string;
+  readonly tls: boolean;
+}
+
+// From codersdk/deployment.go
+export interface AuditLoggingWebhookConfig {
+  readonly url: string;
+  readonly secret: string;
+  readonly batch_size: number;
+  readonly flush_interval: number;
+  readonly max_retries: number;
+}
+
 // From codersdk/audit.go
 export interface AuditLogsRequest extends Pagination {
   readonly q?: string;
@@ -127,6 +177,7 @@ export interface AuthMethods {
   readonly password: AuthMethod;
   readonly github: AuthMethod;
   readonly oidc: OIDCAuthMethod;
+  readonly login_providers: LoginProviderAuthMethod[];
 }
 
 // From codersdk/authorization.go
@@ -204,6 +255,17 @@ export interface CreateOrganizationRequest {
   readonly name: string;
 }
 
+// From codersdk/provisionerkeys.go
+export interface CreateProvisionerKeyRequest {
+  readonly name: string;
+  readonly tags: Record<string, string>;
+}
+
+// From codersdk/provisionerkeys.go
+export interface CreateProvisionerKeyResponse {
+  readonly key: string;
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateRequest {
   readonly name: string;
@@ -230,6 +292,7 @@ export interface CreateTemplateVersionDryRunRequest {
   readonly workspace_name: string;
   readonly rich_parameter_values: WorkspaceBuildParameter[];
   readonly user_variable_values?: VariableValue[];
+  readonly workspace_id?: string;
 }
 
 // From codersdk/organizations.go
@@ -260,6 +323,8 @@ export interface CreateTokenRequest {
   readonly lifetime: number;
   readonly scope: APIKeyScope;
   readonly token_name: string;
+  readonly scope_permissions?: Permission[];
+  readonly scope_allow_list?: string[];
 }
 
 // From codersdk/users.go
@@ -281,6 +346,14 @@ export interface CreateWorkspaceBuildRequest {
   readonly orphan?: boolean;
   readonly rich_parameter_values?: WorkspaceBuildParameter[];
   readonly log_level?: ProvisionerLogLevel;
+  readonly priority?: ProvisionerJobPriority;
+}
+
+// From codersdk/workspaceportshares.go
+export interface CreateWorkspacePortShareRequest {
+  readonly agent_name: string;
+  readonly port: number;
+  readonly ttl_ms: number;
 }
 
 // From codersdk/workspaceproxy.go
@@ -417,14 +490,19 @@ export interface DeploymentValues {
   readonly disable_password_auth?: boolean;
   readonly support?: SupportConfig;
   readonly external_auth?: ExternalAuthConfig[];
+  readonly login_providers?: LoginProviderConfig[];
   readonly config_ssh?: SSHConfig;
   readonly wgtunnel_host?: string;
   readonly disable_owner_workspace_exec?: boolean;
+  readonly record_sessions?: boolean;
   readonly proxy_health_status_interval?: number;
   readonly enable_terraform_debug_mode?: boolean;
   readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig;
   readonly web_terminal_renderer?: string;
   readonly healthcheck?: HealthcheckConfig;
+  readonly notifications?: NotificationsConfig;
+  readonly audit_logging?: AuditLoggingConfig;
+  readonly retention?: RetentionConfig;
   readonly config?: string;
   readonly write_config?: boolean;
   readonly address?: string;
@@ -587,6 +665,7 @@ export interface HealthcheckConfig {
 export interface IssueReconnectingPTYSignedTokenRequest {
   readonly url: string;
   readonly agentID: string;
+  readonly mode?: ReconnectingPTYMode;
 }
 
 // From codersdk/workspaceagents.go
@@ -625,6 +704,40 @@ export interface LoggingConfig {
   readonly stackdriver: string;
 }
 
+// From codersdk/users.go
+export interface LoginProviderAuthMethod {
+  readonly id: string;
+  readonly type: LoginType;
+  readonly display_name: string;
+  readonly icon_url: string;
+  readonly callback_url: string;
+}
+
+// From codersdk/deployment.go
+export interface LoginProviderConfig {
+  readonly id: string;
+  readonly type: string;
+  readonly client_id: string;
+  readonly allow_signups: boolean;
+  readonly display_name: string;
+  readonly icon_url: string;
+  readonly issuer_url: string;
+  readonly scopes: string[];
+  readonly email_domain: string[];
+  readonly email_field: string;
+  readonly username_field: string;
+  readonly ignore_email_verified: boolean;
+  readonly ignore_user_info: boolean;
+  readonly group_field: string;
+  readonly group_mapping: Record<string, string>;
+  readonly user_role_field: string;
+  readonly user_role_mapping: Record<string, string[]>;
+  readonly allow_everyone: boolean;
+  readonly allowed_orgs: string[];
+  readonly allowed_teams: string[];
+  readonly enterprise_base_url: string;
+}
+
 // From codersdk/users.go
 export interface LoginWithPasswordRequest {
   readonly email: string;
@@ -643,6 +756,36 @@ export interface MinimalUser {
   readonly avatar_url: string;
 }
 
+// From codersdk/deployment.go
+export interface NotificationsConfig {
+  readonly method: string;
+  readonly max_send_attempts: number;
+  readonly retry_interval: number;
+  readonly dispatch_timeout: number;
+  readonly fetch_interval: number;
+  readonly lease_period: number;
+  readonly lease_count: number;
+  readonly autostop_warning: number;
+  readonly autodelete_warning: number;
+  readonly email: NotificationsEmailConfig;
+  readonly webhook: NotificationsWebhookConfig;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsEmailConfig {
+  readonly from: string;
+  readonly smarthost: string;
+  readonly hello: string;
+  readonly username: string;
+  readonly password: string;
+  readonly force_tls: boolean;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsWebhookConfig {
+  readonly endpoint: string;
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
   readonly github: OAuth2GithubConfig;
@@ -700,6 +843,48 @@ export interface OIDCConfig {
   readonly icon_url: string;
 }
 
+// From codersdk/oidcsync.go
+export interface OIDCSyncDryRunRequest {
+  // Empty interface{} type, cannot resolve the type.
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- interface{}
+  readonly claims: Record<string, any>;
+  readonly rules?: OIDCSyncRules;
+}
+
+// From codersdk/oidcsync.go
+export interface OIDCSyncDryRunResponse {
+  readonly matched_rules: string[];
+  readonly site_roles: string[];
+  readonly organizations: OIDCSyncOrganizationResult[];
+}
+
+// From codersdk/oidcsync.go
+export interface OIDCSyncOrganizationResult {
+  readonly organization_name: string;
+  readonly organization_id: string;
+  readonly roles: string[];
+  readonly groups: string[];
+  readonly quota_allowances: Record<string, number>;
+}
+
+// From codersdk/oidcsync.go
+export interface OIDCSyncRule {
+  readonly name: string;
+  readonly claim: string;
+  readonly match?: string;
+  readonly organization?: string;
+  readonly site_roles?: string[];
+  readonly organization_roles?: string[];
+  readonly groups?: string[];
+  readonly quota_allowance?: number;
+}
+
+// From codersdk/oidcsync.go
+export interface OIDCSyncRules {
+  readonly sync_roles: boolean;
+  readonly rules: OIDCSyncRule[];
+}
+
 // From codersdk/organizations.go
 export interface Organization {
   readonly id: string;
@@ -717,6 +902,11 @@ export interface OrganizationMember {
   readonly roles: Role[];
 }
 
+// From codersdk/organizations.go
+export interface OrganizationMemberWithName extends OrganizationMember {
+  readonly username: string;
+}
+
 // From codersdk/pagination.go
 export interface Pagination {
   readonly after_id?: string;
@@ -749,6 +939,13 @@ export interface PatchWorkspaceProxy {
   readonly regenerate_token: boolean;
 }
 
+// From codersdk/roles.go
+export interface Permission {
+  readonly negate: boolean;
+  readonly resource_type: RBACResource;
+  readonly action: string;
+}
+
 // From codersdk/deployment.go
 export interface PprofConfig {
   readonly enable: boolean;
@@ -771,6 +968,8 @@ export interface ProvisionerConfig {
   readonly daemon_poll_jitter: number;
   readonly force_cancel_interval: number;
   readonly daemon_psk: string;
+  readonly max_concurrent_jobs_per_user: number;
+  readonly max_concurrent_jobs_per_template: number;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -782,6 +981,8 @@ export interface ProvisionerDaemon {
   readonly version: string;
   readonly provisioners: ProvisionerType[];
   readonly tags: Record<string, string>;
+  readonly key_id?: string;
+  readonly key_name?: string;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -799,6 +1000,7 @@ export interface ProvisionerJob {
   readonly tags: Record<string, string>;
   readonly queue_position: number;
   readonly queue_size: number;
+  readonly priority: ProvisionerJobPriority;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -811,6 +1013,26 @@ export interface ProvisionerJobLog {
   readonly output: string;
 }
 
+// From codersdk/provisionerkeys.go
+export interface ProvisionerKey {
+  readonly id: string;
+  readonly created_at: string;
+  readonly organization_id: string;
+  readonly name: string;
+  readonly tags: Record<string, string>;
+}
+
+// From codersdk/workspacebuilds.go
+export interface ProvisionerTiming {
+  readonly job_id: string;
+  readonly started_at: string;
+  readonly ended_at: string;
+  readonly stage: ProvisionerTimingStage;
+  readonly source: string;
+  readonly action: string;
+  readonly resource: string;
+}
+
 // From codersdk/workspaceproxy.go
 export interface ProxyHealthReport {
   readonly errors: string[];
@@ -860,6 +1082,14 @@ export interface ResolveAutostartResponse {
   readonly parameter_mismatch: boolean;
 }
 
+// From codersdk/templateversions.go
+export interface ResourceChange {
+  readonly address: string;
+  readonly type: string;
+  readonly name: string;
+  readonly action: ResourceChangeAction;
+}
+
 // From codersdk/client.go
 export interface Response {
   readonly message: string;
@@ -867,10 +1097,22 @@ export interface Response {
   readonly validations?: ValidationError[];
 }
 
+// From codersdk/deployment.go
+export interface RetentionConfig {
+  readonly audit_logs: number;
+  readonly provisioner_job_logs: number;
+  readonly workspace_builds: number;
+  readonly session_recordings: number;
+  readonly dry_run: boolean;
+}
+
 // From codersdk/roles.go
 export interface Role {
   readonly name: string;
   readonly display_name: string;
+  readonly site_permissions?: Permission[];
+  readonly organization_permissions?: Permission[];
+  readonly user_permissions?: Permission[];
 }
 
 // From codersdk/deployment.go
@@ -971,6 +1213,7 @@ export interface Template {
   readonly time_til_dormant_ms: number;
   readonly time_til_dormant_autodelete_ms: number;
   readonly require_active_version: boolean;
+  readonly record_sessions: boolean;
 }
 
 // From codersdk/templates.go
@@ -1017,6 +1260,24 @@ export interface TemplateExample {
   readonly markdown: string;
 }
 
+// From codersdk/templategitsources.go
+export interface TemplateGitSource {
+  readonly template_id: string;
+  readonly repository_url: string;
+  readonly branch: string;
+  readonly subdirectory: string;
+  readonly auto_promote: boolean;
+  readonly poll_interval_ms: number;
+  readonly webhook_url: string;
+  readonly webhook_secret: string;
+  readonly last_checked_at?: string;
+  readonly last_commit_sha: string;
+  readonly last_template_version_id?: string;
+  readonly last_error: string;
+  readonly created_at: string;
+  readonly updated_at: string;
+}
+
 // From codersdk/templates.go
 export interface TemplateGroup extends Group {
   readonly role: TemplateRole;
@@ -1091,6 +1352,7 @@ export interface TemplateVersion {
   readonly readme: string;
   readonly created_by: MinimalUser;
   readonly archived: boolean;
+  readonly git_commit?: TemplateVersionGitCommit;
   readonly warnings?: TemplateVersionWarning[];
 }
 
@@ -1104,6 +1366,12 @@ export interface TemplateVersionExternalAuth {
   readonly authenticated: boolean;
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionGitCommit {
+  readonly sha: string;
+  readonly message: string;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionParameter {
   readonly name: string;
@@ -1208,6 +1476,16 @@ export interface UpdateTemplateACL {
   readonly group_perms?: Record<string, TemplateRole>;
 }
 
+// From codersdk/templategitsources.go
+export interface UpdateTemplateGitSourceRequest {
+  readonly repository_url: string;
+  readonly branch: string;
+  readonly subdirectory?: string;
+  readonly auto_promote?: boolean;
+  readonly poll_interval_ms?: number;
+  readonly regenerate_webhook_secret?: boolean;
+}
+
 // From codersdk/templates.go
 export interface UpdateTemplateMeta {
   readonly name?: string;
@@ -1228,6 +1506,7 @@ export interface UpdateTemplateMeta {
   readonly update_workspace_dormant_at: boolean;
   readonly require_active_version: boolean;
   readonly deprecation_message?: string;
+  readonly record_sessions?: boolean;
 }
 
 // From codersdk/users.go
@@ -1251,6 +1530,12 @@ export interface UpdateUserQuietHoursScheduleRequest {
   readonly schedule: string;
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceACL {
+  readonly user_roles?: Record<string, WorkspaceRole>;
+  readonly group_roles?: Record<string, WorkspaceRole>;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutomaticUpdatesRequest {
   readonly automatic_updates: AutomaticUpdates;
@@ -1431,6 +1716,12 @@ export interface Workspace {
   readonly automatic_updates: AutomaticUpdates;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceACL {
+  readonly users: WorkspaceUser[];
+  readonly groups: WorkspaceGroup[];
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgent {
   readonly id: string;
@@ -1467,6 +1758,22 @@ export interface WorkspaceAgent {
   readonly startup_script_behavior: WorkspaceAgentStartupScriptBehavior;
 }
 
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentExecFrame {
+  readonly type: WorkspaceAgentExecFrameType;
+  readonly data?: string;
+  readonly exit_code?: number;
+  readonly error?: string;
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentExecRequest {
+  readonly command: string;
+  readonly env?: Record<string, string>;
+  readonly dir?: string;
+  readonly timeout_ms?: number;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentHealth {
   readonly healthy: boolean;
@@ -1526,6 +1833,20 @@ export interface WorkspaceAgentMetadataResult {
   readonly error: string;
 }
 
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentReconnectingPTY {
+  readonly id: string;
+  readonly command: string;
+  readonly created_at: string;
+  readonly connections: number;
+  readonly observers: number;
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentReconnectingPTYsResponse {
+  readonly sessions: WorkspaceAgentReconnectingPTY[];
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentScript {
   readonly log_source_id: string;
@@ -1584,6 +1905,13 @@ export interface WorkspaceBuildParameter {
   readonly value: string;
 }
 
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildTimings {
+  readonly provisioner_timings: ProvisionerTiming[];
+  readonly agent_script_timings: AgentScriptTiming[];
+  readonly agent_connection_timings: AgentConnectionTiming[];
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceBuildsRequest extends Pagination {
   readonly since?: string;
@@ -1612,6 +1940,15 @@ export interface WorkspaceFilter {
   readonly q?: string;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceGroup {
+  readonly id: string;
+  readonly name: string;
+  readonly display_name: string;
+  readonly avatar_url: string;
+  readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceHealth {
   readonly healthy: boolean;
@@ -1623,6 +1960,18 @@ export interface WorkspaceOptions {
   readonly include_deleted?: boolean;
 }
 
+// From codersdk/workspaceportshares.go
+export interface WorkspacePortShare {
+  readonly id: string;
+  readonly workspace_id: string;
+  readonly agent_name: string;
+  readonly port: number;
+  readonly created_by: string;
+  readonly created_at: string;
+  readonly expires_at: string;
+  readonly url: string;
+}
+
 // From codersdk/workspaceproxy.go
 export interface WorkspaceProxy extends Region {
   readonly derp_enabled: boolean;
@@ -1675,6 +2024,23 @@ export interface WorkspaceResourceMetadata {
   readonly sensitive: boolean;
 }
 
+// From codersdk/workspacesessionrecordings.go
+export interface WorkspaceSessionRecording {
+  readonly id: string;
+  readonly workspace_id: string;
+  readonly workspace_build_id: string;
+  readonly agent_id: string;
+  readonly type: WorkspaceSessionRecordingType;
+  readonly started_at: string;
+  readonly ended_at: string;
+  readonly size: number;
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceUser extends MinimalUser {
+  readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
   readonly q?: string;
@@ -1687,8 +2053,12 @@ export interface WorkspacesResponse {
 }
 
 // From codersdk/apikey.go
-export type APIKeyScope = "all" | "application_connect";
-export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"];
+export type APIKeyScope = "all" | "application_connect" | "custom";
+export const APIKeyScopes: APIKeyScope[] = [
+  "all",
+  "application_connect",
+  "custom",
+];
 
 // From codersdk/workspaceagents.go
 export type AgentSubsystem = "envbox" | "envbuilder" | "exectrace";
@@ -1719,6 +2089,10 @@ export const AuditActions: AuditAction[] = [
   "write",
 ];
 
+// From codersdk/audit.go
+export type AuditLogExportFormat = "csv" | "ndjson";
+export const AuditLogExportFormats: AuditLogExportFormat[] = ["csv", "ndjson"];
+
 // From codersdk/workspaces.go
 export type AutomaticUpdates = "always" | "never";
 export const AutomaticUpdateses: AutomaticUpdates[] = ["always", "never"];
@@ -1880,6 +2254,15 @@ export const LoginTypes: LoginType[] = [
   "token",
 ];
 
+// From codersdk/provisionerdaemons.go
+export type ProvisionerJobPriority = "autobuild" | "batch" | "dry_run" | "user";
+export const ProvisionerJobPrioritys: ProvisionerJobPriority[] = [
+  "autobuild",
+  "batch",
+  "dry_run",
+  "user",
+];
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
   | "canceled"
@@ -1907,6 +2290,15 @@ export const ProvisionerLogLevels: ProvisionerLogLevel[] = ["debug"];
 export type ProvisionerStorageMethod = "file";
 export const ProvisionerStorageMethods: ProvisionerStorageMethod[] = ["file"];
 
+// From codersdk/workspacebuilds.go
+export type ProvisionerTimingStage = "apply" | "graph" | "init" | "plan";
+export const ProvisionerTimingStages: ProvisionerTimingStage[] = [
+  "apply",
+  "graph",
+  "init",
+  "plan",
+];
+
 // From codersdk/organizations.go
 export type ProvisionerType = "echo" | "terraform";
 export const ProvisionerTypes: ProvisionerType[] = ["echo", "terraform"];
@@ -1975,6 +2367,23 @@ export const RBACResources: RBACResource[] = [
   "workspace_proxy",
 ];
 
+// From codersdk/workspaceagentconn.go
+export type ReconnectingPTYMode = "collaborator" | "observer" | "owner";
+export const ReconnectingPTYModes: ReconnectingPTYMode[] = [
+  "collaborator",
+  "observer",
+  "owner",
+];
+
+// From codersdk/templateversions.go
+export type ResourceChangeAction = "create" | "delete" | "replace" | "update";
+export const ResourceChangeActions: ResourceChangeAction[] = [
+  "create",
+  "delete",
+  "replace",
+  "update",
+];
+
 // From codersdk/audit.go
 export type ResourceType =
   | "api_key"
@@ -1983,6 +2392,7 @@ export type ResourceType =
   | "group"
   | "health_settings"
   | "license"
+  | "oidc_sync_rules"
   | "organization"
   | "template"
   | "template_version"
@@ -1997,6 +2407,7 @@ export const ResourceTypes: ResourceType[] = [
   "group",
   "health_settings",
   "license",
+  "oidc_sync_rules",
   "organization",
   "template",
   "template_version",
@@ -2046,6 +2457,14 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
   "increasing",
 ];
 
+// From codersdk/workspaceagentconn.go
+export type WorkspaceAgentExecFrameType = "exit" | "stderr" | "stdout";
+export const WorkspaceAgentExecFrameTypes: WorkspaceAgentExecFrameType[] = [
+  "exit",
+  "stderr",
+  "stdout",
+];
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLifecycle =
   | "created"
@@ -2069,6 +2488,20 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
   "starting",
 ];
 
+// From codersdk/workspacebuilds.go
+export type WorkspaceAgentScriptTimingStage = "start" | "stop";
+export const WorkspaceAgentScriptTimingStages: WorkspaceAgentScriptTimingStage[] =
+  ["start", "stop"];
+
+// From codersdk/workspacebuilds.go
+export type WorkspaceAgentScriptTimingStatus =
+  | "exit_failure"
+  | "ok"
+  | "pipes_left_open"
+  | "timed_out";
+export const WorkspaceAgentScriptTimingStatuses: WorkspaceAgentScriptTimingStatus[] =
+  ["exit_failure", "ok", "pipes_left_open", "timed_out"];
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentStartupScriptBehavior = "blocking" | "non-blocking";
 export const WorkspaceAgentStartupScriptBehaviors: WorkspaceAgentStartupScriptBehavior[] =
@@ -2108,6 +2541,17 @@ export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
   "public",
 ];
 
+// From codersdk/workspaces.go
+export type WorkspaceRole = "" | "app" | "ssh" | "use";
+export const WorkspaceRoles: WorkspaceRole[] = ["", "app", "ssh", "use"];
+
+// From codersdk/workspacesessionrecordings.go
+export type WorkspaceSessionRecordingType = "reconnecting_pty" | "ssh";
+export const WorkspaceSessionRecordingTypes: WorkspaceSessionRecordingType[] = [
+  "reconnecting_pty",
+  "ssh",
+];
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
   | "canceled"
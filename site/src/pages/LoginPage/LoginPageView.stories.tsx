@@ -1,6 +1,7 @@
 import {
   MockAuthMethodsAll,
   MockAuthMethodsExternal,
+  MockAuthMethodsLoginProviders,
   MockAuthMethodsPasswordOnly,
   mockApiError,
 } from "testHelpers/entities";
@@ -33,6 +34,12 @@ export const WithAllAuthMethods: Story = {
   },
 };
 
+export const WithLoginProviders: Story = {
+  args: {
+    authMethods: MockAuthMethodsLoginProviders,
+  },
+};
+
 export const AuthError: Story = {
   args: {
     error: mockApiError({
@@ -63,6 +63,32 @@ export const OAuthSignInForm: FC<OAuthSignInFormProps> = ({
           {authMethods.oidc.signInText || Language.oidcSignIn}
         </Button>
       )}
+
+      {authMethods?.login_providers.map((provider) => (
+        <Button
+          key={provider.id}
+          component="a"
+          href={`${provider.callback_url}?redirect=${encodeURIComponent(
+            redirectTo,
+          )}`}
+          variant="contained"
+          size="xlarge"
+          startIcon={
+            provider.icon_url ? (
+              <OidcIcon iconUrl={provider.icon_url} />
+            ) : provider.type === "github" ? (
+              <GitHubIcon css={iconStyles} />
+            ) : (
+              <KeyIcon css={iconStyles} />
+            )
+          }
+          disabled={isSigningIn}
+          fullWidth
+          type="submit"
+        >
+          {provider.display_name || provider.id}
+        </Button>
+      ))}
     </div>
   );
 };
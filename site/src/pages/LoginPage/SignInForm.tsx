@@ -77,7 +77,9 @@ export const SignInForm: FC<React.PropsWithChildren<SignInFormProps>> = ({
   onSubmit,
 }) => {
   const oAuthEnabled = Boolean(
-    authMethods?.github.enabled || authMethods?.oidc.enabled,
+    authMethods?.github.enabled ||
+      authMethods?.oidc.enabled ||
+      (authMethods?.login_providers.length ?? 0) > 0,
   );
   const passwordEnabled = authMethods?.password.enabled ?? true;
 
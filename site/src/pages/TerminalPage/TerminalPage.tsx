@@ -14,7 +14,7 @@ import { MONOSPACE_FONT_FAMILY } from "theme/constants";
 import { pageTitle } from "utils/page";
 import { useProxy } from "contexts/ProxyContext";
 import { useDashboard } from "components/Dashboard/DashboardProvider";
-import type { Region } from "api/typesGenerated";
+import type { ReconnectingPTYMode, Region } from "api/typesGenerated";
 import { getLatencyColor } from "utils/latency";
 import { ProxyStatusLatency } from "components/ProxyStatusLatency/ProxyStatusLatency";
 import { openMaybePortForwardedURL } from "utils/portForward";
@@ -58,6 +58,10 @@ const TerminalPage: FC = () => {
   // a round-trip, and must be a UUIDv4.
   const reconnectionToken = searchParams.get("reconnect") ?? uuidv4();
   const command = searchParams.get("command") || undefined;
+  // Observers can watch an existing session but cannot type into it.
+  const mode = (searchParams.get("mode") || undefined) as
+    | ReconnectingPTYMode
+    | undefined;
   // The workspace name is in the format:
   // <workspace name>[.<agent name>]
   const workspaceNameParts = params.workspace?.split(".");
@@ -226,6 +230,7 @@ const TerminalPage: FC = () => {
       command,
       terminal.rows,
       terminal.cols,
+      mode,
     )
       .then((url) => {
         if (disposed) {
@@ -236,7 +241,7 @@ const TerminalPage: FC = () => {
         websocket.addEventListener("open", () => {
           // Now that we are connected, allow user input.
           terminal.options = {
-            disableStdin: false,
+            disableStdin: mode === "observer",
             windowsMode: workspaceAgent?.operating_system === "windows",
           };
           // Send the initial size.
@@ -286,6 +291,7 @@ const TerminalPage: FC = () => {
     };
   }, [
     command,
+    mode,
     proxy.preferredPathAppURL,
     reconnectionToken,
     terminal,
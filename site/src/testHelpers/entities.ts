@@ -356,6 +356,7 @@ export const MockProvisionerJob: TypesGen.ProvisionerJob = {
   tags: {},
   queue_position: 0,
   queue_size: 0,
+  priority: "user",
 };
 
 export const MockFailedProvisionerJob: TypesGen.ProvisionerJob = {
@@ -1309,6 +1310,7 @@ export const MockAuthMethodsPasswordOnly: TypesGen.AuthMethods = {
   password: { enabled: true },
   github: { enabled: false },
   oidc: { enabled: false, signInText: "", iconUrl: "" },
+  login_providers: [],
 };
 
 export const MockAuthMethodsExternal: TypesGen.AuthMethods = {
@@ -1319,6 +1321,7 @@ export const MockAuthMethodsExternal: TypesGen.AuthMethods = {
     signInText: "Google",
     iconUrl: "/icon/google.svg",
   },
+  login_providers: [],
 };
 
 export const MockAuthMethodsAll: TypesGen.AuthMethods = {
@@ -1329,6 +1332,33 @@ export const MockAuthMethodsAll: TypesGen.AuthMethods = {
     signInText: "Google",
     iconUrl: "/icon/google.svg",
   },
+  login_providers: [],
+};
+
+export const MockAuthMethodsLoginProviders: TypesGen.AuthMethods = {
+  password: { enabled: true },
+  github: { enabled: false },
+  oidc: {
+    enabled: true,
+    signInText: "Google",
+    iconUrl: "/icon/google.svg",
+  },
+  login_providers: [
+    {
+      id: "partner",
+      type: "oidc",
+      display_name: "Partner SSO",
+      icon_url: "/icon/okta.svg",
+      callback_url: "/api/v2/users/login-providers/partner/callback",
+    },
+    {
+      id: "partner-github",
+      type: "github",
+      display_name: "Partner GitHub",
+      icon_url: "",
+      callback_url: "/api/v2/users/login-providers/partner-github/callback",
+    },
+  ],
 };
 
 export const MockGitSSHKey: TypesGen.GitSSHKey = {
@@ -1,4 +1,5 @@
 import * as API from "api/api";
+import type { ReconnectingPTYMode } from "api/typesGenerated";
 
 export const terminalWebsocketUrl = async (
   baseUrl: string | undefined,
@@ -7,11 +8,15 @@ export const terminalWebsocketUrl = async (
   command: string | undefined,
   height: number,
   width: number,
+  mode?: ReconnectingPTYMode,
 ): Promise<string> => {
   const query = new URLSearchParams({ reconnect });
   if (command) {
     query.set("command", command);
   }
+  if (mode) {
+    query.set("mode", mode);
+  }
   query.set("height", height.toString());
   query.set("width", width.toString());
 
@@ -33,6 +38,7 @@ export const terminalWebsocketUrl = async (
   const tokenRes = await API.issueReconnectingPTYSignedToken({
     url: url.toString(),
     agentID: agentId,
+    mode,
   });
   query.set("coder_signed_app_token_23db1dde", tokenRes.signed_token);
   url.search = "?" + query.toString();